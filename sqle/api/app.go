@@ -182,6 +182,9 @@ func StartApi(net *gracenet.Net, exitChan chan struct{}, config config.SqleConfi
 		v1Router.PATCH("/configurations/webhook", v1.UpdateWorkflowWebHookConfig, AdminUserAllowed())
 		v1Router.GET("/configurations/webhook", v1.GetWorkflowWebHookConfig, AdminUserAllowed())
 		v1Router.POST("/configurations/webhook/test", v1.TestWorkflowWebHookConfig, AdminUserAllowed())
+		v1Router.GET("/configurations/bundle/export", v1.ExportConfigBundle, AdminUserAllowed())
+		v1Router.POST("/configurations/bundle/plan", v1.PlanConfigBundle, AdminUserAllowed())
+		v1Router.POST("/configurations/bundle/apply", v1.ApplyConfigBundle, AdminUserAllowed())
 
 		// statistic
 		v1Router.GET("/statistic/instances/type_percent", v1.GetInstancesTypePercentV1, AdminUserAllowed())
@@ -192,7 +195,7 @@ func StartApi(net *gracenet.Net, exitChan chan struct{}, config config.SqleConfi
 		//v1Router.GET("/statistic/workflows/rejected_percent_group_by_instance", v1.GetWorkflowRejectedPercentGroupByInstanceV1, AdminUserAllowed())
 		v1Router.GET("/statistic/workflows/counts", v1.GetWorkflowCountsV1, AdminUserAllowed())
 		v1Router.GET("/statistic/workflows/duration_of_waiting_for_audit", v1.GetWorkflowDurationOfWaitingForAuditV1, AdminUserAllowed())
-		//v1Router.GET("/statistic/workflows/duration_of_waiting_for_execution", v1.GetWorkflowDurationOfWaitingForExecutionV1, AdminUserAllowed())
+		v1Router.GET("/statistic/workflows/duration_of_waiting_for_execution", v1.GetWorkflowDurationOfWaitingForExecutionV1, AdminUserAllowed())
 		//v1Router.GET("/statistic/workflows/pass_percent", v1.GetWorkflowPassPercentV1, AdminUserAllowed())
 		v1Router.GET("/statistic/workflows/audit_pass_percent", v1.GetWorkflowAuditPassPercentV1, AdminUserAllowed())
 		v1Router.GET("/statistic/workflows/each_day_counts", v1.GetWorkflowCreatedCountsEachDayV1, AdminUserAllowed())
@@ -207,6 +210,7 @@ func StartApi(net *gracenet.Net, exitChan chan struct{}, config config.SqleConfi
 		v1Router.GET("/sync_instances/source_tips", v1.GetSyncTaskSourceTips, AdminUserAllowed())
 		v1Router.DELETE("/sync_instances/:task_id/", v1.DeleteSyncInstanceTask, AdminUserAllowed())
 		v1Router.POST("/sync_instances/:task_id/trigger", v1.TriggerSyncInstance, AdminUserAllowed())
+		v1Router.GET("/sync_instances/:task_id/records", v1.GetSyncInstanceTaskRecords, AdminUserAllowed())
 
 		// operation record
 		v1Router.GET("/operation_records/operation_type_names", v1.GetOperationTypeNameList, AdminUserAllowed())
@@ -214,6 +218,9 @@ func StartApi(net *gracenet.Net, exitChan chan struct{}, config config.SqleConfi
 		v1Router.GET("/operation_records", v1.GetOperationRecordListV1, AdminUserAllowed())
 		v1Router.GET("/operation_records/exports", v1.GetExportOperationRecordListV1, AdminUserAllowed())
 
+		// cluster
+		v1Router.GET("/cluster/nodes", v1.GetClusterNodesV1, AdminUserAllowed())
+
 		// other
 		v1Router.GET("/management_permissions", v1.GetManagementPermissions, AdminUserAllowed())
 
@@ -341,10 +348,16 @@ func StartApi(net *gracenet.Net, exitChan chan struct{}, config config.SqleConfi
 	v1Router.POST("/projects/:project_name/workflows/:workflow_name/tasks/:task_id/execute", DeprecatedBy(apiV2))
 	v2Router.POST("/projects/:project_name/workflows/:workflow_id/tasks/:task_id/execute", v2.ExecuteOneTaskOnWorkflowV2)
 	v1Router.POST("/projects/:project_name/workflows/:workflow_id/tasks/:task_id/terminate", v1.TerminateSingleTaskByWorkflowV1)
+	v1Router.POST("/projects/:project_name/workflows/:workflow_id/tasks/:task_id/rehearse", v1.RehearseSingleTaskByWorkflowV1)
+	v1Router.POST("/projects/:project_name/workflows/:workflow_id/tasks/:task_id/pause", v1.PauseSingleTaskByWorkflowV1)
+	v1Router.POST("/projects/:project_name/workflows/:workflow_id/tasks/:task_id/resume", v1.ResumeSingleTaskByWorkflowV1)
+	v1Router.POST("/projects/:project_name/workflows/:workflow_id/tasks/:task_id/online_ddl/cut_over", v1.CutOverOnlineDDLByWorkflowV1)
 	v1Router.GET("/projects/:project_name/workflows/:workflow_name/tasks", DeprecatedBy(apiV2))
 	v2Router.GET("/projects/:project_name/workflows/:workflow_id/tasks", v2.GetSummaryOfWorkflowTasksV2)
 	v1Router.POST("/projects/:project_name/workflows/:workflow_name/tasks/execute", DeprecatedBy(apiV2))
 	v2Router.POST("/projects/:project_name/workflows/:workflow_id/tasks/execute", v2.ExecuteTasksOnWorkflowV2)
+	v2Router.GET("/projects/:project_name/workflows/:workflow_id/tasks/execute_stages", v2.GetWorkflowExecuteStagesV2)
+	v2Router.PUT("/projects/:project_name/workflows/:workflow_id/tasks/execute_stages", v2.UpdateWorkflowExecuteStagesV2)
 	v1Router.POST("/projects/:project_name/workflows/:workflow_id/tasks/terminate", v1.TerminateMultipleTaskByWorkflowV1)
 	v1Router.PUT("/projects/:project_name/workflows/:workflow_name/tasks/:task_id/schedule", DeprecatedBy(apiV2))
 	v2Router.PUT("/projects/:project_name/workflows/:workflow_id/tasks/:task_id/schedule", v2.UpdateWorkflowScheduleV2)
@@ -362,6 +375,7 @@ func StartApi(net *gracenet.Net, exitChan chan struct{}, config config.SqleConfi
 	v1Router.GET("/tasks/audits/:task_id/sql_content", v1.GetAuditTaskSQLContent)
 	v1Router.PATCH("/tasks/audits/:task_id/sqls/:number", v1.UpdateAuditTaskSQLs)
 	v1Router.GET("/tasks/audits/:task_id/sqls/:number/analysis", v1.GetTaskAnalysisData)
+	v1Router.GET("/tasks/audits/:task_id/index_advices", v1.GetTaskIndexAdvices)
 	v2Router.GET("/tasks/audits/:task_id/sqls/:number/analysis", v2.GetTaskAnalysisData)
 	v1Router.POST("/projects/:project_name/task_groups", v1.CreateAuditTasksGroupV1)
 	v1Router.POST("/task_groups/audit", v1.AuditTaskGroupV1)
@@ -401,6 +415,7 @@ func StartApi(net *gracenet.Net, exitChan chan struct{}, config config.SqleConfi
 	v1Router.GET("/projects/:project_name/audit_plans/:audit_plan_name/reports/:audit_plan_report_id/sqls", v1.GetAuditPlanReportSQLsV1)
 	v2Router.GET("/projects/:project_name/audit_plans/:audit_plan_name/reports/:audit_plan_report_id/sqls", v2.GetAuditPlanReportSQLs)
 	v1Router.GET("/projects/:project_name/audit_plans/:audit_plan_name/reports/:audit_plan_report_id/export", v1.ExportAuditPlanReportV1)
+	v1Router.GET("/projects/:project_name/audit_plans/:audit_plan_name/reports/:audit_plan_report_id/index_advices", v1.GetAuditPlanReportIndexAdvices)
 
 	// sql manager
 	v1Router.GET("/projects/:project_name/sql_manages", v1.GetSqlManageList)
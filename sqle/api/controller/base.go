@@ -56,7 +56,8 @@ func GetUserName(c echo.Context) string {
 	if !ok {
 		return ""
 	}
-	return claims["name"].(string)
+	name, _ := claims["name"].(string)
+	return name
 }
 
 func GetCurrentUser(c echo.Context) (*model.User, error) {
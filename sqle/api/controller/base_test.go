@@ -0,0 +1,22 @@
+package controller
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang-jwt/jwt"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetUserName(t *testing.T) {
+	c := echo.New().NewContext(httptest.NewRequest("GET", "/", nil), httptest.NewRecorder())
+	assert.Equal(t, "", GetUserName(c))
+
+	c.Set("user", &jwt.Token{Claims: jwt.MapClaims{"name": "admin"}})
+	assert.Equal(t, "admin", GetUserName(c))
+
+	// the token without name, e.g. the state token of oauth2, has no user
+	c.Set("user", &jwt.Token{Claims: jwt.MapClaims{"state": "xxx"}})
+	assert.Equal(t, "", GetUserName(c))
+}
@@ -791,6 +791,48 @@ func GetAuditPlanReport(c echo.Context) error {
 	})
 }
 
+// @Summary 获取指定扫描任务的SQL扫描记录的索引建议
+// @Description get index advices of the SELECT statements in audit plan report, the advices are deduplicated and ordered by estimated benefit.
+// @Description The advices are generated by the index optimization rule (optimize_index_enabled) when auditing, so the result is empty if the rule is not enabled in the rule template of audit plan.
+// @Id getAuditPlanReportIndexAdvicesV1
+// @Tags audit_plan
+// @Security ApiKeyAuth
+// @Param project_name path string true "project name"
+// @Param audit_plan_name path string true "audit plan name"
+// @Param audit_plan_report_id path string true "audit plan report id"
+// @Success 200 {object} v1.GetIndexAdvicesResV1
+// @router /v1/projects/{project_name}/audit_plans/{audit_plan_name}/reports/{audit_plan_report_id}/index_advices [get]
+func GetAuditPlanReportIndexAdvices(c echo.Context) error {
+	projectName := c.Param("project_name")
+	apName := c.Param("audit_plan_name")
+
+	ap, exist, err := GetAuditPlanIfCurrentUserCanAccess(c, projectName, apName, model.OP_AUDIT_PLAN_VIEW_OTHERS)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	if !exist {
+		return controller.JSONBaseErrorReq(c, errAuditPlanNotExist)
+	}
+
+	reportID, err := strconv.Atoi(c.Param("audit_plan_report_id"))
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, errors.New(errors.DataInvalid, fmt.Errorf("parse audit plan report id failed: %v", err)))
+	}
+	s := model.GetStorage()
+	report, exist, err := s.GetReportWithAuditPlanByReportID(reportID)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	if !exist || report.AuditPlanID != ap.ID {
+		return controller.JSONBaseErrorReq(c, errors.New(errors.DataNotExist, fmt.Errorf("audit plan report not exist")))
+	}
+
+	return c.JSON(http.StatusOK, &GetIndexAdvicesResV1{
+		BaseRes: controller.NewBaseReq(nil),
+		Data:    convertIndexAdvicesToRes(server.GetAuditPlanReportIndexAdvices(report)),
+	})
+}
+
 type FullSyncAuditPlanSQLsReqV1 struct {
 	SQLs []*AuditPlanSQLReqV1 `json:"audit_plan_sql_list" form:"audit_plan_sql_list" valid:"dive"`
 }
@@ -4,12 +4,33 @@
 package v1
 
 import (
+	"net/http"
+
 	"github.com/actiontech/sqle/sqle/api/controller"
-	"github.com/actiontech/sqle/sqle/errors"
 
 	"github.com/labstack/echo/v4"
 )
 
 func getAuditPlanAnalysisData(c echo.Context) error {
-	return controller.JSONBaseErrorReq(c, errors.NewNotSupportGetAuditPlanAnalysisDataErr())
+	ap, instance, reportSQL, err := GetAuditPlanReportSQLForAnalysis(c, c.Param("project_name"),
+		c.Param("audit_plan_name"), c.Param("audit_plan_report_id"), c.Param("number"))
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	schema := reportSQL.Schema
+	if schema == "" {
+		schema = ap.InstanceDatabase
+	}
+	res, err := GetSQLAnalysisResult(instance, schema, reportSQL.SQL)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+
+	return c.JSON(http.StatusOK, &GetAuditPlanAnalysisDataResV1{
+		BaseRes: controller.NewBaseReq(nil),
+		Data: GetSQLAnalysisDataResItemV1{
+			SQLExplain: ConvertExplainResultToRes(reportSQL.SQL, res.ExplainResult, res.ExplainErr),
+			TableMetas: ConvertTableMetasToRes(res.TableMetaResult),
+		},
+	})
 }
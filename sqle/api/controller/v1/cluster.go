@@ -0,0 +1,77 @@
+package v1
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/actiontech/sqle/sqle/api/controller"
+	"github.com/actiontech/sqle/sqle/model"
+	"github.com/actiontech/sqle/sqle/server/cluster"
+
+	"github.com/labstack/echo/v4"
+)
+
+type GetClusterNodesResV1 struct {
+	controller.BaseRes
+	Data *ClusterStatusResV1 `json:"data"`
+}
+
+type ClusterStatusResV1 struct {
+	IsClusterMode  bool                `json:"is_cluster_mode"`
+	LeaderServerId string              `json:"leader_server_id"`
+	Nodes          []*ClusterNodeResV1 `json:"nodes"`
+}
+
+type ClusterNodeResV1 struct {
+	ServerId      string    `json:"server_id"`
+	IsLeader      bool      `json:"is_leader"`
+	IsAlive       bool      `json:"is_alive"`
+	HeartbeatTime time.Time `json:"heartbeat_time"`
+}
+
+// GetClusterNodesV1
+// @Summary 获取集群节点状态
+// @Description get nodes and leader of cluster
+// @Id getClusterNodesV1
+// @Tags cluster
+// @Security ApiKeyAuth
+// @Success 200 {object} v1.GetClusterNodesResV1
+// @Router /v1/cluster/nodes [get]
+func GetClusterNodesV1(c echo.Context) error {
+	data := &ClusterStatusResV1{
+		IsClusterMode: cluster.IsClusterMode,
+		Nodes:         []*ClusterNodeResV1{},
+	}
+	if !cluster.IsClusterMode {
+		return c.JSON(http.StatusOK, GetClusterNodesResV1{
+			BaseRes: controller.NewBaseReq(nil),
+			Data:    data,
+		})
+	}
+
+	s := model.GetStorage()
+	leaseSeconds := int(cluster.LeaseTimeout.Seconds())
+	leader, exist, err := s.GetClusterLeader(leaseSeconds)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	if exist {
+		data.LeaderServerId = leader.ServerId
+	}
+	nodes, err := s.GetClusterNodeStatusList(leaseSeconds)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	for _, node := range nodes {
+		data.Nodes = append(data.Nodes, &ClusterNodeResV1{
+			ServerId:      node.ServerId,
+			IsLeader:      exist && node.ServerId == leader.ServerId,
+			IsAlive:       node.IsAlive,
+			HeartbeatTime: node.HeartbeatTime,
+		})
+	}
+	return c.JSON(http.StatusOK, GetClusterNodesResV1{
+		BaseRes: controller.NewBaseReq(nil),
+		Data:    data,
+	})
+}
@@ -0,0 +1,156 @@
+package v1
+
+import (
+	"fmt"
+	"mime"
+	"net/http"
+	"strings"
+
+	"github.com/actiontech/sqle/sqle/api/controller"
+	"github.com/actiontech/sqle/sqle/errors"
+	"github.com/actiontech/sqle/sqle/model"
+	"github.com/actiontech/sqle/sqle/server/configbundle"
+
+	"github.com/labstack/echo/v4"
+)
+
+type ExportConfigBundleReqV1 struct {
+	ProjectNames string `json:"project_names" query:"project_names" valid:"required"`
+	Format       string `json:"format" query:"format" valid:"omitempty,oneof=yaml json" enums:"yaml,json"`
+}
+
+// ExportConfigBundle
+// @Summary 导出配置包
+// @Description export projects and their members, rule templates, workflow template, instances (without password), audit plans (without secret params) and sql whitelist as config bundle
+// @Id exportConfigBundleV1
+// @Tags configuration
+// @Security ApiKeyAuth
+// @Param project_names query string true "project names, separated by comma"
+// @Param format query string false "bundle format" Enums(yaml, json)
+// @Success 200 file 1 "sqle config bundle file"
+// @router /v1/configurations/bundle/export [get]
+func ExportConfigBundle(c echo.Context) error {
+	req := new(ExportConfigBundleReqV1)
+	if err := controller.BindAndValidateReq(c, req); err != nil {
+		return err
+	}
+	if req.Format == "" {
+		req.Format = configbundle.FormatYAML
+	}
+
+	projectNames := []string{}
+	for _, name := range strings.Split(req.ProjectNames, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			projectNames = append(projectNames, name)
+		}
+	}
+	bundle, err := configbundle.Export(model.GetStorage(), projectNames)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	content, err := configbundle.Marshal(bundle, req.Format)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+
+	c.Response().Header().Set(echo.HeaderContentDisposition,
+		mime.FormatMediaType("attachment", map[string]string{"filename": fmt.Sprintf("SQLE-config-bundle.%v", req.Format)}))
+	return c.Blob(http.StatusOK, "text/plain;charset=utf-8", content)
+}
+
+type ConfigBundleChangeResV1 struct {
+	Action      string   `json:"action" enums:"create,update"`
+	Kind        string   `json:"kind" enums:"project,rule_template,instance,workflow_template,member,audit_plan,sql_whitelist"`
+	ProjectName string   `json:"project_name"`
+	Name        string   `json:"name"`
+	Details     []string `json:"details"`
+}
+
+type PlanConfigBundleResV1 struct {
+	controller.BaseRes
+	Data []*ConfigBundleChangeResV1 `json:"data"`
+}
+
+// PlanConfigBundle
+// @Summary 预览配置包的变更
+// @Description compare the config bundle with current configuration, return the changes needed to apply the bundle
+// @Id planConfigBundleV1
+// @Tags configuration
+// @Accept mpfd
+// @Security ApiKeyAuth
+// @Param bundle_file formData file true "SQLE config bundle file, yaml or json"
+// @Success 200 {object} v1.PlanConfigBundleResV1
+// @router /v1/configurations/bundle/plan [post]
+func PlanConfigBundle(c echo.Context) error {
+	bundle, err := readConfigBundleFile(c)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	changes, err := configbundle.Plan(model.GetStorage(), bundle)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	return c.JSON(http.StatusOK, &PlanConfigBundleResV1{
+		BaseRes: controller.NewBaseReq(nil),
+		Data:    convertConfigBundleChangesToRes(changes),
+	})
+}
+
+type ApplyConfigBundleResV1 struct {
+	controller.BaseRes
+	Data []*ConfigBundleChangeResV1 `json:"data"`
+}
+
+// ApplyConfigBundle
+// @Summary 应用配置包
+// @Description apply the config bundle, create or update the resources in bundle, the resources absent from bundle are not changed
+// @Id applyConfigBundleV1
+// @Tags configuration
+// @Accept mpfd
+// @Security ApiKeyAuth
+// @Param bundle_file formData file true "SQLE config bundle file, yaml or json"
+// @Success 200 {object} v1.ApplyConfigBundleResV1
+// @router /v1/configurations/bundle/apply [post]
+func ApplyConfigBundle(c echo.Context) error {
+	bundle, err := readConfigBundleFile(c)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	user, err := controller.GetCurrentUser(c)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	changes, err := configbundle.Apply(model.GetStorage(), bundle, user)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	return c.JSON(http.StatusOK, &ApplyConfigBundleResV1{
+		BaseRes: controller.NewBaseReq(nil),
+		Data:    convertConfigBundleChangesToRes(changes),
+	})
+}
+
+func readConfigBundleFile(c echo.Context) (*configbundle.Bundle, error) {
+	file, exist, err := controller.ReadFileContent(c, "bundle_file")
+	if err != nil {
+		return nil, err
+	}
+	if !exist {
+		return nil, errors.New(errors.DataNotExist, fmt.Errorf("the file has not been uploaded or the key bound to the file is not 'bundle_file'"))
+	}
+	return configbundle.Parse([]byte(file))
+}
+
+func convertConfigBundleChangesToRes(changes []*configbundle.Change) []*ConfigBundleChangeResV1 {
+	res := make([]*ConfigBundleChangeResV1, 0, len(changes))
+	for _, change := range changes {
+		res = append(res, &ConfigBundleChangeResV1{
+			Action:      change.Action,
+			Kind:        change.Kind,
+			ProjectName: change.ProjectName,
+			Name:        change.Name,
+			Details:     change.Details,
+		})
+	}
+	return res
+}
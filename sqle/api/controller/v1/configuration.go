@@ -1010,10 +1010,70 @@ type GetOauth2ConfigurationResDataV1 struct {
 	ServerAuthUrl   string   `json:"server_auth_url"`
 	ServerTokenUrl  string   `json:"server_token_url"`
 	ServerUserIdUrl string   `json:"server_user_id_url"`
+	ServerJwksUrl   string   `json:"server_jwks_url"`
+	ServerIssuer    string   `json:"server_issuer"`
 	Scopes          []string `json:"scopes"`
 	AccessTokenTag  string   `json:"access_token_tag"`
 	UserIdTag       string   `json:"user_id_tag"`
-	LoginTip        string   `json:"login_tip"`
+	UserNameTag     string   `json:"user_name_tag"`
+	UserEmailTag    string   `json:"user_email_tag"`
+	UserGroupsTag   string   `json:"user_groups_tag"`
+	AutoCreateUser  bool     `json:"auto_create_user"`
+	// GroupMappings grant the user groups and project roles to the users in the groups of identity provider
+	GroupMappings []Oauth2GroupMappingV1 `json:"group_mappings"`
+	LoginTip      string                 `json:"login_tip"`
+}
+
+type Oauth2GroupMappingV1 struct {
+	Group        string                `json:"group" valid:"required" example:"dba"`
+	UserGroups   []string              `json:"user_groups"`
+	ProjectRoles []Oauth2ProjectRoleV1 `json:"project_roles" valid:"dive"`
+}
+
+type Oauth2ProjectRoleV1 struct {
+	ProjectName string           `json:"project_name" valid:"required" example:"default"`
+	IsManager   bool             `json:"is_manager"`
+	BindRoles   []model.BindRole `json:"bind_roles"`
+}
+
+func convertOauth2GroupMappingsToRes(mappings model.Oauth2GroupMappings) []Oauth2GroupMappingV1 {
+	res := make([]Oauth2GroupMappingV1, 0, len(mappings))
+	for _, mapping := range mappings {
+		projectRoles := make([]Oauth2ProjectRoleV1, 0, len(mapping.ProjectRoles))
+		for _, projectRole := range mapping.ProjectRoles {
+			projectRoles = append(projectRoles, Oauth2ProjectRoleV1{
+				ProjectName: projectRole.ProjectName,
+				IsManager:   projectRole.IsManager,
+				BindRoles:   projectRole.BindRoles,
+			})
+		}
+		res = append(res, Oauth2GroupMappingV1{
+			Group:        mapping.Group,
+			UserGroups:   mapping.UserGroups,
+			ProjectRoles: projectRoles,
+		})
+	}
+	return res
+}
+
+func convertOauth2GroupMappingsToModel(mappings []Oauth2GroupMappingV1) model.Oauth2GroupMappings {
+	res := make(model.Oauth2GroupMappings, 0, len(mappings))
+	for _, mapping := range mappings {
+		projectRoles := make([]model.Oauth2ProjectRole, 0, len(mapping.ProjectRoles))
+		for _, projectRole := range mapping.ProjectRoles {
+			projectRoles = append(projectRoles, model.Oauth2ProjectRole{
+				ProjectName: projectRole.ProjectName,
+				IsManager:   projectRole.IsManager,
+				BindRoles:   projectRole.BindRoles,
+			})
+		}
+		res = append(res, model.Oauth2GroupMapping{
+			Group:        mapping.Group,
+			UserGroups:   mapping.UserGroups,
+			ProjectRoles: projectRoles,
+		})
+	}
+	return res
 }
 
 // @Summary 获取 Oauth2 配置
@@ -1035,26 +1095,40 @@ func GetOauth2Configuration(c echo.Context) error {
 			ServerAuthUrl:   oauth2C.ServerAuthUrl,
 			ServerTokenUrl:  oauth2C.ServerTokenUrl,
 			ServerUserIdUrl: oauth2C.ServerUserIdUrl,
+			ServerJwksUrl:   oauth2C.ServerJwksUrl,
+			ServerIssuer:    oauth2C.ServerIssuer,
 			Scopes:          oauth2C.GetScopes(),
 			AccessTokenTag:  oauth2C.AccessTokenTag,
 			UserIdTag:       oauth2C.UserIdTag,
+			UserNameTag:     oauth2C.UserNameTag,
+			UserEmailTag:    oauth2C.UserEmailTag,
+			UserGroupsTag:   oauth2C.UserGroupsTag,
+			AutoCreateUser:  oauth2C.AutoCreateUser,
+			GroupMappings:   convertOauth2GroupMappingsToRes(oauth2C.GroupMappings),
 			LoginTip:        oauth2C.LoginTip,
 		},
 	})
 }
 
 type Oauth2ConfigurationReqV1 struct {
-	EnableOauth2    *bool     `json:"enable_oauth2"`
-	ClientID        *string   `json:"client_id"`
-	ClientKey       *string   `json:"client_key"`
-	ClientHost      *string   `json:"client_host"`
-	ServerAuthUrl   *string   `json:"server_auth_url"`
-	ServerTokenUrl  *string   `json:"server_token_url"`
-	ServerUserIdUrl *string   `json:"server_user_id_url"`
-	Scopes          *[]string `json:"scopes"`
-	AccessTokenTag  *string   `json:"access_token_tag"`
-	UserIdTag       *string   `json:"user_id_tag"`
-	LoginTip        *string   `json:"login_tip"`
+	EnableOauth2    *bool                   `json:"enable_oauth2"`
+	ClientID        *string                 `json:"client_id"`
+	ClientKey       *string                 `json:"client_key"`
+	ClientHost      *string                 `json:"client_host"`
+	ServerAuthUrl   *string                 `json:"server_auth_url"`
+	ServerTokenUrl  *string                 `json:"server_token_url"`
+	ServerUserIdUrl *string                 `json:"server_user_id_url"`
+	ServerJwksUrl   *string                 `json:"server_jwks_url"`
+	ServerIssuer    *string                 `json:"server_issuer"`
+	Scopes          *[]string               `json:"scopes"`
+	AccessTokenTag  *string                 `json:"access_token_tag"`
+	UserIdTag       *string                 `json:"user_id_tag"`
+	UserNameTag     *string                 `json:"user_name_tag"`
+	UserEmailTag    *string                 `json:"user_email_tag"`
+	UserGroupsTag   *string                 `json:"user_groups_tag"`
+	AutoCreateUser  *bool                   `json:"auto_create_user"`
+	GroupMappings   *[]Oauth2GroupMappingV1 `json:"group_mappings"`
+	LoginTip        *string                 `json:"login_tip"`
 }
 
 // @Summary 修改 Oauth2 配置
@@ -1106,6 +1180,12 @@ func UpdateOauth2Configuration(c echo.Context) error {
 		if req.ServerUserIdUrl != nil {
 			oauth2C.ServerUserIdUrl = *req.ServerUserIdUrl
 		}
+		if req.ServerJwksUrl != nil {
+			oauth2C.ServerJwksUrl = *req.ServerJwksUrl
+		}
+		if req.ServerIssuer != nil {
+			oauth2C.ServerIssuer = *req.ServerIssuer
+		}
 		if req.Scopes != nil {
 			oauth2C.SetScopes(*req.Scopes)
 		}
@@ -1115,6 +1195,21 @@ func UpdateOauth2Configuration(c echo.Context) error {
 		if req.UserIdTag != nil {
 			oauth2C.UserIdTag = *req.UserIdTag
 		}
+		if req.UserNameTag != nil {
+			oauth2C.UserNameTag = *req.UserNameTag
+		}
+		if req.UserEmailTag != nil {
+			oauth2C.UserEmailTag = *req.UserEmailTag
+		}
+		if req.UserGroupsTag != nil {
+			oauth2C.UserGroupsTag = *req.UserGroupsTag
+		}
+		if req.AutoCreateUser != nil {
+			oauth2C.AutoCreateUser = *req.AutoCreateUser
+		}
+		if req.GroupMappings != nil {
+			oauth2C.GroupMappings = convertOauth2GroupMappingsToModel(*req.GroupMappings)
+		}
 		if req.LoginTip != nil {
 			oauth2C.LoginTip = *req.LoginTip
 		}
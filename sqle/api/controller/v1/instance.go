@@ -1016,9 +1016,16 @@ type Table struct {
 	Name string `json:"name"`
 }
 
+type ListTableBySchemaReqV1 struct {
+	FuzzySearchTableName string `json:"fuzzy_search_table_name" query:"fuzzy_search_table_name"`
+	PageIndex            uint32 `json:"page_index" query:"page_index"`
+	PageSize             uint32 `json:"page_size" query:"page_size"`
+}
+
 type ListTableBySchemaResV1 struct {
 	controller.BaseRes
-	Data []Table `json:"data"`
+	Data      []Table `json:"data"`
+	TotalNums uint64  `json:"total_nums"`
 }
 
 // ListTableBySchema list table by schema
@@ -1029,6 +1036,9 @@ type ListTableBySchemaResV1 struct {
 // @Param project_name path string true "project name"
 // @Param instance_name path string true "instance name"
 // @Param schema_name path string true "schema name"
+// @Param fuzzy_search_table_name query string false "fuzzy search table name"
+// @Param page_index query uint32 false "page index, return all tables when page_size is not set"
+// @Param page_size query uint32 false "size of per page"
 // @Security ApiKeyAuth
 // @Success 200 {object} v1.ListTableBySchemaResV1
 // @router /v1/projects/{project_name}/instances/{instance_name}/schemas/{schema_name}/tables [get]
@@ -1059,6 +1069,28 @@ type InstanceTableMeta struct {
 	CreateTableSQL string       `json:"create_table_sql"`
 }
 
+// convertTabularDataToRes converts driver tabular data to head and rows, each row is keyed by head name.
+func convertTabularDataToRes(data driverV2.TabularData) ([]TableMetaItemHeadResV1, []map[string]string) {
+	head := make([]TableMetaItemHeadResV1, 0, len(data.Columns))
+	for _, column := range data.Columns {
+		head = append(head, TableMetaItemHeadResV1{
+			FieldName: column.Name,
+			Desc:      column.Desc,
+		})
+	}
+	rows := make([]map[string]string, 0, len(data.Rows))
+	for _, row := range data.Rows {
+		r := make(map[string]string, len(data.Columns))
+		for i, column := range data.Columns {
+			if i < len(row) {
+				r[column.Name] = row[i]
+			}
+		}
+		rows = append(rows, r)
+	}
+	return head, rows
+}
+
 type GetTableMetadataResV1 struct {
 	controller.BaseRes
 	Data InstanceTableMeta `json:"data"`
@@ -4,19 +4,124 @@
 package v1
 
 import (
-	e "errors"
+	"context"
+	"fmt"
+	"net/http"
+
 	"github.com/actiontech/sqle/sqle/api/controller"
+	"github.com/actiontech/sqle/sqle/common"
+	"github.com/actiontech/sqle/sqle/driver"
+	driverV2 "github.com/actiontech/sqle/sqle/driver/v2"
 	"github.com/actiontech/sqle/sqle/errors"
+	"github.com/actiontech/sqle/sqle/log"
+	"github.com/actiontech/sqle/sqle/model"
+
 	"github.com/labstack/echo/v4"
 )
 
-var errCommunityEditionDoesNotSupportListTables = errors.New(errors.EnterpriseEditionFeatures, e.New("community edition does not support list tables"))
-var errCommunityEditionDoesNotSupportGetTablesMetadata = errors.New(errors.EnterpriseEditionFeatures, e.New("community edition does not support get table metadata"))
-
 func listTableBySchema(c echo.Context) error {
-	return controller.JSONBaseErrorReq(c, errCommunityEditionDoesNotSupportListTables)
+	req := new(ListTableBySchemaReqV1)
+	if err := controller.BindAndValidateReq(c, req); err != nil {
+		return err
+	}
+	instance, err := getInstanceForMetadata(c, driverV2.OptionalModuleListTables)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+
+	plugin, err := common.NewDriverManagerWithoutAudit(log.NewEntry(), instance, "")
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	defer plugin.Close(context.TODO())
+
+	conf := &driverV2.ListTablesConf{
+		Schema:               c.Param("schema_name"),
+		FuzzySearchTableName: req.FuzzySearchTableName,
+		Limit:                req.PageSize,
+	}
+	if req.PageIndex >= 1 {
+		conf.Offset = req.PageSize * (req.PageIndex - 1)
+	}
+	result, err := plugin.ListTables(context.TODO(), conf)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+
+	tables := make([]Table, 0, len(result.Tables))
+	for _, t := range result.Tables {
+		tables = append(tables, Table{Name: t.Name})
+	}
+	return c.JSON(http.StatusOK, &ListTableBySchemaResV1{
+		BaseRes:   controller.NewBaseReq(nil),
+		Data:      tables,
+		TotalNums: result.Total,
+	})
 }
 
 func getTableMetadata(c echo.Context) error {
-	return controller.JSONBaseErrorReq(c, errCommunityEditionDoesNotSupportGetTablesMetadata)
+	instance, err := getInstanceForMetadata(c, driverV2.OptionalModuleGetTableMeta)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+
+	plugin, err := common.NewDriverManagerWithoutAudit(log.NewEntry(), instance, "")
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	defer plugin.Close(context.TODO())
+
+	table := &driverV2.Table{
+		Schema: c.Param("schema_name"),
+		Name:   c.Param("table_name"),
+	}
+	meta, err := plugin.GetTableMeta(context.TODO(), table)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	if meta.Message != "" {
+		return controller.JSONBaseErrorReq(c, errors.New(errors.DataNotExist, fmt.Errorf("%s", meta.Message)))
+	}
+
+	res := InstanceTableMeta{
+		Name:           table.Name,
+		Schema:         table.Schema,
+		CreateTableSQL: meta.CreateTableSQL,
+	}
+	res.Columns.Head, res.Columns.Rows = convertTabularDataToRes(meta.ColumnsInfo.TabularData)
+	res.Indexes.Head, res.Indexes.Rows = convertTabularDataToRes(meta.IndexesInfo.TabularData)
+	return c.JSON(http.StatusOK, &GetTableMetadataResV1{
+		BaseRes: controller.NewBaseReq(nil),
+		Data:    res,
+	})
+}
+
+// getInstanceForMetadata returns the instance in path if current user can access it
+// and the instance's plugin supports the module.
+func getInstanceForMetadata(c echo.Context, module driverV2.OptionalModule) (*model.Instance, error) {
+	projectName := c.Param("project_name")
+	instanceName := c.Param("instance_name")
+	if err := CheckIsProjectMember(controller.GetUserName(c), projectName); err != nil {
+		return nil, err
+	}
+
+	instance, exist, err := model.GetStorage().GetInstanceByNameAndProjectName(instanceName, projectName)
+	if err != nil {
+		return nil, err
+	}
+	if !exist {
+		return nil, ErrInstanceNoAccess
+	}
+	can, err := checkCurrentUserCanAccessInstance(c, instance)
+	if err != nil {
+		return nil, err
+	}
+	if !can {
+		return nil, ErrInstanceNoAccess
+	}
+
+	if !driver.GetPluginManager().IsOptionalModuleEnabled(instance.DbType, module) {
+		return nil, errors.New(errors.DataInvalid, fmt.Errorf("db type %s does not support %s", instance.DbType, module))
+	}
+	return instance, nil
 }
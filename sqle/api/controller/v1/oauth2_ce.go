@@ -4,24 +4,427 @@
 package v1
 
 import (
+	"crypto/hmac"
+	"crypto/sha256"
 	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
 
 	"github.com/actiontech/sqle/sqle/api/controller"
 	"github.com/actiontech/sqle/sqle/errors"
+	"github.com/actiontech/sqle/sqle/log"
+	"github.com/actiontech/sqle/sqle/model"
+	"github.com/actiontech/sqle/sqle/pkg/oauth2"
+	"github.com/actiontech/sqle/sqle/utils"
 
+	"github.com/golang-jwt/jwt"
 	"github.com/labstack/echo/v4"
 )
 
-var errNotSupportOauth2 = errors.New(errors.EnterpriseEditionFeatures, fmt.Errorf("oauth2 related functions are enterprise version functions"))
+const (
+	oauth2StateCookieName = "sqle-oauth2-state"
+	oauth2CallbackPath    = "/v1/oauth2/callback"
+	// oauth2BindPagePath is the page of frontend which logs in or binds the SQLE user after callback.
+	oauth2BindPagePath = "/user/bind"
+	// oauth2FlowLifeTime is the valid time of the state and the token for binding user.
+	oauth2FlowLifeTime = 10 * time.Minute
 
+	oauth2TokenTypeState = "oauth2_state"
+	oauth2TokenTypeBind  = "oauth2_bind"
+
+	defaultOauth2UserIdTag     = "sub"
+	defaultOauth2UserNameTag   = "preferred_username"
+	defaultOauth2UserEmailTag  = "email"
+	defaultOauth2UserGroupsTag = "groups"
+)
+
+var errOauth2Disabled = errors.New(errors.DataInvalid, fmt.Errorf("oauth2 login is disabled"))
+
+func getEnabledOauth2Configuration() (*model.Oauth2Configuration, error) {
+	conf, exist, err := model.GetStorage().GetOauth2Configuration()
+	if err != nil {
+		return nil, err
+	}
+	if !exist || !conf.EnableOauth2 {
+		return nil, errOauth2Disabled
+	}
+	return conf, nil
+}
+
+func newOauth2Config(conf *model.Oauth2Configuration) *oauth2.Config {
+	scopes := []string{}
+	for _, scope := range conf.GetScopes() {
+		if scope = strings.TrimSpace(scope); scope != "" {
+			scopes = append(scopes, scope)
+		}
+	}
+	return &oauth2.Config{
+		ClientID:       conf.ClientID,
+		ClientSecret:   conf.ClientKey,
+		RedirectURL:    strings.TrimSuffix(conf.ClientHost, "/") + oauth2CallbackPath,
+		AuthURL:        conf.ServerAuthUrl,
+		TokenURL:       conf.ServerTokenUrl,
+		UserInfoURL:    conf.ServerUserIdUrl,
+		JWKSURL:        conf.ServerJwksUrl,
+		Issuer:         conf.ServerIssuer,
+		Scopes:         scopes,
+		AccessTokenTag: conf.AccessTokenTag,
+	}
+}
+
+func oauth2Tag(tag, defaultTag string) string {
+	if tag == "" {
+		return defaultTag
+	}
+	return tag
+}
+
+// oauth2UserInfo is the user of identity provider.
+type oauth2UserInfo struct {
+	ID     string
+	Name   string
+	Email  string
+	Groups []string
+}
+
+func getOauth2UserInfo(conf *model.Oauth2Configuration, claims oauth2.Claims) *oauth2UserInfo {
+	return &oauth2UserInfo{
+		ID:     claims.String(oauth2Tag(conf.UserIdTag, defaultOauth2UserIdTag)),
+		Name:   claims.String(oauth2Tag(conf.UserNameTag, defaultOauth2UserNameTag)),
+		Email:  claims.String(oauth2Tag(conf.UserEmailTag, defaultOauth2UserEmailTag)),
+		Groups: claims.Strings(oauth2Tag(conf.UserGroupsTag, defaultOauth2UserGroupsTag)),
+	}
+}
+
+// oauth2FlowSigningKey is derived from the JWT secret key but differs from it, so the state and the
+// token for binding user are never accepted as the login token of SQLE.
+func oauth2FlowSigningKey() []byte {
+	mac := hmac.New(sha256.New, utils.JWTSecretKey)
+	mac.Write([]byte("sqle-oauth2-flow"))
+	return mac.Sum(nil)
+}
+
+func signOauth2Claims(typ string, claims jwt.MapClaims) (string, error) {
+	claims["typ"] = typ
+	claims["exp"] = time.Now().Add(oauth2FlowLifeTime).Unix()
+	return jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(oauth2FlowSigningKey())
+}
+
+func parseOauth2Claims(typ, token string) (jwt.MapClaims, error) {
+	t, err := jwt.Parse(token, func(t *jwt.Token) (interface{}, error) {
+		if _, ok := t.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, fmt.Errorf("unexpected signing method %v", t.Header["alg"])
+		}
+		return oauth2FlowSigningKey(), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	claims, ok := t.Claims.(jwt.MapClaims)
+	if !ok || !t.Valid || claimString(claims, "typ") != typ {
+		return nil, fmt.Errorf("token is invalid")
+	}
+	return claims, nil
+}
+
+func claimString(claims jwt.MapClaims, key string) string {
+	s, _ := claims[key].(string)
+	return s
+}
+
+// oauth2Link redirects to the identity provider with the state, nonce and PKCE code challenge, the
+// code verifier is kept in a signed cookie until callback.
 func oauth2Link(c echo.Context) error {
-	return controller.JSONBaseErrorReq(c, errNotSupportOauth2)
+	conf, err := getEnabledOauth2Configuration()
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	values := map[string]string{}
+	for _, key := range []string{"state", "nonce", "verifier"} {
+		if values[key], err = oauth2.RandomString(); err != nil {
+			return controller.JSONBaseErrorReq(c, err)
+		}
+	}
+	authURL, err := newOauth2Config(conf).AuthCodeURL(values["state"], values["nonce"], values["verifier"])
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, errors.New(errors.DataInvalid, err))
+	}
+	stateToken, err := signOauth2Claims(oauth2TokenTypeState, jwt.MapClaims{
+		"state":    values["state"],
+		"nonce":    values["nonce"],
+		"verifier": values["verifier"],
+	})
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	// the scheme is https if the request is over TLS or forwarded from https by proxy
+	c.SetCookie(&http.Cookie{
+		Name:     oauth2StateCookieName,
+		Value:    stateToken,
+		Path:     "/v1/oauth2",
+		Expires:  time.Now().Add(oauth2FlowLifeTime),
+		HttpOnly: true,
+		Secure:   c.Scheme() == "https",
+		SameSite: http.SameSiteLaxMode,
+	})
+	return c.Redirect(http.StatusFound, authURL)
 }
 
 func oauth2Callback(c echo.Context) error {
-	return controller.JSONBaseErrorReq(c, errNotSupportOauth2)
+	conf, err := getEnabledOauth2Configuration()
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	if idpErr := c.QueryParam("error"); idpErr != "" {
+		return controller.JSONBaseErrorReq(c, errors.New(errors.LoginAuthFail,
+			fmt.Errorf("oauth2 login failed: %s %s", idpErr, c.QueryParam("error_description"))))
+	}
+
+	cookie, err := c.Cookie(oauth2StateCookieName)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, errors.New(errors.LoginAuthFail, fmt.Errorf("oauth2 state is missing")))
+	}
+	c.SetCookie(&http.Cookie{Name: oauth2StateCookieName, Path: "/v1/oauth2", MaxAge: -1})
+	state, err := parseOauth2Claims(oauth2TokenTypeState, cookie.Value)
+	if err != nil || claimString(state, "state") != c.QueryParam("state") {
+		return controller.JSONBaseErrorReq(c, errors.New(errors.LoginAuthFail, fmt.Errorf("oauth2 state is invalid")))
+	}
+
+	ctx := c.Request().Context()
+	cfg := newOauth2Config(conf)
+	token, err := cfg.Exchange(ctx, c.QueryParam("code"), claimString(state, "verifier"))
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, errors.New(errors.LoginAuthFail, err))
+	}
+	claims, err := cfg.Claims(ctx, token, claimString(state, "nonce"))
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, errors.New(errors.LoginAuthFail, err))
+	}
+	info := getOauth2UserInfo(conf, claims)
+	if info.ID == "" {
+		return controller.JSONBaseErrorReq(c, errors.New(errors.LoginAuthFail,
+			fmt.Errorf("claim %s of user id is not found", oauth2Tag(conf.UserIdTag, defaultOauth2UserIdTag))))
+	}
+
+	user, exist, err := getOrCreateOauth2User(conf, info)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	query := url.Values{}
+	if exist {
+		sqleToken, err := loginOauth2User(c, user, conf, info)
+		if err != nil {
+			return controller.JSONBaseErrorReq(c, err)
+		}
+		query.Set("user_exist", "true")
+		query.Set("sqle_token", sqleToken)
+	} else {
+		oauth2Token, err := signOauth2Claims(oauth2TokenTypeBind, jwt.MapClaims{
+			"uid":    info.ID,
+			"email":  info.Email,
+			"groups": info.Groups,
+		})
+		if err != nil {
+			return controller.JSONBaseErrorReq(c, err)
+		}
+		query.Set("user_exist", "false")
+		query.Set("oauth2_token", oauth2Token)
+		query.Set("user_name", info.Name)
+	}
+	return c.Redirect(http.StatusFound, fmt.Sprintf("%s%s?%s", strings.TrimSuffix(conf.ClientHost, "/"), oauth2BindPagePath, query.Encode()))
+}
+
+// getOrCreateOauth2User returns the user bound to the user of identity provider, the user is created
+// if auto creation is enabled and the name is not used.
+func getOrCreateOauth2User(conf *model.Oauth2Configuration, info *oauth2UserInfo) (*model.User, bool, error) {
+	s := model.GetStorage()
+	user, exist, err := s.GetUserByThirdPartyUserID(info.ID)
+	if err != nil || exist {
+		return user, exist, err
+	}
+	if !conf.AutoCreateUser || info.Name == "" {
+		return nil, false, nil
+	}
+	_, nameUsed, err := s.GetUserByName(info.Name)
+	if err != nil || nameUsed {
+		return nil, false, err
+	}
+	password, err := oauth2.RandomString()
+	if err != nil {
+		return nil, false, err
+	}
+	user = &model.User{
+		Name:                   info.Name,
+		Email:                  info.Email,
+		Password:               password,
+		UserAuthenticationType: model.UserAuthenticationTypeOAUTH2,
+		ThirdPartyUserID:       info.ID,
+	}
+	if err := s.Save(user); err != nil {
+		return nil, false, err
+	}
+	return user, true, nil
+}
+
+// loginOauth2User grants the mapped user groups and project roles, then logs in the user.
+func loginOauth2User(c echo.Context, user *model.User, conf *model.Oauth2Configuration, info *oauth2UserInfo) (string, error) {
+	if user.IsDisabled() {
+		return "", errors.New(errors.LoginAuthFail, fmt.Errorf("user %s is disabled", user.Name))
+	}
+	grantOauth2GroupMappings(user, conf.GroupMappings.Match(info.Groups))
+
+	token, err := generateToken(user.Name)
+	if err != nil {
+		return "", errors.New(http.StatusInternalServerError, err)
+	}
+	SetCookie(c, token)
+	return token, nil
+}
+
+// grantOauth2GroupMappings only adds the user groups and project members, the permissions granted
+// in SQLE are never revoked. The invalid mapping is logged and skipped.
+func grantOauth2GroupMappings(user *model.User, mappings []model.Oauth2GroupMapping) {
+	if len(mappings) == 0 {
+		return
+	}
+	entry := log.NewEntry().WithField("user", user.Name)
+	s := model.GetStorage()
+
+	groupNames := []string{}
+	for _, mapping := range mappings {
+		groupNames = append(groupNames, mapping.UserGroups...)
+	}
+	if len(groupNames) > 0 {
+		if err := addUserToUserGroups(user.Name, groupNames); err != nil {
+			entry.Errorf("add user to user groups %v failed, error: %v", groupNames, err)
+		}
+	}
+
+	for _, mapping := range mappings {
+		for _, projectRole := range mapping.ProjectRoles {
+			isMember, err := s.CheckUserIsMember(user.Name, projectRole.ProjectName)
+			if err != nil {
+				entry.Errorf("check user is member of project %s failed, error: %v", projectRole.ProjectName, err)
+				continue
+			}
+			if isMember {
+				continue
+			}
+			if err := s.AddMember(user.Name, projectRole.ProjectName, projectRole.IsManager, projectRole.BindRoles); err != nil {
+				entry.Errorf("add user to project %s failed, error: %v", projectRole.ProjectName, err)
+			}
+		}
+	}
+}
+
+func addUserToUserGroups(userName string, groupNames []string) error {
+	s := model.GetStorage()
+	user, exist, err := s.GetUserDetailByName(userName)
+	if err != nil {
+		return err
+	}
+	if !exist {
+		return fmt.Errorf("user %s is not exist", userName)
+	}
+	userGroups, err := s.GetUserGroupsByNames(groupNames)
+	if err != nil {
+		return err
+	}
+	joined := make(map[uint]struct{}, len(user.UserGroups))
+	for _, group := range user.UserGroups {
+		joined[group.ID] = struct{}{}
+	}
+	added := false
+	groups := user.UserGroups
+	for _, group := range userGroups {
+		if _, ok := joined[group.ID]; !ok {
+			joined[group.ID] = struct{}{}
+			groups = append(groups, group)
+			added = true
+		}
+	}
+	if !added {
+		return nil
+	}
+	return s.SaveUserAndAssociations(user, groups, nil)
 }
 
 func bindOauth2User(c echo.Context) error {
-	return controller.JSONBaseErrorReq(c, errNotSupportOauth2)
+	req := new(BindOauth2UserReqV1)
+	if err := controller.BindAndValidateReq(c, req); err != nil {
+		return err
+	}
+	conf, err := getEnabledOauth2Configuration()
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	claims, err := parseOauth2Claims(oauth2TokenTypeBind, req.Oauth2Token)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, errors.New(errors.LoginAuthFail, fmt.Errorf("oauth2 token is invalid: %v", err)))
+	}
+	info := &oauth2UserInfo{
+		ID:    claimString(claims, "uid"),
+		Email: claimString(claims, "email"),
+	}
+	if groups, ok := claims["groups"].([]interface{}); ok {
+		for _, group := range groups {
+			if g, ok := group.(string); ok {
+				info.Groups = append(info.Groups, g)
+			}
+		}
+	}
+	if info.ID == "" {
+		return controller.JSONBaseErrorReq(c, errors.New(errors.LoginAuthFail, fmt.Errorf("oauth2 token is invalid")))
+	}
+
+	s := model.GetStorage()
+	bound, exist, err := s.GetUserByThirdPartyUserID(info.ID)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	if exist && bound.Name != req.UserName {
+		return controller.JSONBaseErrorReq(c, errors.New(errors.DataConflict,
+			fmt.Errorf("the oauth2 user is bound to another user")))
+	}
+
+	user, exist, err := s.GetUserByName(req.UserName)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	if exist {
+		if user.ThirdPartyUserID != "" && user.ThirdPartyUserID != info.ID {
+			return controller.JSONBaseErrorReq(c, errors.New(errors.DataConflict,
+				fmt.Errorf("user %s is bound to another oauth2 user", req.UserName)))
+		}
+		loginChecker, err := GetLoginCheckerByUserName(req.UserName)
+		if err != nil {
+			return controller.JSONBaseErrorReq(c, errors.New(errors.LoginAuthFail, err))
+		}
+		if err := loginChecker.login(req.Pwd); err != nil {
+			return controller.JSONBaseErrorReq(c, errors.New(errors.LoginAuthFail, err))
+		}
+		user.ThirdPartyUserID = info.ID
+	} else {
+		user = &model.User{
+			Name:                   req.UserName,
+			Password:               req.Pwd,
+			Email:                  info.Email,
+			UserAuthenticationType: model.UserAuthenticationTypeOAUTH2,
+			ThirdPartyUserID:       info.ID,
+		}
+	}
+	if err := s.Save(user); err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+
+	token, err := loginOauth2User(c, user, conf, info)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	return c.JSON(http.StatusOK, &BindOauth2UserResV1{
+		BaseRes: controller.NewBaseReq(nil),
+		Data:    BindOauth2UserResDataV1{Token: token},
+	})
 }
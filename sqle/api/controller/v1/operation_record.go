@@ -78,6 +78,7 @@ type OperationRecordList struct {
 	OperationContent  string        `json:"operation_content"`
 	ProjectName       string        `json:"project_name"`
 	Status            string        `json:"status" enums:"succeeded,failed"`
+	ErrorMessage      string        `json:"error_message,omitempty"`
 }
 
 type OperationUser struct {
@@ -4,28 +4,169 @@
 package v1
 
 import (
-	e "errors"
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"mime"
+	"net/http"
+	"time"
 
 	"github.com/actiontech/sqle/sqle/api/controller"
-
 	"github.com/actiontech/sqle/sqle/errors"
+	"github.com/actiontech/sqle/sqle/model"
+
 	"github.com/labstack/echo/v4"
 )
 
-var errCommunityEditionDoesNotSupportFeatureOperationRecord = errors.New(errors.EnterpriseEditionFeatures, e.New("community edition does not support feature operation record"))
-
 func getOperationTypeNameList(c echo.Context) error {
-	return controller.JSONBaseErrorReq(c, errCommunityEditionDoesNotSupportFeatureOperationRecord)
+	data := make([]OperationTypeNameList, 0, len(model.OperationRecordTypes))
+	for _, typ := range model.OperationRecordTypes {
+		data = append(data, OperationTypeNameList{
+			OperationTypeName: typ,
+			Desc:              model.OperationRecordTypeDesc[typ],
+		})
+	}
+	return c.JSON(http.StatusOK, &GetOperationTypeNamesListResV1{
+		BaseRes: controller.NewBaseReq(nil),
+		Data:    data,
+	})
+}
+
+func getOperationActionDesc(action string) string {
+	if desc, ok := model.OperationRecordActionDesc[action]; ok {
+		return desc
+	}
+	return action
 }
 
 func getOperationActionList(c echo.Context) error {
-	return controller.JSONBaseErrorReq(c, errCommunityEditionDoesNotSupportFeatureOperationRecord)
+	items, err := model.GetStorage().GetOperationActionList()
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	data := make([]OperationActionList, 0, len(items))
+	for _, item := range items {
+		data = append(data, OperationActionList{
+			OperationType:   item.OperationTypeName,
+			OperationAction: item.OperationAction,
+			Desc:            getOperationActionDesc(item.OperationAction),
+		})
+	}
+	return c.JSON(http.StatusOK, &GetOperationActionListResV1{
+		BaseRes: controller.NewBaseReq(nil),
+		Data:    data,
+	})
+}
+
+func getOperationRecordQueryData(filterTimeFrom, filterTimeTo string, filterProjectName *string,
+	fuzzyUserName, filterTypeName, filterAction string) map[string]interface{} {
+	data := map[string]interface{}{
+		"filter_operate_time_from":   filterTimeFrom,
+		"filter_operate_time_to":     filterTimeTo,
+		"check_operate_project_name": filterProjectName != nil,
+		"filter_operate_type_name":   filterTypeName,
+		"filter_operate_action":      filterAction,
+	}
+	if filterProjectName != nil {
+		data["filter_operate_project_name"] = *filterProjectName
+	}
+	if fuzzyUserName != "" {
+		data["fuzzy_search_operate_user_name"] = "%" + fuzzyUserName + "%"
+	}
+	return data
+}
+
+func convertOperationRecordToRes(record *model.OperationRecord) OperationRecordList {
+	operationTime := record.OperationTime
+	return OperationRecordList{
+		ID:            uint64(record.ID),
+		OperationTime: &operationTime,
+		OperationUser: OperationUser{
+			UserName: record.OperationUserName,
+			IP:       record.OperationReqIP,
+		},
+		OperationTypeName: record.OperationTypeName,
+		OperationAction:   record.OperationAction,
+		OperationContent:  record.OperationContent,
+		ProjectName:       record.OperationProjectName,
+		Status:            record.OperationStatus,
+		ErrorMessage:      record.OperationErrorMessage,
+	}
 }
 
 func getOperationRecordList(c echo.Context) error {
-	return controller.JSONBaseErrorReq(c, errCommunityEditionDoesNotSupportFeatureOperationRecord)
+	req := new(GetOperationRecordListReqV1)
+	if err := controller.BindAndValidateReq(c, req); err != nil {
+		return err
+	}
+	limit, offset := controller.GetLimitAndOffset(req.PageIndex, req.PageSize)
+	data := getOperationRecordQueryData(req.FilterOperateTimeFrom, req.FilterOperateTimeTo, req.FilterOperateProjectName,
+		req.FuzzySearchOperateUserName, req.FilterOperateTypeName, req.FilterOperateAction)
+	data["limit"] = limit
+	data["offset"] = offset
+
+	records, count, err := model.GetStorage().GetOperationRecordsByReq(data)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	res := make([]OperationRecordList, 0, len(records))
+	for _, record := range records {
+		res = append(res, convertOperationRecordToRes(record))
+	}
+	return c.JSON(http.StatusOK, &GetOperationRecordListResV1{
+		BaseRes:   controller.NewBaseReq(nil),
+		Data:      res,
+		TotalNums: count,
+	})
 }
 
 func exportOperationRecordList(c echo.Context) error {
-	return controller.JSONBaseErrorReq(c, errCommunityEditionDoesNotSupportFeatureOperationRecord)
+	req := new(GetExportOperationRecordListReqV1)
+	if err := controller.BindAndValidateReq(c, req); err != nil {
+		return err
+	}
+	data := getOperationRecordQueryData(req.FilterOperateTimeFrom, req.FilterOperateTimeTo, req.FilterOperateProjectName,
+		req.FuzzySearchOperateUserName, req.FilterOperateTypeName, req.FilterOperateAction)
+	records, _, err := model.GetStorage().GetOperationRecordsByReq(data)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+
+	buff := &bytes.Buffer{}
+	buff.WriteString("\xEF\xBB\xBF") // 写入UTF-8 BOM
+	cw := csv.NewWriter(buff)
+	err = cw.Write([]string{"操作时间", "项目", "操作人", "操作IP", "操作对象", "操作类型", "操作内容", "操作结果", "失败原因"})
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, errors.New(errors.WriteDataToTheFileError, err))
+	}
+	for _, record := range records {
+		status := "成功"
+		if record.OperationStatus == model.OperationRecordStatusFailed {
+			status = "失败"
+		}
+		typeDesc, ok := model.OperationRecordTypeDesc[record.OperationTypeName]
+		if !ok {
+			typeDesc = record.OperationTypeName
+		}
+		err := cw.Write([]string{
+			record.OperationTime.Format("2006-01-02 15:04:05"),
+			record.OperationProjectName,
+			record.OperationUserName,
+			record.OperationReqIP,
+			typeDesc,
+			getOperationActionDesc(record.OperationAction),
+			record.OperationContent,
+			status,
+			record.OperationErrorMessage,
+		})
+		if err != nil {
+			return controller.JSONBaseErrorReq(c, errors.New(errors.WriteDataToTheFileError, err))
+		}
+	}
+	cw.Flush()
+
+	fileName := fmt.Sprintf("操作记录_%s.csv", time.Now().Format("20060102150405"))
+	c.Response().Header().Set(echo.HeaderContentDisposition,
+		mime.FormatMediaType("attachment", map[string]string{"filename": fileName}))
+	return c.Blob(http.StatusOK, "text/csv", buff.Bytes())
 }
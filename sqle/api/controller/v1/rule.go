@@ -1493,6 +1493,7 @@ type CustomRuleResV1 struct {
 	Level      string `json:"level" example:"notice" enums:"normal,notice,warn,error"`
 	Type       string `json:"type" example:"DDL规则"`
 	RuleScript string `json:"rule_script,omitempty"`
+	ScriptType string `json:"script_type,omitempty" enums:"regular,expression"`
 }
 
 type GetCustomRulesResV1 struct {
@@ -1537,6 +1538,9 @@ type CreateCustomRuleReqV1 struct {
 	Level      string `json:"level" form:"level" example:"notice" valid:"required" enums:"normal,notice,warn,error"`
 	Type       string `json:"type" form:"type" example:"DDL规则" valid:"required"`
 	RuleScript string `json:"rule_script" form:"rule_script" valid:"required"`
+	// ScriptType is regular by default, the expression is in Go syntax and can use the variables
+	// sql, sql_type, tables, fingerprint and db_type, e.g. sql_type == "dml" && !match("(?i)\\bwhere\\b", sql)
+	ScriptType string `json:"script_type" form:"script_type" enums:"regular,expression" valid:"omitempty,oneof=regular expression"`
 }
 
 // @Summary 添加自定义规则
@@ -1557,6 +1561,7 @@ type UpdateCustomRuleReqV1 struct {
 	Level      *string `json:"level" form:"level" example:"notice" enums:"normal,notice,warn,error"`
 	Type       *string `json:"type" form:"type" example:"DDL规则"`
 	RuleScript *string `json:"rule_script" form:"rule_script"`
+	ScriptType *string `json:"script_type" form:"script_type" enums:"regular,expression" valid:"omitempty,oneof=regular expression"`
 }
 
 // @Summary 更新自定义规则
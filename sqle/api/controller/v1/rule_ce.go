@@ -5,36 +5,260 @@ package v1
 
 import (
 	e "errors"
+	"fmt"
+	"net/http"
 
+	"github.com/actiontech/sqle/sqle/api/controller"
+	"github.com/actiontech/sqle/sqle/driver"
+	driverV2 "github.com/actiontech/sqle/sqle/driver/v2"
 	"github.com/actiontech/sqle/sqle/errors"
+	"github.com/actiontech/sqle/sqle/model"
+	"github.com/actiontech/sqle/sqle/server"
+	"github.com/actiontech/sqle/sqle/utils"
+
 	"github.com/labstack/echo/v4"
+	dry "github.com/ungerik/go-dry"
 )
 
-var errCommunityEditionNotSupportCustomRule = errors.New(errors.CustomRuleEditionNotSupported, e.New("custom rule community not supported"))
 var errCommunityEditionNotSupportRuleKnowledge = errors.New(errors.CustomRuleEditionNotSupported, e.New("community do not support rule knowledge"))
 
+var customRuleLevels = []string{
+	string(driverV2.RuleLevelNormal),
+	string(driverV2.RuleLevelNotice),
+	string(driverV2.RuleLevelWarn),
+	string(driverV2.RuleLevelError),
+}
+
+func checkCustomRuleLevel(level string) error {
+	if !dry.StringInSlice(level, customRuleLevels) {
+		return errors.New(errors.DataInvalid, fmt.Errorf("rule level %s is invalid", level))
+	}
+	return nil
+}
+
+func checkCustomRuleScript(scriptType, script string) error {
+	if _, err := server.CompileCustomRule(scriptType, script); err != nil {
+		return errors.New(errors.DataInvalid, fmt.Errorf("rule script is invalid: %v", err))
+	}
+	return nil
+}
+
+func convertCustomRuleToCustomRuleResV1(rule *model.CustomRule, withScript bool) CustomRuleResV1 {
+	res := CustomRuleResV1{
+		RuleId:     rule.RuleId,
+		Desc:       rule.Desc,
+		Annotation: rule.Annotation,
+		DBType:     rule.DBType,
+		Level:      rule.Level,
+		Type:       rule.Typ,
+		ScriptType: rule.ScriptType,
+	}
+	if withScript {
+		res.RuleScript = rule.RuleScript
+	}
+	return res
+}
+
 func getCustomRules(c echo.Context) error {
-	return errCommunityEditionNotSupportCustomRule
+	req := new(GetCustomRulesReqV1)
+	if err := controller.BindAndValidateReq(c, req); err != nil {
+		return err
+	}
+	rules, err := model.GetStorage().GetCustomRulesByDBTypeAndFuzzyDesc(
+		"rule_id, `desc`, annotation, db_type, level, type, script_type", req.FilterDBType, req.FilterDesc)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	data := make([]CustomRuleResV1, 0, len(rules))
+	for _, rule := range rules {
+		data = append(data, convertCustomRuleToCustomRuleResV1(rule, false))
+	}
+	return c.JSON(http.StatusOK, &GetCustomRulesResV1{
+		BaseRes: controller.NewBaseReq(nil),
+		Data:    data,
+	})
 }
 
 func deleteCustomRule(c echo.Context) error {
-	return errCommunityEditionNotSupportCustomRule
+	ruleId := c.Param("rule_id")
+	s := model.GetStorage()
+	_, exist, err := s.GetCustomRuleByRuleId(ruleId)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	if !exist {
+		return controller.JSONBaseErrorReq(c, errors.New(errors.DataNotExist, fmt.Errorf("rule is not exist")))
+	}
+	if err := s.DeleteCustomRule(ruleId); err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	return c.JSON(http.StatusOK, controller.NewBaseReq(nil))
 }
 
 func createCustomRule(c echo.Context) error {
-	return errCommunityEditionNotSupportCustomRule
+	req := new(CreateCustomRuleReqV1)
+	if err := controller.BindAndValidateReq(c, req); err != nil {
+		return err
+	}
+	if !dry.StringInSlice(req.DBType, driver.GetPluginManager().AllDrivers()) {
+		return controller.JSONBaseErrorReq(c, errors.New(errors.DriverNotExist, fmt.Errorf("db type %s is not supported", req.DBType)))
+	}
+	if err := checkCustomRuleLevel(req.Level); err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	if req.ScriptType == "" {
+		req.ScriptType = model.CustomRuleScriptTypeRegular
+	}
+	if err := checkCustomRuleScript(req.ScriptType, req.RuleScript); err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+
+	s := model.GetStorage()
+	_, exist, err := s.GetCustomRulesByDescAndDBType(req.Desc, req.DBType)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	if exist {
+		return controller.JSONBaseErrorReq(c, errors.New(errors.DataExist, fmt.Errorf("rule with the same desc is exist")))
+	}
+
+	ruleId, err := utils.GenUid()
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	rule := &model.CustomRule{
+		RuleId:     ruleId,
+		Desc:       req.Desc,
+		Annotation: req.Annotation,
+		DBType:     req.DBType,
+		Level:      req.Level,
+		Typ:        req.Type,
+		RuleScript: req.RuleScript,
+		ScriptType: req.ScriptType,
+	}
+	if err := s.Save(rule); err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	return c.JSON(http.StatusOK, controller.NewBaseReq(nil))
 }
 
 func updateCustomRule(c echo.Context) error {
-	return errCommunityEditionNotSupportCustomRule
+	ruleId := c.Param("rule_id")
+	req := new(UpdateCustomRuleReqV1)
+	if err := controller.BindAndValidateReq(c, req); err != nil {
+		return err
+	}
+	s := model.GetStorage()
+	rule, exist, err := s.GetCustomRuleByRuleId(ruleId)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	if !exist {
+		return controller.JSONBaseErrorReq(c, errors.New(errors.DataNotExist, fmt.Errorf("rule is not exist")))
+	}
+
+	attrs := map[string]interface{}{}
+	if req.Desc != nil && *req.Desc != rule.Desc {
+		another, exist, err := s.GetCustomRulesByDescAndDBType(*req.Desc, rule.DBType)
+		if err != nil {
+			return controller.JSONBaseErrorReq(c, err)
+		}
+		if exist && another.RuleId != rule.RuleId {
+			return controller.JSONBaseErrorReq(c, errors.New(errors.DataExist, fmt.Errorf("rule with the same desc is exist")))
+		}
+		attrs["desc"] = *req.Desc
+	}
+	if req.Annotation != nil {
+		attrs["annotation"] = *req.Annotation
+	}
+	if req.Level != nil {
+		if err := checkCustomRuleLevel(*req.Level); err != nil {
+			return controller.JSONBaseErrorReq(c, err)
+		}
+		attrs["level"] = *req.Level
+	}
+	if req.Type != nil {
+		attrs["type"] = *req.Type
+	}
+	if req.RuleScript != nil || req.ScriptType != nil {
+		script, scriptType := rule.RuleScript, rule.ScriptType
+		if req.RuleScript != nil {
+			script = *req.RuleScript
+		}
+		if req.ScriptType != nil {
+			scriptType = *req.ScriptType
+		}
+		if err := checkCustomRuleScript(scriptType, script); err != nil {
+			return controller.JSONBaseErrorReq(c, err)
+		}
+		attrs["rule_script"] = script
+		attrs["script_type"] = scriptType
+	}
+	if len(attrs) == 0 {
+		return c.JSON(http.StatusOK, controller.NewBaseReq(nil))
+	}
+	if err := s.UpdateCustomRuleByRuleId(ruleId, attrs); err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	return c.JSON(http.StatusOK, controller.NewBaseReq(nil))
 }
 
 func getCustomRule(c echo.Context) error {
-	return errCommunityEditionNotSupportCustomRule
+	rule, exist, err := model.GetStorage().GetCustomRuleByRuleId(c.Param("rule_id"))
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	if !exist {
+		return controller.JSONBaseErrorReq(c, errors.New(errors.DataNotExist, fmt.Errorf("rule is not exist")))
+	}
+	return c.JSON(http.StatusOK, &GetCustomRuleResV1{
+		BaseRes: controller.NewBaseReq(nil),
+		Data:    convertCustomRuleToCustomRuleResV1(rule, true),
+	})
 }
 
 func getRuleTypeByDBType(c echo.Context) error {
-	return errCommunityEditionNotSupportCustomRule
+	dbType := c.QueryParam("db_type")
+	if dbType == "" {
+		dbType = c.Param("db_type")
+	}
+	s := model.GetStorage()
+	builtinTypes, err := s.GetRuleTypeByDBType(dbType)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	customTypeCounts, err := s.GetCustomRuleTypeCountByDBType(dbType)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+
+	counts := make(map[string]uint, len(customTypeCounts))
+	for _, tc := range customTypeCounts {
+		counts[tc.Type] = tc.TypeCount
+	}
+	data := make([]RuleTypeV1, 0, len(builtinTypes)+len(customTypeCounts))
+	builtin := make(map[string]struct{}, len(builtinTypes))
+	for _, typ := range builtinTypes {
+		builtin[typ] = struct{}{}
+		data = append(data, RuleTypeV1{
+			RuleType:  typ,
+			RuleCount: counts[typ],
+		})
+	}
+	for _, tc := range customTypeCounts {
+		if _, ok := builtin[tc.Type]; ok {
+			continue
+		}
+		data = append(data, RuleTypeV1{
+			RuleType:         tc.Type,
+			RuleCount:        tc.TypeCount,
+			IsCustomRuleType: true,
+		})
+	}
+	return c.JSON(http.StatusOK, &GetRuleTypeByDBTypeResV1{
+		BaseRes: controller.NewBaseReq(nil),
+		Data:    data,
+	})
 }
 
 func getRuleKnowledge(c echo.Context) error {
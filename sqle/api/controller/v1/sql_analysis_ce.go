@@ -0,0 +1,171 @@
+//go:build !enterprise
+// +build !enterprise
+
+package v1
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/actiontech/sqle/sqle/common"
+	"github.com/actiontech/sqle/sqle/driver"
+	driverV2 "github.com/actiontech/sqle/sqle/driver/v2"
+	"github.com/actiontech/sqle/sqle/errors"
+	"github.com/actiontech/sqle/sqle/log"
+	"github.com/actiontech/sqle/sqle/model"
+
+	"github.com/labstack/echo/v4"
+)
+
+// SQLAnalysisResult is the analysis of a single SQL on instance, each part of it is collected
+// independently, so the failure of one part is recorded in its error instead of failing the others.
+type SQLAnalysisResult struct {
+	ExplainResult *driverV2.ExplainResult
+	ExplainErr    error
+
+	TableMetaResult *driver.GetTableMetaBySQLResult
+	TableMetaErr    error
+
+	AffectRows    *driverV2.EstimatedAffectRows
+	AffectRowsErr error
+}
+
+func GetSQLAnalysisResult(instance *model.Instance, schema, sql string) (*SQLAnalysisResult, error) {
+	if instance == nil {
+		return nil, errors.New(errors.DataNotExist, fmt.Errorf("SQL analysis requires an instance"))
+	}
+	plugin, err := common.NewDriverManagerWithoutAudit(log.NewEntry(), instance, schema)
+	if err != nil {
+		return nil, err
+	}
+	defer plugin.Close(context.TODO())
+
+	return getSQLAnalysisResultByPlugin(plugin, instance.DbType, sql), nil
+}
+
+func getSQLAnalysisResultByPlugin(plugin driver.Plugin, dbType, sql string) *SQLAnalysisResult {
+	pm := driver.GetPluginManager()
+	res := &SQLAnalysisResult{}
+	if pm.IsOptionalModuleEnabled(dbType, driverV2.OptionalModuleExplain) {
+		res.ExplainResult, res.ExplainErr = plugin.Explain(context.TODO(), &driverV2.ExplainConf{Sql: sql})
+	} else {
+		res.ExplainErr = driver.NewErrPluginAPINotImplement(driverV2.OptionalModuleExplain)
+	}
+
+	if pm.IsOptionalModuleEnabled(dbType, driverV2.OptionalModuleGetTableMeta) &&
+		pm.IsOptionalModuleEnabled(dbType, driverV2.OptionalModuleExtractTableFromSQL) {
+		res.TableMetaResult, res.TableMetaErr = plugin.GetTableMetaBySQL(context.TODO(), &driver.GetTableMetaBySQLConf{Sql: sql})
+	} else {
+		res.TableMetaErr = driver.NewErrPluginAPINotImplement(driverV2.OptionalModuleGetTableMeta)
+	}
+
+	if pm.IsOptionalModuleEnabled(dbType, driverV2.OptionalModuleEstimateSQLAffectRows) {
+		res.AffectRows, res.AffectRowsErr = plugin.EstimateSQLAffectRows(context.TODO(), sql)
+	} else {
+		res.AffectRowsErr = driver.NewErrPluginAPINotImplement(driverV2.OptionalModuleEstimateSQLAffectRows)
+	}
+	return res
+}
+
+func ConvertExplainResultToRes(sql string, result *driverV2.ExplainResult, err error) SQLExplain {
+	explain := SQLExplain{SQL: sql}
+	if err != nil {
+		explain.Message = err.Error()
+		return explain
+	}
+	explain.ClassicResult.Head, explain.ClassicResult.Rows = convertTabularDataToRes(result.ClassicResult.TabularData)
+	return explain
+}
+
+func ConvertTableMetasToRes(result *driver.GetTableMetaBySQLResult) []TableMeta {
+	if result == nil {
+		return []TableMeta{}
+	}
+	tableMetas := make([]TableMeta, 0, len(result.TableMetas))
+	for _, tm := range result.TableMetas {
+		meta := TableMeta{
+			Name:           tm.Name,
+			Schema:         tm.Schema,
+			CreateTableSQL: tm.CreateTableSQL,
+			Message:        tm.Message,
+		}
+		meta.Columns.Head, meta.Columns.Rows = convertTabularDataToRes(tm.ColumnsInfo.TabularData)
+		meta.Indexes.Head, meta.Indexes.Rows = convertTabularDataToRes(tm.IndexesInfo.TabularData)
+		tableMetas = append(tableMetas, meta)
+	}
+	return tableMetas
+}
+
+// GetTaskSQLForAnalysis returns the task and its SQL of number, if current user can view the task.
+func GetTaskSQLForAnalysis(c echo.Context, taskId, number string) (*model.Task, *model.ExecuteSQL, error) {
+	s := model.GetStorage()
+	task, exist, err := s.GetTaskById(taskId)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !exist {
+		return nil, nil, errors.NewTaskNoExistOrNoAccessErr()
+	}
+	if err := CheckCurrentUserCanViewTask(c, task); err != nil {
+		return nil, nil, err
+	}
+	if task.InstanceId == 0 {
+		return nil, nil, errors.New(errors.DataInvalid, fmt.Errorf("SQL analysis is not supported for offline audit task"))
+	}
+	executeSQL, exist, err := s.GetTaskSQLByNumber(taskId, number)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !exist {
+		return nil, nil, errors.New(errors.DataNotExist, fmt.Errorf("sql number not found"))
+	}
+	return task, executeSQL, nil
+}
+
+// GetAuditPlanReportSQLForAnalysis returns the audit plan, its instance and the report SQL of number,
+// if current user can view the audit plan.
+func GetAuditPlanReportSQLForAnalysis(c echo.Context, projectName, auditPlanName, reportId, number string) (
+	*model.AuditPlan, *model.Instance, *model.AuditPlanReportSQLV2, error) {
+
+	ap, exist, err := GetAuditPlanIfCurrentUserCanAccess(c, projectName, auditPlanName, model.OP_AUDIT_PLAN_VIEW_OTHERS)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if !exist {
+		return nil, nil, nil, errors.NewAuditPlanNotExistErr()
+	}
+	if ap.InstanceName == "" {
+		return nil, nil, nil, errors.New(errors.DataInvalid, fmt.Errorf("SQL analysis is not supported for audit plan without instance"))
+	}
+
+	s := model.GetStorage()
+	id, err := FormatStringToUint64(reportId)
+	if err != nil {
+		return nil, nil, nil, errors.New(errors.DataInvalid, fmt.Errorf("invalid audit plan report id: %v", reportId))
+	}
+	sqlNumber, err := FormatStringToUint64(number)
+	if err != nil {
+		return nil, nil, nil, errors.New(errors.DataInvalid, fmt.Errorf("invalid sql number: %v", number))
+	}
+	if _, exist, err := s.GetAuditPlanReportByID(ap.ID, uint(id)); err != nil {
+		return nil, nil, nil, err
+	} else if !exist {
+		return nil, nil, nil, errors.New(errors.DataNotExist, fmt.Errorf("audit plan report not exist"))
+	}
+	reportSQL, exist, err := s.GetAuditPlanReportSQLV2ByReportIDAndNumber(uint(id), uint(sqlNumber))
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if !exist {
+		return nil, nil, nil, errors.New(errors.DataNotExist, fmt.Errorf("audit plan report sql not exist"))
+	}
+
+	instance, exist, err := s.GetInstanceByNameAndProjectID(ap.InstanceName, ap.ProjectId)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if !exist {
+		return nil, nil, nil, errors.New(errors.DataNotExist, fmt.Errorf("instance %s of audit plan not exist", ap.InstanceName))
+	}
+	return ap, instance, reportSQL, nil
+}
@@ -231,7 +231,7 @@ type GetSQLAnalysisReq struct {
 	ProjectName  string `json:"project_name" query:"project_name" example:"default" valid:"required"`
 	InstanceName string `json:"instance_name" query:"instance_name" example:"MySQL" valid:"required"`
 	SchemaName   string `json:"schema_name" query:"schema_name" example:"test"`
-	Sql          string `json:"sql" query:"sql" example:"select * from t1; select * from t2;"`
+	Sql          string `json:"sql" query:"sql" example:"select * from t1; select * from t2;" valid:"required"`
 }
 
 type DirectGetSQLAnalysisResV1 struct {
@@ -240,19 +240,27 @@ type DirectGetSQLAnalysisResV1 struct {
 }
 
 type SqlAnalysisResDataV1 struct {
-	SQLExplain SQLExplain  `json:"sql_explain"`
-	TableMetas []TableMeta `json:"table_metas"`
+	SQLExplain   SQLExplain          `json:"sql_explain"`
+	TableMetas   []TableMeta         `json:"table_metas"`
+	AuditResult  AuditSQLResV1       `json:"audit_result"`
+	IndexAdvices []*IndexAdviceResV1 `json:"index_advices"`
+	AffectRows   AffectRowsResV1     `json:"affect_rows"`
+}
+
+type AffectRowsResV1 struct {
+	Count      int64  `json:"count"`
+	ErrMessage string `json:"err_message"`
 }
 
 // DirectGetSQLAnalysis
 // @Summary 直接获取SQL分析结果
-// @Description Direct get sql analysis result
+// @Description Direct get sql analysis result, including audit result, explain, table metadata, index advices and affect rows of each sql
 // @Id directGetSQLAnalysisV1
 // @Tags sql_analysis
 // @Param project_name query string true "project name"
 // @Param instance_name query string true "instance name"
 // @Param schema_name query string false "schema name"
-// @Param sql query string false "sql"
+// @Param sql query string true "sql"
 // @Security ApiKeyAuth
 // @Success 200 {object} v1.DirectGetSQLAnalysisResV1
 // @router /v1/sql_analysis [get]
@@ -4,12 +4,89 @@
 package v1
 
 import (
-	e "errors"
+	"context"
+	"net/http"
+
+	"github.com/actiontech/sqle/sqle/api/controller"
+	"github.com/actiontech/sqle/sqle/common"
+	"github.com/actiontech/sqle/sqle/log"
+	"github.com/actiontech/sqle/sqle/model"
+	"github.com/actiontech/sqle/sqle/server"
 
-	"github.com/actiontech/sqle/sqle/errors"
 	"github.com/labstack/echo/v4"
 )
 
 func directGetSQLAnalysis(c echo.Context) error {
-	return errors.New(errors.SQLAnalysisCommunityNotSupported, e.New("sql analysis community not supported"))
+	req := new(GetSQLAnalysisReq)
+	if err := controller.BindAndValidateReq(c, req); err != nil {
+		return err
+	}
+	if err := CheckIsProjectMember(controller.GetUserName(c), req.ProjectName); err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+
+	instance, exist, err := model.GetStorage().GetInstanceByNameAndProjectName(req.InstanceName, req.ProjectName)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	if !exist {
+		return controller.JSONBaseErrorReq(c, ErrInstanceNotExist)
+	}
+	can, err := checkCurrentUserCanAccessInstance(c, instance)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	if !can {
+		return controller.JSONBaseErrorReq(c, ErrInstanceNoAccess)
+	}
+
+	l := log.NewEntry().WithField("api", "[get]/v1/sql_analysis")
+	task, err := server.DirectAuditByInstance(l, req.Sql, req.SchemaName, instance)
+	if err != nil {
+		l.Errorf("audit sqls failed: %v", err)
+		return controller.JSONBaseErrorReq(c, ErrDirectAudit)
+	}
+
+	plugin, err := common.NewDriverManagerWithoutAudit(l, instance, req.SchemaName)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	defer plugin.Close(context.TODO())
+
+	indexAdvices := convertIndexAdvicesToRes(server.GetTaskIndexAdvices(task))
+	data := make([]*SqlAnalysisResDataV1, 0, len(task.ExecuteSQLs))
+	for _, executeSQL := range task.ExecuteSQLs {
+		res := getSQLAnalysisResultByPlugin(plugin, instance.DbType, executeSQL.Content)
+		item := &SqlAnalysisResDataV1{
+			SQLExplain: ConvertExplainResultToRes(executeSQL.Content, res.ExplainResult, res.ExplainErr),
+			TableMetas: ConvertTableMetasToRes(res.TableMetaResult),
+			AuditResult: AuditSQLResV1{
+				Number:      executeSQL.Number,
+				ExecSQL:     executeSQL.Content,
+				AuditResult: executeSQL.GetAuditResults(),
+				AuditLevel:  executeSQL.AuditLevel,
+			},
+			IndexAdvices: []*IndexAdviceResV1{},
+		}
+		for _, advice := range indexAdvices {
+			for _, number := range advice.SQLNumbers {
+				if number == executeSQL.Number {
+					item.IndexAdvices = append(item.IndexAdvices, advice)
+					break
+				}
+			}
+		}
+		if res.AffectRowsErr != nil {
+			item.AffectRows.ErrMessage = res.AffectRowsErr.Error()
+		} else {
+			item.AffectRows.Count = res.AffectRows.Count
+			item.AffectRows.ErrMessage = res.AffectRows.ErrMessage
+		}
+		data = append(data, item)
+	}
+
+	return c.JSON(http.StatusOK, &DirectGetSQLAnalysisResV1{
+		BaseRes: controller.NewBaseReq(nil),
+		Data:    data,
+	})
 }
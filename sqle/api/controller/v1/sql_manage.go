@@ -80,7 +80,7 @@ func GetSqlManageList(c echo.Context) error {
 
 type BatchUpdateSqlManageReq struct {
 	SqlManageIdList []*uint64 `json:"sql_manage_id_list"`
-	Status          *string   `json:"status" enums:"solved,ignored,manual_audited"`
+	Status          *string   `json:"status" enums:"unhandled,solved,ignored,manual_audited"`
 	Assignees       []*string `json:"assignees"`
 	Remark          *string   `json:"remark"`
 }
@@ -108,6 +108,7 @@ type ExportSqlManagesReq struct {
 	FilterLastAuditStartTimeFrom *string `query:"filter_last_audit_start_time_from" json:"filter_last_audit_start_time_from,omitempty"`
 	FilterLastAuditStartTimeTo   *string `query:"filter_last_audit_start_time_to" json:"filter_last_audit_start_time_to,omitempty"`
 	FilterStatus                 *string `query:"filter_status" json:"filter_status,omitempty"`
+	ExportFormat                 string  `query:"export_format" json:"export_format,omitempty" valid:"omitempty,oneof=csv xlsx"`
 }
 
 // ExportSqlManagesV1
@@ -125,6 +126,7 @@ type ExportSqlManagesReq struct {
 // @Param filter_last_audit_start_time_from query string false "last audit start time from"
 // @Param filter_last_audit_start_time_to query string false "last audit start time to"
 // @Param filter_status query string false "status" Enums(unhandled,solved,ignored,manual_audited)
+// @Param export_format query string false "export format, default is csv" Enums(csv,xlsx)
 // @Success 200 {file} file "export sql manage"
 // @Router /v1/projects/{project_name}/sql_manages/exports [get]
 func ExportSqlManagesV1(c echo.Context) error {
@@ -4,22 +4,241 @@
 package v1
 
 import (
-	e "errors"
+	"bytes"
+	"fmt"
+	"mime"
+	"net/http"
+	"strings"
+	"time"
 
+	"github.com/actiontech/sqle/sqle/api/controller"
 	"github.com/actiontech/sqle/sqle/errors"
+	"github.com/actiontech/sqle/sqle/model"
+
 	"github.com/labstack/echo/v4"
 )
 
-var ErrCommunityEditionNotSupportSqlManage = errors.New(errors.EnterpriseEditionFeatures, e.New("sql manage is enterprise version feature"))
+var sqlManageBatchUpdateStatus = map[string]struct{}{
+	model.SQLManageStatusUnhandled:     {},
+	model.SQLManageStatusSolved:        {},
+	model.SQLManageStatusIgnored:       {},
+	model.SQLManageStatusManualAudited: {},
+}
+
+func getSqlManageQueryData(projectName string, fuzzySearchSqlFingerprint, filterAssignee, filterInstanceName, filterSource,
+	filterAuditLevel, filterLastAuditStartTimeFrom, filterLastAuditStartTimeTo, filterStatus *string) map[string]interface{} {
+	data := map[string]interface{}{
+		"project_name": projectName,
+	}
+	if fuzzySearchSqlFingerprint != nil && *fuzzySearchSqlFingerprint != "" {
+		data["fuzzy_search_sql_fingerprint"] = "%" + *fuzzySearchSqlFingerprint + "%"
+	}
+	filters := map[string]*string{
+		"filter_assignee":                   filterAssignee,
+		"filter_instance_name":              filterInstanceName,
+		"filter_source":                     filterSource,
+		"filter_audit_level":                filterAuditLevel,
+		"filter_last_audit_start_time_from": filterLastAuditStartTimeFrom,
+		"filter_last_audit_start_time_to":   filterLastAuditStartTimeTo,
+		"filter_status":                     filterStatus,
+	}
+	for key, value := range filters {
+		if value != nil && *value != "" {
+			data[key] = *value
+		}
+	}
+	return data
+}
+
+func convertSqlManageDetailToRes(sm *model.SqlManageDetail) *SqlManage {
+	source := &Source{
+		Type:              sm.Source,
+		SqlAuditRecordIds: []string(sm.SqlAuditRecordIDs),
+	}
+	if sm.ApName != nil {
+		source.AuditPlanName = *sm.ApName
+	}
+	auditResults := make([]*AuditResult, 0, len(sm.AuditResults))
+	for _, result := range sm.AuditResults {
+		auditResults = append(auditResults, &AuditResult{
+			Level:    result.Level,
+			Message:  result.Message,
+			RuleName: result.RuleName,
+		})
+	}
+	assignees := []string(sm.Assignees)
+	if assignees == nil {
+		assignees = []string{}
+	}
+	return &SqlManage{
+		Id:              uint64(sm.ID),
+		SqlFingerprint:  sm.SqlFingerprint,
+		Sql:             sm.SqlText,
+		Source:          source,
+		InstanceName:    sm.InstanceName,
+		SchemaName:      sm.SchemaName,
+		AuditResult:     auditResults,
+		FirstAppearTime: sm.FirstAppearTime(),
+		LastAppearTime:  sm.LastReceiveTime(),
+		AppearNum:       sm.FpCount,
+		Assignees:       assignees,
+		Status:          sm.Status,
+		Remark:          sm.Remark,
+	}
+}
+
+func checkCurrentUserCanAccessSqlManage(c echo.Context, projectName string) error {
+	s := model.GetStorage()
+	_, exist, err := s.GetProjectByName(projectName)
+	if err != nil {
+		return err
+	}
+	if !exist {
+		return ErrProjectNotExist(projectName)
+	}
+	return CheckIsProjectMember(controller.GetUserName(c), projectName)
+}
 
 func getSqlManageList(c echo.Context) error {
-	return ErrCommunityEditionNotSupportSqlManage
+	req := new(GetSqlManageListReq)
+	if err := controller.BindAndValidateReq(c, req); err != nil {
+		return err
+	}
+	projectName := c.Param("project_name")
+	if err := checkCurrentUserCanAccessSqlManage(c, projectName); err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+
+	limit, offset := controller.GetLimitAndOffset(req.PageIndex, req.PageSize)
+	data := getSqlManageQueryData(projectName, req.FuzzySearchSqlFingerprint, req.FilterAssignee, req.FilterInstanceName,
+		req.FilterSource, req.FilterAuditLevel, req.FilterLastAuditStartTimeFrom, req.FilterLastAuditStartTimeTo, req.FilterStatus)
+	data["limit"] = limit
+	data["offset"] = offset
+
+	result, err := model.GetStorage().GetSqlManageListByReq(data)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, errors.New(errors.ConnectStorageError, err))
+	}
+	list := make([]*SqlManage, 0, len(result.SqlManageList))
+	for _, sm := range result.SqlManageList {
+		list = append(list, convertSqlManageDetailToRes(sm))
+	}
+	return c.JSON(http.StatusOK, &GetSqlManageListResp{
+		BaseRes:               controller.NewBaseReq(nil),
+		Data:                  list,
+		SqlManageTotalNum:     result.SqlManageTotalNum,
+		SqlManageBadNum:       result.SqlManageBadNum,
+		SqlManageOptimizedNum: result.SqlManageOptimizedNum,
+	})
 }
 
 func batchUpdateSqlManage(c echo.Context) error {
-	return ErrCommunityEditionNotSupportSqlManage
+	req := new(BatchUpdateSqlManageReq)
+	if err := controller.BindAndValidateReq(c, req); err != nil {
+		return err
+	}
+	projectName := c.Param("project_name")
+	if err := checkCurrentUserCanAccessSqlManage(c, projectName); err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	if len(req.SqlManageIdList) == 0 {
+		return controller.JSONBaseErrorReq(c, errors.NewDataInvalidErr("sql manage id list is empty"))
+	}
+	if req.Status != nil {
+		if _, ok := sqlManageBatchUpdateStatus[*req.Status]; !ok {
+			return controller.JSONBaseErrorReq(c, errors.NewDataInvalidErr("status %s is invalid", *req.Status))
+		}
+	}
+
+	s := model.GetStorage()
+	project, _, err := s.GetProjectByName(projectName)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	sqlManageList, err := s.GetSqlManageListByIDs(req.SqlManageIdList)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, errors.New(errors.ConnectStorageError, err))
+	}
+	if len(sqlManageList) != len(req.SqlManageIdList) {
+		return controller.JSONBaseErrorReq(c, errors.NewDataNotExistErr("some of sql manage are not exist"))
+	}
+	for _, sm := range sqlManageList {
+		if sm.ProjectId != project.ID {
+			return controller.JSONBaseErrorReq(c, errors.NewDataNotExistErr("sql manage %d is not in project %s", sm.ID, projectName))
+		}
+	}
+
+	for _, assignee := range req.Assignees {
+		if assignee == nil {
+			continue
+		}
+		if err := CheckIsProjectMember(*assignee, projectName); err != nil {
+			return controller.JSONBaseErrorReq(c, err)
+		}
+	}
+
+	err = s.BatchUpdateSqlManage(req.SqlManageIdList, req.Status, req.Remark, req.Assignees)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, errors.New(errors.ConnectStorageError, err))
+	}
+	return c.JSON(http.StatusOK, controller.NewBaseReq(nil))
 }
 
 func exportSqlManagesV1(c echo.Context) error {
-	return ErrCommunityEditionNotSupportSqlManage
+	req := new(ExportSqlManagesReq)
+	if err := controller.BindAndValidateReq(c, req); err != nil {
+		return err
+	}
+	projectName := c.Param("project_name")
+	if err := checkCurrentUserCanAccessSqlManage(c, projectName); err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+
+	data := getSqlManageQueryData(projectName, req.FuzzySearchSqlFingerprint, req.FilterAssignee, req.FilterInstanceName,
+		req.FilterSource, req.FilterAuditLevel, req.FilterLastAuditStartTimeFrom, req.FilterLastAuditStartTimeTo, req.FilterStatus)
+	result, err := model.GetStorage().GetSqlManageListByReq(data)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, errors.New(errors.ConnectStorageError, err))
+	}
+
+	buff := &bytes.Buffer{}
+	cw, contentType, ext, err := newExportWriter(buff, req.ExportFormat, "SQL管控")
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, errors.New(errors.WriteDataToTheFileError, err))
+	}
+	err = cw.Write([]string{"SQL指纹", "SQL", "来源", "数据源", "SCHEMA", "审核结果", "初次出现时间", "最后一次出现时间", "出现数量", "负责人", "状态", "备注"})
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, errors.New(errors.WriteDataToTheFileError, err))
+	}
+	for _, sm := range result.SqlManageList {
+		source := model.SqlManageSourceMap[sm.Source]
+		if sm.ApName != nil && *sm.ApName != "" {
+			source = fmt.Sprintf("%s: %s", source, *sm.ApName)
+		}
+		err := cw.Write([]string{
+			sm.SqlFingerprint,
+			sm.SqlText,
+			source,
+			sm.InstanceName,
+			sm.SchemaName,
+			sm.AuditResults.String(),
+			sm.FirstAppearTime(),
+			sm.LastReceiveTime(),
+			fmt.Sprintf("%d", sm.FpCount),
+			strings.Join(sm.Assignees, ","),
+			model.SqlManageStatusMap[sm.Status],
+			sm.Remark,
+		})
+		if err != nil {
+			return controller.JSONBaseErrorReq(c, errors.New(errors.WriteDataToTheFileError, err))
+		}
+	}
+	if err := cw.Close(); err != nil {
+		return controller.JSONBaseErrorReq(c, errors.New(errors.WriteDataToTheFileError, err))
+	}
+
+	fileName := fmt.Sprintf("SQL管控_%s_%s.%s", projectName, time.Now().Format("20060102150405"), ext)
+	c.Response().Header().Set(echo.HeaderContentDisposition,
+		mime.FormatMediaType("attachment", map[string]string{"filename": fileName}))
+	return c.Blob(http.StatusOK, contentType, buff.Bytes())
 }
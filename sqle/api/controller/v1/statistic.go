@@ -30,6 +30,9 @@ type GetWorkflowCountsResV1 struct {
 // @Tags statistic
 // @Id getWorkflowCountV1
 // @Security ApiKeyAuth
+// @Param filter_project_name query string false "filter project name"
+// @Param filter_date_from query string false "filter create date from.(format:yyyy-mm-dd)"
+// @Param filter_date_to query string false "filter create date to.(format:yyyy-mm-dd)"
 // @Success 200 {object} v1.GetWorkflowCountsResV1
 // @router /v1/statistic/workflows/counts [get]
 func GetWorkflowCountsV1(c echo.Context) error {
@@ -51,6 +54,9 @@ type GetWorkflowDurationOfWaitingForAuditResV1 struct {
 // @Tags statistic
 // @Id getWorkflowDurationOfWaitingForAuditV1
 // @Security ApiKeyAuth
+// @Param filter_project_name query string false "filter project name"
+// @Param filter_date_from query string false "filter create date from.(format:yyyy-mm-dd)"
+// @Param filter_date_to query string false "filter create date to.(format:yyyy-mm-dd)"
 // @Success 200 {object} v1.GetWorkflowDurationOfWaitingForAuditResV1
 // @router /v1/statistic/workflows/duration_of_waiting_for_audit [get]
 func GetWorkflowDurationOfWaitingForAuditV1(c echo.Context) error {
@@ -80,6 +86,9 @@ type SqlAverageExecutionTime struct {
 // @Id getSqlAverageExecutionTimeV1
 // @Security ApiKeyAuth
 // @Param limit query uint true "the limit of result item number"
+// @Param filter_project_name query string false "filter project name"
+// @Param filter_date_from query string false "filter create date from.(format:yyyy-mm-dd)"
+// @Param filter_date_to query string false "filter create date to.(format:yyyy-mm-dd)"
 // @Success 200 {object} v1.GetSqlAverageExecutionTimeResV1
 // @router /v1/statistic/instances/sql_average_execution_time [get]
 func GetSqlAverageExecutionTimeV1(c echo.Context) error {
@@ -92,12 +101,14 @@ type GetWorkflowDurationOfWaitingForExecutionResV1 struct {
 }
 
 // GetWorkflowDurationOfWaitingForExecutionV1
-// @Deprecated
 // @Summary 获取工单各从审核完毕到执行上线的平均时长
-// @Description get duration from workflow being created to executed
+// @Description get duration from workflow being audited to executed
 // @Tags statistic
 // @Id getWorkflowDurationOfWaitingForExecutionV1
 // @Security ApiKeyAuth
+// @Param filter_project_name query string false "filter project name"
+// @Param filter_date_from query string false "filter create date from.(format:yyyy-mm-dd)"
+// @Param filter_date_to query string false "filter create date to.(format:yyyy-mm-dd)"
 // @Success 200 {object} v1.GetWorkflowDurationOfWaitingForExecutionResV1
 // @router /v1/statistic/workflows/duration_of_waiting_for_execution [get]
 func GetWorkflowDurationOfWaitingForExecutionV1(c echo.Context) error {
@@ -142,6 +153,9 @@ type GetWorkflowAuditPassPercentResV1 struct {
 // @Tags statistic
 // @Id getWorkflowAuditPassPercentV1
 // @Security ApiKeyAuth
+// @Param filter_project_name query string false "filter project name"
+// @Param filter_date_from query string false "filter create date from.(format:yyyy-mm-dd)"
+// @Param filter_date_to query string false "filter create date to.(format:yyyy-mm-dd)"
 // @Success 200 {object} v1.GetWorkflowAuditPassPercentResV1
 // @router /v1/statistic/workflows/audit_pass_percent [get]
 func GetWorkflowAuditPassPercentV1(c echo.Context) error {
@@ -175,6 +189,7 @@ type GetWorkflowCreatedCountsEachDayResV1 struct {
 // @Security ApiKeyAuth
 // @Param filter_date_from query string true "filter date from.(format:yyyy-mm-dd)"
 // @Param filter_date_to query string true "filter date to.(format:yyyy-mm-dd)"
+// @Param filter_project_name query string false "filter project name"
 // @Success 200 {object} v1.GetWorkflowCreatedCountsEachDayResV1
 // @router /v1/statistic/workflows/each_day_counts [get]
 func GetWorkflowCreatedCountsEachDayV1(c echo.Context) error {
@@ -202,6 +217,9 @@ type GetWorkflowStatusCountResV1 struct {
 // @Tags statistic
 // @Id getWorkflowStatusCountV1
 // @Security ApiKeyAuth
+// @Param filter_project_name query string false "filter project name"
+// @Param filter_date_from query string false "filter create date from.(format:yyyy-mm-dd)"
+// @Param filter_date_to query string false "filter create date to.(format:yyyy-mm-dd)"
 // @Success 200 {object} v1.GetWorkflowStatusCountResV1
 // @router /v1/statistic/workflows/status_count [get]
 func GetWorkflowStatusCountV1(c echo.Context) error {
@@ -230,6 +248,9 @@ type GetWorkflowPercentCountedByInstanceTypeResV1 struct {
 // @Tags statistic
 // @Id getWorkflowPercentCountedByInstanceTypeV1
 // @Security ApiKeyAuth
+// @Param filter_project_name query string false "filter project name"
+// @Param filter_date_from query string false "filter create date from.(format:yyyy-mm-dd)"
+// @Param filter_date_to query string false "filter create date to.(format:yyyy-mm-dd)"
 // @Success 200 {object} v1.GetWorkflowPercentCountedByInstanceTypeResV1
 // @router /v1/statistic/workflows/instance_type_percent [get]
 func GetWorkflowPercentCountedByInstanceTypeV1(c echo.Context) error {
@@ -258,6 +279,9 @@ type GetWorkflowRejectedPercentGroupByCreatorResV1 struct {
 // @Id getWorkflowRejectedPercentGroupByCreatorV1
 // @Security ApiKeyAuth
 // @Param limit query uint true "the limit of result item number"
+// @Param filter_project_name query string false "filter project name"
+// @Param filter_date_from query string false "filter create date from.(format:yyyy-mm-dd)"
+// @Param filter_date_to query string false "filter create date to.(format:yyyy-mm-dd)"
 // @Success 200 {object} v1.GetWorkflowRejectedPercentGroupByCreatorResV1
 // @router /v1/statistic/workflows/rejected_percent_group_by_creator [get]
 func GetWorkflowRejectedPercentGroupByCreatorV1(c echo.Context) error {
@@ -315,6 +339,9 @@ type GetInstancesTypePercentResV1 struct {
 // @Tags statistic
 // @Id getInstancesTypePercentV1
 // @Security ApiKeyAuth
+// @Param filter_project_name query string false "filter project name"
+// @Param filter_date_from query string false "filter create date from.(format:yyyy-mm-dd)"
+// @Param filter_date_to query string false "filter create date to.(format:yyyy-mm-dd)"
 // @Success 200 {object} v1.GetInstancesTypePercentResV1
 // @router /v1/statistic/instances/type_percent [get]
 func GetInstancesTypePercentV1(c echo.Context) error {
@@ -372,6 +399,9 @@ type GetSqlExecutionFailPercentResV1 struct {
 // @Id getSqlExecutionFailPercentV1
 // @Security ApiKeyAuth
 // @Param limit query uint true "the limit of result item number"
+// @Param filter_project_name query string false "filter project name"
+// @Param filter_date_from query string false "filter create date from.(format:yyyy-mm-dd)"
+// @Param filter_date_to query string false "filter create date to.(format:yyyy-mm-dd)"
 // @Success 200 {object} v1.GetSqlExecutionFailPercentResV1
 // @router /v1/statistic/instances/sql_execution_fail_percent [get]
 func GetSqlExecutionFailPercentV1(c echo.Context) error {
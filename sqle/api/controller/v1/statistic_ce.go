@@ -5,58 +5,399 @@ package v1
 
 import (
 	e "errors"
+	"fmt"
+	"math"
+	"net/http"
+	"sort"
+	"time"
 
 	"github.com/actiontech/sqle/sqle/api/controller"
 	"github.com/actiontech/sqle/sqle/errors"
+	"github.com/actiontech/sqle/sqle/model"
+
 	"github.com/labstack/echo/v4"
 )
 
-var errCommunityEditionDoesNotSupportStatistic = errors.New(errors.EnterpriseEditionFeatures, e.New("community edition does not support statistic"))
+var errCommunityEditionDoesNotSupportLicense = errors.New(errors.EnterpriseEditionFeatures, e.New("community edition does not support license"))
+
+const statisticDateLayout = "2006-01-02"
+
+// getStatisticFilter parses the optional filters shared by the global statistics,
+// filter_date_to is inclusive, so it is extended to the end of the day.
+func getStatisticFilter(c echo.Context) (*model.StatisticFilter, error) {
+	filter := &model.StatisticFilter{ProjectName: c.QueryParam("filter_project_name")}
+	if from := c.QueryParam("filter_date_from"); from != "" {
+		t, err := time.ParseInLocation(statisticDateLayout, from, time.Local)
+		if err != nil {
+			return nil, errors.New(errors.DataInvalid, fmt.Errorf("invalid filter_date_from: %v", from))
+		}
+		filter.StartTime = &t
+	}
+	if to := c.QueryParam("filter_date_to"); to != "" {
+		t, err := time.ParseInLocation(statisticDateLayout, to, time.Local)
+		if err != nil {
+			return nil, errors.New(errors.DataInvalid, fmt.Errorf("invalid filter_date_to: %v", to))
+		}
+		t = t.Add(24*time.Hour - time.Second)
+		filter.EndTime = &t
+	}
+	if filter.StartTime != nil && filter.EndTime != nil && filter.StartTime.After(*filter.EndTime) {
+		return nil, errors.New(errors.DataInvalid, fmt.Errorf("filter_date_from should not be later than filter_date_to"))
+	}
+	return filter, nil
+}
+
+// percent returns part/total in percentage, rounded to two decimal places.
+func percent(part, total float64) float64 {
+	if total == 0 {
+		return 0
+	}
+	return math.Round(part/total*10000) / 100
+}
 
 func getInstancesTypePercentV1(c echo.Context) error {
-	return controller.JSONBaseErrorReq(c, errCommunityEditionDoesNotSupportStatistic)
+	filter, err := getStatisticFilter(c)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	typeCounts, err := model.GetStorage().GetAllInstanceCount(filter)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+
+	var total int64
+	for _, typeCount := range typeCounts {
+		total += typeCount.Count
+	}
+	typePercents := make([]InstanceTypePercent, 0, len(typeCounts))
+	for _, typeCount := range typeCounts {
+		typePercents = append(typePercents, InstanceTypePercent{
+			Type:    typeCount.DBType,
+			Count:   uint(typeCount.Count),
+			Percent: percent(float64(typeCount.Count), float64(total)),
+		})
+	}
+
+	return c.JSON(http.StatusOK, &GetInstancesTypePercentResV1{
+		BaseRes: controller.NewBaseReq(nil),
+		Data: &InstancesTypePercentV1{
+			InstanceTypePercents: typePercents,
+			InstanceTotalNum:     uint(total),
+		},
+	})
 }
 
 func getLicenseUsageV1(c echo.Context) error {
-	return controller.JSONBaseErrorReq(c, errCommunityEditionDoesNotSupportStatistic)
+	return controller.JSONBaseErrorReq(c, errCommunityEditionDoesNotSupportLicense)
 }
 
 func getWorkflowRejectedPercentGroupByCreatorV1(c echo.Context) error {
-	return controller.JSONBaseErrorReq(c, errCommunityEditionDoesNotSupportStatistic)
+	req := new(GetWorkflowRejectedPercentGroupByCreatorReqV1)
+	if err := controller.BindAndValidateReq(c, req); err != nil {
+		return err
+	}
+	filter, err := getStatisticFilter(c)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	counts, err := model.GetStorage().GetWorkflowRejectedCountGroupByCreator(filter, req.Limit)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+
+	data := make([]*WorkflowRejectedPercentGroupByCreator, 0, len(counts))
+	for _, count := range counts {
+		data = append(data, &WorkflowRejectedPercentGroupByCreator{
+			Creator:          count.Creator,
+			WorkflowTotalNum: count.TotalCount,
+			RejectedPercent:  percent(float64(count.RejectedCount), float64(count.TotalCount)),
+		})
+	}
+	return c.JSON(http.StatusOK, &GetWorkflowRejectedPercentGroupByCreatorResV1{
+		BaseRes: controller.NewBaseReq(nil),
+		Data:    data,
+	})
 }
 
 func getWorkflowCounts(c echo.Context) error {
-	return controller.JSONBaseErrorReq(c, errCommunityEditionDoesNotSupportStatistic)
+	filter, err := getStatisticFilter(c)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	s := model.GetStorage()
+	total, err := s.GetAllWorkflowCount(filter)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+
+	now := time.Now()
+	todayStart := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	todayCount, err := s.GetAllWorkflowCount(&model.StatisticFilter{
+		ProjectName: filter.ProjectName,
+		StartTime:   &todayStart,
+		EndTime:     &now,
+	})
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+
+	return c.JSON(http.StatusOK, &GetWorkflowCountsResV1{
+		BaseRes: controller.NewBaseReq(nil),
+		Data: &WorkflowCountsV1{
+			Total:      uint(total),
+			TodayCount: uint(todayCount),
+		},
+	})
+}
+
+// averageMinutes returns the average minutes from start to end of each workflow, the workflows
+// which have not reached the end are ignored.
+func averageMinutes(startOf, endOf map[uint]time.Time) uint {
+	var totalMinutes float64
+	var count int
+	for workflowId, end := range endOf {
+		start, exist := startOf[workflowId]
+		if !exist {
+			continue
+		}
+		totalMinutes += end.Sub(start).Minutes()
+		count++
+	}
+	if count == 0 {
+		return 0
+	}
+	return uint(math.Round(totalMinutes / float64(count)))
+}
+
+// getWorkflowStepsFinishedTime returns the created time and the time when all the steps of the type are
+// finished of the workflows matching filter.
+func getWorkflowStepsFinishedTime(filter *model.StatisticFilter, stepType string) (createdAt, finishedAt map[uint]time.Time, err error) {
+	times, err := model.GetStorage().GetWorkflowStepsFinishedTime(filter, stepType)
+	if err != nil {
+		return nil, nil, err
+	}
+	createdAt = make(map[uint]time.Time, len(times))
+	finishedAt = make(map[uint]time.Time, len(times))
+	for _, t := range times {
+		createdAt[t.WorkflowId] = t.CreatedAt
+		finishedAt[t.WorkflowId] = t.FinishedAt
+	}
+	return createdAt, finishedAt, nil
 }
 
 func getWorkflowDurationOfWaitingForAuditV1(c echo.Context) error {
-	return controller.JSONBaseErrorReq(c, errCommunityEditionDoesNotSupportStatistic)
+	filter, err := getStatisticFilter(c)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	createdAt, auditedAt, err := getWorkflowStepsFinishedTime(filter, model.WorkflowStepTypeSQLReview)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	return c.JSON(http.StatusOK, &GetWorkflowDurationOfWaitingForAuditResV1{
+		BaseRes: controller.NewBaseReq(nil),
+		Data:    &WorkflowStageDuration{Minutes: averageMinutes(createdAt, auditedAt)},
+	})
 }
 
 func getWorkflowDurationOfWaitingForExecutionV1(c echo.Context) error {
-	return controller.JSONBaseErrorReq(c, errCommunityEditionDoesNotSupportStatistic)
+	filter, err := getStatisticFilter(c)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	_, auditedAt, err := getWorkflowStepsFinishedTime(filter, model.WorkflowStepTypeSQLReview)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	_, executedAt, err := getWorkflowStepsFinishedTime(filter, model.WorkflowStepTypeSQLExecute)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	return c.JSON(http.StatusOK, &GetWorkflowDurationOfWaitingForExecutionResV1{
+		BaseRes: controller.NewBaseReq(nil),
+		Data:    &WorkflowStageDuration{Minutes: averageMinutes(auditedAt, executedAt)},
+	})
 }
 
 func getWorkflowAuditPassPercentV1(c echo.Context) error {
-	return controller.JSONBaseErrorReq(c, errCommunityEditionDoesNotSupportStatistic)
+	filter, err := getStatisticFilter(c)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	s := model.GetStorage()
+	total, err := s.GetAllWorkflowCount(filter)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	approved, err := s.GetApprovedWorkflowCount(filter)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	return c.JSON(http.StatusOK, &GetWorkflowAuditPassPercentResV1{
+		BaseRes: controller.NewBaseReq(nil),
+		Data: &WorkflowAuditPassPercentV1{
+			AuditPassPercent: percent(float64(approved), float64(total)),
+		},
+	})
 }
 
 func getWorkflowCreatedCountsEachDayV1(c echo.Context) error {
-	return controller.JSONBaseErrorReq(c, errCommunityEditionDoesNotSupportStatistic)
+	req := new(GetWorkflowCreatedCountsEachDayReqV1)
+	if err := controller.BindAndValidateReq(c, req); err != nil {
+		return err
+	}
+	filter, err := getStatisticFilter(c)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	dailyCounts, err := model.GetStorage().GetWorkflowDailyCountBetweenStartTimeAndEndTime(filter)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	countOfDate := make(map[string]uint, len(dailyCounts))
+	for _, dailyCount := range dailyCounts {
+		countOfDate[dailyCount.Date.Format(statisticDateLayout)] = uint(dailyCount.Count)
+	}
+
+	// every day in the range has a sample, even there is no workflow created.
+	samples := []WorkflowCreatedCountsEachDayItem{}
+	for date := *filter.StartTime; date.Before(*filter.EndTime); date = date.AddDate(0, 0, 1) {
+		day := date.Format(statisticDateLayout)
+		samples = append(samples, WorkflowCreatedCountsEachDayItem{Date: day, Value: countOfDate[day]})
+	}
+	return c.JSON(http.StatusOK, &GetWorkflowCreatedCountsEachDayResV1{
+		BaseRes: controller.NewBaseReq(nil),
+		Data:    &WorkflowCreatedCountsEachDayV1{Samples: samples},
+	})
 }
 
 func getWorkflowStatusCountV1(c echo.Context) error {
-	return controller.JSONBaseErrorReq(c, errCommunityEditionDoesNotSupportStatistic)
+	filter, err := getStatisticFilter(c)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	statusCounts, err := model.GetStorage().GetWorkflowCountGroupByStatus(filter)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	countOfStatus := make(map[string]int, len(statusCounts))
+	for _, statusCount := range statusCounts {
+		countOfStatus[statusCount.Status] = statusCount.Count
+	}
+
+	return c.JSON(http.StatusOK, &GetWorkflowStatusCountResV1{
+		BaseRes: controller.NewBaseReq(nil),
+		Data: &WorkflowStatusCountV1{
+			ExecutionSuccessCount:    countOfStatus[model.WorkflowStatusFinish],
+			ExecutingCount:           countOfStatus[model.WorkflowStatusExecuting],
+			ExecutingFailedCount:     countOfStatus[model.WorkflowStatusExecFailed],
+			WaitingForExecutionCount: countOfStatus[model.WorkflowStatusWaitForExecution],
+			RejectedCount:            countOfStatus[model.WorkflowStatusReject],
+			WaitingForAuditCount:     countOfStatus[model.WorkflowStatusWaitForAudit],
+			ClosedCount:              countOfStatus[model.WorkflowStatusCancel],
+		},
+	})
 }
 
 func getWorkflowPercentCountedByInstanceTypeV1(c echo.Context) error {
-	return controller.JSONBaseErrorReq(c, errCommunityEditionDoesNotSupportStatistic)
+	filter, err := getStatisticFilter(c)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	s := model.GetStorage()
+	typeCounts, err := s.GetWorkflowCountGroupByInstanceType(filter)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	total, err := s.GetAllWorkflowCount(filter)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+
+	workflowPercents := make([]WorkflowPercentCountedByInstanceType, 0, len(typeCounts))
+	for _, typeCount := range typeCounts {
+		workflowPercents = append(workflowPercents, WorkflowPercentCountedByInstanceType{
+			InstanceType: typeCount.DBType,
+			Count:        uint(typeCount.Count),
+			Percent:      percent(float64(typeCount.Count), float64(total)),
+		})
+	}
+	return c.JSON(http.StatusOK, &GetWorkflowPercentCountedByInstanceTypeResV1{
+		BaseRes: controller.NewBaseReq(nil),
+		Data: &WorkflowPercentCountedByInstanceTypeV1{
+			WorkflowPercents: workflowPercents,
+			WorkflowTotalNum: uint(total),
+		},
+	})
 }
 
 func getSqlAverageExecutionTimeV1(c echo.Context) error {
-	return controller.JSONBaseErrorReq(c, errCommunityEditionDoesNotSupportStatistic)
+	req := new(GetSqlAverageExecutionTimeReqV1)
+	if err := controller.BindAndValidateReq(c, req); err != nil {
+		return err
+	}
+	filter, err := getStatisticFilter(c)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	statistics, err := model.GetStorage().GetSqlAvgExecutionTimeStatistic(filter, req.Limit)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+
+	data := make([]SqlAverageExecutionTime, 0, len(statistics))
+	for _, statistic := range statistics {
+		data = append(data, SqlAverageExecutionTime{
+			InstanceName:            statistic.InstanceName,
+			AverageExecutionSeconds: statistic.AvgExecutionTime,
+			MaxExecutionSeconds:     statistic.MaxExecutionTime,
+			MinExecutionSeconds:     statistic.MinExecutionTime,
+		})
+	}
+	return c.JSON(http.StatusOK, &GetSqlAverageExecutionTimeResV1{
+		BaseRes: controller.NewBaseReq(nil),
+		Data:    data,
+	})
 }
 
 func getSqlExecutionFailPercentV1(c echo.Context) error {
-	return controller.JSONBaseErrorReq(c, errCommunityEditionDoesNotSupportStatistic)
+	req := new(GetSqlExecutionFailPercentReqV1)
+	if err := controller.BindAndValidateReq(c, req); err != nil {
+		return err
+	}
+	filter, err := getStatisticFilter(c)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	s := model.GetStorage()
+	totalCounts, err := s.GetSqlExecutionTotalCount(filter)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	failCounts, err := s.GetSqlExecutionFailCount(filter)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	failCountOfInstance := make(map[string]uint, len(failCounts))
+	for _, count := range failCounts {
+		failCountOfInstance[count.InstanceName] = count.Count
+	}
+
+	data := make([]SqlExecutionFailPercent, 0, len(totalCounts))
+	for _, count := range totalCounts {
+		data = append(data, SqlExecutionFailPercent{
+			InstanceName: count.InstanceName,
+			Percent:      percent(float64(failCountOfInstance[count.InstanceName]), float64(count.Count)),
+		})
+	}
+	sort.SliceStable(data, func(i, j int) bool {
+		return data[i].Percent > data[j].Percent
+	})
+	if uint(len(data)) > req.Limit {
+		data = data[:req.Limit]
+	}
+	return c.JSON(http.StatusOK, &GetSqlExecutionFailPercentResV1{
+		BaseRes: controller.NewBaseReq(nil),
+		Data:    data,
+	})
 }
@@ -8,12 +8,15 @@ import (
 )
 
 type CreateSyncInstanceTaskReqV1 struct {
-	Source               string `json:"source" form:"source" validate:"required" example:"actiontech-dmp"`
-	Version              string `json:"version" form:"version" validate:"required" example:"5.23.01.0"`
-	URL                  string `json:"url" form:"url" validate:"required" example:"http://10.186.62.56:10000"`
-	DbType               string `json:"db_type" form:"db_type" validate:"required" example:"mysql"`
-	GlobalRuleTemplate   string `json:"global_rule_template" form:"global_rule_template" validate:"required" example:"default_mysql"`
-	SyncInstanceInterval string `json:"sync_instance_interval" form:"sync_instance_interval" validate:"required" example:"0 0 * * *"`
+	ProjectName          string `json:"project_name" form:"project_name" valid:"required" example:"default"`
+	Source               string `json:"source" form:"source" valid:"required" enums:"http,file" example:"http"`
+	Version              string `json:"version" form:"version" example:"5.23.01.0"`
+	URL                  string `json:"url" form:"url" valid:"required" example:"http://10.186.62.56:10000"`
+	DbType               string `json:"db_type" form:"db_type" valid:"required" example:"mysql"`
+	GlobalRuleTemplate   string `json:"global_rule_template" form:"global_rule_template" valid:"required" example:"default_mysql"`
+	SyncInstanceInterval string `json:"sync_instance_interval" form:"sync_instance_interval" valid:"required,cron" example:"0 0 * * *"`
+	// 源端未返回任何实例时是否允许删除该任务同步的全部实例
+	AllowEmptySource bool `json:"allow_empty_source" form:"allow_empty_source" example:"false"`
 }
 
 // CreateSyncInstanceTask create sync instance task
@@ -34,12 +37,13 @@ type UpdateSyncInstanceTaskReqV1 struct {
 	Version              *string `json:"version" form:"version" example:"5.23.01.0"`
 	URL                  *string `json:"url" form:"url" example:"http://10.186.62.56:10000"`
 	GlobalRuleTemplate   *string `json:"global_rule_template" form:"global_rule_template" example:"default_mysql"`
-	SyncInstanceInterval *string `json:"sync_instance_interval" form:"sync_instance_interval" example:"0 0 * * *"`
+	SyncInstanceInterval *string `json:"sync_instance_interval" form:"sync_instance_interval" valid:"omitempty,cron" example:"0 0 * * *"`
+	AllowEmptySource     *bool   `json:"allow_empty_source" form:"allow_empty_source" example:"false"`
 }
 
 // UpdateSyncInstanceTask update sync instance task
 // @Summary 更新同步实例任务
-// @Description update sync instance task
+// @Description update sync instance task, the password of url is kept if it is the redacted "xxxxx" returned by the task detail and the host is not changed
 // @Id updateSyncInstanceTaskV1
 // @Tags sync_instance
 // @Security ApiKeyAuth
@@ -94,6 +98,7 @@ type GetSyncInstanceTaskListResV1 struct {
 
 type InstanceTaskResV1 struct {
 	ID                  int        `json:"id" example:"1"`
+	ProjectName         string     `json:"project_name" example:"default"`
 	Source              string     `json:"source" example:"actiontech-dmp"`
 	Version             string     `json:"version" example:"1.23.1"`
 	URL                 string     `json:"url" example:"http://10.186.62.56:10000"`
@@ -138,12 +143,14 @@ func GetSyncTaskSourceTips(c echo.Context) error {
 
 type InstanceTaskDetailResV1 struct {
 	ID                   int    `json:"id" example:"1"`
+	ProjectName          string `json:"project_name" example:"default"`
 	Source               string `json:"source" example:"actiontech-dmp"`
 	Version              string `json:"version" example:"1.23.1"`
 	URL                  string `json:"url" example:"http://10.186.62.56:10000"`
 	DbType               string `json:"db_type" example:"mysql"`
 	RuleTemplate         string `json:"rule_template" example:"default_mysql"`
 	SyncInstanceInterval string `json:"sync_instance_interval" example:"0 0 * * *"`
+	AllowEmptySource     bool   `json:"allow_empty_source" example:"false"`
 }
 
 type GetSyncInstanceTaskResV1 struct {
@@ -163,3 +170,46 @@ type GetSyncInstanceTaskResV1 struct {
 func GetSyncInstanceTask(c echo.Context) error {
 	return getSyncInstanceTask(c)
 }
+
+type GetSyncInstanceTaskRecordsReqV1 struct {
+	PageIndex uint32 `json:"page_index" query:"page_index" valid:"required"`
+	PageSize  uint32 `json:"page_size" query:"page_size" valid:"required"`
+}
+
+type SyncInstanceConflictResV1 struct {
+	InstanceName string `json:"instance_name" example:"mysql_1"`
+	Reason       string `json:"reason"`
+}
+
+type SyncInstanceTaskRecordResV1 struct {
+	ID           uint                         `json:"id" example:"1"`
+	StartTime    *time.Time                   `json:"start_time"`
+	EndTime      *time.Time                   `json:"end_time"`
+	Status       string                       `json:"status" enums:"succeeded,failed" example:"succeeded"`
+	CreatedNum   uint                         `json:"created_num" example:"1"`
+	UpdatedNum   uint                         `json:"updated_num" example:"1"`
+	DeletedNum   uint                         `json:"deleted_num" example:"1"`
+	Conflicts    []*SyncInstanceConflictResV1 `json:"conflicts"`
+	ErrorMessage string                       `json:"error_message"`
+}
+
+type GetSyncInstanceTaskRecordsResV1 struct {
+	controller.BaseRes
+	Data      []*SyncInstanceTaskRecordResV1 `json:"data"`
+	TotalNums uint64                         `json:"total_nums"`
+}
+
+// GetSyncInstanceTaskRecords get records of sync task
+// @Summary 获取同步任务执行记录
+// @Description get records of sync task
+// @Id getSyncInstanceTaskRecordsV1
+// @Tags sync_instance
+// @Security ApiKeyAuth
+// @param task_id path string true "sync task id"
+// @Param page_index query uint32 true "page index"
+// @Param page_size query uint32 true "size of per page"
+// @Success 200 {object} v1.GetSyncInstanceTaskRecordsResV1
+// @router /v1/sync_instances/{task_id}/records [get]
+func GetSyncInstanceTaskRecords(c echo.Context) error {
+	return getSyncInstanceTaskRecords(c)
+}
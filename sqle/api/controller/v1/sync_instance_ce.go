@@ -4,38 +4,298 @@
 package v1
 
 import (
-	e "errors"
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+
 	"github.com/actiontech/sqle/sqle/api/controller"
+	"github.com/actiontech/sqle/sqle/driver"
 	"github.com/actiontech/sqle/sqle/errors"
+	"github.com/actiontech/sqle/sqle/model"
+	"github.com/actiontech/sqle/sqle/server/syncinstance"
+
 	"github.com/labstack/echo/v4"
+	cron "github.com/robfig/cron/v3"
+	dry "github.com/ungerik/go-dry"
 )
 
-var errCommunityEditionDoesNotSupportSyncInstance = errors.New(errors.EnterpriseEditionFeatures, e.New("community edition does not support sync instance"))
+var errSyncInstanceTaskNotExist = errors.New(errors.DataNotExist, fmt.Errorf("sync instance task is not exist"))
+
+func getSyncInstanceTaskByParam(c echo.Context) (*model.SyncInstanceTask, error) {
+	taskId, err := strconv.Atoi(c.Param("task_id"))
+	if err != nil {
+		return nil, errors.New(errors.DataInvalid, fmt.Errorf("parse sync instance task id failed: %v", err))
+	}
+	task, exist, err := model.GetStorage().GetSyncInstanceTaskById(uint(taskId))
+	if err != nil {
+		return nil, err
+	}
+	if !exist {
+		return nil, errSyncInstanceTaskNotExist
+	}
+	return task, nil
+}
+
+// getSyncRuleTemplate returns the rule template which is bound to the synchronized instances, the
+// rule template of the project is also allowed.
+func getSyncRuleTemplate(name string, projectId uint, dbType string) (*model.RuleTemplate, error) {
+	template, exist, err := model.GetStorage().GetGlobalAndProjectRuleTemplateByNameAndProjectId(name, projectId)
+	if err != nil {
+		return nil, err
+	}
+	if !exist {
+		return nil, ErrRuleTemplateNotExist
+	}
+	if template.DBType != dbType {
+		return nil, errors.New(errors.DataInvalid, fmt.Errorf("db type of rule template %s is %s, but db type of sync task is %s",
+			template.Name, template.DBType, dbType))
+	}
+	return template, nil
+}
+
+// checkSyncInstanceInterval rejects the interval which can't be scheduled, the task would never run
+// otherwise.
+func checkSyncInstanceInterval(interval string) error {
+	if _, err := cron.ParseStandard(interval); err != nil {
+		return errors.New(errors.DataInvalid, fmt.Errorf("sync instance interval %s is invalid: %v", interval, err))
+	}
+	return nil
+}
 
 func createSyncInstanceTask(c echo.Context) error {
-	return controller.JSONBaseErrorReq(c, errCommunityEditionDoesNotSupportSyncInstance)
+	req := new(CreateSyncInstanceTaskReqV1)
+	if err := controller.BindAndValidateReq(c, req); err != nil {
+		return err
+	}
+	if err := checkSyncInstanceInterval(req.SyncInstanceInterval); err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	if _, ok := syncinstance.GetSource(req.Source); !ok {
+		return controller.JSONBaseErrorReq(c, errors.New(errors.DataInvalid, fmt.Errorf("source %s is not supported", req.Source)))
+	}
+	if !dry.StringInSlice(req.DbType, driver.GetPluginManager().AllDrivers()) {
+		return controller.JSONBaseErrorReq(c, errors.New(errors.DriverNotExist, fmt.Errorf("db type %s is not supported", req.DbType)))
+	}
+
+	s := model.GetStorage()
+	project, exist, err := s.GetProjectByName(req.ProjectName)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	if !exist {
+		return controller.JSONBaseErrorReq(c, ErrProjectNotExist(req.ProjectName))
+	}
+	if project.IsArchived() {
+		return controller.JSONBaseErrorReq(c, ErrProjectArchived)
+	}
+	template, err := getSyncRuleTemplate(req.GlobalRuleTemplate, project.ID, req.DbType)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+
+	task := &model.SyncInstanceTask{
+		ProjectId:            project.ID,
+		Source:               req.Source,
+		Version:              req.Version,
+		URL:                  req.URL,
+		DbType:               req.DbType,
+		RuleTemplateID:       template.ID,
+		SyncInstanceInterval: req.SyncInstanceInterval,
+		LastSyncStatus:       model.SyncInstanceStatusInitialized,
+		AllowEmptySource:     req.AllowEmptySource,
+	}
+	if err := s.Save(task); err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	return c.JSON(http.StatusOK, controller.NewBaseReq(nil))
 }
 
 func updateSyncInstanceTask(c echo.Context) error {
-	return controller.JSONBaseErrorReq(c, errCommunityEditionDoesNotSupportSyncInstance)
+	req := new(UpdateSyncInstanceTaskReqV1)
+	if err := controller.BindAndValidateReq(c, req); err != nil {
+		return err
+	}
+	task, err := getSyncInstanceTaskByParam(c)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+
+	attrs := map[string]interface{}{}
+	if req.Version != nil {
+		attrs["version"] = *req.Version
+	}
+	if req.URL != nil {
+		addr, err := syncinstance.RestoreRedactedURL(*req.URL, task.URL)
+		if err != nil {
+			return controller.JSONBaseErrorReq(c, errors.New(errors.DataInvalid, err))
+		}
+		attrs["url"] = addr
+	}
+	if req.SyncInstanceInterval != nil {
+		if err := checkSyncInstanceInterval(*req.SyncInstanceInterval); err != nil {
+			return controller.JSONBaseErrorReq(c, err)
+		}
+		attrs["sync_instance_interval"] = *req.SyncInstanceInterval
+	}
+	if req.AllowEmptySource != nil {
+		attrs["allow_empty_source"] = *req.AllowEmptySource
+	}
+	if req.GlobalRuleTemplate != nil {
+		template, err := getSyncRuleTemplate(*req.GlobalRuleTemplate, task.ProjectId, task.DbType)
+		if err != nil {
+			return controller.JSONBaseErrorReq(c, err)
+		}
+		attrs["rule_template_id"] = template.ID
+	}
+	if len(attrs) == 0 {
+		return c.JSON(http.StatusOK, controller.NewBaseReq(nil))
+	}
+	if err := model.GetStorage().UpdateSyncInstanceTaskById(task.ID, attrs); err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	return c.JSON(http.StatusOK, controller.NewBaseReq(nil))
 }
 
 func deleteSyncInstanceTask(c echo.Context) error {
-	return controller.JSONBaseErrorReq(c, errCommunityEditionDoesNotSupportSyncInstance)
+	task, err := getSyncInstanceTaskByParam(c)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	if err := model.GetStorage().DeleteSyncInstanceTask(task); err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	return c.JSON(http.StatusOK, controller.NewBaseReq(nil))
 }
 
 func triggerSyncInstance(c echo.Context) error {
-	return controller.JSONBaseErrorReq(c, errCommunityEditionDoesNotSupportSyncInstance)
+	task, err := getSyncInstanceTaskByParam(c)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	data := SyncInstanceResV1{IsSyncInstanceSuccess: true}
+	if _, err := syncinstance.Run(context.Background(), task.ID); err != nil {
+		data = SyncInstanceResV1{
+			IsSyncInstanceSuccess: false,
+			SyncErrorMessage:      err.Error(),
+		}
+	}
+	return c.JSON(http.StatusOK, &TriggerSyncInstanceResV1{
+		BaseRes: controller.NewBaseReq(nil),
+		Data:    data,
+	})
+}
+
+func getSyncInstanceTaskProjectName(task *model.SyncInstanceTask) string {
+	if task.Project == nil {
+		return ""
+	}
+	return task.Project.Name
 }
 
 func getSyncInstanceTaskList(c echo.Context) error {
-	return controller.JSONBaseErrorReq(c, errCommunityEditionDoesNotSupportSyncInstance)
+	tasks, err := model.GetStorage().GetAllSyncInstanceTasks()
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	data := make([]InstanceTaskResV1, 0, len(tasks))
+	for _, task := range tasks {
+		data = append(data, InstanceTaskResV1{
+			ID:                  int(task.ID),
+			ProjectName:         getSyncInstanceTaskProjectName(task),
+			Source:              task.Source,
+			Version:             task.Version,
+			URL:                 syncinstance.RedactURL(task.URL),
+			DbType:              task.DbType,
+			LastSyncStatus:      task.LastSyncStatus,
+			LastSyncSuccessTime: task.LastSyncSuccessTime,
+		})
+	}
+	return c.JSON(http.StatusOK, &GetSyncInstanceTaskListResV1{
+		BaseRes: controller.NewBaseReq(nil),
+		Data:    data,
+	})
 }
 
 func getSyncTaskSourceTips(c echo.Context) error {
-	return controller.JSONBaseErrorReq(c, errCommunityEditionDoesNotSupportSyncInstance)
+	dbTypes := driver.GetPluginManager().AllDrivers()
+	data := make([]SyncTaskTipsResV1, 0, len(syncinstance.SourceNames()))
+	for _, source := range syncinstance.SourceNames() {
+		data = append(data, SyncTaskTipsResV1{
+			Source:  source,
+			DbTypes: dbTypes,
+		})
+	}
+	return c.JSON(http.StatusOK, &GetSyncTaskSourceTipsResV1{
+		BaseRes: controller.NewBaseReq(nil),
+		Data:    data,
+	})
 }
 
 func getSyncInstanceTask(c echo.Context) error {
-	return controller.JSONBaseErrorReq(c, errCommunityEditionDoesNotSupportSyncInstance)
+	task, err := getSyncInstanceTaskByParam(c)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	data := InstanceTaskDetailResV1{
+		ID:                   int(task.ID),
+		ProjectName:          getSyncInstanceTaskProjectName(task),
+		Source:               task.Source,
+		Version:              task.Version,
+		URL:                  syncinstance.RedactURL(task.URL),
+		DbType:               task.DbType,
+		SyncInstanceInterval: task.SyncInstanceInterval,
+		AllowEmptySource:     task.AllowEmptySource,
+	}
+	if task.RuleTemplate != nil {
+		data.RuleTemplate = task.RuleTemplate.Name
+	}
+	return c.JSON(http.StatusOK, &GetSyncInstanceTaskResV1{
+		BaseRes: controller.NewBaseReq(nil),
+		Data:    data,
+	})
+}
+
+func getSyncInstanceTaskRecords(c echo.Context) error {
+	req := new(GetSyncInstanceTaskRecordsReqV1)
+	if err := controller.BindAndValidateReq(c, req); err != nil {
+		return err
+	}
+	task, err := getSyncInstanceTaskByParam(c)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	limit, offset := controller.GetLimitAndOffset(req.PageIndex, req.PageSize)
+	records, count, err := model.GetStorage().GetSyncInstanceTaskRecords(task.ID, limit, offset)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+
+	data := make([]*SyncInstanceTaskRecordResV1, 0, len(records))
+	for _, record := range records {
+		startTime, endTime := record.StartTime, record.EndTime
+		conflicts := make([]*SyncInstanceConflictResV1, 0, len(record.Conflicts))
+		for _, conflict := range record.Conflicts {
+			conflicts = append(conflicts, &SyncInstanceConflictResV1{
+				InstanceName: conflict.InstanceName,
+				Reason:       conflict.Reason,
+			})
+		}
+		data = append(data, &SyncInstanceTaskRecordResV1{
+			ID:           record.ID,
+			StartTime:    &startTime,
+			EndTime:      &endTime,
+			Status:       record.Status,
+			CreatedNum:   record.CreatedNum,
+			UpdatedNum:   record.UpdatedNum,
+			DeletedNum:   record.DeletedNum,
+			Conflicts:    conflicts,
+			ErrorMessage: record.ErrorMessage,
+		})
+	}
+	return c.JSON(http.StatusOK, &GetSyncInstanceTaskRecordsResV1{
+		BaseRes:   controller.NewBaseReq(nil),
+		Data:      data,
+		TotalNums: count,
+	})
 }
@@ -524,6 +524,65 @@ func UpdateAuditTaskSQLs(c echo.Context) error {
 	return controller.JSONBaseErrorReq(c, err)
 }
 
+type IndexAdviceResV1 struct {
+	TableName        string   `json:"table_name"`
+	Columns          []string `json:"columns"`
+	CreateIndexSQL   string   `json:"create_index_sql"`
+	CoveringIndexSQL string   `json:"covering_index_sql"`
+	EstimatedRows    int64    `json:"estimated_rows"`
+	SQLNumbers       []uint   `json:"sql_numbers"`
+}
+
+type GetIndexAdvicesResV1 struct {
+	controller.BaseRes
+	Data []*IndexAdviceResV1 `json:"data"`
+}
+
+func convertIndexAdvicesToRes(advices []*server.IndexAdvice) []*IndexAdviceResV1 {
+	res := make([]*IndexAdviceResV1, 0, len(advices))
+	for _, advice := range advices {
+		res = append(res, &IndexAdviceResV1{
+			TableName:        advice.TableName,
+			Columns:          advice.Columns,
+			CreateIndexSQL:   advice.CreateIndexSQL,
+			CoveringIndexSQL: advice.CoveringIndexSQL,
+			EstimatedRows:    advice.EstimatedRows,
+			SQLNumbers:       advice.SQLNumbers,
+		})
+	}
+	return res
+}
+
+// @Summary 获取Sql扫描任务的索引建议
+// @Description get index advices of the SELECT statements in task, the advices are deduplicated and ordered by estimated benefit.
+// @Description The advices are generated by the index optimization rule (optimize_index_enabled) when auditing, so the result is empty if the rule is not enabled in the rule template of task.
+// @Tags task
+// @Id getAuditTaskIndexAdvicesV1
+// @Security ApiKeyAuth
+// @Param task_id path string true "task id"
+// @Success 200 {object} v1.GetIndexAdvicesResV1
+// @router /v1/tasks/audits/{task_id}/index_advices [get]
+func GetTaskIndexAdvices(c echo.Context) error {
+	s := model.GetStorage()
+	taskId := c.Param("task_id")
+	task, exist, err := s.GetTaskDetailById(taskId)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	if !exist {
+		return controller.JSONBaseErrorReq(c, errors.NewTaskNoExistOrNoAccessErr())
+	}
+	err = CheckCurrentUserCanViewTask(c, task)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+
+	return c.JSON(http.StatusOK, &GetIndexAdvicesResV1{
+		BaseRes: controller.NewBaseReq(nil),
+		Data:    convertIndexAdvicesToRes(server.GetTaskIndexAdvices(task)),
+	})
+}
+
 func CheckCurrentUserCanViewTask(c echo.Context, task *model.Task) (err error) {
 	return checkCurrentUserCanAccessTask(c, task, []uint{model.OP_WORKFLOW_VIEW_OTHERS})
 }
@@ -4,12 +4,28 @@
 package v1
 
 import (
+	"net/http"
+
 	"github.com/actiontech/sqle/sqle/api/controller"
-	"github.com/actiontech/sqle/sqle/errors"
 
 	"github.com/labstack/echo/v4"
 )
 
 func getTaskAnalysisData(c echo.Context) error {
-	return controller.JSONBaseErrorReq(c, errors.NewNotSupportGetTaskAnalysisDataErr())
+	task, executeSQL, err := GetTaskSQLForAnalysis(c, c.Param("task_id"), c.Param("number"))
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	res, err := GetSQLAnalysisResult(task.Instance, task.Schema, executeSQL.Content)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+
+	return c.JSON(http.StatusOK, &GetTaskAnalysisDataResV1{
+		BaseRes: controller.NewBaseReq(nil),
+		Data: GetTaskAnalysisDataResItemV1{
+			SQLExplain: ConvertExplainResultToRes(executeSQL.Content, res.ExplainResult, res.ExplainErr),
+			TableMetas: ConvertTableMetasToRes(res.TableMetaResult),
+		},
+	})
 }
@@ -9,9 +9,11 @@ import (
 	"time"
 
 	"github.com/actiontech/sqle/sqle/api/controller"
+	"github.com/actiontech/sqle/sqle/driver/mysql/onlineddl"
 	driverV2 "github.com/actiontech/sqle/sqle/driver/v2"
 	"github.com/actiontech/sqle/sqle/errors"
 	"github.com/actiontech/sqle/sqle/model"
+	"github.com/actiontech/sqle/sqle/server"
 	"github.com/actiontech/sqle/sqle/utils"
 	"github.com/labstack/echo/v4"
 )
@@ -45,12 +47,57 @@ type WorkflowTemplateDetailResV1 struct {
 }
 
 type WorkFlowStepTemplateResV1 struct {
-	Number               int      `json:"number"`
-	Typ                  string   `json:"type"`
-	Desc                 string   `json:"desc,omitempty"`
-	ApprovedByAuthorized bool     `json:"approved_by_authorized"`
-	ExecuteByAuthorized  bool     `json:"execute_by_authorized"`
-	Users                []string `json:"assignee_user_name_list"`
+	Number               int                      `json:"number"`
+	Typ                  string                   `json:"type"`
+	Desc                 string                   `json:"desc,omitempty"`
+	ApprovedByAuthorized bool                     `json:"approved_by_authorized"`
+	ExecuteByAuthorized  bool                     `json:"execute_by_authorized"`
+	Users                []string                 `json:"assignee_user_name_list"`
+	Condition            *WorkflowStepConditionV1 `json:"condition,omitempty"`
+	ApproveMode          string                   `json:"approve_mode" enums:"any_of,all_of,quorum"`
+	ApproveQuorum        uint                     `json:"approve_quorum,omitempty"`
+}
+
+// WorkflowStepConditionV1 decides whether the step is required by the workflow, the step
+// is skipped if the condition is not matched. All the non-empty fields must be matched.
+type WorkflowStepConditionV1 struct {
+	AuditLevel    string   `json:"audit_level,omitempty" enums:"normal,notice,warn,error"`
+	SQLTypes      []string `json:"sql_types,omitempty" enums:"ddl,dml"`
+	InstanceNames []string `json:"instance_name_list,omitempty"`
+}
+
+// convertWorkflowStepConditionToRes returns the names of instances in condition, the deleted
+// instances are left out since they are never matched.
+func convertWorkflowStepConditionToRes(condition model.WorkflowStepCondition, instanceNames map[uint]string) *WorkflowStepConditionV1 {
+	if condition.IsEmpty() {
+		return nil
+	}
+	res := &WorkflowStepConditionV1{
+		AuditLevel: condition.AuditLevel,
+		SQLTypes:   condition.SQLTypes,
+	}
+	for _, id := range condition.InstanceIds {
+		if name, ok := instanceNames[id]; ok {
+			res.InstanceNames = append(res.InstanceNames, name)
+		}
+	}
+	return res
+}
+
+// convertWorkflowStepConditionToModel keeps the instances of condition by id, instanceIds is
+// the id of instances in project by name.
+func convertWorkflowStepConditionToModel(condition *WorkflowStepConditionV1, instanceIds map[string]uint) model.WorkflowStepCondition {
+	if condition == nil {
+		return model.WorkflowStepCondition{}
+	}
+	c := model.WorkflowStepCondition{
+		AuditLevel: condition.AuditLevel,
+		SQLTypes:   condition.SQLTypes,
+	}
+	for _, name := range condition.InstanceNames {
+		c.InstanceIds = append(c.InstanceIds, instanceIds[name])
+	}
+	return c
 }
 
 // @Summary 获取审批流程模板详情
@@ -106,6 +153,20 @@ func getWorkflowTemplateDetailByTemplate(template *model.WorkflowTemplate) (*Wor
 		return nil, err
 	}
 	template.Steps = steps
+	conditionInstanceIds := []uint{}
+	for _, step := range steps {
+		conditionInstanceIds = append(conditionInstanceIds, step.Condition.InstanceIds...)
+	}
+	conditionInstanceNames := map[uint]string{}
+	if len(conditionInstanceIds) > 0 {
+		instances, err := s.GetInstancesByIds(conditionInstanceIds)
+		if err != nil {
+			return nil, err
+		}
+		for _, instance := range instances {
+			conditionInstanceNames[instance.ID] = instance.Name
+		}
+	}
 	res := &WorkflowTemplateDetailResV1{
 		Name:                          template.Name,
 		Desc:                          template.Desc,
@@ -119,6 +180,9 @@ func getWorkflowTemplateDetailByTemplate(template *model.WorkflowTemplate) (*Wor
 			ExecuteByAuthorized:  step.ExecuteByAuthorized.Bool,
 			Typ:                  step.Typ,
 			Desc:                 step.Desc,
+			Condition:            convertWorkflowStepConditionToRes(step.Condition, conditionInstanceNames),
+			ApproveMode:          step.GetApproveMode(),
+			ApproveQuorum:        step.ApproveQuorum,
 		}
 		users := []string{}
 		if step.Users != nil {
@@ -140,37 +204,16 @@ func getWorkflowTemplateDetailByTemplate(template *model.WorkflowTemplate) (*Wor
 }
 
 type WorkFlowStepTemplateReqV1 struct {
-	Type                 string   `json:"type" form:"type" valid:"oneof=sql_review sql_execute" enums:"sql_review,sql_execute"`
-	Desc                 string   `json:"desc" form:"desc"`
-	ApprovedByAuthorized bool     `json:"approved_by_authorized"`
-	ExecuteByAuthorized  bool     `json:"execute_by_authorized"`
-	Users                []string `json:"assignee_user_name_list" form:"assignee_user_name_list"`
-}
-
-func validWorkflowTemplateReq(steps []*WorkFlowStepTemplateReqV1) error {
-	if len(steps) == 0 {
-		return fmt.Errorf("workflow steps cannot be empty")
-	}
-	if len(steps) > 5 {
-		return fmt.Errorf("workflow steps length must be less than 6")
-	}
-
-	for i, step := range steps {
-		isLastStep := i == len(steps)-1
-		if isLastStep && step.Type != model.WorkflowStepTypeSQLExecute {
-			return fmt.Errorf("the last workflow step type must be sql_execute")
-		}
-		if !isLastStep && step.Type == model.WorkflowStepTypeSQLExecute {
-			return fmt.Errorf("workflow step type sql_execute just be used in last step")
-		}
-		if len(step.Users) == 0 && !step.ApprovedByAuthorized && !step.ExecuteByAuthorized {
-			return fmt.Errorf("the assignee is empty for step %s", step.Desc)
-		}
-		if len(step.Users) > 3 {
-			return fmt.Errorf("the assignee for step cannot be more than 3")
-		}
-	}
-	return nil
+	Type                 string                   `json:"type" form:"type" valid:"oneof=sql_review sql_execute" enums:"sql_review,sql_execute"`
+	Desc                 string                   `json:"desc" form:"desc"`
+	ApprovedByAuthorized bool                     `json:"approved_by_authorized"`
+	ExecuteByAuthorized  bool                     `json:"execute_by_authorized"`
+	Users                []string                 `json:"assignee_user_name_list" form:"assignee_user_name_list"`
+	Condition            *WorkflowStepConditionV1 `json:"condition" form:"condition"`
+	// ApproveMode is how the assignees approve the sql_review step, any_of by default,
+	// ApproveQuorum is the count of approvals required by quorum mode, e.g. 2 of 4 DBAs.
+	ApproveMode   string `json:"approve_mode" form:"approve_mode" valid:"omitempty,oneof=any_of all_of quorum" enums:"any_of,all_of,quorum"`
+	ApproveQuorum uint   `json:"approve_quorum" form:"approve_quorum"`
 }
 
 type UpdateWorkflowTemplateReqV1 struct {
@@ -235,13 +278,23 @@ func UpdateWorkflowTemplate(c echo.Context) error {
 	}
 
 	if req.Steps != nil {
-		err = validWorkflowTemplateReq(req.Steps)
-		if err != nil {
-			return controller.JSONBaseErrorReq(c, errors.New(errors.DataInvalid, err))
-		}
 		userNames := []string{}
+		conditionInstanceNames := []string{}
 		for _, step := range req.Steps {
 			userNames = append(userNames, step.Users...)
+			if step.Condition != nil {
+				conditionInstanceNames = append(conditionInstanceNames, step.Condition.InstanceNames...)
+			}
+		}
+		conditionInstanceIds := map[string]uint{}
+		if len(conditionInstanceNames) > 0 {
+			conditionInstances, err := s.GetAndCheckInstanceExist(conditionInstanceNames, project.Name)
+			if err != nil {
+				return controller.JSONBaseErrorReq(c, err)
+			}
+			for _, instance := range conditionInstances {
+				conditionInstanceIds[instance.Name] = instance.ID
+			}
 		}
 
 		users, err := s.GetAndCheckUserExist(userNames)
@@ -265,8 +318,11 @@ func UpdateWorkflowTemplate(c echo.Context) error {
 					Bool:  step.ExecuteByAuthorized,
 					Valid: true,
 				},
-				Typ:  step.Type,
-				Desc: step.Desc,
+				Typ:           step.Type,
+				Desc:          step.Desc,
+				Condition:     convertWorkflowStepConditionToModel(step.Condition, conditionInstanceIds),
+				ApproveMode:   step.ApproveMode,
+				ApproveQuorum: step.ApproveQuorum,
 			}
 			stepUsers := make([]*model.User, 0, len(step.Users))
 			for _, userName := range step.Users {
@@ -275,6 +331,9 @@ func UpdateWorkflowTemplate(c echo.Context) error {
 			s.Users = stepUsers
 			steps = append(steps, s)
 		}
+		if err := model.ValidateWorkflowStepTemplates(steps); err != nil {
+			return controller.JSONBaseErrorReq(c, errors.New(errors.DataInvalid, err))
+		}
 		err = s.UpdateWorkflowTemplateSteps(workflowTemplate.ID, steps)
 		if err != nil {
 			return controller.JSONBaseErrorReq(c, err)
@@ -312,7 +371,7 @@ type WorkflowStepResV1 struct {
 	Users         []string   `json:"assignee_user_name_list,omitempty"`
 	OperationUser string     `json:"operation_user_name,omitempty"`
 	OperationTime *time.Time `json:"operation_time,omitempty"`
-	State         string     `json:"state,omitempty" enums:"initialized,approved,rejected"`
+	State         string     `json:"state,omitempty" enums:"initialized,approved,rejected,skipped"`
 	Reason        string     `json:"reason,omitempty"`
 }
 
@@ -467,7 +526,7 @@ func IsTaskCanExecute(s *model.Storage, taskId string) (bool, error) {
 		return false, fmt.Errorf("get work instance record by task id failed. taskId=%v err=%v", taskId, err)
 	}
 
-	if instanceRecord.ScheduledAt != nil || instanceRecord.IsSQLExecuted {
+	if instanceRecord.ScheduledAt != nil || instanceRecord.MaintenanceQueuedAt != nil || instanceRecord.IsSQLExecuted {
 		return false, nil
 	}
 
@@ -541,12 +600,14 @@ const (
 	taskStatusManuallyExecuted          = "manually_executed"
 	taskDisplayStatusExecuting          = "executing"
 	taskDisplayStatusScheduled          = "exec_scheduled"
+	taskDisplayStatusQueued             = "exec_queued"
+	taskDisplayStatusPaused             = "paused"
 	taskDisplayStatusTerminating        = "terminating"
 	taskDisplayStatusTerminateSucceeded = "terminate_succeeded"
 	taskDisplayStatusTerminateFailed    = "terminate_failed"
 )
 
-func GetTaskStatusRes(workflowStatus string, taskStatus string, scheduleAt *time.Time) (status string) {
+func GetTaskStatusRes(workflowStatus string, taskStatus string, scheduleAt, maintenanceQueuedAt *time.Time) (status string) {
 	if workflowStatus == model.WorkflowStatusWaitForAudit {
 		return taskDisplayStatusWaitForAudit
 	}
@@ -555,6 +616,10 @@ func GetTaskStatusRes(workflowStatus string, taskStatus string, scheduleAt *time
 		return taskDisplayStatusScheduled
 	}
 
+	if maintenanceQueuedAt != nil && taskStatus == model.TaskStatusAudited {
+		return taskDisplayStatusQueued
+	}
+
 	switch taskStatus {
 	case model.TaskStatusAudited:
 		return taskDisplayStatusWaitForExecution
@@ -566,6 +631,8 @@ func GetTaskStatusRes(workflowStatus string, taskStatus string, scheduleAt *time
 		return taskDisplayStatusExecuting
 	case model.TaskStatusManuallyExecuted:
 		return taskStatusManuallyExecuted
+	case model.TaskStatusPaused:
+		return taskDisplayStatusPaused
 	case model.TaskStatusTerminating:
 		return taskDisplayStatusTerminating
 	case model.TaskStatusTerminateSucc:
@@ -931,6 +998,7 @@ func GetWorkflowV1(c echo.Context) error {
 
 type ExportWorkflowReqV1 struct {
 	FilterSubject                     string `json:"filter_subject" query:"filter_subject"`
+	FilterWorkflowID                  string `json:"filter_workflow_id" query:"filter_workflow_id"`
 	FuzzySearchWorkflowDesc           string `json:"fuzzy_search_workflow_desc" query:"fuzzy_search_workflow_desc"`
 	FilterCreateTimeFrom              string `json:"filter_create_time_from" query:"filter_create_time_from"`
 	FilterCreateTimeTo                string `json:"filter_create_time_to" query:"filter_create_time_to"`
@@ -940,6 +1008,7 @@ type ExportWorkflowReqV1 struct {
 	FilterTaskInstanceName            string `json:"filter_task_instance_name" query:"filter_task_instance_name"`
 	FilterTaskExecuteStartTimeFrom    string `json:"filter_task_execute_start_time_from" query:"filter_task_execute_start_time_from"`
 	FilterTaskExecuteStartTimeTo      string `json:"filter_task_execute_start_time_to" query:"filter_task_execute_start_time_to"`
+	ExportFormat                      string `json:"export_format" query:"export_format" valid:"omitempty,oneof=csv xlsx"`
 }
 
 // ExportWorkflowV1
@@ -949,6 +1018,7 @@ type ExportWorkflowReqV1 struct {
 // @Tags workflow
 // @Security ApiKeyAuth
 // @Param filter_subject query string false "filter subject"
+// @Param filter_workflow_id query string false "filter by workflow_id"
 // @Param fuzzy_search_workflow_desc query string false "fuzzy search by workflow description"
 // @Param filter_create_time_from query string false "filter create time from"
 // @Param filter_create_time_to query string false "filter create time to"
@@ -958,6 +1028,7 @@ type ExportWorkflowReqV1 struct {
 // @Param filter_status query string false "filter workflow status" Enums(wait_for_audit,wait_for_execution,rejected,executing,canceled,exec_failed,finished)
 // @Param filter_current_step_assignee_user_name query string false "filter current step assignee user name"
 // @Param filter_task_instance_name query string false "filter instance name"
+// @Param export_format query string false "export format, default is csv" Enums(csv,xlsx)
 // @Param project_name path string true "project name"
 // @Success 200 {file} file "export workflow"
 // @Router /v1/projects/{project_name}/workflows/exports [get]
@@ -1069,6 +1140,223 @@ func TerminateSingleTaskByWorkflowV1(c echo.Context) error {
 	return c.JSON(http.StatusOK, controller.NewBaseReq(err))
 }
 
+var ErrCanNotRehearseTask = func(workflowStatus string) error {
+	return errors.NewDataInvalidErr(
+		"workflow status is %s, rehearsal can not be performed", workflowStatus)
+}
+
+// RehearseSingleTaskByWorkflowV1
+// @Summary 预演单个上线任务
+// @Description rehearse one task on workflow, the DMLs are executed in a transaction and rolled back, the affected rows, warnings and elapsed time are recorded on task SQLs
+// @Tags workflow
+// @Id rehearseSingleTaskByWorkflowV1
+// @Security ApiKeyAuth
+// @Param workflow_id path string true "workflow id"
+// @Param project_name path string true "project name"
+// @Param task_id path string true "task id"
+// @Success 200 {object} controller.BaseRes
+// @Router /v1/projects/{project_name}/workflows/{workflow_id}/tasks/{task_id}/rehearse [post]
+func RehearseSingleTaskByWorkflowV1(c echo.Context) error {
+	projectName := c.Param("project_name")
+	workflowID := c.Param("workflow_id")
+	taskIDStr := c.Param("task_id")
+	taskID, err := strconv.Atoi(taskIDStr)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	s := model.GetStorage()
+
+	workflow, exist, err := s.GetWorkflowDetailByWorkflowID(projectName, workflowID)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	if !exist {
+		return controller.JSONBaseErrorReq(c, ErrWorkflowNoAccess)
+	}
+
+	err = CheckCurrentUserCanOperateTasks(c,
+		&model.Project{Name: projectName}, workflow, []uint{model.OP_WORKFLOW_EXECUTE}, []uint{uint(taskID)})
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+
+	if workflow.Record.Status != model.WorkflowStatusWaitForAudit &&
+		workflow.Record.Status != model.WorkflowStatusWaitForExecution {
+		return controller.JSONBaseErrorReq(c, ErrCanNotRehearseTask(workflow.Record.Status))
+	}
+
+	var taskInWorkflow bool
+	for _, record := range workflow.Record.InstanceRecords {
+		if record.TaskId == uint(taskID) {
+			taskInWorkflow = true
+			break
+		}
+	}
+	if !taskInWorkflow {
+		return controller.JSONBaseErrorReq(c, errors.NewDataNotExistErr("task %v is not in workflow %v", taskID, workflowID))
+	}
+
+	err = server.GetSqled().AddTask(taskIDStr, server.ActionTypeRehearse)
+	return c.JSON(http.StatusOK, controller.NewBaseReq(err))
+}
+
+var ErrCanNotPauseTask = func(taskStatus string) error {
+	return errors.NewDataInvalidErr("task status is %s, it can not be paused", taskStatus)
+}
+
+var ErrCanNotResumeTask = func(taskStatus string) error {
+	return errors.NewDataInvalidErr("task status is %s, it can not be resumed", taskStatus)
+}
+
+// PauseSingleTaskByWorkflowV1
+// @Summary 暂停单个上线任务
+// @Description pause one executing task on workflow, the SQL being executed is not interrupted, the pause takes effect at the following points:
+// @Description 1. before the next chunk of the UPDATE/DELETE executed in batches;
+// @Description 2. before the next non-DML SQL, e.g. DDL;
+// @Description 3. before the next group of adjacent DMLs which are not executed in batches, the DMLs of the group are executed in one transaction, so the task can not be paused between them.
+// @Tags workflow
+// @Id pauseSingleTaskByWorkflowV1
+// @Security ApiKeyAuth
+// @Param workflow_id path string true "workflow id"
+// @Param project_name path string true "project name"
+// @Param task_id path string true "task id"
+// @Success 200 {object} controller.BaseRes
+// @Router /v1/projects/{project_name}/workflows/{workflow_id}/tasks/{task_id}/pause [post]
+func PauseSingleTaskByWorkflowV1(c echo.Context) error {
+	return updateExecutingTaskStatus(c, model.TaskStatusExecuting, model.TaskStatusPaused, ErrCanNotPauseTask)
+}
+
+// ResumeSingleTaskByWorkflowV1
+// @Summary 恢复单个已暂停的上线任务
+// @Description resume one paused task on workflow
+// @Tags workflow
+// @Id resumeSingleTaskByWorkflowV1
+// @Security ApiKeyAuth
+// @Param workflow_id path string true "workflow id"
+// @Param project_name path string true "project name"
+// @Param task_id path string true "task id"
+// @Success 200 {object} controller.BaseRes
+// @Router /v1/projects/{project_name}/workflows/{workflow_id}/tasks/{task_id}/resume [post]
+func ResumeSingleTaskByWorkflowV1(c echo.Context) error {
+	return updateExecutingTaskStatus(c, model.TaskStatusPaused, model.TaskStatusExecuting, ErrCanNotResumeTask)
+}
+
+func updateExecutingTaskStatus(c echo.Context, expected, status string, errFn func(taskStatus string) error) error {
+	projectName := c.Param("project_name")
+	workflowID := c.Param("workflow_id")
+	taskIDStr := c.Param("task_id")
+	taskID, err := strconv.Atoi(taskIDStr)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	s := model.GetStorage()
+
+	workflow, exist, err := s.GetWorkflowDetailByWorkflowID(projectName, workflowID)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	if !exist {
+		return controller.JSONBaseErrorReq(c, ErrWorkflowNoAccess)
+	}
+
+	err = CheckCurrentUserCanOperateTasks(c,
+		&model.Project{Name: projectName}, workflow, []uint{model.OP_WORKFLOW_EXECUTE}, []uint{uint(taskID)})
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+
+	var record *model.WorkflowInstanceRecord
+	for _, instRecord := range workflow.Record.InstanceRecords {
+		if instRecord.TaskId == uint(taskID) {
+			record = instRecord
+			break
+		}
+	}
+	if record == nil {
+		return controller.JSONBaseErrorReq(c, errors.NewDataNotExistErr("task %v is not in workflow %v", taskID, workflowID))
+	}
+
+	ok, err := s.CompareAndUpdateTaskStatus(uint(taskID), expected, status)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	if !ok {
+		// the status may have been changed after the workflow is queried, query it again for the error message.
+		taskStatus, err := s.GetTaskStatusByID(taskIDStr)
+		if err != nil {
+			return controller.JSONBaseErrorReq(c, err)
+		}
+		return controller.JSONBaseErrorReq(c, errFn(taskStatus))
+	}
+	return c.JSON(http.StatusOK, controller.NewBaseReq(nil))
+}
+
+type OnlineDDLCutOverReqV1 struct {
+	Postpone bool `json:"postpone" form:"postpone"`
+}
+
+// CutOverOnlineDDLByWorkflowV1
+// @Summary 触发或推迟上线任务中 gh-ost 的切换表
+// @Description trigger or postpone the cut-over of gh-ost migration of the executing task, the postpone is only available if the instance enables ghost_postpone_cut_over
+// @Description in cluster mode, the request of the migration running on other node is applied by that node within a few seconds
+// @Accept json
+// @Tags workflow
+// @Id cutOverOnlineDDLByWorkflowV1
+// @Security ApiKeyAuth
+// @Param workflow_id path string true "workflow id"
+// @Param project_name path string true "project name"
+// @Param task_id path string true "task id"
+// @Param instance body v1.OnlineDDLCutOverReqV1 true "cut-over request"
+// @Success 200 {object} controller.BaseRes
+// @Router /v1/projects/{project_name}/workflows/{workflow_id}/tasks/{task_id}/online_ddl/cut_over [post]
+func CutOverOnlineDDLByWorkflowV1(c echo.Context) error {
+	req := new(OnlineDDLCutOverReqV1)
+	if err := controller.BindAndValidateReq(c, req); err != nil {
+		return err
+	}
+	projectName := c.Param("project_name")
+	workflowID := c.Param("workflow_id")
+	taskID, err := strconv.Atoi(c.Param("task_id"))
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	s := model.GetStorage()
+
+	workflow, exist, err := s.GetWorkflowDetailByWorkflowID(projectName, workflowID)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	if !exist {
+		return controller.JSONBaseErrorReq(c, ErrWorkflowNoAccess)
+	}
+
+	err = CheckCurrentUserCanOperateTasks(c,
+		&model.Project{Name: projectName}, workflow, []uint{model.OP_WORKFLOW_EXECUTE}, []uint{uint(taskID)})
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+
+	var taskInWorkflow bool
+	for _, record := range workflow.Record.InstanceRecords {
+		if record.TaskId == uint(taskID) {
+			taskInWorkflow = true
+			break
+		}
+	}
+	if !taskInWorkflow {
+		return controller.JSONBaseErrorReq(c, errors.NewDataNotExistErr("task %v is not in workflow %v", taskID, workflowID))
+	}
+
+	err = server.CutOverOnlineDDL(uint(taskID), req.Postpone)
+	if e.Is(err, onlineddl.ErrMigrationNotFound) {
+		return controller.JSONBaseErrorReq(c, errors.NewDataNotExistErr("there is no running gh-ost migration in task %v", taskID))
+	}
+	if e.Is(err, onlineddl.ErrCutOverNotPostponable) {
+		return controller.JSONBaseErrorReq(c, errors.NewDataInvalidErr("%v", err))
+	}
+	return c.JSON(http.StatusOK, controller.NewBaseReq(err))
+}
+
 func checkBeforeTasksTermination(c echo.Context, projectName string, workflow *model.Workflow, needTerminatedTaskIdList []uint) error {
 	needTerminatedTaskIdMap := make(map[uint]struct{}, len(needTerminatedTaskIdList))
 	for _, taskID := range needTerminatedTaskIdList {
@@ -1082,7 +1370,7 @@ func checkBeforeTasksTermination(c echo.Context, projectName string, workflow *m
 
 		isWorkflowWaitForExecution := workflow.Record.Status == model.WorkflowStatusWaitForExecution
 		isWorkflowExecuting := workflow.Record.Status == model.WorkflowStatusExecuting
-		isTaskExecuting := record.Task.Status == model.TaskStatusExecuting
+		isTaskExecuting := record.Task.Status == model.TaskStatusExecuting || record.Task.Status == model.TaskStatusPaused
 
 		if !(isWorkflowWaitForExecution || isWorkflowExecuting) {
 			return ErrCanNotTerminateExecute(workflow.Record.Status, record.Task.Status)
@@ -1116,7 +1404,7 @@ func isTaskCanBeTerminate(s *model.Storage, taskID string) (bool, error) {
 		return false, fmt.Errorf("task instance is nil. taskID=%v", taskID)
 	}
 
-	if task.Status == model.TaskStatusExecuting {
+	if task.Status == model.TaskStatusExecuting || task.Status == model.TaskStatusPaused {
 		return true, nil
 	}
 
@@ -1128,7 +1416,7 @@ func getTerminatingTaskIDs(workflow *model.Workflow) (taskIDs []uint) {
 	taskIDs = make([]uint, 0)
 	for i := range workflow.Record.InstanceRecords {
 		instRecord := workflow.Record.InstanceRecords[i]
-		if instRecord.Task.Status == model.TaskStatusExecuting {
+		if instRecord.Task.Status == model.TaskStatusExecuting || instRecord.Task.Status == model.TaskStatusPaused {
 			taskIDs = append(taskIDs, instRecord.TaskId)
 		}
 	}
@@ -4,15 +4,266 @@
 package v1
 
 import (
-	e "errors"
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/actiontech/sqle/sqle/api/controller"
 	"github.com/actiontech/sqle/sqle/errors"
+	"github.com/actiontech/sqle/sqle/model"
+	"github.com/actiontech/sqle/sqle/pkg/xlsx"
+	"github.com/actiontech/sqle/sqle/utils"
+
 	"github.com/labstack/echo/v4"
 )
 
-var errCommunityEditionDoesNotSupportFeatureExportWorkflowList = errors.New(errors.EnterpriseEditionFeatures, e.New("community edition does not support feature export workflow list"))
+const (
+	exportTimeLayout = "2006-01-02 15:04:05"
+
+	exportFormatXlsx = "xlsx"
+)
+
+// exportWriter writes the rows of exported file, csv.Writer and xlsx.Writer are supported.
+type exportWriter interface {
+	Write(record []string) error
+	Close() error
+}
+
+type csvExportWriter struct {
+	*csv.Writer
+}
+
+func (w csvExportWriter) Close() error {
+	w.Flush()
+	return w.Error()
+}
+
+// newExportWriter returns the writer of export format, the file is csv by default, the
+// sheetName is only used by xlsx.
+func newExportWriter(buff *bytes.Buffer, format, sheetName string) (w exportWriter, contentType, ext string, err error) {
+	if format == exportFormatXlsx {
+		w, err = xlsx.NewWriter(buff, sheetName)
+		return w, "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet", exportFormatXlsx, err
+	}
+	buff.WriteString("\xEF\xBB\xBF") // 写入UTF-8 BOM
+	return csvExportWriter{csv.NewWriter(buff)}, "text/csv", "csv", nil
+}
 
 func exportWorkflowV1(c echo.Context) error {
-	return controller.JSONBaseErrorReq(c, errCommunityEditionDoesNotSupportFeatureExportWorkflowList)
+	req := new(ExportWorkflowReqV1)
+	if err := controller.BindAndValidateReq(c, req); err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+
+	projectName := c.Param("project_name")
+	s := model.GetStorage()
+	project, exist, err := s.GetProjectByName(projectName)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	if !exist {
+		return controller.JSONBaseErrorReq(c, ErrProjectNotExist(projectName))
+	}
+
+	user, err := controller.GetCurrentUser(c)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	if err := CheckIsProjectMember(user.Name, project.Name); err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+
+	data := map[string]interface{}{
+		"filter_workflow_id":                     req.FilterWorkflowID,
+		"fuzzy_search_workflow_desc":             req.FuzzySearchWorkflowDesc,
+		"filter_subject":                         req.FilterSubject,
+		"filter_create_time_from":                req.FilterCreateTimeFrom,
+		"filter_create_time_to":                  req.FilterCreateTimeTo,
+		"filter_create_user_name":                req.FilterCreateUserName,
+		"filter_task_execute_start_time_from":    req.FilterTaskExecuteStartTimeFrom,
+		"filter_task_execute_start_time_to":      req.FilterTaskExecuteStartTimeTo,
+		"filter_status":                          req.FilterStatus,
+		"filter_current_step_assignee_user_name": req.FilterCurrentStepAssigneeUserName,
+		"filter_task_instance_name":              req.FilterTaskInstanceName,
+		"filter_project_name":                    project.Name,
+		"current_user_id":                        user.ID,
+		"check_user_can_access":                  CheckIsProjectManager(user.Name, project.Name) != nil,
+	}
+	workflowList, _, err := s.GetWorkflowsByReq(data, user)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+
+	buff := &bytes.Buffer{}
+	cw, contentType, ext, err := newExportWriter(buff, req.ExportFormat, "工单")
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, errors.New(errors.WriteDataToTheFileError, err))
+	}
+	err = cw.Write([]string{
+		"工单ID", "工单名称", "工单描述", "创建人", "创建时间", "工单状态", "审批记录",
+		"数据源", "Schema", "SQL序号", "SQL", "审核结果", "执行状态", "执行结果", "影响行数", "回滚SQL",
+	})
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, errors.New(errors.WriteDataToTheFileError, err))
+	}
+
+	for _, item := range workflowList {
+		workflow, exist, err := s.GetWorkflowByProjectNameAndWorkflowId(project.Name, item.WorkflowId)
+		if err != nil {
+			return controller.JSONBaseErrorReq(c, err)
+		}
+		if !exist {
+			continue
+		}
+		workflow, exist, err = s.GetWorkflowExportById(strconv.Itoa(int(workflow.ID)))
+		if err != nil {
+			return controller.JSONBaseErrorReq(c, err)
+		}
+		if !exist {
+			continue
+		}
+		for _, row := range getWorkflowExportRows(workflow) {
+			if err := cw.Write(row); err != nil {
+				return controller.JSONBaseErrorReq(c, errors.New(errors.WriteDataToTheFileError, err))
+			}
+		}
+	}
+	if err := cw.Close(); err != nil {
+		return controller.JSONBaseErrorReq(c, errors.New(errors.WriteDataToTheFileError, err))
+	}
+
+	fileName := fmt.Sprintf("%s_工单_%s.%s", project.Name, time.Now().Format("20060102150405"), ext)
+	if req.FilterWorkflowID != "" {
+		fileName = fmt.Sprintf("%s_工单_%s.%s", project.Name, req.FilterWorkflowID, ext)
+	}
+	c.Response().Header().Set(echo.HeaderContentDisposition,
+		mime.FormatMediaType("attachment", map[string]string{"filename": fileName}))
+	return c.Blob(http.StatusOK, contentType, buff.Bytes())
+}
+
+// getWorkflowExportRows returns one row for each SQL of the workflow, the workflow information is repeated in each row.
+func getWorkflowExportRows(workflow *model.Workflow) [][]string {
+	createUser := ""
+	if workflow.CreateUser != nil {
+		createUser = utils.AddDelTag(workflow.CreateUser.DeletedAt, workflow.CreateUser.Name)
+	}
+	workflowColumns := []string{
+		workflow.WorkflowId,
+		workflow.Subject,
+		workflow.Desc,
+		createUser,
+		workflow.CreatedAt.Format(exportTimeLayout),
+		model.WorkflowStatus[workflow.Record.Status],
+		getWorkflowApprovalHistory(workflow),
+	}
+
+	rows := [][]string{}
+	for _, instanceRecord := range workflow.Record.InstanceRecords {
+		task := instanceRecord.Task
+		if task == nil {
+			continue
+		}
+		instanceName := ""
+		if instanceRecord.Instance != nil {
+			instanceName = utils.AddDelTag(instanceRecord.Instance.DeletedAt, instanceRecord.Instance.Name)
+		}
+		rollbackSQLs := map[uint][]string{}
+		for _, rollbackSQL := range task.RollbackSQLs {
+			rollbackSQLs[rollbackSQL.ExecuteSQLId] = append(rollbackSQLs[rollbackSQL.ExecuteSQLId], rollbackSQL.Content)
+		}
+		for _, executeSQL := range task.ExecuteSQLs {
+			row := append([]string{}, workflowColumns...)
+			row = append(row,
+				instanceName,
+				task.Schema,
+				strconv.Itoa(int(executeSQL.Number)),
+				executeSQL.Content,
+				executeSQL.AuditResults.String(),
+				executeSQL.GetExecStatusDesc(),
+				executeSQL.ExecResult,
+				strconv.FormatInt(executeSQL.RowAffects, 10),
+				strings.Join(rollbackSQLs[executeSQL.ID], "\n"),
+			)
+			rows = append(rows, row)
+		}
+	}
+	// keep the workflow in export even if it has no SQL, e.g. the task is deleted.
+	if len(rows) == 0 {
+		rows = append(rows, append(workflowColumns, make([]string, 9)...))
+	}
+	return rows
+}
+
+// getWorkflowApprovalHistory returns the operated steps of all records of the workflow, one step per line.
+// The step approved by several assignees has one line per assignee.
+func getWorkflowApprovalHistory(workflow *model.Workflow) string {
+	steps := []*model.WorkflowStep{}
+	for _, record := range workflow.RecordHistory {
+		steps = append(steps, record.Steps...)
+	}
+	steps = append(steps, workflow.Record.Steps...)
+
+	lines := []string{}
+	for _, step := range steps {
+		stepDesc := ""
+		if step.Template != nil {
+			stepDesc = step.Template.Desc
+			if stepDesc == "" {
+				stepDesc = getWorkflowStepTypeDesc(step.Template.Typ)
+			}
+		}
+		if len(step.Approvals) > 0 {
+			for _, approval := range step.Approvals {
+				if approval.OperateAt == nil {
+					continue
+				}
+				lines = append(lines, formatWorkflowApprovalHistoryLine(*approval.OperateAt, stepDesc, approval.User, approval.State, approval.Reason))
+			}
+			continue
+		}
+		if step.State == model.WorkflowStepStateInit || step.OperateAt == nil {
+			continue
+		}
+		lines = append(lines, formatWorkflowApprovalHistoryLine(*step.OperateAt, stepDesc, step.OperationUser, step.State, step.Reason))
+	}
+	return strings.Join(lines, "\n")
+}
+
+func formatWorkflowApprovalHistoryLine(operateAt time.Time, stepDesc string, user *model.User, state, reason string) string {
+	operator := ""
+	if user != nil {
+		operator = utils.AddDelTag(user.DeletedAt, user.Name)
+	}
+	line := fmt.Sprintf("%s %s %s %s", operateAt.Format(exportTimeLayout), stepDesc, operator, getWorkflowStepStateDesc(state))
+	if reason != "" {
+		line = fmt.Sprintf("%s: %s", line, reason)
+	}
+	return line
+}
+
+func getWorkflowStepTypeDesc(typ string) string {
+	switch typ {
+	case model.WorkflowStepTypeSQLReview:
+		return "审核"
+	case model.WorkflowStepTypeSQLExecute:
+		return "上线"
+	default:
+		return typ
+	}
+}
+
+func getWorkflowStepStateDesc(state string) string {
+	switch state {
+	case model.WorkflowStepStateApprove:
+		return "通过"
+	case model.WorkflowStepStateReject:
+		return "驳回"
+	default:
+		return state
+	}
 }
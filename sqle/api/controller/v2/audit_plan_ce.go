@@ -4,12 +4,90 @@
 package v2
 
 import (
+	"net/http"
+	"strconv"
+
 	"github.com/actiontech/sqle/sqle/api/controller"
-	"github.com/actiontech/sqle/sqle/errors"
+	v1 "github.com/actiontech/sqle/sqle/api/controller/v1"
+	"github.com/actiontech/sqle/sqle/model"
 
 	"github.com/labstack/echo/v4"
 )
 
+// executionStatisticsHistoryLimit is the max number of reports used to show the execution statistics over time.
+const executionStatisticsHistoryLimit = 30
+
 func getAuditPlanAnalysisData(c echo.Context) error {
-	return controller.JSONBaseErrorReq(c, errors.NewNotSupportGetAuditPlanAnalysisDataErr())
+	ap, instance, reportSQL, err := v1.GetAuditPlanReportSQLForAnalysis(c, c.Param("project_name"),
+		c.Param("audit_plan_name"), c.Param("audit_plan_report_id"), c.Param("number"))
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	schema := reportSQL.Schema
+	if schema == "" {
+		schema = ap.InstanceDatabase
+	}
+	res, err := v1.GetSQLAnalysisResult(instance, schema, reportSQL.SQL)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	data := convertSQLAnalysisResultToRes(reportSQL.SQL, res)
+
+	statistics, err := getExecutionStatistics(ap.ID, reportSQL)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	data.PerformanceStatistics.ExecutionStatistics = statistics
+
+	return c.JSON(http.StatusOK, &GetAuditPlanAnalysisDataResV2{
+		BaseRes: controller.NewBaseReq(nil),
+		Data:    data,
+	})
+}
+
+func getExecutionStatistics(auditPlanId uint, reportSQL *model.AuditPlanReportSQLV2) (*ExecutionStatistics, error) {
+	// the SQL of reports generated before the statistics are recorded has no fingerprint.
+	if reportSQL.FingerprintMD5 == "" {
+		return nil, nil
+	}
+	statistics := &ExecutionStatistics{History: []*ExecutionStatisticsPoint{}}
+	statistics.Counter, statistics.QueryTimeAvg, statistics.QueryTimeMax = parseExecutionStatistics(reportSQL.Info)
+
+	history, err := model.GetStorage().GetAuditPlanReportSQLInfoHistory(auditPlanId, reportSQL.FingerprintMD5, executionStatisticsHistoryLimit)
+	if err != nil {
+		return nil, err
+	}
+	for _, h := range history {
+		point := &ExecutionStatisticsPoint{Time: h.ReportCreatedAt}
+		point.Counter, point.QueryTimeAvg, point.QueryTimeMax = parseExecutionStatistics(h.Info)
+		statistics.History = append(statistics.History, point)
+	}
+	return statistics, nil
+}
+
+// parseExecutionStatistics parses the counter and query time from the info of audit plan SQL,
+// the values may be recorded as number or string by different audit plan types.
+func parseExecutionStatistics(info model.JSON) (counter int64, queryTimeAvg, queryTimeMax float64) {
+	if len(info) == 0 {
+		return
+	}
+	values, err := info.OriginValue()
+	if err != nil {
+		return
+	}
+	counter = int64(parseFloat(values["counter"]))
+	queryTimeAvg = parseFloat(values["query_time_avg"])
+	queryTimeMax = parseFloat(values["query_time_max"])
+	return
+}
+
+func parseFloat(v interface{}) float64 {
+	switch v := v.(type) {
+	case float64:
+		return v
+	case string:
+		f, _ := strconv.ParseFloat(v, 64)
+		return f
+	}
+	return 0
 }
@@ -2,6 +2,7 @@ package v2
 
 import (
 	"net/http"
+	"time"
 
 	"github.com/actiontech/sqle/sqle/api/controller"
 	v1 "github.com/actiontech/sqle/sqle/api/controller/v1"
@@ -36,6 +37,30 @@ type AuditTaskSQLResV2 struct {
 	ExecStatus  string         `json:"exec_status"`
 	RollbackSQL string         `json:"rollback_sql,omitempty"`
 	Description string         `json:"description"`
+
+	RehearseStatus     string `json:"rehearse_status" enums:"initialized,succeeded,failed,skipped"`
+	RehearseResult     string `json:"rehearse_result"`
+	RehearseRowAffects int64  `json:"rehearse_row_affects"`
+	RehearseWarnings   string `json:"rehearse_warnings"`
+	RehearseElapsedMs  int64  `json:"rehearse_elapsed_ms"`
+
+	RowAffects    int64   `json:"row_affects"`
+	BatchProgress float64 `json:"batch_progress"`
+	BatchChunks   int64   `json:"batch_chunks"`
+
+	OnlineDDLProgress *OnlineDDLProgressResV2 `json:"online_ddl_progress,omitempty"`
+}
+
+type OnlineDDLProgressResV2 struct {
+	RowsCopied         int64   `json:"rows_copied"`
+	RowsEstimate       int64   `json:"rows_estimate"`
+	ProgressPct        float64 `json:"progress_pct"`
+	ETASeconds         int64   `json:"eta_seconds"`
+	LagMillis          int64   `json:"lag_millis"`
+	Throttled          bool    `json:"throttled"`
+	ThrottleReason     string  `json:"throttle_reason"`
+	PostponingCutOver  bool    `json:"postponing_cut_over"`
+	CutOverPostponable bool    `json:"cut_over_postponable"`
 }
 
 type AuditResult struct {
@@ -108,6 +133,29 @@ func GetTaskSQLs(c echo.Context) error {
 			ExecResult:  taskSQL.ExecResult,
 			ExecStatus:  taskSQL.ExecStatus,
 			RollbackSQL: taskSQL.RollbackSQL.String,
+
+			RehearseStatus:     taskSQL.RehearseStatus.String,
+			RehearseResult:     taskSQL.RehearseResult.String,
+			RehearseRowAffects: taskSQL.RehearseRowAffects.Int64,
+			RehearseWarnings:   taskSQL.RehearseWarnings.String,
+			RehearseElapsedMs:  taskSQL.RehearseElapsedMs.Int64,
+
+			RowAffects:    taskSQL.RowAffects.Int64,
+			BatchProgress: taskSQL.BatchProgress.Float64,
+			BatchChunks:   taskSQL.BatchChunks.Int64,
+		}
+		if p := taskSQL.OnlineDDLProgress; p != nil {
+			taskSQLRes.OnlineDDLProgress = &OnlineDDLProgressResV2{
+				RowsCopied:         p.RowsCopied,
+				RowsEstimate:       p.RowsEstimate,
+				ProgressPct:        p.ProgressPct,
+				ETASeconds:         p.ETASeconds,
+				LagMillis:          p.LagMillis,
+				Throttled:          p.Throttled,
+				ThrottleReason:     p.ThrottleReason,
+				PostponingCutOver:  p.PostponingCutOver,
+				CutOverPostponable: p.CutOverPostponable,
+			}
 		}
 		for i := range taskSQL.AuditResults {
 			ar := taskSQL.AuditResults[i]
@@ -142,6 +190,22 @@ type SQLExplain struct {
 
 type PerformanceStatistics struct {
 	AffectRows *AffectRows `json:"affect_rows"`
+	// ExecutionStatistics is only available for SQL collected by audit plan
+	ExecutionStatistics *ExecutionStatistics `json:"execution_statistics,omitempty"`
+}
+
+type ExecutionStatistics struct {
+	Counter      int64                       `json:"counter"`
+	QueryTimeAvg float64                     `json:"query_time_avg"`
+	QueryTimeMax float64                     `json:"query_time_max"`
+	History      []*ExecutionStatisticsPoint `json:"history"`
+}
+
+type ExecutionStatisticsPoint struct {
+	Time         time.Time `json:"time"`
+	Counter      int64     `json:"counter"`
+	QueryTimeAvg float64   `json:"query_time_avg"`
+	QueryTimeMax float64   `json:"query_time_max"`
 }
 
 type TableMetas struct {
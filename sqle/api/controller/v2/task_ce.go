@@ -4,12 +4,58 @@
 package v2
 
 import (
+	"net/http"
+
 	"github.com/actiontech/sqle/sqle/api/controller"
-	"github.com/actiontech/sqle/sqle/errors"
+	v1 "github.com/actiontech/sqle/sqle/api/controller/v1"
 
 	"github.com/labstack/echo/v4"
 )
 
 func getTaskAnalysisData(c echo.Context) error {
-	return controller.JSONBaseErrorReq(c, errors.NewNotSupportGetTaskAnalysisDataErr())
+	task, executeSQL, err := v1.GetTaskSQLForAnalysis(c, c.Param("task_id"), c.Param("number"))
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	res, err := v1.GetSQLAnalysisResult(task.Instance, task.Schema, executeSQL.Content)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+
+	return c.JSON(http.StatusOK, &GetTaskAnalysisDataResV2{
+		BaseRes: controller.NewBaseReq(nil),
+		Data:    convertSQLAnalysisResultToRes(executeSQL.Content, res),
+	})
+}
+
+func convertSQLAnalysisResultToRes(sql string, res *v1.SQLAnalysisResult) *TaskAnalysisDataV2 {
+	data := &TaskAnalysisDataV2{
+		SQLExplain:            &SQLExplain{SQL: sql},
+		TableMetas:            &TableMetas{Items: []*v1.TableMeta{}},
+		PerformanceStatistics: &PerformanceStatistics{AffectRows: &AffectRows{}},
+	}
+
+	explain := v1.ConvertExplainResultToRes(sql, res.ExplainResult, res.ExplainErr)
+	if res.ExplainErr != nil {
+		data.SQLExplain.ErrMessage = explain.Message
+	} else {
+		data.SQLExplain.ClassicResult = &explain.ClassicResult
+	}
+
+	if res.TableMetaErr != nil {
+		data.TableMetas.ErrMessage = res.TableMetaErr.Error()
+	} else {
+		tableMetas := v1.ConvertTableMetasToRes(res.TableMetaResult)
+		for i := range tableMetas {
+			data.TableMetas.Items = append(data.TableMetas.Items, &tableMetas[i])
+		}
+	}
+
+	if res.AffectRowsErr != nil {
+		data.PerformanceStatistics.AffectRows.ErrMessage = res.AffectRowsErr.Error()
+	} else {
+		data.PerformanceStatistics.AffectRows.Count = int(res.AffectRows.Count)
+		data.PerformanceStatistics.AffectRows.ErrMessage = res.AffectRows.ErrMessage
+	}
+	return data
 }
@@ -10,8 +10,10 @@ import (
 	"github.com/actiontech/sqle/sqle/server"
 
 	"github.com/actiontech/sqle/sqle/errors"
+	"github.com/actiontech/sqle/sqle/log"
 	"github.com/actiontech/sqle/sqle/notification"
 	"github.com/actiontech/sqle/sqle/pkg/im"
+	"github.com/actiontech/sqle/sqle/server/auditplan"
 	"github.com/actiontech/sqle/sqle/utils"
 
 	"github.com/actiontech/sqle/sqle/model"
@@ -32,7 +34,18 @@ type WorkflowStepResV2 struct {
 	Users         []string   `json:"assignee_user_name_list,omitempty"`
 	OperationUser string     `json:"operation_user_name,omitempty"`
 	OperationTime *time.Time `json:"operation_time,omitempty"`
-	State         string     `json:"state,omitempty" enums:"initialized,approved,rejected"`
+	State         string     `json:"state,omitempty" enums:"initialized,approved,rejected,skipped"`
+	Reason        string     `json:"reason,omitempty"`
+	// ApproveMode and RequiredApprovalCount are only returned for sql_review step.
+	ApproveMode           string                       `json:"approve_mode,omitempty" enums:"any_of,all_of,quorum"`
+	RequiredApprovalCount int                          `json:"required_approval_count,omitempty"`
+	Approvals             []*WorkflowStepApprovalResV2 `json:"approval_list,omitempty"`
+}
+
+type WorkflowStepApprovalResV2 struct {
+	OperationUser string     `json:"operation_user_name"`
+	OperationTime *time.Time `json:"operation_time"`
+	State         string     `json:"state" enums:"approved,rejected"`
 	Reason        string     `json:"reason,omitempty"`
 }
 
@@ -110,7 +123,8 @@ func ApproveWorkflowV2(c echo.Context) error {
 
 	go im.UpdateApprove(workflow.ID, user, model.ApproveStatusAgree, "")
 
-	if nextStep != nil {
+	// the step may wait for the approvals of other assignees
+	if nextStep != nil && workflow.Record.CurrentWorkflowStepId == nextStep.ID {
 		go im.CreateApprove(strconv.Itoa(int(workflow.ID)))
 	}
 
@@ -196,6 +210,9 @@ func RejectWorkflowV2(c echo.Context) error {
 		if inst.ScheduledAt != nil {
 			return controller.JSONBaseErrorReq(c, fmt.Errorf("can not reject workflow, cause there is any task is scheduled to be executed"))
 		}
+		if inst.MaintenanceQueuedAt != nil {
+			return controller.JSONBaseErrorReq(c, fmt.Errorf("can not reject workflow, cause there is any task is queued to be executed in maintenance time"))
+		}
 	}
 
 	if err := server.RejectWorkflowProcess(workflow, req.Reason, user, s); err != nil {
@@ -492,6 +509,10 @@ func ExecuteOneTaskOnWorkflowV2(c echo.Context) error {
 		return controller.JSONBaseErrorReq(c, fmt.Errorf("task has no need to be executed. taskId=%v workflowId=%v", taskId, workflowId))
 	}
 
+	if err := workflow.Record.CheckTaskExecuteStage(uint(taskId)); err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+
 	err = server.ExecuteWorkflow(workflow, map[uint]uint{uint(taskId): user.ID})
 	if err != nil {
 		return controller.JSONBaseErrorReq(c, err)
@@ -507,10 +528,13 @@ type GetWorkflowTasksResV2 struct {
 type GetWorkflowTasksItemV2 struct {
 	TaskId                   uint                       `json:"task_id"`
 	InstanceName             string                     `json:"instance_name"`
-	Status                   string                     `json:"status" enums:"wait_for_audit,wait_for_execution,exec_scheduled,exec_failed,exec_succeeded,executing,manually_executed,terminating,terminate_succeeded,terminate_failed"`
+	Status                   string                     `json:"status" enums:"wait_for_audit,wait_for_execution,exec_scheduled,exec_queued,exec_failed,exec_succeeded,executing,paused,manually_executed,terminating,terminate_succeeded,terminate_failed"`
 	ExecStartTime            *time.Time                 `json:"exec_start_time,omitempty"`
 	ExecEndTime              *time.Time                 `json:"exec_end_time,omitempty"`
 	ScheduleTime             *time.Time                 `json:"schedule_time,omitempty"`
+	MaintenanceQueuedTime    *time.Time                 `json:"maintenance_queued_time,omitempty"`
+	MaintenancePriority      string                     `json:"maintenance_priority,omitempty" enums:"high,medium,low"`
+	ExecuteStage             uint                       `json:"execute_stage,omitempty"`
 	CurrentStepAssigneeUser  []string                   `json:"current_step_assignee_user_name_list,omitempty"`
 	TaskPassRate             float64                    `json:"task_pass_rate"`
 	TaskScore                int32                      `json:"task_score"`
@@ -579,10 +603,13 @@ func convertWorkflowToTasksSummaryRes(taskDetails []*model.WorkflowTasksSummaryD
 		res[i] = &GetWorkflowTasksItemV2{
 			TaskId:                   taskDetail.TaskId,
 			InstanceName:             utils.AddDelTag(taskDetail.InstanceDeletedAt, taskDetail.InstanceName),
-			Status:                   v1.GetTaskStatusRes(taskDetail.WorkflowRecordStatus, taskDetail.TaskStatus, taskDetail.InstanceScheduledAt),
+			Status:                   v1.GetTaskStatusRes(taskDetail.WorkflowRecordStatus, taskDetail.TaskStatus, taskDetail.InstanceScheduledAt, taskDetail.MaintenanceQueuedAt),
 			ExecStartTime:            taskDetail.TaskExecStartAt,
 			ExecEndTime:              taskDetail.TaskExecEndAt,
 			ScheduleTime:             taskDetail.InstanceScheduledAt,
+			MaintenanceQueuedTime:    taskDetail.MaintenanceQueuedAt,
+			MaintenancePriority:      convertMaintenancePriorityToRes(taskDetail.MaintenancePriority),
+			ExecuteStage:             taskDetail.ExecuteStage,
 			CurrentStepAssigneeUser:  taskDetail.CurrentStepAssigneeUsers,
 			TaskPassRate:             taskDetail.TaskPassRate,
 			TaskScore:                taskDetail.TaskScore,
@@ -772,6 +799,13 @@ func CreateWorkflowV2(c echo.Context) error {
 	workFlowId := strconv.Itoa(int(workflow.ID))
 	go notification.NotifyWorkflow(workFlowId, notification.WorkflowNotifyTypeCreate)
 
+	go func() {
+		syncFromWorkflow := auditplan.NewSyncFromWorkflow(taskIds, project.ID)
+		if err := syncFromWorkflow.SyncSqlManager(); err != nil {
+			log.NewEntry().WithField("workflow", workflow.WorkflowId).Errorf("sync sql manage failed, error: %v", err)
+		}
+	}()
+
 	go im.CreateApprove(workFlowId)
 
 	return c.JSON(http.StatusOK, &CreateWorkflowResV2{
@@ -921,10 +955,43 @@ func UpdateWorkflowV2(c echo.Context) error {
 
 type UpdateWorkflowScheduleReqV2 struct {
 	ScheduleTime *time.Time `json:"schedule_time"`
+	// 在数据源的下个运维时间内自动上线，同一数据源的任务按优先级和排队时间依次上线，与定时上线互斥
+	ExecuteInMaintenanceTime bool   `json:"execute_in_maintenance_time"`
+	MaintenancePriority      string `json:"maintenance_priority" enums:"high,medium,low" valid:"omitempty,oneof=high medium low"`
+}
+
+const (
+	maintenancePriorityHigh   = "high"
+	maintenancePriorityMedium = "medium"
+	maintenancePriorityLow    = "low"
+)
+
+func convertMaintenancePriorityToModel(priority string) int {
+	switch priority {
+	case maintenancePriorityHigh:
+		return model.MaintenancePriorityHigh
+	case maintenancePriorityLow:
+		return model.MaintenancePriorityLow
+	default:
+		return model.MaintenancePriorityMedium
+	}
+}
+
+func convertMaintenancePriorityToRes(priority int) string {
+	switch priority {
+	case model.MaintenancePriorityHigh:
+		return maintenancePriorityHigh
+	case model.MaintenancePriorityMedium:
+		return maintenancePriorityMedium
+	case model.MaintenancePriorityLow:
+		return maintenancePriorityLow
+	default:
+		return ""
+	}
 }
 
 // UpdateWorkflowScheduleV2
-// @Summary 设置工单数据源定时上线时间（设置为空则代表取消定时时间，需要SQL审核流程都通过后才可以设置）
+// @Summary 设置工单数据源定时上线时间或在下个运维时间内自动上线（都设置为空则代表取消，需要SQL审核流程都通过后才可以设置）
 // @Description update workflow schedule.
 // @Tags workflow
 // @Accept json
@@ -1003,6 +1070,11 @@ func UpdateWorkflowScheduleV2(c echo.Context) error {
 		return controller.JSONBaseErrorReq(c, errors.New(errors.DataInvalid, _err.New("task is not found in workflow")))
 	}
 
+	if req.ScheduleTime != nil && req.ExecuteInMaintenanceTime {
+		return controller.JSONBaseErrorReq(c, errors.New(errors.DataInvalid, fmt.Errorf(
+			"schedule time and execute in maintenance time can not be set at the same time")))
+	}
+
 	if req.ScheduleTime != nil && req.ScheduleTime.Before(time.Now()) {
 		return controller.JSONBaseErrorReq(c, errors.New(errors.DataInvalid, fmt.Errorf(
 			"request schedule time is too early")))
@@ -1013,6 +1085,13 @@ func UpdateWorkflowScheduleV2(c echo.Context) error {
 			"task has been executed")))
 	}
 
+	// the tasks of later stages are executed automatically after the previous stage.
+	if req.ScheduleTime != nil || req.ExecuteInMaintenanceTime {
+		if err := workflow.Record.CheckTaskExecuteStage(curTaskRecord.TaskId); err != nil {
+			return controller.JSONBaseErrorReq(c, err)
+		}
+	}
+
 	instance, exist, err := s.GetInstanceById(fmt.Sprintf("%v", curTaskRecord.InstanceId))
 	if err != nil {
 		return controller.JSONBaseErrorReq(c, err)
@@ -1025,7 +1104,15 @@ func UpdateWorkflowScheduleV2(c echo.Context) error {
 		return controller.JSONBaseErrorReq(c, v1.ErrWorkflowExecuteTimeIncorrect)
 	}
 
-	err = s.UpdateInstanceRecordSchedule(curTaskRecord, user.ID, req.ScheduleTime)
+	if req.ExecuteInMaintenanceTime {
+		if len(instance.MaintenancePeriod) == 0 {
+			return controller.JSONBaseErrorReq(c, errors.New(errors.DataInvalid, fmt.Errorf(
+				"instance %s has no maintenance time", instance.Name)))
+		}
+		err = s.QueueInstanceRecordForMaintenance(curTaskRecord, user.ID, convertMaintenancePriorityToModel(req.MaintenancePriority))
+	} else {
+		err = s.UpdateInstanceRecordSchedule(curTaskRecord, user.ID, req.ScheduleTime)
+	}
 	if err != nil {
 		return controller.JSONBaseErrorReq(c, err)
 	}
@@ -1073,6 +1160,235 @@ func ExecuteTasksOnWorkflowV2(c echo.Context) error {
 	return c.JSON(http.StatusOK, controller.NewBaseReq(nil))
 }
 
+type UpdateWorkflowExecuteStagesReqV2 struct {
+	// 按上线顺序排列的阶段，需要包含工单的所有任务，为空则代表取消分阶段上线
+	Stages []*WorkflowExecuteStageReqV2 `json:"stages"`
+}
+
+type WorkflowExecuteStageReqV2 struct {
+	TaskIds []uint `json:"task_ids" valid:"required"`
+}
+
+// UpdateWorkflowExecuteStagesV2
+// @Summary 设置工单任务分阶段上线（前一阶段全部上线成功后自动上线下一阶段，任一任务上线失败则停止上线之后的阶段）
+// @Description update execute stages of workflow tasks, it can be updated before any task is executed. A task depends on all the tasks of the previous stages, the dependency between two single tasks is not supported. The task of later stage whose instance is out of maintenance window is queued for the next maintenance window.
+// @Tags workflow
+// @Accept json
+// @Produce json
+// @Id updateWorkflowExecuteStagesV2
+// @Security ApiKeyAuth
+// @Param workflow_id path string true "workflow id"
+// @Param project_name path string true "project name"
+// @Param instance body v2.UpdateWorkflowExecuteStagesReqV2 true "update workflow execute stages request"
+// @Success 200 {object} controller.BaseRes
+// @router /v2/projects/{project_name}/workflows/{workflow_id}/tasks/execute_stages [put]
+func UpdateWorkflowExecuteStagesV2(c echo.Context) error {
+	req := new(UpdateWorkflowExecuteStagesReqV2)
+	if err := controller.BindAndValidateReq(c, req); err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	projectName := c.Param("project_name")
+	workflowId := c.Param("workflow_id")
+
+	s := model.GetStorage()
+	workflow, exist, err := s.GetWorkflowByProjectNameAndWorkflowId(projectName, workflowId)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	if !exist {
+		return controller.JSONBaseErrorReq(c, v1.ErrWorkflowNoAccess)
+	}
+
+	err = v1.CheckCurrentUserCanOperateWorkflow(c, &model.Project{Name: projectName}, workflow, []uint{})
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+
+	user, err := controller.GetCurrentUser(c)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	workflow, exist, err = s.GetWorkflowDetailById(strconv.Itoa(int(workflow.ID)))
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	if !exist {
+		return controller.JSONBaseErrorReq(c, v1.ErrWorkflowNoAccess)
+	}
+
+	// the stages can be updated by the creator or the operator of current step.
+	if user.ID != workflow.CreateUserId {
+		currentStep := workflow.CurrentStep()
+		if currentStep == nil {
+			return controller.JSONBaseErrorReq(c, errors.New(errors.DataInvalid, _err.New("workflow current step not found")))
+		}
+		if err := server.CheckUserCanOperateStep(user, workflow, int(currentStep.ID)); err != nil {
+			return controller.JSONBaseErrorReq(c, errors.New(errors.DataInvalid, err))
+		}
+	}
+
+	if workflow.Record.Status != model.WorkflowStatusWaitForAudit &&
+		workflow.Record.Status != model.WorkflowStatusWaitForExecution {
+		return controller.JSONBaseErrorReq(c, errors.New(errors.DataInvalid,
+			fmt.Errorf("workflow status is %s, not allow to update execute stages", workflow.Record.Status)))
+	}
+	for _, ir := range workflow.Record.InstanceRecords {
+		if ir.IsSQLExecuted || ir.ScheduledAt != nil || ir.MaintenanceQueuedAt != nil {
+			return controller.JSONBaseErrorReq(c, errors.New(errors.DataInvalid,
+				fmt.Errorf("can not update execute stages, cause there is any task is executed or scheduled")))
+		}
+	}
+
+	taskIdToStage, err := checkWorkflowExecuteStages(workflow, req.Stages)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	err = s.UpdateWorkflowExecuteStages(workflow.Record, taskIdToStage)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	return c.JSON(http.StatusOK, controller.NewBaseReq(nil))
+}
+
+// checkWorkflowExecuteStages checks that each task of workflow is in exactly one stage, and
+// returns the stage of tasks which starts from 1.
+func checkWorkflowExecuteStages(workflow *model.Workflow, stages []*WorkflowExecuteStageReqV2) (map[uint]uint, error) {
+	taskIdToStage := map[uint]uint{}
+	if len(stages) == 0 {
+		return taskIdToStage, nil
+	}
+	workflowTaskIds := map[uint]struct{}{}
+	for _, ir := range workflow.Record.InstanceRecords {
+		workflowTaskIds[ir.TaskId] = struct{}{}
+	}
+	for i, stage := range stages {
+		for _, taskId := range stage.TaskIds {
+			if _, ok := workflowTaskIds[taskId]; !ok {
+				return nil, errors.New(errors.DataInvalid, fmt.Errorf("task %d is not in workflow", taskId))
+			}
+			if _, ok := taskIdToStage[taskId]; ok {
+				return nil, errors.New(errors.DataInvalid, fmt.Errorf("task %d is in more than one stage", taskId))
+			}
+			taskIdToStage[taskId] = uint(i + 1)
+		}
+	}
+	if len(taskIdToStage) != len(workflowTaskIds) {
+		return nil, errors.New(errors.DataInvalid, fmt.Errorf("all the tasks of workflow should be in the stages"))
+	}
+	return taskIdToStage, nil
+}
+
+type GetWorkflowExecuteStagesResV2 struct {
+	controller.BaseRes
+	Data []*WorkflowExecuteStageResV2 `json:"data"`
+}
+
+type WorkflowExecuteStageResV2 struct {
+	Stage uint                             `json:"stage"`
+	State string                           `json:"state" enums:"pending,executing,succeeded,failed"`
+	Tasks []*WorkflowExecuteStageTaskResV2 `json:"tasks"`
+}
+
+type WorkflowExecuteStageTaskResV2 struct {
+	TaskId       uint   `json:"task_id"`
+	InstanceName string `json:"instance_name"`
+	Status       string `json:"status"`
+	// 工单停止上线时，返回已上线成功的任务的回滚SQL
+	RollbackSQLs []string `json:"rollback_sql_list,omitempty"`
+}
+
+// GetWorkflowExecuteStagesV2
+// @Summary 获取工单任务上线阶段
+// @Description get execute stages of workflow tasks, the rollback SQLs of executed tasks are returned if the execution is halted.
+// @Tags workflow
+// @Id getWorkflowExecuteStagesV2
+// @Security ApiKeyAuth
+// @Param workflow_id path string true "workflow id"
+// @Param project_name path string true "project name"
+// @Success 200 {object} v2.GetWorkflowExecuteStagesResV2
+// @router /v2/projects/{project_name}/workflows/{workflow_id}/tasks/execute_stages [get]
+func GetWorkflowExecuteStagesV2(c echo.Context) error {
+	projectName := c.Param("project_name")
+	workflowId := c.Param("workflow_id")
+
+	if err := CheckCurrentUserCanViewWorkflow(c, workflowId, projectName); err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+
+	s := model.GetStorage()
+	workflow, exist, err := s.GetWorkflowByProjectNameAndWorkflowId(projectName, workflowId)
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	if !exist {
+		return controller.JSONBaseErrorReq(c, v1.ErrWorkflowNoAccess)
+	}
+	workflow, exist, err = s.GetWorkflowDetailById(strconv.Itoa(int(workflow.ID)))
+	if err != nil {
+		return controller.JSONBaseErrorReq(c, err)
+	}
+	if !exist {
+		return controller.JSONBaseErrorReq(c, v1.ErrWorkflowNoAccess)
+	}
+
+	res := []*WorkflowExecuteStageResV2{}
+	if !workflow.Record.IsStaged() {
+		return c.JSON(http.StatusOK, &GetWorkflowExecuteStagesResV2{
+			BaseRes: controller.NewBaseReq(nil),
+			Data:    res,
+		})
+	}
+
+	currentStage := workflow.Record.CurrentExecuteStage()
+	halted := currentStage != nil && currentStage.State() == model.WorkflowExecuteStageStateFailed
+	for _, stage := range workflow.Record.GetExecuteStages() {
+		stageRes := &WorkflowExecuteStageResV2{
+			Stage: stage.Stage,
+			State: stage.State(),
+			Tasks: make([]*WorkflowExecuteStageTaskResV2, 0, len(stage.InstanceRecords)),
+		}
+		for _, ir := range stage.InstanceRecords {
+			taskRes := &WorkflowExecuteStageTaskResV2{TaskId: ir.TaskId}
+			if ir.Instance != nil {
+				taskRes.InstanceName = ir.Instance.Name
+			}
+			if ir.Task != nil {
+				taskRes.Status = ir.Task.Status
+			}
+			if halted && taskRes.Status == model.TaskStatusExecuteSucceeded {
+				taskRes.RollbackSQLs, err = getTaskRollbackSQLs(s, ir.TaskId)
+				if err != nil {
+					return controller.JSONBaseErrorReq(c, err)
+				}
+			}
+			stageRes.Tasks = append(stageRes.Tasks, taskRes)
+		}
+		res = append(res, stageRes)
+	}
+
+	return c.JSON(http.StatusOK, &GetWorkflowExecuteStagesResV2{
+		BaseRes: controller.NewBaseReq(nil),
+		Data:    res,
+	})
+}
+
+func getTaskRollbackSQLs(s *model.Storage, taskId uint) ([]string, error) {
+	task, exist, err := s.GetTaskDetailById(strconv.Itoa(int(taskId)))
+	if err != nil {
+		return nil, err
+	}
+	if !exist {
+		return nil, errors.NewTaskNoExistOrNoAccessErr()
+	}
+	sqls := make([]string, 0, len(task.RollbackSQLs))
+	for _, rollbackSQL := range task.RollbackSQLs {
+		if rollbackSQL.Content != "" {
+			sqls = append(sqls, rollbackSQL.Content)
+		}
+	}
+	return sqls, nil
+}
+
 type GetWorkflowResV2 struct {
 	controller.BaseRes
 	Data *WorkflowResV2 `json:"data"`
@@ -1240,6 +1556,21 @@ func convertWorkflowStepToRes(step *model.WorkflowStep) *WorkflowStepResV2 {
 			stepRes.Users = append(stepRes.Users, user.Name)
 		}
 	}
+	if step.Template.Typ == model.WorkflowStepTypeSQLReview {
+		stepRes.ApproveMode = step.Template.GetApproveMode()
+		stepRes.RequiredApprovalCount = step.RequiredApprovals()
+	}
+	for _, approval := range step.Approvals {
+		approvalRes := &WorkflowStepApprovalResV2{
+			OperationTime: approval.OperateAt,
+			State:         approval.State,
+			Reason:        approval.Reason,
+		}
+		if approval.User != nil {
+			approvalRes.OperationUser = utils.AddDelTag(approval.User.DeletedAt, approval.User.Name)
+		}
+		stepRes.Approvals = append(stepRes.Approvals, approvalRes)
+	}
 	return stepRes
 }
 
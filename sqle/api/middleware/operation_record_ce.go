@@ -4,13 +4,226 @@
 package middleware
 
 import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/actiontech/sqle/sqle/api/controller"
+	"github.com/actiontech/sqle/sqle/log"
+	"github.com/actiontech/sqle/sqle/model"
+
 	"github.com/labstack/echo/v4"
 )
 
+// maxRecordedBodySize limits the size of request and response body which are read to generate
+// the operation record, the larger body is not parsed.
+const maxRecordedBodySize = 1 << 20
+
+// operationRecordTypes maps the resource in the path of API to the operation type.
+var operationRecordTypes = map[string]string{
+	"projects":            model.OperationRecordTypeProject,
+	"members":             model.OperationRecordTypeMember,
+	"member_groups":       model.OperationRecordTypeMember,
+	"users":               model.OperationRecordTypeUser,
+	"user":                model.OperationRecordTypeUser,
+	"logout":              model.OperationRecordTypeUser,
+	"user_groups":         model.OperationRecordTypeUserGroup,
+	"roles":               model.OperationRecordTypeRole,
+	"instances":           model.OperationRecordTypeInstance,
+	"instance_connection": model.OperationRecordTypeInstance,
+	"sync_instances":      model.OperationRecordTypeInstance,
+	"rule_templates":      model.OperationRecordTypeRuleTemplate,
+	"custom_rules":        model.OperationRecordTypeRuleTemplate,
+	"rule_knowledge":      model.OperationRecordTypeRuleTemplate,
+	"workflows":           model.OperationRecordTypeWorkflow,
+	"workflow_template":   model.OperationRecordTypeWorkflow,
+	"audit_plans":         model.OperationRecordTypeAuditPlan,
+	"audit_whitelist":     model.OperationRecordTypeAuditWhitelist,
+	"tasks":               model.OperationRecordTypeSQLAudit,
+	"task_groups":         model.OperationRecordTypeSQLAudit,
+	"sql_audit":           model.OperationRecordTypeSQLAudit,
+	"audit_files":         model.OperationRecordTypeSQLAudit,
+	"sql_audit_records":   model.OperationRecordTypeSQLAudit,
+	"sql_manages":         model.OperationRecordTypeSQLManage,
+	"configurations":      model.OperationRecordTypeConfiguration,
+}
+
+// operationRecordVerbs are the actions which are the last segment of the path.
+var operationRecordVerbs = map[string]struct{}{}
+
+func init() {
+	for action := range model.OperationRecordActionDesc {
+		operationRecordVerbs[action] = struct{}{}
+	}
+}
+
+// operationRecordBodyKeys are the fields of request body which describe the operated object,
+// the other fields (e.g. password) are never recorded.
+var operationRecordBodyKeys = []string{
+	"name", "project_name", "user_name", "user_group_name", "role_name", "instance_name",
+	"rule_template_name", "audit_plan_name", "workflow_subject", "desc", "db_type",
+}
+
+// parseOperation returns the operation type and action of the API by its route path, e.g.
+// "/v1/projects/:project_name/workflows/:workflow_id/cancel" is the action "cancel" of "workflow".
+func parseOperation(method, path string) (typ, action string) {
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	if len(segments) > 0 && (segments[0] == "v1" || segments[0] == "v2") {
+		segments = segments[1:]
+	}
+	typ = model.OperationRecordTypeOther
+	if len(segments) > 2 && segments[0] == "projects" && segments[1] == ":project_name" {
+		// e.g. "/v1/projects/:project_name/archive" operates the project itself
+		typ = model.OperationRecordTypeProject
+		segments = segments[2:]
+	}
+	if len(segments) > 0 {
+		if t, ok := operationRecordTypes[segments[0]]; ok {
+			typ = t
+		}
+	}
+
+	if len(segments) > 0 {
+		if _, ok := operationRecordVerbs[segments[len(segments)-1]]; ok {
+			return typ, segments[len(segments)-1]
+		}
+	}
+	switch method {
+	case http.MethodPost:
+		action = model.OperationRecordActionCreate
+	case http.MethodDelete:
+		action = model.OperationRecordActionDelete
+	default:
+		action = model.OperationRecordActionUpdate
+	}
+	return typ, action
+}
+
+// operationContent describes the operated object by the path params and some fields of request body.
+func operationContent(c echo.Context, body map[string]interface{}) string {
+	parts := []string{}
+	for i, name := range c.ParamNames() {
+		if name == "project_name" || i >= len(c.ParamValues()) {
+			continue
+		}
+		parts = append(parts, fmt.Sprintf("%s: %s", name, c.ParamValues()[i]))
+	}
+	for _, key := range operationRecordBodyKeys {
+		if v, ok := body[key].(string); ok && v != "" {
+			parts = append(parts, fmt.Sprintf("%s: %s", key, v))
+		}
+	}
+	if len(parts) == 0 {
+		return c.Request().URL.Path
+	}
+	return strings.Join(parts, ", ")
+}
+
+// readJSONBody reads the request body and restores it for the handler.
+func readJSONBody(req *http.Request) map[string]interface{} {
+	if req.Body == nil || req.ContentLength > maxRecordedBodySize ||
+		!strings.HasPrefix(req.Header.Get(echo.HeaderContentType), echo.MIMEApplicationJSON) {
+		return nil
+	}
+	b, err := ioutil.ReadAll(req.Body)
+	req.Body = ioutil.NopCloser(bytes.NewReader(b))
+	if err != nil {
+		return nil
+	}
+	body := map[string]interface{}{}
+	if err := json.Unmarshal(b, &body); err != nil {
+		return nil
+	}
+	return body
+}
+
+// responseRecorder keeps the beginning of the response body to check the error code, most of
+// the API respond the error in the body with http status 200.
+type responseRecorder struct {
+	http.ResponseWriter
+	body bytes.Buffer
+}
+
+func (r *responseRecorder) Write(b []byte) (int, error) {
+	if remain := maxRecordedBodySize - r.body.Len(); remain > 0 {
+		if len(b) > remain {
+			r.body.Write(b[:remain])
+		} else {
+			r.body.Write(b)
+		}
+	}
+	return r.ResponseWriter.Write(b)
+}
+
+func (r *responseRecorder) Flush() {
+	if f, ok := r.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// operationResult returns the status and the error message of the operation.
+func operationResult(c echo.Context, err error, recorder *responseRecorder) (string, string) {
+	if err != nil {
+		return model.OperationRecordStatusFailed, err.Error()
+	}
+	if c.Response().Status >= http.StatusBadRequest {
+		return model.OperationRecordStatusFailed, http.StatusText(c.Response().Status)
+	}
+	res := controller.BaseRes{}
+	if json.Unmarshal(recorder.body.Bytes(), &res) == nil && res.Code != 0 {
+		return model.OperationRecordStatusFailed, res.Message
+	}
+	return model.OperationRecordStatusSucceeded, ""
+}
+
+// OperationLogRecord records the mutating API calls, the records are cleaned by CleanJob after
+// the expired hours of system variable.
 func OperationLogRecord() echo.MiddlewareFunc {
 	return func(next echo.HandlerFunc) echo.HandlerFunc {
 		return func(c echo.Context) (err error) {
-			return next(c)
+			req := c.Request()
+			switch req.Method {
+			case http.MethodPost, http.MethodPatch, http.MethodPut, http.MethodDelete:
+			default:
+				return next(c)
+			}
+
+			body := readJSONBody(req)
+			recorder := &responseRecorder{ResponseWriter: c.Response().Writer}
+			c.Response().Writer = recorder
+			defer func() {
+				c.Response().Writer = recorder.ResponseWriter
+			}()
+
+			err = next(c)
+
+			typ, action := parseOperation(req.Method, c.Path())
+			projectName := c.Param("project_name")
+			if typ == model.OperationRecordTypeProject && action == model.OperationRecordActionCreate {
+				if name, ok := body["name"].(string); ok {
+					projectName = name
+				}
+			}
+			status, errMsg := operationResult(c, err, recorder)
+			record := &model.OperationRecord{
+				OperationTime:         time.Now(),
+				OperationUserName:     controller.GetUserName(c),
+				OperationReqIP:        c.RealIP(),
+				OperationTypeName:     typ,
+				OperationAction:       action,
+				OperationContent:      operationContent(c, body),
+				OperationProjectName:  projectName,
+				OperationStatus:       status,
+				OperationErrorMessage: errMsg,
+			}
+			if saveErr := model.GetStorage().Save(record); saveErr != nil {
+				log.NewEntry().Errorf("save operation record failed, err: %v", saveErr)
+			}
+			return err
 		}
 	}
 }
@@ -0,0 +1,103 @@
+//go:build !enterprise
+// +build !enterprise
+
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
+	"github.com/actiontech/sqle/sqle/api/controller"
+	"github.com/actiontech/sqle/sqle/errors"
+	"github.com/actiontech/sqle/sqle/model"
+	"github.com/golang-jwt/jwt"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseOperation(t *testing.T) {
+	cases := []struct {
+		method, path, typ, action string
+	}{
+		{http.MethodPost, "/v1/projects", model.OperationRecordTypeProject, model.OperationRecordActionCreate},
+		{http.MethodDelete, "/v1/projects/:project_name/", model.OperationRecordTypeProject, model.OperationRecordActionDelete},
+		{http.MethodPost, "/v1/projects/:project_name/archive", model.OperationRecordTypeProject, model.OperationRecordActionArchive},
+		{http.MethodPost, "/v2/projects/:project_name/workflows/:workflow_id/steps/:workflow_step_id/approve", model.OperationRecordTypeWorkflow, model.OperationRecordActionApprove},
+		{http.MethodPost, "/v1/projects/:project_name/workflows/:workflow_id/tasks/:task_id/execute", model.OperationRecordTypeWorkflow, model.OperationRecordActionExecute},
+		{http.MethodPut, "/v2/projects/:project_name/workflows/:workflow_id/tasks/:task_id/schedule", model.OperationRecordTypeWorkflow, model.OperationRecordActionSchedule},
+		{http.MethodPatch, "/v1/rule_templates/:rule_template_name/", model.OperationRecordTypeRuleTemplate, model.OperationRecordActionUpdate},
+		{http.MethodPost, "/v1/projects/:project_name/rule_templates/:rule_template_name/clone", model.OperationRecordTypeRuleTemplate, model.OperationRecordActionClone},
+		{http.MethodPatch, "/v1/configurations/ldap", model.OperationRecordTypeConfiguration, model.OperationRecordActionUpdate},
+		{http.MethodPost, "/v1/configurations/smtp/test", model.OperationRecordTypeConfiguration, model.OperationRecordActionTest},
+		{http.MethodPut, "/v1/user/password", model.OperationRecordTypeUser, model.OperationRecordActionPassword},
+		{http.MethodPost, "/v1/logout", model.OperationRecordTypeUser, model.OperationRecordActionLogout},
+		{http.MethodPost, "/v1/unknown", model.OperationRecordTypeOther, model.OperationRecordActionCreate},
+	}
+	for _, c := range cases {
+		typ, action := parseOperation(c.method, c.path)
+		assert.Equal(t, c.typ, typ, c.path)
+		assert.Equal(t, c.action, action, c.path)
+	}
+}
+
+func TestOperationLogRecord(t *testing.T) {
+	e := echo.New()
+	mw := OperationLogRecord()
+
+	newContext := func(method, body string) (echo.Context, *httptest.ResponseRecorder) {
+		req := httptest.NewRequest(method, "/v1/projects/p1/workflows/1/cancel", strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		req.Header.Set(echo.HeaderXRealIP, "10.0.0.1")
+		res := httptest.NewRecorder()
+		ctx := e.NewContext(req, res)
+		ctx.SetPath("/v1/projects/:project_name/workflows/:workflow_id/cancel")
+		ctx.SetParamNames("project_name", "workflow_id")
+		ctx.SetParamValues("p1", "1")
+		ctx.Set("user", &jwt.Token{Claims: jwt.MapClaims{"name": "admin"}})
+		return ctx, res
+	}
+
+	{ // GET is not recorded
+		ctx, _ := newContext(http.MethodGet, "")
+		err := mw(func(c echo.Context) error {
+			return c.JSON(http.StatusOK, controller.NewBaseReq(nil))
+		})(ctx)
+		assert.NoError(t, err)
+	}
+
+	for _, handlerErr := range []error{nil, errors.New(errors.DataNotExist, fmt.Errorf("workflow is not exist"))} {
+		mockDB, mock, err := sqlmock.New()
+		assert.NoError(t, err)
+		model.InitMockStorage(mockDB)
+
+		status, errMsg := model.OperationRecordStatusSucceeded, ""
+		if handlerErr != nil {
+			status, errMsg = model.OperationRecordStatusFailed, handlerErr.Error()
+		}
+		mock.ExpectBegin()
+		mock.ExpectExec(regexp.QuoteMeta("INSERT INTO `operation_records`")).
+			WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), nil, sqlmock.AnyArg(), "admin", "10.0.0.1",
+				model.OperationRecordTypeWorkflow, model.OperationRecordActionCancel, "workflow_id: 1, desc: closed",
+				"p1", status, errMsg).
+			WillReturnResult(sqlmock.NewResult(1, 1))
+		mock.ExpectCommit()
+
+		ctx, res := newContext(http.MethodPost, `{"desc":"closed","password":"123"}`)
+		err = mw(func(c echo.Context) error {
+			req := map[string]string{}
+			assert.NoError(t, c.Bind(&req))
+			assert.Equal(t, "closed", req["desc"])
+			return controller.JSONBaseErrorReq(c, handlerErr)
+		})(ctx)
+		assert.NoError(t, err)
+		assert.Contains(t, res.Body.String(), `"code"`)
+
+		mockDB.Close()
+		assert.NoError(t, mock.ExpectationsWereMet())
+	}
+}
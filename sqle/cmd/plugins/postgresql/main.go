@@ -0,0 +1,11 @@
+package main
+
+import (
+	"github.com/actiontech/sqle/sqle/driver/postgresql"
+	driverV2 "github.com/actiontech/sqle/sqle/driver/v2"
+)
+
+// The PostgreSQL plugin should be put into the plugin path of SQLE, see "plugin_path" in config.
+func main() {
+	driverV2.ServePlugin(*postgresql.Metas(), postgresql.NewDriver)
+}
@@ -21,6 +21,7 @@ var (
 	excludeUsers   string
 	includeSchemas string
 	excludeSchemas string
+	fromBeginning  bool
 
 	slowlogCmd = &cobra.Command{
 		Use:   "slowquery",
@@ -33,6 +34,7 @@ var (
 				ExcludeUsers:   excludeUsers,
 				IncludeSchemas: includeSchemas,
 				ExcludeSchemas: excludeSchemas,
+				FromBeginning:  fromBeginning,
 			}
 			log := logrus.WithField("scanner", "slowquery")
 			client := scanner.NewSQLEClient(time.Second*time.Duration(rootCmdFlags.timeout), rootCmdFlags.host, rootCmdFlags.port).WithToken(rootCmdFlags.token).WithProject(rootCmdFlags.project)
@@ -57,6 +59,7 @@ func init() {
 	slowlogCmd.Flags().StringVarP(&excludeUsers, "exclude-user-list", "", "", "exclude mysql user list, split by \",\"")
 	slowlogCmd.Flags().StringVarP(&includeSchemas, "include-schema-list", "", "", "include mysql schema list, split by \",\"")
 	slowlogCmd.Flags().StringVarP(&excludeSchemas, "exclude-schema-list", "", "", "exclude mysql schema list, split by \",\"")
+	slowlogCmd.Flags().BoolVarP(&fromBeginning, "from-beginning", "", false, "collect the slow log written before scanner started")
 	_ = slowlogCmd.MarkFlagRequired("log-file")
 	rootCmd.AddCommand(slowlogCmd)
 }
@@ -0,0 +1,169 @@
+package slowquery
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/actiontech/sqle/sqle/cmd/scannerd/scanners"
+	"github.com/actiontech/sqle/sqle/driver/mysql/util"
+)
+
+// slowLogParser parses MySQL slow log line by line, a slow log entry looks like:
+//
+//	# Time: 2023-09-12T02:48:01.317880Z
+//	# User@Host: root[root] @ localhost []  Id:     8
+//	# Query_time: 2.000201  Lock_time: 0.000000 Rows_sent: 1  Rows_examined: 0
+//	use db1;
+//	SET timestamp=1694486881;
+//	select sleep(2);
+//
+// The parser is not goroutine safe.
+type slowLogParser struct {
+	// schema is the last database switched by "use db;", MySQL only writes the "use db;" line
+	// when the database is different from the last logged one, so it is kept across entries.
+	schema string
+
+	entry *slowLogEntry
+}
+
+type slowLogEntry struct {
+	queryTime float64
+	queryAt   time.Time
+	user      string
+	lines     []string
+}
+
+func newSlowLogParser() *slowLogParser {
+	return &slowLogParser{entry: &slowLogEntry{}}
+}
+
+// Parse parses one line of slow log, it returns the SQL when an entry is completed.
+func (p *slowLogParser) Parse(line string) (*scanners.SQL, bool) {
+	line = strings.TrimRight(line, "\r\n")
+	if line == "" {
+		return nil, false
+	}
+
+	switch {
+	case strings.HasPrefix(line, "# Time:"):
+		sql, ok := p.Flush()
+		p.entry.queryAt = parseSlowLogTime(strings.TrimSpace(strings.TrimPrefix(line, "# Time:")))
+		return sql, ok
+	case strings.HasPrefix(line, "# User@Host:"):
+		// "# Time:" is not written when the time is the same as the last entry on MySQL 5.6,
+		// so the "# User@Host:" line is also the beginning of an entry if the last entry has query.
+		var sql *scanners.SQL
+		var ok bool
+		if len(p.entry.lines) > 0 {
+			sql, ok = p.Flush()
+		}
+		p.entry.user = parseSlowLogUser(strings.TrimPrefix(line, "# User@Host:"))
+		return sql, ok
+	case strings.HasPrefix(line, "# Query_time:"):
+		fields := strings.Fields(strings.TrimPrefix(line, "#"))
+		if len(fields) >= 2 {
+			p.entry.queryTime, _ = strconv.ParseFloat(fields[1], 64)
+		}
+		return nil, false
+	case strings.HasPrefix(line, "# Schema:"):
+		// Percona Server writes the schema of the entry on log_slow_verbosity=full.
+		fields := strings.Fields(strings.TrimPrefix(line, "# Schema:"))
+		if len(fields) > 0 {
+			p.schema = fields[0]
+		}
+		return nil, false
+	case strings.HasPrefix(line, "#"):
+		return nil, false
+	case isSlowLogFileHeader(line):
+		return nil, false
+	}
+
+	lower := strings.ToLower(line)
+	if len(p.entry.lines) == 0 {
+		switch {
+		case strings.HasPrefix(lower, "use "):
+			p.schema = strings.Trim(strings.TrimSuffix(strings.TrimSpace(line[4:]), ";"), "`")
+			return nil, false
+		case strings.HasPrefix(lower, "set timestamp="):
+			ts, err := strconv.ParseInt(strings.TrimSuffix(line[len("set timestamp="):], ";"), 10, 64)
+			if err == nil && p.entry.queryAt.IsZero() {
+				p.entry.queryAt = time.Unix(ts, 0)
+			}
+			return nil, false
+		}
+	}
+
+	p.entry.lines = append(p.entry.lines, line)
+	if strings.HasSuffix(line, ";") {
+		return p.Flush()
+	}
+	return nil, false
+}
+
+// Flush returns the SQL of the entry being parsed, and starts a new entry.
+func (p *slowLogParser) Flush() (*scanners.SQL, bool) {
+	entry := p.entry
+	p.entry = &slowLogEntry{}
+	if len(entry.lines) == 0 {
+		return nil, false
+	}
+
+	query := strings.TrimSpace(strings.TrimSuffix(strings.Join(entry.lines, "\n"), ";"))
+	if query == "" {
+		return nil, false
+	}
+	fingerprint, err := util.Fingerprint(query, true)
+	if err != nil {
+		// the fingerprint will be generated by SQLE if it is empty.
+		fingerprint = ""
+	}
+	queryAt := entry.queryAt
+	if queryAt.IsZero() {
+		queryAt = time.Now()
+	}
+	return &scanners.SQL{
+		Fingerprint: fingerprint,
+		RawText:     query,
+		Counter:     1,
+		Schema:      p.schema,
+		QueryTime:   entry.queryTime,
+		QueryAt:     queryAt,
+		DBUser:      entry.user,
+	}, true
+}
+
+// parseSlowLogTime parses the time of "# Time:", the format is "2023-09-12T02:48:01.317880Z" on MySQL 5.7+,
+// and "230912  2:48:01" on MySQL 5.6 and before.
+func parseSlowLogTime(s string) time.Time {
+	if t, err := time.Parse(time.RFC3339Nano, s); err == nil {
+		return t
+	}
+	if t, err := time.ParseInLocation("060102 15:04:05", strings.Join(strings.Fields(s), " "), time.Local); err == nil {
+		return t
+	}
+	return time.Time{}
+}
+
+// parseSlowLogUser parses the user of "# User@Host:", e.g. "root[root] @ localhost []  Id:     8".
+func parseSlowLogUser(s string) string {
+	s = strings.TrimSpace(s)
+	if i := strings.Index(s, "["); i >= 0 {
+		return strings.TrimSpace(s[:i])
+	}
+	if i := strings.Index(s, "@"); i >= 0 {
+		return strings.TrimSpace(s[:i])
+	}
+	return s
+}
+
+// isSlowLogFileHeader returns true on the lines written by mysqld when the slow log file is opened, e.g.
+//
+//	/usr/sbin/mysqld, Version: 5.7.40-log (MySQL Community Server (GPL)). started with:
+//	Tcp port: 3306  Unix socket: /var/run/mysqld/mysqld.sock
+//	Time                 Id Command    Argument
+func isSlowLogFileHeader(line string) bool {
+	return strings.Contains(line, ", Version: ") && strings.HasSuffix(line, "started with:") ||
+		strings.HasPrefix(line, "Tcp port: ") ||
+		strings.HasPrefix(line, "Time ") && strings.Contains(line, "Id Command") && strings.HasSuffix(line, "Argument")
+}
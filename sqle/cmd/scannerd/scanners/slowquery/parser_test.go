@@ -0,0 +1,75 @@
+package slowquery
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/actiontech/sqle/sqle/cmd/scannerd/scanners"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func parseSlowLog(t *testing.T, content string) []*scanners.SQL {
+	p := newSlowLogParser()
+	sqls := []*scanners.SQL{}
+	for _, line := range strings.SplitAfter(content, "\n") {
+		if sql, ok := p.Parse(line); ok {
+			sqls = append(sqls, sql)
+		}
+	}
+	if sql, ok := p.Flush(); ok {
+		sqls = append(sqls, sql)
+	}
+	return sqls
+}
+
+func TestSlowLogParser(t *testing.T) {
+	content, err := readTestData("slow.log")
+	assert.NoError(t, err)
+
+	sqls := parseSlowLog(t, content)
+	assert.Len(t, sqls, 3)
+
+	assert.Equal(t, "select sleep(2)", sqls[0].RawText)
+	assert.Equal(t, "SELECT SLEEP(?)", sqls[0].Fingerprint)
+	assert.Equal(t, "db1", sqls[0].Schema)
+	assert.Equal(t, "root", sqls[0].DBUser)
+	assert.Equal(t, 2.000201, sqls[0].QueryTime)
+	assert.Equal(t, time.Date(2023, 9, 12, 2, 48, 1, 317880000, time.UTC), sqls[0].QueryAt)
+
+	assert.Equal(t, "update t1\nset c1 = 1\nwhere c2 = 'a'", sqls[1].RawText)
+	assert.Equal(t, "UPDATE `t1` SET `c1`=? WHERE `c2`=?", sqls[1].Fingerprint)
+	assert.Equal(t, "db1", sqls[1].Schema)
+	assert.Equal(t, "app", sqls[1].DBUser)
+	assert.Equal(t, 1.5, sqls[1].QueryTime)
+
+	assert.Equal(t, "delete from t2 where id > 100", sqls[2].RawText)
+	assert.Equal(t, "db2", sqls[2].Schema)
+	assert.Equal(t, 3.0, sqls[2].QueryTime)
+	assert.Equal(t, time.Date(2023, 9, 12, 2, 51, 1, 0, time.Local), sqls[2].QueryAt)
+}
+
+func TestSlowLogParser_WithoutSemicolon(t *testing.T) {
+	sqls := parseSlowLog(t, `# User@Host: root[root] @ localhost []  Id:     8
+# Query_time: 1.000000  Lock_time: 0.000000 Rows_sent: 1  Rows_examined: 0
+SET timestamp=1694486881;
+select 1
+# User@Host: root[root] @ localhost []  Id:     8
+# Query_time: 1.000000  Lock_time: 0.000000 Rows_sent: 1  Rows_examined: 0
+SET timestamp=1694486881;
+select 2
+`)
+	assert.Len(t, sqls, 2)
+	assert.Equal(t, "select 1", sqls[0].RawText)
+	assert.Equal(t, time.Unix(1694486881, 0), sqls[0].QueryAt)
+	assert.Equal(t, "select 2", sqls[1].RawText)
+}
+
+func TestIsIncluded(t *testing.T) {
+	assert.True(t, isIncluded("root", splitList(""), splitList("")))
+	assert.True(t, isIncluded("root", splitList("root, app"), splitList("")))
+	assert.False(t, isIncluded("dba", splitList("root,app"), splitList("")))
+	assert.False(t, isIncluded("root", splitList(""), splitList("root")))
+	assert.False(t, isIncluded("root", splitList("root"), splitList("root")))
+}
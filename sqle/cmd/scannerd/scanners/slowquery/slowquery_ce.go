@@ -5,16 +5,33 @@ package slowquery
 
 import (
 	"context"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/actiontech/sqle/sqle/cmd/scannerd/scanners"
-	"github.com/actiontech/sqle/sqle/errors"
 	"github.com/actiontech/sqle/sqle/pkg/scanner"
+	"github.com/actiontech/sqle/sqle/utils"
+
 	"github.com/sirupsen/logrus"
 )
 
-var errSlowQueryNotImplemented = errors.NewNotImplemented("SlowQuery Scanner")
+const defaultPollInterval = time.Second
+
+type SlowQuery struct {
+	l *logrus.Entry
+	c *scanner.Client
+
+	sqlCh chan scanners.SQL
 
-type SlowQuery struct{}
+	apName         string
+	logFilePath    string
+	fromBeginning  bool
+	includeUsers   map[string]struct{}
+	excludeUsers   map[string]struct{}
+	includeSchemas map[string]struct{}
+	excludeSchemas map[string]struct{}
+}
 
 type Params struct {
 	LogFilePath    string
@@ -23,20 +40,128 @@ type Params struct {
 	ExcludeUsers   string
 	IncludeSchemas string
 	ExcludeSchemas string
+	// FromBeginning indicates whether to collect the slow log written before the scanner started.
+	FromBeginning bool
 }
 
 func New(params *Params, l *logrus.Entry, c *scanner.Client) (*SlowQuery, error) {
-	return &SlowQuery{}, errSlowQueryNotImplemented
+	return &SlowQuery{
+		l:              l,
+		c:              c,
+		sqlCh:          make(chan scanners.SQL, 10240),
+		apName:         params.APName,
+		logFilePath:    params.LogFilePath,
+		fromBeginning:  params.FromBeginning,
+		includeUsers:   splitList(params.IncludeUsers),
+		excludeUsers:   splitList(params.ExcludeUsers),
+		includeSchemas: splitList(params.IncludeSchemas),
+		excludeSchemas: splitList(params.ExcludeSchemas),
+	}, nil
 }
 
-func (s *SlowQuery) Run(ctx context.Context) error {
-	return errSlowQueryNotImplemented
+func (sq *SlowQuery) Run(ctx context.Context) error {
+	defer close(sq.sqlCh)
+
+	parser := newSlowLogParser()
+	t := newTailer(sq.l, sq.logFilePath, defaultPollInterval)
+	return t.Run(ctx, sq.fromBeginning, func(line string) {
+		sql, ok := parser.Parse(line)
+		if !ok || !sq.isMatched(sql) {
+			return
+		}
+		select {
+		case sq.sqlCh <- *sql:
+		case <-ctx.Done():
+		}
+	})
 }
 
 func (sq *SlowQuery) SQLs() <-chan scanners.SQL {
-	return nil
+	return sq.sqlCh
 }
 
+// Upload merges the SQLs by fingerprint and upload them with the statistics of query time.
 func (sq *SlowQuery) Upload(ctx context.Context, sqls []scanners.SQL) error {
-	return errSlowQueryNotImplemented
+	type stat struct {
+		sql          scanners.SQL
+		counter      int
+		sumQueryTime float64
+		maxQueryTime float64
+		firstQueryAt time.Time
+		lastQueryAt  time.Time
+	}
+	stats := map[string]*stat{}
+	keys := []string{}
+	for _, sql := range sqls {
+		// SQLE generates the fingerprint for the SQL which has no fingerprint.
+		key := sql.Fingerprint
+		if key == "" {
+			key = sql.RawText
+		}
+		st, ok := stats[key]
+		if !ok {
+			st = &stat{firstQueryAt: sql.QueryAt}
+			stats[key] = st
+			keys = append(keys, key)
+		}
+		st.sql = sql
+		st.counter++
+		st.sumQueryTime += sql.QueryTime
+		if sql.QueryTime > st.maxQueryTime {
+			st.maxQueryTime = sql.QueryTime
+		}
+		if sql.QueryAt.Before(st.firstQueryAt) {
+			st.firstQueryAt = sql.QueryAt
+		}
+		if sql.QueryAt.After(st.lastQueryAt) {
+			st.lastQueryAt = sql.QueryAt
+		}
+	}
+
+	reqBody := make([]*scanner.AuditPlanSQLReq, 0, len(keys))
+	for _, key := range keys {
+		st := stats[key]
+		avg := utils.Round(st.sumQueryTime/float64(st.counter), 6)
+		max := st.maxQueryTime
+		reqBody = append(reqBody, &scanner.AuditPlanSQLReq{
+			Fingerprint:          st.sql.Fingerprint,
+			Counter:              strconv.Itoa(st.counter),
+			LastReceiveText:      st.sql.RawText,
+			LastReceiveTimestamp: st.lastQueryAt.Format(time.RFC3339),
+			Schema:               st.sql.Schema,
+			QueryTimeAvg:         &avg,
+			QueryTimeMax:         &max,
+			FirstQueryAt:         st.firstQueryAt,
+			DBUser:               st.sql.DBUser,
+		})
+	}
+	return sq.c.UploadReq(scanner.PartialUpload, sq.apName, reqBody)
+}
+
+func (sq *SlowQuery) isMatched(sql *scanners.SQL) bool {
+	return isIncluded(sql.DBUser, sq.includeUsers, sq.excludeUsers) &&
+		isIncluded(sql.Schema, sq.includeSchemas, sq.excludeSchemas)
+}
+
+// isIncluded returns true if the value is not in exclude list, and in include list when the include list is not empty.
+func isIncluded(value string, include, exclude map[string]struct{}) bool {
+	if _, ok := exclude[value]; ok {
+		return false
+	}
+	if len(include) == 0 {
+		return true
+	}
+	_, ok := include[value]
+	return ok
+}
+
+func splitList(s string) map[string]struct{} {
+	m := map[string]struct{}{}
+	for _, v := range strings.Split(s, ",") {
+		v = strings.TrimSpace(v)
+		if v != "" {
+			m[v] = struct{}{}
+		}
+	}
+	return m
 }
@@ -0,0 +1,138 @@
+package slowquery
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// tailer follows the file like "tail -F", it reopens the file from the beginning
+// when the file is rotated (renamed, removed and recreated) or truncated.
+type tailer struct {
+	l            *logrus.Entry
+	path         string
+	pollInterval time.Duration
+
+	file   *os.File
+	info   os.FileInfo
+	reader *bufio.Reader
+	offset int64
+	// partial is the content of the last line which has no line feed yet.
+	partial string
+}
+
+func newTailer(l *logrus.Entry, path string, pollInterval time.Duration) *tailer {
+	return &tailer{
+		l:            l,
+		path:         filepath.Clean(path),
+		pollInterval: pollInterval,
+	}
+}
+
+// Run reads the lines of file until ctx is canceled, fromBeginning indicates whether the lines
+// existing before the tailer started should be read.
+func (t *tailer) Run(ctx context.Context, fromBeginning bool, lineFn func(line string)) error {
+	if err := t.open(fromBeginning); err != nil {
+		return err
+	}
+	defer t.close()
+
+	ticker := time.NewTicker(t.pollInterval)
+	defer ticker.Stop()
+	for {
+		if err := t.readLines(lineFn); err != nil {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+		if err := t.checkRotation(lineFn); err != nil {
+			return err
+		}
+	}
+}
+
+func (t *tailer) open(fromBeginning bool) error {
+	f, err := os.Open(t.path)
+	if err != nil {
+		return err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		_ = f.Close()
+		return err
+	}
+	var offset int64
+	if !fromBeginning {
+		offset, err = f.Seek(0, io.SeekEnd)
+		if err != nil {
+			_ = f.Close()
+			return err
+		}
+	}
+	t.file = f
+	t.info = info
+	t.offset = offset
+	t.reader = bufio.NewReader(f)
+	t.partial = ""
+	return nil
+}
+
+func (t *tailer) close() {
+	if t.file != nil {
+		_ = t.file.Close()
+		t.file = nil
+	}
+}
+
+func (t *tailer) readLines(lineFn func(line string)) error {
+	for {
+		s, err := t.reader.ReadString('\n')
+		t.offset += int64(len(s))
+		if err == io.EOF {
+			t.partial += s
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		lineFn(t.partial + s)
+		t.partial = ""
+	}
+}
+
+func (t *tailer) checkRotation(lineFn func(line string)) error {
+	info, err := os.Stat(t.path)
+	if os.IsNotExist(err) {
+		// the file is renamed or removed, wait for the new one.
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case !os.SameFile(t.info, info):
+		// drain the lines written to the old file before it was rotated.
+		if err := t.readLines(lineFn); err != nil {
+			return err
+		}
+		if t.partial != "" {
+			lineFn(t.partial)
+		}
+		t.l.Infof("file %s is rotated, reopen it", t.path)
+	case info.Size() < t.offset:
+		t.l.Infof("file %s is truncated, reopen it", t.path)
+	default:
+		return nil
+	}
+	t.close()
+	return t.open(true)
+}
@@ -0,0 +1,68 @@
+package slowquery
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/actiontech/sqle/sqle/cmd/scannerd/scanners/common"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+func readTestData(name string) (string, error) {
+	return common.ReadFileContent(filepath.Join("testdata", name))
+}
+
+func TestTailer(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "slow.log")
+	assert.NoError(t, os.WriteFile(path, []byte("old line\n"), 0644))
+
+	var mutex sync.Mutex
+	lines := []string{}
+	getLines := func() []string {
+		mutex.Lock()
+		defer mutex.Unlock()
+		return append([]string{}, lines...)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	doneCh := make(chan error)
+	go func() {
+		tl := newTailer(logrus.NewEntry(logrus.New()), path, 10*time.Millisecond)
+		doneCh <- tl.Run(ctx, false, func(line string) {
+			mutex.Lock()
+			defer mutex.Unlock()
+			lines = append(lines, line)
+		})
+	}()
+	time.Sleep(50 * time.Millisecond)
+
+	appendFile := func(content string) {
+		f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY|os.O_CREATE, 0644)
+		assert.NoError(t, err)
+		_, err = f.WriteString(content)
+		assert.NoError(t, err)
+		assert.NoError(t, f.Close())
+	}
+
+	// the partial line is read after the line feed is written.
+	appendFile("line 1\nline")
+	assert.Eventually(t, func() bool { return len(getLines()) == 1 }, time.Second, 10*time.Millisecond)
+	appendFile(" 2\n")
+	assert.Eventually(t, func() bool { return len(getLines()) == 2 }, time.Second, 10*time.Millisecond)
+
+	// rotate the file.
+	appendFile("line 3\n")
+	assert.NoError(t, os.Rename(path, path+".1"))
+	appendFile("line 4\n")
+	assert.Eventually(t, func() bool { return len(getLines()) == 4 }, time.Second, 10*time.Millisecond)
+
+	cancel()
+	assert.NoError(t, <-doneCh)
+	assert.Equal(t, []string{"line 1\n", "line 2\n", "line 3\n", "line 4\n"}, getLines())
+}
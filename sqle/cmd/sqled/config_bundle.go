@@ -0,0 +1,181 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	v1 "github.com/actiontech/sqle/sqle/api/controller/v1"
+
+	"github.com/spf13/cobra"
+)
+
+var configBundleFlags struct {
+	host         string
+	port         string
+	token        string
+	file         string
+	projectNames string
+	format       string
+}
+
+// configBundleCmd manages the config bundle of a running SQLE by API, so the SQLE
+// configuration can be stored in git and promoted from one SQLE to another.
+func configBundleCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "config",
+		Short: "export, plan and apply config bundle of SQLE",
+	}
+	cmd.PersistentFlags().StringVarP(&configBundleFlags.host, "host", "H", "127.0.0.1", "sqle host")
+	cmd.PersistentFlags().StringVarP(&configBundleFlags.port, "port", "P", "10000", "sqle port")
+	cmd.PersistentFlags().StringVarP(&configBundleFlags.token, "token", "A", "", "sqle token of admin")
+	_ = cmd.MarkPersistentFlagRequired("token")
+
+	exportCmd := &cobra.Command{
+		Use:   "export",
+		Short: "export projects as config bundle",
+		Run: func(cmd *cobra.Command, args []string) {
+			exitIfError(exportConfigBundle())
+		},
+	}
+	exportCmd.Flags().StringVarP(&configBundleFlags.projectNames, "projects", "", "", "project names, separated by comma")
+	exportCmd.Flags().StringVarP(&configBundleFlags.format, "format", "", "yaml", "bundle format, yaml or json")
+	exportCmd.Flags().StringVarP(&configBundleFlags.file, "file", "f", "", "output file path, print to stdout if empty")
+	_ = exportCmd.MarkFlagRequired("projects")
+
+	planCmd := &cobra.Command{
+		Use:   "plan",
+		Short: "show the changes needed to apply config bundle",
+		Run: func(cmd *cobra.Command, args []string) {
+			exitIfError(sendConfigBundle("/v1/configurations/bundle/plan", "Plan"))
+		},
+	}
+	planCmd.Flags().StringVarP(&configBundleFlags.file, "file", "f", "", "config bundle file path")
+	_ = planCmd.MarkFlagRequired("file")
+
+	applyCmd := &cobra.Command{
+		Use:   "apply",
+		Short: "apply config bundle",
+		Run: func(cmd *cobra.Command, args []string) {
+			exitIfError(sendConfigBundle("/v1/configurations/bundle/apply", "Applied"))
+		},
+	}
+	applyCmd.Flags().StringVarP(&configBundleFlags.file, "file", "f", "", "config bundle file path")
+	_ = applyCmd.MarkFlagRequired("file")
+
+	cmd.AddCommand(exportCmd, planCmd, applyCmd)
+	return cmd
+}
+
+func exitIfError(err error) {
+	if err != nil {
+		fmt.Println(err.Error())
+		os.Exit(1)
+	}
+}
+
+func configBundleURL(uri string) string {
+	baseURL := fmt.Sprintf("%s:%v", configBundleFlags.host, configBundleFlags.port)
+	if !strings.HasPrefix(baseURL, "http://") && !strings.HasPrefix(baseURL, "https://") {
+		baseURL = "http://" + baseURL
+	}
+	return baseURL + uri
+}
+
+func doConfigBundleRequest(method, uri, contentType string, body io.Reader) ([]byte, error) {
+	req, err := http.NewRequest(method, configBundleURL(uri), body)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", configBundleFlags.token)
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	client := &http.Client{Timeout: 5 * time.Minute}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	data, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("request %s failed, status: %s, body: %s", uri, resp.Status, string(data))
+	}
+	return data, nil
+}
+
+func exportConfigBundle() error {
+	query := url.Values{}
+	query.Set("project_names", configBundleFlags.projectNames)
+	query.Set("format", configBundleFlags.format)
+	data, err := doConfigBundleRequest(http.MethodGet, "/v1/configurations/bundle/export?"+query.Encode(), "", nil)
+	if err != nil {
+		return err
+	}
+	// the error is returned as json instead of file
+	res := &v1.PlanConfigBundleResV1{}
+	if json.Unmarshal(data, res) == nil && res.Code != 0 {
+		return fmt.Errorf("export config bundle failed: %s", res.Message)
+	}
+	if configBundleFlags.file == "" {
+		fmt.Print(string(data))
+		return nil
+	}
+	return ioutil.WriteFile(configBundleFlags.file, data, 0644)
+}
+
+func sendConfigBundle(uri, title string) error {
+	content, err := ioutil.ReadFile(configBundleFlags.file)
+	if err != nil {
+		return fmt.Errorf("read config bundle file %s failed: %v", configBundleFlags.file, err)
+	}
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	part, err := writer.CreateFormFile("bundle_file", filepath.Base(configBundleFlags.file))
+	if err != nil {
+		return err
+	}
+	if _, err = part.Write(content); err != nil {
+		return err
+	}
+	if err = writer.Close(); err != nil {
+		return err
+	}
+
+	data, err := doConfigBundleRequest(http.MethodPost, uri, writer.FormDataContentType(), body)
+	if err != nil {
+		return err
+	}
+	res := &v1.PlanConfigBundleResV1{}
+	if err := json.Unmarshal(data, res); err != nil {
+		return err
+	}
+	if res.Code != 0 {
+		return fmt.Errorf("%s config bundle failed: %s", strings.ToLower(title), res.Message)
+	}
+
+	if len(res.Data) == 0 {
+		fmt.Println("No changes, the configuration matches the config bundle.")
+		return nil
+	}
+	fmt.Printf("%s %d change(s):\n", title, len(res.Data))
+	for _, change := range res.Data {
+		fmt.Printf("  %s %s %s/%s\n", change.Action, change.Kind, change.ProjectName, change.Name)
+		for _, detail := range change.Details {
+			fmt.Printf("      %s\n", detail)
+		}
+	}
+	return nil
+}
@@ -131,6 +131,141 @@ var doc = `{
                 }
             }
         },
+        "/v1/cluster/nodes": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "get nodes and leader of cluster",
+                "tags": [
+                    "cluster"
+                ],
+                "summary": "获取集群节点状态",
+                "operationId": "getClusterNodesV1",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v1.GetClusterNodesResV1"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/configurations/bundle/apply": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "apply the config bundle, create or update the resources in bundle, the resources absent from bundle are not changed",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "tags": [
+                    "configuration"
+                ],
+                "summary": "应用配置包",
+                "operationId": "applyConfigBundleV1",
+                "parameters": [
+                    {
+                        "type": "file",
+                        "description": "SQLE config bundle file, yaml or json",
+                        "name": "bundle_file",
+                        "in": "formData",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v1.ApplyConfigBundleResV1"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/configurations/bundle/export": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "export projects and their members, rule templates, workflow template, instances (without password), audit plans (without secret params) and sql whitelist as config bundle",
+                "tags": [
+                    "configuration"
+                ],
+                "summary": "导出配置包",
+                "operationId": "exportConfigBundleV1",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "project names, separated by comma",
+                        "name": "project_names",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "yaml",
+                            "json"
+                        ],
+                        "type": "string",
+                        "description": "bundle format",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "sqle config bundle file",
+                        "schema": {
+                            "type": "file"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/configurations/bundle/plan": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "compare the config bundle with current configuration, return the changes needed to apply the bundle",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "tags": [
+                    "configuration"
+                ],
+                "summary": "预览配置包的变更",
+                "operationId": "planConfigBundleV1",
+                "parameters": [
+                    {
+                        "type": "file",
+                        "description": "SQLE config bundle file, yaml or json",
+                        "name": "bundle_file",
+                        "in": "formData",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v1.PlanConfigBundleResV1"
+                        }
+                    }
+                }
+            }
+        },
         "/v1/configurations/ding_talk": {
             "get": {
                 "security": [
@@ -2490,6 +2625,52 @@ var doc = `{
                 }
             }
         },
+        "/v1/projects/{project_name}/audit_plans/{audit_plan_name}/reports/{audit_plan_report_id}/index_advices": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "get index advices of the SELECT statements in audit plan report, the advices are deduplicated and ordered by estimated benefit.\nThe advices are generated by the index optimization rule (optimize_index_enabled) when auditing, so the result is empty if the rule is not enabled in the rule template of audit plan.",
+                "tags": [
+                    "audit_plan"
+                ],
+                "summary": "获取指定扫描任务的SQL扫描记录的索引建议",
+                "operationId": "getAuditPlanReportIndexAdvicesV1",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "project name",
+                        "name": "project_name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "audit plan name",
+                        "name": "audit_plan_name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "audit plan report id",
+                        "name": "audit_plan_report_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v1.GetIndexAdvicesResV1"
+                        }
+                    }
+                }
+            }
+        },
         "/v1/projects/{project_name}/audit_plans/{audit_plan_name}/reports/{audit_plan_report_id}/sqls": {
             "get": {
                 "security": [
@@ -3461,6 +3642,24 @@ var doc = `{
                         "name": "schema_name",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "fuzzy search table name",
+                        "name": "fuzzy_search_table_name",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "page index, return all tables when page_size is not set",
+                        "name": "page_index",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "size of per page",
+                        "name": "page_size",
+                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -4895,6 +5094,16 @@ var doc = `{
                         "description": "status",
                         "name": "filter_status",
                         "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "csv",
+                            "xlsx"
+                        ],
+                        "type": "string",
+                        "description": "export format, default is csv",
+                        "name": "export_format",
+                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -5697,6 +5906,12 @@ var doc = `{
                         "name": "filter_subject",
                         "in": "query"
                     },
+                    {
+                        "type": "string",
+                        "description": "filter by workflow_id",
+                        "name": "filter_workflow_id",
+                        "in": "query"
+                    },
                     {
                         "type": "string",
                         "description": "fuzzy search by workflow description",
@@ -5760,6 +5975,16 @@ var doc = `{
                         "name": "filter_task_instance_name",
                         "in": "query"
                     },
+                    {
+                        "enum": [
+                            "csv",
+                            "xlsx"
+                        ],
+                        "type": "string",
+                        "description": "export format, default is csv",
+                        "name": "export_format",
+                        "in": "query"
+                    },
                     {
                         "type": "string",
                         "description": "project name",
@@ -5817,19 +6042,22 @@ var doc = `{
                 }
             }
         },
-        "/v1/projects/{project_name}/workflows/{workflow_id}/tasks/{task_id}/terminate": {
+        "/v1/projects/{project_name}/workflows/{workflow_id}/tasks/{task_id}/online_ddl/cut_over": {
             "post": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "execute one task on workflow",
+                "description": "trigger or postpone the cut-over of gh-ost migration of the executing task, the postpone is only available if the instance enables ghost_postpone_cut_over\nin cluster mode, the request of the migration running on other node is applied by that node within a few seconds",
+                "consumes": [
+                    "application/json"
+                ],
                 "tags": [
                     "workflow"
                 ],
-                "summary": "终止单个上线任务",
-                "operationId": "terminateSingleTaskByWorkflowV1",
+                "summary": "触发或推迟上线任务中 gh-ost 的切换表",
+                "operationId": "cutOverOnlineDDLByWorkflowV1",
                 "parameters": [
                     {
                         "type": "string",
@@ -5851,6 +6079,15 @@ var doc = `{
                         "name": "task_id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "cut-over request",
+                        "name": "instance",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/v1.OnlineDDLCutOverReqV1"
+                        }
                     }
                 ],
                 "responses": {
@@ -5863,25 +6100,24 @@ var doc = `{
                 }
             }
         },
-        "/v1/projects/{project_name}/workflows/{workflow_name}/": {
-            "get": {
+        "/v1/projects/{project_name}/workflows/{workflow_id}/tasks/{task_id}/pause": {
+            "post": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "get workflow detail",
+                "description": "pause one executing task on workflow, the SQL being executed is not interrupted, the pause takes effect at the following points:\n1. before the next chunk of the UPDATE/DELETE executed in batches;\n2. before the next non-DML SQL, e.g. DDL;\n3. before the next group of adjacent DMLs which are not executed in batches, the DMLs of the group are executed in one transaction, so the task can not be paused between them.",
                 "tags": [
                     "workflow"
                 ],
-                "summary": "获取工单详情",
-                "operationId": "getWorkflowV1",
-                "deprecated": true,
+                "summary": "暂停单个上线任务",
+                "operationId": "pauseSingleTaskByWorkflowV1",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "workflow name",
-                        "name": "workflow_name",
+                        "description": "workflow id",
+                        "name": "workflow_id",
                         "in": "path",
                         "required": true
                     },
@@ -5891,41 +6127,43 @@ var doc = `{
                         "name": "project_name",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "task id",
+                        "name": "task_id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/v1.GetWorkflowResV1"
+                            "$ref": "#/definitions/controller.BaseRes"
                         }
                     }
                 }
-            },
-            "patch": {
+            }
+        },
+        "/v1/projects/{project_name}/workflows/{workflow_id}/tasks/{task_id}/rehearse": {
+            "post": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "update workflow when it is rejected to creator.",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
+                "description": "rehearse one task on workflow, the DMLs are executed in a transaction and rolled back, the affected rows, warnings and elapsed time are recorded on task SQLs",
                 "tags": [
                     "workflow"
                 ],
-                "summary": "更新工单（驳回后才可更新）",
-                "operationId": "updateWorkflowV1",
-                "deprecated": true,
+                "summary": "预演单个上线任务",
+                "operationId": "rehearseSingleTaskByWorkflowV1",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "workflow name",
-                        "name": "workflow_name",
+                        "description": "workflow id",
+                        "name": "workflow_id",
                         "in": "path",
                         "required": true
                     },
@@ -5937,13 +6175,11 @@ var doc = `{
                         "required": true
                     },
                     {
-                        "description": "update workflow request",
-                        "name": "instance",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/v1.UpdateWorkflowReqV1"
-                        }
+                        "type": "string",
+                        "description": "task id",
+                        "name": "task_id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
@@ -5956,32 +6192,217 @@ var doc = `{
                 }
             }
         },
-        "/v1/projects/{project_name}/workflows/{workflow_name}/cancel": {
+        "/v1/projects/{project_name}/workflows/{workflow_id}/tasks/{task_id}/resume": {
             "post": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "cancel workflow",
+                "description": "resume one paused task on workflow",
                 "tags": [
                     "workflow"
                 ],
-                "summary": "审批关闭（中止）",
-                "operationId": "cancelWorkflowV1",
-                "deprecated": true,
+                "summary": "恢复单个已暂停的上线任务",
+                "operationId": "resumeSingleTaskByWorkflowV1",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "project name",
-                        "name": "project_name",
+                        "description": "workflow id",
+                        "name": "workflow_id",
                         "in": "path",
                         "required": true
                     },
                     {
                         "type": "string",
-                        "description": "workflow name",
-                        "name": "workflow_name",
+                        "description": "project name",
+                        "name": "project_name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "task id",
+                        "name": "task_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/controller.BaseRes"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/projects/{project_name}/workflows/{workflow_id}/tasks/{task_id}/terminate": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "execute one task on workflow",
+                "tags": [
+                    "workflow"
+                ],
+                "summary": "终止单个上线任务",
+                "operationId": "terminateSingleTaskByWorkflowV1",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "workflow id",
+                        "name": "workflow_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "project name",
+                        "name": "project_name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "task id",
+                        "name": "task_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/controller.BaseRes"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/projects/{project_name}/workflows/{workflow_name}/": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "get workflow detail",
+                "tags": [
+                    "workflow"
+                ],
+                "summary": "获取工单详情",
+                "operationId": "getWorkflowV1",
+                "deprecated": true,
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "workflow name",
+                        "name": "workflow_name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "project name",
+                        "name": "project_name",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v1.GetWorkflowResV1"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "update workflow when it is rejected to creator.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "workflow"
+                ],
+                "summary": "更新工单（驳回后才可更新）",
+                "operationId": "updateWorkflowV1",
+                "deprecated": true,
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "workflow name",
+                        "name": "workflow_name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "project name",
+                        "name": "project_name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "update workflow request",
+                        "name": "instance",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/v1.UpdateWorkflowReqV1"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/controller.BaseRes"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/projects/{project_name}/workflows/{workflow_name}/cancel": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "cancel workflow",
+                "tags": [
+                    "workflow"
+                ],
+                "summary": "审批关闭（中止）",
+                "operationId": "cancelWorkflowV1",
+                "deprecated": true,
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "project name",
+                        "name": "project_name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "workflow name",
+                        "name": "workflow_name",
                         "in": "path",
                         "required": true
                     }
@@ -7020,7 +7441,7 @@ var doc = `{
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "Direct get sql analysis result",
+                "description": "Direct get sql analysis result, including audit result, explain, table metadata, index advices and affect rows of each sql",
                 "tags": [
                     "sql_analysis"
                 ],
@@ -7051,7 +7472,8 @@ var doc = `{
                         "type": "string",
                         "description": "sql",
                         "name": "sql",
-                        "in": "query"
+                        "in": "query",
+                        "required": true
                     }
                 ],
                 "responses": {
@@ -7177,6 +7599,24 @@ var doc = `{
                         "name": "limit",
                         "in": "query",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "filter project name",
+                        "name": "filter_project_name",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "filter create date from.(format:yyyy-mm-dd)",
+                        "name": "filter_date_from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "filter create date to.(format:yyyy-mm-dd)",
+                        "name": "filter_date_to",
+                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -7209,6 +7649,24 @@ var doc = `{
                         "name": "limit",
                         "in": "query",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "filter project name",
+                        "name": "filter_project_name",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "filter create date from.(format:yyyy-mm-dd)",
+                        "name": "filter_date_from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "filter create date to.(format:yyyy-mm-dd)",
+                        "name": "filter_date_to",
+                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -7234,6 +7692,26 @@ var doc = `{
                 ],
                 "summary": "获取数据源类型百分比",
                 "operationId": "getInstancesTypePercentV1",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "filter project name",
+                        "name": "filter_project_name",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "filter create date from.(format:yyyy-mm-dd)",
+                        "name": "filter_date_from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "filter create date to.(format:yyyy-mm-dd)",
+                        "name": "filter_date_to",
+                        "in": "query"
+                    }
+                ],
                 "responses": {
                     "200": {
                         "description": "OK",
@@ -7280,6 +7758,26 @@ var doc = `{
                 ],
                 "summary": "获取工单审核通过率",
                 "operationId": "getWorkflowAuditPassPercentV1",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "filter project name",
+                        "name": "filter_project_name",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "filter create date from.(format:yyyy-mm-dd)",
+                        "name": "filter_date_from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "filter create date to.(format:yyyy-mm-dd)",
+                        "name": "filter_date_to",
+                        "in": "query"
+                    }
+                ],
                 "responses": {
                     "200": {
                         "description": "OK",
@@ -7303,6 +7801,26 @@ var doc = `{
                 ],
                 "summary": "获取工单数量统计数据",
                 "operationId": "getWorkflowCountV1",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "filter project name",
+                        "name": "filter_project_name",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "filter create date from.(format:yyyy-mm-dd)",
+                        "name": "filter_date_from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "filter create date to.(format:yyyy-mm-dd)",
+                        "name": "filter_date_to",
+                        "in": "query"
+                    }
+                ],
                 "responses": {
                     "200": {
                         "description": "OK",
@@ -7326,6 +7844,26 @@ var doc = `{
                 ],
                 "summary": "获取工单从创建到审核结束的平均时长",
                 "operationId": "getWorkflowDurationOfWaitingForAuditV1",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "filter project name",
+                        "name": "filter_project_name",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "filter create date from.(format:yyyy-mm-dd)",
+                        "name": "filter_date_from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "filter create date to.(format:yyyy-mm-dd)",
+                        "name": "filter_date_to",
+                        "in": "query"
+                    }
+                ],
                 "responses": {
                     "200": {
                         "description": "OK",
@@ -7343,13 +7881,32 @@ var doc = `{
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "get duration from workflow being created to executed",
+                "description": "get duration from workflow being audited to executed",
                 "tags": [
                     "statistic"
                 ],
                 "summary": "获取工单各从审核完毕到执行上线的平均时长",
                 "operationId": "getWorkflowDurationOfWaitingForExecutionV1",
-                "deprecated": true,
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "filter project name",
+                        "name": "filter_project_name",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "filter create date from.(format:yyyy-mm-dd)",
+                        "name": "filter_date_from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "filter create date to.(format:yyyy-mm-dd)",
+                        "name": "filter_date_to",
+                        "in": "query"
+                    }
+                ],
                 "responses": {
                     "200": {
                         "description": "OK",
@@ -7387,6 +7944,12 @@ var doc = `{
                         "name": "filter_date_to",
                         "in": "query",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "filter project name",
+                        "name": "filter_project_name",
+                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -7412,6 +7975,26 @@ var doc = `{
                 ],
                 "summary": "获取按数据源类型统计的工单百分比",
                 "operationId": "getWorkflowPercentCountedByInstanceTypeV1",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "filter project name",
+                        "name": "filter_project_name",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "filter create date from.(format:yyyy-mm-dd)",
+                        "name": "filter_date_from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "filter create date to.(format:yyyy-mm-dd)",
+                        "name": "filter_date_to",
+                        "in": "query"
+                    }
+                ],
                 "responses": {
                     "200": {
                         "description": "OK",
@@ -7466,6 +8049,24 @@ var doc = `{
                         "name": "limit",
                         "in": "query",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "filter project name",
+                        "name": "filter_project_name",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "filter create date from.(format:yyyy-mm-dd)",
+                        "name": "filter_date_from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "filter create date to.(format:yyyy-mm-dd)",
+                        "name": "filter_date_to",
+                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -7515,15 +8116,35 @@ var doc = `{
             "get": {
                 "security": [
                     {
-                        "ApiKeyAuth": []
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "get count of workflow status",
+                "tags": [
+                    "statistic"
+                ],
+                "summary": "获取各种状态工单的数量",
+                "operationId": "getWorkflowStatusCountV1",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "filter project name",
+                        "name": "filter_project_name",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "filter create date from.(format:yyyy-mm-dd)",
+                        "name": "filter_date_from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "filter create date to.(format:yyyy-mm-dd)",
+                        "name": "filter_date_to",
+                        "in": "query"
                     }
                 ],
-                "description": "get count of workflow status",
-                "tags": [
-                    "statistic"
-                ],
-                "summary": "获取各种状态工单的数量",
-                "operationId": "getWorkflowStatusCountV1",
                 "responses": {
                     "200": {
                         "description": "OK",
@@ -7682,7 +8303,7 @@ var doc = `{
                         "ApiKeyAuth": []
                     }
                 ],
-                "description": "update sync instance task",
+                "description": "update sync instance task, the password of url is kept if it is the redacted \"xxxxx\" returned by the task detail and the host is not changed",
                 "tags": [
                     "sync_instance"
                 ],
@@ -7716,6 +8337,52 @@ var doc = `{
                 }
             }
         },
+        "/v1/sync_instances/{task_id}/records": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "get records of sync task",
+                "tags": [
+                    "sync_instance"
+                ],
+                "summary": "获取同步任务执行记录",
+                "operationId": "getSyncInstanceTaskRecordsV1",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "sync task id",
+                        "name": "task_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "page index",
+                        "name": "page_index",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "size of per page",
+                        "name": "page_size",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v1.GetSyncInstanceTaskRecordsResV1"
+                        }
+                    }
+                }
+            }
+        },
         "/v1/sync_instances/{task_id}/trigger": {
             "post": {
                 "security": [
@@ -7836,6 +8503,38 @@ var doc = `{
                 }
             }
         },
+        "/v1/tasks/audits/{task_id}/index_advices": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "get index advices of the SELECT statements in task, the advices are deduplicated and ordered by estimated benefit.\nThe advices are generated by the index optimization rule (optimize_index_enabled) when auditing, so the result is empty if the rule is not enabled in the rule template of task.",
+                "tags": [
+                    "task"
+                ],
+                "summary": "获取Sql扫描任务的索引建议",
+                "operationId": "getAuditTaskIndexAdvicesV1",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "task id",
+                        "name": "task_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v1.GetIndexAdvicesResV1"
+                        }
+                    }
+                }
+            }
+        },
         "/v1/tasks/audits/{task_id}/sql_content": {
             "get": {
                 "security": [
@@ -9611,6 +10310,97 @@ var doc = `{
                 }
             }
         },
+        "/v2/projects/{project_name}/workflows/{workflow_id}/tasks/execute_stages": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "get execute stages of workflow tasks, the rollback SQLs of executed tasks are returned if the execution is halted.",
+                "tags": [
+                    "workflow"
+                ],
+                "summary": "获取工单任务上线阶段",
+                "operationId": "getWorkflowExecuteStagesV2",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "workflow id",
+                        "name": "workflow_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "project name",
+                        "name": "project_name",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/v2.GetWorkflowExecuteStagesResV2"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "update execute stages of workflow tasks, it can be updated before any task is executed. A task depends on all the tasks of the previous stages, the dependency between two single tasks is not supported. The task of later stage whose instance is out of maintenance window is queued for the next maintenance window.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "workflow"
+                ],
+                "summary": "设置工单任务分阶段上线（前一阶段全部上线成功后自动上线下一阶段，任一任务上线失败则停止上线之后的阶段）",
+                "operationId": "updateWorkflowExecuteStagesV2",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "workflow id",
+                        "name": "workflow_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "project name",
+                        "name": "project_name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "update workflow execute stages request",
+                        "name": "instance",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/v2.UpdateWorkflowExecuteStagesReqV2"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/controller.BaseRes"
+                        }
+                    }
+                }
+            }
+        },
         "/v2/projects/{project_name}/workflows/{workflow_id}/tasks/{task_id}/execute": {
             "post": {
                 "security": [
@@ -9674,7 +10464,7 @@ var doc = `{
                 "tags": [
                     "workflow"
                 ],
-                "summary": "设置工单数据源定时上线时间（设置为空则代表取消定时时间，需要SQL审核流程都通过后才可以设置）",
+                "summary": "设置工单数据源定时上线时间或在下个运维时间内自动上线（都设置为空则代表取消，需要SQL审核流程都通过后才可以设置）",
                 "operationId": "updateWorkflowScheduleV2",
                 "parameters": [
                     {
@@ -9898,6 +10688,50 @@ var doc = `{
                 }
             }
         },
+        "model.BindRole": {
+            "type": "object",
+            "properties": {
+                "instance_name": {
+                    "type": "string"
+                },
+                "role_names": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "v1.AffectRowsResV1": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer"
+                },
+                "err_message": {
+                    "type": "string"
+                }
+            }
+        },
+        "v1.ApplyConfigBundleResV1": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/v1.ConfigBundleChangeResV1"
+                    }
+                },
+                "message": {
+                    "type": "string",
+                    "example": "ok"
+                }
+            }
+        },
         "v1.AuditPlanCount": {
             "type": "object",
             "properties": {
@@ -10448,6 +11282,7 @@ var doc = `{
                 "status": {
                     "type": "string",
                     "enum": [
+                        "unhandled",
                         "solved",
                         "ignored",
                         "manual_audited"
@@ -10542,18 +11377,88 @@ var doc = `{
                         "type": "string"
                     }
                 },
-                "new_rule_template_name": {
-                    "type": "string"
-                }
-            }
-        },
-        "v1.CloneRuleTemplateReqV1": {
-            "type": "object",
-            "properties": {
-                "desc": {
+                "new_rule_template_name": {
+                    "type": "string"
+                }
+            }
+        },
+        "v1.CloneRuleTemplateReqV1": {
+            "type": "object",
+            "properties": {
+                "desc": {
+                    "type": "string"
+                },
+                "new_rule_template_name": {
+                    "type": "string"
+                }
+            }
+        },
+        "v1.ClusterNodeResV1": {
+            "type": "object",
+            "properties": {
+                "heartbeat_time": {
+                    "type": "string"
+                },
+                "is_alive": {
+                    "type": "boolean"
+                },
+                "is_leader": {
+                    "type": "boolean"
+                },
+                "server_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "v1.ClusterStatusResV1": {
+            "type": "object",
+            "properties": {
+                "is_cluster_mode": {
+                    "type": "boolean"
+                },
+                "leader_server_id": {
+                    "type": "string"
+                },
+                "nodes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/v1.ClusterNodeResV1"
+                    }
+                }
+            }
+        },
+        "v1.ConfigBundleChangeResV1": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "type": "string",
+                    "enum": [
+                        "create",
+                        "update"
+                    ]
+                },
+                "details": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "kind": {
+                    "type": "string",
+                    "enum": [
+                        "project",
+                        "rule_template",
+                        "instance",
+                        "workflow_template",
+                        "member",
+                        "audit_plan",
+                        "sql_whitelist"
+                    ]
+                },
+                "name": {
                     "type": "string"
                 },
-                "new_rule_template_name": {
+                "project_name": {
                     "type": "string"
                 }
             }
@@ -10674,6 +11579,14 @@ var doc = `{
                 "rule_script": {
                     "type": "string"
                 },
+                "script_type": {
+                    "description": "ScriptType is regular by default, the expression is in Go syntax and can use the variables\nsql, sql_type, tables, fingerprint and db_type, e.g. sql_type == \"dml\" \u0026\u0026 !match(\"(?i)\\\\bwhere\\\\b\", sql)",
+                    "type": "string",
+                    "enum": [
+                        "regular",
+                        "expression"
+                    ]
+                },
                 "type": {
                     "type": "string",
                     "example": "DDL规则"
@@ -10856,15 +11769,12 @@ var doc = `{
         },
         "v1.CreateSyncInstanceTaskReqV1": {
             "type": "object",
-            "required": [
-                "db_type",
-                "global_rule_template",
-                "source",
-                "sync_instance_interval",
-                "url",
-                "version"
-            ],
             "properties": {
+                "allow_empty_source": {
+                    "description": "源端未返回任何实例时是否允许删除该任务同步的全部实例",
+                    "type": "boolean",
+                    "example": false
+                },
                 "db_type": {
                     "type": "string",
                     "example": "mysql"
@@ -10873,9 +11783,17 @@ var doc = `{
                     "type": "string",
                     "example": "default_mysql"
                 },
+                "project_name": {
+                    "type": "string",
+                    "example": "default"
+                },
                 "source": {
                     "type": "string",
-                    "example": "actiontech-dmp"
+                    "enum": [
+                        "http",
+                        "file"
+                    ],
+                    "example": "http"
                 },
                 "sync_instance_interval": {
                     "type": "string",
@@ -10995,6 +11913,13 @@ var doc = `{
                 "rule_script": {
                     "type": "string"
                 },
+                "script_type": {
+                    "type": "string",
+                    "enum": [
+                        "regular",
+                        "expression"
+                    ]
+                },
                 "type": {
                     "type": "string",
                     "example": "DDL规则"
@@ -11524,6 +12449,23 @@ var doc = `{
                 }
             }
         },
+        "v1.GetClusterNodesResV1": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "data": {
+                    "type": "object",
+                    "$ref": "#/definitions/v1.ClusterStatusResV1"
+                },
+                "message": {
+                    "type": "string",
+                    "example": "ok"
+                }
+            }
+        },
         "v1.GetCustomRuleResV1": {
             "type": "object",
             "properties": {
@@ -11664,6 +12606,25 @@ var doc = `{
                 }
             }
         },
+        "v1.GetIndexAdvicesResV1": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/v1.IndexAdviceResV1"
+                    }
+                },
+                "message": {
+                    "type": "string",
+                    "example": "ok"
+                }
+            }
+        },
         "v1.GetInstanceAdditionalMetasResV1": {
             "type": "object",
             "properties": {
@@ -12057,6 +13018,9 @@ var doc = `{
                 "access_token_tag": {
                     "type": "string"
                 },
+                "auto_create_user": {
+                    "type": "boolean"
+                },
                 "client_host": {
                     "type": "string"
                 },
@@ -12066,6 +13030,13 @@ var doc = `{
                 "enable_oauth2": {
                     "type": "boolean"
                 },
+                "group_mappings": {
+                    "description": "GroupMappings grant the user groups and project roles to the users in the groups of identity provider",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/v1.Oauth2GroupMappingV1"
+                    }
+                },
                 "login_tip": {
                     "type": "string"
                 },
@@ -12078,14 +13049,29 @@ var doc = `{
                 "server_auth_url": {
                     "type": "string"
                 },
+                "server_issuer": {
+                    "type": "string"
+                },
+                "server_jwks_url": {
+                    "type": "string"
+                },
                 "server_token_url": {
                     "type": "string"
                 },
                 "server_user_id_url": {
                     "type": "string"
                 },
+                "user_email_tag": {
+                    "type": "string"
+                },
+                "user_groups_tag": {
+                    "type": "string"
+                },
                 "user_id_tag": {
                     "type": "string"
+                },
+                "user_name_tag": {
+                    "type": "string"
                 }
             }
         },
@@ -12793,6 +13779,28 @@ var doc = `{
                 }
             }
         },
+        "v1.GetSyncInstanceTaskRecordsResV1": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/v1.SyncInstanceTaskRecordResV1"
+                    }
+                },
+                "message": {
+                    "type": "string",
+                    "example": "ok"
+                },
+                "total_nums": {
+                    "type": "integer"
+                }
+            }
+        },
         "v1.GetSyncInstanceTaskResV1": {
             "type": "object",
             "properties": {
@@ -13358,6 +14366,35 @@ var doc = `{
                 }
             }
         },
+        "v1.IndexAdviceResV1": {
+            "type": "object",
+            "properties": {
+                "columns": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "covering_index_sql": {
+                    "type": "string"
+                },
+                "create_index_sql": {
+                    "type": "string"
+                },
+                "estimated_rows": {
+                    "type": "integer"
+                },
+                "sql_numbers": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "table_name": {
+                    "type": "string"
+                }
+            }
+        },
         "v1.InstanceAdditionalMetaV1": {
             "type": "object",
             "properties": {
@@ -13535,6 +14572,10 @@ var doc = `{
         "v1.InstanceTaskDetailResV1": {
             "type": "object",
             "properties": {
+                "allow_empty_source": {
+                    "type": "boolean",
+                    "example": false
+                },
                 "db_type": {
                     "type": "string",
                     "example": "mysql"
@@ -13543,6 +14584,10 @@ var doc = `{
                     "type": "integer",
                     "example": 1
                 },
+                "project_name": {
+                    "type": "string",
+                    "example": "default"
+                },
                 "rule_template": {
                     "type": "string",
                     "example": "default_mysql"
@@ -13588,6 +14633,10 @@ var doc = `{
                     "type": "string",
                     "example": "2021-08-12 12:00:00"
                 },
+                "project_name": {
+                    "type": "string",
+                    "example": "default"
+                },
                 "source": {
                     "type": "string",
                     "example": "actiontech-dmp"
@@ -13776,6 +14825,9 @@ var doc = `{
                 "message": {
                     "type": "string",
                     "example": "ok"
+                },
+                "total_nums": {
+                    "type": "integer"
                 }
             }
         },
@@ -13838,6 +14890,9 @@ var doc = `{
                 "access_token_tag": {
                     "type": "string"
                 },
+                "auto_create_user": {
+                    "type": "boolean"
+                },
                 "client_host": {
                     "type": "string"
                 },
@@ -13850,6 +14905,12 @@ var doc = `{
                 "enable_oauth2": {
                     "type": "boolean"
                 },
+                "group_mappings": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/v1.Oauth2GroupMappingV1"
+                    }
+                },
                 "login_tip": {
                     "type": "string"
                 },
@@ -13862,14 +14923,76 @@ var doc = `{
                 "server_auth_url": {
                     "type": "string"
                 },
+                "server_issuer": {
+                    "type": "string"
+                },
+                "server_jwks_url": {
+                    "type": "string"
+                },
                 "server_token_url": {
                     "type": "string"
                 },
                 "server_user_id_url": {
                     "type": "string"
                 },
+                "user_email_tag": {
+                    "type": "string"
+                },
+                "user_groups_tag": {
+                    "type": "string"
+                },
                 "user_id_tag": {
                     "type": "string"
+                },
+                "user_name_tag": {
+                    "type": "string"
+                }
+            }
+        },
+        "v1.Oauth2GroupMappingV1": {
+            "type": "object",
+            "properties": {
+                "group": {
+                    "type": "string",
+                    "example": "dba"
+                },
+                "project_roles": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/v1.Oauth2ProjectRoleV1"
+                    }
+                },
+                "user_groups": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "v1.Oauth2ProjectRoleV1": {
+            "type": "object",
+            "properties": {
+                "bind_roles": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.BindRole"
+                    }
+                },
+                "is_manager": {
+                    "type": "boolean"
+                },
+                "project_name": {
+                    "type": "string",
+                    "example": "default"
+                }
+            }
+        },
+        "v1.OnlineDDLCutOverReqV1": {
+            "type": "object",
+            "properties": {
+                "postpone": {
+                    "type": "boolean"
                 }
             }
         },
@@ -13901,6 +15024,9 @@ var doc = `{
         "v1.OperationRecordList": {
             "type": "object",
             "properties": {
+                "error_message": {
+                    "type": "string"
+                },
                 "id": {
                     "type": "integer"
                 },
@@ -14039,6 +15165,25 @@ var doc = `{
                 }
             }
         },
+        "v1.PlanConfigBundleResV1": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/v1.ConfigBundleChangeResV1"
+                    }
+                },
+                "message": {
+                    "type": "string",
+                    "example": "ok"
+                }
+            }
+        },
         "v1.ProjectDetailItem": {
             "type": "object",
             "properties": {
@@ -14601,6 +15746,20 @@ var doc = `{
         "v1.SqlAnalysisResDataV1": {
             "type": "object",
             "properties": {
+                "affect_rows": {
+                    "type": "object",
+                    "$ref": "#/definitions/v1.AffectRowsResV1"
+                },
+                "audit_result": {
+                    "type": "object",
+                    "$ref": "#/definitions/v1.AuditSQLResV1"
+                },
+                "index_advices": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/v1.IndexAdviceResV1"
+                    }
+                },
                 "sql_explain": {
                     "type": "object",
                     "$ref": "#/definitions/v1.SQLExplain"
@@ -14756,6 +15915,18 @@ var doc = `{
                 }
             }
         },
+        "v1.SyncInstanceConflictResV1": {
+            "type": "object",
+            "properties": {
+                "instance_name": {
+                    "type": "string",
+                    "example": "mysql_1"
+                },
+                "reason": {
+                    "type": "string"
+                }
+            }
+        },
         "v1.SyncInstanceResV1": {
             "type": "object",
             "properties": {
@@ -14763,8 +15934,52 @@ var doc = `{
                     "type": "boolean",
                     "example": true
                 },
-                "sync_error_message": {
+                "sync_error_message": {
+                    "type": "string"
+                }
+            }
+        },
+        "v1.SyncInstanceTaskRecordResV1": {
+            "type": "object",
+            "properties": {
+                "conflicts": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/v1.SyncInstanceConflictResV1"
+                    }
+                },
+                "created_num": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "deleted_num": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "end_time": {
+                    "type": "string"
+                },
+                "error_message": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "start_time": {
                     "type": "string"
+                },
+                "status": {
+                    "type": "string",
+                    "enum": [
+                        "succeeded",
+                        "failed"
+                    ],
+                    "example": "succeeded"
+                },
+                "updated_num": {
+                    "type": "integer",
+                    "example": 1
                 }
             }
         },
@@ -15270,6 +16485,13 @@ var doc = `{
                 "rule_script": {
                     "type": "string"
                 },
+                "script_type": {
+                    "type": "string",
+                    "enum": [
+                        "regular",
+                        "expression"
+                    ]
+                },
                 "type": {
                     "type": "string",
                     "example": "DDL规则"
@@ -15506,6 +16728,10 @@ var doc = `{
         "v1.UpdateSyncInstanceTaskReqV1": {
             "type": "object",
             "properties": {
+                "allow_empty_source": {
+                    "type": "boolean",
+                    "example": false
+                },
                 "global_rule_template": {
                     "type": "string",
                     "example": "default_mysql"
@@ -15864,6 +17090,18 @@ var doc = `{
         "v1.WorkFlowStepTemplateReqV1": {
             "type": "object",
             "properties": {
+                "approve_mode": {
+                    "description": "ApproveMode is how the assignees approve the sql_review step, any_of by default,\nApproveQuorum is the count of approvals required by quorum mode, e.g. 2 of 4 DBAs.",
+                    "type": "string",
+                    "enum": [
+                        "any_of",
+                        "all_of",
+                        "quorum"
+                    ]
+                },
+                "approve_quorum": {
+                    "type": "integer"
+                },
                 "approved_by_authorized": {
                     "type": "boolean"
                 },
@@ -15873,6 +17111,10 @@ var doc = `{
                         "type": "string"
                     }
                 },
+                "condition": {
+                    "type": "object",
+                    "$ref": "#/definitions/v1.WorkflowStepConditionV1"
+                },
                 "desc": {
                     "type": "string"
                 },
@@ -15891,6 +17133,17 @@ var doc = `{
         "v1.WorkFlowStepTemplateResV1": {
             "type": "object",
             "properties": {
+                "approve_mode": {
+                    "type": "string",
+                    "enum": [
+                        "any_of",
+                        "all_of",
+                        "quorum"
+                    ]
+                },
+                "approve_quorum": {
+                    "type": "integer"
+                },
                 "approved_by_authorized": {
                     "type": "boolean"
                 },
@@ -15900,6 +17153,10 @@ var doc = `{
                         "type": "string"
                     }
                 },
+                "condition": {
+                    "type": "object",
+                    "$ref": "#/definitions/v1.WorkflowStepConditionV1"
+                },
                 "desc": {
                     "type": "string"
                 },
@@ -16194,6 +17451,36 @@ var doc = `{
                 }
             }
         },
+        "v1.WorkflowStepConditionV1": {
+            "type": "object",
+            "properties": {
+                "audit_level": {
+                    "type": "string",
+                    "enum": [
+                        "normal",
+                        "notice",
+                        "warn",
+                        "error"
+                    ]
+                },
+                "instance_name_list": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "sql_types": {
+                    "type": "array",
+                    "items": {
+                        "type": "string",
+                        "enum": [
+                            "ddl",
+                            "dml"
+                        ]
+                    }
+                }
+            }
+        },
         "v1.WorkflowStepResV1": {
             "type": "object",
             "properties": {
@@ -16223,7 +17510,8 @@ var doc = `{
                     "enum": [
                         "initialized",
                         "approved",
-                        "rejected"
+                        "rejected",
+                        "skipped"
                     ]
                 },
                 "type": {
@@ -16428,6 +17716,12 @@ var doc = `{
                 "audit_status": {
                     "type": "string"
                 },
+                "batch_chunks": {
+                    "type": "integer"
+                },
+                "batch_progress": {
+                    "type": "number"
+                },
                 "description": {
                     "type": "string"
                 },
@@ -16443,8 +17737,36 @@ var doc = `{
                 "number": {
                     "type": "integer"
                 },
+                "online_ddl_progress": {
+                    "type": "object",
+                    "$ref": "#/definitions/v2.OnlineDDLProgressResV2"
+                },
+                "rehearse_elapsed_ms": {
+                    "type": "integer"
+                },
+                "rehearse_result": {
+                    "type": "string"
+                },
+                "rehearse_row_affects": {
+                    "type": "integer"
+                },
+                "rehearse_status": {
+                    "type": "string",
+                    "enum": [
+                        "initialized",
+                        "succeeded",
+                        "failed",
+                        "skipped"
+                    ]
+                },
+                "rehearse_warnings": {
+                    "type": "string"
+                },
                 "rollback_sql": {
                     "type": "string"
+                },
+                "row_affects": {
+                    "type": "integer"
                 }
             }
         },
@@ -16618,6 +17940,43 @@ var doc = `{
                 }
             }
         },
+        "v2.ExecutionStatistics": {
+            "type": "object",
+            "properties": {
+                "counter": {
+                    "type": "integer"
+                },
+                "history": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/v2.ExecutionStatisticsPoint"
+                    }
+                },
+                "query_time_avg": {
+                    "type": "number"
+                },
+                "query_time_max": {
+                    "type": "number"
+                }
+            }
+        },
+        "v2.ExecutionStatisticsPoint": {
+            "type": "object",
+            "properties": {
+                "counter": {
+                    "type": "integer"
+                },
+                "query_time_avg": {
+                    "type": "number"
+                },
+                "query_time_max": {
+                    "type": "number"
+                },
+                "time": {
+                    "type": "string"
+                }
+            }
+        },
         "v2.GetAuditPlanAnalysisDataResV2": {
             "type": "object",
             "properties": {
@@ -16776,6 +18135,25 @@ var doc = `{
                 }
             }
         },
+        "v2.GetWorkflowExecuteStagesResV2": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/v2.WorkflowExecuteStageResV2"
+                    }
+                },
+                "message": {
+                    "type": "string",
+                    "example": "ok"
+                }
+            }
+        },
         "v2.GetWorkflowResV2": {
             "type": "object",
             "properties": {
@@ -16808,6 +18186,9 @@ var doc = `{
                 "exec_start_time": {
                     "type": "string"
                 },
+                "execute_stage": {
+                    "type": "integer"
+                },
                 "execution_user_name": {
                     "type": "string"
                 },
@@ -16820,6 +18201,17 @@ var doc = `{
                 "instance_name": {
                     "type": "string"
                 },
+                "maintenance_priority": {
+                    "type": "string",
+                    "enum": [
+                        "high",
+                        "medium",
+                        "low"
+                    ]
+                },
+                "maintenance_queued_time": {
+                    "type": "string"
+                },
                 "schedule_time": {
                     "type": "string"
                 },
@@ -16829,9 +18221,11 @@ var doc = `{
                         "wait_for_audit",
                         "wait_for_execution",
                         "exec_scheduled",
+                        "exec_queued",
                         "exec_failed",
                         "exec_succeeded",
                         "executing",
+                        "paused",
                         "manually_executed",
                         "terminating",
                         "terminate_succeeded",
@@ -16920,12 +18314,49 @@ var doc = `{
                 }
             }
         },
+        "v2.OnlineDDLProgressResV2": {
+            "type": "object",
+            "properties": {
+                "cut_over_postponable": {
+                    "type": "boolean"
+                },
+                "eta_seconds": {
+                    "type": "integer"
+                },
+                "lag_millis": {
+                    "type": "integer"
+                },
+                "postponing_cut_over": {
+                    "type": "boolean"
+                },
+                "progress_pct": {
+                    "type": "number"
+                },
+                "rows_copied": {
+                    "type": "integer"
+                },
+                "rows_estimate": {
+                    "type": "integer"
+                },
+                "throttle_reason": {
+                    "type": "string"
+                },
+                "throttled": {
+                    "type": "boolean"
+                }
+            }
+        },
         "v2.PerformanceStatistics": {
             "type": "object",
             "properties": {
                 "affect_rows": {
                     "type": "object",
                     "$ref": "#/definitions/v2.AffectRows"
+                },
+                "execution_statistics": {
+                    "description": "ExecutionStatistics is only available for SQL collected by audit plan",
+                    "type": "object",
+                    "$ref": "#/definitions/v2.ExecutionStatistics"
                 }
             }
         },
@@ -16994,6 +18425,18 @@ var doc = `{
                 }
             }
         },
+        "v2.UpdateWorkflowExecuteStagesReqV2": {
+            "type": "object",
+            "properties": {
+                "stages": {
+                    "description": "按上线顺序排列的阶段，需要包含工单的所有任务，为空则代表取消分阶段上线",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/v2.WorkflowExecuteStageReqV2"
+                    }
+                }
+            }
+        },
         "v2.UpdateWorkflowReqV2": {
             "type": "object",
             "properties": {
@@ -17008,11 +18451,78 @@ var doc = `{
         "v2.UpdateWorkflowScheduleReqV2": {
             "type": "object",
             "properties": {
+                "execute_in_maintenance_time": {
+                    "description": "在数据源的下个运维时间内自动上线，同一数据源的任务按优先级和排队时间依次上线，与定时上线互斥",
+                    "type": "boolean"
+                },
+                "maintenance_priority": {
+                    "type": "string",
+                    "enum": [
+                        "high",
+                        "medium",
+                        "low"
+                    ]
+                },
                 "schedule_time": {
                     "type": "string"
                 }
             }
         },
+        "v2.WorkflowExecuteStageReqV2": {
+            "type": "object",
+            "properties": {
+                "task_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                }
+            }
+        },
+        "v2.WorkflowExecuteStageResV2": {
+            "type": "object",
+            "properties": {
+                "stage": {
+                    "type": "integer"
+                },
+                "state": {
+                    "type": "string",
+                    "enum": [
+                        "pending",
+                        "executing",
+                        "succeeded",
+                        "failed"
+                    ]
+                },
+                "tasks": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/v2.WorkflowExecuteStageTaskResV2"
+                    }
+                }
+            }
+        },
+        "v2.WorkflowExecuteStageTaskResV2": {
+            "type": "object",
+            "properties": {
+                "instance_name": {
+                    "type": "string"
+                },
+                "rollback_sql_list": {
+                    "description": "工单停止上线时，返回已上线成功的任务的回滚SQL",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "status": {
+                    "type": "string"
+                },
+                "task_id": {
+                    "type": "integer"
+                }
+            }
+        },
         "v2.WorkflowRecordResV2": {
             "type": "object",
             "properties": {
@@ -17082,9 +18592,45 @@ var doc = `{
                 }
             }
         },
+        "v2.WorkflowStepApprovalResV2": {
+            "type": "object",
+            "properties": {
+                "operation_time": {
+                    "type": "string"
+                },
+                "operation_user_name": {
+                    "type": "string"
+                },
+                "reason": {
+                    "type": "string"
+                },
+                "state": {
+                    "type": "string",
+                    "enum": [
+                        "approved",
+                        "rejected"
+                    ]
+                }
+            }
+        },
         "v2.WorkflowStepResV2": {
             "type": "object",
             "properties": {
+                "approval_list": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/v2.WorkflowStepApprovalResV2"
+                    }
+                },
+                "approve_mode": {
+                    "description": "ApproveMode and RequiredApprovalCount are only returned for sql_review step.",
+                    "type": "string",
+                    "enum": [
+                        "any_of",
+                        "all_of",
+                        "quorum"
+                    ]
+                },
                 "assignee_user_name_list": {
                     "type": "array",
                     "items": {
@@ -17106,12 +18652,16 @@ var doc = `{
                 "reason": {
                     "type": "string"
                 },
+                "required_approval_count": {
+                    "type": "integer"
+                },
                 "state": {
                     "type": "string",
                     "enum": [
                         "initialized",
                         "approved",
-                        "rejected"
+                        "rejected",
+                        "skipped"
                     ]
                 },
                 "type": {
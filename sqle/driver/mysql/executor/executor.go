@@ -358,6 +358,52 @@ func (c *Executor) ShowSchemaTables(schema string) ([]string, error) {
 	return tables, nil
 }
 
+// ListSchemaTables returns the tables in schema whose name contains fuzzyTableName and the total number of them,
+// limit 0 means return all tables.
+func (c *Executor) ListSchemaTables(schema, fuzzyTableName string, limit, offset uint32) ([]string, uint64, error) {
+	where := "table_schema=? and TABLE_TYPE in ('BASE TABLE','SYSTEM VIEW')"
+	if c.IsLowerCaseTableNames() {
+		schema = strings.ToLower(schema)
+		where = "lower(table_schema)=? and TABLE_TYPE in ('BASE TABLE','SYSTEM VIEW')"
+	}
+	args := []interface{}{schema}
+	if fuzzyTableName != "" {
+		where += " and TABLE_NAME like ?"
+		args = append(args, "%"+likeEscaper.Replace(fuzzyTableName)+"%")
+	}
+
+	result, err := c.Db.Query(fmt.Sprintf("select count(*) as total from information_schema.tables where %s", where), args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	if len(result) != 1 {
+		err := fmt.Errorf("count tables error, result not match")
+		c.Db.Logger().Error(err)
+		return nil, 0, errors.New(errors.ConnectRemoteDatabaseError, err)
+	}
+	total, err := strconv.ParseUint(result[0]["total"].String, 10, 64)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	query := fmt.Sprintf("select TABLE_NAME as table_name from information_schema.tables where %s order by TABLE_NAME", where)
+	if limit > 0 {
+		query += " limit ? offset ?"
+		args = append(args, limit, offset)
+	}
+	result, err = c.Db.Query(query, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	tables := make([]string, 0, len(result))
+	for _, v := range result {
+		tables = append(tables, v["table_name"].String)
+	}
+	return tables, total, nil
+}
+
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
 func (c *Executor) ShowSchemaViews(schema string) ([]string, error) {
 	query := fmt.Sprintf(
 		"select TABLE_NAME from information_schema.tables where table_schema='%s' and TABLE_TYPE='VIEW'", schema)
@@ -16,7 +16,9 @@ import (
 	"github.com/actiontech/sqle/sqle/driver/mysql/util"
 	driverV2 "github.com/actiontech/sqle/sqle/driver/v2"
 	"github.com/actiontech/sqle/sqle/log"
+	indexoptimizer "github.com/actiontech/sqle/sqle/pkg/optimizer/index"
 	"github.com/actiontech/sqle/sqle/pkg/params"
+	"github.com/actiontech/sqle/sqle/utils"
 	"github.com/pingcap/parser/ast"
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
@@ -369,11 +371,16 @@ func (i *MysqlDriverImpl) audit(ctx context.Context, sql string) (*driverV2.Audi
 			i.log.Errorf("optimize sqle failed: %v", err)
 		}
 
-		var buf strings.Builder
 		for _, advice := range advices {
-			buf.WriteString(fmt.Sprintf("建议从表 %s 的以下列中 [%s] 选取合适的列添加索引", advice.TableName, strings.Join(advice.IndexedColumns, ",")))
+			a := &driverV2.IndexAdvice{
+				TableName:        advice.TableName,
+				Columns:          advice.IndexedColumns,
+				CreateIndexSQL:   advice.CreateIndexSQL,
+				CoveringIndexSQL: advice.CoveringIndexSQL,
+				EstimatedRows:    advice.EstimatedRows,
+			}
+			i.result.AddIndexAdvice(driverV2.RuleLevelNotice, rulepkg.ConfigOptimizeIndexEnabled, indexoptimizer.AdviceMessage(a), a)
 		}
-		i.result.Add(driverV2.RuleLevelNotice, rulepkg.ConfigOptimizeIndexEnabled, buf.String())
 	}
 
 	// dry run gh-ost
@@ -457,6 +464,88 @@ func (i *MysqlDriverImpl) Schemas(ctx context.Context) ([]string, error) {
 	return conn.ShowDatabases(true)
 }
 
+func (i *MysqlDriverImpl) ListTables(ctx context.Context, conf *driverV2.ListTablesConf) (*driverV2.ListTablesResult, error) {
+	if i.IsOfflineAudit() {
+		return &driverV2.ListTablesResult{}, nil
+	}
+	conn, err := i.getDbConn()
+	if err != nil {
+		return nil, err
+	}
+	tableNames, total, err := conn.ListSchemaTables(conf.Schema, conf.FuzzySearchTableName, conf.Limit, conf.Offset)
+	if err != nil {
+		return nil, err
+	}
+	tables := make([]*driverV2.Table, 0, len(tableNames))
+	for _, name := range tableNames {
+		tables = append(tables, &driverV2.Table{Name: name, Schema: conf.Schema})
+	}
+	return &driverV2.ListTablesResult{Tables: tables, Total: total}, nil
+}
+
+func (i *MysqlDriverImpl) GetTableMeta(ctx context.Context, table *driverV2.Table) (*driverV2.TableMeta, error) {
+	if i.IsOfflineAudit() {
+		return nil, fmt.Errorf("get table meta is not supported in offline audit")
+	}
+	conn, err := i.getDbConn()
+	if err != nil {
+		return nil, err
+	}
+	columns, err := conn.GetTableColumnsInfo(table.Schema, table.Name)
+	if err != nil {
+		return nil, err
+	}
+	if len(columns) == 0 {
+		return &driverV2.TableMeta{Message: fmt.Sprintf("table %s.%s is not found", table.Schema, table.Name)}, nil
+	}
+
+	schemaName := utils.SupplementalQuotationMarks(table.Schema)
+	tableName := utils.SupplementalQuotationMarks(table.Name)
+	indexes, err := conn.GetTableIndexesInfo(schemaName, tableName)
+	if err != nil {
+		return nil, err
+	}
+	createTableSQL, err := conn.ShowCreateTable(schemaName, tableName)
+	if err != nil {
+		return nil, err
+	}
+
+	meta := &driverV2.TableMeta{CreateTableSQL: createTableSQL}
+	meta.ColumnsInfo.Columns = []driverV2.TabularDataHead{
+		{Name: "COLUMN_NAME", Desc: "列名"},
+		{Name: "COLUMN_TYPE", Desc: "列类型"},
+		{Name: "CHARACTER_SET_NAME", Desc: "列字符集"},
+		{Name: "IS_NULLABLE", Desc: "是否可以为空"},
+		{Name: "COLUMN_KEY", Desc: "列索引"},
+		{Name: "COLUMN_DEFAULT", Desc: "默认值"},
+		{Name: "EXTRA", Desc: "拓展信息"},
+		{Name: "COLUMN_COMMENT", Desc: "列说明"},
+	}
+	for _, c := range columns {
+		meta.ColumnsInfo.Rows = append(meta.ColumnsInfo.Rows, []string{
+			c.ColumnName, c.ColumnType, c.CharacterSetName, c.IsNullable,
+			c.ColumnKey, c.ColumnDefault, c.Extra, c.ColumnComment,
+		})
+	}
+	meta.IndexesInfo.Columns = []driverV2.TabularDataHead{
+		{Name: "Column_name", Desc: "列名"},
+		{Name: "Key_name", Desc: "索引名"},
+		{Name: "Non_unique", Desc: "唯一性"},
+		{Name: "Seq_in_index", Desc: "列序列"},
+		{Name: "Cardinality", Desc: "基数"},
+		{Name: "Null", Desc: "是否为空"},
+		{Name: "Index_type", Desc: "索引类型"},
+		{Name: "Comment", Desc: "备注"},
+	}
+	for _, idx := range indexes {
+		meta.IndexesInfo.Rows = append(meta.IndexesInfo.Rows, []string{
+			idx.ColumnName, idx.KeyName, idx.NonUnique, idx.SeqInIndex,
+			idx.Cardinality, idx.Null, idx.IndexType, idx.Comment,
+		})
+	}
+	return meta, nil
+}
+
 func (i *MysqlDriverImpl) EstimateSQLAffectRows(ctx context.Context, sql string) (*driverV2.EstimatedAffectRows, error) {
 	if i.IsOfflineAudit() {
 		return nil, nil
@@ -569,6 +658,20 @@ func (i *MysqlDriverImpl) getPrimaryKey(stmt *ast.CreateTableStmt) (map[string]s
 	return pkColumnsName, hasPk, nil
 }
 
+// ParamRollbackByBinlog is the additional param of instance, the rollback SQL of DML is generated
+// from the binlog which is written by the execution if it is enabled.
+const ParamRollbackByBinlog = "rollback_by_binlog"
+
+// ParamBatchDML* are the additional params of instance, the eligible UPDATE/DELETE is rewritten
+// into primary key range chunks and executed in batches if it is enabled.
+const (
+	ParamBatchDMLEnabled       = "batch_dml_enabled"
+	ParamBatchDMLSize          = "batch_dml_size"
+	ParamBatchDMLSleepMs       = "batch_dml_sleep_ms"
+	ParamBatchDMLMaxReplicaLag = "batch_dml_max_replica_lag"
+	ParamBatchDMLReplicas      = "batch_dml_replicas"
+)
+
 type PluginProcessor struct{}
 
 func (p *PluginProcessor) GetDriverMetas() (*driverV2.DriverMetas, error) {
@@ -580,11 +683,48 @@ func (p *PluginProcessor) GetDriverMetas() (*driverV2.DriverMetas, error) {
 		allRules[i] = &rulepkg.RuleHandlers[i].Rule
 	}
 	return &driverV2.DriverMetas{
-		PluginName:               driverV2.DriverTypeMySQL,
-		DatabaseDefaultPort:      3306,
-		Logo:                     logo,
-		Rules:                    allRules,
-		DatabaseAdditionalParams: params.Params{},
+		PluginName:          driverV2.DriverTypeMySQL,
+		DatabaseDefaultPort: 3306,
+		Logo:                logo,
+		Rules:               allRules,
+		DatabaseAdditionalParams: append(params.Params{
+			&params.Param{
+				Key:   ParamRollbackByBinlog,
+				Value: "false",
+				Desc:  "上线 DML 时解析 binlog 生成回滚语句（需要 ROW 格式的 binlog 和 REPLICATION SLAVE 权限）",
+				Type:  params.ParamTypeBool,
+			},
+			&params.Param{
+				Key:   ParamBatchDMLEnabled,
+				Value: "false",
+				Desc:  "上线时将单表 UPDATE/DELETE 按主键范围分批执行（要求表有单列整型主键）",
+				Type:  params.ParamTypeBool,
+			},
+			&params.Param{
+				Key:   ParamBatchDMLSize,
+				Value: "1000",
+				Desc:  "分批执行时每批扫描的行数",
+				Type:  params.ParamTypeInt,
+			},
+			&params.Param{
+				Key:   ParamBatchDMLSleepMs,
+				Value: "100",
+				Desc:  "分批执行时每批之间的间隔（毫秒）",
+				Type:  params.ParamTypeInt,
+			},
+			&params.Param{
+				Key:   ParamBatchDMLMaxReplicaLag,
+				Value: "10",
+				Desc:  "分批执行时允许的最大从库延迟（秒），超过时等待从库追上后再执行",
+				Type:  params.ParamTypeInt,
+			},
+			&params.Param{
+				Key:   ParamBatchDMLReplicas,
+				Value: "",
+				Desc:  "分批执行时检查延迟的从库地址，格式为 host:port，多个地址用逗号分隔，使用实例的账号连接",
+				Type:  params.ParamTypeString,
+			},
+		}, onlineddl.AdditionalParams...),
 		EnabledOptionalModule: []driverV2.OptionalModule{
 			driverV2.OptionalModuleGenRollbackSQL,
 			driverV2.OptionalModuleQuery,
@@ -593,6 +733,7 @@ func (p *PluginProcessor) GetDriverMetas() (*driverV2.DriverMetas, error) {
 			driverV2.OptionalModuleExtractTableFromSQL,
 			driverV2.OptionalModuleEstimateSQLAffectRows,
 			driverV2.OptionalModuleKillProcess,
+			driverV2.OptionalModuleListTables,
 		},
 	}, nil
 }
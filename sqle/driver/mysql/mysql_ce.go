@@ -6,17 +6,102 @@ package mysql
 import (
 	"context"
 	"fmt"
+	"sort"
 
 	"github.com/actiontech/sqle/sqle/driver"
+	"github.com/actiontech/sqle/sqle/driver/mysql/util"
 	driverV2 "github.com/actiontech/sqle/sqle/driver/v2"
+
+	"github.com/pingcap/parser/ast"
 )
 
 func (i *MysqlDriverImpl) Explain(ctx context.Context, conf *driverV2.ExplainConf) (*driverV2.ExplainResult, error) {
-	return nil, fmt.Errorf("only support Explain in enterprise edition")
+	if i.IsOfflineAudit() {
+		return nil, fmt.Errorf("explain is not supported in offline audit")
+	}
+	node, err := i.parseSingleSql(conf.Sql)
+	if err != nil {
+		return nil, err
+	}
+	switch node.(type) {
+	case *ast.SelectStmt, *ast.UnionStmt, *ast.InsertStmt, *ast.UpdateStmt, *ast.DeleteStmt:
+	default:
+		return nil, driverV2.ErrSQLIsNotSupported
+	}
+
+	conn, err := i.getDbConn()
+	if err != nil {
+		return nil, err
+	}
+	columns, rows, err := conn.Explain(conf.Sql)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &driverV2.ExplainResult{}
+	for _, column := range columns {
+		result.ClassicResult.Columns = append(result.ClassicResult.Columns, driverV2.TabularDataHead{Name: column})
+	}
+	for _, row := range rows {
+		values := make([]string, len(row))
+		for j, v := range row {
+			values[j] = v.String
+		}
+		result.ClassicResult.Rows = append(result.ClassicResult.Rows, values)
+	}
+	return result, nil
 }
 
 func (i *MysqlDriverImpl) GetTableMetaBySQL(ctx context.Context, conf *driver.GetTableMetaBySQLConf) (*driver.GetTableMetaBySQLResult, error) {
-	return nil, fmt.Errorf("only support GetTableMetaBySQL in enterprise edition")
+	if i.IsOfflineAudit() {
+		return nil, fmt.Errorf("get table meta is not supported in offline audit")
+	}
+	node, err := i.parseSingleSql(conf.Sql)
+	if err != nil {
+		return nil, err
+	}
+	extractor := util.TableNameExtractor{TableNames: map[string]*ast.TableName{}}
+	node.Accept(&extractor)
+
+	tables := make([]*driverV2.Table, 0, len(extractor.TableNames))
+	for _, tableName := range extractor.TableNames {
+		schema := i.Ctx.GetSchemaName(tableName)
+		if schema == "" && i.inst != nil {
+			schema = i.inst.DatabaseName
+		}
+		tables = append(tables, &driverV2.Table{Name: tableName.Name.O, Schema: schema})
+	}
+	sort.Slice(tables, func(a, b int) bool {
+		if tables[a].Schema != tables[b].Schema {
+			return tables[a].Schema < tables[b].Schema
+		}
+		return tables[a].Name < tables[b].Name
+	})
+
+	result := &driver.GetTableMetaBySQLResult{}
+	for _, table := range tables {
+		tableMeta := &driver.TableMeta{Table: *table}
+		meta, err := i.GetTableMeta(ctx, table)
+		if err != nil {
+			// keep metas of other tables when one of them failed, e.g. the table is a CTE.
+			tableMeta.Message = err.Error()
+		} else {
+			tableMeta.TableMeta = *meta
+		}
+		result.TableMetas = append(result.TableMetas, tableMeta)
+	}
+	return result, nil
+}
+
+func (i *MysqlDriverImpl) parseSingleSql(sql string) (ast.Node, error) {
+	nodes, err := i.ParseSql(sql)
+	if err != nil {
+		return nil, err
+	}
+	if len(nodes) != 1 {
+		return nil, fmt.Errorf("only single SQL is supported, but got %d", len(nodes))
+	}
+	return nodes[0], nil
 }
 
 func (i *MysqlDriverImpl) Query(ctx context.Context, sql string, conf *driverV2.QueryConf) (*driverV2.QueryResult, error) {
@@ -0,0 +1,53 @@
+//go:build !enterprise
+// +build !enterprise
+
+package mysql
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	"github.com/actiontech/sqle/sqle/driver"
+	"github.com/actiontech/sqle/sqle/driver/mysql/executor"
+	driverV2 "github.com/actiontech/sqle/sqle/driver/v2"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInspect_Explain(t *testing.T) {
+	e, handler, err := executor.NewMockExecutor()
+	assert.NoError(t, err)
+	inspect := NewMockInspect(e)
+	inspect.isConnected = true
+
+	_, err = inspect.Explain(context.TODO(), &driverV2.ExplainConf{Sql: "create table t1(id int)"})
+	assert.Equal(t, driverV2.ErrSQLIsNotSupported, err)
+
+	handler.ExpectQuery(regexp.QuoteMeta("EXPLAIN select * from exist_db.exist_tb_1")).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "table", "type"}).AddRow("1", "exist_tb_1", "ALL"))
+	result, err := inspect.Explain(context.TODO(), &driverV2.ExplainConf{Sql: "select * from exist_db.exist_tb_1"})
+	assert.NoError(t, err)
+	assert.Equal(t, []driverV2.TabularDataHead{{Name: "id"}, {Name: "table"}, {Name: "type"}}, result.ClassicResult.Columns)
+	assert.Equal(t, [][]string{{"1", "exist_tb_1", "ALL"}}, result.ClassicResult.Rows)
+	assert.NoError(t, handler.ExpectationsWereMet())
+}
+
+func TestInspect_GetTableMetaBySQL(t *testing.T) {
+	e, handler, err := executor.NewMockExecutor()
+	assert.NoError(t, err)
+	inspect := NewMockInspect(e)
+	inspect.isConnected = true
+
+	handler.ExpectQuery(regexp.QuoteMeta("SELECT COLUMN_NAME, COLUMN_TYPE, CHARACTER_SET_NAME, IS_NULLABLE, COLUMN_KEY, COLUMN_DEFAULT, EXTRA, COLUMN_COMMENT FROM INFORMATION_SCHEMA.COLUMNS WHERE TABLE_SCHEMA=? AND TABLE_NAME=?")).
+		WithArgs("exist_db", "not_exist_tb_1").
+		WillReturnRows(sqlmock.NewRows([]string{"COLUMN_NAME", "COLUMN_TYPE", "CHARACTER_SET_NAME", "IS_NULLABLE", "COLUMN_KEY", "COLUMN_DEFAULT", "EXTRA", "COLUMN_COMMENT"}))
+
+	result, err := inspect.GetTableMetaBySQL(context.TODO(), &driver.GetTableMetaBySQLConf{Sql: "select * from exist_db.not_exist_tb_1"})
+	assert.NoError(t, err)
+	assert.Len(t, result.TableMetas, 1)
+	assert.Equal(t, driverV2.Table{Name: "not_exist_tb_1", Schema: "exist_db"}, result.TableMetas[0].Table)
+	assert.Equal(t, "table exist_db.not_exist_tb_1 is not found", result.TableMetas[0].Message)
+	assert.NoError(t, handler.ExpectationsWereMet())
+}
@@ -2,9 +2,13 @@ package mysql
 
 import (
 	"context"
+	"regexp"
 	"testing"
 
+	"github.com/actiontech/sqle/sqle/driver/mysql/executor"
 	driverV2 "github.com/actiontech/sqle/sqle/driver/v2"
+
+	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -162,3 +166,28 @@ func TestInspect_GenRollbackSQL(t *testing.T) {
 	assert.Equal(t, "", reason)
 	assert.Equal(t, "ALTER TABLE `exist_db`.`t1`\nDROP COLUMN `c1`;", rollback)
 }
+
+func TestInspect_ListTables(t *testing.T) {
+	e, handler, err := executor.NewMockExecutor()
+	assert.NoError(t, err)
+	inspect := NewMockInspect(e)
+	inspect.isConnected = true
+
+	handler.ExpectQuery(regexp.QuoteMeta("select count(*) as total from information_schema.tables where table_schema=? and TABLE_TYPE in ('BASE TABLE','SYSTEM VIEW') and TABLE_NAME like ?")).
+		WithArgs("exist_db", `%tb\_%`).
+		WillReturnRows(sqlmock.NewRows([]string{"total"}).AddRow("3"))
+	handler.ExpectQuery(regexp.QuoteMeta("select TABLE_NAME as table_name from information_schema.tables where table_schema=? and TABLE_TYPE in ('BASE TABLE','SYSTEM VIEW') and TABLE_NAME like ? order by TABLE_NAME limit ? offset ?")).
+		WithArgs("exist_db", `%tb\_%`, 2, 2).
+		WillReturnRows(sqlmock.NewRows([]string{"table_name"}).AddRow("exist_tb_3"))
+
+	result, err := inspect.ListTables(context.TODO(), &driverV2.ListTablesConf{
+		Schema:               "exist_db",
+		FuzzySearchTableName: "tb_",
+		Limit:                2,
+		Offset:               2,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(3), result.Total)
+	assert.Equal(t, []*driverV2.Table{{Name: "exist_tb_3", Schema: "exist_db"}}, result.Tables)
+	assert.NoError(t, handler.ExpectationsWereMet())
+}
@@ -7,6 +7,7 @@ import (
 	"os"
 	"strconv"
 	"strings"
+	"sync/atomic"
 
 	driverV2 "github.com/actiontech/sqle/sqle/driver/v2"
 
@@ -75,7 +76,9 @@ func NewExecutor(logger *logrus.Entry, inst *driverV2.DSN, schema string, query
 			}
 		}
 
-		if err := cfg.apply(mc); err != nil {
+		cfg.override(inst.AdditionalParams)
+
+		if err := cfg.apply(mc, newMigrationId()); err != nil {
 			return nil, errors.Wrap(err, "apply config to migration context")
 		}
 	}
@@ -93,6 +96,19 @@ func NewExecutor(logger *logrus.Entry, inst *driverV2.DSN, schema string, query
 func (e *Executor) Execute(ctx context.Context, dryRun bool) error {
 	if dryRun {
 		e.mc.Noop = true
+	} else {
+		key := MigrationKey(e.mc.InspectorConnectionConfig.Key.Hostname,
+			strconv.Itoa(e.mc.InspectorConnectionConfig.Key.Port), e.mc.DatabaseName, e.mc.OriginalTableName)
+		register(key, e)
+		defer unregister(key, e)
+
+		// the flag left behind by the aborted migration should not postpone this one, the
+		// flag is created by gh-ost again when the migration starts.
+		if e.mc.PostponeCutOverFlagFile != "" {
+			if err := os.Remove(e.mc.PostponeCutOverFlagFile); err != nil && !os.IsNotExist(err) {
+				return errors.Wrap(err, "remove postpone cut-over flag file")
+			}
+		}
 	}
 
 	m := logic.NewMigrator(e.mc)
@@ -106,6 +122,21 @@ func (e *Executor) Execute(ctx context.Context, dryRun bool) error {
 
 const cfgPath = "./etc/gh-ost.ini"
 
+var migrationSeq uint64
+
+// newMigrationId returns the id which is unique among the migrations of the process, it is
+// a part of the default file names of migration.
+func newMigrationId() string {
+	return fmt.Sprintf("%d.%d", os.Getpid(), atomic.AddUint64(&migrationSeq, 1))
+}
+
+// defaultFilePath returns the default path of the file of migration, the migrations of the same
+// table on different instances, or the runs of the same migration, do not share the file.
+func defaultFilePath(mc *base.MigrationContext, migrationId, suffix string) string {
+	return fmt.Sprintf("/tmp/gh-ost.%s.%d.%s.%s.%s.%s", mc.InspectorConnectionConfig.Key.Hostname,
+		mc.InspectorConnectionConfig.Key.Port, mc.DatabaseName, mc.OriginalTableName, migrationId, suffix)
+}
+
 // config refer to https://github.com/github/gh-ost/blob/master/go/cmd/gh-ost/main.go
 type config struct {
 	//user             string  `ini:"user"`
@@ -175,6 +206,10 @@ type config struct {
 	PostponeCutOverFlagFile    string `ini:"postpone_cut_over_flag_file"`
 	PanicFlagFile              string `ini:"panic_flag_file"`
 
+	// PostponeCutOver postpones the cut-over with the default flag file if the postpone_cut_over_flag_file
+	// is not specified, the cut-over is triggered by CutOver.
+	PostponeCutOver bool `ini:"postpone_cut_over"`
+
 	InitiallyDropSocketFile bool   `ini:"initially_drop_socket_file"`
 	ServeSocketFile         string `ini:"serve_socket_file"`
 	ServeTCPPort            int64  `ini:"serve_tcp_port"`
@@ -262,7 +297,7 @@ func newDefaultConfig() *config {
 	return cfg
 }
 
-func (cfg *config) apply(mc *base.MigrationContext) error {
+func (cfg *config) apply(mc *base.MigrationContext, migrationId string) error {
 	mc.ReplicaServerId = cfg.ReplicaServerID
 	mc.AssumeMasterHostname = cfg.AssumeMasterHost
 	mc.CliMasterUser = cfg.MasterUser
@@ -293,13 +328,16 @@ func (cfg *config) apply(mc *base.MigrationContext) error {
 	mc.ThrottleFlagFile = cfg.ThrottleFlagFile
 	mc.ThrottleAdditionalFlagFile = cfg.ThrottleAdditionalFlagFile
 	mc.PostponeCutOverFlagFile = cfg.PostponeCutOverFlagFile
+	if cfg.PostponeCutOver && mc.PostponeCutOverFlagFile == "" {
+		mc.PostponeCutOverFlagFile = defaultFilePath(mc, migrationId, "postpone.flag")
+	}
 	mc.IgnoreHTTPErrors = cfg.IgnoreHTTPErrors
 	mc.DropServeSocket = cfg.InitiallyDropSocketFile
 	mc.ServeTCPPort = cfg.ServeTCPPort
 
 	mc.ServeSocketFile = cfg.ServeSocketFile
 	if mc.ServeSocketFile == "" {
-		mc.ServeSocketFile = fmt.Sprintf("/tmp/gh-ost.%s.%s.sock", mc.DatabaseName, mc.OriginalTableName)
+		mc.ServeSocketFile = defaultFilePath(mc, migrationId, "sock")
 	}
 
 	switch cfg.CutOver {
@@ -1,9 +1,13 @@
 package onlineddl
 
 import (
+	"path/filepath"
+	"sync/atomic"
 	"testing"
 
+	"github.com/github/gh-ost/go/base"
 	_ "github.com/pingcap/tidb/types/parser_driver"
+	"github.com/stretchr/testify/assert"
 )
 
 func Test_parseAlterTableOptions(t *testing.T) {
@@ -65,3 +69,72 @@ func Test_parseAlterTableOptions(t *testing.T) {
 		})
 	}
 }
+
+func Test_config_override(t *testing.T) {
+	ps := AdditionalParams.Copy()
+	cfg := newDefaultConfig()
+	cfg.override(ps)
+	assert.Equal(t, newDefaultConfig().MaxLagMillis, cfg.MaxLagMillis)
+	assert.Equal(t, newDefaultConfig().MaxLoad, cfg.MaxLoad)
+	assert.False(t, cfg.PostponeCutOver)
+
+	assert.NoError(t, ps.SetParamValue(ParamMaxLagMillis, "3000"))
+	assert.NoError(t, ps.SetParamValue(ParamMaxLoad, "Threads_running=30"))
+	assert.NoError(t, ps.SetParamValue(ParamThrottleControlReplicas, "10.0.0.2:3306"))
+	assert.NoError(t, ps.SetParamValue(ParamChunkSize, "500"))
+	assert.NoError(t, ps.SetParamValue(ParamPostponeCutOver, "true"))
+	cfg.override(ps)
+	assert.Equal(t, int64(3000), cfg.MaxLagMillis)
+	assert.Equal(t, "Threads_running=30", cfg.MaxLoad)
+	assert.Equal(t, "10.0.0.2:3306", cfg.ThrottleControlReplicas)
+	assert.Equal(t, int64(500), cfg.ChunkSize)
+	assert.True(t, cfg.PostponeCutOver)
+
+	mc := base.NewMigrationContext()
+	mc.InspectorConnectionConfig.Key.Hostname = "10.0.0.1"
+	mc.InspectorConnectionConfig.Key.Port = 3306
+	mc.DatabaseName = "db1"
+	mc.OriginalTableName = "t1"
+	assert.NoError(t, cfg.apply(mc, "100.1"))
+	assert.Equal(t, int64(3000), mc.MaxLagMillisecondsThrottleThreshold)
+	assert.Equal(t, int64(500), mc.ChunkSize)
+	assert.Equal(t, "/tmp/gh-ost.10.0.0.1.3306.db1.t1.100.1.postpone.flag", mc.PostponeCutOverFlagFile)
+	assert.Equal(t, "/tmp/gh-ost.10.0.0.1.3306.db1.t1.100.1.sock", mc.ServeSocketFile)
+
+	// the migration of the same table on another instance does not share the flag
+	mc2 := base.NewMigrationContext()
+	mc2.InspectorConnectionConfig.Key.Hostname = "10.0.0.2"
+	mc2.InspectorConnectionConfig.Key.Port = 3306
+	mc2.DatabaseName = "db1"
+	mc2.OriginalTableName = "t1"
+	assert.NoError(t, cfg.apply(mc2, newMigrationId()))
+	assert.NotEqual(t, mc.PostponeCutOverFlagFile, mc2.PostponeCutOverFlagFile)
+}
+
+func TestCutOver(t *testing.T) {
+	key := MigrationKey("127.0.0.1", "3306", "db1", "t1")
+	assert.Equal(t, ErrMigrationNotFound, CutOver(key))
+	assert.Equal(t, ErrMigrationNotFound, PostponeCutOver(key))
+
+	mc := base.NewMigrationContext()
+	mc.PostponeCutOverFlagFile = filepath.Join(t.TempDir(), "postpone.flag")
+	e := &Executor{mc: mc}
+	register(key, e)
+	defer unregister(key, e)
+
+	assert.NoError(t, PostponeCutOver(key))
+	assert.FileExists(t, mc.PostponeCutOverFlagFile)
+
+	progress, ok := GetProgress(key)
+	assert.True(t, ok)
+	assert.True(t, progress.CutOverPostponable)
+	assert.Equal(t, int64(-1), progress.ETASeconds)
+
+	atomic.StoreInt64(&mc.IsPostponingCutOver, 1)
+	assert.NoError(t, CutOver(key))
+	assert.NoFileExists(t, mc.PostponeCutOverFlagFile)
+	assert.Equal(t, int64(1), atomic.LoadInt64(&mc.UserCommandedUnpostponeFlag))
+
+	mc.PostponeCutOverFlagFile = ""
+	assert.Equal(t, ErrCutOverNotPostponable, PostponeCutOver(key))
+}
@@ -0,0 +1,87 @@
+package onlineddl
+
+import (
+	"github.com/actiontech/sqle/sqle/pkg/params"
+)
+
+// The additional params of instance which override the throttling and cut-over settings of gh-ost,
+// the zero value means the setting in config file or the default is used.
+const (
+	ParamMaxLagMillis              = "ghost_max_lag_millis"
+	ParamMaxLoad                   = "ghost_max_load"
+	ParamCriticalLoad              = "ghost_critical_load"
+	ParamThrottleControlReplicas   = "ghost_throttle_control_replicas"
+	ParamChunkSize                 = "ghost_chunk_size"
+	ParamCutOverLockTimeoutSeconds = "ghost_cut_over_lock_timeout_seconds"
+	ParamPostponeCutOver           = "ghost_postpone_cut_over"
+)
+
+var AdditionalParams = params.Params{
+	&params.Param{
+		Key:   ParamMaxLagMillis,
+		Value: "0",
+		Desc:  "gh-ost 允许的最大从库延迟（毫秒），超过时暂停拷贝数据，0 表示使用配置文件中的值",
+		Type:  params.ParamTypeInt,
+	},
+	&params.Param{
+		Key:   ParamMaxLoad,
+		Value: "",
+		Desc:  "gh-ost 负载阈值，超过时暂停拷贝数据，例如 Threads_running=80，为空表示使用配置文件中的值",
+		Type:  params.ParamTypeString,
+	},
+	&params.Param{
+		Key:   ParamCriticalLoad,
+		Value: "",
+		Desc:  "gh-ost 危险负载阈值，超过时中止上线，例如 Threads_running=200，为空表示使用配置文件中的值",
+		Type:  params.ParamTypeString,
+	},
+	&params.Param{
+		Key:   ParamThrottleControlReplicas,
+		Value: "",
+		Desc:  "gh-ost 检查延迟的从库地址，格式为 host:port，多个地址用逗号分隔，为空表示使用配置文件中的值",
+		Type:  params.ParamTypeString,
+	},
+	&params.Param{
+		Key:   ParamChunkSize,
+		Value: "0",
+		Desc:  "gh-ost 每批拷贝的行数，0 表示使用配置文件中的值",
+		Type:  params.ParamTypeInt,
+	},
+	&params.Param{
+		Key:   ParamCutOverLockTimeoutSeconds,
+		Value: "0",
+		Desc:  "gh-ost 切换表时的锁超时（秒），0 表示使用配置文件中的值",
+		Type:  params.ParamTypeInt,
+	},
+	&params.Param{
+		Key:   ParamPostponeCutOver,
+		Value: "false",
+		Desc:  "gh-ost 拷贝数据完成后推迟切换表，需要在工单中手动触发切换",
+		Type:  params.ParamTypeBool,
+	},
+}
+
+// override overrides the config by the additional params of instance.
+func (cfg *config) override(ps params.Params) {
+	if v := ps.GetParam(ParamMaxLagMillis).Int(); v > 0 {
+		cfg.MaxLagMillis = int64(v)
+	}
+	if v := ps.GetParam(ParamMaxLoad).String(); v != "" {
+		cfg.MaxLoad = v
+	}
+	if v := ps.GetParam(ParamCriticalLoad).String(); v != "" {
+		cfg.CriticalLoad = v
+	}
+	if v := ps.GetParam(ParamThrottleControlReplicas).String(); v != "" {
+		cfg.ThrottleControlReplicas = v
+	}
+	if v := ps.GetParam(ParamChunkSize).Int(); v > 0 {
+		cfg.ChunkSize = int64(v)
+	}
+	if v := ps.GetParam(ParamCutOverLockTimeoutSeconds).Int(); v > 0 {
+		cfg.CutOverLockTimeoutSeconds = int64(v)
+	}
+	if ps.GetParam(ParamPostponeCutOver).Bool() {
+		cfg.PostponeCutOver = true
+	}
+}
@@ -0,0 +1,125 @@
+package onlineddl
+
+import (
+	"fmt"
+	"os"
+	"sync"
+	"sync/atomic"
+
+	"github.com/github/gh-ost/go/base"
+)
+
+var (
+	migrationsMutex sync.Mutex
+	// migrations keeps the running migrations, the key is generated by MigrationKey.
+	migrations = map[string]*Executor{}
+)
+
+// MigrationKey returns the key of the migration of the table, there is at most one running
+// migration of a table since gh-ost holds the voluntary lock of the table.
+func MigrationKey(host, port, schema, table string) string {
+	return fmt.Sprintf("%s:%s/%s.%s", host, port, schema, table)
+}
+
+func register(key string, e *Executor) {
+	migrationsMutex.Lock()
+	migrations[key] = e
+	migrationsMutex.Unlock()
+}
+
+func unregister(key string, e *Executor) {
+	migrationsMutex.Lock()
+	if migrations[key] == e {
+		delete(migrations, key)
+	}
+	migrationsMutex.Unlock()
+}
+
+func getMigration(key string) (*Executor, bool) {
+	migrationsMutex.Lock()
+	defer migrationsMutex.Unlock()
+	e, ok := migrations[key]
+	return e, ok
+}
+
+// Progress is the live status of the running migration.
+type Progress struct {
+	RowsCopied   int64
+	RowsEstimate int64
+	ProgressPct  float64
+	// ETASeconds is -1 if it is unknown.
+	ETASeconds        int64
+	LagMillis         int64
+	Throttled         bool
+	ThrottleReason    string
+	PostponingCutOver bool
+	// CutOverPostponable is true if the migration is started with the postpone cut-over flag file.
+	CutOverPostponable bool
+}
+
+// GetProgress returns false if there is no running migration of the key.
+func GetProgress(key string) (*Progress, bool) {
+	e, ok := getMigration(key)
+	if !ok {
+		return nil, false
+	}
+	return e.Progress(), true
+}
+
+func (e *Executor) Progress() *Progress {
+	mc := e.mc
+	throttled, reason, _ := mc.IsThrottled()
+	p := &Progress{
+		RowsCopied:         mc.GetTotalRowsCopied(),
+		RowsEstimate:       atomic.LoadInt64(&mc.RowsEstimate) + atomic.LoadInt64(&mc.RowsDeltaEstimate),
+		ProgressPct:        mc.GetProgressPct(),
+		ETASeconds:         mc.GetETASeconds(),
+		LagMillis:          mc.GetCurrentLagDuration().Milliseconds(),
+		Throttled:          throttled,
+		ThrottleReason:     reason,
+		PostponingCutOver:  atomic.LoadInt64(&mc.IsPostponingCutOver) > 0,
+		CutOverPostponable: mc.PostponeCutOverFlagFile != "",
+	}
+	if p.ETASeconds == base.ETAUnknown {
+		p.ETASeconds = -1
+	}
+	return p
+}
+
+var ErrMigrationNotFound = fmt.Errorf("there is no running gh-ost migration")
+var ErrCutOverNotPostponable = fmt.Errorf("the gh-ost migration is not started with postponed cut-over")
+
+// CutOver triggers the cut-over of the migration which is postponed, the cut-over happens as soon as
+// the rows are copied if it is called before the copy is completed.
+func CutOver(key string) error {
+	e, ok := getMigration(key)
+	if !ok {
+		return ErrMigrationNotFound
+	}
+	mc := e.mc
+	if mc.PostponeCutOverFlagFile == "" {
+		return nil
+	}
+	if err := os.Remove(mc.PostponeCutOverFlagFile); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	if atomic.LoadInt64(&mc.IsPostponingCutOver) > 0 {
+		atomic.StoreInt64(&mc.UserCommandedUnpostponeFlag, 1)
+	}
+	return nil
+}
+
+// PostponeCutOver postpones the cut-over of the migration again after CutOver is called, it takes no
+// effect if the cut-over is in progress.
+func PostponeCutOver(key string) error {
+	e, ok := getMigration(key)
+	if !ok {
+		return ErrMigrationNotFound
+	}
+	mc := e.mc
+	if mc.PostponeCutOverFlagFile == "" {
+		return ErrCutOverNotPostponable
+	}
+	atomic.StoreInt64(&mc.UserCommandedUnpostponeFlag, 0)
+	return base.TouchFile(mc.PostponeCutOverFlagFile)
+}
@@ -48,6 +48,30 @@ func (sa *selectAST) EqualPredicateColumnsInWhere() []string {
 	return columns
 }
 
+func (sa *selectAST) RangePredicateColumnsInWhere() []string {
+	var columns []string
+
+	util.ScanWhereStmt(func(expr ast.ExprNode) (skip bool) {
+		switch x := expr.(type) {
+		case *ast.BinaryOperationExpr:
+			switch x.Op {
+			case opcode.GT, opcode.GE, opcode.LT, opcode.LE:
+				if col, ok := x.L.(*ast.ColumnNameExpr); ok {
+					columns = append(columns, col.Name.Name.L)
+				}
+			}
+		case *ast.BetweenExpr:
+			if col, ok := x.Expr.(*ast.ColumnNameExpr); ok && !x.Not {
+				columns = append(columns, col.Name.Name.L)
+			}
+		}
+
+		return false
+	}, sa.selectStmt.Where)
+
+	return columns
+}
+
 func (sa *selectAST) ColumnsInOrderBy() []string {
 	var columns []string
 
@@ -14,12 +14,14 @@ func Test_selectAST(t *testing.T) {
 		input      string
 		orderBy    []string
 		whereEqual []string
+		whereRange []string
 		projection []string
 	}{
-		{"select id, a from t where a = 1 and b = 1 order by b", []string{"b"}, []string{"a", "b"}, []string{"id", "a"}},
-		{"select * from t order by a desc", []string{"a"}, nil, nil},
-		{"select * from t", nil, nil, nil},
-		{"select t.id = 1 from t where a > 1", nil, nil, nil},
+		{"select id, a from t where a = 1 and b = 1 order by b", []string{"b"}, []string{"a", "b"}, nil, []string{"id", "a"}},
+		{"select * from t order by a desc", []string{"a"}, nil, nil, nil},
+		{"select * from t", nil, nil, nil, nil},
+		{"select t.id = 1 from t where a > 1", nil, nil, []string{"a"}, nil},
+		{"select a from t where a = 1 and b <= 2 and c between 1 and 2 and d not between 1 and 2", nil, []string{"a"}, []string{"b", "c"}, []string{"a"}},
 	}
 	for i, tt := range tests {
 		t.Run(fmt.Sprintf("%d", i), func(t *testing.T) {
@@ -27,6 +29,7 @@ func Test_selectAST(t *testing.T) {
 			assert.NoError(t, err)
 			assert.Equal(t, tt.orderBy, sa.ColumnsInOrderBy())
 			assert.Equal(t, tt.whereEqual, sa.EqualPredicateColumnsInWhere())
+			assert.Equal(t, tt.whereRange, sa.RangePredicateColumnsInWhere())
 			assert.Equal(t, tt.projection, sa.ColumnsInProjection())
 		})
 	}
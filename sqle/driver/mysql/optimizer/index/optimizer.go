@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"reflect"
+	"regexp"
 	"sort"
 	"strings"
 
@@ -13,6 +14,7 @@ import (
 	"github.com/actiontech/sqle/sqle/driver/mysql/util"
 	"github.com/actiontech/sqle/sqle/log"
 	indexoptimizer "github.com/actiontech/sqle/sqle/pkg/optimizer/index"
+	"github.com/actiontech/sqle/sqle/utils"
 	"github.com/pingcap/parser/ast"
 	"github.com/pingcap/parser/format"
 	driver "github.com/pingcap/tidb/types/parser_driver"
@@ -93,8 +95,22 @@ func NewOptimizer(log *logrus.Entry, ctx *session.Context, opts ...optimizerOpti
 type OptimizeResult struct {
 	TableName      string
 	IndexedColumns []string
+	// CoveringIndexColumns is the columns of the covering index which makes
+	// the query index only, it is empty if the query can not be covered.
+	CoveringIndexColumns []string
+
+	CreateIndexSQL   string
+	CoveringIndexSQL string
+
+	// EstimatedRows is the rows examined by the table in execution plan.
+	EstimatedRows int64
 
 	Reason string
+
+	// sortableColumnCount is the count of the leading indexed columns which
+	// can be sorted by cardinality, e.g. the columns of equal predicates.
+	sortableColumnCount int
+	selectAST           indexoptimizer.SelectAST
 }
 
 // tableInSelect store the information of a table in select statement for later optimize.
@@ -129,9 +145,11 @@ func (o *Optimizer) Optimize(ctx context.Context, selectStmt *ast.SelectStmt) ([
 	executionPlan = removeDrivingTable(executionPlan)
 
 	var needOptimizedTables []string
+	estimatedRows := map[string]int64{}
 	for _, record := range executionPlan {
 		if o.needOptimize(record) {
 			needOptimizedTables = append(needOptimizedTables, record.Table)
+			estimatedRows[record.Table] = record.Rows
 		}
 	}
 
@@ -159,6 +177,8 @@ func (o *Optimizer) Optimize(ctx context.Context, selectStmt *ast.SelectStmt) ([
 			result = o.optimizeJoinTable(tbl)
 		}
 		if result != nil {
+			result.EstimatedRows = estimatedRows[tbl]
+			o.generateCreateIndexSQL(result)
 			results = append(results, result)
 		}
 	}
@@ -166,6 +186,28 @@ func (o *Optimizer) Optimize(ctx context.Context, selectStmt *ast.SelectStmt) ([
 	return results, nil
 }
 
+var plainColumnRegexp = regexp.MustCompile(`^\w+$`)
+
+// generateCreateIndexSQL generates the DDL of the index advice, the advice on
+// expression, e.g. the function index, can not be created directly.
+func (o *Optimizer) generateCreateIndexSQL(result *OptimizeResult) {
+	for _, column := range result.CoveringIndexColumns {
+		if !plainColumnRegexp.MatchString(column) {
+			return
+		}
+	}
+	for _, column := range result.IndexedColumns {
+		if !plainColumnRegexp.MatchString(column) {
+			return
+		}
+	}
+
+	result.CreateIndexSQL = o.createIndexStatement(result.TableName, result.IndexedColumns...)
+	if len(result.CoveringIndexColumns) > 0 {
+		result.CoveringIndexSQL = o.createIndexStatement(result.TableName, result.CoveringIndexColumns...)
+	}
+}
+
 // SelectStmt:
 //   1. single select on single table
 //   2. single select on multiple tables, such join
@@ -251,6 +293,9 @@ func (o *Optimizer) optimizeSingleTable(ctx context.Context, tbl string, ss *ast
 	if len(optimizeResult.IndexedColumns) > o.compositeIndexMaxColumn {
 		optimizeResult.IndexedColumns = optimizeResult.IndexedColumns[:o.compositeIndexMaxColumn]
 	}
+	if optimizeResult.sortableColumnCount > len(optimizeResult.IndexedColumns) {
+		optimizeResult.sortableColumnCount = len(optimizeResult.IndexedColumns)
+	}
 
 	needIndex, err := o.needIndex(optimizeResult.TableName, optimizeResult.IndexedColumns...)
 	if err != nil {
@@ -263,7 +308,7 @@ func (o *Optimizer) optimizeSingleTable(ctx context.Context, tbl string, ss *ast
 
 	o.l.Infof("table:%s, indexed columns:%v, reason:%s", optimizeResult.TableName, optimizeResult.IndexedColumns, optimizeResult.Reason)
 
-	if len(optimizeResult.IndexedColumns) > 1 {
+	if optimizeResult.sortableColumnCount > 1 {
 		tableNameFromAST, err := extractTableNameFromAST(ss, tbl)
 		if err != nil {
 			return nil, errors.Wrap(err, "extract table name from AST")
@@ -275,10 +320,21 @@ func (o *Optimizer) optimizeSingleTable(ctx context.Context, tbl string, ss *ast
 		}
 
 		if rowCount < o.calculateCardinalityMaxRow {
-			optimizeResult.IndexedColumns, err = o.sortColumnsByCardinality(tableNameFromAST, optimizeResult.IndexedColumns)
+			// only the leading columns can be reordered, the range and order by
+			// columns must follow them to keep the index usable.
+			count := optimizeResult.sortableColumnCount
+			sortedColumns, err := o.sortColumnsByCardinality(tableNameFromAST, optimizeResult.IndexedColumns[:count])
 			if err != nil {
 				return nil, err
 			}
+			optimizeResult.IndexedColumns = append(sortedColumns, optimizeResult.IndexedColumns[count:]...)
+		}
+	}
+
+	if optimizeResult.selectAST != nil {
+		covering := indexoptimizer.NewOptimizer(o.Context).CoveringColumns(optimizeResult.selectAST, optimizeResult.IndexedColumns)
+		if len(covering) <= o.compositeIndexMaxColumn {
+			optimizeResult.CoveringIndexColumns = covering
 		}
 	}
 
@@ -317,9 +373,10 @@ func (o *Optimizer) doSpecifiedOptimization(ctx context.Context, selectStmt *ast
 					if !strings.HasPrefix(datum, "%") &&
 						!strings.HasPrefix(datum, "_") {
 						return &OptimizeResult{
-							TableName:      getTableNameFromSingleSelect(selectStmt),
-							IndexedColumns: []string{cne.Name.Name.L},
-							Reason:         "为前缀模式匹配添加前缀索引",
+							TableName:           getTableNameFromSingleSelect(selectStmt),
+							IndexedColumns:      []string{cne.Name.Name.L},
+							Reason:              "为前缀模式匹配添加前缀索引",
+							sortableColumnCount: 1,
 						}, nil
 					}
 				}
@@ -346,9 +403,10 @@ func (o *Optimizer) doSpecifiedOptimization(ctx context.Context, selectStmt *ast
 		}
 		if len(cols) > 0 {
 			return &OptimizeResult{
-				TableName:      getTableNameFromSingleSelect(selectStmt),
-				IndexedColumns: cols,
-				Reason:         "利用索引有序的性质快速找到最值",
+				TableName:           getTableNameFromSingleSelect(selectStmt),
+				IndexedColumns:      cols,
+				Reason:              "利用索引有序的性质快速找到最值",
+				sortableColumnCount: len(cols),
 			}, nil
 		}
 	}
@@ -424,10 +482,21 @@ func (o *Optimizer) doGeneralOptimization(ctx context.Context, selectStmt *ast.S
 
 	o.l.Infof("general optimize result: %v(index columns)", indexedColumns)
 
+	equalColumns := sa.EqualPredicateColumnsInWhere()
+	sortableColumnCount := 0
+	for _, column := range indexedColumns {
+		if !utils.StringsContains(equalColumns, column) {
+			break
+		}
+		sortableColumnCount++
+	}
+
 	return &OptimizeResult{
-		TableName:      getTableNameFromSingleSelect(selectStmt),
-		IndexedColumns: indexedColumns,
-		Reason:         "三星索引建议",
+		TableName:           getTableNameFromSingleSelect(selectStmt),
+		IndexedColumns:      indexedColumns,
+		Reason:              "三星索引建议",
+		sortableColumnCount: sortableColumnCount,
+		selectAST:           sa,
 	}, nil
 }
 
@@ -65,7 +65,7 @@ func TestOptimizer_Optimize(t *testing.T) {
 				{"EXPLAIN", [][]string{explainHead, {"1", "exist_tb_3", executor.ExplainRecordAccessTypeAll}}},
 			},
 			nil,
-			[]*OptimizeResult{{"exist_tb_3", []string{"v1"}, ""}},
+			[]*OptimizeResult{{TableName: "exist_tb_3", IndexedColumns: []string{"v1"}}},
 		},
 		{
 			"select * from exist_tb_3 where v1 = 1",
@@ -73,7 +73,7 @@ func TestOptimizer_Optimize(t *testing.T) {
 				{"EXPLAIN", [][]string{explainHead, {"1", "exist_tb_3", executor.ExplainRecordAccessTypeIndex}}},
 			},
 			nil,
-			[]*OptimizeResult{{"exist_tb_3", []string{"v1"}, ""}},
+			[]*OptimizeResult{{TableName: "exist_tb_3", IndexedColumns: []string{"v1"}}},
 		},
 		{
 			"select * from exist_tb_3 where v1 = 1 and v2 = 2 and v3 > 3",
@@ -84,7 +84,7 @@ func TestOptimizer_Optimize(t *testing.T) {
 				{"select count(distinct `v2`)", [][]string{cardinalityHead, {"101"}}},
 			},
 			nil,
-			[]*OptimizeResult{{"exist_tb_3", []string{"v2", "v1"}, ""}},
+			[]*OptimizeResult{{TableName: "exist_tb_3", IndexedColumns: []string{"v2", "v1", "v3"}}},
 		},
 
 		{
@@ -96,7 +96,7 @@ func TestOptimizer_Optimize(t *testing.T) {
 				{"select count(distinct `v2`)", [][]string{cardinalityHead, {"100"}}},
 			},
 			nil,
-			[]*OptimizeResult{{"exist_tb_3", []string{"v1", "v2"}, ""}},
+			[]*OptimizeResult{{TableName: "exist_tb_3", IndexedColumns: []string{"v1", "v2", "v3"}}},
 		},
 		{
 			"select v1,v2,v3 from exist_tb_3 where v2 = 1 and v1 = 2 and v3 > 3",
@@ -107,7 +107,7 @@ func TestOptimizer_Optimize(t *testing.T) {
 				{"select count(distinct `v1`)", [][]string{cardinalityHead, {"101"}}},
 			},
 			nil,
-			[]*OptimizeResult{{"exist_tb_3", []string{"v2", "v1"}, ""}},
+			[]*OptimizeResult{{TableName: "exist_tb_3", IndexedColumns: []string{"v2", "v1", "v3"}}},
 		},
 		{
 			"select v1,v2,v3 from exist_tb_3 where v2 = 1 and v1 = 2 and v3 > 3",
@@ -118,7 +118,7 @@ func TestOptimizer_Optimize(t *testing.T) {
 				{"select count(distinct `v1`)", [][]string{cardinalityHead, {"101"}}},
 			},
 			[]optimizerOption{WithCompositeIndexMaxColumn(2)},
-			[]*OptimizeResult{{"exist_tb_3", []string{"v2", "v1"}, ""}},
+			[]*OptimizeResult{{TableName: "exist_tb_3", IndexedColumns: []string{"v2", "v1"}}},
 		},
 		// multi table, single select
 		{
@@ -127,7 +127,7 @@ func TestOptimizer_Optimize(t *testing.T) {
 				{"EXPLAIN", [][]string{explainHead, {"1", "exist_tb_1", executor.ExplainRecordAccessTypeAll}, {"1", "exist_tb_2", executor.ExplainRecordAccessTypeAll}}},
 			},
 			nil,
-			[]*OptimizeResult{{"exist_tb_2", []string{"v1"}, ""}},
+			[]*OptimizeResult{{TableName: "exist_tb_2", IndexedColumns: []string{"v1"}}},
 		},
 		{
 			"select * from exist_tb_1 join exist_tb_2 on exist_tb_1.v1 = exist_tb_2.v1",
@@ -143,7 +143,7 @@ func TestOptimizer_Optimize(t *testing.T) {
 				{"EXPLAIN", [][]string{explainHead, {"1", "exist_tb_1", executor.ExplainRecordAccessTypeAll}, {"1", "exist_tb_2", executor.ExplainRecordAccessTypeAll}}},
 			},
 			nil,
-			[]*OptimizeResult{{"exist_tb_2", []string{"v1"}, ""}},
+			[]*OptimizeResult{{TableName: "exist_tb_2", IndexedColumns: []string{"v1"}}},
 		},
 		// will not give advice when join without condition
 		{
@@ -177,7 +177,7 @@ func TestOptimizer_Optimize(t *testing.T) {
 				{"EXPLAIN", [][]string{explainHead, {"1", "exist_tb_2", executor.ExplainRecordAccessTypeIndex}}},
 			},
 			nil,
-			[]*OptimizeResult{{"exist_tb_2", []string{"v1"}, ""}},
+			[]*OptimizeResult{{TableName: "exist_tb_2", IndexedColumns: []string{"v1"}}},
 		},
 		{
 			"select * from exist_tb_2 where left(v3, 5) = 'hello'",
@@ -195,7 +195,7 @@ func TestOptimizer_Optimize(t *testing.T) {
 				{"SHOW GLOBAL VARIABLES", [][]string{showGlobalVariableHead, {"version", "5.7.3"}}},
 			},
 			nil,
-			[]*OptimizeResult{{"exist_tb_2", []string{"LEFT(`v3`, 5)"}, ""}},
+			[]*OptimizeResult{{TableName: "exist_tb_2", IndexedColumns: []string{"LEFT(`v3`, 5)"}}},
 		},
 		{
 			"select * from exist_tb_2 where left(v3, 5) = 'hello'",
@@ -204,7 +204,7 @@ func TestOptimizer_Optimize(t *testing.T) {
 				{"SHOW GLOBAL VARIABLES", [][]string{showGlobalVariableHead, {"version", "8.0.14"}}},
 			},
 			nil,
-			[]*OptimizeResult{{"exist_tb_2", []string{"LEFT(`v3`, 5)"}, ""}},
+			[]*OptimizeResult{{TableName: "exist_tb_2", IndexedColumns: []string{"LEFT(`v3`, 5)"}}},
 		},
 		{
 			"select * from exist_tb_2 where v3 like 'mike%'",
@@ -212,7 +212,7 @@ func TestOptimizer_Optimize(t *testing.T) {
 				{"EXPLAIN", [][]string{explainHead, {"1", "exist_tb_2", executor.ExplainRecordAccessTypeIndex}}},
 			},
 			nil,
-			[]*OptimizeResult{{"exist_tb_2", []string{"v3"}, ""}},
+			[]*OptimizeResult{{TableName: "exist_tb_2", IndexedColumns: []string{"v3"}}},
 		},
 		{
 			"select * from exist_tb_2 where v3 like '_mike%'",
@@ -236,7 +236,7 @@ func TestOptimizer_Optimize(t *testing.T) {
 				{"EXPLAIN", [][]string{explainHead, {"1", "exist_tb_2", executor.ExplainRecordAccessTypeIndex}}},
 			},
 			nil,
-			[]*OptimizeResult{{"exist_tb_2", []string{"v1"}, ""}},
+			[]*OptimizeResult{{TableName: "exist_tb_2", IndexedColumns: []string{"v1"}}},
 		},
 
 		{
@@ -245,7 +245,7 @@ func TestOptimizer_Optimize(t *testing.T) {
 				{"EXPLAIN", [][]string{explainHead, {"1", "exist_tb_3", executor.ExplainRecordAccessTypeAll}}},
 			},
 			nil,
-			[]*OptimizeResult{{"exist_tb_3", []string{"v3"}, ""}},
+			[]*OptimizeResult{{TableName: "exist_tb_3", IndexedColumns: []string{"v3"}}},
 		},
 
 		{
@@ -254,7 +254,7 @@ func TestOptimizer_Optimize(t *testing.T) {
 				{"EXPLAIN", [][]string{explainHead, {"1", "exist_tb_3", executor.ExplainRecordAccessTypeAll}}},
 			},
 			nil,
-			[]*OptimizeResult{{"exist_tb_3", []string{"v3"}, ""}},
+			[]*OptimizeResult{{TableName: "exist_tb_3", IndexedColumns: []string{"v3"}}},
 		},
 		{
 			"select sum(v3) from exist_tb_3",
@@ -271,7 +271,7 @@ func TestOptimizer_Optimize(t *testing.T) {
 				{"EXPLAIN", [][]string{explainHead, {"1", "EXIST_TB_5", executor.ExplainRecordAccessTypeAll}}},
 			},
 			nil,
-			[]*OptimizeResult{{"EXIST_TB_5", []string{"v1"}, ""}},
+			[]*OptimizeResult{{TableName: "EXIST_TB_5", IndexedColumns: []string{"v1"}}},
 		},
 		{
 			"select * from EXIST_TB_5 join exist_tb_3 on EXIST_TB_5.v1 = exist_tb_3.v1",
@@ -279,7 +279,7 @@ func TestOptimizer_Optimize(t *testing.T) {
 				{"EXPLAIN", [][]string{explainHead, {"1", "exist_tb_3", executor.ExplainRecordAccessTypeAll}, {"1", "EXIST_TB_5", executor.ExplainRecordAccessTypeAll}}},
 			},
 			nil,
-			[]*OptimizeResult{{"EXIST_TB_5", []string{"v1"}, ""}},
+			[]*OptimizeResult{{TableName: "EXIST_TB_5", IndexedColumns: []string{"v1"}}},
 		},
 		{
 			"select v1,v2 from EXIST_TB_5 as tb5 where v2 = '1'",
@@ -287,7 +287,7 @@ func TestOptimizer_Optimize(t *testing.T) {
 				{"EXPLAIN", [][]string{explainHead, {"1", "tb5", executor.ExplainRecordAccessTypeAll}}},
 			},
 			nil,
-			[]*OptimizeResult{{"EXIST_TB_5", []string{"v2"}, ""}},
+			[]*OptimizeResult{{TableName: "EXIST_TB_5", IndexedColumns: []string{"v2"}}},
 		},
 		{
 			"select v2 from EXIST_TB_5 as tb5 where v1 = '1' and v2 = '1'",
@@ -298,7 +298,7 @@ func TestOptimizer_Optimize(t *testing.T) {
 				{"select count(distinct `v2`)", [][]string{cardinalityHead, {"1000"}}},
 			},
 			nil,
-			[]*OptimizeResult{{"EXIST_TB_5", []string{"v2","v1"}, ""}},
+			[]*OptimizeResult{{TableName: "EXIST_TB_5", IndexedColumns: []string{"v2","v1"}}},
 		},
 		{
 			"select v2 from EXIST_TB_5 as tb5 where v1 = '1'",
@@ -306,7 +306,7 @@ func TestOptimizer_Optimize(t *testing.T) {
 				{"EXPLAIN", [][]string{explainHead, {"1", "tb5", executor.ExplainRecordAccessTypeAll}}},
 			},
 			nil,
-			[]*OptimizeResult{{"EXIST_TB_5", []string{"v1"}, ""}},
+			[]*OptimizeResult{{TableName: "EXIST_TB_5", IndexedColumns: []string{"v1"}}},
 		},
 		// issue:690 https://github.com/actiontech/sqle/issues/690
 		{
@@ -316,7 +316,7 @@ func TestOptimizer_Optimize(t *testing.T) {
 				{"show table status", [][]string{showTableStatusHead, {"EXIST_TB_5", "10000000"}}},
 			},
 			nil,
-			[]*OptimizeResult{{"EXIST_TB_5", []string{"v1", "v2"}, ""}},
+			[]*OptimizeResult{{TableName: "EXIST_TB_5", IndexedColumns: []string{"v1", "v2"}}},
 		},
 		{
 			"select id from EXIST_TB_5 where (v1 = '1') and (v2 = '2')",
@@ -325,7 +325,7 @@ func TestOptimizer_Optimize(t *testing.T) {
 				{"show table status", [][]string{showTableStatusHead, {"EXIST_TB_5", "10000000"}}},
 			},
 			nil,
-			[]*OptimizeResult{{"EXIST_TB_5", []string{"v1", "v2"}, ""}},
+			[]*OptimizeResult{{TableName: "EXIST_TB_5", IndexedColumns: []string{"v1", "v2"}}},
 		},
 		{
 			"select id from EXIST_TB_5 where (v1 = '1' and v2 = '2')",
@@ -334,7 +334,7 @@ func TestOptimizer_Optimize(t *testing.T) {
 				{"show table status", [][]string{showTableStatusHead, {"EXIST_TB_5", "10000000"}}},
 			},
 			nil,
-			[]*OptimizeResult{{"EXIST_TB_5", []string{"v1", "v2"}, ""}},
+			[]*OptimizeResult{{TableName: "EXIST_TB_5", IndexedColumns: []string{"v1", "v2"}}},
 		},
 	}
 
@@ -372,6 +372,75 @@ func TestOptimizer_Optimize(t *testing.T) {
 	}
 }
 
+func TestOptimizer_OptimizeAdvice(t *testing.T) {
+	entry := testLogger.WithFields(logrus.Fields{"test": "optimizer"})
+
+	tests := []struct {
+		SQL     string
+		explain []string
+		output  *OptimizeResult
+	}{
+		{
+			"select v1, v2 from exist_tb_3 where v3 > 1 order by v2",
+			[]string{"1", "exist_tb_3", executor.ExplainRecordAccessTypeAll, "1000"},
+			&OptimizeResult{
+				TableName:            "exist_tb_3",
+				IndexedColumns:       []string{"v3", "v2"},
+				CoveringIndexColumns: []string{"v3", "v2", "v1"},
+				CreateIndexSQL:       "CREATE INDEX idx_exist_tb_3_v3_v2 ON exist_tb_3 (v3, v2)",
+				CoveringIndexSQL:     "CREATE INDEX idx_exist_tb_3_v3_v2_v1 ON exist_tb_3 (v3, v2, v1)",
+				EstimatedRows:        1000,
+			},
+		},
+		{
+			"select * from exist_tb_3 where v3 between 1 and 2 and v1 > 1",
+			[]string{"1", "exist_tb_3", executor.ExplainRecordAccessTypeAll, "20"},
+			&OptimizeResult{
+				TableName:      "exist_tb_3",
+				IndexedColumns: []string{"v3"},
+				CreateIndexSQL: "CREATE INDEX idx_exist_tb_3_v3 ON exist_tb_3 (v3)",
+				EstimatedRows:  20,
+			},
+		},
+		{
+			"select id, v1, v2, v3 from exist_tb_3 where v1 = 1",
+			[]string{"1", "exist_tb_3", executor.ExplainRecordAccessTypeAll, "20"},
+			&OptimizeResult{
+				TableName:      "exist_tb_3",
+				IndexedColumns: []string{"v1"},
+				CreateIndexSQL: "CREATE INDEX idx_exist_tb_3_v1 ON exist_tb_3 (v1)",
+				EstimatedRows:  20,
+			},
+		},
+	}
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("%d", i), func(t *testing.T) {
+			ss, err := parser.New().ParseOneStmt(tt.SQL, "", "")
+			assert.NoError(t, err)
+			e, mocker, err := executor.NewMockExecutor()
+			assert.NoError(t, err)
+
+			mocker.ExpectQuery(regexp.QuoteMeta("EXPLAIN")).WillReturnRows(
+				sqlmock.NewRows([]string{"id", "table", "type", "rows"}).
+					AddRow(tt.explain[0], tt.explain[1], tt.explain[2], tt.explain[3]))
+
+			o := NewOptimizer(entry, session.NewMockContext(e))
+			optimizeResults, err := o.Optimize(context.TODO(), ss.(*ast.SelectStmt))
+			assert.NoError(t, err)
+			if assert.Len(t, optimizeResults, 1) {
+				result := optimizeResults[0]
+				assert.Equal(t, tt.output.TableName, result.TableName)
+				assert.Equal(t, tt.output.IndexedColumns, result.IndexedColumns)
+				assert.Equal(t, tt.output.CoveringIndexColumns, result.CoveringIndexColumns)
+				assert.Equal(t, tt.output.CreateIndexSQL, result.CreateIndexSQL)
+				assert.Equal(t, tt.output.CoveringIndexSQL, result.CoveringIndexSQL)
+				assert.Equal(t, tt.output.EstimatedRows, result.EstimatedRows)
+			}
+			assert.NoError(t, mocker.ExpectationsWereMet())
+		})
+	}
+}
+
 func TestOptimizer_parseSelectStmt(t *testing.T) {
 	t.Parallel()
 	tests := []struct {
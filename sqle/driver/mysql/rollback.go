@@ -11,6 +11,7 @@ import (
 
 	"github.com/pingcap/parser/ast"
 	_model "github.com/pingcap/parser/model"
+	driver "github.com/pingcap/tidb/types/parser_driver"
 )
 
 func (i *MysqlDriverImpl) GenerateRollbackSql(node ast.Node) (string, string, error) {
@@ -73,6 +74,8 @@ const (
 	NotSupportInsertWithoutPrimaryKeyRollback = "不支持回滚 INSERT 没有指定主键的语句"
 	NotSupportParamMarkerStatementRollback    = "不支持回滚包含指纹的语句"
 	NotSupportExceedMaxRowsRollback           = "预计影响行数超过配置的最大值，不生成回滚语句"
+	NotSupportUpdatePrimaryKeyRollback        = "暂不支持回滚修改主键的多表 UPDATE 语句"
+	NotSupportInsertSelectAllColumnRollback   = "暂不支持回滚 INSERT ... SELECT * 语句"
 )
 
 // generateAlterTableRollbackSql generate alter table SQL for alter table.
@@ -368,9 +371,6 @@ func (i *MysqlDriverImpl) generateInsertRollbackSql(stmt *ast.InsertStmt) (strin
 	if len(tables) != 1 {
 		return "", NotSupportMultiTableStatementRollback, nil
 	}
-	if stmt.OnDuplicate != nil {
-		return "", NotSupportOnDuplicatStatementRollback, nil
-	}
 	table := tables[0]
 	createTableStmt, exist, err := i.Ctx.GetCreateTableStmt(table)
 	if err != nil {
@@ -388,6 +388,15 @@ func (i *MysqlDriverImpl) generateInsertRollbackSql(stmt *ast.InsertStmt) (strin
 		return "", NotSupportNoPrimaryKeyTableRollback, nil
 	}
 
+	// match "replace into ..." and "insert into ... on duplicate key update ..."
+	if stmt.IsReplace || stmt.OnDuplicate != nil {
+		return i.generateUpsertRollbackSql(stmt, table, createTableStmt, pkColumnsName)
+	}
+	// match "insert into table_name (column_name,...) select ..."
+	if stmt.Select != nil {
+		return i.generateInsertSelectRollbackSql(stmt, table, createTableStmt, pkColumnsName)
+	}
+
 	rollbackSql := ""
 
 	// match "insert into table_name (column_name,...) value (v1,...)"
@@ -451,16 +460,15 @@ func (i *MysqlDriverImpl) generateInsertRollbackSql(stmt *ast.InsertStmt) (strin
 
 // generateDeleteRollbackSql generate insert SQL for delete.
 func (i *MysqlDriverImpl) generateDeleteRollbackSql(stmt *ast.DeleteStmt) (string, string, error) {
-	// not support multi-table syntax
-	if stmt.IsMultiTable {
-		i.Logger().Infof("not support generate rollback sql with multi-delete statement")
-		return "", NotSupportMultiTableStatementRollback, nil
-	}
 	// sub query statement
 	if util.WhereStmtHasSubQuery(stmt.Where) {
 		i.Logger().Infof("not support generate rollback sql with sub query")
 		return "", NotSupportSubQueryStatementRollback, nil
 	}
+	// multi-table syntax, e.g. "delete t1, t2 from t1 join t2 ..."
+	if stmt.IsMultiTable {
+		return i.generateMultiTableDeleteRollbackSql(stmt)
+	}
 	var err error
 	tables := util.GetTables(stmt.TableRefs.TableRefs)
 	table := tables[0]
@@ -494,6 +502,16 @@ func (i *MysqlDriverImpl) generateDeleteRollbackSql(stmt *ast.DeleteStmt) (strin
 	if err != nil {
 		return "", "", err
 	}
+	rollbackSql, ok := i.generateInsertSqlFromRecords(table, createTableStmt, records)
+	if !ok {
+		return "", "", nil
+	}
+	return rollbackSql, "", nil
+}
+
+// generateInsertSqlFromRecords generate insert SQL which restores the records of table.
+func (i *MysqlDriverImpl) generateInsertSqlFromRecords(table *ast.TableName, createTableStmt *ast.CreateTableStmt,
+	records []map[string]sql.NullString) (string, bool) {
 	values := []string{}
 
 	columnsName := []string{}
@@ -502,15 +520,11 @@ func (i *MysqlDriverImpl) generateDeleteRollbackSql(stmt *ast.DeleteStmt) (strin
 	}
 	for _, record := range records {
 		if len(record) != len(columnsName) {
-			return "", "", nil
+			return "", false
 		}
 		vs := []string{}
 		for _, name := range columnsName {
-			v := "NULL"
-			if record[name].Valid {
-				v = fmt.Sprintf("'%s'", record[name].String)
-			}
-			vs = append(vs, v)
+			vs = append(vs, formatRecordValue(record[name]))
 		}
 		values = append(values, fmt.Sprintf("(%s)", strings.Join(vs, ", ")))
 	}
@@ -520,22 +534,21 @@ func (i *MysqlDriverImpl) generateDeleteRollbackSql(stmt *ast.DeleteStmt) (strin
 			i.getTableNameWithQuote(table), strings.Join(columnsName, "`, `"),
 			strings.Join(values, ", "))
 	}
-	return rollbackSql, "", nil
+	return rollbackSql, true
 }
 
 // generateUpdateRollbackSql generate update SQL for update.
 func (i *MysqlDriverImpl) generateUpdateRollbackSql(stmt *ast.UpdateStmt) (string, string, error) {
 	tableSources := util.GetTableSources(stmt.TableRefs.TableRefs)
-	// multi table syntax
-	if len(tableSources) != 1 {
-		i.Logger().Infof("not support generate rollback sql with multi-update statement")
-		return "", NotSupportMultiTableStatementRollback, nil
-	}
 	// sub query statement
 	if util.WhereStmtHasSubQuery(stmt.Where) {
 		i.Logger().Infof("not support generate rollback sql with sub query")
 		return "", NotSupportSubQueryStatementRollback, nil
 	}
+	// multi table syntax, e.g. "update t1 join t2 on ... set ..."
+	if len(tableSources) != 1 {
+		return i.generateMultiTableUpdateRollbackSql(stmt, tableSources)
+	}
 	var (
 		table      *ast.TableName
 		tableAlias string
@@ -608,10 +621,7 @@ func (i *MysqlDriverImpl) generateUpdateRollbackSql(stmt *ast.UpdateStmt) (strin
 				}
 			}
 			name := col.Name.Name.O
-			v := "NULL"
-			if record[name].Valid {
-				v = fmt.Sprintf("'%s'", record[name].String)
-			}
+			v := formatRecordValue(record[name])
 
 			if colChanged {
 				value = append(value, fmt.Sprintf("%s = %s", name, v))
@@ -699,3 +709,500 @@ func (i *MysqlDriverImpl) generateGetRecordsSql(expr string, tableName *ast.Tabl
 	recordSql += ";"
 	return recordSql
 }
+
+// generateMultiTableUpdateRollbackSql generate update SQL for multi-table update, the records of each updated
+// table are selected by the join and where condition of update before it is executed.
+func (i *MysqlDriverImpl) generateMultiTableUpdateRollbackSql(stmt *ast.UpdateStmt, tableSources []*ast.TableSource) (string, string, error) {
+	createTableStmts := map[*ast.TableSource]*ast.CreateTableStmt{}
+	for _, source := range tableSources {
+		table, ok := source.Source.(*ast.TableName)
+		if !ok {
+			i.Logger().Infof("not support generate rollback sql with update-select statement")
+			return "", NotSupportSubQueryStatementRollback, nil
+		}
+		createTableStmt, exist, err := i.Ctx.GetCreateTableStmt(table)
+		if err != nil || !exist {
+			return "", "", err
+		}
+		createTableStmts[source] = createTableStmt
+	}
+
+	// group the changed columns by the table which they belong to.
+	changedColumns := map[*ast.TableSource]map[string]struct{}{}
+	for _, l := range stmt.List {
+		var target *ast.TableSource
+		if l.Column.Table.L != "" {
+			target = i.matchTableSource(tableSources, l.Column.Schema, l.Column.Table)
+		} else {
+			for _, source := range tableSources {
+				if !util.TableExistCol(createTableStmts[source], l.Column.Name.L) {
+					continue
+				}
+				// mysql will throw error: 1052 (23000): Column is ambiguous
+				if target != nil {
+					return "", "", nil
+				}
+				target = source
+			}
+		}
+		// mysql will throw error: 1054 (42S22): Unknown column
+		if target == nil {
+			return "", "", nil
+		}
+		if _, ok := changedColumns[target]; !ok {
+			changedColumns[target] = map[string]struct{}{}
+		}
+		changedColumns[target][l.Column.Name.L] = struct{}{}
+	}
+
+	var max = i.cnf.DMLRollbackMaxRows
+	rollbackSql := ""
+	for _, source := range tableSources {
+		columns, ok := changedColumns[source]
+		if !ok {
+			continue
+		}
+		createTableStmt := createTableStmts[source]
+		pkColumnsName, hasPk, err := i.getPrimaryKey(createTableStmt)
+		if err != nil {
+			return "", "", err
+		}
+		if !hasPk {
+			return "", NotSupportNoPrimaryKeyTableRollback, nil
+		}
+		for name := range columns {
+			if _, isPk := pkColumnsName[name]; isPk {
+				return "", NotSupportUpdatePrimaryKeyRollback, nil
+			}
+		}
+		records, err := i.getJoinRecords(source, stmt.TableRefs.TableRefs, stmt.Where, max+1)
+		if err != nil {
+			return "", "", err
+		}
+		if int64(len(records)) > max {
+			return "", NotSupportExceedMaxRowsRollback, nil
+		}
+		for _, record := range records {
+			if len(record) != len(createTableStmt.Cols) {
+				return "", "", nil
+			}
+			where := []string{}
+			value := []string{}
+			for _, col := range createTableStmt.Cols {
+				name := col.Name.Name.O
+				v := formatRecordValue(record[name])
+				if _, ok := columns[col.Name.Name.L]; ok {
+					value = append(value, fmt.Sprintf("%s = %s", name, v))
+				}
+				if _, isPk := pkColumnsName[col.Name.Name.L]; isPk {
+					where = append(where, fmt.Sprintf("%s = %s", name, v))
+				}
+			}
+			rollbackSql += fmt.Sprintf("UPDATE %s SET %s WHERE %s;", i.getTableNameWithQuote(source.Source.(*ast.TableName)),
+				strings.Join(value, ", "), strings.Join(where, " AND "))
+		}
+	}
+	return rollbackSql, "", nil
+}
+
+// generateMultiTableDeleteRollbackSql generate insert SQL for multi-table delete, the records of each deleted
+// table are selected by the join and where condition of delete before it is executed.
+func (i *MysqlDriverImpl) generateMultiTableDeleteRollbackSql(stmt *ast.DeleteStmt) (string, string, error) {
+	tableSources := util.GetTableSources(stmt.TableRefs.TableRefs)
+	for _, source := range tableSources {
+		if _, ok := source.Source.(*ast.TableName); !ok {
+			i.Logger().Infof("not support generate rollback sql with delete-select statement")
+			return "", NotSupportSubQueryStatementRollback, nil
+		}
+	}
+
+	var max = i.cnf.DMLRollbackMaxRows
+	rollbackSqls := []string{}
+	for _, target := range stmt.Tables.Tables {
+		source := i.matchTableSource(tableSources, target.Schema, target.Name)
+		// mysql will throw error: 1109 (42S02): Unknown table in MULTI DELETE
+		if source == nil {
+			return "", "", nil
+		}
+		table := source.Source.(*ast.TableName)
+		createTableStmt, exist, err := i.Ctx.GetCreateTableStmt(table)
+		if err != nil || !exist {
+			return "", "", err
+		}
+		_, hasPk, err := i.getPrimaryKey(createTableStmt)
+		if err != nil {
+			return "", "", err
+		}
+		if !hasPk {
+			return "", NotSupportNoPrimaryKeyTableRollback, nil
+		}
+		records, err := i.getJoinRecords(source, stmt.TableRefs.TableRefs, stmt.Where, max+1)
+		if err != nil {
+			return "", "", err
+		}
+		if int64(len(records)) > max {
+			return "", NotSupportExceedMaxRowsRollback, nil
+		}
+		rollbackSql, ok := i.generateInsertSqlFromRecords(table, createTableStmt, records)
+		if !ok {
+			return "", "", nil
+		}
+		if rollbackSql != "" {
+			rollbackSqls = append(rollbackSqls, rollbackSql)
+		}
+	}
+	return strings.Join(rollbackSqls, "\n"), "", nil
+}
+
+// generateInsertSelectRollbackSql generate delete SQL for "insert ... select", the primary keys of the rows
+// which will be inserted are selected before it is executed.
+func (i *MysqlDriverImpl) generateInsertSelectRollbackSql(stmt *ast.InsertStmt, table *ast.TableName,
+	createTableStmt *ast.CreateTableStmt, pkColumnsName map[string]struct{}) (string, string, error) {
+	rows, reason, err := i.getInsertedRows(stmt, createTableStmt, pkColumnsName)
+	if err != nil || reason != "" || rows == nil {
+		return "", reason, err
+	}
+	rollbackSql := ""
+	for _, row := range rows {
+		where, ok := getKeyCondition(createTableStmt, row, pkColumnsName)
+		if !ok {
+			return "", NotSupportInsertWithoutPrimaryKeyRollback, nil
+		}
+		rollbackSql += fmt.Sprintf("DELETE FROM %s WHERE %s;\n", i.getTableNameWithQuote(table), where)
+	}
+	return rollbackSql, "", nil
+}
+
+// generateUpsertRollbackSql generate rollback SQL for "replace into ..." and "insert ... on duplicate key update".
+// The records which conflict with the inserted rows on primary key or unique key are selected before it is
+// executed, the rollback SQL deletes the inserted rows and restores the conflicting records.
+func (i *MysqlDriverImpl) generateUpsertRollbackSql(stmt *ast.InsertStmt, table *ast.TableName,
+	createTableStmt *ast.CreateTableStmt, pkColumnsName map[string]struct{}) (string, string, error) {
+	updatedColumns := map[string]struct{}{}
+	for _, l := range stmt.OnDuplicate {
+		if _, isPk := pkColumnsName[l.Column.Name.L]; isPk {
+			return "", NotSupportOnDuplicatStatementRollback, nil
+		}
+		updatedColumns[l.Column.Name.L] = struct{}{}
+	}
+
+	keys := []map[string]struct{}{pkColumnsName}
+	keyColumns := map[string]struct{}{}
+	for name := range pkColumnsName {
+		keyColumns[name] = struct{}{}
+	}
+	for _, uniqueKey := range util.GetUniqueKeys(createTableStmt) {
+		key := map[string]struct{}{}
+		for _, name := range uniqueKey {
+			key[name] = struct{}{}
+			keyColumns[name] = struct{}{}
+		}
+		keys = append(keys, key)
+	}
+
+	rows, reason, err := i.getInsertedRows(stmt, createTableStmt, keyColumns)
+	if err != nil || reason != "" || rows == nil {
+		return "", reason, err
+	}
+	if len(rows) == 0 {
+		return "", "", nil
+	}
+	pkConditions := []string{}
+	conflictConditions := []string{}
+	for _, row := range rows {
+		pkCondition, ok := getKeyCondition(createTableStmt, row, pkColumnsName)
+		if !ok {
+			return "", NotSupportInsertWithoutPrimaryKeyRollback, nil
+		}
+		pkConditions = append(pkConditions, pkCondition)
+		for _, key := range keys {
+			// the unique key which has NULL value never conflicts.
+			if condition, ok := getKeyCondition(createTableStmt, row, key); ok {
+				conflictConditions = append(conflictConditions, fmt.Sprintf("(%s)", condition))
+			}
+		}
+	}
+
+	conn, err := i.getDbConn()
+	if err != nil {
+		return "", "", err
+	}
+	var max = i.cnf.DMLRollbackMaxRows
+	records, err := conn.Db.Query(fmt.Sprintf("SELECT * FROM %s WHERE %s LIMIT %d;",
+		i.getTableNameWithQuote(table), strings.Join(conflictConditions, " OR "), max+1))
+	if err != nil {
+		return "", "", err
+	}
+	if int64(len(records)) > max {
+		return "", NotSupportExceedMaxRowsRollback, nil
+	}
+
+	rollbackSqls := []string{}
+	for n, row := range rows {
+		// the row which conflicts on primary key updates the existing record instead of inserting a new one.
+		if !stmt.IsReplace && recordsContainKey(createTableStmt, records, row, pkColumnsName) {
+			continue
+		}
+		rollbackSqls = append(rollbackSqls, fmt.Sprintf("DELETE FROM %s WHERE %s;",
+			i.getTableNameWithQuote(table), pkConditions[n]))
+	}
+	if stmt.IsReplace {
+		insertSql, ok := i.generateInsertSqlFromRecords(table, createTableStmt, records)
+		if !ok {
+			return "", "", nil
+		}
+		if insertSql != "" {
+			rollbackSqls = append(rollbackSqls, insertSql)
+		}
+		return strings.Join(rollbackSqls, "\n"), "", nil
+	}
+	for _, record := range records {
+		where := []string{}
+		value := []string{}
+		for _, col := range createTableStmt.Cols {
+			name := col.Name.Name.O
+			v := formatRecordValue(record[name])
+			if _, ok := updatedColumns[col.Name.Name.L]; ok {
+				value = append(value, fmt.Sprintf("%s = %s", name, v))
+			}
+			if _, isPk := pkColumnsName[col.Name.Name.L]; isPk {
+				where = append(where, fmt.Sprintf("%s = %s", name, v))
+			}
+		}
+		rollbackSqls = append(rollbackSqls, fmt.Sprintf("UPDATE %s SET %s WHERE %s;",
+			i.getTableNameWithQuote(table), strings.Join(value, ", "), strings.Join(where, " AND ")))
+	}
+	return strings.Join(rollbackSqls, "\n"), "", nil
+}
+
+// getInsertedRows returns the values of key columns of each row which will be inserted, the value which is
+// not a constant is absent from the row. It returns nil rows if the insert statement is invalid.
+func (i *MysqlDriverImpl) getInsertedRows(stmt *ast.InsertStmt, createTableStmt *ast.CreateTableStmt,
+	keyColumns map[string]struct{}) ([]map[string]sql.NullString, string, error) {
+	columnsName := []string{}
+	if stmt.Columns != nil {
+		for _, col := range stmt.Columns {
+			columnsName = append(columnsName, col.Name.L)
+		}
+	} else {
+		for _, col := range createTableStmt.Cols {
+			columnsName = append(columnsName, col.Name.Name.L)
+		}
+	}
+
+	switch {
+	case stmt.Lists != nil:
+		if int64(len(stmt.Lists)) > i.cnf.DMLRollbackMaxRows {
+			return nil, NotSupportExceedMaxRowsRollback, nil
+		}
+		rows := []map[string]sql.NullString{}
+		for _, value := range stmt.Lists {
+			// mysql will throw error: 1136 (21S01): Column count doesn't match value count
+			if len(columnsName) != len(value) {
+				return nil, "", nil
+			}
+			row := map[string]sql.NullString{}
+			for n, name := range columnsName {
+				if _, isKey := keyColumns[name]; !isKey {
+					continue
+				}
+				if v, ok := getConstantValue(value[n]); ok {
+					row[name] = v
+				}
+			}
+			rows = append(rows, row)
+		}
+		return rows, "", nil
+	case stmt.Setlist != nil:
+		row := map[string]sql.NullString{}
+		for _, setExpr := range stmt.Setlist {
+			name := setExpr.Column.Name.L
+			if _, isKey := keyColumns[name]; !isKey {
+				continue
+			}
+			if v, ok := getConstantValue(setExpr.Expr); ok {
+				row[name] = v
+			}
+		}
+		return []map[string]sql.NullString{row}, "", nil
+	case stmt.Select != nil:
+		return i.getInsertSelectRows(stmt.Select, columnsName, keyColumns)
+	}
+	return nil, NotSupportStatementRollback, nil
+}
+
+// getInsertSelectRows selects the values of key columns of each row which will be inserted by "insert ... select".
+func (i *MysqlDriverImpl) getInsertSelectRows(node ast.ResultSetNode, columnsName []string,
+	keyColumns map[string]struct{}) ([]map[string]sql.NullString, string, error) {
+	selectStmt, ok := node.(*ast.SelectStmt)
+	if !ok || selectStmt.Fields == nil {
+		i.Logger().Infof("not support generate rollback sql with insert-union statement")
+		return nil, NotSupportSubQueryStatementRollback, nil
+	}
+	for _, field := range selectStmt.Fields.Fields {
+		if field.WildCard != nil {
+			return nil, NotSupportInsertSelectAllColumnRollback, nil
+		}
+	}
+	// mysql will throw error: 1136 (21S01): Column count doesn't match value count
+	if len(selectStmt.Fields.Fields) != len(columnsName) {
+		return nil, "", nil
+	}
+
+	// only the fields of key columns are selected, and they are renamed to the inserted column.
+	fields := []*ast.SelectField{}
+	for n, name := range columnsName {
+		if _, isKey := keyColumns[name]; !isKey {
+			continue
+		}
+		field := *selectStmt.Fields.Fields[n]
+		field.AsName = _model.NewCIStr(name)
+		fields = append(fields, &field)
+	}
+	if len(fields) == 0 {
+		return nil, NotSupportInsertWithoutPrimaryKeyRollback, nil
+	}
+	keySelectStmt := *selectStmt
+	keySelectStmt.Fields = &ast.FieldList{Fields: fields}
+	query, err := util.RestoreToSql(&keySelectStmt)
+	if err != nil {
+		return nil, "", err
+	}
+
+	conn, err := i.getDbConn()
+	if err != nil {
+		return nil, "", err
+	}
+	var max = i.cnf.DMLRollbackMaxRows
+	records, err := conn.Db.Query(fmt.Sprintf("SELECT * FROM (%s) AS t LIMIT %d;", query, max+1))
+	if err != nil {
+		return nil, "", err
+	}
+	if int64(len(records)) > max {
+		return nil, NotSupportExceedMaxRowsRollback, nil
+	}
+	return records, "", nil
+}
+
+// getJoinRecords selects the records of table source which match the join and where condition.
+func (i *MysqlDriverImpl) getJoinRecords(source *ast.TableSource, join *ast.Join, where ast.ExprNode,
+	limit int64) ([]map[string]sql.NullString, error) {
+	conn, err := i.getDbConn()
+	if err != nil {
+		return nil, err
+	}
+	from, err := util.RestoreToSql(join)
+	if err != nil {
+		return nil, err
+	}
+	recordSql := fmt.Sprintf("SELECT DISTINCT %s.* FROM %s", getTableSourceRef(source), from)
+	if where != nil {
+		condition, err := util.RestoreToSql(where)
+		if err != nil {
+			return nil, err
+		}
+		recordSql = fmt.Sprintf("%s WHERE %s", recordSql, condition)
+	}
+	recordSql = fmt.Sprintf("%s LIMIT %d;", recordSql, limit)
+	return conn.Db.Query(recordSql)
+}
+
+// matchTableSource returns the table source which is referenced by the name, the name is the alias of
+// table source or the table name if the table source has no alias.
+func (i *MysqlDriverImpl) matchTableSource(sources []*ast.TableSource, schema, name _model.CIStr) *ast.TableSource {
+	for _, source := range sources {
+		table, ok := source.Source.(*ast.TableName)
+		if !ok {
+			continue
+		}
+		if source.AsName.L != "" {
+			if schema.L == "" && source.AsName.L == name.L {
+				return source
+			}
+			continue
+		}
+		if table.Name.L == name.L && (schema.L == "" || strings.EqualFold(i.Ctx.GetSchemaName(table), schema.O)) {
+			return source
+		}
+	}
+	return nil
+}
+
+// getTableSourceRef returns the name which the table source is referenced by in SQL.
+func getTableSourceRef(source *ast.TableSource) string {
+	if source.AsName.L != "" {
+		return fmt.Sprintf("`%s`", source.AsName.O)
+	}
+	table := source.Source.(*ast.TableName)
+	if table.Schema.O != "" {
+		return fmt.Sprintf("`%s`.`%s`", table.Schema.O, table.Name.O)
+	}
+	return fmt.Sprintf("`%s`", table.Name.O)
+}
+
+// getKeyCondition returns the where condition which matches the key value of row, it returns false if
+// the row does not contain all the key columns or one of them is NULL.
+func getKeyCondition(createTableStmt *ast.CreateTableStmt, row map[string]sql.NullString, key map[string]struct{}) (string, bool) {
+	where := []string{}
+	for _, col := range createTableStmt.Cols {
+		if _, isKey := key[col.Name.Name.L]; !isKey {
+			continue
+		}
+		v, ok := row[col.Name.Name.L]
+		if !ok || !v.Valid {
+			return "", false
+		}
+		where = append(where, fmt.Sprintf("%s = %s", col.Name.Name.O, formatRecordValue(v)))
+	}
+	return strings.Join(where, " AND "), len(where) == len(key)
+}
+
+// recordsContainKey returns true if one of records has the same key value as the row.
+func recordsContainKey(createTableStmt *ast.CreateTableStmt, records []map[string]sql.NullString,
+	row map[string]sql.NullString, key map[string]struct{}) bool {
+	for _, record := range records {
+		matched := true
+		for _, col := range createTableStmt.Cols {
+			if _, isKey := key[col.Name.Name.L]; !isKey {
+				continue
+			}
+			v, r := row[col.Name.Name.L], record[col.Name.Name.O]
+			// the key is compared case-insensitively like the default collation, the record which may
+			// be updated by the row is never deleted by rollback.
+			if !v.Valid || !r.Valid || !strings.EqualFold(v.String, r.String) {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return true
+		}
+	}
+	return false
+}
+
+// getConstantValue returns the value of expression if it is a constant.
+func getConstantValue(expr ast.ExprNode) (sql.NullString, bool) {
+	v, ok := expr.(*driver.ValueExpr)
+	if !ok {
+		return sql.NullString{}, false
+	}
+	if v.Datum.IsNull() {
+		return sql.NullString{}, true
+	}
+	s, err := v.Datum.ToString()
+	if err != nil {
+		return sql.NullString{}, false
+	}
+	return sql.NullString{String: s, Valid: true}, true
+}
+
+var recordValueEscaper = strings.NewReplacer(`\`, `\\`, `'`, `\'`)
+
+// formatRecordValue formats the value of record as a SQL literal.
+func formatRecordValue(v sql.NullString) string {
+	if !v.Valid {
+		return "NULL"
+	}
+	return fmt.Sprintf("'%s'", recordValueEscaper.Replace(v.String))
+}
@@ -2,9 +2,13 @@ package mysql
 
 import (
 	"context"
+	"regexp"
 	"testing"
 
+	"github.com/actiontech/sqle/sqle/driver/mysql/executor"
 	"github.com/actiontech/sqle/sqle/driver/mysql/util"
+
+	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -178,3 +182,103 @@ func TestInsertRollbackSql(t *testing.T) {
 		"DELETE FROM `exist_db`.`exist_tb_1` WHERE id = '10';\n",
 	)
 }
+
+func TestMultiTableRollbackSql(t *testing.T) {
+	e, handler, err := executor.NewMockExecutor()
+	assert.NoError(t, err)
+	i := NewMockInspect(e)
+	i.isConnected = true
+
+	handler.ExpectQuery(regexp.QuoteMeta("SELECT DISTINCT `a`.* FROM `exist_db`.`exist_tb_1` AS `a` JOIN `exist_db`.`exist_tb_4` AS `b` ON `a`.`id`=`b`.`id` WHERE `b`.`v3`=1 LIMIT 1001;")).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "v1", "v2"}).AddRow("1", "it's", nil))
+	handler.ExpectQuery(regexp.QuoteMeta("SELECT DISTINCT `b`.* FROM `exist_db`.`exist_tb_1` AS `a` JOIN `exist_db`.`exist_tb_4` AS `b` ON `a`.`id`=`b`.`id` WHERE `b`.`v3`=1 LIMIT 1001;")).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "v1", "v2", "v3"}).AddRow("1", "v1", "v2", "1"))
+	rollback, reason, err := i.GenRollbackSQL(context.TODO(),
+		"UPDATE exist_db.exist_tb_1 a JOIN exist_db.exist_tb_4 b ON a.id = b.id SET a.v1 = b.v1, v3 = 2 WHERE b.v3 = 1")
+	assert.NoError(t, err)
+	assert.Equal(t, "", reason)
+	assert.Equal(t, "UPDATE `exist_db`.`exist_tb_1` SET v1 = 'it\\'s' WHERE id = '1';"+
+		"UPDATE `exist_db`.`exist_tb_4` SET v3 = '1' WHERE id = '1';", rollback)
+
+	_, reason, err = i.GenRollbackSQL(context.TODO(),
+		"UPDATE exist_db.exist_tb_1 a JOIN exist_db.exist_tb_4 b ON a.v1 = b.v1 SET a.id = b.id")
+	assert.NoError(t, err)
+	assert.Equal(t, NotSupportUpdatePrimaryKeyRollback, reason)
+
+	handler.ExpectQuery(regexp.QuoteMeta("SELECT DISTINCT `a`.* FROM `exist_db`.`exist_tb_1` AS `a` JOIN `exist_db`.`exist_tb_4` ON `a`.`id`=`exist_tb_4`.`id` WHERE `exist_tb_4`.`v3`=1 LIMIT 1001;")).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "v1", "v2"}).AddRow("1", "v1", "v2"))
+	handler.ExpectQuery(regexp.QuoteMeta("SELECT DISTINCT `exist_db`.`exist_tb_4`.* FROM `exist_db`.`exist_tb_1` AS `a` JOIN `exist_db`.`exist_tb_4` ON `a`.`id`=`exist_tb_4`.`id` WHERE `exist_tb_4`.`v3`=1 LIMIT 1001;")).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "v1", "v2", "v3"}))
+	rollback, reason, err = i.GenRollbackSQL(context.TODO(),
+		"DELETE a, exist_tb_4 FROM exist_db.exist_tb_1 a JOIN exist_db.exist_tb_4 ON a.id = exist_tb_4.id WHERE exist_tb_4.v3 = 1")
+	assert.NoError(t, err)
+	assert.Equal(t, "", reason)
+	assert.Equal(t, "INSERT INTO `exist_db`.`exist_tb_1` (`id`, `v1`, `v2`) VALUES ('1', 'v1', 'v2');", rollback)
+
+	assert.NoError(t, handler.ExpectationsWereMet())
+}
+
+func TestUpsertRollbackSql(t *testing.T) {
+	e, handler, err := executor.NewMockExecutor()
+	assert.NoError(t, err)
+	i := NewMockInspect(e)
+	i.isConnected = true
+
+	// REPLACE
+	handler.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `exist_db`.`exist_tb_1` WHERE (id = '1') OR (v1 = 'a' AND v2 = 'b') OR (id = '2') LIMIT 1001;")).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "v1", "v2"}).AddRow("1", "v1", "v2").AddRow("3", "a", "b"))
+	rollback, reason, err := i.GenRollbackSQL(context.TODO(),
+		"REPLACE INTO exist_db.exist_tb_1 (id, v1, v2) VALUES (1, 'a', 'b'), (2, 'c', NULL)")
+	assert.NoError(t, err)
+	assert.Equal(t, "", reason)
+	assert.Equal(t, "DELETE FROM `exist_db`.`exist_tb_1` WHERE id = '1';\n"+
+		"DELETE FROM `exist_db`.`exist_tb_1` WHERE id = '2';\n"+
+		"INSERT INTO `exist_db`.`exist_tb_1` (`id`, `v1`, `v2`) VALUES ('1', 'v1', 'v2'), ('3', 'a', 'b');", rollback)
+
+	// INSERT ... ON DUPLICATE KEY UPDATE
+	handler.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `exist_db`.`exist_tb_1` WHERE (id = '1') OR (v1 = 'a' AND v2 = 'b') OR (id = '5') LIMIT 1001;")).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "v1", "v2"}).AddRow("1", "v1", nil))
+	rollback, reason, err = i.GenRollbackSQL(context.TODO(),
+		"INSERT INTO exist_db.exist_tb_1 (id, v1, v2) VALUES (1, 'a', 'b'), (5, 'c', now()) ON DUPLICATE KEY UPDATE v2 = VALUES(v2)")
+	assert.NoError(t, err)
+	assert.Equal(t, "", reason)
+	assert.Equal(t, "DELETE FROM `exist_db`.`exist_tb_1` WHERE id = '5';\n"+
+		"UPDATE `exist_db`.`exist_tb_1` SET v2 = NULL WHERE id = '1';", rollback)
+
+	_, reason, err = i.GenRollbackSQL(context.TODO(),
+		"INSERT INTO exist_db.exist_tb_1 (id, v1, v2) VALUES (1, 'a', 'b') ON DUPLICATE KEY UPDATE id = id + 1")
+	assert.NoError(t, err)
+	assert.Equal(t, NotSupportOnDuplicatStatementRollback, reason)
+
+	_, reason, err = i.GenRollbackSQL(context.TODO(), "REPLACE INTO exist_db.exist_tb_1 (v1, v2) VALUES ('a', 'b')")
+	assert.NoError(t, err)
+	assert.Equal(t, NotSupportInsertWithoutPrimaryKeyRollback, reason)
+
+	assert.NoError(t, handler.ExpectationsWereMet())
+}
+
+func TestInsertSelectRollbackSql(t *testing.T) {
+	e, handler, err := executor.NewMockExecutor()
+	assert.NoError(t, err)
+	i := NewMockInspect(e)
+	i.isConnected = true
+
+	handler.ExpectQuery(regexp.QuoteMeta("SELECT * FROM (SELECT `id`+100 AS `id` FROM `exist_db`.`exist_tb_4` WHERE `v3`=1) AS t LIMIT 1001;")).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("101").AddRow("102"))
+	rollback, reason, err := i.GenRollbackSQL(context.TODO(),
+		"INSERT INTO exist_db.exist_tb_1 (id, v1, v2) SELECT id + 100, v1, v2 FROM exist_db.exist_tb_4 WHERE v3 = 1")
+	assert.NoError(t, err)
+	assert.Equal(t, "", reason)
+	assert.Equal(t, "DELETE FROM `exist_db`.`exist_tb_1` WHERE id = '101';\n"+
+		"DELETE FROM `exist_db`.`exist_tb_1` WHERE id = '102';\n", rollback)
+
+	_, reason, err = i.GenRollbackSQL(context.TODO(), "INSERT INTO exist_db.exist_tb_1 SELECT * FROM exist_db.exist_tb_4")
+	assert.NoError(t, err)
+	assert.Equal(t, NotSupportInsertSelectAllColumnRollback, reason)
+
+	_, reason, err = i.GenRollbackSQL(context.TODO(), "INSERT INTO exist_db.exist_tb_1 (v1, v2) SELECT v1, v2 FROM exist_db.exist_tb_4")
+	assert.NoError(t, err)
+	assert.Equal(t, NotSupportInsertWithoutPrimaryKeyRollback, reason)
+
+	assert.NoError(t, handler.ExpectationsWereMet())
+}
@@ -764,6 +764,41 @@ func TableExistCol(table *ast.CreateTableStmt, colName string) bool {
 	return false
 }
 
+// GetUniqueKeys returns the columns of each unique key of table, the primary key is not included.
+func GetUniqueKeys(stmt *ast.CreateTableStmt) [][]string {
+	keys := [][]string{}
+	for _, col := range stmt.Cols {
+		if HasOneInOptions(col.Options, ast.ColumnOptionUniqKey) {
+			keys = append(keys, []string{col.Name.Name.L})
+		}
+	}
+	for _, constraint := range stmt.Constraints {
+		switch constraint.Tp {
+		case ast.ConstraintUniq, ast.ConstraintUniqKey, ast.ConstraintUniqIndex:
+		default:
+			continue
+		}
+		key := []string{}
+		for _, part := range constraint.Keys {
+			// the functional key part is not a column.
+			if part.Column == nil {
+				key = nil
+				break
+			}
+			key = append(key, part.Column.Name.L)
+		}
+		if len(key) > 0 {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+// RestoreToSql restores the node to SQL text which can be executed on MySQL.
+func RestoreToSql(node ast.Node) (string, error) {
+	return restoreToSqlWithFlag(format.DefaultRestoreFlags, node)
+}
+
 func restoreToSqlWithFlag(restoreFlag format.RestoreFlags, node ast.Node) (sqlStr string, err error) {
 	buf := new(bytes.Buffer)
 	restoreCtx := format.NewRestoreCtx(restoreFlag, buf)
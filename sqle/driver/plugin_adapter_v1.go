@@ -3,6 +3,7 @@ package driver
 import (
 	"context"
 	sqlDriver "database/sql/driver"
+	"strings"
 
 	driverV1 "github.com/actiontech/sqle/sqle/driver/v1"
 	driverV2 "github.com/actiontech/sqle/sqle/driver/v2"
@@ -59,6 +60,7 @@ func (d *PluginProcessorV1) GetDriverMetas() (*driverV2.DriverMetas, error) {
 			driverV2.OptionalModuleExplain,
 			driverV2.OptionalModuleGetTableMeta,
 			driverV2.OptionalModuleExtractTableFromSQL,
+			driverV2.OptionalModuleListTables,
 		}...)
 	}
 
@@ -314,3 +316,71 @@ func (p *PluginImplV1) GetTableMetaBySQL(ctx context.Context, conf *GetTableMeta
 func (p *PluginImplV1) EstimateSQLAffectRows(ctx context.Context, sql string) (*driverV2.EstimatedAffectRows, error) {
 	return nil, NewErrPluginAPINotImplement(driverV2.OptionalModuleEstimateSQLAffectRows)
 }
+
+// ListTables filters and pages tables in SQLE, because V1 plugin only supports listing all tables in schema.
+func (p *PluginImplV1) ListTables(ctx context.Context, conf *driverV2.ListTablesConf) (*driverV2.ListTablesResult, error) {
+	client, err := p.DriverManager.GetAnalysisDriver()
+	if err != nil {
+		return nil, err
+	}
+	resultV1, err := client.ListTablesInSchema(ctx, &driverV1.ListTablesInSchemaConf{
+		Schema: conf.Schema,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	tables := []*driverV2.Table{}
+	for _, t := range resultV1.Tables {
+		if conf.FuzzySearchTableName != "" && !strings.Contains(t.Name, conf.FuzzySearchTableName) {
+			continue
+		}
+		tables = append(tables, &driverV2.Table{Name: t.Name, Schema: conf.Schema})
+	}
+
+	result := &driverV2.ListTablesResult{Total: uint64(len(tables))}
+	start := int(conf.Offset)
+	if start > len(tables) {
+		start = len(tables)
+	}
+	end := len(tables)
+	if conf.Limit > 0 && start+int(conf.Limit) < end {
+		end = start + int(conf.Limit)
+	}
+	result.Tables = tables[start:end]
+	return result, nil
+}
+
+func (p *PluginImplV1) GetTableMeta(ctx context.Context, table *driverV2.Table) (*driverV2.TableMeta, error) {
+	client, err := p.DriverManager.GetAnalysisDriver()
+	if err != nil {
+		return nil, err
+	}
+	resultV1, err := client.GetTableMetaByTableName(ctx, &driverV1.GetTableMetaByTableNameConf{
+		Schema: table.Schema,
+		Table:  table.Name,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	tm := resultV1.TableMeta
+	tmV2 := &driverV2.TableMeta{
+		CreateTableSQL: tm.CreateTableSQL,
+	}
+	for _, column := range tm.ColumnsInfo.Columns {
+		tmV2.ColumnsInfo.Columns = append(tmV2.ColumnsInfo.Columns, driverV2.TabularDataHead{
+			Name: column.Name,
+			Desc: column.Desc,
+		})
+	}
+	tmV2.ColumnsInfo.Rows = tm.ColumnsInfo.Rows
+	for _, column := range tm.IndexesInfo.Columns {
+		tmV2.IndexesInfo.Columns = append(tmV2.IndexesInfo.Columns, driverV2.TabularDataHead{
+			Name: column.Name,
+			Desc: column.Desc,
+		})
+	}
+	tmV2.IndexesInfo.Rows = tm.IndexesInfo.Rows
+	return tmV2, nil
+}
@@ -237,9 +237,10 @@ func (s *PluginImplV2) Audit(ctx context.Context, sqls []string) ([]*driverV2.Au
 		ret := &driverV2.AuditResults{}
 		for _, result := range results.Results {
 			ret.Results = append(ret.Results, &driverV2.AuditResult{
-				Level:    driverV2.RuleLevel(result.Level),
-				Message:  result.Message,
-				RuleName: result.RuleName,
+				Level:       driverV2.RuleLevel(result.Level),
+				Message:     result.Message,
+				RuleName:    result.RuleName,
+				IndexAdvice: driverV2.ConvertProtoIndexAdviceToDriver(result.IndexAdvice),
 			})
 		}
 		rets = append(rets, ret)
@@ -408,7 +409,7 @@ func (s *PluginImplV2) Schemas(ctx context.Context) ([]string, error) {
 	return databases, nil
 }
 
-func (s *PluginImplV2) getTableMeta(ctx context.Context, table *driverV2.Table) (*driverV2.TableMeta, error) {
+func (s *PluginImplV2) GetTableMeta(ctx context.Context, table *driverV2.Table) (*driverV2.TableMeta, error) {
 	api := "GetTableMeta"
 	s.preLog(api)
 	result, err := s.client.GetTableMeta(ctx, &protoV2.GetTableMetaRequest{
@@ -453,7 +454,7 @@ func (s *PluginImplV2) GetTableMetaBySQL(ctx context.Context, conf *GetTableMeta
 	}
 	tableMetas := make([]*TableMeta, 0, len(tables))
 	for _, table := range tables {
-		tableMeta, err := s.getTableMeta(ctx, table)
+		tableMeta, err := s.GetTableMeta(ctx, table)
 		if err != nil {
 			return nil, err
 		}
@@ -484,6 +485,33 @@ func (s *PluginImplV2) EstimateSQLAffectRows(ctx context.Context, sql string) (*
 	}, nil
 }
 
+func (s *PluginImplV2) ListTables(ctx context.Context, conf *driverV2.ListTablesConf) (*driverV2.ListTablesResult, error) {
+	api := "ListTables"
+	s.preLog(api)
+	resp, err := s.client.ListTables(ctx, &protoV2.ListTablesRequest{
+		Session:              s.Session,
+		Schema:               conf.Schema,
+		FuzzySearchTableName: conf.FuzzySearchTableName,
+		Limit:                conf.Limit,
+		Offset:               conf.Offset,
+	})
+	s.afterLog(api, err)
+	if err != nil {
+		return nil, err
+	}
+	tables := make([]*driverV2.Table, 0, len(resp.Tables))
+	for _, table := range resp.Tables {
+		tables = append(tables, &driverV2.Table{
+			Name:   table.Name,
+			Schema: table.Schema,
+		})
+	}
+	return &driverV2.ListTablesResult{
+		Tables: tables,
+		Total:  resp.Total,
+	}, nil
+}
+
 type dbDriverResult struct {
 	lastInsertId    int64
 	lastInsertIdErr string
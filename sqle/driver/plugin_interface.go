@@ -40,6 +40,12 @@ type Plugin interface {
 
 	// Introduced from v2.2304.0
 	EstimateSQLAffectRows(ctx context.Context, sql string) (*driverV2.EstimatedAffectRows, error)
+
+	// ListTables list tables in schema, the result can be filtered by table name and paged.
+	ListTables(ctx context.Context, conf *driverV2.ListTablesConf) (*driverV2.ListTablesResult, error)
+
+	// GetTableMeta get columns, indexes and create table SQL of the table.
+	GetTableMeta(ctx context.Context, table *driverV2.Table) (*driverV2.TableMeta, error)
 }
 
 type PluginProcessor interface {
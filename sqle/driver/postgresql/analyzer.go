@@ -0,0 +1,633 @@
+package postgresql
+
+import (
+	driverV2 "github.com/actiontech/sqle/sqle/driver/v2"
+)
+
+type columnDef struct {
+	name string
+	// typ is the raw text of column type, e.g. "varchar(32)".
+	typ string
+}
+
+type createTableInfo struct {
+	table     tableName
+	columns   []columnDef
+	pkColumns []string
+	hasPK     bool
+	// derived is true when the columns are not defined in the statement,
+	// e.g. "CREATE TABLE ... AS SELECT", "CREATE TABLE ... PARTITION OF" and "CREATE TABLE (LIKE ...)".
+	derived bool
+}
+
+// columnConstraintKeywords are the keywords which end the column type in column definition.
+var columnConstraintKeywords = []string{"constraint", "not", "null", "default", "primary", "unique", "references",
+	"check", "collate", "generated"}
+
+func (s *stmt) createTable() (*createTableInfo, bool) {
+	if s.kind != stmtCreateTable {
+		return nil, false
+	}
+	i := s.body + 1
+	for !s.at(i, "table") {
+		i++
+	}
+	i++
+	if s.at(i, "if") && s.at(i+1, "not") && s.at(i+2, "exists") {
+		i += 3
+	}
+	table, i, ok := s.parseName(i)
+	if !ok {
+		return nil, false
+	}
+	info := &createTableInfo{table: table}
+	if !s.atPunct(i, "(") {
+		info.derived = true
+		return info, true
+	}
+	end := s.skipParens(i)
+	for _, item := range s.splitList(i+1, end-1) {
+		from, to := item[0], item[1]
+		if s.at(from, "constraint") {
+			from += 2
+		}
+		switch {
+		case s.at(from, "primary") && s.at(from+1, "key"):
+			info.hasPK = true
+			info.pkColumns = s.identList(from + 2)
+		case s.at(from, "like"):
+			info.derived = true
+		case s.at(from, "unique"), s.at(from, "foreign"), s.at(from, "check"), s.at(from, "exclude"):
+		default:
+			if from >= to || !s.tokens[from].isIdent() {
+				continue
+			}
+			col := columnDef{name: s.tokens[from].name()}
+			typeEnd := s.findKeyword(from+1, to, columnConstraintKeywords...)
+			if typeEnd < 0 {
+				typeEnd = to
+			}
+			col.typ = s.textOf(from+1, typeEnd)
+			if k := s.findKeyword(from+1, to, "primary"); k >= 0 && s.at(k+1, "key") {
+				info.hasPK = true
+				info.pkColumns = append(info.pkColumns, col.name)
+			}
+			info.columns = append(info.columns, col)
+		}
+	}
+	return info, true
+}
+
+// identList returns the identifiers in parenthesis at index i, e.g. "(a, b)".
+func (s *stmt) identList(i int) []string {
+	if !s.atPunct(i, "(") {
+		return nil
+	}
+	names := []string{}
+	for _, item := range s.splitList(i+1, s.skipParens(i)-1) {
+		if item[1]-item[0] == 1 && s.tokens[item[0]].isIdent() {
+			names = append(names, s.tokens[item[0]].name())
+		}
+	}
+	return names
+}
+
+type createIndexInfo struct {
+	name         string
+	unique       bool
+	concurrently bool
+	table        tableName
+	// columns is the indexed columns, the expression is recorded as empty string.
+	columns []string
+}
+
+func (s *stmt) createIndex() (*createIndexInfo, bool) {
+	if s.kind != stmtCreateIndex {
+		return nil, false
+	}
+	info := &createIndexInfo{}
+	i := s.body + 1
+	if s.at(i, "unique") {
+		info.unique = true
+		i++
+	}
+	i++ // INDEX
+	if s.at(i, "concurrently") {
+		info.concurrently = true
+		i++
+	}
+	if s.at(i, "if") && s.at(i+1, "not") && s.at(i+2, "exists") {
+		i += 3
+	}
+	if !s.at(i, "on") {
+		if i >= len(s.tokens) || !s.tokens[i].isIdent() {
+			return nil, false
+		}
+		info.name = s.tokens[i].name()
+		i++
+	}
+	if !s.at(i, "on") {
+		return nil, false
+	}
+	i++
+	if s.at(i, "only") {
+		i++
+	}
+	table, i, ok := s.parseName(i)
+	if !ok {
+		return nil, false
+	}
+	info.table = table
+	if s.at(i, "using") {
+		i += 2
+	}
+	if !s.atPunct(i, "(") {
+		return nil, false
+	}
+	for _, item := range s.splitList(i+1, s.skipParens(i)-1) {
+		if s.tokens[item[0]].isIdent() && (item[1]-item[0] == 1 || s.isIndexColumnOption(item[0]+1, item[1])) {
+			info.columns = append(info.columns, s.tokens[item[0]].name())
+		} else {
+			info.columns = append(info.columns, "")
+		}
+	}
+	return info, true
+}
+
+// isIndexColumnOption returns true if the tokens are the options of index column, e.g. "DESC NULLS LAST".
+func (s *stmt) isIndexColumnOption(from, to int) bool {
+	for i := from; i < to; i++ {
+		if !(s.at(i, "asc") || s.at(i, "desc") || s.at(i, "nulls") || s.at(i, "first") || s.at(i, "last")) {
+			return false
+		}
+	}
+	return true
+}
+
+type alterActionKind int
+
+const (
+	alterOther alterActionKind = iota
+	alterAddColumn
+	alterDropColumn
+	alterRenameColumn
+	alterRenameTable
+	alterAddConstraint
+	alterDropConstraint
+)
+
+type alterAction struct {
+	kind alterActionKind
+	// name is the column or constraint name, or the new table name of "RENAME TO".
+	name string
+	// newName is the new column name of "RENAME COLUMN".
+	newName string
+	// typ is the column type of "ADD COLUMN".
+	typ string
+}
+
+type alterTableInfo struct {
+	table   tableName
+	actions []alterAction
+}
+
+func (s *stmt) alterTable() (*alterTableInfo, bool) {
+	if s.kind != stmtAlterTable {
+		return nil, false
+	}
+	i := s.body + 2
+	if s.at(i, "if") && s.at(i+1, "exists") {
+		i += 2
+	}
+	if s.at(i, "only") {
+		i++
+	}
+	table, i, ok := s.parseName(i)
+	if !ok {
+		return nil, false
+	}
+	if s.atPunct(i, "*") {
+		i++
+	}
+	info := &alterTableInfo{table: table}
+	for _, item := range s.splitList(i, len(s.tokens)) {
+		info.actions = append(info.actions, s.alterAction(item[0], item[1]))
+	}
+	return info, true
+}
+
+func (s *stmt) alterAction(from, to int) alterAction {
+	i := from
+	name := func(i int) string {
+		if i < to && s.tokens[i].isIdent() {
+			return s.tokens[i].name()
+		}
+		return ""
+	}
+	switch {
+	case s.at(i, "add"):
+		i++
+		switch {
+		case s.at(i, "constraint"):
+			return alterAction{kind: alterAddConstraint, name: name(i + 1)}
+		case s.at(i, "primary"), s.at(i, "unique"), s.at(i, "foreign"), s.at(i, "check"), s.at(i, "exclude"):
+			return alterAction{kind: alterAddConstraint}
+		}
+		if s.at(i, "column") {
+			i++
+		}
+		if s.at(i, "if") && s.at(i+1, "not") && s.at(i+2, "exists") {
+			i += 3
+		}
+		typeEnd := s.findKeyword(i+1, to, columnConstraintKeywords...)
+		if typeEnd < 0 {
+			typeEnd = to
+		}
+		return alterAction{kind: alterAddColumn, name: name(i), typ: s.textOf(i+1, typeEnd)}
+	case s.at(i, "drop"):
+		i++
+		kind := alterDropColumn
+		if s.at(i, "constraint") {
+			kind = alterDropConstraint
+			i++
+		} else if s.at(i, "column") {
+			i++
+		}
+		if s.at(i, "if") && s.at(i+1, "exists") {
+			i += 2
+		}
+		return alterAction{kind: kind, name: name(i)}
+	case s.at(i, "rename"):
+		i++
+		if s.at(i, "to") {
+			return alterAction{kind: alterRenameTable, name: name(i + 1)}
+		}
+		if s.at(i, "constraint") {
+			return alterAction{kind: alterOther}
+		}
+		if s.at(i, "column") {
+			i++
+		}
+		if s.at(i+1, "to") {
+			return alterAction{kind: alterRenameColumn, name: name(i), newName: name(i + 2)}
+		}
+	}
+	return alterAction{kind: alterOther}
+}
+
+type dropInfo struct {
+	names    []tableName
+	ifExists bool
+	cascade  bool
+}
+
+// drop parses "DROP TABLE" and "DROP INDEX".
+func (s *stmt) drop() (*dropInfo, bool) {
+	if s.kind != stmtDropTable && s.kind != stmtDropIndex {
+		return nil, false
+	}
+	info := &dropInfo{}
+	i := s.body + 2
+	if s.at(i, "concurrently") {
+		i++
+	}
+	if s.at(i, "if") && s.at(i+1, "exists") {
+		info.ifExists = true
+		i += 2
+	}
+	for {
+		name, next, ok := s.parseName(i)
+		if !ok {
+			return nil, false
+		}
+		info.names = append(info.names, name)
+		i = next
+		if !s.atPunct(i, ",") {
+			break
+		}
+		i++
+	}
+	info.cascade = s.at(i, "cascade")
+	return info, true
+}
+
+type dmlInfo struct {
+	table tableName
+	alias string
+	// whereFrom and whereTo are the token index range of WHERE condition, whereFrom is -1 without WHERE.
+	whereFrom int
+	whereTo   int
+	// hasFrom is true for "UPDATE ... FROM" and "DELETE ... USING" which join other tables.
+	hasFrom bool
+	// setColumns is the columns assigned by "UPDATE ... SET".
+	setColumns []string
+	// hasReturning is true for the statement with "RETURNING" clause.
+	hasReturning bool
+}
+
+// dml parses "UPDATE" and "DELETE".
+func (s *stmt) dml() (*dmlInfo, bool) {
+	i := s.body + 1
+	switch s.kind {
+	case stmtUpdate:
+	case stmtDelete:
+		if !s.at(i, "from") {
+			return nil, false
+		}
+		i++
+	default:
+		return nil, false
+	}
+	if s.at(i, "only") {
+		i++
+	}
+	table, i, ok := s.parseName(i)
+	if !ok {
+		return nil, false
+	}
+	if s.atPunct(i, "*") {
+		i++
+	}
+	info := &dmlInfo{table: table, whereFrom: -1}
+	if s.at(i, "as") {
+		i++
+	}
+	if i < len(s.tokens) && s.tokens[i].isIdent() && !s.at(i, "set") && !s.at(i, "where") && !s.at(i, "using") &&
+		!s.at(i, "returning") {
+		info.alias = s.tokens[i].name()
+		i++
+	}
+
+	end := len(s.tokens)
+	if k := s.findKeyword(i, end, "returning"); k >= 0 {
+		info.hasReturning = true
+		end = k
+	}
+	where := s.findKeyword(i, end, "where")
+	if where >= 0 {
+		info.whereFrom, info.whereTo = where+1, end
+		if s.at(where+1, "current") && s.at(where+2, "of") {
+			// "WHERE CURRENT OF cursor" updates the row of cursor.
+			info.whereFrom, info.whereTo = -1, -1
+		}
+		end = where
+	}
+	info.hasFrom = s.findKeyword(i, end, "from", "using") >= 0
+
+	if s.kind == stmtUpdate && s.at(i, "set") {
+		setEnd := s.findKeyword(i+1, end, "from")
+		if setEnd < 0 {
+			setEnd = end
+		}
+		for _, item := range s.splitList(i+1, setEnd) {
+			j := item[0]
+			if s.atPunct(j, "(") {
+				// "SET (a, b) = (1, 2)"
+				info.setColumns = append(info.setColumns, s.identList(j)...)
+				continue
+			}
+			if j < item[1] && s.tokens[j].isIdent() {
+				info.setColumns = append(info.setColumns, s.tokens[j].name())
+			}
+		}
+	}
+	return info, true
+}
+
+type insertInfo struct {
+	table   tableName
+	columns []string
+	// rows is the token index ranges of each value in "VALUES (...), (...)", it is nil for "INSERT ... SELECT".
+	rows [][][2]int
+	// onConflict is true for "INSERT ... ON CONFLICT".
+	onConflict   bool
+	defaultRows  bool
+	hasReturning bool
+}
+
+func (s *stmt) insert() (*insertInfo, bool) {
+	if s.kind != stmtInsert || !s.at(s.body+1, "into") {
+		return nil, false
+	}
+	table, i, ok := s.parseName(s.body + 2)
+	if !ok {
+		return nil, false
+	}
+	info := &insertInfo{table: table}
+	if s.at(i, "as") {
+		i += 2
+	}
+	if s.atPunct(i, "(") {
+		info.columns = s.identList(i)
+		i = s.skipParens(i)
+	}
+	if s.at(i, "overriding") {
+		i += 3
+	}
+	if k := s.findKeyword(i, len(s.tokens), "returning"); k >= 0 {
+		info.hasReturning = true
+	}
+	if k := s.findKeyword(i, len(s.tokens), "on"); k >= 0 && s.at(k+1, "conflict") {
+		info.onConflict = true
+	}
+	switch {
+	case s.at(i, "default") && s.at(i+1, "values"):
+		info.defaultRows = true
+	case s.at(i, "values"):
+		i++
+		info.rows = [][][2]int{}
+		for s.atPunct(i, "(") {
+			end := s.skipParens(i)
+			info.rows = append(info.rows, s.splitList(i+1, end-1))
+			i = end
+			if !s.atPunct(i, ",") {
+				break
+			}
+			i++
+		}
+	}
+	return info, true
+}
+
+// subqueryOwners are the tokens before the parenthesis which may contains subquery, the FROM in
+// other parenthesis belongs to function call, e.g. "extract(year from t)".
+var subqueryOwners = map[string]struct{}{
+	"in": {}, "exists": {}, "from": {}, "join": {}, "as": {}, "lateral": {}, "any": {}, "all": {}, "some": {},
+	"select": {}, "where": {}, "and": {}, "or": {}, "not": {}, "on": {}, "union": {}, "except": {}, "intersect": {},
+	"into": {}, "values": {}, "using": {}, "set": {}, "array": {},
+}
+
+// tables returns the tables referenced by statement, the CTE names are excluded.
+func (s *stmt) tables() []tableName {
+	tables := []tableName{}
+	seen := map[tableName]struct{}{}
+	add := func(t tableName) {
+		if _, ok := s.cteNames[t.name]; ok && t.schema == "" {
+			return
+		}
+		if _, ok := seen[t]; ok {
+			return
+		}
+		seen[t] = struct{}{}
+		tables = append(tables, t)
+	}
+
+	switch s.kind {
+	case stmtCreateTable:
+		if info, ok := s.createTable(); ok {
+			add(info.table)
+		}
+	case stmtCreateIndex:
+		if info, ok := s.createIndex(); ok {
+			add(info.table)
+		}
+	case stmtAlterTable:
+		if info, ok := s.alterTable(); ok {
+			add(info.table)
+		}
+	case stmtDropTable:
+		if info, ok := s.drop(); ok {
+			for _, name := range info.names {
+				add(name)
+			}
+		}
+	case stmtTruncate:
+		i := s.body + 1
+		if s.at(i, "table") {
+			i++
+		}
+		for {
+			if s.at(i, "only") {
+				i++
+			}
+			name, next, ok := s.parseName(i)
+			if !ok {
+				break
+			}
+			add(name)
+			i = next
+			if s.atPunct(i, "*") {
+				i++
+			}
+			if !s.atPunct(i, ",") {
+				break
+			}
+			i++
+		}
+	case stmtUpdate, stmtDelete:
+		if info, ok := s.dml(); ok {
+			add(info.table)
+		}
+	case stmtInsert:
+		if info, ok := s.insert(); ok {
+			add(info.table)
+		}
+	}
+
+	if s.sqlType() != driverV2.SQLTypeDML {
+		return tables
+	}
+	// the tables in FROM and JOIN clause of DML, includes the subqueries.
+	type frame struct {
+		// owner is the token before parenthesis.
+		owner  string
+		inFrom bool
+	}
+	frames := []*frame{{}}
+	for i := 0; i < len(s.tokens); i++ {
+		t := s.tokens[i]
+		f := frames[len(frames)-1]
+		switch {
+		case t.isPunct("("):
+			owner := ""
+			if i > 0 && s.tokens[i-1].kind == tokenIdent {
+				owner = s.tokens[i-1].name()
+			}
+			frames = append(frames, &frame{owner: owner})
+			continue
+		case t.isPunct(")"):
+			if len(frames) > 1 {
+				frames = frames[:len(frames)-1]
+			}
+			continue
+		}
+		if _, ok := subqueryOwners[f.owner]; !ok && f.owner != "" {
+			continue
+		}
+		switch {
+		case t.is("from") || t.is("join") || t.is("using") && s.kind == stmtDelete:
+			f.inFrom = true
+		case t.isPunct(","):
+			if !f.inFrom {
+				continue
+			}
+		default:
+			if isClauseKeyword(t) && !t.is("on") && !t.is("using") && !isJoinKeyword(t) {
+				f.inFrom = false
+			}
+			continue
+		}
+		j := i + 1
+		if s.at(j, "only") || s.at(j, "lateral") {
+			j++
+		}
+		name, next, ok := s.parseName(j)
+		// the function in FROM clause is skipped, e.g. "generate_series(1, 10)".
+		if ok && !s.atPunct(next, "(") {
+			add(name)
+		}
+	}
+	return tables
+}
+
+func isJoinKeyword(t token) bool {
+	switch t.name() {
+	case "join", "inner", "left", "right", "full", "cross", "natural", "lateral", "tablesample":
+		return true
+	}
+	return false
+}
+
+// isClauseKeyword returns true for the keywords which may follow the table name in FROM clause.
+func isClauseKeyword(t token) bool {
+	if t.kind != tokenIdent {
+		return false
+	}
+	switch t.name() {
+	case "where", "group", "having", "order", "limit", "offset", "fetch", "for", "window", "union", "except",
+		"intersect", "join", "inner", "left", "right", "full", "cross", "natural", "on", "using", "returning", "set",
+		"tablesample", "lateral":
+		return true
+	}
+	return false
+}
+
+// hasSelectAll returns true if the statement selects all columns, e.g. "SELECT *" and "SELECT t.*".
+func (s *stmt) hasSelectAll() bool {
+	for i := 1; i < len(s.tokens); i++ {
+		if !s.tokens[i].isPunct("*") {
+			continue
+		}
+		prev := s.tokens[i-1]
+		if prev.is("select") || prev.is("distinct") || prev.is("all") || prev.isPunct(",") || prev.isPunct(".") {
+			return true
+		}
+	}
+	return false
+}
+
+// isAlwaysTrue returns true if the condition between index from and to is constant true, e.g. "1=1" and "true".
+func (s *stmt) isAlwaysTrue(from, to int) bool {
+	for from < to && s.atPunct(from, "(") && s.skipParens(from) == to {
+		from, to = from+1, to-1
+	}
+	switch to - from {
+	case 1:
+		return s.at(from, "true")
+	case 3:
+		l, op, r := s.tokens[from], s.tokens[from+1], s.tokens[to-1]
+		isConst := func(t token) bool { return t.kind == tokenNumber || t.kind == tokenString }
+		return op.isPunct("=") && isConst(l) && isConst(r) && l.val == r.val
+	}
+	return false
+}
@@ -0,0 +1,249 @@
+package postgresql
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+type tokenKind int
+
+const (
+	tokenIdent tokenKind = iota
+	// tokenQuotedIdent is the identifier quoted by double quotes, e.g. "User".
+	tokenQuotedIdent
+	// tokenString is the string constant, e.g. 'abc', E'a\'b', $$abc$$, $tag$abc$tag$.
+	tokenString
+	tokenNumber
+	// tokenParam is the positional parameter, e.g. $1.
+	tokenParam
+	tokenOperator
+	tokenPunct
+)
+
+type token struct {
+	kind tokenKind
+	// val is the raw text of token.
+	val string
+	// start and end are the byte offsets of token in SQL text.
+	start int
+	end   int
+}
+
+// name returns the identifier which the token represents, the unquoted identifier
+// is folded to lower case like PostgreSQL does.
+func (t token) name() string {
+	switch t.kind {
+	case tokenQuotedIdent:
+		return strings.ReplaceAll(t.val[1:len(t.val)-1], `""`, `"`)
+	case tokenIdent:
+		return strings.ToLower(t.val)
+	default:
+		return t.val
+	}
+}
+
+// is returns true if the token is the keyword, the keyword should be lower case.
+func (t token) is(keyword string) bool {
+	return t.kind == tokenIdent && strings.EqualFold(t.val, keyword)
+}
+
+func (t token) isPunct(p string) bool {
+	return (t.kind == tokenPunct || t.kind == tokenOperator) && t.val == p
+}
+
+func (t token) isIdent() bool {
+	return t.kind == tokenIdent || t.kind == tokenQuotedIdent
+}
+
+// operatorChars is the characters which can be part of multi-character operator.
+const operatorChars = "+-*/<>=~!@#%^&|`?"
+
+// tokenize splits the SQL text into tokens, the whitespaces and comments are dropped.
+func tokenize(sql string) ([]token, error) {
+	tokens := []token{}
+	i := 0
+	for i < len(sql) {
+		c := sql[i]
+		start := i
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == '\f':
+			i++
+			continue
+		case c == '-' && strings.HasPrefix(sql[i:], "--"):
+			end := strings.IndexByte(sql[i:], '\n')
+			if end < 0 {
+				return tokens, nil
+			}
+			i += end + 1
+			continue
+		case c == '/' && strings.HasPrefix(sql[i:], "/*"):
+			// the block comments can be nested in PostgreSQL.
+			depth := 0
+			for i < len(sql) {
+				if strings.HasPrefix(sql[i:], "/*") {
+					depth++
+					i += 2
+				} else if strings.HasPrefix(sql[i:], "*/") {
+					depth--
+					i += 2
+					if depth == 0 {
+						break
+					}
+				} else {
+					i++
+				}
+			}
+			if depth != 0 {
+				return nil, fmt.Errorf("unterminated /* comment at position %d", start)
+			}
+			continue
+		case c == '\'':
+			end, err := scanQuoted(sql, i, '\'', false)
+			if err != nil {
+				return nil, err
+			}
+			i = end
+			tokens = append(tokens, token{kind: tokenString, val: sql[start:i], start: start, end: i})
+		case (c == 'e' || c == 'E') && i+1 < len(sql) && sql[i+1] == '\'':
+			end, err := scanQuoted(sql, i+1, '\'', true)
+			if err != nil {
+				return nil, err
+			}
+			i = end
+			tokens = append(tokens, token{kind: tokenString, val: sql[start:i], start: start, end: i})
+		case c == '"':
+			end, err := scanQuoted(sql, i, '"', false)
+			if err != nil {
+				return nil, err
+			}
+			i = end
+			tokens = append(tokens, token{kind: tokenQuotedIdent, val: sql[start:i], start: start, end: i})
+		case c == '$':
+			if i+1 < len(sql) && isDigit(sql[i+1]) {
+				i++
+				for i < len(sql) && isDigit(sql[i]) {
+					i++
+				}
+				tokens = append(tokens, token{kind: tokenParam, val: sql[start:i], start: start, end: i})
+				continue
+			}
+			tag, ok := scanDollarTag(sql, i)
+			if !ok {
+				return nil, fmt.Errorf("syntax error at or near \"$\" at position %d", start)
+			}
+			end := strings.Index(sql[i+len(tag):], tag)
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated dollar-quoted string at position %d", start)
+			}
+			i += len(tag) + end + len(tag)
+			tokens = append(tokens, token{kind: tokenString, val: sql[start:i], start: start, end: i})
+		case isDigit(c) || c == '.' && i+1 < len(sql) && isDigit(sql[i+1]):
+			i = scanNumber(sql, i)
+			tokens = append(tokens, token{kind: tokenNumber, val: sql[start:i], start: start, end: i})
+		case isIdentStart(sql, i):
+			for i < len(sql) && isIdentPart(sql, i) {
+				_, size := utf8.DecodeRuneInString(sql[i:])
+				i += size
+			}
+			tokens = append(tokens, token{kind: tokenIdent, val: sql[start:i], start: start, end: i})
+		case c == ':' && strings.HasPrefix(sql[i:], "::"):
+			i += 2
+			tokens = append(tokens, token{kind: tokenOperator, val: "::", start: start, end: i})
+		case strings.IndexByte(operatorChars, c) >= 0:
+			for i < len(sql) && strings.IndexByte(operatorChars, sql[i]) >= 0 {
+				// the comment start ends the operator.
+				if strings.HasPrefix(sql[i:], "--") || strings.HasPrefix(sql[i:], "/*") {
+					break
+				}
+				i++
+			}
+			tokens = append(tokens, token{kind: tokenOperator, val: sql[start:i], start: start, end: i})
+		default:
+			_, size := utf8.DecodeRuneInString(sql[i:])
+			i += size
+			tokens = append(tokens, token{kind: tokenPunct, val: sql[start:i], start: start, end: i})
+		}
+	}
+	return tokens, nil
+}
+
+// scanQuoted returns the end offset of the quoted text which starts at offset i, the quote
+// character is escaped by doubling it, backslash escapes are only allowed in escape strings, e.g. E'a\'b'.
+func scanQuoted(sql string, i int, quote byte, backslashEscape bool) (int, error) {
+	start := i
+	i++
+	for i < len(sql) {
+		switch sql[i] {
+		case '\\':
+			if backslashEscape {
+				i += 2
+				continue
+			}
+		case quote:
+			if i+1 < len(sql) && sql[i+1] == quote {
+				i += 2
+				continue
+			}
+			return i + 1, nil
+		}
+		i++
+	}
+	if quote == '"' {
+		return 0, fmt.Errorf("unterminated quoted identifier at position %d", start)
+	}
+	return 0, fmt.Errorf("unterminated quoted string at position %d", start)
+}
+
+// scanDollarTag returns the tag of dollar-quoted string which starts at offset i, e.g. "$$" or "$body$".
+func scanDollarTag(sql string, i int) (string, bool) {
+	j := i + 1
+	for j < len(sql) && sql[j] != '$' {
+		if !isIdentPart(sql, j) || isDigit(sql[j]) && j == i+1 {
+			return "", false
+		}
+		j++
+	}
+	if j >= len(sql) {
+		return "", false
+	}
+	return sql[i : j+1], true
+}
+
+func scanNumber(sql string, i int) int {
+	for i < len(sql) && (isDigit(sql[i]) || sql[i] == '.' || sql[i] == '_') {
+		// ".." is not part of number, e.g. array slice "a[1..2]" is not supported by PostgreSQL either.
+		if sql[i] == '.' && i+1 < len(sql) && sql[i+1] == '.' {
+			return i
+		}
+		i++
+	}
+	if i < len(sql) && (sql[i] == 'e' || sql[i] == 'E') {
+		j := i + 1
+		if j < len(sql) && (sql[j] == '+' || sql[j] == '-') {
+			j++
+		}
+		if j < len(sql) && isDigit(sql[j]) {
+			i = j
+			for i < len(sql) && isDigit(sql[i]) {
+				i++
+			}
+		}
+	}
+	return i
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func isIdentStart(sql string, i int) bool {
+	r, _ := utf8.DecodeRuneInString(sql[i:])
+	return r == '_' || unicode.IsLetter(r)
+}
+
+func isIdentPart(sql string, i int) bool {
+	r, _ := utf8.DecodeRuneInString(sql[i:])
+	return r == '_' || r == '$' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
@@ -0,0 +1,452 @@
+package postgresql
+
+import (
+	"fmt"
+	"strings"
+
+	driverV2 "github.com/actiontech/sqle/sqle/driver/v2"
+)
+
+type stmtKind int
+
+const (
+	stmtUnknown stmtKind = iota
+	stmtSelect
+	stmtInsert
+	stmtUpdate
+	stmtDelete
+	stmtCreateTable
+	stmtCreateIndex
+	stmtAlterTable
+	stmtDropTable
+	stmtDropIndex
+	stmtTruncate
+)
+
+// stmt is a single SQL statement with its tokens. The statement is not parsed into a full AST,
+// the analyzers below only recognize the clauses which the rules and rollback need.
+type stmt struct {
+	text   string
+	tokens []token
+	kind   stmtKind
+	// body is the index of the first token of statement after the WITH clause.
+	body int
+	// cteNames is the names of common table expressions, they are not real tables.
+	cteNames map[string]struct{}
+	// modifyingCTE is true if any common table expression writes the data, e.g.
+	// "WITH d AS (DELETE FROM t1 RETURNING *) SELECT * FROM d", the statement is not read only
+	// even though its body is SELECT.
+	modifyingCTE bool
+}
+
+type tableName struct {
+	schema string
+	name   string
+}
+
+func (t tableName) String() string {
+	if t.schema == "" {
+		return quoteIdent(t.name)
+	}
+	return quoteIdent(t.schema) + "." + quoteIdent(t.name)
+}
+
+// parseSQL splits the SQL text into statements. The statements are split by the semicolons
+// at the top level, the semicolons in parenthesis, e.g. "CREATE RULE ... DO (INSERT ...; ...)",
+// and in the SQL-standard function body "BEGIN ATOMIC ... END" do not end the statement.
+func parseSQL(sql string) ([]*stmt, error) {
+	tokens, err := tokenize(sql)
+	if err != nil {
+		return nil, err
+	}
+	stmts := []*stmt{}
+	begin := 0
+	// parenDepth is the depth of parenthesis, atomicDepth is the depth of "BEGIN ATOMIC"
+	// block and caseDepth is the depth of "CASE ... END" in the block, since both of them
+	// are ended by END.
+	parenDepth, atomicDepth, caseDepth := 0, 0, 0
+	for i := 0; i <= len(tokens); i++ {
+		if i < len(tokens) {
+			t := tokens[i]
+			switch {
+			case t.isPunct("("):
+				parenDepth++
+			case t.isPunct(")"):
+				if parenDepth > 0 {
+					parenDepth--
+				}
+			case t.is("begin") && i+1 < len(tokens) && tokens[i+1].is("atomic"):
+				atomicDepth++
+			case t.is("case") && atomicDepth > 0:
+				caseDepth++
+			case t.is("end") && atomicDepth > 0:
+				if caseDepth > 0 {
+					caseDepth--
+				} else {
+					atomicDepth--
+				}
+			}
+			if !t.isPunct(";") || parenDepth > 0 || atomicDepth > 0 {
+				continue
+			}
+		}
+		if parenDepth > 0 || atomicDepth > 0 {
+			return nil, fmt.Errorf("syntax error at end of input, unterminated parenthesis or BEGIN ATOMIC block")
+		}
+		if i > begin {
+			text := sql[tokens[begin].start:tokens[i-1].end]
+			stmts = append(stmts, newStmt(text, tokens[begin:i]))
+		}
+		begin = i + 1
+	}
+	return stmts, nil
+}
+
+// parseOneSQL parses the SQL text which should be a single statement.
+func parseOneSQL(sql string) (*stmt, error) {
+	stmts, err := parseSQL(sql)
+	if err != nil {
+		return nil, err
+	}
+	if len(stmts) != 1 {
+		return nil, errSingleSQLRequired
+	}
+	return stmts[0], nil
+}
+
+func newStmt(text string, tokens []token) *stmt {
+	// the token offsets are rebased on the statement text.
+	base := tokens[0].start
+	rebased := make([]token, len(tokens))
+	for i, t := range tokens {
+		t.start -= base
+		t.end -= base
+		rebased[i] = t
+	}
+	s := &stmt{text: text, tokens: rebased, cteNames: map[string]struct{}{}}
+	s.body = s.skipWith()
+	s.kind = s.classify()
+	return s
+}
+
+// skipWith returns the index of the first token after "WITH [RECURSIVE] name [(cols)] AS [NOT] [MATERIALIZED] (...), ...".
+func (s *stmt) skipWith() int {
+	i := 0
+	if !s.at(i, "with") {
+		return 0
+	}
+	i++
+	if s.at(i, "recursive") {
+		i++
+	}
+	for i < len(s.tokens) {
+		if !s.tokens[i].isIdent() {
+			return i
+		}
+		s.cteNames[s.tokens[i].name()] = struct{}{}
+		i++
+		if s.atPunct(i, "(") {
+			i = s.skipParens(i)
+		}
+		if !s.at(i, "as") {
+			return i
+		}
+		i++
+		if s.at(i, "not") {
+			i++
+		}
+		if s.at(i, "materialized") {
+			i++
+		}
+		if !s.atPunct(i, "(") {
+			return i
+		}
+		if s.at(i+1, "insert") || s.at(i+1, "update") || s.at(i+1, "delete") || s.at(i+1, "merge") {
+			s.modifyingCTE = true
+		}
+		i = s.skipParens(i)
+		if !s.atPunct(i, ",") {
+			return i
+		}
+		i++
+	}
+	return i
+}
+
+func (s *stmt) classify() stmtKind {
+	i := s.body
+	switch {
+	case s.at(i, "select"), s.at(i, "values"), s.at(i, "table"), s.atPunct(i, "("):
+		// the SELECT which writes by CTE or creates table by "SELECT ... INTO t" is not a query.
+		if s.modifyingCTE || s.at(i, "select") && s.findKeyword(i, len(s.tokens), "into") >= 0 {
+			return stmtUnknown
+		}
+		return stmtSelect
+	case s.at(i, "insert"):
+		return stmtInsert
+	case s.at(i, "update"):
+		return stmtUpdate
+	case s.at(i, "delete"):
+		return stmtDelete
+	case s.at(i, "truncate"):
+		return stmtTruncate
+	case s.at(i, "create"):
+		j := i + 1
+		for s.at(j, "global") || s.at(j, "local") || s.at(j, "temp") || s.at(j, "temporary") ||
+			s.at(j, "unlogged") || s.at(j, "unique") {
+			j++
+		}
+		if s.at(j, "table") {
+			return stmtCreateTable
+		}
+		if s.at(j, "index") {
+			return stmtCreateIndex
+		}
+	case s.at(i, "alter"):
+		if s.at(i+1, "table") {
+			return stmtAlterTable
+		}
+	case s.at(i, "drop"):
+		if s.at(i+1, "table") {
+			return stmtDropTable
+		}
+		if s.at(i+1, "index") {
+			return stmtDropIndex
+		}
+	}
+	return stmtUnknown
+}
+
+// sqlType returns the type of statement, the statement which reads or writes the data is DML.
+func (s *stmt) sqlType() string {
+	switch s.kind {
+	case stmtSelect, stmtInsert, stmtUpdate, stmtDelete:
+		return driverV2.SQLTypeDML
+	}
+	if s.modifyingCTE || s.at(s.body, "merge") {
+		return driverV2.SQLTypeDML
+	}
+	// "EXPLAIN ANALYZE" executes the statement.
+	if s.at(s.body, "explain") {
+		analyze := false
+		for i := s.body + 1; i < len(s.tokens); i++ {
+			if s.at(i, "analyze") || s.at(i, "analyse") {
+				analyze = true
+			}
+			if analyze && (s.at(i, "insert") || s.at(i, "update") || s.at(i, "delete") || s.at(i, "merge")) {
+				return driverV2.SQLTypeDML
+			}
+		}
+	}
+	return driverV2.SQLTypeDDL
+}
+
+// at returns true if the token at index i is the keyword.
+func (s *stmt) at(i int, keyword string) bool {
+	return i >= 0 && i < len(s.tokens) && s.tokens[i].is(keyword)
+}
+
+func (s *stmt) atPunct(i int, p string) bool {
+	return i >= 0 && i < len(s.tokens) && s.tokens[i].isPunct(p)
+}
+
+// skipParens returns the index after the parenthesis which starts at index i.
+func (s *stmt) skipParens(i int) int {
+	depth := 0
+	for ; i < len(s.tokens); i++ {
+		if s.tokens[i].isPunct("(") || s.tokens[i].isPunct("[") {
+			depth++
+		} else if s.tokens[i].isPunct(")") || s.tokens[i].isPunct("]") {
+			depth--
+			if depth == 0 {
+				return i + 1
+			}
+		}
+	}
+	return i
+}
+
+// findKeyword returns the index of the first keyword at the top level (not in parenthesis)
+// between index from and to, it returns -1 if the keyword is not found.
+func (s *stmt) findKeyword(from, to int, keywords ...string) int {
+	if to > len(s.tokens) {
+		to = len(s.tokens)
+	}
+	for i := from; i < to; {
+		if s.tokens[i].isPunct("(") || s.tokens[i].isPunct("[") {
+			i = s.skipParens(i)
+			continue
+		}
+		for _, keyword := range keywords {
+			if s.tokens[i].is(keyword) {
+				return i
+			}
+		}
+		i++
+	}
+	return -1
+}
+
+// textOf returns the raw text of tokens between index from and to.
+func (s *stmt) textOf(from, to int) string {
+	if to > len(s.tokens) {
+		to = len(s.tokens)
+	}
+	if from >= to {
+		return ""
+	}
+	return s.text[s.tokens[from].start:s.tokens[to-1].end]
+}
+
+// parseName parses the qualified name "[schema.]name" at index i, it returns the index after the name.
+func (s *stmt) parseName(i int) (tableName, int, bool) {
+	if i >= len(s.tokens) || !s.tokens[i].isIdent() {
+		return tableName{}, i, false
+	}
+	parts := []string{s.tokens[i].name()}
+	i++
+	for s.atPunct(i, ".") && i+1 < len(s.tokens) && s.tokens[i+1].isIdent() {
+		parts = append(parts, s.tokens[i+1].name())
+		i += 2
+	}
+	// "database.schema.name" is allowed, but the database must be the current database.
+	if len(parts) > 2 {
+		parts = parts[len(parts)-2:]
+	}
+	if len(parts) == 2 {
+		return tableName{schema: parts[0], name: parts[1]}, i, true
+	}
+	return tableName{name: parts[0]}, i, true
+}
+
+// splitList splits the tokens between index from and to by the top level commas, it returns
+// the [start, end) index pairs of each item.
+func (s *stmt) splitList(from, to int) [][2]int {
+	items := [][2]int{}
+	begin := from
+	for i := from; i < to; {
+		if s.tokens[i].isPunct("(") || s.tokens[i].isPunct("[") {
+			i = s.skipParens(i)
+			continue
+		}
+		if s.tokens[i].isPunct(",") {
+			items = append(items, [2]int{begin, i})
+			begin = i + 1
+		}
+		i++
+	}
+	if begin < to {
+		items = append(items, [2]int{begin, to})
+	}
+	return items
+}
+
+// fingerprint returns the fingerprint of statement, the constants are replaced by "?", the
+// keywords and unquoted identifiers are folded to lower case and the whitespaces are normalized.
+func (s *stmt) fingerprint() string {
+	type part struct {
+		val string
+		// spaced is true if there are whitespaces before the token in SQL text.
+		spaced bool
+	}
+	parts := make([]part, 0, len(s.tokens))
+	for i, t := range s.tokens {
+		p := part{val: t.val, spaced: i > 0 && s.tokens[i-1].end < t.start}
+		switch t.kind {
+		case tokenString, tokenNumber, tokenParam:
+			p.val = "?"
+		case tokenIdent:
+			p.val = strings.ToLower(t.val)
+		}
+		parts = append(parts, p)
+	}
+	// collapse the constant lists, e.g. "in (?, ?, ?)" and "values (?, ?), (?, ?)".
+	collapsed := make([]part, 0, len(parts))
+	for i := 0; i < len(parts); i++ {
+		if parts[i].val == "(" {
+			j := i + 1
+			for j+1 < len(parts) && parts[j].val == "?" && parts[j+1].val == "," {
+				j += 2
+			}
+			if j+1 < len(parts) && parts[j].val == "?" && parts[j+1].val == ")" {
+				collapsed = append(collapsed, parts[i], part{val: "?+"}, part{val: ")"})
+				i = j + 1
+				continue
+			}
+		}
+		collapsed = append(collapsed, parts[i])
+	}
+
+	b := strings.Builder{}
+	for i, p := range collapsed {
+		if i > 0 && needSpace(collapsed[i-1].val, p.val, p.spaced) {
+			b.WriteByte(' ')
+		}
+		b.WriteString(p.val)
+	}
+	fp := b.String()
+	for strings.Contains(fp, "(?+), (?+)") {
+		fp = strings.ReplaceAll(fp, "(?+), (?+)", "(?+)")
+	}
+	return fp
+}
+
+func needSpace(prev, cur string, spaced bool) bool {
+	switch {
+	case cur == "," || cur == ")" || cur == "." || cur == "]" || cur == "::":
+		return false
+	case prev == "(" || prev == "." || prev == "[" || prev == "::":
+		return false
+	case cur == "(" || cur == "[":
+		// the function call and type modifier are written without space usually, e.g. "count(*)" and "varchar(10)".
+		return spaced || !isWordPart(prev)
+	}
+	return true
+}
+
+func isWordPart(s string) bool {
+	if s == "" {
+		return false
+	}
+	c := s[len(s)-1]
+	return c == '_' || c == '"' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9'
+}
+
+// quoteIdent quotes the identifier if it is not a simple lower case identifier.
+func quoteIdent(name string) string {
+	if name == "" {
+		return `""`
+	}
+	simple := !isDigit(name[0])
+	for i := 0; i < len(name) && simple; i++ {
+		c := name[i]
+		simple = c == '_' || c >= 'a' && c <= 'z' || isDigit(c)
+	}
+	if simple && !isReservedKeyword(name) {
+		return name
+	}
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
+
+// quoteLiteral quotes the text as a string constant.
+func quoteLiteral(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
+
+var reservedKeywords = map[string]struct{}{}
+
+func init() {
+	for _, k := range strings.Fields(`all analyse analyze and any array as asc asymmetric both case cast check collate column
+		constraint create current_catalog current_date current_role current_time current_timestamp current_user default
+		deferrable desc distinct do else end except false fetch for foreign from grant group having in initially
+		intersect into lateral leading limit localtime localtimestamp not null offset on only or order placing primary
+		references returning select session_user some symmetric table then to trailing true union unique user using
+		variadic when where window with`) {
+		reservedKeywords[k] = struct{}{}
+	}
+}
+
+func isReservedKeyword(name string) bool {
+	_, ok := reservedKeywords[name]
+	return ok
+}
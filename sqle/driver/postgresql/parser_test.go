@@ -0,0 +1,150 @@
+package postgresql
+
+import (
+	"testing"
+
+	driverV2 "github.com/actiontech/sqle/sqle/driver/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseSQL(t *testing.T) {
+	stmts, err := parseSQL(`-- comment; not split
+select 'a;b', "c;d" from t1; /* nested /* comment; */ */
+CREATE FUNCTION f() RETURNS int AS $body$ SELECT 1; $body$ LANGUAGE sql;
+update t1 set a = E'x\';' where id = $1;;`)
+	assert.NoError(t, err)
+	if assert.Len(t, stmts, 3) {
+		assert.Equal(t, `select 'a;b', "c;d" from t1`, stmts[0].text)
+		assert.Equal(t, `CREATE FUNCTION f() RETURNS int AS $body$ SELECT 1; $body$ LANGUAGE sql`, stmts[1].text)
+		assert.Equal(t, `update t1 set a = E'x\';' where id = $1`, stmts[2].text)
+		assert.Equal(t, driverV2.SQLTypeDML, stmts[0].sqlType())
+		assert.Equal(t, driverV2.SQLTypeDDL, stmts[1].sqlType())
+		assert.Equal(t, driverV2.SQLTypeDML, stmts[2].sqlType())
+	}
+
+	// the semicolons in parenthesis and "BEGIN ATOMIC ... END" do not end the statement.
+	stmts, err = parseSQL(`CREATE RULE r AS ON INSERT TO t1 DO ALSO (INSERT INTO t2 VALUES (1); DELETE FROM t3);
+CREATE FUNCTION f(a int) RETURNS int LANGUAGE sql BEGIN ATOMIC SELECT CASE WHEN a > 0 THEN 1 ELSE 0 END; SELECT 2; END;
+DO $do$ BEGIN PERFORM 1; END $do$`)
+	assert.NoError(t, err)
+	if assert.Len(t, stmts, 3) {
+		assert.Equal(t, `CREATE RULE r AS ON INSERT TO t1 DO ALSO (INSERT INTO t2 VALUES (1); DELETE FROM t3)`, stmts[0].text)
+		assert.Equal(t, `CREATE FUNCTION f(a int) RETURNS int LANGUAGE sql BEGIN ATOMIC SELECT CASE WHEN a > 0 THEN 1 ELSE 0 END; SELECT 2; END`, stmts[1].text)
+		assert.Equal(t, `DO $do$ BEGIN PERFORM 1; END $do$`, stmts[2].text)
+	}
+
+	for _, sql := range []string{"select 'a", `select "a`, "select $$a", "select /* a", "select (1; select 2",
+		"create function f() returns int language sql begin atomic select 1;"} {
+		_, err := parseSQL(sql)
+		assert.Error(t, err, sql)
+	}
+}
+
+func TestStmtFingerprint(t *testing.T) {
+	for sql, expect := range map[string]string{
+		"SELECT  a, b FROM T1 WHERE id = 1 AND name = 'x'":       "select a, b from t1 where id = ? and name = ?",
+		"select * from t1 where id in (1, 2, 3)":                 "select * from t1 where id in (?+)",
+		`insert into "T1" (a, b) values (1, 'a'), (2, 'b')`:      `insert into "T1" (a, b) values (?+)`,
+		"select count(*) from t1 where created_at > $1::date":    "select count(*) from t1 where created_at > ?::date",
+		"update t1 set a = -1.5e3, b = $$x$$ where c.d = E'\\''": "update t1 set a = - ?, b = ? where c.d = ?",
+	} {
+		s, err := parseOneSQL(sql)
+		assert.NoError(t, err)
+		assert.Equal(t, expect, s.fingerprint(), sql)
+	}
+}
+
+func TestStmtTables(t *testing.T) {
+	for sql, expect := range map[string][]tableName{
+		"select a from t1 join s1.t2 on t1.id = t2.id, t3 x where exists (select 1 from t4)": {
+			{name: "t1"}, {schema: "s1", name: "t2"}, {name: "t3"}, {name: "t4"}},
+		"with c as (select * from t1) select extract(year from c.d) from c, generate_series(1, 2)": {{name: "t1"}},
+		"update t1 set a = 1 from t2 where t1.id = t2.id":                                          {{name: "t1"}, {name: "t2"}},
+		"delete from only s1.t1 using t2 where t1.id = t2.id":                                      {{schema: "s1", name: "t1"}, {name: "t2"}},
+		`insert into "T1" select * from t2`:                                                        {{name: "T1"}, {name: "t2"}},
+		"create index idx_a on t1 (a)":                                                             {{name: "t1"}},
+		"drop table if exists t1, t2 cascade":                                                      {{name: "t1"}, {name: "t2"}},
+		"truncate table only t1, t2":                                                               {{name: "t1"}, {name: "t2"}},
+	} {
+		s, err := parseOneSQL(sql)
+		assert.NoError(t, err)
+		assert.Equal(t, expect, s.tables(), sql)
+	}
+}
+
+func TestStmtCreateTable(t *testing.T) {
+	s, err := parseOneSQL(`create table if not exists s1.t1 (
+		id bigint generated always as identity,
+		name varchar(32) not null default '',
+		"Age" int,
+		constraint pk_t1 primary key (id),
+		unique (name)
+	) partition by range (id)`)
+	assert.NoError(t, err)
+	info, ok := s.createTable()
+	assert.True(t, ok)
+	assert.Equal(t, tableName{schema: "s1", name: "t1"}, info.table)
+	assert.Equal(t, []columnDef{{name: "id", typ: "bigint"}, {name: "name", typ: "varchar(32)"}, {name: "Age", typ: "int"}}, info.columns)
+	assert.True(t, info.hasPK)
+	assert.Equal(t, []string{"id"}, info.pkColumns)
+	assert.False(t, info.derived)
+
+	s, err = parseOneSQL("create table t2 as select * from t1")
+	assert.NoError(t, err)
+	info, ok = s.createTable()
+	assert.True(t, ok)
+	assert.True(t, info.derived)
+}
+
+func TestStmtAlterTable(t *testing.T) {
+	s, err := parseOneSQL("alter table t1 add column if not exists c int not null, drop column d, add constraint uk unique (c)")
+	assert.NoError(t, err)
+	info, ok := s.alterTable()
+	assert.True(t, ok)
+	assert.Equal(t, []alterAction{
+		{kind: alterAddColumn, name: "c", typ: "int"},
+		{kind: alterDropColumn, name: "d"},
+		{kind: alterAddConstraint, name: "uk"},
+	}, info.actions)
+}
+
+func TestStmtDML(t *testing.T) {
+	s, err := parseOneSQL("update t1 as a set b = 1, (c, d) = (2, 3) where a.id > 10 returning *")
+	assert.NoError(t, err)
+	info, ok := s.dml()
+	assert.True(t, ok)
+	assert.Equal(t, "a", info.alias)
+	assert.Equal(t, []string{"b", "c", "d"}, info.setColumns)
+	assert.Equal(t, "a.id > 10", s.textOf(info.whereFrom, info.whereTo))
+	assert.True(t, info.hasReturning)
+
+	s, err = parseOneSQL("insert into t1 (id, name) values (1, 'a'), (-2, 'b') on conflict do nothing")
+	assert.NoError(t, err)
+	insert, ok := s.insert()
+	assert.True(t, ok)
+	assert.Equal(t, []string{"id", "name"}, insert.columns)
+	assert.Len(t, insert.rows, 2)
+	assert.True(t, insert.onConflict)
+	value, ok := s.constant(insert.rows[1][0][0], insert.rows[1][0][1])
+	assert.True(t, ok)
+	assert.Equal(t, "-2", value)
+}
+
+func TestStmtClassify(t *testing.T) {
+	for sql, expect := range map[string]struct {
+		kind    stmtKind
+		sqlType string
+	}{
+		"with d as (delete from t1 returning *) select * from d": {stmtUnknown, driverV2.SQLTypeDML},
+		"with c as (select * from t1) select * from c":           {stmtSelect, driverV2.SQLTypeDML},
+		"select * into t2 from t1":                               {stmtUnknown, driverV2.SQLTypeDDL},
+		"select * from t1 where a in (select b into c from d)":   {stmtSelect, driverV2.SQLTypeDML},
+		"explain analyze delete from t1":                         {stmtUnknown, driverV2.SQLTypeDML},
+		"explain delete from t1":                                 {stmtUnknown, driverV2.SQLTypeDDL},
+	} {
+		s, err := parseOneSQL(sql)
+		assert.NoError(t, err)
+		assert.Equal(t, expect.kind, s.kind, sql)
+		assert.Equal(t, expect.sqlType, s.sqlType(), sql)
+	}
+}
@@ -0,0 +1,581 @@
+package postgresql
+
+import (
+	"context"
+	"database/sql"
+	sqlDriver "database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+	"time"
+
+	driverV2 "github.com/actiontech/sqle/sqle/driver/v2"
+	"github.com/actiontech/sqle/sqle/pkg/params"
+
+	_ "github.com/jackc/pgx/v4/stdlib"
+	"github.com/pkg/errors"
+)
+
+var errSingleSQLRequired = errors.New("only single SQL is supported")
+
+// Metas returns the metas of PostgreSQL plugin, the rules and optional modules are filled in.
+func Metas() *driverV2.DriverMetas {
+	rules := make([]*driverV2.Rule, len(RuleHandlers))
+	for i := range RuleHandlers {
+		rules[i] = &RuleHandlers[i].Rule
+	}
+	return &driverV2.DriverMetas{
+		PluginName:          driverV2.DriverTypePostgreSQL,
+		DatabaseDefaultPort: 5432,
+		Rules:               rules,
+		EnabledOptionalModule: []driverV2.OptionalModule{
+			driverV2.OptionalModuleGenRollbackSQL,
+			driverV2.OptionalModuleQuery,
+			driverV2.OptionalModuleExplain,
+			driverV2.OptionalModuleGetTableMeta,
+			driverV2.OptionalModuleExtractTableFromSQL,
+			driverV2.OptionalModuleEstimateSQLAffectRows,
+			driverV2.OptionalModuleListTables,
+		},
+	}
+}
+
+type DriverImpl struct {
+	cfg  *driverV2.Config
+	db   *sql.DB
+	conn *sql.Conn
+}
+
+var _ driverV2.Driver = &DriverImpl{}
+
+func NewDriver(cfg *driverV2.Config) (driverV2.Driver, error) {
+	d := &DriverImpl{cfg: cfg}
+	if cfg.DSN == nil {
+		return d, nil
+	}
+	dsn := cfg.DSN
+	database := dsn.DatabaseName
+	if database == "" {
+		database = "postgres"
+	}
+	u := &url.URL{
+		Scheme: "postgres",
+		User:   url.UserPassword(dsn.User, dsn.Password),
+		Host:   net.JoinHostPort(dsn.Host, dsn.Port),
+		Path:   "/" + database,
+	}
+	db, err := sql.Open("pgx", u.String())
+	if err != nil {
+		return nil, err
+	}
+	conn, err := db.Conn(context.TODO())
+	if err != nil {
+		db.Close()
+		return nil, errors.Wrap(err, "get database connection failed")
+	}
+	if err := conn.PingContext(context.TODO()); err != nil {
+		conn.Close()
+		db.Close()
+		return nil, errors.Wrap(err, "ping database connection failed")
+	}
+	d.db = db
+	d.conn = conn
+	return d, nil
+}
+
+func (d *DriverImpl) isOfflineAudit() bool {
+	return d.conn == nil
+}
+
+func (d *DriverImpl) getConn() (*sql.Conn, error) {
+	if d.conn == nil {
+		return nil, fmt.Errorf("database conn not initialized")
+	}
+	return d.conn, nil
+}
+
+func (d *DriverImpl) Close(ctx context.Context) {
+	if d.conn != nil {
+		d.conn.Close()
+	}
+	if d.db != nil {
+		d.db.Close()
+	}
+}
+
+func (d *DriverImpl) Ping(ctx context.Context) error {
+	conn, err := d.getConn()
+	if err != nil {
+		return err
+	}
+	return conn.PingContext(ctx)
+}
+
+func (d *DriverImpl) Exec(ctx context.Context, sqlText string) (sqlDriver.Result, error) {
+	conn, err := d.getConn()
+	if err != nil {
+		return nil, err
+	}
+	return conn.ExecContext(ctx, sqlText)
+}
+
+func (d *DriverImpl) Tx(ctx context.Context, sqls ...string) ([]sqlDriver.Result, error) {
+	conn, err := d.getConn()
+	if err != nil {
+		return nil, err
+	}
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "begin tx failed")
+	}
+	results := make([]sqlDriver.Result, 0, len(sqls))
+	for _, sqlText := range sqls {
+		result, err := tx.ExecContext(ctx, sqlText)
+		if err != nil {
+			if rollbackErr := tx.Rollback(); rollbackErr != nil {
+				return nil, errors.Wrapf(rollbackErr, "rollback tx failed after exec sql failed: %v", err)
+			}
+			return nil, err
+		}
+		results = append(results, result)
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, errors.Wrap(err, "commit tx failed")
+	}
+	return results, nil
+}
+
+func (d *DriverImpl) Query(ctx context.Context, sqlText string, conf *driverV2.QueryConf) (*driverV2.QueryResult, error) {
+	conn, err := d.getConn()
+	if err != nil {
+		return nil, err
+	}
+	if conf != nil && conf.TimeOutSecond > 0 {
+		var cancel func()
+		ctx, cancel = context.WithTimeout(ctx, time.Duration(conf.TimeOutSecond)*time.Second)
+		defer cancel()
+	}
+	rows, err := conn.QueryContext(ctx, sqlText)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+	result := &driverV2.QueryResult{
+		Column: params.Params{},
+		Rows:   []*driverV2.QueryResultRow{},
+	}
+	for _, column := range columns {
+		result.Column = append(result.Column, &params.Param{
+			Key:   column,
+			Value: column,
+			Desc:  column,
+		})
+	}
+	for rows.Next() {
+		values := make([]sql.NullString, len(columns))
+		dest := make([]interface{}, len(columns))
+		for i := range values {
+			dest[i] = &values[i]
+		}
+		if err := rows.Scan(dest...); err != nil {
+			return nil, err
+		}
+		row := &driverV2.QueryResultRow{Values: make([]*driverV2.QueryResultValue, 0, len(columns))}
+		for _, v := range values {
+			row.Values = append(row.Values, &driverV2.QueryResultValue{Value: v.String})
+		}
+		result.Rows = append(result.Rows, row)
+	}
+	return result, rows.Err()
+}
+
+func (d *DriverImpl) GetDatabases(ctx context.Context) ([]string, error) {
+	conn, err := d.getConn()
+	if err != nil {
+		return nil, err
+	}
+	rows, err := conn.QueryContext(ctx, "SELECT datname FROM pg_database WHERE NOT datistemplate ORDER BY datname")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	databases := []string{}
+	for rows.Next() {
+		var database string
+		if err := rows.Scan(&database); err != nil {
+			return nil, err
+		}
+		databases = append(databases, database)
+	}
+	return databases, rows.Err()
+}
+
+func (d *DriverImpl) KillProcess(ctx context.Context) (*driverV2.KillProcessInfo, error) {
+	return driverV2.NewKillProcessInfo("PostgreSQL driver does not support kill process"), nil
+}
+
+func (d *DriverImpl) Parse(ctx context.Context, sqlText string) ([]driverV2.Node, error) {
+	stmts, err := parseSQL(sqlText)
+	if err != nil {
+		return nil, err
+	}
+	nodes := make([]driverV2.Node, 0, len(stmts))
+	for _, s := range stmts {
+		nodes = append(nodes, driverV2.Node{
+			Text:        s.text,
+			Type:        s.sqlType(),
+			Fingerprint: s.fingerprint(),
+		})
+	}
+	return nodes, nil
+}
+
+func (d *DriverImpl) Audit(ctx context.Context, sqls []string) ([]*driverV2.AuditResults, error) {
+	results := make([]*driverV2.AuditResults, 0, len(sqls))
+	for _, sqlText := range sqls {
+		res := driverV2.NewAuditResults()
+		stmts, err := parseSQL(sqlText)
+		if err != nil {
+			res.Add(driverV2.RuleLevelError, "", "语法错误: %v", err)
+			results = append(results, res)
+			continue
+		}
+		for _, s := range stmts {
+			for _, rule := range d.cfg.Rules {
+				handler, ok := RuleHandlerMap[rule.Name]
+				if !ok || handler.Func == nil {
+					continue
+				}
+				if handler.OnlineOnly && d.isOfflineAudit() {
+					continue
+				}
+				input := &RuleHandlerInput{
+					Ctx:  ctx,
+					Rule: *rule,
+					Res:  res,
+					Stmt: s,
+					Conn: d.conn,
+				}
+				if err := handler.Func(input); err != nil {
+					return nil, fmt.Errorf("audit SQL %s with rule %s failed: %v", s.text, rule.Name, err)
+				}
+			}
+		}
+		results = append(results, res)
+	}
+	return results, nil
+}
+
+func (d *DriverImpl) Explain(ctx context.Context, conf *driverV2.ExplainConf) (*driverV2.ExplainResult, error) {
+	conn, err := d.getConn()
+	if err != nil {
+		return nil, err
+	}
+	s, err := parseOneSQL(conf.Sql)
+	if err != nil {
+		return nil, err
+	}
+	if s.sqlType() != driverV2.SQLTypeDML {
+		return nil, fmt.Errorf("only DML is supported by explain, but got: %s", s.text)
+	}
+	// EXPLAIN without ANALYZE does not execute the statement.
+	rows, err := conn.QueryContext(ctx, "EXPLAIN "+s.text)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := &driverV2.ExplainResult{}
+	result.ClassicResult.Columns = []driverV2.TabularDataHead{{Name: "QUERY PLAN", Desc: "执行计划"}}
+	for rows.Next() {
+		var line string
+		if err := rows.Scan(&line); err != nil {
+			return nil, err
+		}
+		result.ClassicResult.Rows = append(result.ClassicResult.Rows, []string{line})
+	}
+	return result, rows.Err()
+}
+
+func (d *DriverImpl) GetTableMeta(ctx context.Context, table *driverV2.Table) (*driverV2.TableMeta, error) {
+	conn, err := d.getConn()
+	if err != nil {
+		return nil, err
+	}
+	name := tableName{schema: table.Schema, name: table.Name}
+	columns, err := queryColumns(ctx, conn, name)
+	if err != nil {
+		return nil, err
+	}
+	if len(columns) == 0 {
+		return &driverV2.TableMeta{Message: fmt.Sprintf("table %s is not found", name)}, nil
+	}
+
+	meta := &driverV2.TableMeta{}
+	meta.ColumnsInfo.Columns = []driverV2.TabularDataHead{
+		{Name: "COLUMN_NAME", Desc: "列名"},
+		{Name: "COLUMN_TYPE", Desc: "列类型"},
+		{Name: "IS_NULLABLE", Desc: "是否可以为空"},
+		{Name: "COLUMN_DEFAULT", Desc: "默认值"},
+		{Name: "COMMENT", Desc: "备注"},
+	}
+	for _, c := range columns {
+		nullable := "YES"
+		if c.notNull {
+			nullable = "NO"
+		}
+		meta.ColumnsInfo.Rows = append(meta.ColumnsInfo.Rows, []string{c.name, c.typ, nullable, c.defaultValue, c.comment})
+	}
+
+	indexes, err := queryIndexes(ctx, conn, name)
+	if err != nil {
+		return nil, err
+	}
+	meta.IndexesInfo.Columns = []driverV2.TabularDataHead{
+		{Name: "INDEX_NAME", Desc: "索引名"},
+		{Name: "INDEX_DEF", Desc: "索引定义"},
+	}
+	for _, index := range indexes {
+		meta.IndexesInfo.Rows = append(meta.IndexesInfo.Rows, []string{index.name, index.def})
+	}
+
+	meta.CreateTableSQL, err = showCreateTable(ctx, conn, name)
+	if err != nil {
+		return nil, err
+	}
+	return meta, nil
+}
+
+func (d *DriverImpl) ListTables(ctx context.Context, conf *driverV2.ListTablesConf) (*driverV2.ListTablesResult, error) {
+	conn, err := d.getConn()
+	if err != nil {
+		return nil, err
+	}
+	schema := conf.Schema
+	if schema == "" {
+		schema = "public"
+	}
+	const where = `WHERE table_schema = $1 AND table_type = 'BASE TABLE' AND ($2 = '' OR strpos(table_name, $2) > 0)`
+
+	result := &driverV2.ListTablesResult{Tables: []*driverV2.Table{}}
+	if err := conn.QueryRowContext(ctx, "SELECT count(*) FROM information_schema.tables "+where,
+		schema, conf.FuzzySearchTableName).Scan(&result.Total); err != nil {
+		return nil, err
+	}
+
+	// LIMIT NULL means no limit
+	limit := sql.NullInt64{Int64: int64(conf.Limit), Valid: conf.Limit > 0}
+	rows, err := conn.QueryContext(ctx, "SELECT table_name FROM information_schema.tables "+where+" ORDER BY table_name LIMIT $3 OFFSET $4",
+		schema, conf.FuzzySearchTableName, limit, conf.Offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		t := &driverV2.Table{Schema: conf.Schema}
+		if err := rows.Scan(&t.Name); err != nil {
+			return nil, err
+		}
+		result.Tables = append(result.Tables, t)
+	}
+	return result, rows.Err()
+}
+
+func (d *DriverImpl) ExtractTableFromSQL(ctx context.Context, sqlText string) ([]*driverV2.Table, error) {
+	stmts, err := parseSQL(sqlText)
+	if err != nil {
+		return nil, err
+	}
+	tables := []*driverV2.Table{}
+	for _, s := range stmts {
+		for _, t := range s.tables() {
+			tables = append(tables, &driverV2.Table{Name: t.name, Schema: t.schema})
+		}
+	}
+	return tables, nil
+}
+
+func (d *DriverImpl) EstimateSQLAffectRows(ctx context.Context, sqlText string) (*driverV2.EstimatedAffectRows, error) {
+	s, err := parseOneSQL(sqlText)
+	if err != nil {
+		return nil, err
+	}
+	if info, ok := s.insert(); ok && info.rows != nil {
+		return &driverV2.EstimatedAffectRows{Count: int64(len(info.rows))}, nil
+	}
+	switch s.kind {
+	case stmtSelect, stmtInsert, stmtUpdate, stmtDelete:
+	default:
+		return &driverV2.EstimatedAffectRows{ErrMessage: "暂不支持估算该类型语句的影响行数"}, nil
+	}
+
+	conn, err := d.getConn()
+	if err != nil {
+		return nil, err
+	}
+	var plan string
+	if err := conn.QueryRowContext(ctx, "EXPLAIN (FORMAT JSON) "+s.text).Scan(&plan); err != nil {
+		return &driverV2.EstimatedAffectRows{ErrMessage: err.Error()}, nil
+	}
+	count, err := estimateRowsFromPlan(plan)
+	if err != nil {
+		return &driverV2.EstimatedAffectRows{ErrMessage: err.Error()}, nil
+	}
+	return &driverV2.EstimatedAffectRows{Count: count}, nil
+}
+
+type explainPlan struct {
+	NodeType string         `json:"Node Type"`
+	PlanRows float64        `json:"Plan Rows"`
+	Plans    []*explainPlan `json:"Plans"`
+}
+
+// estimateRowsFromPlan returns the estimated rows of the JSON format plan, the rows of
+// "ModifyTable" node is always 0, the rows of its child node is the affected rows.
+func estimateRowsFromPlan(plan string) (int64, error) {
+	result := []struct {
+		Plan *explainPlan `json:"Plan"`
+	}{}
+	if err := json.Unmarshal([]byte(plan), &result); err != nil {
+		return 0, fmt.Errorf("unmarshal explain result failed: %v", err)
+	}
+	if len(result) == 0 || result[0].Plan == nil {
+		return 0, fmt.Errorf("explain result is empty")
+	}
+	p := result[0].Plan
+	if p.NodeType == "ModifyTable" && len(p.Plans) > 0 {
+		p = p.Plans[0]
+	}
+	return int64(p.PlanRows), nil
+}
+
+type columnInfo struct {
+	name         string
+	typ          string
+	notNull      bool
+	defaultValue string
+	comment      string
+	// identity is "a" (ALWAYS) or "d" (BY DEFAULT) for identity column, generated is "s" for
+	// generated column, they are empty for other columns.
+	identity  string
+	generated string
+}
+
+func queryColumns(ctx context.Context, conn *sql.Conn, table tableName) ([]*columnInfo, error) {
+	// attidentity and attgenerated are read by to_jsonb since they are not in the PostgreSQL
+	// before 10 and 12.
+	rows, err := conn.QueryContext(ctx, `SELECT a.attname, format_type(a.atttypid, a.atttypmod), a.attnotnull,
+COALESCE(pg_get_expr(d.adbin, d.adrelid), ''), COALESCE(col_description(a.attrelid, a.attnum), ''),
+COALESCE(to_jsonb(a)->>'attidentity', ''), COALESCE(to_jsonb(a)->>'attgenerated', '')
+FROM pg_attribute a
+LEFT JOIN pg_attrdef d ON d.adrelid = a.attrelid AND d.adnum = a.attnum
+WHERE a.attrelid = to_regclass($1) AND a.attnum > 0 AND NOT a.attisdropped
+ORDER BY a.attnum`, table.String())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	columns := []*columnInfo{}
+	for rows.Next() {
+		c := &columnInfo{}
+		if err := rows.Scan(&c.name, &c.typ, &c.notNull, &c.defaultValue, &c.comment, &c.identity, &c.generated); err != nil {
+			return nil, err
+		}
+		columns = append(columns, c)
+	}
+	return columns, rows.Err()
+}
+
+type indexInfo struct {
+	name string
+	def  string
+	// constraint is true if the index is created by constraint, e.g. primary key and unique constraint.
+	constraint bool
+}
+
+func queryIndexes(ctx context.Context, conn *sql.Conn, table tableName) ([]*indexInfo, error) {
+	rows, err := conn.QueryContext(ctx, `SELECT c.relname, pg_get_indexdef(i.indexrelid),
+EXISTS (SELECT 1 FROM pg_constraint con WHERE con.conindid = i.indexrelid)
+FROM pg_index i
+JOIN pg_class c ON c.oid = i.indexrelid
+WHERE i.indrelid = to_regclass($1)
+ORDER BY c.relname`, table.String())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	indexes := []*indexInfo{}
+	for rows.Next() {
+		index := &indexInfo{}
+		if err := rows.Scan(&index.name, &index.def, &index.constraint); err != nil {
+			return nil, err
+		}
+		indexes = append(indexes, index)
+	}
+	return indexes, rows.Err()
+}
+
+// showCreateTable generates the CREATE TABLE statement of table, PostgreSQL has no
+// "SHOW CREATE TABLE", the statement is assembled from the system catalogs.
+func showCreateTable(ctx context.Context, conn *sql.Conn, table tableName) (string, error) {
+	columns, err := queryColumns(ctx, conn, table)
+	if err != nil {
+		return "", err
+	}
+	if len(columns) == 0 {
+		return "", fmt.Errorf("table %s is not found", table)
+	}
+	defs := []string{}
+	for _, c := range columns {
+		def := fmt.Sprintf("%s %s", quoteIdent(c.name), c.typ)
+		if c.notNull {
+			def += " NOT NULL"
+		}
+		switch {
+		case c.generated != "":
+			def += fmt.Sprintf(" GENERATED ALWAYS AS (%s) STORED", c.defaultValue)
+		case c.identity == "a":
+			def += " GENERATED ALWAYS AS IDENTITY"
+		case c.identity == "d":
+			def += " GENERATED BY DEFAULT AS IDENTITY"
+		case c.defaultValue != "":
+			def += " DEFAULT " + c.defaultValue
+		}
+		defs = append(defs, def)
+	}
+
+	rows, err := conn.QueryContext(ctx, `SELECT conname, pg_get_constraintdef(oid)
+FROM pg_constraint WHERE conrelid = to_regclass($1) ORDER BY contype DESC, conname`, table.String())
+	if err != nil {
+		return "", err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var name, def string
+		if err := rows.Scan(&name, &def); err != nil {
+			return "", err
+		}
+		defs = append(defs, fmt.Sprintf("CONSTRAINT %s %s", quoteIdent(name), def))
+	}
+	if err := rows.Err(); err != nil {
+		return "", err
+	}
+
+	stmts := []string{fmt.Sprintf("CREATE TABLE %s (\n  %s\n);", table, strings.Join(defs, ",\n  "))}
+	indexes, err := queryIndexes(ctx, conn, table)
+	if err != nil {
+		return "", err
+	}
+	for _, index := range indexes {
+		if !index.constraint {
+			stmts = append(stmts, index.def+";")
+		}
+	}
+	return strings.Join(stmts, "\n"), nil
+}
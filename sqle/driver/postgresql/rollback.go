@@ -0,0 +1,407 @@
+package postgresql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+)
+
+const (
+	NotSupportStatementRollback               = "暂不支持回滚该类型的语句"
+	NotSupportAlterTableActionRollback        = "暂不支持回滚该 ALTER TABLE 操作"
+	NotSupportUnnamedIndexRollback            = "不支持回滚未指定名称的索引"
+	NotSupportMultiTableStatementRollback     = "暂不支持回滚多表的 DML 语句"
+	NotSupportOnConflictStatementRollback     = "暂不支持回滚 ON CONFLICT 语句"
+	NotSupportNoPrimaryKeyTableRollback       = "不支持回滚没有主键的表的DML语句"
+	NotSupportInsertWithoutPrimaryKeyRollback = "不支持回滚 INSERT 没有指定主键的语句"
+	NotSupportInsertNonConstantValueRollback  = "不支持回滚主键值不是常量的 INSERT 语句"
+	NotSupportUpdatePrimaryKeyRollback        = "不支持回滚修改主键的 UPDATE 语句"
+	NotSupportParamMarkerStatementRollback    = "不支持回滚包含指纹的语句"
+	NotSupportExceedMaxRowsRollback           = "预计影响行数超过配置的最大值，不生成回滚语句"
+)
+
+// DMLRollbackMaxRows is the max rows of DML which the rollback SQL is generated for.
+var DMLRollbackMaxRows = 1000
+
+func (d *DriverImpl) GenRollbackSQL(ctx context.Context, sqlText string) (string, string, error) {
+	if d.isOfflineAudit() {
+		return "", "", nil
+	}
+	s, err := parseOneSQL(sqlText)
+	if err != nil {
+		return "", "", err
+	}
+	return d.generateRollbackSQL(ctx, s)
+}
+
+func (d *DriverImpl) generateRollbackSQL(ctx context.Context, s *stmt) (string, string, error) {
+	if s.modifyingCTE {
+		return "", NotSupportStatementRollback, nil
+	}
+	switch s.kind {
+	case stmtCreateTable:
+		return generateCreateTableRollbackSQL(s)
+	case stmtCreateIndex:
+		return generateCreateIndexRollbackSQL(s)
+	case stmtAlterTable:
+		return generateAlterTableRollbackSQL(s)
+	case stmtDropTable:
+		return d.generateDropTableRollbackSQL(ctx, s)
+	case stmtDropIndex:
+		return d.generateDropIndexRollbackSQL(ctx, s)
+	case stmtInsert, stmtUpdate, stmtDelete:
+		for _, t := range s.tokens {
+			if t.kind == tokenParam {
+				return "", NotSupportParamMarkerStatementRollback, nil
+			}
+		}
+		if s.body != 0 {
+			return "", NotSupportStatementRollback, nil
+		}
+		switch s.kind {
+		case stmtInsert:
+			return d.generateInsertRollbackSQL(ctx, s)
+		case stmtDelete:
+			return d.generateDeleteRollbackSQL(ctx, s)
+		default:
+			return d.generateUpdateRollbackSQL(ctx, s)
+		}
+	}
+	return "", "", nil
+}
+
+func generateCreateTableRollbackSQL(s *stmt) (string, string, error) {
+	info, ok := s.createTable()
+	if !ok {
+		return "", NotSupportStatementRollback, nil
+	}
+	return fmt.Sprintf("DROP TABLE IF EXISTS %s;", info.table), "", nil
+}
+
+func generateCreateIndexRollbackSQL(s *stmt) (string, string, error) {
+	info, ok := s.createIndex()
+	if !ok {
+		return "", NotSupportStatementRollback, nil
+	}
+	if info.name == "" {
+		return "", NotSupportUnnamedIndexRollback, nil
+	}
+	// the index is created in the schema of table.
+	index := tableName{schema: info.table.schema, name: info.name}
+	return fmt.Sprintf("DROP INDEX IF EXISTS %s;", index), "", nil
+}
+
+func generateAlterTableRollbackSQL(s *stmt) (string, string, error) {
+	info, ok := s.alterTable()
+	if !ok {
+		return "", NotSupportStatementRollback, nil
+	}
+	if len(info.actions) == 1 {
+		action := info.actions[0]
+		switch action.kind {
+		case alterRenameTable:
+			renamed := tableName{schema: info.table.schema, name: action.name}
+			return fmt.Sprintf("ALTER TABLE %s RENAME TO %s;", renamed, quoteIdent(info.table.name)), "", nil
+		case alterRenameColumn:
+			return fmt.Sprintf("ALTER TABLE %s RENAME COLUMN %s TO %s;", info.table,
+				quoteIdent(action.newName), quoteIdent(action.name)), "", nil
+		}
+	}
+
+	// the actions are reverted in reverse order.
+	reverts := []string{}
+	for i := len(info.actions) - 1; i >= 0; i-- {
+		action := info.actions[i]
+		switch {
+		case action.kind == alterAddColumn && action.name != "":
+			reverts = append(reverts, "DROP COLUMN "+quoteIdent(action.name))
+		case action.kind == alterAddConstraint && action.name != "":
+			reverts = append(reverts, "DROP CONSTRAINT "+quoteIdent(action.name))
+		default:
+			return "", NotSupportAlterTableActionRollback, nil
+		}
+	}
+	return fmt.Sprintf("ALTER TABLE %s %s;", info.table, strings.Join(reverts, ", ")), "", nil
+}
+
+func (d *DriverImpl) generateDropTableRollbackSQL(ctx context.Context, s *stmt) (string, string, error) {
+	info, ok := s.drop()
+	if !ok {
+		return "", NotSupportStatementRollback, nil
+	}
+	stmts := []string{}
+	for _, table := range info.names {
+		columns, err := queryColumns(ctx, d.conn, table)
+		if err != nil {
+			return "", "", err
+		}
+		if len(columns) == 0 {
+			// "DROP TABLE IF EXISTS" on the table which is not exist.
+			continue
+		}
+		createTable, err := showCreateTable(ctx, d.conn, table)
+		if err != nil {
+			return "", "", err
+		}
+		stmts = append(stmts, createTable)
+	}
+	return strings.Join(stmts, "\n"), "", nil
+}
+
+func (d *DriverImpl) generateDropIndexRollbackSQL(ctx context.Context, s *stmt) (string, string, error) {
+	info, ok := s.drop()
+	if !ok {
+		return "", NotSupportStatementRollback, nil
+	}
+	stmts := []string{}
+	for _, index := range info.names {
+		var def string
+		err := d.conn.QueryRowContext(ctx, "SELECT pg_get_indexdef(to_regclass($1))", index.String()).Scan(&def)
+		if err == sql.ErrNoRows {
+			continue
+		}
+		if err != nil {
+			return "", "", err
+		}
+		if def != "" {
+			stmts = append(stmts, def+";")
+		}
+	}
+	return strings.Join(stmts, "\n"), "", nil
+}
+
+// queryPrimaryKey returns the primary key columns of table in order.
+func queryPrimaryKey(ctx context.Context, conn *sql.Conn, table tableName) ([]string, error) {
+	rows, err := conn.QueryContext(ctx, `SELECT a.attname
+FROM pg_index i
+JOIN pg_attribute a ON a.attrelid = i.indrelid AND a.attnum = ANY(i.indkey)
+WHERE i.indrelid = to_regclass($1) AND i.indisprimary
+ORDER BY array_position(i.indkey::int2[], a.attnum)`, table.String())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	columns := []string{}
+	for rows.Next() {
+		var column string
+		if err := rows.Scan(&column); err != nil {
+			return nil, err
+		}
+		columns = append(columns, column)
+	}
+	return columns, rows.Err()
+}
+
+func (d *DriverImpl) generateInsertRollbackSQL(ctx context.Context, s *stmt) (string, string, error) {
+	info, ok := s.insert()
+	if !ok || info.rows == nil {
+		return "", NotSupportStatementRollback, nil
+	}
+	if info.onConflict {
+		return "", NotSupportOnConflictStatementRollback, nil
+	}
+	if len(info.rows) > DMLRollbackMaxRows {
+		return "", NotSupportExceedMaxRowsRollback, nil
+	}
+	pk, err := queryPrimaryKey(ctx, d.conn, info.table)
+	if err != nil {
+		return "", "", err
+	}
+	if len(pk) == 0 {
+		return "", NotSupportNoPrimaryKeyTableRollback, nil
+	}
+	pkIndex := make([]int, len(pk))
+	for i, column := range pk {
+		pkIndex[i] = -1
+		for j, c := range info.columns {
+			if c == column {
+				pkIndex[i] = j
+			}
+		}
+		if pkIndex[i] < 0 {
+			return "", NotSupportInsertWithoutPrimaryKeyRollback, nil
+		}
+	}
+
+	stmts := []string{}
+	for _, row := range info.rows {
+		if len(row) != len(info.columns) {
+			return "", NotSupportStatementRollback, nil
+		}
+		conditions := []string{}
+		for i, column := range pk {
+			value, ok := s.constant(row[pkIndex[i]][0], row[pkIndex[i]][1])
+			if !ok {
+				return "", NotSupportInsertNonConstantValueRollback, nil
+			}
+			conditions = append(conditions, fmt.Sprintf("%s = %s", quoteIdent(column), value))
+		}
+		stmts = append(stmts, fmt.Sprintf("DELETE FROM %s WHERE %s;", info.table, strings.Join(conditions, " AND ")))
+	}
+	return strings.Join(stmts, "\n"), "", nil
+}
+
+// constant returns the text of constant between index from and to, e.g. "'abc'", "1" and "-1".
+func (s *stmt) constant(from, to int) (string, bool) {
+	switch {
+	case to-from == 1 && (s.tokens[from].kind == tokenString || s.tokens[from].kind == tokenNumber):
+		return s.tokens[from].val, true
+	case to-from == 2 && s.atPunct(from, "-") && s.tokens[from+1].kind == tokenNumber:
+		return "-" + s.tokens[from+1].val, true
+	}
+	return "", false
+}
+
+func (d *DriverImpl) generateDeleteRollbackSQL(ctx context.Context, s *stmt) (string, string, error) {
+	info, ok := s.dml()
+	if !ok {
+		return "", NotSupportStatementRollback, nil
+	}
+	if info.hasFrom {
+		return "", NotSupportMultiTableStatementRollback, nil
+	}
+	columns, records, exceeded, err := d.queryAffectedRecords(ctx, s, info)
+	if err != nil || exceeded {
+		return "", reasonOfExceeded(exceeded), err
+	}
+
+	// the generated columns can not be inserted, the identity columns generated ALWAYS can
+	// only be inserted with OVERRIDING SYSTEM VALUE.
+	names := []string{}
+	overriding := ""
+	for _, c := range columns {
+		if c.generated != "" {
+			continue
+		}
+		if c.identity != "" {
+			overriding = " OVERRIDING SYSTEM VALUE"
+		}
+		names = append(names, quoteIdent(c.name))
+	}
+	stmts := []string{}
+	for _, record := range records {
+		values := []string{}
+		for i, c := range columns {
+			if c.generated == "" {
+				values = append(values, record[i])
+			}
+		}
+		stmts = append(stmts, fmt.Sprintf("INSERT INTO %s (%s)%s VALUES (%s);", info.table,
+			strings.Join(names, ", "), overriding, strings.Join(values, ", ")))
+	}
+	return strings.Join(stmts, "\n"), "", nil
+}
+
+func (d *DriverImpl) generateUpdateRollbackSQL(ctx context.Context, s *stmt) (string, string, error) {
+	info, ok := s.dml()
+	if !ok {
+		return "", NotSupportStatementRollback, nil
+	}
+	if info.hasFrom {
+		return "", NotSupportMultiTableStatementRollback, nil
+	}
+	pk, err := queryPrimaryKey(ctx, d.conn, info.table)
+	if err != nil {
+		return "", "", err
+	}
+	if len(pk) == 0 {
+		return "", NotSupportNoPrimaryKeyTableRollback, nil
+	}
+	for _, column := range info.setColumns {
+		for _, pkColumn := range pk {
+			if column == pkColumn {
+				return "", NotSupportUpdatePrimaryKeyRollback, nil
+			}
+		}
+	}
+
+	columns, records, exceeded, err := d.queryAffectedRecords(ctx, s, info)
+	if err != nil || exceeded {
+		return "", reasonOfExceeded(exceeded), err
+	}
+	columnIndex := map[string]int{}
+	for i, c := range columns {
+		columnIndex[c.name] = i
+	}
+	stmts := []string{}
+	for _, record := range records {
+		values := []string{}
+		for _, column := range info.setColumns {
+			values = append(values, fmt.Sprintf("%s = %s", quoteIdent(column), record[columnIndex[column]]))
+		}
+		conditions := []string{}
+		for _, column := range pk {
+			conditions = append(conditions, fmt.Sprintf("%s = %s", quoteIdent(column), record[columnIndex[column]]))
+		}
+		stmts = append(stmts, fmt.Sprintf("UPDATE %s SET %s WHERE %s;", info.table,
+			strings.Join(values, ", "), strings.Join(conditions, " AND ")))
+	}
+	return strings.Join(stmts, "\n"), "", nil
+}
+
+func reasonOfExceeded(exceeded bool) string {
+	if exceeded {
+		return NotSupportExceedMaxRowsRollback
+	}
+	return ""
+}
+
+// queryAffectedRecords queries the records which will be affected by UPDATE or DELETE, the values are
+// formatted as SQL constants. exceeded is true if the records are more than DMLRollbackMaxRows.
+func (d *DriverImpl) queryAffectedRecords(ctx context.Context, s *stmt, info *dmlInfo) (columns []*columnInfo, records [][]string, exceeded bool, err error) {
+	columns, err = queryColumns(ctx, d.conn, info.table)
+	if err != nil {
+		return nil, nil, false, err
+	}
+	if len(columns) == 0 {
+		return nil, nil, false, fmt.Errorf("table %s is not found", info.table)
+	}
+
+	from := info.table.String()
+	if info.alias != "" {
+		from += " AS " + quoteIdent(info.alias)
+	}
+	selects := make([]string, len(columns))
+	for i, c := range columns {
+		// the value is queried as text, it can be converted back to the column type by PostgreSQL.
+		selects[i] = quoteIdent(c.name) + "::text"
+	}
+	query := fmt.Sprintf("SELECT %s FROM %s", strings.Join(selects, ", "), from)
+	if info.whereFrom >= 0 {
+		query += " WHERE " + s.textOf(info.whereFrom, info.whereTo)
+	}
+	query += fmt.Sprintf(" LIMIT %d", DMLRollbackMaxRows+1)
+
+	rows, err := d.conn.QueryContext(ctx, query)
+	if err != nil {
+		return nil, nil, false, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		values := make([]sql.NullString, len(columns))
+		dest := make([]interface{}, len(columns))
+		for i := range values {
+			dest[i] = &values[i]
+		}
+		if err := rows.Scan(dest...); err != nil {
+			return nil, nil, false, err
+		}
+		record := make([]string, len(columns))
+		for i, v := range values {
+			if v.Valid {
+				record[i] = quoteLiteral(v.String)
+			} else {
+				record[i] = "NULL"
+			}
+		}
+		records = append(records, record)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, nil, false, err
+	}
+	if len(records) > DMLRollbackMaxRows {
+		return nil, nil, true, nil
+	}
+	return columns, records, false, nil
+}
@@ -0,0 +1,131 @@
+package postgresql
+
+import (
+	"context"
+	"testing"
+
+	driverV2 "github.com/actiontech/sqle/sqle/driver/v2"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func newMockDriver(t *testing.T) (*DriverImpl, sqlmock.Sqlmock, func()) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	conn, err := db.Conn(context.TODO())
+	assert.NoError(t, err)
+	return &DriverImpl{cfg: &driverV2.Config{}, db: db, conn: conn}, mock, func() { db.Close() }
+}
+
+func TestGenRollbackSQL_DDL(t *testing.T) {
+	d, _, closeFn := newMockDriver(t)
+	defer closeFn()
+
+	for sql, expect := range map[string]string{
+		"create table s1.t1 (id int primary key)":                       "DROP TABLE IF EXISTS s1.t1;",
+		`create unique index concurrently "Idx" on s1.t1 (a)`:           `DROP INDEX IF EXISTS s1."Idx";`,
+		"alter table t1 add column a int, add constraint uk unique (a)": "ALTER TABLE t1 DROP CONSTRAINT uk, DROP COLUMN a;",
+		"alter table t1 rename column a to b":                           "ALTER TABLE t1 RENAME COLUMN b TO a;",
+		"alter table s1.t1 rename to t2":                                "ALTER TABLE s1.t2 RENAME TO t1;",
+	} {
+		rollback, reason, err := d.GenRollbackSQL(context.TODO(), sql)
+		assert.NoError(t, err)
+		assert.Equal(t, "", reason, sql)
+		assert.Equal(t, expect, rollback, sql)
+	}
+
+	for sql, expect := range map[string]string{
+		"create index on t1 (a)":                 NotSupportUnnamedIndexRollback,
+		"alter table t1 alter column a type int": NotSupportAlterTableActionRollback,
+	} {
+		rollback, reason, err := d.GenRollbackSQL(context.TODO(), sql)
+		assert.NoError(t, err)
+		assert.Equal(t, expect, reason, sql)
+		assert.Equal(t, "", rollback, sql)
+	}
+}
+
+func TestGenRollbackSQL_DML(t *testing.T) {
+	d, mock, closeFn := newMockDriver(t)
+	defer closeFn()
+
+	// INSERT
+	mock.ExpectQuery("SELECT a.attname").WithArgs("t1").
+		WillReturnRows(sqlmock.NewRows([]string{"attname"}).AddRow("id"))
+	rollback, reason, err := d.GenRollbackSQL(context.TODO(), "insert into t1 (id, name) values (1, 'a'), (-2, 'b')")
+	assert.NoError(t, err)
+	assert.Equal(t, "", reason)
+	assert.Equal(t, "DELETE FROM t1 WHERE id = 1;\nDELETE FROM t1 WHERE id = -2;", rollback)
+
+	mock.ExpectQuery("SELECT a.attname").WithArgs("t1").
+		WillReturnRows(sqlmock.NewRows([]string{"attname"}).AddRow("id"))
+	_, reason, err = d.GenRollbackSQL(context.TODO(), "insert into t1 (name) values ('a')")
+	assert.NoError(t, err)
+	assert.Equal(t, NotSupportInsertWithoutPrimaryKeyRollback, reason)
+
+	// DELETE
+	mock.ExpectQuery("SELECT a.attname, format_type").WithArgs("t1").
+		WillReturnRows(sqlmock.NewRows([]string{"attname", "format_type", "attnotnull", "default", "comment", "identity", "generated"}).
+			AddRow("id", "integer", true, "", "", "", "").
+			AddRow("name", "text", false, "", "", "", ""))
+	mock.ExpectQuery(`SELECT id::text, name::text FROM t1 AS a WHERE a.id > 1 LIMIT 1001`).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow("2", "it's").AddRow("3", nil))
+	rollback, reason, err = d.GenRollbackSQL(context.TODO(), "delete from t1 as a where a.id > 1")
+	assert.NoError(t, err)
+	assert.Equal(t, "", reason)
+	assert.Equal(t, "INSERT INTO t1 (id, name) VALUES ('2', 'it''s');\nINSERT INTO t1 (id, name) VALUES ('3', NULL);", rollback)
+
+	// the generated column is skipped and the identity column is inserted by OVERRIDING SYSTEM VALUE
+	mock.ExpectQuery("SELECT a.attname, format_type").WithArgs("t2").
+		WillReturnRows(sqlmock.NewRows([]string{"attname", "format_type", "attnotnull", "default", "comment", "identity", "generated"}).
+			AddRow("id", "integer", true, "", "", "a", "").
+			AddRow("name", "text", false, "", "", "", "").
+			AddRow("upper_name", "text", false, "upper(name)", "", "", "s"))
+	mock.ExpectQuery(`SELECT id::text, name::text, upper_name::text FROM t2 WHERE id = 1 LIMIT 1001`).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "upper_name"}).AddRow("1", "a", "A"))
+	rollback, reason, err = d.GenRollbackSQL(context.TODO(), "delete from t2 where id = 1")
+	assert.NoError(t, err)
+	assert.Equal(t, "", reason)
+	assert.Equal(t, "INSERT INTO t2 (id, name) OVERRIDING SYSTEM VALUE VALUES ('1', 'a');", rollback)
+
+	// UPDATE
+	mock.ExpectQuery("SELECT a.attname").WithArgs("t1").
+		WillReturnRows(sqlmock.NewRows([]string{"attname"}).AddRow("id"))
+	mock.ExpectQuery("SELECT a.attname, format_type").WithArgs("t1").
+		WillReturnRows(sqlmock.NewRows([]string{"attname", "format_type", "attnotnull", "default", "comment", "identity", "generated"}).
+			AddRow("id", "integer", true, "", "", "", "").
+			AddRow("name", "text", false, "", "", "", ""))
+	mock.ExpectQuery(`SELECT id::text, name::text FROM t1 WHERE id = 2 LIMIT 1001`).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow("2", "a"))
+	rollback, reason, err = d.GenRollbackSQL(context.TODO(), "update t1 set name = 'b' where id = 2")
+	assert.NoError(t, err)
+	assert.Equal(t, "", reason)
+	assert.Equal(t, "UPDATE t1 SET name = 'a' WHERE id = '2';", rollback)
+
+	mock.ExpectQuery("SELECT a.attname").WithArgs("t1").
+		WillReturnRows(sqlmock.NewRows([]string{"attname"}).AddRow("id"))
+	_, reason, err = d.GenRollbackSQL(context.TODO(), "update t1 set id = 3 where id = 2")
+	assert.NoError(t, err)
+	assert.Equal(t, NotSupportUpdatePrimaryKeyRollback, reason)
+
+	_, reason, err = d.GenRollbackSQL(context.TODO(), "update t1 set name = 'a' from t2 where t1.id = t2.id")
+	assert.NoError(t, err)
+	assert.Equal(t, NotSupportMultiTableStatementRollback, reason)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestEstimateRowsFromPlan(t *testing.T) {
+	count, err := estimateRowsFromPlan(`[{"Plan": {"Node Type": "ModifyTable", "Plan Rows": 0,
+		"Plans": [{"Node Type": "Seq Scan", "Plan Rows": 42}]}}]`)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(42), count)
+
+	count, err = estimateRowsFromPlan(`[{"Plan": {"Node Type": "Seq Scan", "Plan Rows": 7}}]`)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(7), count)
+
+	_, err = estimateRowsFromPlan(`[]`)
+	assert.Error(t, err)
+}
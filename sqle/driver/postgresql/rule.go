@@ -0,0 +1,390 @@
+package postgresql
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+
+	driverV2 "github.com/actiontech/sqle/sqle/driver/v2"
+	"github.com/actiontech/sqle/sqle/pkg/params"
+)
+
+// rule type
+const (
+	RuleTypeNamingConvention   = "命名规范"
+	RuleTypeIndexingConvention = "索引规范"
+	RuleTypeDDLConvention      = "DDL规范"
+	RuleTypeDMLConvention      = "DML规范"
+	RuleTypeUsageSuggestion    = "使用建议"
+	RuleTypeIndexOptimization  = "索引优化"
+)
+
+// rule name
+const (
+	DDLCheckObjectNameLength            = "ddl_check_object_name_length"
+	DDLCheckObjectNameIsLowerCase       = "ddl_check_object_name_is_lower_case"
+	DDLCheckIndexPrefix                 = "ddl_check_index_prefix"
+	DDLCheckUniqueIndexPrefix           = "ddl_check_unique_index_prefix"
+	DDLCheckPKNotExist                  = "ddl_check_pk_not_exist"
+	DDLCheckIndexOnLowCardinalityColumn = "ddl_check_index_on_low_cardinality_column"
+	DDLCheckCreateIndexConcurrently     = "ddl_check_create_index_concurrently"
+	DDLDisableDropStatement             = "ddl_disable_drop_statement"
+	DMLDisableSelectAllColumn           = "dml_disable_select_all_column"
+	DMLCheckWhereIsInvalid              = "dml_check_where_is_invalid"
+	DMLCheckInsertColumnsExist          = "dml_check_insert_columns_exist"
+)
+
+const (
+	paramKeyMaxLength         = "max_length"
+	paramKeyPrefix            = "prefix"
+	paramKeyMinDistinctValues = "min_distinct_values"
+)
+
+type RuleHandlerInput struct {
+	Ctx  context.Context
+	Rule driverV2.Rule
+	Res  *driverV2.AuditResults
+	Stmt *stmt
+	// Conn is nil when the SQL is audited offline.
+	Conn *sql.Conn
+}
+
+type RuleHandlerFunc func(input *RuleHandlerInput) error
+
+type RuleHandler struct {
+	Rule    driverV2.Rule
+	Message string
+	// OnlineOnly is true for the rule which needs to query the database.
+	OnlineOnly bool
+	Func       RuleHandlerFunc
+}
+
+var RuleHandlerMap = map[string]RuleHandler{}
+
+func init() {
+	for _, rh := range RuleHandlers {
+		RuleHandlerMap[rh.Rule.Name] = rh
+	}
+}
+
+var RuleHandlers = []RuleHandler{
+	{
+		Rule: driverV2.Rule{
+			Name:       DDLCheckObjectNameLength,
+			Desc:       "表名、列名、索引名的长度不建议超过阈值",
+			Annotation: "PostgreSQL 标识符的最大长度为63字节，超出部分会被截断，截断后的名称可能与预期不符甚至发生冲突",
+			Level:      driverV2.RuleLevelError,
+			Category:   RuleTypeNamingConvention,
+			Params: params.Params{
+				&params.Param{
+					Key:   paramKeyMaxLength,
+					Value: "63",
+					Desc:  "最大长度（字节）",
+					Type:  params.ParamTypeInt,
+				},
+			},
+		},
+		Message: "表名、列名、索引名的长度不建议大于%v字节",
+		Func:    checkObjectNameLength,
+	},
+	{
+		Rule: driverV2.Rule{
+			Name:       DDLCheckObjectNameIsLowerCase,
+			Desc:       "对象名称建议只使用小写字母、数字和下划线",
+			Annotation: "PostgreSQL 会将未加双引号的标识符转换为小写，使用大写字母或特殊字符的名称必须在每次引用时加双引号，容易出错",
+			Level:      driverV2.RuleLevelNotice,
+			Category:   RuleTypeNamingConvention,
+		},
+		Message: "对象名称建议只使用小写字母、数字和下划线，以下名称不符合规范: %v",
+		Func:    checkObjectNameIsLowerCase,
+	},
+	{
+		Rule: driverV2.Rule{
+			Name:       DDLCheckIndexPrefix,
+			Desc:       "普通索引必须使用固定前缀",
+			Annotation: "通过配置该规则可以规范指定业务的索引命名规则，具体命名规范可以自定义设置，默认提示值：idx_",
+			Level:      driverV2.RuleLevelError,
+			Category:   RuleTypeNamingConvention,
+			Params: params.Params{
+				&params.Param{
+					Key:   paramKeyPrefix,
+					Value: "idx_",
+					Desc:  "索引前缀",
+					Type:  params.ParamTypeString,
+				},
+			},
+		},
+		Message: "普通索引必须要以\"%v\"为前缀",
+		Func:    checkIndexPrefix,
+	},
+	{
+		Rule: driverV2.Rule{
+			Name:       DDLCheckUniqueIndexPrefix,
+			Desc:       "唯一索引必须使用固定前缀",
+			Annotation: "通过配置该规则可以规范指定业务的唯一索引命名规则，具体命名规范可以自定义设置，默认提示值：uniq_",
+			Level:      driverV2.RuleLevelError,
+			Category:   RuleTypeNamingConvention,
+			Params: params.Params{
+				&params.Param{
+					Key:   paramKeyPrefix,
+					Value: "uniq_",
+					Desc:  "索引前缀",
+					Type:  params.ParamTypeString,
+				},
+			},
+		},
+		Message: "唯一索引必须要以\"%v\"为前缀",
+		Func:    checkIndexPrefix,
+	},
+	{
+		Rule: driverV2.Rule{
+			Name:       DDLCheckPKNotExist,
+			Desc:       "表必须有主键",
+			Annotation: "主键使数据达到全局唯一，逻辑复制等功能也依赖主键定位数据行",
+			Level:      driverV2.RuleLevelError,
+			Category:   RuleTypeIndexingConvention,
+		},
+		Message: "表必须有主键",
+		Func:    checkPKNotExist,
+	},
+	{
+		Rule: driverV2.Rule{
+			Name:       DDLCheckIndexOnLowCardinalityColumn,
+			Desc:       "不建议在区分度低的列上建索引",
+			Annotation: "区分度低的列（如布尔类型、状态值）上的索引过滤效果差，优化器通常不会使用，还会增加写入开销；该规则依据列类型和 pg_stats 中的统计信息判断区分度，需要连接数据库",
+			Level:      driverV2.RuleLevelWarn,
+			Category:   RuleTypeIndexOptimization,
+			Params: params.Params{
+				&params.Param{
+					Key:   paramKeyMinDistinctValues,
+					Value: "10",
+					Desc:  "索引首列的最小不同值个数",
+					Type:  params.ParamTypeInt,
+				},
+			},
+		},
+		Message:    "不建议在区分度低的列上建索引，以下列区分度低: %v",
+		OnlineOnly: true,
+		Func:       checkIndexOnLowCardinalityColumn,
+	},
+	{
+		Rule: driverV2.Rule{
+			Name:       DDLCheckCreateIndexConcurrently,
+			Desc:       "建议使用 CREATE INDEX CONCURRENTLY 创建索引",
+			Annotation: "普通的 CREATE INDEX 会在建索引期间阻塞表的写入，CONCURRENTLY 方式不会阻塞写入，适合在线上表执行",
+			Level:      driverV2.RuleLevelNotice,
+			Category:   RuleTypeUsageSuggestion,
+		},
+		Message: "建议使用 CREATE INDEX CONCURRENTLY 创建索引",
+		Func:    checkCreateIndexConcurrently,
+	},
+	{
+		Rule: driverV2.Rule{
+			Name:       DDLDisableDropStatement,
+			Desc:       "禁止除索引外的DROP操作",
+			Annotation: "DROP 是 DDL，数据变更不会写入日志，无法进行回滚；建议开启此规则，避免误删除操作",
+			Level:      driverV2.RuleLevelError,
+			Category:   RuleTypeUsageSuggestion,
+		},
+		Message: "禁止除索引外的DROP操作",
+		Func:    checkDropStatement,
+	},
+	{
+		Rule: driverV2.Rule{
+			Name:       DMLDisableSelectAllColumn,
+			Desc:       "不建议使用SELECT *",
+			Annotation: "当表结构变更时，使用*通配符选择所有列将导致查询行为会发生更改，与业务期望不符；同时SELECT * 中的无用字段会带来不必要的磁盘I/O，以及网络开销",
+			Level:      driverV2.RuleLevelNotice,
+			Category:   RuleTypeDMLConvention,
+		},
+		Message: "不建议使用SELECT *",
+		Func:    checkSelectAll,
+	},
+	{
+		Rule: driverV2.Rule{
+			Name:       DMLCheckWhereIsInvalid,
+			Desc:       "禁止使用没有WHERE条件或者WHERE条件恒为TRUE的UPDATE/DELETE语句",
+			Annotation: "没有有效WHERE条件的UPDATE/DELETE会修改全表数据，误操作的影响面大且难以恢复",
+			Level:      driverV2.RuleLevelError,
+			Category:   RuleTypeDMLConvention,
+		},
+		Message: "禁止使用没有WHERE条件或者WHERE条件恒为TRUE的UPDATE/DELETE语句",
+		Func:    checkWhereIsInvalid,
+	},
+	{
+		Rule: driverV2.Rule{
+			Name:       DMLCheckInsertColumnsExist,
+			Desc:       "INSERT 语句必须指定COLUMN",
+			Annotation: "当表结构发生变更，INSERT请求不明确指定列名，会发生插入数据不匹配的情况；建议开启此规则，避免插入结果与业务预期不符",
+			Level:      driverV2.RuleLevelNotice,
+			Category:   RuleTypeDMLConvention,
+		},
+		Message: "INSERT 语句必须指定COLUMN",
+		Func:    checkInsertColumnsExist,
+	},
+}
+
+// objectNames returns the names of objects created or renamed by the DDL statement.
+func objectNames(s *stmt) []string {
+	names := []string{}
+	switch s.kind {
+	case stmtCreateTable:
+		if info, ok := s.createTable(); ok {
+			names = append(names, info.table.name)
+			for _, col := range info.columns {
+				names = append(names, col.name)
+			}
+		}
+	case stmtCreateIndex:
+		if info, ok := s.createIndex(); ok && info.name != "" {
+			names = append(names, info.name)
+		}
+	case stmtAlterTable:
+		if info, ok := s.alterTable(); ok {
+			for _, action := range info.actions {
+				switch action.kind {
+				case alterAddColumn, alterAddConstraint, alterRenameTable:
+					names = append(names, action.name)
+				case alterRenameColumn:
+					names = append(names, action.newName)
+				}
+			}
+		}
+	}
+	result := []string{}
+	for _, name := range names {
+		if name != "" {
+			result = append(result, name)
+		}
+	}
+	return result
+}
+
+func checkObjectNameLength(input *RuleHandlerInput) error {
+	max := input.Rule.Params.GetParam(paramKeyMaxLength).Int()
+	if max <= 0 {
+		return nil
+	}
+	for _, name := range objectNames(input.Stmt) {
+		if len(name) > max {
+			addResult(input, max)
+			return nil
+		}
+	}
+	return nil
+}
+
+func checkObjectNameIsLowerCase(input *RuleHandlerInput) error {
+	invalid := []string{}
+	for _, name := range objectNames(input.Stmt) {
+		if quoteIdent(name) != name && !isReservedKeyword(name) {
+			invalid = append(invalid, name)
+		}
+	}
+	if len(invalid) > 0 {
+		addResult(input, strings.Join(invalid, ","))
+	}
+	return nil
+}
+
+func checkIndexPrefix(input *RuleHandlerInput) error {
+	info, ok := input.Stmt.createIndex()
+	if !ok || info.name == "" {
+		return nil
+	}
+	if info.unique != (input.Rule.Name == DDLCheckUniqueIndexPrefix) {
+		return nil
+	}
+	prefix := input.Rule.Params.GetParam(paramKeyPrefix).String()
+	if prefix != "" && !strings.HasPrefix(info.name, prefix) {
+		addResult(input, prefix)
+	}
+	return nil
+}
+
+func checkPKNotExist(input *RuleHandlerInput) error {
+	info, ok := input.Stmt.createTable()
+	if ok && !info.derived && !info.hasPK {
+		addResult(input)
+	}
+	return nil
+}
+
+func checkIndexOnLowCardinalityColumn(input *RuleHandlerInput) error {
+	info, ok := input.Stmt.createIndex()
+	if !ok || len(info.columns) == 0 || info.columns[0] == "" {
+		return nil
+	}
+	column := info.columns[0]
+	var typ string
+	var nDistinct float64
+	err := input.Conn.QueryRowContext(input.Ctx, `SELECT format_type(a.atttypid, a.atttypmod), COALESCE(s.n_distinct, 0)
+FROM pg_attribute a
+JOIN pg_class c ON c.oid = a.attrelid
+JOIN pg_namespace n ON n.oid = c.relnamespace
+LEFT JOIN pg_stats s ON s.schemaname = n.nspname AND s.tablename = c.relname AND s.attname = a.attname
+WHERE c.oid = to_regclass($1) AND a.attname = $2 AND NOT a.attisdropped`, info.table.String(), column).Scan(&typ, &nDistinct)
+	if err == sql.ErrNoRows {
+		// the table or column is created in the same batch.
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	min := input.Rule.Params.GetParam(paramKeyMinDistinctValues).Int()
+	// n_distinct is negative when the distinct values grow with the rows, it is high cardinality.
+	if typ == "boolean" || nDistinct > 0 && nDistinct < float64(min) {
+		addResult(input, column)
+	}
+	return nil
+}
+
+func checkCreateIndexConcurrently(input *RuleHandlerInput) error {
+	info, ok := input.Stmt.createIndex()
+	if ok && !info.concurrently {
+		addResult(input)
+	}
+	return nil
+}
+
+func checkDropStatement(input *RuleHandlerInput) error {
+	if input.Stmt.kind == stmtDropTable || input.Stmt.at(input.Stmt.body, "drop") &&
+		(input.Stmt.at(input.Stmt.body+1, "schema") || input.Stmt.at(input.Stmt.body+1, "database")) {
+		addResult(input)
+	}
+	return nil
+}
+
+func checkSelectAll(input *RuleHandlerInput) error {
+	if input.Stmt.sqlType() == driverV2.SQLTypeDML && input.Stmt.hasSelectAll() {
+		addResult(input)
+	}
+	return nil
+}
+
+func checkWhereIsInvalid(input *RuleHandlerInput) error {
+	info, ok := input.Stmt.dml()
+	if !ok {
+		return nil
+	}
+	if info.whereFrom < 0 && info.whereTo < 0 {
+		// WHERE CURRENT OF
+		return nil
+	}
+	if info.whereFrom < 0 || input.Stmt.isAlwaysTrue(info.whereFrom, info.whereTo) {
+		addResult(input)
+	}
+	return nil
+}
+
+func checkInsertColumnsExist(input *RuleHandlerInput) error {
+	info, ok := input.Stmt.insert()
+	if ok && len(info.columns) == 0 && !info.defaultRows {
+		addResult(input)
+	}
+	return nil
+}
+
+func addResult(input *RuleHandlerInput, args ...interface{}) {
+	input.Res.Add(input.Rule.Level, input.Rule.Name, RuleHandlerMap[input.Rule.Name].Message, args...)
+}
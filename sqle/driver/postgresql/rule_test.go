@@ -0,0 +1,121 @@
+package postgresql
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	driverV2 "github.com/actiontech/sqle/sqle/driver/v2"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func newOfflineDriver(ruleNames ...string) *DriverImpl {
+	rules := []*driverV2.Rule{}
+	for _, name := range ruleNames {
+		rule := RuleHandlerMap[name].Rule
+		rules = append(rules, &rule)
+	}
+	return &DriverImpl{cfg: &driverV2.Config{Rules: rules}}
+}
+
+func runSingleRuleCase(t *testing.T, ruleName string, sql string, expectTriggered bool) {
+	d := newOfflineDriver(ruleName)
+	results, err := d.Audit(context.TODO(), []string{sql})
+	assert.NoError(t, err)
+	if assert.Len(t, results, 1) {
+		if expectTriggered {
+			assert.Len(t, results[0].Results, 1, sql)
+			if len(results[0].Results) == 1 {
+				assert.Equal(t, ruleName, results[0].Results[0].RuleName)
+			}
+		} else {
+			assert.Empty(t, results[0].Results, sql)
+		}
+	}
+}
+
+func TestRules(t *testing.T) {
+	cases := []struct {
+		rule      string
+		sql       string
+		triggered bool
+	}{
+		{DDLCheckObjectNameLength, "create table t1 (id int primary key)", false},
+		{DDLCheckObjectNameLength, "alter table t1 add column " + strings.Repeat("a", 63) + " int", false},
+		{DDLCheckObjectNameLength, "alter table t1 add column " + strings.Repeat("a", 64) + " int", true},
+		{DDLCheckObjectNameLength, "create index idx_" + strings.Repeat("a", 60) + " on t1 (a)", true},
+		{DDLCheckObjectNameIsLowerCase, "create table t1 (id int primary key, name text)", false},
+		{DDLCheckObjectNameIsLowerCase, `create table t1 (id int primary key, "Name" text)`, true},
+		{DDLCheckIndexPrefix, "create index idx_a on t1 (a)", false},
+		{DDLCheckIndexPrefix, "create index a on t1 (a)", true},
+		{DDLCheckIndexPrefix, "create unique index a on t1 (a)", false},
+		{DDLCheckUniqueIndexPrefix, "create unique index a on t1 (a)", true},
+		{DDLCheckUniqueIndexPrefix, "create unique index uniq_a on t1 (a)", false},
+		{DDLCheckPKNotExist, "create table t1 (id int primary key)", false},
+		{DDLCheckPKNotExist, "create table t1 (id int, constraint pk primary key (id))", false},
+		{DDLCheckPKNotExist, "create table t1 (id int)", true},
+		{DDLCheckPKNotExist, "create table t1 as select * from t2", false},
+		{DDLCheckCreateIndexConcurrently, "create index concurrently idx_a on t1 (a)", false},
+		{DDLCheckCreateIndexConcurrently, "create index idx_a on t1 (a)", true},
+		{DDLDisableDropStatement, "drop index idx_a", false},
+		{DDLDisableDropStatement, "drop table t1", true},
+		{DMLDisableSelectAllColumn, "select count(*) from t1", false},
+		{DMLDisableSelectAllColumn, "select a * 2 from t1", false},
+		{DMLDisableSelectAllColumn, "select * from t1", true},
+		{DMLDisableSelectAllColumn, "select t1.* from t1", true},
+		{DMLDisableSelectAllColumn, "insert into t2 (a) select * from t1", true},
+		{DMLCheckWhereIsInvalid, "update t1 set a = 1 where id = 1", false},
+		{DMLCheckWhereIsInvalid, "update t1 set a = 1", true},
+		{DMLCheckWhereIsInvalid, "delete from t1 where (1 = 1)", true},
+		{DMLCheckWhereIsInvalid, "delete from t1 where true returning id", true},
+		{DMLCheckInsertColumnsExist, "insert into t1 (a) values (1)", false},
+		{DMLCheckInsertColumnsExist, "insert into t1 default values", false},
+		{DMLCheckInsertColumnsExist, "insert into t1 values (1)", true},
+	}
+	for _, c := range cases {
+		runSingleRuleCase(t, c.rule, c.sql, c.triggered)
+	}
+}
+
+func TestCheckIndexOnLowCardinalityColumn(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+	conn, err := db.Conn(context.TODO())
+	assert.NoError(t, err)
+
+	rule := RuleHandlerMap[DDLCheckIndexOnLowCardinalityColumn].Rule
+	d := &DriverImpl{cfg: &driverV2.Config{Rules: []*driverV2.Rule{&rule}}, conn: conn}
+
+	mock.ExpectQuery("SELECT format_type").WithArgs("t1", "is_deleted").
+		WillReturnRows(sqlmock.NewRows([]string{"format_type", "n_distinct"}).AddRow("boolean", 0))
+	mock.ExpectQuery("SELECT format_type").WithArgs("t1", "status").
+		WillReturnRows(sqlmock.NewRows([]string{"format_type", "n_distinct"}).AddRow("integer", 3))
+	mock.ExpectQuery("SELECT format_type").WithArgs("t1", "id").
+		WillReturnRows(sqlmock.NewRows([]string{"format_type", "n_distinct"}).AddRow("integer", -1))
+	mock.ExpectQuery("SELECT format_type").WithArgs("t1", "c").
+		WillReturnRows(sqlmock.NewRows([]string{"format_type", "n_distinct"}))
+
+	results, err := d.Audit(context.TODO(), []string{
+		"create index idx_1 on t1 (is_deleted)",
+		"create index idx_2 on t1 (status, id)",
+		"create index idx_3 on t1 (id)",
+		"create index idx_4 on t1 (c)",
+		"create index idx_5 on t1 (lower(name))",
+	})
+	assert.NoError(t, err)
+	assert.Len(t, results, 5)
+	assert.Len(t, results[0].Results, 1)
+	assert.Len(t, results[1].Results, 1)
+	assert.Empty(t, results[2].Results)
+	assert.Empty(t, results[3].Results)
+	assert.Empty(t, results[4].Results)
+	assert.NoError(t, mock.ExpectationsWereMet())
+
+	// the rule is skipped on offline audit.
+	results, err = newOfflineDriver(DDLCheckIndexOnLowCardinalityColumn).Audit(context.TODO(), []string{"create index idx_1 on t1 (is_deleted)"})
+	assert.NoError(t, err)
+	assert.Empty(t, results[0].Results)
+}
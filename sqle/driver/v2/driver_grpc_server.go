@@ -195,9 +195,10 @@ func (d *DriverGrpcServer) Audit(ctx context.Context, req *protoV2.AuditRequest)
 		}
 		for _, result := range results.Results {
 			rets.Results = append(rets.Results, &protoV2.AuditResult{
-				Level:    string(result.Level),
-				Message:  result.Message,
-				RuleName: result.RuleName,
+				Level:       string(result.Level),
+				Message:     result.Message,
+				RuleName:    result.RuleName,
+				IndexAdvice: ConvertIndexAdviceToProto(result.IndexAdvice),
 			})
 		}
 		resp.AuditResults = append(resp.AuditResults, rets)
@@ -449,3 +450,30 @@ func (d *DriverGrpcServer) KillProcess(ctx context.Context, req *protoV2.KillPro
 		ErrMessage: info.ErrMessage,
 	}, nil
 }
+
+func (d *DriverGrpcServer) ListTables(ctx context.Context, req *protoV2.ListTablesRequest) (*protoV2.ListTablesResponse, error) {
+	driver, err := d.getDriverBySession(req.Session)
+	if err != nil {
+		return &protoV2.ListTablesResponse{}, err
+	}
+	result, err := driver.ListTables(ctx, &ListTablesConf{
+		Schema:               req.Schema,
+		FuzzySearchTableName: req.FuzzySearchTableName,
+		Limit:                req.Limit,
+		Offset:               req.Offset,
+	})
+	if err != nil {
+		return &protoV2.ListTablesResponse{}, err
+	}
+	protoTables := make([]*protoV2.Table, 0, len(result.Tables))
+	for _, t := range result.Tables {
+		protoTables = append(protoTables, &protoV2.Table{
+			Name:   t.Name,
+			Schema: t.Schema,
+		})
+	}
+	return &protoV2.ListTablesResponse{
+		Tables: protoTables,
+		Total:  result.Total,
+	}, nil
+}
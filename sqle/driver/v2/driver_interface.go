@@ -88,6 +88,7 @@ type Driver interface {
 	ExtractTableFromSQL(ctx context.Context, sql string) ([]*Table, error)
 	EstimateSQLAffectRows(ctx context.Context, sql string) (*EstimatedAffectRows, error)
 	KillProcess(ctx context.Context) (*KillProcessInfo, error)
+	ListTables(ctx context.Context, conf *ListTablesConf) (*ListTablesResult, error)
 }
 
 type Node struct {
@@ -144,6 +145,25 @@ type AuditResult struct {
 	Level    RuleLevel
 	Message  string
 	RuleName string
+	// IndexAdvice is the advice of index optimization rule, it is nil for other rules.
+	IndexAdvice *IndexAdvice
+}
+
+// IndexAdvice is the index advice of a SELECT statement, it is carried by the audit
+// result so that the advices of the whole task or audit plan report can be collected.
+type IndexAdvice struct {
+	TableName string
+	Columns   []string
+
+	// CreateIndexSQL is the ready-to-apply DDL of the advice, it is empty if the
+	// index can not be created directly, e.g. the function index before MySQL 8.0.13.
+	CreateIndexSQL string
+	// CoveringIndexSQL is the DDL of the covering index which makes the query
+	// index only, it is empty if the query can not be covered.
+	CoveringIndexSQL string
+	// EstimatedRows is the rows examined by the table in execution plan, the
+	// index is expected to avoid most of them.
+	EstimatedRows int64
 }
 
 func NewAuditResults() *AuditResults {
@@ -203,6 +223,20 @@ func (rs *AuditResults) Add(level RuleLevel, ruleName string, messagePattern str
 	rs.SortByLevel()
 }
 
+// AddIndexAdvice adds the audit result of index optimization rule with the advice.
+func (rs *AuditResults) AddIndexAdvice(level RuleLevel, ruleName string, message string, advice *IndexAdvice) {
+	if level == "" || message == "" {
+		return
+	}
+	rs.Results = append(rs.Results, &AuditResult{
+		Level:       level,
+		Message:     message,
+		RuleName:    ruleName,
+		IndexAdvice: advice,
+	})
+	rs.SortByLevel()
+}
+
 func (rs *AuditResults) SortByLevel() {
 	sort.Slice(rs.Results, func(i, j int) bool {
 		return rs.Results[i].Level.More(rs.Results[j].Level)
@@ -283,6 +317,21 @@ type Table struct {
 	Schema string
 }
 
+type ListTablesConf struct {
+	Schema string
+	// FuzzySearchTableName filters tables whose name contains it, empty means no filter.
+	FuzzySearchTableName string
+	// Limit is the max number of tables returned, 0 means no limit.
+	Limit  uint32
+	Offset uint32
+}
+
+type ListTablesResult struct {
+	Tables []*Table
+	// Total is the number of tables matching the filter, regardless of Limit and Offset.
+	Total uint64
+}
+
 type EstimatedAffectRows struct {
 	Count      int64
 	ErrMessage string
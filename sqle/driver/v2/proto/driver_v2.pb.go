@@ -68,6 +68,9 @@ It has these top-level messages:
 	EstimateSQLAffectRowsRequest
 	EstimateSQLAffectRowsResponse
 	KillProcessResponse
+	ListTablesRequest
+	ListTablesResponse
+	IndexAdvice
 */
 package protoV2
 
@@ -101,6 +104,7 @@ const (
 	OptionalModule_ExtractTableFromSQL   OptionalModule = 4
 	OptionalModule_EstimateSQLAffectRows OptionalModule = 5
 	OptionalModule_KillProcess           OptionalModule = 6
+	OptionalModule_ListTables            OptionalModule = 7
 )
 
 var OptionalModule_name = map[int32]string{
@@ -111,6 +115,7 @@ var OptionalModule_name = map[int32]string{
 	4: "ExtractTableFromSQL",
 	5: "EstimateSQLAffectRows",
 	6: "KillProcess",
+	7: "ListTables",
 }
 var OptionalModule_value = map[string]int32{
 	"GenRollbackSQL":        0,
@@ -120,6 +125,7 @@ var OptionalModule_value = map[string]int32{
 	"ExtractTableFromSQL":   4,
 	"EstimateSQLAffectRows": 5,
 	"KillProcess":           6,
+	"ListTables":            7,
 }
 
 func (x OptionalModule) String() string {
@@ -590,9 +596,10 @@ func (m *AuditRequest) GetSqls() []*AuditSQL {
 }
 
 type AuditResult struct {
-	Message  string `protobuf:"bytes,1,opt,name=message" json:"message,omitempty"`
-	Level    string `protobuf:"bytes,2,opt,name=level" json:"level,omitempty"`
-	RuleName string `protobuf:"bytes,3,opt,name=rule_name,json=ruleName" json:"rule_name,omitempty"`
+	Message     string       `protobuf:"bytes,1,opt,name=message" json:"message,omitempty"`
+	Level       string       `protobuf:"bytes,2,opt,name=level" json:"level,omitempty"`
+	RuleName    string       `protobuf:"bytes,3,opt,name=rule_name,json=ruleName" json:"rule_name,omitempty"`
+	IndexAdvice *IndexAdvice `protobuf:"bytes,4,opt,name=index_advice,json=indexAdvice" json:"index_advice,omitempty"`
 }
 
 func (m *AuditResult) Reset()                    { *m = AuditResult{} }
@@ -621,6 +628,13 @@ func (m *AuditResult) GetRuleName() string {
 	return ""
 }
 
+func (m *AuditResult) GetIndexAdvice() *IndexAdvice {
+	if m != nil {
+		return m.IndexAdvice
+	}
+	return nil
+}
+
 type AuditResults struct {
 	Results []*AuditResult `protobuf:"bytes,1,rep,name=results" json:"results,omitempty"`
 }
@@ -1471,6 +1485,127 @@ func (m *KillProcessResponse) GetErrMessage() string {
 	return ""
 }
 
+type ListTablesRequest struct {
+	Session              *Session `protobuf:"bytes,1,opt,name=session" json:"session,omitempty"`
+	Schema               string   `protobuf:"bytes,2,opt,name=schema" json:"schema,omitempty"`
+	FuzzySearchTableName string   `protobuf:"bytes,3,opt,name=fuzzySearchTableName" json:"fuzzySearchTableName,omitempty"`
+	Limit                uint32   `protobuf:"varint,4,opt,name=limit" json:"limit,omitempty"`
+	Offset               uint32   `protobuf:"varint,5,opt,name=offset" json:"offset,omitempty"`
+}
+
+func (m *ListTablesRequest) Reset()                    { *m = ListTablesRequest{} }
+func (m *ListTablesRequest) String() string            { return proto.CompactTextString(m) }
+func (*ListTablesRequest) ProtoMessage()               {}
+func (*ListTablesRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{60} }
+
+func (m *ListTablesRequest) GetSession() *Session {
+	if m != nil {
+		return m.Session
+	}
+	return nil
+}
+
+func (m *ListTablesRequest) GetSchema() string {
+	if m != nil {
+		return m.Schema
+	}
+	return ""
+}
+
+func (m *ListTablesRequest) GetFuzzySearchTableName() string {
+	if m != nil {
+		return m.FuzzySearchTableName
+	}
+	return ""
+}
+
+func (m *ListTablesRequest) GetLimit() uint32 {
+	if m != nil {
+		return m.Limit
+	}
+	return 0
+}
+
+func (m *ListTablesRequest) GetOffset() uint32 {
+	if m != nil {
+		return m.Offset
+	}
+	return 0
+}
+
+type ListTablesResponse struct {
+	Tables []*Table `protobuf:"bytes,1,rep,name=tables" json:"tables,omitempty"`
+	Total  uint64   `protobuf:"varint,2,opt,name=total" json:"total,omitempty"`
+}
+
+func (m *ListTablesResponse) Reset()                    { *m = ListTablesResponse{} }
+func (m *ListTablesResponse) String() string            { return proto.CompactTextString(m) }
+func (*ListTablesResponse) ProtoMessage()               {}
+func (*ListTablesResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{61} }
+
+func (m *ListTablesResponse) GetTables() []*Table {
+	if m != nil {
+		return m.Tables
+	}
+	return nil
+}
+
+func (m *ListTablesResponse) GetTotal() uint64 {
+	if m != nil {
+		return m.Total
+	}
+	return 0
+}
+
+// IndexAdvice
+type IndexAdvice struct {
+	TableName        string   `protobuf:"bytes,1,opt,name=table_name,json=tableName" json:"table_name,omitempty"`
+	Columns          []string `protobuf:"bytes,2,rep,name=columns" json:"columns,omitempty"`
+	CreateIndexSql   string   `protobuf:"bytes,3,opt,name=create_index_sql,json=createIndexSql" json:"create_index_sql,omitempty"`
+	CoveringIndexSql string   `protobuf:"bytes,4,opt,name=covering_index_sql,json=coveringIndexSql" json:"covering_index_sql,omitempty"`
+	EstimatedRows    int64    `protobuf:"varint,5,opt,name=estimated_rows,json=estimatedRows" json:"estimated_rows,omitempty"`
+}
+
+func (m *IndexAdvice) Reset()                    { *m = IndexAdvice{} }
+func (m *IndexAdvice) String() string            { return proto.CompactTextString(m) }
+func (*IndexAdvice) ProtoMessage()               {}
+func (*IndexAdvice) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{62} }
+
+func (m *IndexAdvice) GetTableName() string {
+	if m != nil {
+		return m.TableName
+	}
+	return ""
+}
+
+func (m *IndexAdvice) GetColumns() []string {
+	if m != nil {
+		return m.Columns
+	}
+	return nil
+}
+
+func (m *IndexAdvice) GetCreateIndexSql() string {
+	if m != nil {
+		return m.CreateIndexSql
+	}
+	return ""
+}
+
+func (m *IndexAdvice) GetCoveringIndexSql() string {
+	if m != nil {
+		return m.CoveringIndexSql
+	}
+	return ""
+}
+
+func (m *IndexAdvice) GetEstimatedRows() int64 {
+	if m != nil {
+		return m.EstimatedRows
+	}
+	return 0
+}
+
 func init() {
 	proto.RegisterType((*Empty)(nil), "protoV2.Empty")
 	proto.RegisterType((*Session)(nil), "protoV2.Session")
@@ -1532,6 +1667,9 @@ func init() {
 	proto.RegisterType((*EstimateSQLAffectRowsRequest)(nil), "protoV2.EstimateSQLAffectRowsRequest")
 	proto.RegisterType((*EstimateSQLAffectRowsResponse)(nil), "protoV2.EstimateSQLAffectRowsResponse")
 	proto.RegisterType((*KillProcessResponse)(nil), "protoV2.KillProcessResponse")
+	proto.RegisterType((*ListTablesRequest)(nil), "protoV2.ListTablesRequest")
+	proto.RegisterType((*ListTablesResponse)(nil), "protoV2.ListTablesResponse")
+	proto.RegisterType((*IndexAdvice)(nil), "protoV2.IndexAdvice")
 	proto.RegisterEnum("protoV2.OptionalModule", OptionalModule_name, OptionalModule_value)
 }
 
@@ -1570,6 +1708,7 @@ type DriverClient interface {
 	GetTableMeta(ctx context.Context, in *GetTableMetaRequest, opts ...grpc.CallOption) (*GetTableMetaResponse, error)
 	ExtractTableFromSQL(ctx context.Context, in *ExtractTableFromSQLRequest, opts ...grpc.CallOption) (*ExtractTableFromSQLResponse, error)
 	EstimateSQLAffectRows(ctx context.Context, in *EstimateSQLAffectRowsRequest, opts ...grpc.CallOption) (*EstimateSQLAffectRowsResponse, error)
+	ListTables(ctx context.Context, in *ListTablesRequest, opts ...grpc.CallOption) (*ListTablesResponse, error)
 }
 
 type driverClient struct {
@@ -1724,6 +1863,15 @@ func (c *driverClient) EstimateSQLAffectRows(ctx context.Context, in *EstimateSQ
 	return out, nil
 }
 
+func (c *driverClient) ListTables(ctx context.Context, in *ListTablesRequest, opts ...grpc.CallOption) (*ListTablesResponse, error) {
+	out := new(ListTablesResponse)
+	err := grpc.Invoke(ctx, "/protoV2.Driver/ListTables", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // Server API for Driver service
 
 type DriverServer interface {
@@ -1751,6 +1899,7 @@ type DriverServer interface {
 	GetTableMeta(context.Context, *GetTableMetaRequest) (*GetTableMetaResponse, error)
 	ExtractTableFromSQL(context.Context, *ExtractTableFromSQLRequest) (*ExtractTableFromSQLResponse, error)
 	EstimateSQLAffectRows(context.Context, *EstimateSQLAffectRowsRequest) (*EstimateSQLAffectRowsResponse, error)
+	ListTables(context.Context, *ListTablesRequest) (*ListTablesResponse, error)
 }
 
 func RegisterDriverServer(s *grpc.Server, srv DriverServer) {
@@ -2045,6 +2194,24 @@ func _Driver_EstimateSQLAffectRows_Handler(srv interface{}, ctx context.Context,
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Driver_ListTables_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListTablesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DriverServer).ListTables(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/protoV2.Driver/ListTables",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DriverServer).ListTables(ctx, req.(*ListTablesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 var _Driver_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "protoV2.Driver",
 	HandlerType: (*DriverServer)(nil),
@@ -2113,6 +2280,10 @@ var _Driver_serviceDesc = grpc.ServiceDesc{
 			MethodName: "EstimateSQLAffectRows",
 			Handler:    _Driver_EstimateSQLAffectRows_Handler,
 		},
+		{
+			MethodName: "ListTables",
+			Handler:    _Driver_ListTables_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "driver_v2.proto",
@@ -2121,123 +2292,135 @@ var _Driver_serviceDesc = grpc.ServiceDesc{
 func init() { proto.RegisterFile("driver_v2.proto", fileDescriptor0) }
 
 var fileDescriptor0 = []byte{
-	// 1875 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x9c, 0x18, 0xdb, 0x72, 0x14, 0xc7,
-	0x35, 0x7b, 0x95, 0xf6, 0xec, 0x45, 0xeb, 0x96, 0x04, 0xcb, 0x02, 0xb2, 0xd2, 0x80, 0xac, 0xc2,
-	0x44, 0x60, 0x91, 0xe0, 0xc2, 0x24, 0x29, 0x88, 0xa4, 0x80, 0x8c, 0x51, 0x44, 0x8b, 0x90, 0x2a,
-	0x57, 0xb9, 0xec, 0xd6, 0x4e, 0x6b, 0x3d, 0xc5, 0xec, 0xf4, 0x6a, 0xba, 0x17, 0x89, 0x2f, 0xc8,
-	0x07, 0xe4, 0x21, 0xbf, 0x91, 0x17, 0x3f, 0xe6, 0x47, 0xf2, 0x35, 0xa9, 0xbe, 0xcc, 0x4c, 0xcf,
-	0x65, 0x6d, 0xb3, 0x4f, 0xd3, 0x7d, 0xee, 0x7d, 0xce, 0xe9, 0xd3, 0xe7, 0x0c, 0xac, 0x78, 0x91,
-	0xff, 0x9e, 0x45, 0xdf, 0xbf, 0xdf, 0xdd, 0x99, 0x46, 0x5c, 0x72, 0xb4, 0xa4, 0x3f, 0x6f, 0x77,
-	0xf1, 0x12, 0x34, 0x0e, 0x26, 0x53, 0xf9, 0x01, 0x5f, 0x83, 0xa5, 0x13, 0x26, 0x84, 0xcf, 0x43,
-	0xd4, 0x83, 0xaa, 0xef, 0x0d, 0x2a, 0x9b, 0x95, 0xed, 0x16, 0xa9, 0xfa, 0x1e, 0xfe, 0x07, 0x34,
-	0x8e, 0x69, 0x44, 0x27, 0xa8, 0x0f, 0xb5, 0x77, 0xec, 0x83, 0xc5, 0xa8, 0x25, 0x5a, 0x83, 0xc6,
-	0x7b, 0x1a, 0xcc, 0xd8, 0xa0, 0xaa, 0x61, 0x66, 0x83, 0x10, 0xd4, 0x3d, 0x26, 0x46, 0x83, 0x9a,
-	0x06, 0xea, 0xb5, 0x82, 0xc9, 0x0f, 0x53, 0x36, 0xa8, 0x1b, 0x98, 0x5a, 0xe3, 0x9f, 0x2a, 0x50,
-	0xdb, 0x3f, 0x39, 0x52, 0xb8, 0x1f, 0xb9, 0x90, 0x56, 0xb0, 0x5e, 0x2b, 0xd8, 0x94, 0x47, 0xd2,
-	0x0a, 0xd6, 0x6b, 0x05, 0x9b, 0x09, 0x16, 0xc5, 0x72, 0xd5, 0x1a, 0x0d, 0x61, 0x79, 0x4a, 0x85,
-	0xb8, 0xe0, 0x91, 0x67, 0x65, 0x27, 0x7b, 0x85, 0xf3, 0xa8, 0xa4, 0xa7, 0x54, 0xb0, 0x41, 0xc3,
-	0xe0, 0xe2, 0x3d, 0xfa, 0x0a, 0xfa, 0xd4, 0xf3, 0x7c, 0xe9, 0xf3, 0x90, 0x06, 0xfa, 0x78, 0x62,
-	0xd0, 0xdc, 0xac, 0x6d, 0xb7, 0x77, 0x7b, 0x3b, 0xd6, 0x39, 0x3b, 0x1a, 0x4c, 0x0a, 0x74, 0xf8,
-	0x7f, 0x15, 0xa8, 0x93, 0x59, 0xa0, 0x0f, 0x1a, 0xd2, 0x09, 0x8b, 0x0d, 0x57, 0xeb, 0xe4, 0xf0,
-	0x55, 0xe7, 0xf0, 0x6b, 0xd0, 0x08, 0xd8, 0x7b, 0x16, 0x58, 0xcb, 0xcd, 0x46, 0x99, 0x37, 0xa2,
-	0x92, 0x8d, 0x79, 0xf4, 0x21, 0x36, 0x3d, 0xde, 0xa3, 0x2d, 0x68, 0x4e, 0x8d, 0x51, 0x8d, 0x52,
-	0xa3, 0x2c, 0x16, 0x6d, 0x00, 0xd0, 0x30, 0xe4, 0x92, 0x2a, 0x03, 0x07, 0x4d, 0x2d, 0xc5, 0x81,
-	0xa0, 0x07, 0xd0, 0x7a, 0x17, 0xf2, 0x8b, 0x80, 0x79, 0x63, 0x36, 0x58, 0xda, 0xac, 0x6c, 0xb7,
-	0x77, 0x51, 0x22, 0xea, 0x65, 0x8c, 0x21, 0x29, 0x11, 0xbe, 0x03, 0xad, 0x04, 0x8e, 0x06, 0xb0,
-	0x34, 0xe2, 0xa1, 0x64, 0x61, 0x1c, 0x9c, 0x78, 0x8b, 0x7f, 0xaa, 0x42, 0xf7, 0x15, 0x93, 0x54,
-	0x10, 0x26, 0xa6, 0x3c, 0x14, 0x4c, 0x99, 0x32, 0x0d, 0x66, 0x63, 0x3f, 0x3c, 0x4a, 0x5d, 0xe2,
-	0x40, 0xd0, 0x03, 0x58, 0x8d, 0xbd, 0xbf, 0xcf, 0xce, 0xe8, 0x2c, 0x90, 0xc7, 0x71, 0x80, 0x6b,
-	0xa4, 0x0c, 0x85, 0xbe, 0x86, 0x41, 0x0c, 0x7e, 0x96, 0x8f, 0x55, 0xad, 0xd4, 0x2d, 0x73, 0xe9,
-	0xd1, 0x2d, 0x68, 0x44, 0xb3, 0x80, 0x89, 0x41, 0x5d, 0x33, 0x76, 0x13, 0x46, 0x15, 0x48, 0x62,
-	0x70, 0xe8, 0x15, 0xac, 0xb3, 0x90, 0x9e, 0x06, 0xcc, 0xfb, 0xdb, 0xd4, 0x70, 0xbf, 0xe2, 0xde,
-	0x2c, 0x60, 0x3a, 0x08, 0xbd, 0xdd, 0xab, 0x09, 0x53, 0x16, 0x4d, 0xca, 0xb9, 0x54, 0x2a, 0x04,
-	0x7c, 0xcc, 0x75, 0x58, 0x3a, 0x44, 0xaf, 0x31, 0x81, 0xf6, 0x61, 0xe8, 0x4b, 0xc2, 0xce, 0x67,
-	0x4c, 0x48, 0xb4, 0x01, 0x35, 0x4f, 0x84, 0xda, 0x5b, 0xed, 0xdd, 0x4e, 0x22, 0x7f, 0xff, 0xe4,
-	0x88, 0x28, 0x44, 0x6a, 0x76, 0x75, 0xbe, 0xd9, 0xf8, 0x2b, 0xe8, 0x18, 0x99, 0x36, 0x12, 0x77,
-	0x61, 0x49, 0x98, 0xbb, 0x6c, 0x05, 0xf7, 0x13, 0x36, 0x7b, 0xc7, 0x49, 0x4c, 0xa0, 0x78, 0xf7,
-	0x02, 0x2e, 0x58, 0x6c, 0xd0, 0xc7, 0xf0, 0x3e, 0x05, 0xf4, 0xd2, 0x0f, 0x82, 0xe3, 0x88, 0x8f,
-	0x98, 0x10, 0x8b, 0x48, 0xf8, 0x2d, 0xb4, 0x8e, 0x69, 0x24, 0x98, 0x77, 0xf2, 0xfa, 0x1b, 0x75,
-	0x4b, 0xce, 0x67, 0x2c, 0x8a, 0x0b, 0x8c, 0xd9, 0xe0, 0x1f, 0xa0, 0xa3, 0x49, 0x16, 0x10, 0x8f,
-	0x6e, 0x43, 0x4d, 0x9c, 0x07, 0x3a, 0xc5, 0xdc, 0xbc, 0x4f, 0x54, 0x12, 0x85, 0xc6, 0xc7, 0x50,
-	0x3f, 0xe2, 0x9e, 0x0e, 0x97, 0x64, 0x97, 0x49, 0x19, 0x52, 0xeb, 0xa4, 0x6c, 0x55, 0xd3, 0xb2,
-	0x85, 0x36, 0xa1, 0x7d, 0xe6, 0x87, 0x63, 0x16, 0x4d, 0x23, 0x3f, 0x94, 0xf6, 0x4e, 0xbb, 0x20,
-	0xfc, 0x7b, 0xe8, 0x5a, 0x9b, 0x6d, 0x44, 0x6e, 0x41, 0x23, 0xe4, 0x1e, 0x13, 0x83, 0x4a, 0x2e,
-	0x8c, 0x4a, 0x31, 0x31, 0x38, 0xbc, 0x09, 0xcb, 0xcf, 0x66, 0x9e, 0x2f, 0xe7, 0xfb, 0x82, 0x42,
-	0x47, 0x53, 0x2c, 0xe2, 0x8b, 0x3b, 0x50, 0x17, 0xe7, 0x41, 0x9c, 0x48, 0x9f, 0x24, 0x84, 0xb1,
-	0x4a, 0xa2, 0xd1, 0xf8, 0x5b, 0x68, 0x5b, 0x15, 0x62, 0x16, 0x48, 0x55, 0x00, 0x26, 0x4c, 0x08,
-	0x3a, 0x8e, 0x6f, 0x74, 0xbc, 0x4d, 0x6b, 0x5a, 0xd5, 0xad, 0x69, 0xd7, 0xa1, 0xa5, 0x72, 0xf2,
-	0x7b, 0x5d, 0x16, 0x8d, 0x67, 0x96, 0x15, 0x40, 0x55, 0x00, 0xfc, 0xe7, 0xc4, 0x7c, 0x25, 0x5b,
-	0xa0, 0x1d, 0x58, 0x8a, 0xcc, 0xd2, 0xfa, 0x65, 0x2d, 0x6b, 0x95, 0xa1, 0x23, 0x31, 0x11, 0xfe,
-	0x1a, 0xba, 0x31, 0xdc, 0xb8, 0xf5, 0x31, 0x74, 0xa8, 0x23, 0xd0, 0x4a, 0x59, 0x2f, 0x93, 0x22,
-	0x48, 0x86, 0x14, 0x7f, 0x06, 0x2b, 0x47, 0x8c, 0x79, 0x84, 0x07, 0xc1, 0x29, 0x1d, 0xbd, 0x9b,
-	0xef, 0x73, 0x0e, 0xeb, 0xcf, 0x59, 0xe8, 0xd0, 0x2d, 0xe2, 0xfc, 0xbb, 0x6e, 0x22, 0x0e, 0xd2,
-	0xe8, 0x67, 0x2d, 0x30, 0xe9, 0xf8, 0x27, 0x68, 0xff, 0xa2, 0x55, 0x6e, 0x5c, 0xaa, 0x99, 0xb8,
-	0xe0, 0xa7, 0x70, 0x25, 0x6f, 0xaf, 0xf5, 0xd6, 0x96, 0x31, 0xc2, 0x18, 0x9b, 0xba, 0xba, 0x60,
-	0xc0, 0x63, 0x68, 0x1f, 0xfb, 0xe1, 0x78, 0x91, 0xfb, 0xfc, 0x29, 0x2c, 0x1d, 0x5c, 0xb2, 0xd1,
-	0x7c, 0x6f, 0x7e, 0x07, 0x6d, 0x45, 0xb0, 0x88, 0x0f, 0xb1, 0xeb, 0xc3, 0x94, 0xce, 0xea, 0x33,
-	0xa6, 0xff, 0xa7, 0x02, 0x60, 0xe4, 0xeb, 0xec, 0xc5, 0xd0, 0x09, 0xa8, 0x90, 0x87, 0xa1, 0x60,
-	0x91, 0x3c, 0x34, 0x3d, 0x4d, 0x8d, 0x64, 0x60, 0xe8, 0x1e, 0x7c, 0xe2, 0xee, 0x0f, 0xa2, 0x88,
-	0x47, 0xd6, 0xa7, 0x45, 0x84, 0x92, 0x18, 0xf1, 0x0b, 0xf1, 0xec, 0xec, 0x8c, 0x8d, 0x24, 0xf3,
-	0x74, 0x8a, 0xd7, 0x48, 0x06, 0xa6, 0x24, 0xba, 0x7b, 0x23, 0xd1, 0x3c, 0xf0, 0x45, 0x04, 0x7e,
-	0x02, 0x1d, 0x6b, 0xb1, 0x89, 0xd2, 0xe7, 0xd0, 0x34, 0xf9, 0x6e, 0x3d, 0xb2, 0x9a, 0x39, 0xa9,
-	0xbd, 0x12, 0x96, 0x04, 0x7f, 0x07, 0xad, 0x37, 0x97, 0x8b, 0x55, 0x46, 0xb7, 0x1a, 0x14, 0xbd,
-	0x69, 0x8a, 0xc1, 0x13, 0x00, 0x25, 0xde, 0x5a, 0xf6, 0xbb, 0xfc, 0x75, 0x2d, 0x35, 0x2d, 0xb9,
-	0xad, 0x9b, 0xb0, 0xfc, 0x5a, 0xc5, 0x7c, 0x7e, 0x32, 0x7c, 0x01, 0x2d, 0x4d, 0xb1, 0xc7, 0xc3,
-	0x33, 0x74, 0x1b, 0xba, 0xd2, 0x9f, 0x30, 0x3e, 0x93, 0x27, 0x6c, 0xc4, 0x43, 0x13, 0xac, 0x2e,
-	0xc9, 0x02, 0xf1, 0x3f, 0x2b, 0xd0, 0xd1, 0x3c, 0x8b, 0x1c, 0xfa, 0x96, 0x9b, 0x41, 0x69, 0x05,
-	0x8c, 0xad, 0xd4, 0x29, 0x84, 0xb6, 0xa0, 0x3e, 0xe2, 0xe1, 0x99, 0x8e, 0xac, 0xfb, 0x68, 0x24,
-	0x96, 0x12, 0x8d, 0xc7, 0x1e, 0x74, 0xad, 0x21, 0xc9, 0xf5, 0x6a, 0x8e, 0x78, 0x30, 0x9b, 0x84,
-	0xd6, 0x3b, 0x85, 0x96, 0xcd, 0x60, 0xd1, 0xe7, 0x50, 0x57, 0x59, 0x60, 0x5d, 0x7f, 0x35, 0xab,
-	0xc0, 0x3a, 0x91, 0x5f, 0x10, 0x4d, 0x84, 0xf7, 0xa0, 0x97, 0x85, 0xa3, 0x2f, 0xa0, 0xa9, 0xbb,
-	0xec, 0x38, 0x08, 0xd7, 0xca, 0x04, 0xbc, 0x55, 0x14, 0xc4, 0x12, 0xe2, 0x6d, 0xe8, 0xe7, 0x71,
-	0x69, 0xe7, 0x5e, 0x71, 0x3a, 0x77, 0x8c, 0xd5, 0xf5, 0x99, 0x06, 0xd4, 0x0f, 0xe7, 0x47, 0x6d,
-	0x04, 0x3d, 0x4b, 0xb3, 0xd8, 0x33, 0xe4, 0xc4, 0xc0, 0x4d, 0xa0, 0x58, 0xab, 0xb9, 0xc8, 0x6f,
-	0x61, 0x25, 0x51, 0x62, 0xfd, 0xbb, 0x07, 0xdd, 0x51, 0x40, 0x85, 0xf0, 0x6d, 0xa6, 0x59, 0x5d,
-	0x37, 0xf3, 0x32, 0xf6, 0x5c, 0x22, 0x92, 0xe5, 0xc1, 0x4f, 0x61, 0xad, 0x8c, 0x0c, 0x6d, 0x43,
-	0x5d, 0xb5, 0x8e, 0x85, 0xe2, 0xf8, 0x86, 0x9e, 0xce, 0x02, 0x1a, 0xed, 0x53, 0x49, 0x89, 0xa6,
-	0xc0, 0xcf, 0x60, 0xf5, 0x39, 0x93, 0xfb, 0xb6, 0xcf, 0x5c, 0xa8, 0xeb, 0xd9, 0x80, 0xe5, 0x98,
-	0xbf, 0x6c, 0x84, 0xc0, 0xcf, 0x61, 0x2d, 0xab, 0xc2, 0x7a, 0xe0, 0x3e, 0xb4, 0xe2, 0xfe, 0x36,
-	0x8e, 0x7e, 0x9a, 0xc5, 0x31, 0x39, 0x49, 0x69, 0xf0, 0x43, 0x68, 0xbc, 0x51, 0x8d, 0x69, 0xe9,
-	0xa0, 0x72, 0x05, 0x9a, 0x62, 0xf4, 0x23, 0x9b, 0x50, 0x5b, 0xed, 0xec, 0x0e, 0x8f, 0xf5, 0x01,
-	0x35, 0x9f, 0x6a, 0xf0, 0x17, 0xab, 0x2e, 0x0d, 0xa9, 0xf8, 0x6d, 0x98, 0x7b, 0xae, 0x3b, 0x55,
-	0xdb, 0xaa, 0x91, 0xf8, 0x85, 0x3e, 0xa6, 0xa3, 0xc8, 0x1e, 0xf3, 0x01, 0xb4, 0x64, 0x0c, 0xb4,
-	0xba, 0x50, 0x56, 0x82, 0x26, 0x4f, 0x89, 0xf0, 0x7f, 0x2b, 0xd0, 0x4a, 0x10, 0xe8, 0x11, 0xb4,
-	0xcd, 0x55, 0x13, 0x87, 0xe1, 0x19, 0x2f, 0x84, 0x74, 0x2f, 0xc5, 0x11, 0x97, 0x50, 0xf1, 0xf9,
-	0xa1, 0xc7, 0x2e, 0x99, 0xe1, 0xab, 0xe6, 0xf8, 0x0e, 0x53, 0x1c, 0x71, 0x09, 0xd1, 0x16, 0xf4,
-	0x46, 0x11, 0xa3, 0x92, 0x69, 0x13, 0x4e, 0x5e, 0x7f, 0x63, 0x1b, 0x9f, 0x1c, 0xd4, 0x7d, 0xb3,
-	0xeb, 0xd9, 0x37, 0xfb, 0x4b, 0x68, 0x3b, 0x56, 0x7d, 0x44, 0x32, 0x7e, 0xa9, 0xa6, 0x89, 0xd4,
-	0x92, 0x5f, 0xcf, 0xf8, 0x18, 0x56, 0x1c, 0xe0, 0x0b, 0x46, 0xbd, 0x5f, 0x3b, 0xcc, 0xaa, 0xce,
-	0xc9, 0x95, 0xc7, 0x2f, 0x84, 0x2a, 0x14, 0xbe, 0x64, 0x13, 0x93, 0x94, 0x2d, 0x62, 0x36, 0x98,
-	0x43, 0xdb, 0x21, 0x44, 0xbb, 0x6a, 0x96, 0xd4, 0x87, 0xb4, 0xb9, 0x3b, 0x28, 0xb3, 0x4f, 0x99,
-	0x42, 0x62, 0x42, 0x74, 0x2f, 0x53, 0x2b, 0x4b, 0x19, 0x94, 0x01, 0xb6, 0x58, 0xde, 0x56, 0x4f,
-	0xa9, 0x8c, 0xa8, 0x7a, 0x5c, 0xe7, 0xd7, 0xaf, 0x73, 0x18, 0x5a, 0x2a, 0x1d, 0x99, 0xbf, 0x46,
-	0x7c, 0xb2, 0x60, 0x57, 0xf7, 0x99, 0x5b, 0xcb, 0xd6, 0x9d, 0x3a, 0x94, 0xda, 0x60, 0xaa, 0xd9,
-	0x01, 0x5c, 0x2f, 0x55, 0x99, 0xbe, 0x1c, 0x3a, 0x97, 0x45, 0xe1, 0xe5, 0x30, 0xf7, 0xc5, 0x62,
-	0xf1, 0x1d, 0xe8, 0x9a, 0xde, 0x41, 0x9d, 0x79, 0xfe, 0x01, 0x25, 0xdc, 0x38, 0x10, 0xd2, 0x9f,
-	0x50, 0xa9, 0xd2, 0x2e, 0xe5, 0x58, 0xe4, 0x88, 0xdb, 0xee, 0x11, 0xaf, 0xa4, 0x8d, 0xb5, 0x6b,
-	0x86, 0x39, 0xe3, 0xdf, 0xe1, 0xe6, 0x1c, 0xad, 0xf6, 0x94, 0x6b, 0xd0, 0x18, 0xf1, 0x99, 0xfd,
-	0x93, 0x50, 0x23, 0x66, 0x83, 0x36, 0x00, 0x58, 0x14, 0xbd, 0xca, 0xf4, 0xb2, 0x0e, 0x04, 0xff,
-	0x01, 0x56, 0x33, 0x33, 0x66, 0xfa, 0xb3, 0xc1, 0x61, 0xab, 0xe4, 0xd9, 0xee, 0xfe, 0xab, 0x02,
-	0xbd, 0xc2, 0x34, 0xde, 0xcb, 0x36, 0xc6, 0xfd, 0xdf, 0xa0, 0x16, 0x34, 0xf4, 0xcb, 0xd8, 0xaf,
-	0xa0, 0xb6, 0x6a, 0x5d, 0xf5, 0xcb, 0xd0, 0xaf, 0xa2, 0x3e, 0x74, 0xdc, 0xd2, 0xd4, 0xaf, 0xa1,
-	0xab, 0xb0, 0x5a, 0x12, 0xc2, 0x7e, 0x1d, 0x5d, 0x83, 0xf5, 0xd2, 0x73, 0xf7, 0x1b, 0x68, 0x05,
-	0xda, 0x8e, 0xed, 0xfd, 0xe6, 0xee, 0xbf, 0x97, 0xa1, 0xb9, 0xaf, 0x7f, 0xc5, 0xa1, 0xfb, 0xd0,
-	0xd0, 0xbf, 0x4f, 0x50, 0x1a, 0x6c, 0xfd, 0x23, 0x6e, 0x98, 0x3a, 0x39, 0xfb, 0x7b, 0xe5, 0x21,
-	0xd4, 0xd5, 0x90, 0x8f, 0xdc, 0x82, 0x94, 0x4c, 0x82, 0xc3, 0xf5, 0x1c, 0xd4, 0x32, 0xed, 0x40,
-	0x43, 0x4f, 0xf7, 0x28, 0xc5, 0xbb, 0xd3, 0xfe, 0x30, 0xa7, 0x1c, 0xbd, 0xc8, 0x58, 0x8c, 0xae,
-	0xa7, 0xbf, 0x8a, 0x0a, 0x73, 0xfe, 0xf0, 0x46, 0x39, 0xd2, 0x6a, 0x7e, 0xa4, 0x7f, 0x1a, 0x66,
-	0x34, 0xbb, 0x63, 0xbc, 0x73, 0xcc, 0xec, 0xa4, 0xfc, 0x08, 0x1a, 0x7a, 0x6a, 0x43, 0x85, 0x29,
-	0x2e, 0xcf, 0x97, 0x1d, 0x05, 0x5f, 0xe7, 0xa3, 0x8b, 0x36, 0x12, 0xca, 0xd2, 0xf9, 0x6d, 0xf8,
-	0xe9, 0x5c, 0xbc, 0x15, 0x79, 0x0f, 0xea, 0x6a, 0x0e, 0x72, 0x3c, 0xee, 0x8c, 0x45, 0x05, 0xd7,
-	0x3d, 0x84, 0xba, 0xea, 0x82, 0x1d, 0x6a, 0x67, 0xd0, 0x19, 0xae, 0xe7, 0x5b, 0xe5, 0xb8, 0xa5,
-	0xae, 0xbe, 0xb9, 0x44, 0xce, 0xeb, 0x16, 0x37, 0xf3, 0xc3, 0xd5, 0x0c, 0x2c, 0x75, 0x8e, 0x4e,
-	0x57, 0xc7, 0x39, 0x6e, 0x33, 0xec, 0x38, 0x27, 0xdb, 0x9a, 0xfe, 0x31, 0xc9, 0x6d, 0x74, 0x35,
-	0xdf, 0x2e, 0xc5, 0xbc, 0x83, 0x22, 0xc2, 0x72, 0xbf, 0xd4, 0x97, 0x21, 0x69, 0x47, 0xd0, 0x0d,
-	0xc7, 0x71, 0x85, 0x46, 0x68, 0x78, 0x73, 0x0e, 0x36, 0x23, 0x2c, 0x7d, 0xac, 0x33, 0xc2, 0xf2,
-	0x4d, 0x47, 0x56, 0x58, 0xb1, 0x53, 0xf8, 0xa1, 0xf4, 0x52, 0xa2, 0x5b, 0xf9, 0x52, 0x5c, 0x52,
-	0xe8, 0x87, 0xb7, 0x7f, 0x9e, 0xc8, 0x6a, 0x38, 0x9b, 0x73, 0xbb, 0xd1, 0x9d, 0x94, 0xfd, 0x67,
-	0x6a, 0xed, 0x70, 0xeb, 0x97, 0xc8, 0x8c, 0x9e, 0xbf, 0x74, 0xbe, 0x85, 0x9d, 0xfb, 0x4f, 0x2c,
-	0xed, 0x69, 0x53, 0x2f, 0x1e, 0xfe, 0x3f, 0x00, 0x00, 0xff, 0xff, 0xc4, 0x9b, 0x00, 0x1c, 0xb8,
-	0x17, 0x00, 0x00,
+	// 2077 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x9c, 0x18, 0xdb, 0x72, 0xd4, 0xc8,
+	0x35, 0x9a, 0xab, 0xe7, 0xcc, 0x85, 0xd9, 0xb6, 0x0d, 0x62, 0xc0, 0xac, 0xd3, 0x60, 0xe3, 0x62,
+	0x89, 0x61, 0x87, 0x64, 0x29, 0x96, 0x24, 0x85, 0x63, 0x3b, 0xe0, 0x05, 0x1c, 0xd3, 0x76, 0x48,
+	0x55, 0xaa, 0xb6, 0xbc, 0xf2, 0xa8, 0xc7, 0xa8, 0xd0, 0x48, 0x63, 0x75, 0x8f, 0x2f, 0xfb, 0x03,
+	0xf9, 0x80, 0x7c, 0x42, 0x7e, 0x20, 0x2f, 0xfb, 0x90, 0x87, 0x3c, 0xe6, 0x27, 0xf2, 0x90, 0x6f,
+	0x49, 0xf5, 0x45, 0x52, 0x4b, 0xa3, 0xd9, 0x5d, 0xe6, 0x49, 0xea, 0x73, 0xef, 0x73, 0xeb, 0xd3,
+	0x0d, 0xd7, 0xdc, 0xc8, 0x3b, 0xa7, 0xd1, 0xf1, 0x79, 0x7f, 0x73, 0x1c, 0x85, 0x3c, 0x44, 0x75,
+	0xf9, 0x79, 0xdf, 0xc7, 0x75, 0xa8, 0xee, 0x8e, 0xc6, 0xfc, 0x0a, 0xdf, 0x84, 0xfa, 0x21, 0x65,
+	0xcc, 0x0b, 0x03, 0xd4, 0x81, 0x92, 0xe7, 0xda, 0xd6, 0xaa, 0xb5, 0xd1, 0x20, 0x25, 0xcf, 0xc5,
+	0x7f, 0x81, 0xea, 0x81, 0x13, 0x39, 0x23, 0xd4, 0x85, 0xf2, 0x47, 0x7a, 0xa5, 0x31, 0xe2, 0x17,
+	0x2d, 0x41, 0xf5, 0xdc, 0xf1, 0x27, 0xd4, 0x2e, 0x49, 0x98, 0x5a, 0x20, 0x04, 0x15, 0x97, 0xb2,
+	0x81, 0x5d, 0x96, 0x40, 0xf9, 0x2f, 0x60, 0xfc, 0x6a, 0x4c, 0xed, 0x8a, 0x82, 0x89, 0x7f, 0xfc,
+	0x83, 0x05, 0xe5, 0x9d, 0xc3, 0x7d, 0x81, 0xfb, 0x10, 0x32, 0xae, 0x05, 0xcb, 0x7f, 0x01, 0x1b,
+	0x87, 0x11, 0xd7, 0x82, 0xe5, 0xbf, 0x80, 0x4d, 0x18, 0x8d, 0x62, 0xb9, 0xe2, 0x1f, 0xf5, 0x60,
+	0x61, 0xec, 0x30, 0x76, 0x11, 0x46, 0xae, 0x96, 0x9d, 0xac, 0x05, 0xce, 0x75, 0xb8, 0x73, 0xe2,
+	0x30, 0x6a, 0x57, 0x15, 0x2e, 0x5e, 0xa3, 0xaf, 0xa1, 0xeb, 0xb8, 0xae, 0xc7, 0xbd, 0x30, 0x70,
+	0x7c, 0xb9, 0x3d, 0x66, 0xd7, 0x56, 0xcb, 0x1b, 0xcd, 0x7e, 0x67, 0x53, 0x3b, 0x67, 0x53, 0x82,
+	0xc9, 0x14, 0x1d, 0xfe, 0xaf, 0x05, 0x15, 0x32, 0xf1, 0xe5, 0x46, 0x03, 0x67, 0x44, 0x63, 0xc3,
+	0xc5, 0x7f, 0xb2, 0xf9, 0x92, 0xb1, 0xf9, 0x25, 0xa8, 0xfa, 0xf4, 0x9c, 0xfa, 0xda, 0x72, 0xb5,
+	0x10, 0xe6, 0x0d, 0x1c, 0x4e, 0x4f, 0xc3, 0xe8, 0x2a, 0x36, 0x3d, 0x5e, 0xa3, 0x75, 0xa8, 0x8d,
+	0x95, 0x51, 0xd5, 0x42, 0xa3, 0x34, 0x16, 0xdd, 0x01, 0x70, 0x82, 0x20, 0xe4, 0x8e, 0x30, 0xd0,
+	0xae, 0x49, 0x29, 0x06, 0x04, 0x3d, 0x86, 0xc6, 0xc7, 0x20, 0xbc, 0xf0, 0xa9, 0x7b, 0x4a, 0xed,
+	0xfa, 0xaa, 0xb5, 0xd1, 0xec, 0xa3, 0x44, 0xd4, 0xeb, 0x18, 0x43, 0x52, 0x22, 0xbc, 0x06, 0x8d,
+	0x04, 0x8e, 0x6c, 0xa8, 0x0f, 0xc2, 0x80, 0xd3, 0x20, 0x0e, 0x4e, 0xbc, 0xc4, 0x3f, 0x94, 0xa0,
+	0xfd, 0x96, 0x72, 0x87, 0x11, 0xca, 0xc6, 0x61, 0xc0, 0xa8, 0x30, 0x65, 0xec, 0x4f, 0x4e, 0xbd,
+	0x60, 0x3f, 0x75, 0x89, 0x01, 0x41, 0x8f, 0x61, 0x31, 0xf6, 0xfe, 0x0e, 0x1d, 0x3a, 0x13, 0x9f,
+	0x1f, 0xc4, 0x01, 0x2e, 0x93, 0x22, 0x14, 0xfa, 0x06, 0xec, 0x18, 0xbc, 0x95, 0x8f, 0x55, 0xb9,
+	0xd0, 0x2d, 0x33, 0xe9, 0xd1, 0x5d, 0xa8, 0x46, 0x13, 0x9f, 0x32, 0xbb, 0x22, 0x19, 0xdb, 0x09,
+	0xa3, 0x08, 0x24, 0x51, 0x38, 0xf4, 0x16, 0x96, 0x69, 0xe0, 0x9c, 0xf8, 0xd4, 0xfd, 0xd3, 0x58,
+	0x71, 0xbf, 0x0d, 0xdd, 0x89, 0x4f, 0x65, 0x10, 0x3a, 0xfd, 0x1b, 0x09, 0x53, 0x16, 0x4d, 0x8a,
+	0xb9, 0x44, 0x2a, 0xf8, 0xe1, 0x69, 0x28, 0xc3, 0xd2, 0x22, 0xf2, 0x1f, 0x13, 0x68, 0xee, 0x05,
+	0x1e, 0x27, 0xf4, 0x6c, 0x42, 0x19, 0x47, 0x77, 0xa0, 0xec, 0xb2, 0x40, 0x7a, 0xab, 0xd9, 0x6f,
+	0x25, 0xf2, 0x77, 0x0e, 0xf7, 0x89, 0x40, 0xa4, 0x66, 0x97, 0x66, 0x9b, 0x8d, 0xbf, 0x86, 0x96,
+	0x92, 0xa9, 0x23, 0xf1, 0x00, 0xea, 0x4c, 0xd5, 0xb2, 0x16, 0xdc, 0x4d, 0xd8, 0x74, 0x8d, 0x93,
+	0x98, 0x40, 0xf0, 0x6e, 0xfb, 0x21, 0xa3, 0xb1, 0x41, 0x9f, 0xc2, 0xfb, 0x02, 0xd0, 0x6b, 0xcf,
+	0xf7, 0x0f, 0xa2, 0x70, 0x40, 0x19, 0x9b, 0x47, 0xc2, 0x2f, 0xa1, 0x71, 0xe0, 0x44, 0x8c, 0xba,
+	0x87, 0xef, 0xde, 0x88, 0x2a, 0x39, 0x9b, 0xd0, 0x28, 0x6e, 0x30, 0x6a, 0x81, 0xbf, 0x83, 0x96,
+	0x24, 0x99, 0x43, 0x3c, 0xba, 0x07, 0x65, 0x76, 0xe6, 0xdb, 0xa5, 0x5c, 0xde, 0x27, 0x2a, 0x89,
+	0x40, 0xe3, 0x03, 0xa8, 0xec, 0x87, 0xae, 0x0c, 0x17, 0xa7, 0x97, 0x49, 0x1b, 0x12, 0xff, 0x49,
+	0xdb, 0x2a, 0xa5, 0x6d, 0x0b, 0xad, 0x42, 0x73, 0xe8, 0x05, 0xa7, 0x34, 0x1a, 0x47, 0x5e, 0xc0,
+	0x75, 0x4d, 0x9b, 0x20, 0xfc, 0x6b, 0x68, 0x6b, 0x9b, 0x75, 0x44, 0xee, 0x42, 0x35, 0x08, 0x5d,
+	0xca, 0x6c, 0x2b, 0x17, 0x46, 0xa1, 0x98, 0x28, 0x1c, 0x5e, 0x85, 0x85, 0xad, 0x89, 0xeb, 0xf1,
+	0xd9, 0xbe, 0x70, 0xa0, 0x25, 0x29, 0xe6, 0xf1, 0xc5, 0x1a, 0x54, 0xd8, 0x99, 0x1f, 0x27, 0xd2,
+	0x67, 0x09, 0x61, 0xac, 0x92, 0x48, 0x34, 0xfe, 0xbb, 0x05, 0x4d, 0xad, 0x83, 0x4d, 0x7c, 0x2e,
+	0x3a, 0xc0, 0x88, 0x32, 0xe6, 0x9c, 0xc6, 0x25, 0x1d, 0x2f, 0xd3, 0xa6, 0x56, 0x32, 0x9b, 0xda,
+	0x2d, 0x68, 0x88, 0xa4, 0x3c, 0x96, 0x7d, 0x51, 0xb9, 0x66, 0x41, 0x00, 0x64, 0x0b, 0x78, 0x0a,
+	0x2d, 0x2f, 0x70, 0xe9, 0xe5, 0xb1, 0xe3, 0x9e, 0x7b, 0x03, 0x75, 0x18, 0x34, 0xfb, 0x4b, 0x89,
+	0x2d, 0x7b, 0x02, 0xb9, 0x25, 0x71, 0xa4, 0xe9, 0xa5, 0x0b, 0xfc, 0x7b, 0x68, 0x19, 0x46, 0x31,
+	0xb4, 0x09, 0xf5, 0x48, 0xfd, 0x6a, 0x8f, 0x2e, 0x65, 0xf7, 0xa3, 0xe8, 0x48, 0x4c, 0x84, 0xbf,
+	0x81, 0x76, 0x0c, 0x57, 0x01, 0x79, 0x06, 0x2d, 0xc7, 0x10, 0xa8, 0xa5, 0x2c, 0x17, 0x49, 0x61,
+	0x24, 0x43, 0x8a, 0xef, 0xc3, 0xb5, 0x7d, 0x4a, 0x5d, 0x12, 0xfa, 0xfe, 0x89, 0x33, 0xf8, 0x38,
+	0x3b, 0x5a, 0x21, 0x2c, 0xbf, 0xa4, 0x81, 0x41, 0x37, 0x4f, 0xd8, 0x1e, 0x98, 0x29, 0x6c, 0xa7,
+	0x79, 0x93, 0xb5, 0x40, 0x25, 0xf2, 0xef, 0xa0, 0xf9, 0x93, 0x56, 0x99, 0x01, 0x2d, 0x65, 0x02,
+	0x8a, 0x5f, 0xc0, 0xf5, 0xbc, 0xbd, 0xda, 0x5b, 0xeb, 0xca, 0x08, 0x2b, 0x17, 0xae, 0x29, 0x03,
+	0x9e, 0x41, 0xf3, 0xc0, 0x0b, 0x4e, 0xe7, 0xe9, 0x04, 0x9f, 0x43, 0x7d, 0xf7, 0x92, 0x0e, 0x66,
+	0x7b, 0xf3, 0x5b, 0x68, 0x0a, 0x82, 0x79, 0x7c, 0x88, 0x4d, 0x1f, 0xa6, 0x74, 0x5a, 0x9f, 0x32,
+	0xfd, 0x9f, 0x16, 0x80, 0x92, 0x2f, 0xd3, 0x1e, 0x43, 0xcb, 0x77, 0x18, 0xdf, 0x0b, 0x18, 0x8d,
+	0xf8, 0x9e, 0x9a, 0x86, 0xca, 0x24, 0x03, 0x43, 0x0f, 0xe1, 0x33, 0x73, 0xbd, 0x1b, 0x45, 0x61,
+	0xa4, 0x7d, 0x3a, 0x8d, 0x10, 0x12, 0xa3, 0xf0, 0x82, 0x6d, 0x0d, 0x87, 0x74, 0xc0, 0xa9, 0x2b,
+	0x6b, 0xa3, 0x4c, 0x32, 0x30, 0x21, 0xd1, 0x5c, 0x2b, 0x89, 0x6a, 0x34, 0x98, 0x46, 0xe0, 0xe7,
+	0xd0, 0xd2, 0x16, 0xab, 0x28, 0x7d, 0x01, 0x35, 0x95, 0xef, 0xda, 0x23, 0x8b, 0x99, 0x9d, 0xea,
+	0x92, 0xd0, 0x24, 0xf8, 0x5b, 0x68, 0x1c, 0x5d, 0xce, 0xd7, 0x53, 0xcd, 0x3e, 0x32, 0xed, 0x4d,
+	0xd5, 0x46, 0x9e, 0x03, 0x1c, 0x5d, 0x26, 0x96, 0xfd, 0x2a, 0x5f, 0xae, 0x85, 0xa6, 0x25, 0xd5,
+	0xba, 0x0a, 0x0b, 0xef, 0x44, 0xcc, 0x67, 0x27, 0xc3, 0x97, 0xd0, 0x90, 0x14, 0xdb, 0x61, 0x30,
+	0x44, 0xf7, 0xa0, 0xcd, 0xbd, 0x11, 0x0d, 0x27, 0xfc, 0x90, 0x0e, 0xc2, 0x40, 0x05, 0xab, 0x4d,
+	0xb2, 0x40, 0xfc, 0x37, 0x0b, 0x5a, 0x92, 0x67, 0x9e, 0x4d, 0xdf, 0x35, 0x33, 0x28, 0xed, 0x9d,
+	0xb1, 0x95, 0x32, 0x85, 0xd0, 0x3a, 0x54, 0x06, 0x61, 0x30, 0xb4, 0xcb, 0xb9, 0xe3, 0x26, 0xb1,
+	0x94, 0x48, 0x3c, 0x76, 0xa1, 0xad, 0x0d, 0x49, 0xca, 0xab, 0x36, 0x08, 0xfd, 0xc9, 0x28, 0xb0,
+	0xad, 0xc2, 0xa9, 0x46, 0x63, 0xd1, 0x17, 0x50, 0x11, 0x59, 0xa0, 0x5d, 0x7f, 0x23, 0xab, 0x40,
+	0x3b, 0x31, 0xbc, 0x20, 0x92, 0x08, 0x6f, 0x43, 0x27, 0x0b, 0x47, 0x5f, 0x42, 0x4d, 0xce, 0xe7,
+	0x71, 0x10, 0x6e, 0x16, 0x09, 0x78, 0x2f, 0x28, 0x88, 0x26, 0xc4, 0x1b, 0xd0, 0xcd, 0xe3, 0xd2,
+	0x99, 0xdf, 0x32, 0x66, 0x7e, 0x8c, 0x45, 0xf9, 0x8c, 0x7d, 0xc7, 0x0b, 0x66, 0x47, 0x6d, 0x00,
+	0x1d, 0x4d, 0x33, 0xdf, 0x01, 0x66, 0xc4, 0xc0, 0x4c, 0xa0, 0x58, 0xab, 0x2a, 0xe4, 0xf7, 0x70,
+	0x2d, 0x51, 0xa2, 0xfd, 0xbb, 0x0d, 0xed, 0x81, 0xef, 0x30, 0xe6, 0xe9, 0x4c, 0xd3, 0xba, 0x56,
+	0xf2, 0x32, 0xb6, 0x4d, 0x22, 0x92, 0xe5, 0xc1, 0x2f, 0x60, 0xa9, 0x88, 0x0c, 0x6d, 0x40, 0x45,
+	0x0c, 0x9d, 0x53, 0xcd, 0xf1, 0xc8, 0x39, 0x99, 0xf8, 0x4e, 0xb4, 0xe3, 0x70, 0x87, 0x48, 0x0a,
+	0xbc, 0x05, 0x8b, 0x2f, 0x29, 0xdf, 0xd1, 0x13, 0xea, 0x5c, 0xf3, 0xd2, 0x1d, 0x58, 0x88, 0xf9,
+	0x8b, 0x2e, 0x1f, 0xf8, 0x25, 0x2c, 0x65, 0x55, 0x68, 0x0f, 0x3c, 0x82, 0x46, 0x3c, 0x19, 0xc7,
+	0xd1, 0x4f, 0xb3, 0x38, 0x26, 0x27, 0x29, 0x0d, 0x7e, 0x02, 0xd5, 0x23, 0x31, 0xd2, 0x16, 0x69,
+	0x41, 0xd7, 0xa1, 0xc6, 0x06, 0x1f, 0xe8, 0xc8, 0xd1, 0xdd, 0x4e, 0xaf, 0xf0, 0xa9, 0xdc, 0xa0,
+	0xe4, 0x13, 0x57, 0x83, 0xf9, 0xba, 0x4b, 0x95, 0x0b, 0x7e, 0x1d, 0xe6, 0x8e, 0xe9, 0x4e, 0x31,
+	0xf0, 0x4a, 0x24, 0x7e, 0x25, 0xb7, 0x69, 0x28, 0xd2, 0xdb, 0x7c, 0x0c, 0x0d, 0x1e, 0x03, 0x6d,
+	0x2b, 0x57, 0x86, 0x29, 0x79, 0x4a, 0x84, 0xff, 0x6d, 0x41, 0x23, 0x41, 0xa0, 0xaf, 0xa0, 0xa9,
+	0x4a, 0x8d, 0xed, 0x05, 0xc3, 0x70, 0x2a, 0xa4, 0xdb, 0x29, 0x8e, 0x98, 0x84, 0x82, 0x4f, 0x4e,
+	0x2b, 0x54, 0xf1, 0x95, 0x8a, 0xc6, 0x1a, 0xaa, 0xf9, 0x0c, 0x42, 0xb4, 0x0e, 0x9d, 0x41, 0x44,
+	0x1d, 0x4e, 0xa5, 0x09, 0x87, 0xef, 0xde, 0xe8, 0x89, 0x29, 0x07, 0x35, 0xcf, 0xec, 0x4a, 0xf6,
+	0xcc, 0x7e, 0x0a, 0x4d, 0xc3, 0xaa, 0x4f, 0x48, 0xc6, 0xa7, 0xe2, 0x1e, 0x92, 0x5a, 0xf2, 0xf3,
+	0x19, 0x9f, 0xc1, 0x35, 0x03, 0xf8, 0x8a, 0x3a, 0xee, 0xcf, 0xbd, 0x06, 0xe3, 0xfb, 0x19, 0x56,
+	0x12, 0x5e, 0x30, 0xd1, 0x28, 0x3c, 0x4e, 0x47, 0x2a, 0x29, 0x1b, 0x44, 0x2d, 0x70, 0x08, 0x4d,
+	0x83, 0x10, 0xf5, 0xc5, 0x2d, 0x54, 0x6e, 0x52, 0xe7, 0xae, 0x5d, 0x64, 0x9f, 0x30, 0x85, 0xc4,
+	0x84, 0xe8, 0x61, 0xa6, 0x57, 0x16, 0x32, 0x08, 0x03, 0x74, 0xb3, 0xbc, 0x27, 0x8e, 0x52, 0x1e,
+	0x39, 0xe2, 0x70, 0x9d, 0xdd, 0xbf, 0xce, 0xa0, 0xa7, 0xa9, 0x64, 0x64, 0xfe, 0x18, 0x85, 0xa3,
+	0x39, 0xa7, 0xba, 0xfb, 0x66, 0x2f, 0x5b, 0x36, 0xfa, 0x50, 0x6a, 0x83, 0xea, 0x66, 0xbb, 0x70,
+	0xab, 0x50, 0x65, 0x7a, 0x72, 0xc8, 0x5c, 0x66, 0x53, 0x27, 0x87, 0xaa, 0x17, 0x8d, 0xc5, 0x6b,
+	0xd0, 0x56, 0xb3, 0x83, 0xd8, 0xf3, 0xec, 0x0d, 0x72, 0xb8, 0xbd, 0xcb, 0xb8, 0x37, 0x72, 0xb8,
+	0x48, 0xbb, 0x94, 0x63, 0x9e, 0x2d, 0x6e, 0x98, 0x5b, 0xbc, 0x9e, 0x0e, 0xd6, 0xa6, 0x19, 0x6a,
+	0x8f, 0x7f, 0x86, 0x95, 0x19, 0x5a, 0xf5, 0x2e, 0x97, 0xa0, 0x3a, 0x08, 0x27, 0xfa, 0x0d, 0xa2,
+	0x4c, 0xd4, 0x42, 0xbc, 0x37, 0xd0, 0x28, 0x7a, 0x9b, 0x99, 0x65, 0x0d, 0x08, 0xfe, 0x0d, 0x2c,
+	0x66, 0x6e, 0xa7, 0xe9, 0x33, 0x85, 0xc1, 0x66, 0x4d, 0xb1, 0xfd, 0xcb, 0x82, 0xcf, 0xde, 0x78,
+	0x4c, 0xf9, 0x7b, 0xae, 0x9d, 0xcf, 0x68, 0x8f, 0xa8, 0x0f, 0x4b, 0xc3, 0xc9, 0xf7, 0xdf, 0x5f,
+	0x1d, 0x52, 0x27, 0x1a, 0x7c, 0x90, 0xf2, 0xf7, 0xd3, 0x5b, 0x52, 0x21, 0x4e, 0x5e, 0xb2, 0xbc,
+	0x91, 0xc7, 0x65, 0xdd, 0xb7, 0x89, 0x5a, 0x08, 0x0d, 0xe1, 0x70, 0xc8, 0x28, 0x97, 0xcf, 0x5a,
+	0x6d, 0xa2, 0x57, 0x98, 0x00, 0x32, 0x4d, 0xff, 0xb4, 0x24, 0x11, 0xba, 0x78, 0xc8, 0x1d, 0x15,
+	0xb3, 0x0a, 0x51, 0x0b, 0xfc, 0x1f, 0x4b, 0x77, 0x0a, 0x75, 0x15, 0x43, 0x2b, 0x00, 0x92, 0xfe,
+	0xd8, 0x28, 0xf9, 0x06, 0x4f, 0x0c, 0xb6, 0xd3, 0x5a, 0x2d, 0xc9, 0x92, 0x8e, 0x97, 0x68, 0x03,
+	0xba, 0xaa, 0xad, 0x1d, 0xab, 0x3b, 0xa0, 0xc8, 0x8e, 0x4c, 0xbb, 0x93, 0x5a, 0x0e, 0xcf, 0x7c,
+	0xf4, 0x10, 0xd0, 0x20, 0x3c, 0xa7, 0x91, 0x17, 0x9c, 0x1a, 0xb4, 0xaa, 0xf3, 0x75, 0x63, 0x4c,
+	0x42, 0xbd, 0x06, 0x1d, 0xaa, 0xd3, 0xc7, 0x3d, 0x96, 0x35, 0x5f, 0x95, 0x69, 0xd2, 0x4e, 0xa0,
+	0x22, 0x99, 0x1e, 0xfc, 0xc3, 0x82, 0xce, 0xd4, 0xfb, 0x4c, 0x27, 0x7b, 0xe1, 0xe9, 0xfe, 0x02,
+	0x35, 0xa0, 0x2a, 0x27, 0x9e, 0xae, 0x85, 0x9a, 0x50, 0xd7, 0x27, 0x7e, 0xb7, 0x84, 0xba, 0xd0,
+	0x32, 0x8f, 0x9c, 0x6e, 0x19, 0xdd, 0x80, 0xc5, 0x82, 0xd2, 0xec, 0x56, 0xd0, 0x4d, 0x58, 0x2e,
+	0xcc, 0xe7, 0x6e, 0x15, 0x5d, 0x83, 0xa6, 0x91, 0x93, 0xdd, 0x1a, 0xea, 0x00, 0xa4, 0x11, 0xeb,
+	0xd6, 0xfb, 0xff, 0x5b, 0x80, 0xda, 0x8e, 0x7c, 0xac, 0x45, 0x8f, 0xa0, 0x2a, 0x34, 0x31, 0x94,
+	0xc6, 0x4b, 0x3e, 0xd5, 0xf6, 0xd2, 0x62, 0xca, 0x3e, 0xc0, 0x3d, 0x81, 0x8a, 0x78, 0x06, 0x42,
+	0xe6, 0xc1, 0x93, 0xbc, 0x15, 0xf4, 0x96, 0x73, 0x50, 0xcd, 0xb4, 0x09, 0x55, 0xf9, 0xfe, 0x83,
+	0x52, 0xbc, 0xf9, 0x1e, 0xd4, 0xcb, 0x29, 0x47, 0xaf, 0x32, 0x3b, 0x40, 0xb7, 0xd2, 0xc7, 0xc4,
+	0xa9, 0x97, 0xa0, 0xde, 0xed, 0x62, 0xa4, 0xd6, 0xfc, 0x95, 0x7c, 0x56, 0xce, 0x68, 0x36, 0x1f,
+	0x7a, 0x7a, 0xd7, 0xf3, 0xe0, 0x94, 0x4f, 0xde, 0xce, 0xd1, 0xd4, 0x6d, 0x3d, 0xcf, 0x97, 0xbd,
+	0xf2, 0xbf, 0xcb, 0x47, 0x1b, 0xdd, 0x49, 0x28, 0x0b, 0xef, 0xe9, 0xbd, 0xcf, 0x67, 0xe2, 0xb5,
+	0xc8, 0x87, 0x50, 0x11, 0xf7, 0x5d, 0xc3, 0xe3, 0xc6, 0xf5, 0x77, 0xca, 0x75, 0x4f, 0xa0, 0x22,
+	0x6e, 0x3b, 0x06, 0xb5, 0x71, 0xa1, 0xed, 0x2d, 0xe7, 0xa0, 0xc9, 0xd5, 0xa9, 0x74, 0x74, 0x89,
+	0x8c, 0x29, 0x26, 0xbe, 0xb4, 0xf5, 0x16, 0x33, 0xb0, 0xd4, 0x39, 0x32, 0x7d, 0x0d, 0xe7, 0x98,
+	0x97, 0x9e, 0xde, 0xf5, 0x3c, 0x58, 0xf3, 0xfd, 0x36, 0xc9, 0x75, 0x74, 0x23, 0x3f, 0x16, 0xc7,
+	0xbc, 0xf6, 0x34, 0x42, 0x73, 0xbf, 0x96, 0xc5, 0x91, 0x8c, 0x9d, 0xe8, 0xb6, 0xe1, 0xb8, 0xa9,
+	0x81, 0xb7, 0xb7, 0x32, 0x03, 0x9b, 0x11, 0x96, 0x0e, 0x65, 0x19, 0x61, 0xf9, 0xe1, 0xb2, 0xb7,
+	0x32, 0x03, 0xab, 0x85, 0x7d, 0x57, 0x58, 0xa4, 0xe8, 0x6e, 0xfe, 0xc8, 0x2d, 0x38, 0xd0, 0x7b,
+	0xf7, 0x7e, 0x9c, 0x48, 0x6b, 0x18, 0xce, 0xa8, 0x76, 0xb4, 0x96, 0xb2, 0xff, 0xc8, 0x99, 0xda,
+	0x5b, 0xff, 0x29, 0x32, 0xad, 0x67, 0xd7, 0xec, 0x14, 0xa8, 0x97, 0x70, 0x4d, 0x9d, 0x55, 0xbd,
+	0x5b, 0x85, 0x38, 0x25, 0xe6, 0x0f, 0xad, 0xbf, 0xc2, 0xe6, 0xa3, 0xe7, 0x9a, 0xe0, 0xa4, 0x26,
+	0x7f, 0x9e, 0xfc, 0x7f, 0x00, 0xb5, 0xa9, 0xb6, 0x36, 0x21, 0x1a, 0x00, 0x00,
 }
@@ -48,6 +48,7 @@ const (
 	OptionalModuleExtractTableFromSQL
 	OptionalModuleEstimateSQLAffectRows
 	OptionalModuleKillProcess
+	OptionalModuleListTables
 )
 
 func (m OptionalModule) String() string {
@@ -66,6 +67,8 @@ func (m OptionalModule) String() string {
 		return "EstimateSQLAffectRows"
 	case OptionalModuleKillProcess:
 		return "KillProcess"
+	case OptionalModuleListTables:
+		return "ListTables"
 	default:
 		return "Unknown"
 	}
@@ -211,6 +214,32 @@ func ConvertProtoTableMetaToDriver(meta *protoV2.TableMeta) *TableMeta {
 	}
 }
 
+func ConvertIndexAdviceToProto(advice *IndexAdvice) *protoV2.IndexAdvice {
+	if advice == nil {
+		return nil
+	}
+	return &protoV2.IndexAdvice{
+		TableName:        advice.TableName,
+		Columns:          advice.Columns,
+		CreateIndexSql:   advice.CreateIndexSQL,
+		CoveringIndexSql: advice.CoveringIndexSQL,
+		EstimatedRows:    advice.EstimatedRows,
+	}
+}
+
+func ConvertProtoIndexAdviceToDriver(advice *protoV2.IndexAdvice) *IndexAdvice {
+	if advice == nil {
+		return nil
+	}
+	return &IndexAdvice{
+		TableName:        advice.TableName,
+		Columns:          advice.Columns,
+		CreateIndexSQL:   advice.CreateIndexSql,
+		CoveringIndexSQL: advice.CoveringIndexSql,
+		EstimatedRows:    advice.EstimatedRows,
+	}
+}
+
 func RandStr(length int) string {
 	str := "0123456789abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ"
 	bytes := []byte(str)
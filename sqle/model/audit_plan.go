@@ -85,6 +85,12 @@ func (s *Storage) GetAuditPlans() ([]*AuditPlan, error) {
 	return aps, errors.New(errors.ConnectStorageError, err)
 }
 
+func (s *Storage) GetAuditPlansByProjectId(projectId uint) ([]*AuditPlan, error) {
+	var aps []*AuditPlan
+	err := s.db.Model(AuditPlan{}).Where("project_id = ?", projectId).Order("id ASC").Find(&aps).Error
+	return aps, errors.New(errors.ConnectStorageError, err)
+}
+
 func (s *Storage) GetActiveAuditPlans() ([]*AuditPlan, error) {
 	var aps []*AuditPlan
 	err := s.db.Model(AuditPlan{}).
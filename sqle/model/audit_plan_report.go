@@ -1,6 +1,8 @@
 package model
 
 import (
+	"time"
+
 	"github.com/actiontech/sqle/sqle/errors"
 
 	"github.com/jinzhu/gorm"
@@ -28,6 +30,10 @@ type AuditPlanReportSQLV2 struct {
 	Number            uint         `json:"number"`
 	AuditResults      AuditResults `json:"audit_results" gorm:"type:json"`
 	Schema            string       `json:"schema" gorm:"type:varchar(512);not null"`
+	// FingerprintMD5 and Info are copied from the audit plan SQL when the report is generated,
+	// so that the execution statistics of the SQL can be traced through reports.
+	FingerprintMD5 string `json:"fingerprint_md5" gorm:"column:fingerprint_md5;index"`
+	Info           JSON   `json:"info" gorm:"type:json"`
 
 	AuditPlanReport *AuditPlanReportV2 `gorm:"foreignkey:AuditPlanReportID"`
 }
@@ -48,6 +54,32 @@ func (s *Storage) GetAuditPlanReportSQLV2ByReportIDAndNumber(reportId, number ui
 	return auditPlanReportSQLV2, true, errors.New(errors.ConnectStorageError, err)
 }
 
+type AuditPlanReportSQLInfoHistory struct {
+	ReportCreatedAt time.Time `json:"report_created_at"`
+	Info            JSON      `json:"info"`
+}
+
+// GetAuditPlanReportSQLInfoHistory returns the latest limit info snapshots of the SQL in reports of the audit plan,
+// ordered by report created time ascending.
+func (s *Storage) GetAuditPlanReportSQLInfoHistory(auditPlanId uint, fingerprintMD5 string, limit int) ([]*AuditPlanReportSQLInfoHistory, error) {
+	history := []*AuditPlanReportSQLInfoHistory{}
+	err := s.db.Model(&AuditPlanReportSQLV2{}).
+		Select("audit_plan_reports_v2.created_at AS report_created_at, audit_plan_report_sqls_v2.info").
+		Joins("JOIN audit_plan_reports_v2 ON audit_plan_reports_v2.id = audit_plan_report_sqls_v2.audit_plan_report_id").
+		Where("audit_plan_reports_v2.audit_plan_id = ? AND audit_plan_reports_v2.deleted_at IS NULL", auditPlanId).
+		Where("audit_plan_report_sqls_v2.fingerprint_md5 = ?", fingerprintMD5).
+		Order("audit_plan_reports_v2.created_at DESC").
+		Limit(limit).
+		Scan(&history).Error
+	if err != nil {
+		return nil, errors.ConnectStorageErrWrapper(err)
+	}
+	for i, j := 0, len(history)-1; i < j; i, j = i+1, j-1 {
+		history[i], history[j] = history[j], history[i]
+	}
+	return history, nil
+}
+
 func (s *Storage) GetAuditPlanReportByProjectName(projectName string) ([]*AuditPlanReportV2, error) {
 	auditPlanReportV2Slice := []*AuditPlanReportV2{}
 	err := s.db.Model(&AuditPlanReportV2{}).
@@ -0,0 +1,99 @@
+package model
+
+import (
+	"time"
+
+	"github.com/actiontech/sqle/sqle/errors"
+	"github.com/jinzhu/gorm"
+)
+
+// ClusterLeader only has one record, the server which hold the record is the leader of cluster.
+type ClusterLeader struct {
+	Anchor       int       `gorm:"primary_key;auto_increment:false" json:"anchor"`
+	ServerId     string    `gorm:"type:varchar(255);not null" json:"server_id"`
+	LastSeenTime time.Time `gorm:"type:datetime;not null" json:"last_seen_time"`
+}
+
+const clusterLeaderAnchor = 1
+
+type ClusterNodeInfo struct {
+	ServerId      string    `gorm:"primary_key;type:varchar(255)" json:"server_id"`
+	HeartbeatTime time.Time `gorm:"type:datetime;not null" json:"heartbeat_time"`
+	CreatedAt     time.Time `gorm:"default:current_timestamp" json:"created_at"`
+	UpdatedAt     time.Time `gorm:"default:current_timestamp on update current_timestamp" json:"updated_at"`
+}
+
+// UpdateClusterNodeHeartbeat register the server as a cluster node if not exist, then refresh its heartbeat time.
+// The time of storage is used for all nodes, so the clock skew between nodes will not affect the election.
+func (s *Storage) UpdateClusterNodeHeartbeat(serverId string) error {
+	err := s.db.Exec(`INSERT INTO cluster_node_infos (server_id, heartbeat_time) VALUES (?, NOW())
+ON DUPLICATE KEY UPDATE heartbeat_time = NOW()`, serverId).Error
+	return errors.New(errors.ConnectStorageError, err)
+}
+
+func (s *Storage) DeleteClusterNode(serverId string) error {
+	err := s.db.Exec("DELETE FROM cluster_node_infos WHERE server_id = ?", serverId).Error
+	return errors.New(errors.ConnectStorageError, err)
+}
+
+// TryAcquireClusterLeaderLease try to become the leader or renew the lease if the server is already the leader.
+// The leader will be replaced only when it has not renewed the lease for leaseSeconds.
+// NOTE: the assignments of "ON DUPLICATE KEY UPDATE" are evaluated from left to right,
+// so `last_seen_time` is refreshed only when `server_id` is (or just become) current server.
+func (s *Storage) TryAcquireClusterLeaderLease(serverId string, leaseSeconds int) (isLeader bool, err error) {
+	err = s.db.Exec(`INSERT INTO cluster_leaders (anchor, server_id, last_seen_time) VALUES (?, ?, NOW())
+ON DUPLICATE KEY UPDATE
+server_id = IF(last_seen_time < NOW() - INTERVAL ? SECOND, VALUES(server_id), server_id),
+last_seen_time = IF(server_id = VALUES(server_id), VALUES(last_seen_time), last_seen_time)`,
+		clusterLeaderAnchor, serverId, leaseSeconds).Error
+	if err != nil {
+		return false, errors.New(errors.ConnectStorageError, err)
+	}
+	var count int
+	err = s.db.Model(&ClusterLeader{}).Where("anchor = ? AND server_id = ?", clusterLeaderAnchor, serverId).
+		Count(&count).Error
+	if err != nil {
+		return false, errors.New(errors.ConnectStorageError, err)
+	}
+	return count > 0, nil
+}
+
+// ReleaseClusterLeaderLease let other nodes take over the leader without waiting for the lease to expire.
+func (s *Storage) ReleaseClusterLeaderLease(serverId string) error {
+	err := s.db.Exec("DELETE FROM cluster_leaders WHERE anchor = ? AND server_id = ?",
+		clusterLeaderAnchor, serverId).Error
+	return errors.New(errors.ConnectStorageError, err)
+}
+
+func (s *Storage) GetClusterLeader(leaseSeconds int) (leader *ClusterLeader, exist bool, err error) {
+	leader = &ClusterLeader{}
+	err = s.db.Where("anchor = ? AND last_seen_time >= NOW() - INTERVAL ? SECOND", clusterLeaderAnchor, leaseSeconds).
+		First(leader).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, false, nil
+	}
+	return leader, true, errors.New(errors.ConnectStorageError, err)
+}
+
+type ClusterNodeStatus struct {
+	ServerId      string    `json:"server_id"`
+	HeartbeatTime time.Time `json:"heartbeat_time"`
+	IsAlive       bool      `json:"is_alive"`
+}
+
+// GetClusterNodeStatusList returns all registered nodes, a node is alive if its heartbeat is not older than aliveSeconds.
+func (s *Storage) GetClusterNodeStatusList(aliveSeconds int) ([]*ClusterNodeStatus, error) {
+	nodes := []*ClusterNodeStatus{}
+	err := s.db.Model(&ClusterNodeInfo{}).
+		Select("server_id, heartbeat_time, heartbeat_time >= NOW() - INTERVAL ? SECOND AS is_alive", aliveSeconds).
+		Order("server_id").Scan(&nodes).Error
+	return nodes, errors.New(errors.ConnectStorageError, err)
+}
+
+// GetAliveClusterNodeIds returns the server id of nodes which heartbeat is not older than aliveSeconds.
+func (s *Storage) GetAliveClusterNodeIds(aliveSeconds int) ([]string, error) {
+	ids := []string{}
+	err := s.db.Model(&ClusterNodeInfo{}).Where("heartbeat_time >= NOW() - INTERVAL ? SECOND", aliveSeconds).
+		Order("server_id").Pluck("server_id", &ids).Error
+	return ids, errors.New(errors.ConnectStorageError, err)
+}
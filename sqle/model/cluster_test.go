@@ -0,0 +1,42 @@
+package model
+
+import (
+	"testing"
+
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStorage_TryAcquireClusterLeaderLease(t *testing.T) {
+	acquireSQL := `INSERT INTO cluster_leaders (anchor, server_id, last_seen_time) VALUES (?, ?, NOW())
+ON DUPLICATE KEY UPDATE
+server_id = IF(last_seen_time < NOW() - INTERVAL ? SECOND, VALUES(server_id), server_id),
+last_seen_time = IF(server_id = VALUES(server_id), VALUES(last_seen_time), last_seen_time)`
+	checkSQL := "SELECT count(*) FROM `cluster_leaders`  WHERE (anchor = ? AND server_id = ?)"
+
+	// 1. become leader
+	mockDB, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	assert.NoError(t, err)
+	InitMockStorage(mockDB)
+	mock.ExpectExec(acquireSQL).WithArgs(1, "server_1", 30).WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectQuery(checkSQL).WithArgs(1, "server_1").WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+	mock.ExpectClose()
+	isLeader, err := GetStorage().TryAcquireClusterLeaderLease("server_1", 30)
+	assert.NoError(t, err)
+	assert.True(t, isLeader)
+	mockDB.Close()
+	assert.NoError(t, mock.ExpectationsWereMet())
+
+	// 2. the lease is held by other server
+	mockDB, mock, err = sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	assert.NoError(t, err)
+	InitMockStorage(mockDB)
+	mock.ExpectExec(acquireSQL).WithArgs(1, "server_2", 30).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(checkSQL).WithArgs(1, "server_2").WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+	mock.ExpectClose()
+	isLeader, err = GetStorage().TryAcquireClusterLeaderLease("server_2", 30)
+	assert.NoError(t, err)
+	assert.False(t, isLeader)
+	mockDB.Close()
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
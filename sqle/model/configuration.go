@@ -2,6 +2,8 @@ package model
 
 import (
 	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
 	e "errors"
 	"fmt"
 	"strconv"
@@ -237,10 +239,73 @@ type Oauth2Configuration struct {
 	ServerAuthUrl   string `json:"server_auth_url" gorm:"column:server_auth_url"`
 	ServerTokenUrl  string `json:"server_token_url" gorm:"column:server_token_url"`
 	ServerUserIdUrl string `json:"server_user_id_url" gorm:"column:server_user_id_url"`
-	Scopes          string `json:"scopes" gorm:"column:scopes"`
-	AccessTokenTag  string `json:"access_token_tag" gorm:"column:access_token_tag"`
-	UserIdTag       string `json:"user_id_tag" gorm:"column:user_id_tag"`
-	LoginTip        string `json:"login_tip" gorm:"column:login_tip; default:'使用第三方账户登录'"`
+	// ServerJwksUrl verifies the signature of OIDC ID token which is ignored if the url is empty,
+	// ServerIssuer is the expected issuer of ID token.
+	ServerJwksUrl  string `json:"server_jwks_url" gorm:"column:server_jwks_url"`
+	ServerIssuer   string `json:"server_issuer" gorm:"column:server_issuer"`
+	Scopes         string `json:"scopes" gorm:"column:scopes"`
+	AccessTokenTag string `json:"access_token_tag" gorm:"column:access_token_tag"`
+	UserIdTag      string `json:"user_id_tag" gorm:"column:user_id_tag"`
+	// UserNameTag is the claim used as the name of user created by oauth2, e.g. "preferred_username".
+	UserNameTag   string `json:"user_name_tag" gorm:"column:user_name_tag"`
+	UserEmailTag  string `json:"user_email_tag" gorm:"column:user_email_tag"`
+	UserGroupsTag string `json:"user_groups_tag" gorm:"column:user_groups_tag"`
+	// AutoCreateUser creates the user at the first login, otherwise the user has to bind a SQLE user.
+	AutoCreateUser bool                `json:"auto_create_user" gorm:"column:auto_create_user"`
+	GroupMappings  Oauth2GroupMappings `json:"group_mappings" gorm:"column:group_mappings; type:text"`
+	LoginTip       string              `json:"login_tip" gorm:"column:login_tip; default:'使用第三方账户登录'"`
+}
+
+// Oauth2GroupMapping grants the user groups and project roles to the users in the group of
+// identity provider when they login.
+type Oauth2GroupMapping struct {
+	Group        string              `json:"group"`
+	UserGroups   []string            `json:"user_groups"`
+	ProjectRoles []Oauth2ProjectRole `json:"project_roles"`
+}
+
+type Oauth2ProjectRole struct {
+	ProjectName string     `json:"project_name"`
+	IsManager   bool       `json:"is_manager"`
+	BindRoles   []BindRole `json:"bind_roles"`
+}
+
+type Oauth2GroupMappings []Oauth2GroupMapping
+
+func (m Oauth2GroupMappings) Value() (driver.Value, error) {
+	b, err := json.Marshal(m)
+	return string(b), err
+}
+
+func (m *Oauth2GroupMappings) Scan(input interface{}) error {
+	switch v := input.(type) {
+	case []byte:
+		if len(v) == 0 {
+			return nil
+		}
+		return json.Unmarshal(v, m)
+	case string:
+		if v == "" {
+			return nil
+		}
+		return json.Unmarshal([]byte(v), m)
+	}
+	return nil
+}
+
+// Match returns the mappings of the groups which the user belongs to.
+func (m Oauth2GroupMappings) Match(groups []string) []Oauth2GroupMapping {
+	belong := make(map[string]struct{}, len(groups))
+	for _, group := range groups {
+		belong[group] = struct{}{}
+	}
+	matched := []Oauth2GroupMapping{}
+	for _, mapping := range m {
+		if _, ok := belong[mapping.Group]; ok {
+			matched = append(matched, mapping)
+		}
+	}
+	return matched
 }
 
 func (i *Oauth2Configuration) GetScopes() []string {
@@ -152,6 +152,12 @@ func (s *Storage) GetInstancesBySyncTaskId(projectID, syncTaskID uint) ([]*Insta
 	return instances, nil
 }
 
+func (s *Storage) GetInstancesByProjectId(projectID uint) ([]*Instance, error) {
+	instances := []*Instance{}
+	err := s.db.Preload("RuleTemplates").Where("project_id = ?", projectID).Find(&instances).Error
+	return instances, errors.New(errors.ConnectStorageError, err)
+}
+
 func (s *Storage) DeleteInstance(instance *Instance) error {
 	err := s.DeleteRoleByInstanceID(instance.ID)
 	if err != nil {
@@ -277,9 +283,11 @@ type TypeCount struct {
 	Count  int64  `json:"count"`
 }
 
-func (s *Storage) GetAllInstanceCount() ([]*TypeCount, error) {
+func (s *Storage) GetAllInstanceCount(filter *StatisticFilter) ([]*TypeCount, error) {
 	var counts []*TypeCount
-	return counts, s.db.Table("instances").Select("db_type, count(*) as count").Where("deleted_at is NULL").Group("db_type").Find(&counts).Error
+	query := s.db.Table("instances").Select("instances.db_type, count(*) as count").Where("instances.deleted_at IS NULL")
+	err := filter.apply(query, "instances").Group("instances.db_type").Scan(&counts).Error
+	return counts, errors.ConnectStorageErrWrapper(err)
 }
 
 func (s *Storage) GetInstanceTipsByUser(user *User, dbType string, projectName string) (
@@ -408,13 +416,16 @@ func (s *Storage) BatchUpdateSyncTask(instTemplate *SyncTaskInstance) error {
 				return err
 			}
 
-			if err := s.UpdateInstanceRuleTemplates(instance, instTemplate.RuleTemplate); err != nil {
+			if err := tx.Model(instance).Association("RuleTemplates").Replace(instTemplate.RuleTemplate).Error; err != nil {
 				return err
 			}
 		}
 
 		for _, instance := range instTemplate.NeedDeletedInstances {
-			if err := s.DeleteInstance(instance); err != nil {
+			if err := tx.Exec(deleteRoleByInstanceIDQuery, instance.ID).Error; err != nil {
+				return err
+			}
+			if err := tx.Delete(instance).Error; err != nil {
 				return err
 			}
 		}
@@ -0,0 +1,81 @@
+package model
+
+import (
+	"time"
+
+	"github.com/actiontech/sqle/sqle/errors"
+	"github.com/jinzhu/gorm"
+)
+
+const (
+	OnlineDDLCutOverRequestCutOver  = "cut_over"
+	OnlineDDLCutOverRequestPostpone = "postpone"
+)
+
+// OnlineDDLMigration is the gh-ost migration running for the executing task. The migration can
+// only be controlled on the node which runs it, so the cut-over requested on other nodes is saved
+// here and applied by that node.
+type OnlineDDLMigration struct {
+	TaskId       uint   `gorm:"primary_key;auto_increment:false" json:"task_id"`
+	ExecuteSQLId uint   `gorm:"not null" json:"execute_sql_id"`
+	MigrationKey string `gorm:"type:varchar(512);not null" json:"migration_key"`
+	// ServerId and Host are the node which runs the migration, the ServerId is empty if the
+	// cluster mode is disabled.
+	ServerId       string    `gorm:"type:varchar(255)" json:"server_id"`
+	Host           string    `gorm:"type:varchar(255)" json:"host"`
+	CutOverRequest string    `gorm:"type:varchar(16)" json:"cut_over_request"`
+	CreatedAt      time.Time `gorm:"default:current_timestamp" json:"created_at"`
+	UpdatedAt      time.Time `gorm:"default:current_timestamp on update current_timestamp" json:"updated_at"`
+}
+
+// SaveOnlineDDLMigration registers the migration of task, the migration of previous SQL of the
+// task is replaced.
+func (s *Storage) SaveOnlineDDLMigration(m *OnlineDDLMigration) error {
+	err := s.db.Exec(`INSERT INTO online_ddl_migrations (task_id, execute_sql_id, migration_key, server_id, host, cut_over_request)
+VALUES (?, ?, ?, ?, ?, '')
+ON DUPLICATE KEY UPDATE execute_sql_id = VALUES(execute_sql_id), migration_key = VALUES(migration_key),
+server_id = VALUES(server_id), host = VALUES(host), cut_over_request = ''`,
+		m.TaskId, m.ExecuteSQLId, m.MigrationKey, m.ServerId, m.Host).Error
+	return errors.New(errors.ConnectStorageError, err)
+}
+
+func (s *Storage) DeleteOnlineDDLMigration(taskId, executeSQLId uint) error {
+	err := s.db.Exec("DELETE FROM online_ddl_migrations WHERE task_id = ? AND execute_sql_id = ?",
+		taskId, executeSQLId).Error
+	return errors.New(errors.ConnectStorageError, err)
+}
+
+func (s *Storage) GetOnlineDDLMigration(taskId uint) (*OnlineDDLMigration, bool, error) {
+	m := &OnlineDDLMigration{}
+	err := s.db.Where("task_id = ?", taskId).First(m).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, false, nil
+	}
+	return m, true, errors.New(errors.ConnectStorageError, err)
+}
+
+// RequestOnlineDDLCutOver saves the cut-over request for the node which runs the migration, the
+// previous request which is not applied yet is overwritten.
+func (s *Storage) RequestOnlineDDLCutOver(taskId uint, request string) error {
+	err := s.db.Model(&OnlineDDLMigration{}).Where("task_id = ?", taskId).
+		Update("cut_over_request", request).Error
+	return errors.New(errors.ConnectStorageError, err)
+}
+
+// TakeOnlineDDLCutOverRequest returns the cut-over request of the migration and clears it, it
+// returns empty string if there is no request.
+func (s *Storage) TakeOnlineDDLCutOverRequest(taskId, executeSQLId uint) (string, error) {
+	var requests []string
+	err := s.db.Model(&OnlineDDLMigration{}).Where("task_id = ? AND execute_sql_id = ?", taskId, executeSQLId).
+		Pluck("cut_over_request", &requests).Error
+	if err != nil {
+		return "", errors.New(errors.ConnectStorageError, err)
+	}
+	if len(requests) == 0 || requests[0] == "" {
+		return "", nil
+	}
+	// the request is cleared only if it is not overwritten in the meantime.
+	err = s.db.Exec("UPDATE online_ddl_migrations SET cut_over_request = '' WHERE task_id = ? AND cut_over_request = ?",
+		taskId, requests[0]).Error
+	return requests[0], errors.New(errors.ConnectStorageError, err)
+}
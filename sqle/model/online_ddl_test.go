@@ -0,0 +1,40 @@
+package model
+
+import (
+	"testing"
+
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStorage_TakeOnlineDDLCutOverRequest(t *testing.T) {
+	selectSQL := "SELECT cut_over_request FROM `online_ddl_migrations`  WHERE (task_id = ? AND execute_sql_id = ?)"
+	clearSQL := "UPDATE online_ddl_migrations SET cut_over_request = '' WHERE task_id = ? AND cut_over_request = ?"
+
+	// 1. the request is taken and cleared
+	mockDB, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	assert.NoError(t, err)
+	InitMockStorage(mockDB)
+	mock.ExpectQuery(selectSQL).WithArgs(1, 2).
+		WillReturnRows(sqlmock.NewRows([]string{"cut_over_request"}).AddRow(OnlineDDLCutOverRequestPostpone))
+	mock.ExpectExec(clearSQL).WithArgs(1, OnlineDDLCutOverRequestPostpone).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectClose()
+	request, err := GetStorage().TakeOnlineDDLCutOverRequest(1, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, OnlineDDLCutOverRequestPostpone, request)
+	mockDB.Close()
+	assert.NoError(t, mock.ExpectationsWereMet())
+
+	// 2. there is no request
+	mockDB, mock, err = sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	assert.NoError(t, err)
+	InitMockStorage(mockDB)
+	mock.ExpectQuery(selectSQL).WithArgs(1, 2).
+		WillReturnRows(sqlmock.NewRows([]string{"cut_over_request"}).AddRow(""))
+	mock.ExpectClose()
+	request, err = GetStorage().TakeOnlineDDLCutOverRequest(1, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, "", request)
+	mockDB.Close()
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -1,17 +1,134 @@
 package model
 
-import "time"
+import (
+	"time"
+
+	"github.com/actiontech/sqle/sqle/errors"
+)
+
+const (
+	OperationRecordStatusSucceeded = "succeeded"
+	OperationRecordStatusFailed    = "failed"
+)
+
+// The operation types, the operation type of the API is the resource which the API operates.
+const (
+	OperationRecordTypeProject        = "project"
+	OperationRecordTypeMember         = "member"
+	OperationRecordTypeUser           = "user"
+	OperationRecordTypeUserGroup      = "user_group"
+	OperationRecordTypeRole           = "role"
+	OperationRecordTypeInstance       = "instance"
+	OperationRecordTypeRuleTemplate   = "rule_template"
+	OperationRecordTypeWorkflow       = "workflow"
+	OperationRecordTypeAuditPlan      = "audit_plan"
+	OperationRecordTypeAuditWhitelist = "audit_whitelist"
+	OperationRecordTypeSQLAudit       = "sql_audit"
+	OperationRecordTypeSQLManage      = "sql_manage"
+	OperationRecordTypeConfiguration  = "configuration"
+	OperationRecordTypeOther          = "other"
+)
+
+var OperationRecordTypeDesc = map[string]string{
+	OperationRecordTypeProject:        "项目",
+	OperationRecordTypeMember:         "项目成员",
+	OperationRecordTypeUser:           "用户",
+	OperationRecordTypeUserGroup:      "用户组",
+	OperationRecordTypeRole:           "角色",
+	OperationRecordTypeInstance:       "数据源",
+	OperationRecordTypeRuleTemplate:   "规则模板",
+	OperationRecordTypeWorkflow:       "工单",
+	OperationRecordTypeAuditPlan:      "智能扫描",
+	OperationRecordTypeAuditWhitelist: "审核SQL例外",
+	OperationRecordTypeSQLAudit:       "SQL审核",
+	OperationRecordTypeSQLManage:      "SQL管控",
+	OperationRecordTypeConfiguration:  "系统配置",
+	OperationRecordTypeOther:          "其他",
+}
+
+// OperationRecordTypes keeps the order of the operation types in the list.
+var OperationRecordTypes = []string{
+	OperationRecordTypeProject,
+	OperationRecordTypeMember,
+	OperationRecordTypeUser,
+	OperationRecordTypeUserGroup,
+	OperationRecordTypeRole,
+	OperationRecordTypeInstance,
+	OperationRecordTypeRuleTemplate,
+	OperationRecordTypeWorkflow,
+	OperationRecordTypeAuditPlan,
+	OperationRecordTypeAuditWhitelist,
+	OperationRecordTypeSQLAudit,
+	OperationRecordTypeSQLManage,
+	OperationRecordTypeConfiguration,
+	OperationRecordTypeOther,
+}
+
+// The operation actions, the action of the API is the verb at the end of the path, or it is
+// create, update or delete according to the method of the API.
+const (
+	OperationRecordActionCreate    = "create"
+	OperationRecordActionUpdate    = "update"
+	OperationRecordActionDelete    = "delete"
+	OperationRecordActionApprove   = "approve"
+	OperationRecordActionReject    = "reject"
+	OperationRecordActionCancel    = "cancel"
+	OperationRecordActionComplete  = "complete"
+	OperationRecordActionExecute   = "execute"
+	OperationRecordActionTerminate = "terminate"
+	OperationRecordActionRehearse  = "rehearse"
+	OperationRecordActionPause     = "pause"
+	OperationRecordActionResume    = "resume"
+	OperationRecordActionCutOver   = "cut_over"
+	OperationRecordActionSchedule  = "schedule"
+	OperationRecordActionClone     = "clone"
+	OperationRecordActionArchive   = "archive"
+	OperationRecordActionUnarchive = "unarchive"
+	OperationRecordActionTrigger   = "trigger"
+	OperationRecordActionTest      = "test"
+	OperationRecordActionCheck     = "check"
+	OperationRecordActionParse     = "parse"
+	OperationRecordActionPassword  = "password"
+	OperationRecordActionLogout    = "logout"
+)
+
+var OperationRecordActionDesc = map[string]string{
+	OperationRecordActionCreate:    "创建",
+	OperationRecordActionUpdate:    "编辑",
+	OperationRecordActionDelete:    "删除",
+	OperationRecordActionApprove:   "审核通过",
+	OperationRecordActionReject:    "驳回",
+	OperationRecordActionCancel:    "关闭",
+	OperationRecordActionComplete:  "标记为人工上线",
+	OperationRecordActionExecute:   "上线",
+	OperationRecordActionTerminate: "中止上线",
+	OperationRecordActionRehearse:  "预演",
+	OperationRecordActionPause:     "暂停上线",
+	OperationRecordActionResume:    "恢复上线",
+	OperationRecordActionCutOver:   "切换表",
+	OperationRecordActionSchedule:  "定时上线",
+	OperationRecordActionClone:     "克隆",
+	OperationRecordActionArchive:   "冻结",
+	OperationRecordActionUnarchive: "解冻",
+	OperationRecordActionTrigger:   "触发",
+	OperationRecordActionTest:      "测试",
+	OperationRecordActionCheck:     "校验",
+	OperationRecordActionParse:     "解析",
+	OperationRecordActionPassword:  "修改密码",
+	OperationRecordActionLogout:    "登出",
+}
 
 type OperationRecord struct {
 	Model
-	OperationTime        time.Time `gorm:"column:operation_time;type:datetime;" json:"operation_time"`
-	OperationUserName    string    `gorm:"column:operation_user_name;type:varchar(255);not null" json:"operation_user_name"`
-	OperationReqIP       string    `gorm:"column:operation_req_ip" json:"operation_req_ip"`
-	OperationTypeName    string    `gorm:"column:operation_type_name" json:"operation_type_name"`
-	OperationAction      string    `gorm:"column:operation_action" json:"operation_action"`
-	OperationContent     string    `gorm:"column:operation_content" json:"operation_content"`
-	OperationProjectName string    `gorm:"column:operation_project_name" json:"operation_project_name"`
-	OperationStatus      string    `gorm:"column:operation_status" json:"operation_status"`
+	OperationTime         time.Time `gorm:"column:operation_time;type:datetime;" json:"operation_time"`
+	OperationUserName     string    `gorm:"column:operation_user_name;type:varchar(255);not null" json:"operation_user_name"`
+	OperationReqIP        string    `gorm:"column:operation_req_ip" json:"operation_req_ip"`
+	OperationTypeName     string    `gorm:"column:operation_type_name" json:"operation_type_name"`
+	OperationAction       string    `gorm:"column:operation_action" json:"operation_action"`
+	OperationContent      string    `gorm:"column:operation_content" json:"operation_content"`
+	OperationProjectName  string    `gorm:"column:operation_project_name" json:"operation_project_name"`
+	OperationStatus       string    `gorm:"column:operation_status" json:"operation_status"`
+	OperationErrorMessage string    `gorm:"column:operation_error_message;type:text" json:"operation_error_message"`
 }
 
 func (s *Storage) GetOperationRecordProjectNameList() ([]string, error) {
@@ -35,3 +152,90 @@ func (s *Storage) GetExpiredOperationRecordIDListByStartTime(start time.Time) ([
 func (s *Storage) DeleteExpiredOperationRecordByIDList(idList []string) error {
 	return s.db.Exec("DELETE FROM operation_records WHERE id IN (?)", idList).Error
 }
+
+type OperationActionItem struct {
+	OperationTypeName string `json:"operation_type_name"`
+	OperationAction   string `json:"operation_action"`
+}
+
+// GetOperationActionList returns the distinct operation actions which are recorded.
+func (s *Storage) GetOperationActionList() ([]*OperationActionItem, error) {
+	items := []*OperationActionItem{}
+	err := s.db.Model(&OperationRecord{}).
+		Select("DISTINCT operation_type_name, operation_action").
+		Order("operation_type_name, operation_action").
+		Scan(&items).Error
+	return items, errors.New(errors.ConnectStorageError, err)
+}
+
+var operationRecordsQueryTpl = `
+SELECT operation_records.id,
+       operation_records.operation_time,
+       operation_records.operation_user_name,
+       operation_records.operation_req_ip,
+       operation_records.operation_type_name,
+       operation_records.operation_action,
+       operation_records.operation_content,
+       operation_records.operation_project_name,
+       operation_records.operation_status,
+       operation_records.operation_error_message
+
+{{- template "body" . -}}
+ORDER BY operation_records.operation_time DESC, operation_records.id DESC
+{{- if .limit }}
+LIMIT :limit OFFSET :offset
+{{- end -}}
+`
+
+var operationRecordsCountTpl = `
+SELECT COUNT(*)
+
+{{- template "body" . -}}
+`
+
+var operationRecordsQueryBodyTpl = `
+{{ define "body" }}
+FROM operation_records
+WHERE
+operation_records.deleted_at IS NULL
+
+{{- if .filter_operate_time_from }}
+AND operation_records.operation_time > :filter_operate_time_from
+{{- end }}
+
+{{- if .filter_operate_time_to }}
+AND operation_records.operation_time < :filter_operate_time_to
+{{- end }}
+
+{{- if .check_operate_project_name }}
+AND operation_records.operation_project_name = :filter_operate_project_name
+{{- end }}
+
+{{- if .fuzzy_search_operate_user_name }}
+AND operation_records.operation_user_name LIKE :fuzzy_search_operate_user_name
+{{- end }}
+
+{{- if .filter_operate_type_name }}
+AND operation_records.operation_type_name = :filter_operate_type_name
+{{- end }}
+
+{{- if .filter_operate_action }}
+AND operation_records.operation_action = :filter_operate_action
+{{- end }}
+
+{{ end }}
+`
+
+func (s *Storage) GetOperationRecordsByReq(data map[string]interface{}) (
+	result []*OperationRecord, count uint64, err error) {
+
+	err = s.getListResult(operationRecordsQueryBodyTpl, operationRecordsQueryTpl, data, &result)
+	if err != nil {
+		return result, 0, errors.New(errors.ConnectStorageError, err)
+	}
+	count, err = s.getCountResult(operationRecordsQueryBodyTpl, operationRecordsCountTpl, data)
+	if err != nil {
+		return result, 0, errors.New(errors.ConnectStorageError, err)
+	}
+	return result, count, nil
+}
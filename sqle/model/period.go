@@ -99,3 +99,23 @@ func (r *Periods) IsWithinScope(executeTime time.Time) bool {
 	}
 	return false
 }
+
+// CurrentPeriod returns the start and end time of the period which the time is within,
+// the period ends latest is returned if the periods overlap. ok is false if the time is
+// not within any period.
+func (r *Periods) CurrentPeriod(t time.Time) (start, end time.Time, ok bool) {
+	hm := t.Hour()*60 + t.Minute()
+	for _, period := range *r {
+		if hm < period.StartHour*60+period.StartMinute || hm > period.EndHour*60+period.EndMinute {
+			continue
+		}
+		periodEnd := time.Date(t.Year(), t.Month(), t.Day(), period.EndHour, period.EndMinute, 0, 0, t.Location())
+		if ok && !periodEnd.After(end) {
+			continue
+		}
+		start = time.Date(t.Year(), t.Month(), t.Day(), period.StartHour, period.StartMinute, 0, 0, t.Location())
+		end = periodEnd
+		ok = true
+	}
+	return start, end, ok
+}
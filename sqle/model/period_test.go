@@ -283,3 +283,28 @@ func TestPeriods_IsWithinScope(t *testing.T) {
 	assert.Equal(t, ps.IsWithinScope(t5), false)
 
 }
+
+func TestPeriods_CurrentPeriod(t *testing.T) {
+	ps := Periods{
+		&Period{StartHour: 2, StartMinute: 0, EndHour: 4, EndMinute: 0},
+		&Period{StartHour: 3, StartMinute: 0, EndHour: 5, EndMinute: 30},
+		&Period{StartHour: 22, StartMinute: 0, EndHour: 23, EndMinute: 0},
+	}
+
+	// the periods overlap, the period ends latest is returned
+	t0 := time.Date(2017, 12, 8, 3, 10, 0, 0, time.UTC)
+	start, end, ok := ps.CurrentPeriod(t0)
+	assert.True(t, ok)
+	assert.Equal(t, time.Date(2017, 12, 8, 3, 0, 0, 0, time.UTC), start)
+	assert.Equal(t, time.Date(2017, 12, 8, 5, 30, 0, 0, time.UTC), end)
+
+	t1 := time.Date(2017, 12, 8, 22, 30, 0, 0, time.UTC)
+	start, end, ok = ps.CurrentPeriod(t1)
+	assert.True(t, ok)
+	assert.Equal(t, time.Date(2017, 12, 8, 22, 0, 0, 0, time.UTC), start)
+	assert.Equal(t, time.Date(2017, 12, 8, 23, 0, 0, 0, time.UTC), end)
+
+	// not within any period
+	_, _, ok = ps.CurrentPeriod(time.Date(2017, 12, 8, 12, 0, 0, 0, time.UTC))
+	assert.False(t, ok)
+}
@@ -445,6 +445,15 @@ type CustomRule struct {
 	Knowledge   *RuleKnowledge `json:"knowledge" gorm:"foreignkey:KnowledgeId"`
 }
 
+const (
+	// CustomRuleScriptTypeRegular means the rule script is a regular expression, the rule is
+	// triggered if the SQL matches it.
+	CustomRuleScriptTypeRegular = "regular"
+	// CustomRuleScriptTypeExpression means the rule script is a boolean expression, the rule is
+	// triggered if the expression is true, see customrule.Expression.
+	CustomRuleScriptTypeExpression = "expression"
+)
+
 func (s *Storage) GetCustomRuleByRuleId(ruleId string) (*CustomRule, bool, error) {
 	rule := &CustomRule{}
 	err := s.db.Where("rule_id = ?", ruleId).Find(rule).Error
@@ -6,7 +6,9 @@ import (
 	"strings"
 	"time"
 
+	driverV2 "github.com/actiontech/sqle/sqle/driver/v2"
 	"github.com/actiontech/sqle/sqle/errors"
+	"github.com/actiontech/sqle/sqle/utils"
 	"github.com/jinzhu/gorm"
 )
 
@@ -18,11 +20,13 @@ const (
 
 	SQLManageSourceAuditPlan      = "audit_plan"
 	SQLManageSourceSqlAuditRecord = "sql_audit_record"
+	SQLManageSourceWorkflow       = "workflow"
 )
 
 var SqlManageSourceMap = map[string]string{
 	SQLManageSourceSqlAuditRecord: "SQL审核",
 	SQLManageSourceAuditPlan:      "智能扫描",
+	SQLManageSourceWorkflow:       "工单",
 }
 
 var SqlManageStatusMap = map[string]string{
@@ -66,6 +70,44 @@ func (sm SqlManageSqlAuditRecord) TableName() string {
 	return "sql_manage_sql_audit_records"
 }
 
+// GenProjFpSourceInstSchemaMd5 generates the identity of SQL in the ledger, the same SQL from the same
+// source on the same instance and schema is recorded once in a project.
+func GenProjFpSourceInstSchemaMd5(projectId uint, fingerprint, source, instName, schemaName string) string {
+	return utils.Md5String(fmt.Sprintf("%d:%s:%s:%s:%s", projectId, fingerprint, source, instName, schemaName))
+}
+
+// MergeAuditResults merges the audit results which are triggered by the SQL, the message of the same
+// rule is replaced by the latest one.
+func (sm *SqlManage) MergeAuditResults(level string, results AuditResults) {
+	if driverV2.RuleLevel(level).More(driverV2.RuleLevel(sm.AuditLevel)) {
+		sm.AuditLevel = level
+	}
+	for _, result := range results {
+		replaced := false
+		for i := range sm.AuditResults {
+			existed := sm.AuditResults[i]
+			if (result.RuleName != "" && existed.RuleName == result.RuleName) ||
+				(result.RuleName == "" && existed.RuleName == "" && existed.Message == result.Message) {
+				sm.AuditResults[i] = result
+				replaced = true
+				break
+			}
+		}
+		if !replaced {
+			sm.AuditResults = append(sm.AuditResults, result)
+		}
+	}
+}
+
+func (s *Storage) GetSqlManagesByProjFpSourceInstSchemaMd5s(md5s []string) ([]*SqlManage, error) {
+	sqlManageList := []*SqlManage{}
+	if len(md5s) == 0 {
+		return sqlManageList, nil
+	}
+	err := s.db.Where("proj_fp_source_inst_schema_md5 IN (?)", md5s).Find(&sqlManageList).Error
+	return sqlManageList, errors.New(errors.ConnectStorageError, err)
+}
+
 func (s *Storage) GetSqlManageByFingerprintSourceInstNameSchemaMd5(projFpSourceInstSchemaMd5 string) (*SqlManage, bool, error) {
 	sqlManage := &SqlManage{}
 	err := s.db.Where("proj_fp_source_inst_schema_md5 = ?", projFpSourceInstSchemaMd5).Find(sqlManage).Error
@@ -168,7 +210,7 @@ WHERE p.name = :project_name
   AND sm.deleted_at IS NULL
 
 {{- if .fuzzy_search_sql_fingerprint }}
-AND sm.sql_fingerprint LIKE '%{{ .fuzzy_search_sql_fingerprint }}%'
+AND sm.sql_fingerprint LIKE :fuzzy_search_sql_fingerprint
 {{- end }}
 
 {{- if .filter_assignee }}
@@ -287,6 +329,9 @@ INSERT INTO sql_manages (sql_fingerprint, proj_fp_source_inst_schema_md5, sql_te
 	return errors.New(errors.ConnectStorageError, s.db.Exec(raw, args...).Error)
 }
 
+// InsertOrUpdateSqlManage upserts the SQLs into the ledger, the FpCount of SqlManage is the
+// increment of the occurrence count, it is added to the stored count so that the concurrent
+// syncs of the same SQL do not overwrite each other.
 func (s *Storage) InsertOrUpdateSqlManage(sqlManageList []*SqlManage, sqlAuditRecordID uint) error {
 	return s.Tx(func(tx *gorm.DB) error {
 		batchSize := 50 // 每批处理的大小
@@ -320,7 +365,7 @@ func (s *Storage) InsertOrUpdateSqlManage(sqlManageList []*SqlManage, sqlAuditRe
 			                       audit_plan_id          = VALUES(audit_plan_id),
 			                       audit_level            = VALUES(audit_level),
 			                       audit_results          = VALUES(audit_results),
-			                       fp_count 			   = VALUES(fp_count),
+			                       fp_count               = fp_count + VALUES(fp_count),
 			                       first_appear_timestamp = VALUES(first_appear_timestamp),
 			                       last_receive_timestamp = VALUES(last_receive_timestamp);`,
 				strings.Join(pattern, ", "))
@@ -381,12 +426,13 @@ func (s *Storage) BatchUpdateSqlManage(idList []*uint64, status *string, remark
 				return err
 			}
 
-			if len(userList) > 0 {
-				err := tx.Exec("DELETE FROM sql_manage_assignees WHERE sql_manage_id IN (?)", idList).Error
-				if err != nil {
-					return err
-				}
+			// the empty assignees clear the assignees of sql manage
+			err = tx.Exec("DELETE FROM sql_manage_assignees WHERE sql_manage_id IN (?)", idList).Error
+			if err != nil {
+				return err
+			}
 
+			if len(userList) > 0 {
 				pattern := make([]string, 0, len(userList))
 				args := make([]interface{}, 0)
 				for _, id := range idList {
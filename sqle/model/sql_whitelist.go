@@ -73,6 +73,12 @@ func (s *Storage) GetSqlWhitelistByProjectName(pageIndex, pageSize uint32, proje
 }
 
 
+func (s *Storage) GetSqlWhitelistsByProjectId(projectId uint) ([]*SqlWhitelist, error) {
+	sqlWhitelist := []*SqlWhitelist{}
+	err := s.db.Where("project_id = ?", projectId).Order("id ASC").Find(&sqlWhitelist).Error
+	return sqlWhitelist, errors.New(errors.ConnectStorageError, err)
+}
+
 func (s *Storage) GetSqlWhitelistByInstanceId(instanceId uint) ([]SqlWhitelist,  error) {
 	sqlWhitelist := []SqlWhitelist{}
 	err := s.db.Table("sql_whitelist").
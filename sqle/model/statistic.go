@@ -0,0 +1,106 @@
+package model
+
+import (
+	"time"
+
+	"github.com/actiontech/sqle/sqle/errors"
+
+	"github.com/jinzhu/gorm"
+)
+
+// StatisticFilter limits the global statistics to the workflows (or instances) of a project
+// and created within a time range, zero value means no limit.
+type StatisticFilter struct {
+	ProjectName string
+	StartTime   *time.Time
+	EndTime     *time.Time
+}
+
+func (f *StatisticFilter) apply(db *gorm.DB, table string) *gorm.DB {
+	if f == nil {
+		return db
+	}
+	if f.ProjectName != "" {
+		db = db.Where(table+".project_id IN (SELECT id FROM projects WHERE name = ? AND deleted_at IS NULL)", f.ProjectName)
+	}
+	if f.StartTime != nil {
+		db = db.Where(table+".created_at >= ?", f.StartTime)
+	}
+	if f.EndTime != nil {
+		db = db.Where(table+".created_at <= ?", f.EndTime)
+	}
+	return db
+}
+
+type WorkflowStatusCount struct {
+	Status string `json:"status"`
+	Count  int    `json:"count"`
+}
+
+func (s *Storage) GetWorkflowCountGroupByStatus(filter *StatisticFilter) ([]*WorkflowStatusCount, error) {
+	var counts []*WorkflowStatusCount
+	query := s.db.Model(&Workflow{}).Select("wr.status, count(*) as count").
+		Joins("LEFT JOIN workflow_records wr ON workflows.workflow_record_id = wr.id")
+	err := filter.apply(query, "workflows").Group("wr.status").Scan(&counts).Error
+	return counts, errors.ConnectStorageErrWrapper(err)
+}
+
+type WorkflowStepsFinishedTime struct {
+	WorkflowId uint      `json:"workflow_id"`
+	CreatedAt  time.Time `json:"created_at"`
+	FinishedAt time.Time `json:"finished_at"`
+}
+
+// GetWorkflowStepsFinishedTime 返回工单完成指定类型的所有步骤的时间, 即最后一个通过的该类型步骤的操作时间,
+// 跳过的步骤不计算在内, 存在未完成的该类型步骤的工单不返回
+func (s *Storage) GetWorkflowStepsFinishedTime(filter *StatisticFilter, stepType string) ([]*WorkflowStepsFinishedTime, error) {
+	var times []*WorkflowStepsFinishedTime
+	query := s.db.Model(&Workflow{}).
+		Select("workflows.id AS workflow_id, workflows.created_at, max(ws.operate_at) AS finished_at").
+		Joins("JOIN workflow_steps ws ON ws.workflow_record_id = workflows.workflow_record_id").
+		Joins("JOIN workflow_step_templates wst ON ws.workflow_step_template_id = wst.id").
+		Where("wst.type = ? AND ws.state = ? AND ws.operate_at IS NOT NULL", stepType, WorkflowStepStateApprove).
+		Where("NOT EXISTS (SELECT 1 FROM workflow_steps unfinished "+
+			"JOIN workflow_step_templates unfinished_template ON unfinished.workflow_step_template_id = unfinished_template.id "+
+			"WHERE unfinished.workflow_record_id = workflows.workflow_record_id AND unfinished_template.type = ? "+
+			"AND unfinished.state NOT IN (?))", stepType, []string{WorkflowStepStateApprove, WorkflowStepStateSkip})
+	err := filter.apply(query, "workflows").Group("workflows.id, workflows.created_at").Scan(&times).Error
+	return times, errors.ConnectStorageErrWrapper(err)
+}
+
+type WorkflowRejectedCountGroupByCreator struct {
+	Creator       string `json:"creator"`
+	TotalCount    uint   `json:"total_count"`
+	RejectedCount uint   `json:"rejected_count"`
+}
+
+// GetWorkflowRejectedCountGroupByCreator 返回各个用户提交的工单数及被驳回的工单数, 按驳回率降序排列
+func (s *Storage) GetWorkflowRejectedCountGroupByCreator(filter *StatisticFilter, limit uint) (
+	[]*WorkflowRejectedCountGroupByCreator, error) {
+
+	var counts []*WorkflowRejectedCountGroupByCreator
+	query := s.db.Model(&Workflow{}).
+		Select("users.login_name AS creator, count(*) AS total_count, "+
+			"sum(CASE WHEN wr.status = ? THEN 1 ELSE 0 END) AS rejected_count, "+
+			"sum(CASE WHEN wr.status = ? THEN 1 ELSE 0 END) / count(*) AS rejected_rate",
+			WorkflowStatusReject, WorkflowStatusReject).
+		Joins("LEFT JOIN workflow_records wr ON workflows.workflow_record_id = wr.id").
+		Joins("LEFT JOIN users ON workflows.create_user_id = users.id")
+	err := filter.apply(query, "workflows").
+		Group("users.login_name").
+		Order("rejected_rate DESC").
+		Limit(limit).
+		Scan(&counts).Error
+	return counts, errors.ConnectStorageErrWrapper(err)
+}
+
+// GetWorkflowCountGroupByInstanceType 返回各数据源类型相关的工单数, 一个工单包含多个同类型数据源时只计算一次
+func (s *Storage) GetWorkflowCountGroupByInstanceType(filter *StatisticFilter) ([]*TypeCount, error) {
+	var counts []*TypeCount
+	query := s.db.Model(&Workflow{}).Select("i.db_type, count(DISTINCT workflows.id) AS count").
+		Joins("LEFT JOIN workflow_instance_records wir ON workflows.workflow_record_id = wir.workflow_record_id").
+		Joins("LEFT JOIN instances i ON wir.instance_id = i.id").
+		Where("i.db_type IS NOT NULL")
+	err := filter.apply(query, "workflows").Group("i.db_type").Scan(&counts).Error
+	return counts, errors.ConnectStorageErrWrapper(err)
+}
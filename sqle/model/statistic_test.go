@@ -0,0 +1,72 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStorage_GetAllWorkflowCount(t *testing.T) {
+	mockDB, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	assert.NoError(t, err)
+	InitMockStorage(mockDB)
+
+	// 1. test without filter
+	mock.ExpectQuery("SELECT count(*) FROM `workflows`  WHERE `workflows`.`deleted_at` IS NULL").
+		WillReturnRows(sqlmock.NewRows([]string{"count(*)"}).AddRow(10))
+	count, err := GetStorage().GetAllWorkflowCount(&StatisticFilter{})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(10), count)
+
+	// 2. test with project and time range filter
+	start := time.Date(2023, 1, 1, 0, 0, 0, 0, time.Local)
+	end := time.Date(2023, 1, 31, 23, 59, 59, 0, time.Local)
+	mock.ExpectQuery("SELECT count(*) FROM `workflows`  WHERE `workflows`.`deleted_at` IS NULL AND ((workflows.project_id IN (SELECT id FROM projects WHERE name = ? AND deleted_at IS NULL)) AND (workflows.created_at >= ?) AND (workflows.created_at <= ?))").
+		WithArgs("project_1", start, end).
+		WillReturnRows(sqlmock.NewRows([]string{"count(*)"}).AddRow(3))
+	count, err = GetStorage().GetAllWorkflowCount(&StatisticFilter{ProjectName: "project_1", StartTime: &start, EndTime: &end})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), count)
+
+	mock.ExpectClose()
+	mockDB.Close()
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestStorage_GetWorkflowCountGroupByStatus(t *testing.T) {
+	mockDB, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	assert.NoError(t, err)
+	InitMockStorage(mockDB)
+
+	mock.ExpectQuery("SELECT wr.status, count(*) as count FROM `workflows` LEFT JOIN workflow_records wr ON workflows.workflow_record_id = wr.id WHERE `workflows`.`deleted_at` IS NULL AND ((workflows.project_id IN (SELECT id FROM projects WHERE name = ? AND deleted_at IS NULL))) GROUP BY wr.status").
+		WithArgs("project_1").
+		WillReturnRows(sqlmock.NewRows([]string{"status", "count"}).AddRow(WorkflowStatusReject, 2).AddRow(WorkflowStatusFinish, 5))
+	counts, err := GetStorage().GetWorkflowCountGroupByStatus(&StatisticFilter{ProjectName: "project_1"})
+	assert.NoError(t, err)
+	assert.Equal(t, []*WorkflowStatusCount{{Status: WorkflowStatusReject, Count: 2}, {Status: WorkflowStatusFinish, Count: 5}}, counts)
+
+	mock.ExpectClose()
+	mockDB.Close()
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestStorage_GetWorkflowStepsFinishedTime(t *testing.T) {
+	mockDB, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	assert.NoError(t, err)
+	InitMockStorage(mockDB)
+
+	createdAt := time.Date(2023, 1, 1, 10, 0, 0, 0, time.Local)
+	finishedAt := time.Date(2023, 1, 1, 11, 0, 0, 0, time.Local)
+	mock.ExpectQuery("SELECT workflows.id AS workflow_id, workflows.created_at, max(ws.operate_at) AS finished_at FROM `workflows` JOIN workflow_steps ws ON ws.workflow_record_id = workflows.workflow_record_id JOIN workflow_step_templates wst ON ws.workflow_step_template_id = wst.id WHERE `workflows`.`deleted_at` IS NULL AND ((wst.type = ? AND ws.state = ? AND ws.operate_at IS NOT NULL) AND (NOT EXISTS (SELECT 1 FROM workflow_steps unfinished JOIN workflow_step_templates unfinished_template ON unfinished.workflow_step_template_id = unfinished_template.id WHERE unfinished.workflow_record_id = workflows.workflow_record_id AND unfinished_template.type = ? AND unfinished.state NOT IN (?,?))) AND (workflows.project_id IN (SELECT id FROM projects WHERE name = ? AND deleted_at IS NULL))) GROUP BY workflows.id, workflows.created_at").
+		WithArgs(WorkflowStepTypeSQLReview, WorkflowStepStateApprove, WorkflowStepTypeSQLReview, WorkflowStepStateApprove, WorkflowStepStateSkip, "project_1").
+		WillReturnRows(sqlmock.NewRows([]string{"workflow_id", "created_at", "finished_at"}).AddRow(1, createdAt, finishedAt))
+	times, err := GetStorage().GetWorkflowStepsFinishedTime(&StatisticFilter{ProjectName: "project_1"}, WorkflowStepTypeSQLReview)
+	assert.NoError(t, err)
+	assert.Equal(t, []*WorkflowStepsFinishedTime{{WorkflowId: 1, CreatedAt: createdAt, FinishedAt: finishedAt}}, times)
+
+	mock.ExpectClose()
+	mockDB.Close()
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -1,11 +1,24 @@
 package model
 
 import (
+	"database/sql/driver"
+	"encoding/json"
 	"time"
+
+	"github.com/actiontech/sqle/sqle/errors"
+
+	"github.com/jinzhu/gorm"
+)
+
+const (
+	SyncInstanceStatusInitialized = "initialized"
+	SyncInstanceStatusSucceeded   = "succeeded"
+	SyncInstanceStatusFailed      = "failed"
 )
 
 type SyncInstanceTask struct {
 	Model
+	ProjectId      uint   `json:"project_id" gorm:"index"`
 	Source         string `json:"source" gorm:"not null"`
 	Version        string `json:"version" gorm:"not null"`
 	URL            string `json:"url" gorm:"not null"`
@@ -15,7 +28,116 @@ type SyncInstanceTask struct {
 	SyncInstanceInterval string     `json:"sync_instance_interval" gorm:"not null"`
 	LastSyncStatus       string     `json:"last_sync_status" gorm:"default:\"initialized\""`
 	LastSyncSuccessTime  *time.Time `json:"last_sync_success_time"`
+	// 源端未返回任何实例时是否允许删除该任务同步的全部实例
+	AllowEmptySource bool `json:"allow_empty_source" gorm:"not null;default:false"`
+	// 任务开始运行的时间, 任务运行结束后清空, 用于避免集群中的多个节点同时运行同一任务
+	RunningAt *time.Time `json:"running_at"`
 
 	// 关系表
 	RuleTemplate *RuleTemplate `gorm:"foreignKey:RuleTemplateID"`
+	Project      *Project      `gorm:"foreignkey:ProjectId"`
+}
+
+func (s *Storage) GetSyncInstanceTaskById(id uint) (*SyncInstanceTask, bool, error) {
+	task := &SyncInstanceTask{}
+	err := s.db.Preload("RuleTemplate").Preload("Project").Where("id = ?", id).First(task).Error
+	if err == gorm.ErrRecordNotFound {
+		return task, false, nil
+	}
+	return task, true, errors.New(errors.ConnectStorageError, err)
+}
+
+func (s *Storage) GetAllSyncInstanceTasks() ([]*SyncInstanceTask, error) {
+	tasks := []*SyncInstanceTask{}
+	err := s.db.Preload("RuleTemplate").Preload("Project").Order("id").Find(&tasks).Error
+	return tasks, errors.New(errors.ConnectStorageError, err)
+}
+
+func (s *Storage) UpdateSyncInstanceTaskById(id uint, attrs map[string]interface{}) error {
+	err := s.db.Model(&SyncInstanceTask{}).Where("id = ?", id).Updates(attrs).Error
+	return errors.New(errors.ConnectStorageError, err)
+}
+
+// SyncInstanceTaskRunningTimeout is much longer than a sync takes, the claim of the node which exits
+// while running the task expires after it.
+const SyncInstanceTaskRunningTimeout = 30 * time.Minute
+
+// ClaimSyncInstanceTask marks the task as running, it returns false if the task is running on any
+// node of cluster, e.g. the manual trigger on a follower and the scheduled run on the leader.
+func (s *Storage) ClaimSyncInstanceTask(id uint, now time.Time) (bool, error) {
+	db := s.db.Exec("UPDATE sync_instance_tasks SET running_at = ? WHERE id = ? AND deleted_at IS NULL AND (running_at IS NULL OR running_at < ?)",
+		now, id, now.Add(-SyncInstanceTaskRunningTimeout))
+	if db.Error != nil {
+		return false, errors.New(errors.ConnectStorageError, db.Error)
+	}
+	return db.RowsAffected == 1, nil
+}
+
+func (s *Storage) ReleaseSyncInstanceTask(id uint) error {
+	err := s.db.Exec("UPDATE sync_instance_tasks SET running_at = NULL WHERE id = ?", id).Error
+	return errors.New(errors.ConnectStorageError, err)
+}
+
+// DeleteSyncInstanceTask deletes the task and its records, the synchronized instances are kept
+// and managed manually since then.
+func (s *Storage) DeleteSyncInstanceTask(task *SyncInstanceTask) error {
+	return s.Tx(func(txDB *gorm.DB) error {
+		if err := txDB.Model(&Instance{}).Where("sync_instance_task_id = ?", task.ID).
+			Updates(map[string]interface{}{"sync_instance_task_id": 0, "source": InstanceSourceSQLE}).Error; err != nil {
+			return err
+		}
+		if err := txDB.Where("task_id = ?", task.ID).Delete(&SyncInstanceTaskRecord{}).Error; err != nil {
+			return err
+		}
+		return txDB.Delete(task).Error
+	})
+}
+
+// SyncInstanceConflict is the instance of source which is not synchronized, e.g. an instance with the
+// same name is created manually in the project.
+type SyncInstanceConflict struct {
+	InstanceName string `json:"instance_name"`
+	Reason       string `json:"reason"`
+}
+
+type SyncInstanceConflicts []SyncInstanceConflict
+
+func (c SyncInstanceConflicts) Value() (driver.Value, error) {
+	b, err := json.Marshal(c)
+	return string(b), err
+}
+
+func (c *SyncInstanceConflicts) Scan(input interface{}) error {
+	switch v := input.(type) {
+	case []byte:
+		return json.Unmarshal(v, c)
+	case string:
+		return json.Unmarshal([]byte(v), c)
+	}
+	return nil
+}
+
+// SyncInstanceTaskRecord is the result of a run of SyncInstanceTask.
+type SyncInstanceTaskRecord struct {
+	Model
+	TaskId       uint                  `json:"task_id" gorm:"index; not null"`
+	StartTime    time.Time             `json:"start_time"`
+	EndTime      time.Time             `json:"end_time"`
+	Status       string                `json:"status"`
+	CreatedNum   uint                  `json:"created_num"`
+	UpdatedNum   uint                  `json:"updated_num"`
+	DeletedNum   uint                  `json:"deleted_num"`
+	Conflicts    SyncInstanceConflicts `json:"conflicts" gorm:"type:json"`
+	ErrorMessage string                `json:"error_message" gorm:"type:text"`
+}
+
+func (s *Storage) GetSyncInstanceTaskRecords(taskId uint, limit, offset uint32) ([]*SyncInstanceTaskRecord, uint64, error) {
+	var count uint64
+	query := s.db.Model(&SyncInstanceTaskRecord{}).Where("task_id = ?", taskId)
+	if err := query.Count(&count).Error; err != nil {
+		return nil, 0, errors.New(errors.ConnectStorageError, err)
+	}
+	records := []*SyncInstanceTaskRecord{}
+	err := query.Order("id desc").Limit(limit).Offset(offset).Find(&records).Error
+	return records, count, errors.New(errors.ConnectStorageError, err)
 }
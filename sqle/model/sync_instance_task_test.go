@@ -0,0 +1,37 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStorage_ClaimSyncInstanceTask(t *testing.T) {
+	claimSQL := "UPDATE sync_instance_tasks SET running_at = ? WHERE id = ? AND deleted_at IS NULL AND (running_at IS NULL OR running_at < ?)"
+	now := time.Now()
+
+	mockDB, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	assert.NoError(t, err)
+	InitMockStorage(mockDB)
+
+	// 1. the task is claimed
+	mock.ExpectExec(claimSQL).WithArgs(now, 1, now.Add(-SyncInstanceTaskRunningTimeout)).WillReturnResult(sqlmock.NewResult(0, 1))
+	claimed, err := GetStorage().ClaimSyncInstanceTask(1, now)
+	assert.NoError(t, err)
+	assert.True(t, claimed)
+
+	// 2. the task is running on another node
+	mock.ExpectExec(claimSQL).WithArgs(now, 1, now.Add(-SyncInstanceTaskRunningTimeout)).WillReturnResult(sqlmock.NewResult(0, 0))
+	claimed, err = GetStorage().ClaimSyncInstanceTask(1, now)
+	assert.NoError(t, err)
+	assert.False(t, claimed)
+
+	mock.ExpectExec("UPDATE sync_instance_tasks SET running_at = NULL WHERE id = ?").WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 1))
+	assert.NoError(t, GetStorage().ReleaseSyncInstanceTask(1))
+
+	mock.ExpectClose()
+	mockDB.Close()
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
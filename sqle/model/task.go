@@ -9,6 +9,7 @@ import (
 	"strings"
 	"time"
 
+	driverV2 "github.com/actiontech/sqle/sqle/driver/v2"
 	"github.com/actiontech/sqle/sqle/errors"
 	"github.com/actiontech/sqle/sqle/utils"
 
@@ -22,6 +23,7 @@ const (
 	TaskStatusManuallyExecuted = "manually_executed"
 	TaskStatusExecuteSucceeded = "exec_succeeded"
 	TaskStatusExecuteFailed    = "exec_failed"
+	TaskStatusPaused           = "paused"
 	TaskStatusTerminating      = "terminating"
 	TaskStatusTerminateFail    = "terminate_failed"
 	TaskStatusTerminateSucc    = "terminate_succeeded"
@@ -96,6 +98,13 @@ const (
 	SQLExecuteStatusTerminateFailed  = "terminate_failed"
 )
 
+const (
+	SQLRehearseStatusInitialized = "initialized"
+	SQLRehearseStatusSucceeded   = "succeeded"
+	SQLRehearseStatusFailed      = "failed"
+	SQLRehearseStatusSkipped     = "skipped"
+)
+
 type BaseSQL struct {
 	Model
 	TaskId uint `json:"-" gorm:"index"`
@@ -138,6 +147,9 @@ type AuditResult struct {
 	Level    string `json:"level"`
 	Message  string `json:"message"`
 	RuleName string `json:"rule_name"`
+	// IndexAdvice is the advice of index optimization rule, it is used to collect
+	// the index advices of task or audit plan report.
+	IndexAdvice *driverV2.IndexAdvice `json:"index_advice,omitempty"`
 }
 
 type AuditResults []AuditResult
@@ -162,13 +174,17 @@ func (a *AuditResults) String() string {
 }
 
 func (a *AuditResults) Append(level, ruleName, message string) {
+	a.AppendIndexAdvice(level, ruleName, message, nil)
+}
+
+func (a *AuditResults) AppendIndexAdvice(level, ruleName, message string, advice *driverV2.IndexAdvice) {
 	for i := range *a {
 		ar := (*a)[i]
 		if ar.Level == level && ar.RuleName == ruleName && ar.Message == message {
 			return
 		}
 	}
-	*a = append(*a, AuditResult{Level: level, RuleName: ruleName, Message: message})
+	*a = append(*a, AuditResult{Level: level, RuleName: ruleName, Message: message, IndexAdvice: advice})
 }
 
 type ExecuteSQL struct {
@@ -180,6 +196,46 @@ type ExecuteSQL struct {
 	AuditFingerprint string `json:"audit_fingerprint" gorm:"index;type:char(32)"`
 	// AuditLevel has four level: error, warn, notice, normal.
 	AuditLevel string `json:"audit_level"`
+	// SQLType is the type of SQL parsed by plugin when auditing, e.g. ddl, dml.
+	SQLType string `json:"sql_type" gorm:"type:varchar(16)"`
+
+	// Rehearse* record the result of rehearsal, the SQL is executed in a transaction
+	// which is rolled back at last, so the affected rows are real but nothing is changed.
+	RehearseStatus     string `json:"rehearse_status" gorm:"default:\"initialized\""`
+	RehearseResult     string `json:"rehearse_result" gorm:"type:text"`
+	RehearseRowAffects int64  `json:"rehearse_row_affects"`
+	RehearseWarnings   string `json:"rehearse_warnings" gorm:"type:text"`
+	RehearseElapsedMs  int64  `json:"rehearse_elapsed_ms"`
+
+	// BatchProgress is the percentage of primary key range which has been executed when the
+	// DML is executed in batches, BatchChunks is the number of executed chunks.
+	BatchProgress float64 `json:"batch_progress"`
+	BatchChunks   int64   `json:"batch_chunks"`
+
+	// OnlineDDLProgress is the live status of gh-ost migration, it is nil if the SQL is not executed by gh-ost.
+	OnlineDDLProgress *OnlineDDLProgress `json:"online_ddl_progress" gorm:"type:json"`
+}
+
+type OnlineDDLProgress struct {
+	RowsCopied   int64   `json:"rows_copied"`
+	RowsEstimate int64   `json:"rows_estimate"`
+	ProgressPct  float64 `json:"progress_pct"`
+	// ETASeconds is -1 if it is unknown.
+	ETASeconds         int64  `json:"eta_seconds"`
+	LagMillis          int64  `json:"lag_millis"`
+	Throttled          bool   `json:"throttled"`
+	ThrottleReason     string `json:"throttle_reason"`
+	PostponingCutOver  bool   `json:"postponing_cut_over"`
+	CutOverPostponable bool   `json:"cut_over_postponable"`
+}
+
+func (p OnlineDDLProgress) Value() (driver.Value, error) {
+	b, err := json.Marshal(p)
+	return string(b), err
+}
+
+func (p *OnlineDDLProgress) Scan(input interface{}) error {
+	return json.Unmarshal(input.([]byte), p)
 }
 
 func (s ExecuteSQL) TableName() string {
@@ -371,6 +427,16 @@ func updateTaskStatusById(tx *gorm.DB, taskId uint, status string) error {
 	}).Error
 }
 
+// CompareAndUpdateTaskStatus updates the task status only if the current status is the expected one,
+// it returns false if the status has been changed by others.
+func (s *Storage) CompareAndUpdateTaskStatus(taskId uint, expected, status string) (bool, error) {
+	db := s.db.Model(&Task{}).Where("id = ? AND status = ?", taskId, expected).Update("status", status)
+	if db.Error != nil {
+		return false, errors.New(errors.ConnectStorageError, db.Error)
+	}
+	return db.RowsAffected > 0, nil
+}
+
 func (s *Storage) UpdateTaskStatusByIDs(taskIDs []uint, attrs ...interface{}) error {
 	err := s.db.Model(&Task{}).Where("id IN (?)", taskIDs).Update(attrs...).Error
 	return errors.ConnectStorageErrWrapper(err)
@@ -381,6 +447,12 @@ func updateExecuteSQLStatusByTaskId(tx *gorm.DB, taskId uint, status string) err
 	return tx.Exec(query, status, taskId).Error
 }
 
+func (s *Storage) UpdateExecuteSQLOnlineDDLProgress(executeSQLId uint, progress *OnlineDDLProgress) error {
+	err := s.db.Model(&ExecuteSQL{}).Where("id = ?", executeSQLId).
+		Update("online_ddl_progress", progress).Error
+	return errors.New(errors.ConnectStorageError, err)
+}
+
 func (s *Storage) UpdateExecuteSqlStatus(baseSQL *BaseSQL, status, result string) error {
 	attr := map[string]interface{}{}
 	if status != "" {
@@ -444,6 +516,18 @@ type TaskSQLDetail struct {
 	ExecResult   string         `json:"exec_result"`
 	ExecStatus   string         `json:"exec_status"`
 	RollbackSQL  sql.NullString `json:"rollback_sql"`
+
+	RehearseStatus     sql.NullString `json:"rehearse_status"`
+	RehearseResult     sql.NullString `json:"rehearse_result"`
+	RehearseRowAffects sql.NullInt64  `json:"rehearse_row_affects"`
+	RehearseWarnings   sql.NullString `json:"rehearse_warnings"`
+	RehearseElapsedMs  sql.NullInt64  `json:"rehearse_elapsed_ms"`
+
+	RowAffects    sql.NullInt64   `json:"row_affects"`
+	BatchProgress sql.NullFloat64 `json:"batch_progress"`
+	BatchChunks   sql.NullInt64   `json:"batch_chunks"`
+
+	OnlineDDLProgress *OnlineDDLProgress `json:"online_ddl_progress"`
 }
 
 func (t *TaskSQLDetail) GetAuditResults() string {
@@ -455,7 +539,9 @@ func (t *TaskSQLDetail) GetAuditResults() string {
 }
 
 var taskSQLsQueryTpl = `SELECT e_sql.number, e_sql.description, e_sql.content AS exec_sql, r_sql.content AS rollback_sql,
-e_sql.audit_results, e_sql.audit_level, e_sql.audit_status, e_sql.exec_result, e_sql.exec_status
+e_sql.audit_results, e_sql.audit_level, e_sql.audit_status, e_sql.exec_result, e_sql.exec_status,
+e_sql.rehearse_status, e_sql.rehearse_result, e_sql.rehearse_row_affects, e_sql.rehearse_warnings, e_sql.rehearse_elapsed_ms,
+e_sql.row_affects, e_sql.batch_progress, e_sql.batch_chunks, e_sql.online_ddl_progress
 
 {{- template "body" . -}}
 
@@ -568,22 +654,24 @@ func (s *Storage) GetTaskGroupByGroupId(groupId uint) (*TaskGroup, error) {
 }
 
 type SqlExecuteStatistic struct {
-	InstanceID       uint `json:"instance_id"`
-	AvgExecutionTime uint `json:"avg_execution_time"`
-	MaxExecutionTime uint `json:"max_execution_time"`
-	MinExecutionTime uint `json:"min_execution_time"`
+	InstanceName     string `json:"instance_name"`
+	AvgExecutionTime uint   `json:"avg_execution_time"`
+	MaxExecutionTime uint   `json:"max_execution_time"`
+	MinExecutionTime uint   `json:"min_execution_time"`
 }
 
-func (s *Storage) GetSqlAvgExecutionTimeStatistic(limit uint) ([]*SqlExecuteStatistic, error) {
+func (s *Storage) GetSqlAvgExecutionTimeStatistic(filter *StatisticFilter, limit uint) ([]*SqlExecuteStatistic, error) {
 	var sqlExecuteStatistics []*SqlExecuteStatistic
-	err := s.db.Model(&Workflow{}).Select("t.instance_id,"+
+	query := s.db.Model(&Workflow{}).Select("i.name as instance_name,"+
 		"round(avg(timestampdiff(second, t.exec_start_at, t.exec_end_at))) avg_execution_time,"+
 		"max(timestampdiff(second, t.exec_start_at, t.exec_end_at)) max_execution_time,"+
 		"min(timestampdiff(second, t.exec_start_at, t.exec_end_at)) min_execution_time").
 		Joins("left join workflow_records wr on workflows.workflow_record_id = wr.id").
 		Joins("left join workflow_instance_records wir on wr.id = wir.workflow_record_id").
 		Joins("left join tasks t on wir.task_id = t.id").
-		Where("t.status = ?", TaskStatusExecuteSucceeded).
+		Joins("left join instances i on t.instance_id = i.id").
+		Where("t.status = ?", TaskStatusExecuteSucceeded)
+	err := filter.apply(query, "workflows").
 		Group("t.instance_id").Order("avg_execution_time desc").Limit(limit).
 		Scan(&sqlExecuteStatistics).Error
 	if err != nil {
@@ -599,17 +687,18 @@ type SqlExecutionCount struct {
 }
 
 // GetSqlExecutionFailCount 获取sql上线失败统计
-func (s *Storage) GetSqlExecutionFailCount() ([]SqlExecutionCount, error) {
+func (s *Storage) GetSqlExecutionFailCount(filter *StatisticFilter) ([]SqlExecutionCount, error) {
 	var sqlExecutionFailCount []SqlExecutionCount
 
-	err := s.db.Model(&Workflow{}).Select("i.name as instance_name, count(*) as count").
+	query := s.db.Model(&Workflow{}).Select("i.name as instance_name, count(*) as count").
 		Joins("left join workflow_records wr on workflows.workflow_record_id = wr.id").
 		Joins("left join workflow_instance_records wir on wr.id = wir.workflow_record_id").
 		Joins("left join tasks t on wir.task_id = t.id").
 		Joins("left join instances i on t.instance_id = i.id").
 		Where("t.status = ?", TaskStatusExecuteFailed).
 		Where("t.exec_start_at is not null").
-		Where("t.exec_end_at is not null").
+		Where("t.exec_end_at is not null")
+	err := filter.apply(query, "workflows").
 		Group("t.instance_id").
 		Scan(&sqlExecutionFailCount).Error
 	if err != nil {
@@ -621,16 +710,17 @@ func (s *Storage) GetSqlExecutionFailCount() ([]SqlExecutionCount, error) {
 
 // GetSqlExecutionTotalCount 获取sql上线总数统计
 // 上线总数(根据数据源划分)是指：正在上线,上线成功,上线失败 task的总数
-func (s *Storage) GetSqlExecutionTotalCount() ([]SqlExecutionCount, error) {
+func (s *Storage) GetSqlExecutionTotalCount(filter *StatisticFilter) ([]SqlExecutionCount, error) {
 	var sqlExecutionTotalCount []SqlExecutionCount
 
-	err := s.db.Model(&Workflow{}).Select("i.name as instance_name, count(*) as count").
+	query := s.db.Model(&Workflow{}).Select("i.name as instance_name, count(*) as count").
 		Joins("left join workflow_records wr on workflows.workflow_record_id = wr.id").
 		Joins("left join workflow_instance_records wir on wr.id = wir.workflow_record_id").
 		Joins("left join tasks t on wir.task_id = t.id").
 		Joins("left join instances i on t.instance_id = i.id").
 		Where("t.status not in (?)", []string{TaskStatusInit, TaskStatusAudited}).
-		Where("t.exec_start_at is not null").
+		Where("t.exec_start_at is not null")
+	err := filter.apply(query, "workflows").
 		Group("t.instance_id").
 		Scan(&sqlExecutionTotalCount).Error
 	if err != nil {
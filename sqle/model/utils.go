@@ -130,6 +130,7 @@ var autoMigrateList = []interface{}{
 	&WorkflowRecord{},
 	&WorkflowStepTemplate{},
 	&WorkflowStep{},
+	&WorkflowStepApproval{},
 	&WorkflowTemplate{},
 	&Workflow{},
 	&SqlQueryExecutionSql{},
@@ -146,6 +147,7 @@ var autoMigrateList = []interface{}{
 	&DingTalkInstance{},
 	&FeishuInstance{},
 	&SyncInstanceTask{},
+	&SyncInstanceTaskRecord{},
 	&OperationRecord{},
 	&PersonaliseConfig{},
 	&LogoConfig{},
@@ -156,6 +158,9 @@ var autoMigrateList = []interface{}{
 	&RuleKnowledge{},
 	&SqlManage{},
 	&SqlManageSqlAuditRecord{},
+	&ClusterLeader{},
+	&ClusterNodeInfo{},
+	&OnlineDDLMigration{},
 }
 
 func (s *Storage) AutoMigrate() error {
@@ -37,10 +37,97 @@ type WorkflowStepTemplate struct {
 	Desc                 string
 	ApprovedByAuthorized sql.NullBool `gorm:"column:approved_by_authorized"`
 	ExecuteByAuthorized  sql.NullBool `gorm:"column:execute_by_authorized"`
+	// Condition is empty for the step which is always required, the last step (sql_execute) has no condition.
+	Condition WorkflowStepCondition `gorm:"column:step_condition; type:text"`
+	// ApproveMode and ApproveQuorum are only used by sql_review step, see WorkflowStepApproveModeAnyOf.
+	ApproveMode   string `gorm:"column:approve_mode; type:varchar(16)"`
+	ApproveQuorum uint   `gorm:"column:approve_quorum"`
 
 	Users []*User `gorm:"many2many:workflow_step_template_user"`
 }
 
+const (
+	maxWorkflowSteps         = 5
+	maxWorkflowStepAssignees = 3
+	// maxWorkflowStepGroupAssignees is the assignee limit of the step approved by all_of or
+	// quorum mode, it is larger than the limit of any_of mode since the step is approved by
+	// a group of assignees, e.g. the DBAs.
+	maxWorkflowStepGroupAssignees = 10
+)
+
+// ValidateWorkflowStepTemplates checks the steps of workflow template before they are
+// saved, the instances of step condition should be checked by the caller.
+func ValidateWorkflowStepTemplates(steps []*WorkflowStepTemplate) error {
+	if len(steps) == 0 {
+		return fmt.Errorf("workflow steps cannot be empty")
+	}
+	if len(steps) > maxWorkflowSteps {
+		return fmt.Errorf("workflow steps length must be less than %d", maxWorkflowSteps+1)
+	}
+	for i, step := range steps {
+		if step.Typ != WorkflowStepTypeSQLReview && step.Typ != WorkflowStepTypeSQLExecute {
+			return fmt.Errorf("workflow step type %s is invalid", step.Typ)
+		}
+		isLastStep := i == len(steps)-1
+		if isLastStep && step.Typ != WorkflowStepTypeSQLExecute {
+			return fmt.Errorf("the last workflow step type must be sql_execute")
+		}
+		if !isLastStep && step.Typ == WorkflowStepTypeSQLExecute {
+			return fmt.Errorf("workflow step type sql_execute just be used in last step")
+		}
+		if len(step.Users) == 0 && !step.ApprovedByAuthorized.Bool && !step.ExecuteByAuthorized.Bool {
+			return fmt.Errorf("the assignee is empty for step %s", step.Desc)
+		}
+		if err := step.validateApproveMode(isLastStep); err != nil {
+			return err
+		}
+		if step.Condition.IsEmpty() {
+			continue
+		}
+		if isLastStep {
+			return fmt.Errorf("the last workflow step can not be skipped, it can not have condition")
+		}
+		if err := step.Condition.Validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (st *WorkflowStepTemplate) validateApproveMode(isLastStep bool) error {
+	switch st.ApproveMode {
+	case "", WorkflowStepApproveModeAnyOf:
+		if len(st.Users) > maxWorkflowStepAssignees {
+			return fmt.Errorf("the assignee for step cannot be more than %d", maxWorkflowStepAssignees)
+		}
+		return nil
+	case WorkflowStepApproveModeAllOf, WorkflowStepApproveModeQuorum:
+	default:
+		return fmt.Errorf("approve mode %s is invalid", st.ApproveMode)
+	}
+	if isLastStep {
+		return fmt.Errorf("approve mode %s can not be used by the step of sql_execute", st.ApproveMode)
+	}
+	if len(st.Users) > maxWorkflowStepGroupAssignees {
+		return fmt.Errorf("the assignee for step approved by %s cannot be more than %d", st.ApproveMode, maxWorkflowStepGroupAssignees)
+	}
+	// the assignees approved by authorized are decided when the workflow is created, they
+	// are unbounded and can not be required to approve all.
+	if st.ApproveMode == WorkflowStepApproveModeAllOf && st.ApprovedByAuthorized.Bool {
+		return fmt.Errorf("approve mode %s can not be used by the step approved by authorized", st.ApproveMode)
+	}
+	if st.ApproveMode == WorkflowStepApproveModeQuorum {
+		if st.ApproveQuorum < 1 {
+			return fmt.Errorf("approve quorum of step %s must be greater than 0", st.Desc)
+		}
+		// the assignees approved by authorized are decided when the workflow is created
+		if !st.ApprovedByAuthorized.Bool && int(st.ApproveQuorum) > len(st.Users) {
+			return fmt.Errorf("approve quorum of step %s cannot be more than the count of assignees", st.Desc)
+		}
+	}
+	return nil
+}
+
 func (s *Storage) GetWorkflowTemplateByName(name string) (*WorkflowTemplate, bool, error) {
 	workflowTemplate := &WorkflowTemplate{}
 	err := s.db.Where("name = ?", name).First(workflowTemplate).Error
@@ -90,8 +177,8 @@ func saveWorkflowTemplate(template *WorkflowTemplate, tx *sql.Tx) (templateId in
 	}
 	template.ID = uint(templateId)
 	for _, step := range template.Steps {
-		result, err = tx.Exec("INSERT INTO workflow_step_templates (step_number, workflow_template_id, type, `desc`, approved_by_authorized,execute_by_authorized) values (?,?,?,?,?,?)",
-			step.Number, templateId, step.Typ, step.Desc, step.ApprovedByAuthorized, step.ExecuteByAuthorized)
+		result, err = tx.Exec("INSERT INTO workflow_step_templates (step_number, workflow_template_id, type, `desc`, approved_by_authorized,execute_by_authorized,step_condition,approve_mode,approve_quorum) values (?,?,?,?,?,?,?,?,?)",
+			step.Number, templateId, step.Typ, step.Desc, step.ApprovedByAuthorized, step.ExecuteByAuthorized, step.Condition, step.ApproveMode, step.ApproveQuorum)
 		if err != nil {
 			return 0, err
 		}
@@ -119,8 +206,8 @@ func (s *Storage) UpdateWorkflowTemplateSteps(templateId uint, steps []*Workflow
 			return err
 		}
 		for _, step := range steps {
-			result, err := tx.Exec("INSERT INTO workflow_step_templates (step_number, workflow_template_id, type, `desc`, approved_by_authorized,execute_by_authorized) values (?,?,?,?,?,?)",
-				step.Number, templateId, step.Typ, step.Desc, step.ApprovedByAuthorized, step.ExecuteByAuthorized)
+			result, err := tx.Exec("INSERT INTO workflow_step_templates (step_number, workflow_template_id, type, `desc`, approved_by_authorized,execute_by_authorized,step_condition,approve_mode,approve_quorum) values (?,?,?,?,?,?,?,?,?)",
+				step.Number, templateId, step.Typ, step.Desc, step.ApprovedByAuthorized, step.ExecuteByAuthorized, step.Condition, step.ApproveMode, step.ApproveQuorum)
 			if err != nil {
 				return err
 			}
@@ -210,6 +297,12 @@ type WorkflowInstanceRecord struct {
 	InstanceId       uint
 	ScheduledAt      *time.Time
 	ScheduleUserId   uint
+	// 排队等待在数据源的下个运维时间窗口内自动上线，同一数据源的任务按优先级和排队时间依次上线
+	MaintenanceQueuedAt   *time.Time
+	MaintenancePriority   int
+	MaintenanceRolloverAt *time.Time
+	// 上线阶段，工单的任务按阶段依次上线，前一阶段全部上线成功后才会上线下一阶段，为0表示不分阶段
+	ExecuteStage uint
 	// 用于区分工单处于上线步骤时，某个数据源是否已上线，因为数据源可以分批上线
 	IsSQLExecuted   bool
 	ExecutionUserId uint
@@ -249,6 +342,9 @@ const (
 	WorkflowStepStateInit    = "initialized"
 	WorkflowStepStateApprove = "approved"
 	WorkflowStepStateReject  = "rejected"
+	// WorkflowStepStateSkip is the state of the step whose condition is not matched by
+	// the tasks of workflow, it is evaluated again when the tasks are updated.
+	WorkflowStepStateSkip = "skipped"
 )
 
 type WorkflowStep struct {
@@ -261,9 +357,10 @@ type WorkflowStep struct {
 	State                  string `gorm:"default:\"initialized\""`
 	Reason                 string
 
-	Assignees     []*User               `gorm:"many2many:workflow_step_user"`
-	Template      *WorkflowStepTemplate `gorm:"foreignkey:WorkflowStepTemplateId"`
-	OperationUser *User                 `gorm:"foreignkey:OperationUserId"`
+	Assignees     []*User                 `gorm:"many2many:workflow_step_user"`
+	Template      *WorkflowStepTemplate   `gorm:"foreignkey:WorkflowStepTemplateId"`
+	OperationUser *User                   `gorm:"foreignkey:OperationUserId"`
+	Approvals     []*WorkflowStepApproval `gorm:"-"`
 }
 
 func (ws *WorkflowStep) OperationTime() string {
@@ -280,13 +377,15 @@ func (ws *WorkflowStep) OperationUserName() string {
 	return ws.OperationUser.Name
 }
 
-func generateWorkflowStepByTemplate(stepsTemplate []*WorkflowStepTemplate, allInspector []*User, allExecutor []*User) []*WorkflowStep {
+func generateWorkflowStepByTemplate(stepsTemplate []*WorkflowStepTemplate, allInspector []*User, allExecutor []*User,
+	target *WorkflowStepConditionTarget) []*WorkflowStep {
 	steps := make([]*WorkflowStep, 0, len(stepsTemplate))
 	for i, st := range stepsTemplate {
 
 		step := &WorkflowStep{
 			WorkflowStepTemplateId: st.ID,
 			Assignees:              st.Users,
+			State:                  stepStateByCondition(st, target),
 		}
 		if st.ApprovedByAuthorized.Bool {
 			step.Assignees = allInspector
@@ -300,18 +399,50 @@ func generateWorkflowStepByTemplate(stepsTemplate []*WorkflowStepTemplate, allIn
 	return steps
 }
 
-func (w *Workflow) cloneWorkflowStep() []*WorkflowStep {
+// cloneWorkflowStep clones the steps of current record for the new record, the conditions
+// of steps are evaluated again since the tasks of workflow are changed.
+func (w *Workflow) cloneWorkflowStep(target *WorkflowStepConditionTarget) []*WorkflowStep {
 	steps := make([]*WorkflowStep, 0, len(w.Record.Steps))
 	for _, step := range w.Record.Steps {
 		steps = append(steps, &WorkflowStep{
 			WorkflowStepTemplateId: step.Template.ID,
 			WorkflowId:             w.ID,
 			Assignees:              step.Assignees,
+			State:                  stepStateByCondition(step.Template, target),
 		})
 	}
 	return steps
 }
 
+func stepStateByCondition(stepTemplate *WorkflowStepTemplate, target *WorkflowStepConditionTarget) string {
+	if stepTemplate.Condition.Match(target) {
+		return WorkflowStepStateInit
+	}
+	return WorkflowStepStateSkip
+}
+
+// hasRequiredAuditStep checks whether any audit step, which is the step before the last
+// step, is not skipped.
+func hasRequiredAuditStep(stepTemplates []*WorkflowStepTemplate, target *WorkflowStepConditionTarget) bool {
+	for i := 0; i < len(stepTemplates)-1; i++ {
+		if stepTemplates[i].Condition.Match(target) {
+			return true
+		}
+	}
+	return false
+}
+
+// firstRequiredStep returns the first step which is not skipped, the last step is never
+// skipped so it is nil only if steps is empty.
+func firstRequiredStep(steps []*WorkflowStep) *WorkflowStep {
+	for _, step := range steps {
+		if step.State != WorkflowStepStateSkip {
+			return step
+		}
+	}
+	return nil
+}
+
 func (w *Workflow) CreateUserName() string {
 	if w.CreateUser != nil {
 		return w.CreateUser.Name
@@ -331,6 +462,7 @@ func (w *Workflow) CurrentAssigneeUser() []*User {
 	return currentStep.Assignees
 }
 
+// NextStep returns the step after current step, the skipped steps are ignored.
 func (w *Workflow) NextStep() *WorkflowStep {
 	var nextIndex int
 	for i, step := range w.Record.Steps {
@@ -340,7 +472,7 @@ func (w *Workflow) NextStep() *WorkflowStep {
 		}
 	}
 	if nextIndex <= len(w.Record.Steps)-1 {
-		return w.Record.Steps[nextIndex]
+		return firstRequiredStep(w.Record.Steps[nextIndex:])
 	}
 	return nil
 }
@@ -413,14 +545,19 @@ func (s *Storage) CreateWorkflowV2(subject, workflowId, desc string, user *User,
 		}
 	}
 
+	target, err := s.GetWorkflowStepConditionTarget(tasks)
+	if err != nil {
+		return err
+	}
+
 	tx := s.db.Begin()
 
 	record := new(WorkflowRecord)
-	if len(stepTemplates) == 1 {
+	if !hasRequiredAuditStep(stepTemplates, target) {
 		record.Status = WorkflowStatusWaitForExecution
 	}
 
-	err := tx.Save(record).Error
+	err = tx.Save(record).Error
 	if err != nil {
 		tx.Rollback()
 		return errors.New(errors.ConnectStorageError, err)
@@ -505,7 +642,7 @@ func (s *Storage) CreateWorkflowV2(subject, workflowId, desc string, user *User,
 	}
 
 	{
-		steps := generateWorkflowStepByTemplate(stepTemplates, canOptUsers, canExecUsers)
+		steps := generateWorkflowStepByTemplate(stepTemplates, canOptUsers, canExecUsers, target)
 
 		for _, step := range steps {
 			currentStep := step
@@ -525,8 +662,8 @@ func (s *Storage) CreateWorkflowV2(subject, workflowId, desc string, user *User,
 			}
 		}
 
-		if len(steps) > 0 {
-			err = tx.Model(record).Update("current_workflow_step_id", steps[0].ID).Error
+		if firstStep := firstRequiredStep(steps); firstStep != nil {
+			err = tx.Model(record).Update("current_workflow_step_id", firstStep.ID).Error
 			if err != nil {
 				tx.Rollback()
 				return errors.New(errors.ConnectStorageError, err)
@@ -572,13 +709,18 @@ func (s *Storage) UpdateWorkflowRecord(w *Workflow, tasks []*Task) error {
 		InstanceRecords: instanceRecords,
 	}
 
-	steps := w.cloneWorkflowStep()
-	if len(steps) == 1 {
+	target, err := s.GetWorkflowStepConditionTarget(tasks)
+	if err != nil {
+		return err
+	}
+	steps := w.cloneWorkflowStep(target)
+	firstStep := firstRequiredStep(steps)
+	if len(steps) > 0 && firstStep == steps[len(steps)-1] {
 		record.Status = WorkflowStatusWaitForExecution
 	}
 
 	tx := s.db.Begin()
-	err := tx.Save(record).Error
+	err = tx.Save(record).Error
 	if err != nil {
 		tx.Rollback()
 		return errors.New(errors.ConnectStorageError, err)
@@ -600,8 +742,8 @@ func (s *Storage) UpdateWorkflowRecord(w *Workflow, tasks []*Task) error {
 			return errors.New(errors.ConnectStorageError, err)
 		}
 	}
-	if len(steps) > 0 {
-		err = tx.Model(record).Update("current_workflow_step_id", steps[0].ID).Error
+	if firstStep != nil {
+		err = tx.Model(record).Update("current_workflow_step_id", firstStep.ID).Error
 		if err != nil {
 			tx.Rollback()
 			return errors.New(errors.ConnectStorageError, err)
@@ -632,9 +774,12 @@ func (s *Storage) UpdateWorkflowStatus(w *Workflow) error {
 	})
 }
 
-// UpdateWorkflowStep, 改变工单步骤状态，并且会更新工单状态，用于审批通过和驳回工单
-func (s *Storage) UpdateWorkflowStep(w *Workflow, operateStep *WorkflowStep) error {
+// UpdateWorkflowStep, 改变工单步骤状态，并且会更新工单状态，用于驳回工单，同时记录驳回人的审批记录
+func (s *Storage) UpdateWorkflowStep(w *Workflow, operateStep *WorkflowStep, approval *WorkflowStepApproval) error {
 	return s.Tx(func(tx *gorm.DB) error {
+		if _, err := saveWorkflowStepApproval(tx, approval); err != nil {
+			return err
+		}
 		if err := updateWorkflowStatus(tx, w); err != nil {
 			return err
 		}
@@ -696,6 +841,63 @@ func updateWorkflowInstanceRecord(tx *gorm.DB, needExecInstanceRecords []*Workfl
 	return nil
 }
 
+var errInstanceRecordClaimed = fmt.Errorf("workflow instance record has been claimed")
+
+// ClaimInstanceRecordsForExecution marks the tasks as executed by the user before they are
+// launched automatically, so that the tasks are launched once even if several nodes launch
+// them at the same time. It returns false and claims nothing if any of the tasks has been
+// claimed by others.
+func (s *Storage) ClaimInstanceRecordsForExecution(records []*WorkflowInstanceRecord, userId uint) (bool, error) {
+	return s.claimInstanceRecords(func(tx *gorm.DB) (bool, error) {
+		return claimInstanceRecordsForExecution(tx, records, userId)
+	})
+}
+
+// claimInstanceRecords runs the conditional updates of claim in a transaction, the
+// transaction is rolled back if any of the updates affects no row.
+func (s *Storage) claimInstanceRecords(fn func(tx *gorm.DB) (bool, error)) (bool, error) {
+	claimed := true
+	err := s.Tx(func(tx *gorm.DB) error {
+		ok, err := fn(tx)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			claimed = false
+			return errInstanceRecordClaimed
+		}
+		return nil
+	})
+	if !claimed {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+func claimInstanceRecordsForExecution(tx *gorm.DB, records []*WorkflowInstanceRecord, userId uint) (bool, error) {
+	for _, ir := range records {
+		db := tx.Exec("UPDATE workflow_instance_records SET is_sql_executed = true, execution_user_id = ? WHERE id = ? AND is_sql_executed = false",
+			userId, ir.ID)
+		if db.Error != nil {
+			return false, db.Error
+		}
+		if db.RowsAffected != 1 {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// ReleaseInstanceRecordsClaim reverts the claim of tasks which fail to be launched.
+func (s *Storage) ReleaseInstanceRecordsClaim(records []*WorkflowInstanceRecord) error {
+	ids := make([]uint, 0, len(records))
+	for _, ir := range records {
+		ids = append(ids, ir.ID)
+	}
+	err := s.db.Exec("UPDATE workflow_instance_records SET is_sql_executed = false, execution_user_id = 0 WHERE id IN (?)", ids).Error
+	return errors.New(errors.ConnectStorageError, err)
+}
+
 func (s *Storage) BatchUpdateWorkflowStatus(ws []*Workflow) error {
 	return s.Tx(func(tx *gorm.DB) error {
 		for _, w := range ws {
@@ -736,8 +938,11 @@ func (s *Storage) UpdateWorkflowRecordByID(id uint, workFlow map[string]interfac
 
 func (s *Storage) UpdateInstanceRecordSchedule(ir *WorkflowInstanceRecord, userId uint, scheduleTime *time.Time) error {
 	err := s.db.Model(&WorkflowInstanceRecord{}).Where("id = ?", ir.ID).Update(map[string]interface{}{
-		"scheduled_at":     scheduleTime,
-		"schedule_user_id": userId,
+		"scheduled_at":            scheduleTime,
+		"schedule_user_id":        userId,
+		"maintenance_queued_at":   nil,
+		"maintenance_priority":    0,
+		"maintenance_rollover_at": nil,
 	}).Error
 	return errors.New(errors.ConnectStorageError, err)
 }
@@ -766,6 +971,9 @@ func (s *Storage) getWorkflowStepsByRecordIds(ids []uint) ([]*WorkflowStep, erro
 			}
 		}
 	}
+	if err := s.fillWorkflowStepApprovals(steps); err != nil {
+		return nil, err
+	}
 	return steps, nil
 }
 
@@ -862,6 +1070,10 @@ func (s *Storage) GetWorkflowExportById(id string) (*Workflow, bool, error) {
 		if err != nil {
 			return nil, false, errors.New(errors.ConnectStorageError, err)
 		}
+		err = s.db.Model(&RollbackSQL{}).Where("task_id = ?", instanceRecord.Task.ID).Find(&instanceRecord.Task.RollbackSQLs).Error
+		if err != nil {
+			return nil, false, errors.New(errors.ConnectStorageError, err)
+		}
 	}
 
 	w.Record.InstanceRecords = instanceRecordList
@@ -869,12 +1081,24 @@ func (s *Storage) GetWorkflowExportById(id string) (*Workflow, bool, error) {
 	steps := make([]*WorkflowStep, 0)
 	err = s.db.Where("workflow_record_id = ?", w.Record.ID).
 		Preload("OperationUser", UnScopedFunc).
+		Preload("Template").
+		Order("id ASC").
 		Find(&steps).Error
 	if err != nil {
 		return nil, false, errors.New(errors.ConnectStorageError, err)
 	}
+	if err := s.fillWorkflowStepApprovals(steps); err != nil {
+		return nil, false, err
+	}
 	w.Record.Steps = steps
 
+	// records before the workflow was updated after being rejected, only operated steps are kept.
+	history, err := s.GetWorkflowHistoryById(id)
+	if err != nil {
+		return nil, false, err
+	}
+	w.RecordHistory = history
+
 	return w, true, nil
 }
 
@@ -1115,62 +1339,6 @@ w.id = ?`
 	return instances, err
 }
 
-// GetWorkFlowStepIdsHasAudit 返回走完所有审核流程的workflow_steps的id
-// 返回以workflow_record_id为分组的倒数第二条记录的workflow_steps.id
-// 如果存在多个工单审核流程，workflow_record_id为分组的倒数第二条记录仍然是判断审核流程是否结束的依据
-// 如果不存在工单审核流程，LIMIT 1 offset 1 会将workflow过滤掉
-// 每个workflow_record_id对应一个workflows表中的一条记录，返回的id数组可以作为工单数量统计的依据
-func (s *Storage) GetWorkFlowStepIdsHasAudit() ([]uint, error) {
-	workFlowStepsByIndexAndState, err := s.GetWorkFlowReverseStepsByIndexAndState(1, WorkflowStepStateApprove)
-	if err != nil {
-		return nil, errors.ConnectStorageErrWrapper(err)
-	}
-
-	ids := make([]uint, 0)
-	for _, workflowStep := range workFlowStepsByIndexAndState {
-		ids = append(ids, workflowStep.ID)
-	}
-
-	return ids, nil
-}
-
-func (s *Storage) GetDurationMinHasAudit(ids []uint) (int, error) {
-	type minStruct struct {
-		Min int `json:"min"`
-	}
-
-	var result minStruct
-	err := s.db.Model(&Workflow{}).
-		Select("sum(timestampdiff(minute, workflows.created_at, workflow_steps.operate_at)) as min").
-		Joins("LEFT JOIN workflow_steps ON workflow_steps.workflow_record_id = workflows.workflow_record_id").
-		Where("workflow_steps.id IN (?)", ids).Scan(&result).Error
-
-	return result.Min, errors.ConnectStorageErrWrapper(err)
-}
-
-// WorkFlowStepsBO BO是business object的缩写，表示业务对象
-type WorkFlowStepsBO struct {
-	ID         uint
-	OperateAt  *time.Time
-	WorkflowId uint
-}
-
-// GetWorkFlowReverseStepsByIndexAndState 返回以workflow_id为分组的倒数第index个记录
-func (s *Storage) GetWorkFlowReverseStepsByIndexAndState(index int, state string) ([]*WorkFlowStepsBO, error) {
-	query := fmt.Sprintf(`SELECT id,operate_at,workflow_id
-FROM workflow_steps a
-WHERE a.id =
-      (SELECT id
-       FROM workflow_steps
-       WHERE workflow_id = a.workflow_id
-       ORDER BY id desc
-       limit 1 offset %d)
-  and a.state = '%s';`, index, state)
-
-	workflowStepsBO := make([]*WorkFlowStepsBO, 0)
-	return workflowStepsBO, s.db.Raw(query).Scan(&workflowStepsBO).Error
-}
-
 func (s *Storage) GetWorkflowCountByStepType(stepTypes []string) (int, error) {
 	if len(stepTypes) == 0 {
 		return 0, nil
@@ -1217,13 +1385,13 @@ func (s *Storage) HasNotEndWorkflowByProjectName(projectName string) (bool, erro
 // GetApprovedWorkflowCount
 // 返回审核通过的工单数（工单状态是 待上线,正在上线,上线成功,上线失败 中任意一个表示工单通过审核）
 // 工单状态是 待审核,已驳回,已关闭 中任意一个表示工单未通过审核
-func (s *Storage) GetApprovedWorkflowCount() (count int, err error) {
+func (s *Storage) GetApprovedWorkflowCount(filter *StatisticFilter) (count int64, err error) {
 	notPassAuditStatus := []string{WorkflowStatusWaitForAudit, WorkflowStatusReject, WorkflowStatusCancel}
 
-	err = s.db.Model(&Workflow{}).
+	query := s.db.Model(&Workflow{}).
 		Joins("left join workflow_records wr on workflows.workflow_record_id = wr.id").
-		Where("wr.status not in (?)", notPassAuditStatus).
-		Count(&count).Error
+		Where("wr.status not in (?)", notPassAuditStatus)
+	err = filter.apply(query, "workflows").Count(&count).Error
 	if err != nil {
 		return 0, errors.ConnectStorageErrWrapper(err)
 	}
@@ -1231,9 +1399,10 @@ func (s *Storage) GetApprovedWorkflowCount() (count int, err error) {
 	return count, nil
 }
 
-func (s *Storage) GetAllWorkflowCount() (int, error) {
-	var count int
-	return count, errors.New(errors.ConnectStorageError, s.db.Model(&Workflow{}).Count(&count).Error)
+func (s *Storage) GetAllWorkflowCount(filter *StatisticFilter) (int64, error) {
+	var count int64
+	err := filter.apply(s.db.Model(&Workflow{}), "workflows").Count(&count).Error
+	return count, errors.New(errors.ConnectStorageError, err)
 }
 
 func (s *Storage) GetWorkFlowCountBetweenStartTimeAndEndTime(startTime, endTime time.Time) (int64, error) {
@@ -1246,12 +1415,15 @@ type DailyWorkflowCount struct {
 	Count int       `json:"count"`
 }
 
-func (s *Storage) GetWorkflowDailyCountBetweenStartTimeAndEndTime(startTime, endTime time.Time) ([]*DailyWorkflowCount, error) {
+// GetWorkflowDailyCountBetweenStartTimeAndEndTime returns the count of workflows created each day
+// within the time range of filter.
+func (s *Storage) GetWorkflowDailyCountBetweenStartTimeAndEndTime(filter *StatisticFilter) ([]*DailyWorkflowCount, error) {
 	var counts []*DailyWorkflowCount
-	err := s.db.Table("workflows").
-		Select("cast(created_at as date) as date, count(*) as count").
-		Where("created_at BETWEEN ? and ?", startTime, endTime).
-		Group("cast(created_at as date)").Find(&counts).Error
+	query := s.db.Table("workflows").
+		Select("cast(workflows.created_at as date) as date, count(*) as count").
+		Where("workflows.deleted_at IS NULL")
+	err := filter.apply(query, "workflows").
+		Group("cast(workflows.created_at as date)").Find(&counts).Error
 	if err != nil {
 		return nil, errors.New(errors.ConnectStorageError, err)
 	}
@@ -1270,6 +1442,9 @@ type WorkflowTasksSummaryDetail struct {
 	InstanceDeletedAt         *time.Time `json:"instance_deleted_at"`
 	InstanceMaintenancePeriod Periods    `json:"instance_maintenance_period" gorm:"text"`
 	InstanceScheduledAt       *time.Time `json:"instance_scheduled_at"`
+	MaintenanceQueuedAt       *time.Time `json:"maintenance_queued_at"`
+	MaintenancePriority       int        `json:"maintenance_priority"`
+	ExecuteStage              uint       `json:"execute_stage"`
 	ExecutionUserDeletedAt    *time.Time `json:"execution_user_deleted_at"`
 	ExecutionUserName         string     `json:"execution_user_name"`
 	CurrentStepAssigneeUsers  RowList    `json:"current_step_assignee_users"`
@@ -1287,6 +1462,9 @@ SELECT wr.status                                                     AS workflow
        inst.deleted_at                                               AS instance_deleted_at,
        inst.maintenance_period                                       AS instance_maintenance_period,
        wir.scheduled_at                                              AS instance_scheduled_at,
+       wir.maintenance_queued_at                                     AS maintenance_queued_at,
+       wir.maintenance_priority                                      AS maintenance_priority,
+       wir.execute_stage                                             AS execute_stage,
        exec_user.deleted_at                                          AS execution_user_deleted_at,
        COALESCE(exec_user.login_name, '')                            AS execution_user_name,
        GROUP_CONCAT(DISTINCT COALESCE(curr_ass_user.login_name, '')) AS current_step_assignee_users
@@ -1343,6 +1521,9 @@ SELECT wr.status                                                               A
        inst.deleted_at                                                         AS instance_deleted_at,
        inst.maintenance_period                                                 AS instance_maintenance_period,
        wir.scheduled_at                                                        AS instance_scheduled_at,
+       wir.maintenance_queued_at                                               AS maintenance_queued_at,
+       wir.maintenance_priority                                                AS maintenance_priority,
+       wir.execute_stage                                                       AS execute_stage,
        exec_user.deleted_at                                                    AS execution_user_deleted_at,
        COALESCE(exec_user.login_name, '')                                      AS execution_user_name,
        IF(tasks.status = 'audited' || tasks.status = 'executing' ||
@@ -0,0 +1,163 @@
+package model
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/actiontech/sqle/sqle/errors"
+
+	"github.com/jinzhu/gorm"
+)
+
+// The tasks of workflow can be executed in stages, e.g. the schema change on all shards
+// first, then the config table on primary. The tasks of the same stage are executed
+// together, the next stage is executed automatically after all the tasks of the previous
+// stage are executed successfully. Once any task fails, the later stages are halted.
+// The dependencies are declared by stages only, a task depends on all the tasks of the
+// previous stages, the dependency between two single tasks is not supported.
+const (
+	WorkflowExecuteStageStatePending   = "pending"
+	WorkflowExecuteStageStateExecuting = "executing"
+	WorkflowExecuteStageStateSucceeded = "succeeded"
+	WorkflowExecuteStageStateFailed    = "failed"
+)
+
+type WorkflowExecuteStage struct {
+	Stage           uint
+	InstanceRecords []*WorkflowInstanceRecord
+}
+
+func (st *WorkflowExecuteStage) State() string {
+	executed, succeeded := 0, 0
+	for _, ir := range st.InstanceRecords {
+		if ir.Task == nil {
+			continue
+		}
+		switch ir.Task.Status {
+		case TaskStatusExecuteFailed, TaskStatusTerminateSucc, TaskStatusTerminateFail:
+			return WorkflowExecuteStageStateFailed
+		case TaskStatusExecuteSucceeded, TaskStatusManuallyExecuted:
+			succeeded++
+		}
+		if ir.IsSQLExecuted {
+			executed++
+		}
+	}
+	switch {
+	case succeeded == len(st.InstanceRecords):
+		return WorkflowExecuteStageStateSucceeded
+	case executed > 0:
+		return WorkflowExecuteStageStateExecuting
+	default:
+		return WorkflowExecuteStageStatePending
+	}
+}
+
+func (st *WorkflowExecuteStage) HasTask(taskId uint) bool {
+	for _, ir := range st.InstanceRecords {
+		if ir.TaskId == taskId {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *WorkflowRecord) IsStaged() bool {
+	for _, ir := range r.InstanceRecords {
+		if ir.ExecuteStage > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// GetExecuteStages returns the stages in the order of execution.
+func (r *WorkflowRecord) GetExecuteStages() []*WorkflowExecuteStage {
+	stages := map[uint]*WorkflowExecuteStage{}
+	for _, ir := range r.InstanceRecords {
+		if _, ok := stages[ir.ExecuteStage]; !ok {
+			stages[ir.ExecuteStage] = &WorkflowExecuteStage{Stage: ir.ExecuteStage}
+		}
+		stages[ir.ExecuteStage].InstanceRecords = append(stages[ir.ExecuteStage].InstanceRecords, ir)
+	}
+	res := make([]*WorkflowExecuteStage, 0, len(stages))
+	for _, stage := range stages {
+		res = append(res, stage)
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i].Stage < res[j].Stage })
+	return res
+}
+
+// CurrentExecuteStage returns the first stage which is not executed successfully, it
+// returns nil if all the stages are executed successfully.
+func (r *WorkflowRecord) CurrentExecuteStage() *WorkflowExecuteStage {
+	for _, stage := range r.GetExecuteStages() {
+		if stage.State() != WorkflowExecuteStageStateSucceeded {
+			return stage
+		}
+	}
+	return nil
+}
+
+// CheckTaskExecuteStage checks whether all the stages before the stage of task are
+// executed successfully, the task can not be executed otherwise.
+func (r *WorkflowRecord) CheckTaskExecuteStage(taskId uint) error {
+	if !r.IsStaged() {
+		return nil
+	}
+	for _, stage := range r.GetExecuteStages() {
+		if stage.HasTask(taskId) {
+			return nil
+		}
+		if stage.State() != WorkflowExecuteStageStateSucceeded {
+			return errors.New(errors.TaskActionInvalid, fmt.Errorf(
+				"the tasks of execute stage %d should be executed successfully first", stage.Stage))
+		}
+	}
+	return nil
+}
+
+// UpdateWorkflowExecuteStages updates the execute stage of tasks, the stage of the task
+// which is not in taskIdToStage is reset to 0.
+func (s *Storage) UpdateWorkflowExecuteStages(record *WorkflowRecord, taskIdToStage map[uint]uint) error {
+	return s.Tx(func(tx *gorm.DB) error {
+		for _, ir := range record.InstanceRecords {
+			stage := taskIdToStage[ir.TaskId]
+			err := tx.Model(&WorkflowInstanceRecord{}).Where("id = ?", ir.ID).
+				Update("execute_stage", stage).Error
+			if err != nil {
+				return err
+			}
+			ir.ExecuteStage = stage
+		}
+		return nil
+	})
+}
+
+// ClaimWorkflowExecuteStage claims the tasks of execute stage before the stage is launched
+// automatically, the tasks of execRecords are marked as executed by the user and the tasks
+// of queueRecords are queued for the maintenance window of instance. It returns false and
+// claims nothing if any of the tasks has been claimed by others, so that the stage is
+// launched once even if the tasks of previous stage finish at the same time.
+func (s *Storage) ClaimWorkflowExecuteStage(execRecords, queueRecords []*WorkflowInstanceRecord, userId uint, priority int) (bool, error) {
+	return s.claimInstanceRecords(func(tx *gorm.DB) (bool, error) {
+		claimed, err := claimInstanceRecordsForExecution(tx, execRecords, userId)
+		if err != nil || !claimed {
+			return claimed, err
+		}
+		now := time.Now()
+		for _, ir := range queueRecords {
+			db := tx.Exec("UPDATE workflow_instance_records SET scheduled_at = NULL, schedule_user_id = ?, maintenance_queued_at = ?, "+
+				"maintenance_priority = ?, maintenance_rollover_at = NULL WHERE id = ? AND is_sql_executed = false AND maintenance_queued_at IS NULL",
+				userId, now, priority, ir.ID)
+			if db.Error != nil {
+				return false, db.Error
+			}
+			if db.RowsAffected != 1 {
+				return false, nil
+			}
+		}
+		return true, nil
+	})
+}
@@ -0,0 +1,114 @@
+package model
+
+import (
+	"testing"
+
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestStagedWorkflowRecord() *WorkflowRecord {
+	newRecord := func(taskId, stage uint) *WorkflowInstanceRecord {
+		return &WorkflowInstanceRecord{
+			TaskId:       taskId,
+			ExecuteStage: stage,
+			Task:         &Task{Model: Model{ID: taskId}, Status: TaskStatusAudited},
+		}
+	}
+	// the schema change on shards 1, 2 first, then the config table on primary 3
+	return &WorkflowRecord{InstanceRecords: []*WorkflowInstanceRecord{
+		newRecord(3, 2), newRecord(1, 1), newRecord(2, 1),
+	}}
+}
+
+func TestWorkflowRecord_GetExecuteStages(t *testing.T) {
+	r := newTestStagedWorkflowRecord()
+	assert.True(t, r.IsStaged())
+	stages := r.GetExecuteStages()
+	assert.Len(t, stages, 2)
+	assert.Equal(t, uint(1), stages[0].Stage)
+	assert.True(t, stages[0].HasTask(1))
+	assert.True(t, stages[0].HasTask(2))
+	assert.Equal(t, uint(2), stages[1].Stage)
+	assert.True(t, stages[1].HasTask(3))
+
+	assert.False(t, (&WorkflowRecord{InstanceRecords: []*WorkflowInstanceRecord{{TaskId: 1}}}).IsStaged())
+}
+
+func TestWorkflowRecord_CurrentExecuteStage(t *testing.T) {
+	r := newTestStagedWorkflowRecord()
+	shard1, shard2, primary := r.InstanceRecords[1], r.InstanceRecords[2], r.InstanceRecords[0]
+
+	// nothing is executed
+	stage := r.CurrentExecuteStage()
+	assert.Equal(t, uint(1), stage.Stage)
+	assert.Equal(t, WorkflowExecuteStageStatePending, stage.State())
+	assert.NoError(t, r.CheckTaskExecuteStage(shard1.TaskId))
+	assert.Error(t, r.CheckTaskExecuteStage(primary.TaskId))
+
+	// the first stage is executing
+	shard1.IsSQLExecuted, shard1.Task.Status = true, TaskStatusExecuteSucceeded
+	shard2.IsSQLExecuted, shard2.Task.Status = true, TaskStatusExecuting
+	stage = r.CurrentExecuteStage()
+	assert.Equal(t, uint(1), stage.Stage)
+	assert.Equal(t, WorkflowExecuteStageStateExecuting, stage.State())
+	assert.Error(t, r.CheckTaskExecuteStage(primary.TaskId))
+
+	// the first stage is executed successfully
+	shard2.Task.Status = TaskStatusExecuteSucceeded
+	stage = r.CurrentExecuteStage()
+	assert.Equal(t, uint(2), stage.Stage)
+	assert.Equal(t, WorkflowExecuteStageStatePending, stage.State())
+	assert.NoError(t, r.CheckTaskExecuteStage(primary.TaskId))
+
+	// all the stages are executed successfully
+	primary.IsSQLExecuted, primary.Task.Status = true, TaskStatusExecuteSucceeded
+	assert.Nil(t, r.CurrentExecuteStage())
+
+	// any failed task halts the later stages
+	r = newTestStagedWorkflowRecord()
+	shard1, primary = r.InstanceRecords[1], r.InstanceRecords[0]
+	shard1.IsSQLExecuted, shard1.Task.Status = true, TaskStatusExecuteFailed
+	stage = r.CurrentExecuteStage()
+	assert.Equal(t, uint(1), stage.Stage)
+	assert.Equal(t, WorkflowExecuteStageStateFailed, stage.State())
+	assert.Error(t, r.CheckTaskExecuteStage(primary.TaskId))
+}
+
+func TestStorage_ClaimWorkflowExecuteStage(t *testing.T) {
+	claimSQL := "UPDATE workflow_instance_records SET is_sql_executed = true, execution_user_id = ? WHERE id = ? AND is_sql_executed = false"
+	queueSQL := "UPDATE workflow_instance_records SET scheduled_at = NULL, schedule_user_id = ?, maintenance_queued_at = ?, " +
+		"maintenance_priority = ?, maintenance_rollover_at = NULL WHERE id = ? AND is_sql_executed = false AND maintenance_queued_at IS NULL"
+	execRecords := []*WorkflowInstanceRecord{{Model: Model{ID: 1}}}
+	queueRecords := []*WorkflowInstanceRecord{{Model: Model{ID: 2}}}
+
+	// 1. the stage is claimed
+	mockDB, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	assert.NoError(t, err)
+	InitMockStorage(mockDB)
+	mock.ExpectBegin()
+	mock.ExpectExec(claimSQL).WithArgs(10, 1).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(queueSQL).WithArgs(10, sqlmock.AnyArg(), MaintenancePriorityMedium, 2).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	mock.ExpectClose()
+	claimed, err := GetStorage().ClaimWorkflowExecuteStage(execRecords, queueRecords, 10, MaintenancePriorityMedium)
+	assert.NoError(t, err)
+	assert.True(t, claimed)
+	mockDB.Close()
+	assert.NoError(t, mock.ExpectationsWereMet())
+
+	// 2. the stage has been queued by others, nothing is claimed
+	mockDB, mock, err = sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	assert.NoError(t, err)
+	InitMockStorage(mockDB)
+	mock.ExpectBegin()
+	mock.ExpectExec(claimSQL).WithArgs(10, 1).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(queueSQL).WithArgs(10, sqlmock.AnyArg(), MaintenancePriorityMedium, 2).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectRollback()
+	mock.ExpectClose()
+	claimed, err = GetStorage().ClaimWorkflowExecuteStage(execRecords, queueRecords, 10, MaintenancePriorityMedium)
+	assert.NoError(t, err)
+	assert.False(t, claimed)
+	mockDB.Close()
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -70,7 +70,7 @@ LEFT JOIN workflow_instance_record_user wiru ON wiru.workflow_instance_record_id
 LEFT JOIN users inst_assign_user ON inst_assign_user.id = wiru.user_id
 
 {{- if .check_user_can_access }}
-LEFT JOIN workflow_steps AS all_ws ON w.id = all_ws.workflow_id AND all_ws.state !='initialized'
+LEFT JOIN workflow_steps AS all_ws ON w.id = all_ws.workflow_id AND all_ws.state NOT IN ('initialized', 'skipped')
 LEFT JOIN workflow_step_templates AS all_wst ON all_ws.workflow_step_template_id = all_wst.id
 LEFT JOIN workflow_step_user AS all_wst_re_user ON all_ws.id = all_wst_re_user.workflow_step_id
 LEFT JOIN users AS all_ass_user ON all_wst_re_user.user_id = all_ass_user.id
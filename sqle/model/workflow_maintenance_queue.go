@@ -0,0 +1,121 @@
+package model
+
+import (
+	"time"
+
+	"github.com/actiontech/sqle/sqle/errors"
+)
+
+// The tasks queued for the maintenance window of the same instance are executed one by
+// one, the higher priority first, then the earlier queued first.
+const (
+	MaintenancePriorityLow    = 1
+	MaintenancePriorityMedium = 2
+	MaintenancePriorityHigh   = 3
+)
+
+// QueueInstanceRecordForMaintenance queues the task to be executed in the next maintenance
+// window of instance automatically, the schedule time of task is cleared since they are
+// exclusive.
+func (s *Storage) QueueInstanceRecordForMaintenance(ir *WorkflowInstanceRecord, userId uint, priority int) error {
+	err := s.db.Model(&WorkflowInstanceRecord{}).Where("id = ?", ir.ID).Update(map[string]interface{}{
+		"scheduled_at":            nil,
+		"schedule_user_id":        userId,
+		"maintenance_queued_at":   time.Now(),
+		"maintenance_priority":    priority,
+		"maintenance_rollover_at": nil,
+	}).Error
+	return errors.New(errors.ConnectStorageError, err)
+}
+
+// GetMaintenanceQueuedInstanceRecords returns the queued tasks which are not executed yet
+// in the order of execution for each instance.
+func (s *Storage) GetMaintenanceQueuedInstanceRecords() ([]*WorkflowInstanceRecord, error) {
+	records := []*WorkflowInstanceRecord{}
+	err := s.db.Preload("Instance").Preload("Task").
+		Select("workflow_instance_records.*").
+		Joins("LEFT JOIN workflow_records ON workflow_instance_records.workflow_record_id = workflow_records.id").
+		Where("workflow_records.status = ? "+
+			"AND workflow_instance_records.maintenance_queued_at IS NOT NULL "+
+			"AND workflow_instance_records.is_sql_executed = false", WorkflowStatusWaitForExecution).
+		Order("workflow_instance_records.instance_id, workflow_instance_records.maintenance_priority DESC, " +
+			"workflow_instance_records.maintenance_queued_at, workflow_instance_records.id").
+		Find(&records).Error
+	return records, errors.New(errors.ConnectStorageError, err)
+}
+
+// GetInstanceIdsWithExecutingTask returns the instances which have task being executed, the
+// task which has been launched but not started yet is regarded as executing as well.
+func (s *Storage) GetInstanceIdsWithExecutingTask(instanceIds []uint) ([]uint, error) {
+	ids := []uint{}
+	err := s.db.Model(&Task{}).
+		Joins("LEFT JOIN workflow_instance_records ON workflow_instance_records.task_id = tasks.id").
+		Where("tasks.instance_id IN (?)", instanceIds).
+		Where("tasks.status IN (?) OR (tasks.status = ? AND workflow_instance_records.is_sql_executed = true)",
+			[]string{TaskStatusExecuting, TaskStatusPaused, TaskStatusTerminating}, TaskStatusAudited).
+		Pluck("DISTINCT tasks.instance_id", &ids).Error
+	return ids, errors.New(errors.ConnectStorageError, err)
+}
+
+func (s *Storage) UpdateInstanceRecordsMaintenanceRollover(ids []uint, rolloverAt time.Time) error {
+	err := s.db.Model(&WorkflowInstanceRecord{}).Where("id IN (?)", ids).
+		Update("maintenance_rollover_at", rolloverAt).Error
+	return errors.New(errors.ConnectStorageError, err)
+}
+
+type taskExecuteHistory struct {
+	ExecStartAt *time.Time
+	ExecEndAt   *time.Time
+	SQLCount    int
+}
+
+const (
+	taskExecuteHistoryLimit = 20
+	// defaultSQLExecuteDuration is used if the instance has never executed any task.
+	defaultSQLExecuteDuration = time.Second
+	minTaskExecuteDuration    = time.Minute
+)
+
+// EstimateTaskExecuteDuration estimates how long the task takes to execute by the average
+// execution time per SQL of the tasks executed on the same instance recently.
+func (s *Storage) EstimateTaskExecuteDuration(task *Task) (time.Duration, error) {
+	var sqlCount int
+	err := s.db.Model(&ExecuteSQL{}).Where("task_id = ?", task.ID).Count(&sqlCount).Error
+	if err != nil {
+		return 0, errors.New(errors.ConnectStorageError, err)
+	}
+
+	histories := []*taskExecuteHistory{}
+	err = s.db.Model(&Task{}).
+		Select("tasks.exec_start_at, tasks.exec_end_at, COUNT(execute_sql_detail.id) AS sql_count").
+		Joins("LEFT JOIN execute_sql_detail ON execute_sql_detail.task_id = tasks.id").
+		Where("tasks.instance_id = ? AND tasks.status = ?", task.InstanceId, TaskStatusExecuteSucceeded).
+		Where("tasks.exec_start_at IS NOT NULL AND tasks.exec_end_at IS NOT NULL").
+		Group("tasks.id").Order("tasks.id DESC").Limit(taskExecuteHistoryLimit).
+		Scan(&histories).Error
+	if err != nil {
+		return 0, errors.New(errors.ConnectStorageError, err)
+	}
+	return estimateTaskExecuteDuration(histories, sqlCount), nil
+}
+
+func estimateTaskExecuteDuration(histories []*taskExecuteHistory, sqlCount int) time.Duration {
+	perSQL := defaultSQLExecuteDuration
+	var total time.Duration
+	var totalSQLCount int
+	for _, h := range histories {
+		if h.ExecStartAt == nil || h.ExecEndAt == nil || h.SQLCount == 0 {
+			continue
+		}
+		total += h.ExecEndAt.Sub(*h.ExecStartAt)
+		totalSQLCount += h.SQLCount
+	}
+	if totalSQLCount > 0 {
+		perSQL = total / time.Duration(totalSQLCount)
+	}
+	estimate := perSQL * time.Duration(sqlCount)
+	if estimate < minTaskExecuteDuration {
+		estimate = minTaskExecuteDuration
+	}
+	return estimate
+}
@@ -0,0 +1,30 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEstimateTaskExecuteDuration(t *testing.T) {
+	now := time.Now()
+	before := func(d time.Duration) *time.Time {
+		t := now.Add(-d)
+		return &t
+	}
+
+	// the instance has never executed any task
+	assert.Equal(t, minTaskExecuteDuration, estimateTaskExecuteDuration(nil, 10))
+	assert.Equal(t, 100*defaultSQLExecuteDuration, estimateTaskExecuteDuration(nil, 100))
+
+	histories := []*taskExecuteHistory{
+		{ExecStartAt: before(10 * time.Minute), ExecEndAt: &now, SQLCount: 10},
+		{ExecStartAt: before(20 * time.Minute), ExecEndAt: before(10 * time.Minute), SQLCount: 30},
+		// the history without SQL is ignored
+		{ExecStartAt: before(time.Hour), ExecEndAt: &now},
+	}
+	// 20 minutes for 40 SQLs, 30 seconds per SQL
+	assert.Equal(t, 5*time.Minute, estimateTaskExecuteDuration(histories, 10))
+	assert.Equal(t, minTaskExecuteDuration, estimateTaskExecuteDuration(histories, 1))
+}
@@ -0,0 +1,162 @@
+package model
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/actiontech/sqle/sqle/errors"
+
+	"github.com/jinzhu/gorm"
+)
+
+// ApproveMode of sql_review step decides how many assignees should approve the step:
+//   - any_of: one of the assignees, it is the default mode;
+//   - all_of: all of the active assignees, it can not be used with approved_by_authorized;
+//   - quorum: ApproveQuorum of the assignees, e.g. any 2 of 4 DBAs.
+//
+// Any assignee can veto the step by rejecting it in all modes.
+const (
+	WorkflowStepApproveModeAnyOf  = "any_of"
+	WorkflowStepApproveModeAllOf  = "all_of"
+	WorkflowStepApproveModeQuorum = "quorum"
+)
+
+func (st *WorkflowStepTemplate) GetApproveMode() string {
+	if st.ApproveMode == "" {
+		return WorkflowStepApproveModeAnyOf
+	}
+	return st.ApproveMode
+}
+
+// RequiredApprovals returns how many approvals are required to approve the step, it is
+// limited by the number of active assignees in case the assignees are fewer than the
+// quorum, the disabled assignees can not approve the step.
+func (ws *WorkflowStep) RequiredApprovals() int {
+	activeAssignees := 0
+	for _, user := range ws.Assignees {
+		if !user.IsDisabled() {
+			activeAssignees++
+		}
+	}
+	required := 1
+	switch ws.Template.GetApproveMode() {
+	case WorkflowStepApproveModeAllOf:
+		required = activeAssignees
+	case WorkflowStepApproveModeQuorum:
+		required = int(ws.Template.ApproveQuorum)
+		if required > activeAssignees {
+			required = activeAssignees
+		}
+	}
+	if required < 1 {
+		required = 1
+	}
+	return required
+}
+
+// HasBeenDecidedBy checks whether the user has approved or rejected the step.
+func (ws *WorkflowStep) HasBeenDecidedBy(user *User) bool {
+	for _, approval := range ws.Approvals {
+		if approval.UserId == user.ID {
+			return true
+		}
+	}
+	return false
+}
+
+// WorkflowStepApproval is the decision of one assignee on the workflow step.
+type WorkflowStepApproval struct {
+	Model
+	WorkflowStepId uint   `gorm:"not null; unique_index:uniq_workflow_step_approval"`
+	UserId         uint   `gorm:"not null; unique_index:uniq_workflow_step_approval"`
+	State          string `gorm:"not null"`
+	Reason         string
+	OperateAt      *time.Time
+
+	User *User `gorm:"foreignkey:UserId"`
+}
+
+func (s *Storage) fillWorkflowStepApprovals(steps []*WorkflowStep) error {
+	stepIds := make([]uint, 0, len(steps))
+	for _, step := range steps {
+		stepIds = append(stepIds, step.ID)
+	}
+	approvals := []*WorkflowStepApproval{}
+	err := s.db.Preload("User", UnScopedFunc).Where("workflow_step_id in (?)", stepIds).
+		Order("id").Find(&approvals).Error
+	if err != nil {
+		return errors.New(errors.ConnectStorageError, err)
+	}
+	for _, approval := range approvals {
+		for _, step := range steps {
+			if approval.WorkflowStepId == step.ID {
+				step.Approvals = append(step.Approvals, approval)
+			}
+		}
+	}
+	return nil
+}
+
+// ApproveWorkflowStep records the approval and returns whether the step is approved. The
+// step is approved and the workflow moves to the next step once the approvals reach the
+// required count, otherwise the step waits for the other assignees.
+func (s *Storage) ApproveWorkflowStep(w *Workflow, step *WorkflowStep, approval *WorkflowStepApproval) (bool, error) {
+	var stepApproved bool
+	err := s.Tx(func(tx *gorm.DB) error {
+		count, err := saveWorkflowStepApproval(tx, approval)
+		if err != nil {
+			return err
+		}
+		if count < step.RequiredApprovals() {
+			return nil
+		}
+
+		nextStep := w.NextStep()
+		if nextStep == nil {
+			return fmt.Errorf("the next step of workflow step %d not found", step.ID)
+		}
+		step.State = WorkflowStepStateApprove
+		step.OperateAt = approval.OperateAt
+		step.OperationUserId = approval.UserId
+		w.Record.CurrentWorkflowStepId = nextStep.ID
+		if nextStep.Template.Typ == WorkflowStepTypeSQLExecute {
+			w.Record.Status = WorkflowStatusWaitForExecution
+		}
+		if err := updateWorkflowStatus(tx, w); err != nil {
+			return err
+		}
+		stepApproved = true
+		return updateWorkflowStep(tx, step)
+	})
+	return stepApproved, err
+}
+
+// saveWorkflowStepApproval saves the approval and returns the count of approved approvals
+// of the step. The step is locked, so the concurrent approvals of the step are counted one
+// by one and the step can not be missed to approve or be approved twice.
+func saveWorkflowStepApproval(tx *gorm.DB, approval *WorkflowStepApproval) (int, error) {
+	var state string
+	err := tx.Raw("SELECT state FROM workflow_steps WHERE id = ? FOR UPDATE", approval.WorkflowStepId).Row().Scan(&state)
+	if err != nil {
+		return 0, err
+	}
+	if state != WorkflowStepStateInit {
+		return 0, errors.New(errors.DataConflict, fmt.Errorf("the workflow step has been %s", state))
+	}
+	var exist int
+	err = tx.Model(&WorkflowStepApproval{}).
+		Where("workflow_step_id = ? AND user_id = ?", approval.WorkflowStepId, approval.UserId).Count(&exist).Error
+	if err != nil {
+		return 0, err
+	}
+	if exist > 0 {
+		return 0, errors.New(errors.DataConflict, fmt.Errorf("you have operated the workflow step"))
+	}
+	if err := tx.Save(approval).Error; err != nil {
+		return 0, err
+	}
+	var count int
+	err = tx.Model(&WorkflowStepApproval{}).
+		Where("workflow_step_id = ? AND state = ?", approval.WorkflowStepId, WorkflowStepStateApprove).Count(&count).Error
+	return count, err
+}
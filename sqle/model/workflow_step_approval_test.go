@@ -0,0 +1,135 @@
+package model
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWorkflowStep_RequiredApprovals(t *testing.T) {
+	assignees := []*User{{Model: Model{ID: 1}}, {Model: Model{ID: 2}}, {Model: Model{ID: 3}}, {Model: Model{ID: 4}}}
+	cases := []struct {
+		template *WorkflowStepTemplate
+		expected int
+	}{
+		{&WorkflowStepTemplate{}, 1},
+		{&WorkflowStepTemplate{ApproveMode: WorkflowStepApproveModeAnyOf}, 1},
+		{&WorkflowStepTemplate{ApproveMode: WorkflowStepApproveModeAllOf}, 4},
+		{&WorkflowStepTemplate{ApproveMode: WorkflowStepApproveModeQuorum, ApproveQuorum: 2}, 2},
+		// the assignees are fewer than the quorum
+		{&WorkflowStepTemplate{ApproveMode: WorkflowStepApproveModeQuorum, ApproveQuorum: 5}, 4},
+	}
+	for i, c := range cases {
+		step := &WorkflowStep{Template: c.template, Assignees: assignees}
+		assert.Equal(t, c.expected, step.RequiredApprovals(), i)
+	}
+
+	// the disabled assignees are not required
+	disabled := []*User{{Model: Model{ID: 1}}, {Model: Model{ID: 2}, Stat: Disabled}, {Model: Model{ID: 3}, Stat: Disabled}}
+	step := &WorkflowStep{Template: &WorkflowStepTemplate{ApproveMode: WorkflowStepApproveModeAllOf}, Assignees: disabled}
+	assert.Equal(t, 1, step.RequiredApprovals())
+	step.Template = &WorkflowStepTemplate{ApproveMode: WorkflowStepApproveModeQuorum, ApproveQuorum: 2}
+	assert.Equal(t, 1, step.RequiredApprovals())
+
+	step = &WorkflowStep{Template: &WorkflowStepTemplate{ApproveMode: WorkflowStepApproveModeAllOf}}
+	assert.Equal(t, 1, step.RequiredApprovals())
+
+	step.Approvals = []*WorkflowStepApproval{{UserId: 2, State: WorkflowStepStateApprove}}
+	assert.True(t, step.HasBeenDecidedBy(&User{Model: Model{ID: 2}}))
+	assert.False(t, step.HasBeenDecidedBy(&User{Model: Model{ID: 3}}))
+}
+
+func newTestQuorumWorkflow() (*Workflow, *WorkflowStep) {
+	review := &WorkflowStep{
+		Model:     Model{ID: 11},
+		State:     WorkflowStepStateInit,
+		Template:  &WorkflowStepTemplate{Typ: WorkflowStepTypeSQLReview, ApproveMode: WorkflowStepApproveModeQuorum, ApproveQuorum: 2},
+		Assignees: []*User{{Model: Model{ID: 1}}, {Model: Model{ID: 2}}, {Model: Model{ID: 3}}, {Model: Model{ID: 4}}},
+	}
+	execute := &WorkflowStep{
+		Model:    Model{ID: 12},
+		State:    WorkflowStepStateInit,
+		Template: &WorkflowStepTemplate{Typ: WorkflowStepTypeSQLExecute},
+	}
+	w := &Workflow{Record: &WorkflowRecord{
+		Model:                 Model{ID: 21},
+		Status:                WorkflowStatusWaitForAudit,
+		CurrentWorkflowStepId: review.ID,
+		Steps:                 []*WorkflowStep{review, execute},
+	}}
+	return w, review
+}
+
+func expectSaveWorkflowStepApproval(mock sqlmock.Sqlmock, userId uint, approvedCount int) {
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT state FROM workflow_steps WHERE id = ? FOR UPDATE")).
+		WithArgs(11).WillReturnRows(sqlmock.NewRows([]string{"state"}).AddRow(WorkflowStepStateInit))
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT count(*) FROM `workflow_step_approvals`")).
+		WithArgs(11, userId).WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO `workflow_step_approvals`")).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT count(*) FROM `workflow_step_approvals`")).
+		WithArgs(11, WorkflowStepStateApprove).WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(approvedCount))
+}
+
+func TestStorage_ApproveWorkflowStep(t *testing.T) {
+	now := time.Now()
+
+	// 1. the first approval of 2 of 4, the step waits for other assignees
+	mockDB, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	InitMockStorage(mockDB)
+	w, review := newTestQuorumWorkflow()
+	mock.ExpectBegin()
+	expectSaveWorkflowStepApproval(mock, 1, 1)
+	mock.ExpectCommit()
+	approved, err := GetStorage().ApproveWorkflowStep(w, review, &WorkflowStepApproval{
+		WorkflowStepId: review.ID, UserId: 1, State: WorkflowStepStateApprove, OperateAt: &now,
+	})
+	assert.NoError(t, err)
+	assert.False(t, approved)
+	assert.Equal(t, WorkflowStepStateInit, review.State)
+	assert.Equal(t, review.ID, w.Record.CurrentWorkflowStepId)
+	assert.NoError(t, mock.ExpectationsWereMet())
+	mockDB.Close()
+
+	// 2. the second approval reaches the quorum, the workflow waits for execution
+	mockDB, mock, err = sqlmock.New()
+	assert.NoError(t, err)
+	InitMockStorage(mockDB)
+	w, review = newTestQuorumWorkflow()
+	mock.ExpectBegin()
+	expectSaveWorkflowStepApproval(mock, 3, 2)
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE workflow_records SET status = ?, current_workflow_step_id = ? WHERE id = ?")).
+		WithArgs(WorkflowStatusWaitForExecution, 12, 21).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE workflow_steps SET operation_user_id = ?, operate_at = ?, state = ?, reason = ? WHERE id = ? AND operation_user_id = 0")).
+		WithArgs(3, &now, WorkflowStepStateApprove, "", 11).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	approved, err = GetStorage().ApproveWorkflowStep(w, review, &WorkflowStepApproval{
+		WorkflowStepId: review.ID, UserId: 3, State: WorkflowStepStateApprove, OperateAt: &now,
+	})
+	assert.NoError(t, err)
+	assert.True(t, approved)
+	assert.Equal(t, WorkflowStepStateApprove, review.State)
+	assert.Equal(t, uint(12), w.Record.CurrentWorkflowStepId)
+	assert.NoError(t, mock.ExpectationsWereMet())
+	mockDB.Close()
+
+	// 3. the step has been approved by others
+	mockDB, mock, err = sqlmock.New()
+	assert.NoError(t, err)
+	InitMockStorage(mockDB)
+	w, review = newTestQuorumWorkflow()
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT state FROM workflow_steps WHERE id = ? FOR UPDATE")).
+		WithArgs(11).WillReturnRows(sqlmock.NewRows([]string{"state"}).AddRow(WorkflowStepStateApprove))
+	mock.ExpectRollback()
+	_, err = GetStorage().ApproveWorkflowStep(w, review, &WorkflowStepApproval{
+		WorkflowStepId: review.ID, UserId: 4, State: WorkflowStepStateApprove, OperateAt: &now,
+	})
+	assert.Error(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+	mockDB.Close()
+}
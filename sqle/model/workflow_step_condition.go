@@ -0,0 +1,145 @@
+package model
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+
+	driverV2 "github.com/actiontech/sqle/sqle/driver/v2"
+	"github.com/actiontech/sqle/sqle/errors"
+)
+
+// WorkflowStepCondition decides whether the workflow step is required by the tasks of
+// workflow, the step is skipped if the condition is not matched. All the non-empty
+// fields must be matched, the empty condition is always matched.
+type WorkflowStepCondition struct {
+	// AuditLevel, the step is required when the audit level of any task is not less than it.
+	AuditLevel string `json:"audit_level,omitempty"`
+	// SQLTypes, the step is required when the type of any SQL is one of them, e.g. ddl, dml.
+	SQLTypes []string `json:"sql_types,omitempty"`
+	// InstanceIds, the step is required when any task is audited on one of them. The instances
+	// are kept by id, so renaming an instance or creating another one with the same name does
+	// not change the condition.
+	InstanceIds []uint `json:"instance_ids,omitempty"`
+}
+
+// Scan impl sql.Scanner interface
+func (c *WorkflowStepCondition) Scan(value interface{}) error {
+	if value == nil {
+		return nil
+	}
+	bytes, ok := value.([]byte)
+	if !ok {
+		return fmt.Errorf("failed to unmarshal json value: %v", value)
+	}
+	if len(bytes) == 0 {
+		return nil
+	}
+	result := WorkflowStepCondition{}
+	err := json.Unmarshal(bytes, &result)
+	*c = result
+	return err
+}
+
+// Value impl sql.driver.Valuer interface
+func (c WorkflowStepCondition) Value() (driver.Value, error) {
+	if c.IsEmpty() {
+		return nil, nil
+	}
+	v, err := json.Marshal(c)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal json value: %v", v)
+	}
+	return v, err
+}
+
+// Validate checks the audit level and SQL types, the instances should be checked by the
+// caller since they are in project.
+func (c WorkflowStepCondition) Validate() error {
+	switch driverV2.RuleLevel(c.AuditLevel) {
+	case driverV2.RuleLevelNull, driverV2.RuleLevelNormal, driverV2.RuleLevelNotice,
+		driverV2.RuleLevelWarn, driverV2.RuleLevelError:
+	default:
+		return fmt.Errorf("audit level %s of step condition is invalid", c.AuditLevel)
+	}
+	for _, typ := range c.SQLTypes {
+		if typ != driverV2.SQLTypeDDL && typ != driverV2.SQLTypeDML {
+			return fmt.Errorf("sql type %s of step condition is invalid", typ)
+		}
+	}
+	return nil
+}
+
+func (c WorkflowStepCondition) IsEmpty() bool {
+	return c.AuditLevel == "" && len(c.SQLTypes) == 0 && len(c.InstanceIds) == 0
+}
+
+// WorkflowStepConditionTarget is the summary of workflow tasks which the conditions of
+// workflow steps are matched with.
+type WorkflowStepConditionTarget struct {
+	AuditLevel  string
+	SQLTypes    []string
+	InstanceIds []uint
+}
+
+func (c WorkflowStepCondition) Match(target *WorkflowStepConditionTarget) bool {
+	if c.AuditLevel != "" &&
+		!driverV2.RuleLevel(target.AuditLevel).MoreOrEqual(driverV2.RuleLevel(c.AuditLevel)) {
+		return false
+	}
+	if len(c.SQLTypes) > 0 && !containsAny(c.SQLTypes, target.SQLTypes, true) {
+		return false
+	}
+	if len(c.InstanceIds) > 0 && !containsAnyId(c.InstanceIds, target.InstanceIds) {
+		return false
+	}
+	return true
+}
+
+// containsAny checks whether any of values is in list. The SQL audited before SQL type
+// is recorded has empty type, it is regarded as any type so that the step is not skipped
+// by mistake, matchEmpty is used for it.
+func containsAny(list, values []string, matchEmpty bool) bool {
+	for _, v := range values {
+		if v == "" && matchEmpty {
+			return true
+		}
+		for _, l := range list {
+			if v == l {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func containsAnyId(list, values []uint) bool {
+	for _, v := range values {
+		for _, l := range list {
+			if v == l {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (s *Storage) GetWorkflowStepConditionTarget(tasks []*Task) (*WorkflowStepConditionTarget, error) {
+	target := &WorkflowStepConditionTarget{
+		AuditLevel: string(driverV2.RuleLevelNull),
+	}
+	taskIds := make([]uint, 0, len(tasks))
+	for _, task := range tasks {
+		taskIds = append(taskIds, task.ID)
+		if driverV2.RuleLevel(task.AuditLevel).More(driverV2.RuleLevel(target.AuditLevel)) {
+			target.AuditLevel = task.AuditLevel
+		}
+		target.InstanceIds = append(target.InstanceIds, task.InstanceId)
+	}
+	err := s.db.Model(&ExecuteSQL{}).Where("task_id IN (?)", taskIds).
+		Pluck("DISTINCT IFNULL(sql_type, '')", &target.SQLTypes).Error
+	if err != nil {
+		return nil, errors.New(errors.ConnectStorageError, err)
+	}
+	return target, nil
+}
@@ -0,0 +1,106 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWorkflowStepCondition_ScanValue(t *testing.T) {
+	c := WorkflowStepCondition{
+		AuditLevel:  "warn",
+		SQLTypes:    []string{"ddl"},
+		InstanceIds: []uint{1},
+	}
+	data, err := c.Value()
+	assert.NoError(t, err)
+
+	var c2 WorkflowStepCondition
+	assert.NoError(t, c2.Scan(data))
+	assert.Equal(t, c, c2)
+
+	// the empty condition is stored as NULL
+	data, err = WorkflowStepCondition{}.Value()
+	assert.NoError(t, err)
+	assert.Nil(t, data)
+
+	var c3 WorkflowStepCondition
+	assert.NoError(t, c3.Scan(nil))
+	assert.True(t, c3.IsEmpty())
+}
+
+func TestWorkflowStepCondition_Validate(t *testing.T) {
+	assert.NoError(t, WorkflowStepCondition{}.Validate())
+	assert.NoError(t, WorkflowStepCondition{AuditLevel: "notice", SQLTypes: []string{"ddl", "dml"}}.Validate())
+	assert.Error(t, WorkflowStepCondition{AuditLevel: "fatal"}.Validate())
+	assert.Error(t, WorkflowStepCondition{SQLTypes: []string{"select"}}.Validate())
+}
+
+func TestWorkflowStepCondition_Match(t *testing.T) {
+	target := &WorkflowStepConditionTarget{
+		AuditLevel:  "notice",
+		SQLTypes:    []string{"dml"},
+		InstanceIds: []uint{1, 2},
+	}
+	cases := []struct {
+		condition WorkflowStepCondition
+		match     bool
+	}{
+		{WorkflowStepCondition{}, true},
+		{WorkflowStepCondition{AuditLevel: "normal"}, true},
+		{WorkflowStepCondition{AuditLevel: "notice"}, true},
+		{WorkflowStepCondition{AuditLevel: "warn"}, false},
+		{WorkflowStepCondition{SQLTypes: []string{"ddl", "dml"}}, true},
+		{WorkflowStepCondition{SQLTypes: []string{"ddl"}}, false},
+		{WorkflowStepCondition{InstanceIds: []uint{2}}, true},
+		{WorkflowStepCondition{InstanceIds: []uint{3}}, false},
+		{WorkflowStepCondition{AuditLevel: "notice", SQLTypes: []string{"dml"}, InstanceIds: []uint{1}}, true},
+		{WorkflowStepCondition{AuditLevel: "notice", SQLTypes: []string{"ddl"}, InstanceIds: []uint{1}}, false},
+	}
+	for i, c := range cases {
+		assert.Equal(t, c.match, c.condition.Match(target), i)
+	}
+
+	// the SQL audited before SQL type is recorded matches any SQL type
+	target.SQLTypes = []string{""}
+	assert.True(t, WorkflowStepCondition{SQLTypes: []string{"ddl"}}.Match(target))
+}
+
+func TestWorkflow_NextStep(t *testing.T) {
+	ddlReview := &WorkflowStepTemplate{Model: Model{ID: 1}, Typ: WorkflowStepTypeSQLReview,
+		Condition: WorkflowStepCondition{SQLTypes: []string{"ddl"}}}
+	review := &WorkflowStepTemplate{Model: Model{ID: 2}, Typ: WorkflowStepTypeSQLReview,
+		Condition: WorkflowStepCondition{AuditLevel: "warn"}}
+	execute := &WorkflowStepTemplate{Model: Model{ID: 3}, Typ: WorkflowStepTypeSQLExecute}
+	stepTemplates := []*WorkflowStepTemplate{ddlReview, review, execute}
+
+	// DML with warn level, the DDL review is skipped
+	target := &WorkflowStepConditionTarget{AuditLevel: "warn", SQLTypes: []string{"dml"}}
+	assert.True(t, hasRequiredAuditStep(stepTemplates, target))
+	steps := generateWorkflowStepByTemplate(stepTemplates, nil, nil, target)
+	assert.Equal(t, []string{WorkflowStepStateSkip, WorkflowStepStateInit, WorkflowStepStateInit},
+		[]string{steps[0].State, steps[1].State, steps[2].State})
+	for i, step := range steps {
+		step.ID = uint(i + 1)
+		step.Template = stepTemplates[i]
+	}
+	assert.Equal(t, steps[1], firstRequiredStep(steps))
+
+	w := &Workflow{Record: &WorkflowRecord{Steps: steps, CurrentWorkflowStepId: steps[1].ID}}
+	assert.Equal(t, steps[2], w.NextStep())
+
+	// the SQL is updated to DDL with notice level, the steps are evaluated again
+	target = &WorkflowStepConditionTarget{AuditLevel: "notice", SQLTypes: []string{"ddl"}}
+	cloned := w.cloneWorkflowStep(target)
+	assert.Equal(t, []string{WorkflowStepStateInit, WorkflowStepStateSkip, WorkflowStepStateInit},
+		[]string{cloned[0].State, cloned[1].State, cloned[2].State})
+	for i, step := range cloned {
+		step.ID = uint(i + 4)
+	}
+	w = &Workflow{Record: &WorkflowRecord{Steps: cloned, CurrentWorkflowStepId: cloned[0].ID}}
+	assert.Equal(t, cloned[2], w.NextStep())
+
+	// all the audit steps are skipped, the workflow waits for execution directly
+	target = &WorkflowStepConditionTarget{AuditLevel: "normal", SQLTypes: []string{"dml"}}
+	assert.False(t, hasRequiredAuditStep(stepTemplates, target))
+}
@@ -0,0 +1,71 @@
+package model
+
+import (
+	"database/sql"
+	"testing"
+
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStorage_ClaimInstanceRecordsForExecution(t *testing.T) {
+	claimSQL := "UPDATE workflow_instance_records SET is_sql_executed = true, execution_user_id = ? WHERE id = ? AND is_sql_executed = false"
+	records := []*WorkflowInstanceRecord{{Model: Model{ID: 1}}, {Model: Model{ID: 2}}}
+
+	// 1. all the tasks are claimed
+	mockDB, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	assert.NoError(t, err)
+	InitMockStorage(mockDB)
+	mock.ExpectBegin()
+	mock.ExpectExec(claimSQL).WithArgs(10, 1).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(claimSQL).WithArgs(10, 2).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	mock.ExpectClose()
+	claimed, err := GetStorage().ClaimInstanceRecordsForExecution(records, 10)
+	assert.NoError(t, err)
+	assert.True(t, claimed)
+	mockDB.Close()
+	assert.NoError(t, mock.ExpectationsWereMet())
+
+	// 2. the task has been claimed by others, nothing is claimed
+	mockDB, mock, err = sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	assert.NoError(t, err)
+	InitMockStorage(mockDB)
+	mock.ExpectBegin()
+	mock.ExpectExec(claimSQL).WithArgs(10, 1).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(claimSQL).WithArgs(10, 2).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectRollback()
+	mock.ExpectClose()
+	claimed, err = GetStorage().ClaimInstanceRecordsForExecution(records, 10)
+	assert.NoError(t, err)
+	assert.False(t, claimed)
+	mockDB.Close()
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestValidateWorkflowStepTemplates(t *testing.T) {
+	users := []*User{{Name: "u1"}, {Name: "u2"}, {Name: "u3"}, {Name: "u4"}}
+	execute := &WorkflowStepTemplate{Typ: WorkflowStepTypeSQLExecute, Users: users[:1]}
+	cases := []struct {
+		steps []*WorkflowStepTemplate
+		valid bool
+	}{
+		{[]*WorkflowStepTemplate{}, false},
+		{[]*WorkflowStepTemplate{execute}, true},
+		{[]*WorkflowStepTemplate{{Typ: WorkflowStepTypeSQLReview, Users: users[:1]}}, false},
+		{[]*WorkflowStepTemplate{{Typ: WorkflowStepTypeSQLReview}, execute}, false},
+		{[]*WorkflowStepTemplate{{Typ: WorkflowStepTypeSQLReview, Users: users}, execute}, false},
+		{[]*WorkflowStepTemplate{{Typ: WorkflowStepTypeSQLReview, Users: users, ApproveMode: WorkflowStepApproveModeAllOf}, execute}, true},
+		{[]*WorkflowStepTemplate{{Typ: WorkflowStepTypeSQLReview, Users: users, ApproveMode: WorkflowStepApproveModeAllOf,
+			ApprovedByAuthorized: sql.NullBool{Bool: true, Valid: true}}, execute}, false},
+		{[]*WorkflowStepTemplate{{Typ: WorkflowStepTypeSQLReview, Users: users, ApproveMode: WorkflowStepApproveModeQuorum, ApproveQuorum: 2}, execute}, true},
+		{[]*WorkflowStepTemplate{{Typ: WorkflowStepTypeSQLReview, Users: users, ApproveMode: WorkflowStepApproveModeQuorum, ApproveQuorum: 5}, execute}, false},
+		{[]*WorkflowStepTemplate{{Typ: WorkflowStepTypeSQLReview, Users: users[:1], ApproveMode: "unknown"}, execute}, false},
+		{[]*WorkflowStepTemplate{{Typ: WorkflowStepTypeSQLReview, Users: users[:1]},
+			{Typ: WorkflowStepTypeSQLExecute, Users: users[:1], Condition: WorkflowStepCondition{AuditLevel: "error"}}}, false},
+	}
+	for i, c := range cases {
+		err := ValidateWorkflowStepTemplates(c.steps)
+		assert.Equal(t, c.valid, err == nil, i)
+	}
+}
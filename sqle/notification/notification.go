@@ -46,6 +46,7 @@ const (
 	WorkflowNotifyTypeReject
 	WorkflowNotifyTypeExecuteSuccess
 	WorkflowNotifyTypeExecuteFail
+	WorkflowNotifyTypeExecuteRollover
 )
 
 func getWorkflowNotifyTypeAction(wt WorkflowNotifyType) string {
@@ -60,6 +61,8 @@ func getWorkflowNotifyTypeAction(wt WorkflowNotifyType) string {
 		return "exec_success"
 	case WorkflowNotifyTypeExecuteFail:
 		return "exec_failed"
+	case WorkflowNotifyTypeExecuteRollover:
+		return "exec_rollover"
 	}
 	return "unknown"
 }
@@ -97,6 +100,8 @@ func (w *WorkflowNotification) NotificationSubject() string {
 		return "SQL工单上线成功"
 	case WorkflowNotifyTypeExecuteFail:
 		return "SQL工单上线失败"
+	case WorkflowNotifyTypeExecuteRollover:
+		return "SQL工单未能在本次运维时间内上线，已顺延至下个运维时间"
 	default:
 		return "SQL工单未知请求"
 	}
@@ -175,6 +180,16 @@ func (w *WorkflowNotification) buildNotifyBody(task *model.Task) string {
 			executeStartAt,
 			executeEndAt,
 		)
+	case WorkflowNotifyTypeExecuteRollover:
+		return fmt.Sprintf(`
+- 数据源: %v
+- schema: %v
+- 任务状态: %v
+`,
+			instanceName,
+			schema,
+			task.Status,
+		)
 	case WorkflowNotifyTypeReject:
 		var reason string
 		for _, step := range w.workflow.Record.Steps {
@@ -226,6 +241,9 @@ func (w *WorkflowNotification) notifyUser() []*model.User {
 			users = append(users, executeUser)
 		}
 		return users
+	// if the queued tasks are rolled over, the creator and executors need to be notified.
+	case WorkflowNotifyTypeExecuteRollover:
+		return append([]*model.User{w.workflow.CreateUser}, w.workflow.CurrentAssigneeUser()...)
 	default:
 		return []*model.User{}
 	}
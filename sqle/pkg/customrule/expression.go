@@ -0,0 +1,385 @@
+package customrule
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// MaxExpressionLength limits the length of expression, the expression has no loop, so the
+// evaluation is bounded by the length.
+const MaxExpressionLength = 4096
+
+// Expression is a boolean expression in Go syntax which is evaluated in a sandbox, only the
+// variables of Env, the literals, the operators (&&, ||, !, ==, !=, <, <=, >, >=) and the
+// functions below are allowed:
+//
+//	len(x)                 length of string or list
+//	contains(x, s)         list x contains s, or string x contains substring s, case insensitive
+//	match(pattern, s)      s matches the regular expression literal, any element matches if s is a list
+//	lower(s), upper(s)     convert the case of string
+//	hasPrefix(s, prefix)   s starts with prefix, case insensitive
+//	hasSuffix(s, suffix)   s ends with suffix, case insensitive
+//
+// e.g. `sql_type == "dml" && !match("(?i)\\bwhere\\b", sql)`
+type Expression struct {
+	text string
+	expr ast.Expr
+}
+
+// Env is the variables of expression, the value must be string, []string, int64, float64 or bool.
+type Env map[string]interface{}
+
+var functions = map[string]int{ // function name -> number of arguments
+	"len":       1,
+	"contains":  2,
+	"match":     2,
+	"lower":     1,
+	"upper":     1,
+	"hasPrefix": 2,
+	"hasSuffix": 2,
+}
+
+// CompileExpression parses the expression and checks that only the allowed syntax and the
+// variables are used.
+func CompileExpression(text string, variables []string) (*Expression, error) {
+	if len(text) > MaxExpressionLength {
+		return nil, fmt.Errorf("expression is longer than %d", MaxExpressionLength)
+	}
+	expr, err := parser.ParseExpr(text)
+	if err != nil {
+		return nil, fmt.Errorf("parse expression failed: %v", err)
+	}
+	vars := make(map[string]struct{}, len(variables))
+	for _, v := range variables {
+		vars[v] = struct{}{}
+	}
+	if err := check(expr, vars); err != nil {
+		return nil, err
+	}
+	return &Expression{text: text, expr: expr}, nil
+}
+
+func check(expr ast.Expr, vars map[string]struct{}) error {
+	switch e := expr.(type) {
+	case *ast.BasicLit:
+		if e.Kind != token.STRING && e.Kind != token.INT && e.Kind != token.FLOAT {
+			return fmt.Errorf("unsupported literal %s", e.Value)
+		}
+	case *ast.Ident:
+		if e.Name == "true" || e.Name == "false" {
+			return nil
+		}
+		if _, ok := vars[e.Name]; !ok {
+			return fmt.Errorf("unknown variable %s", e.Name)
+		}
+	case *ast.ParenExpr:
+		return check(e.X, vars)
+	case *ast.UnaryExpr:
+		if e.Op != token.NOT && e.Op != token.SUB {
+			return fmt.Errorf("unsupported operator %s", e.Op)
+		}
+		return check(e.X, vars)
+	case *ast.BinaryExpr:
+		switch e.Op {
+		case token.LAND, token.LOR, token.EQL, token.NEQ, token.LSS, token.LEQ, token.GTR, token.GEQ:
+		default:
+			return fmt.Errorf("unsupported operator %s", e.Op)
+		}
+		if err := check(e.X, vars); err != nil {
+			return err
+		}
+		return check(e.Y, vars)
+	case *ast.CallExpr:
+		fn, ok := e.Fun.(*ast.Ident)
+		if !ok {
+			return fmt.Errorf("unsupported function call")
+		}
+		argc, ok := functions[fn.Name]
+		if !ok {
+			return fmt.Errorf("unknown function %s", fn.Name)
+		}
+		if len(e.Args) != argc || e.Ellipsis.IsValid() {
+			return fmt.Errorf("function %s requires %d arguments", fn.Name, argc)
+		}
+		for _, arg := range e.Args {
+			if err := check(arg, vars); err != nil {
+				return err
+			}
+		}
+		if fn.Name == "match" {
+			lit, ok := e.Args[0].(*ast.BasicLit)
+			if !ok || lit.Kind != token.STRING {
+				return fmt.Errorf("the pattern of match must be a string literal")
+			}
+			pattern, err := strconv.Unquote(lit.Value)
+			if err != nil {
+				return err
+			}
+			if _, err := compileRegexp(pattern); err != nil {
+				return fmt.Errorf("invalid pattern of match: %v", err)
+			}
+		}
+	default:
+		return fmt.Errorf("unsupported syntax %T", expr)
+	}
+	return nil
+}
+
+// Eval returns the result of expression, the result must be bool.
+func (e *Expression) Eval(env Env) (bool, error) {
+	v, err := eval(e.expr, env)
+	if err != nil {
+		return false, err
+	}
+	b, ok := v.(bool)
+	if !ok {
+		return false, fmt.Errorf("the result of expression is %T, not bool", v)
+	}
+	return b, nil
+}
+
+func (e *Expression) String() string {
+	return e.text
+}
+
+func eval(expr ast.Expr, env Env) (interface{}, error) {
+	switch e := expr.(type) {
+	case *ast.BasicLit:
+		switch e.Kind {
+		case token.STRING:
+			return strconv.Unquote(e.Value)
+		case token.INT:
+			return strconv.ParseInt(e.Value, 0, 64)
+		default:
+			return strconv.ParseFloat(e.Value, 64)
+		}
+	case *ast.Ident:
+		switch e.Name {
+		case "true":
+			return true, nil
+		case "false":
+			return false, nil
+		}
+		v, ok := env[e.Name]
+		if !ok {
+			return nil, fmt.Errorf("unknown variable %s", e.Name)
+		}
+		return v, nil
+	case *ast.ParenExpr:
+		return eval(e.X, env)
+	case *ast.UnaryExpr:
+		v, err := eval(e.X, env)
+		if err != nil {
+			return nil, err
+		}
+		switch e.Op {
+		case token.NOT:
+			b, ok := v.(bool)
+			if !ok {
+				return nil, fmt.Errorf("operator ! requires bool, but got %T", v)
+			}
+			return !b, nil
+		default:
+			n, ok := toFloat(v)
+			if !ok {
+				return nil, fmt.Errorf("operator - requires number, but got %T", v)
+			}
+			return -n, nil
+		}
+	case *ast.BinaryExpr:
+		return evalBinary(e, env)
+	case *ast.CallExpr:
+		args := make([]interface{}, len(e.Args))
+		for i, arg := range e.Args {
+			v, err := eval(arg, env)
+			if err != nil {
+				return nil, err
+			}
+			args[i] = v
+		}
+		return call(e.Fun.(*ast.Ident).Name, args)
+	}
+	return nil, fmt.Errorf("unsupported syntax %T", expr)
+}
+
+func evalBinary(e *ast.BinaryExpr, env Env) (interface{}, error) {
+	x, err := eval(e.X, env)
+	if err != nil {
+		return nil, err
+	}
+
+	// short circuit
+	if e.Op == token.LAND || e.Op == token.LOR {
+		bx, ok := x.(bool)
+		if !ok {
+			return nil, fmt.Errorf("operator %s requires bool, but got %T", e.Op, x)
+		}
+		if (e.Op == token.LAND && !bx) || (e.Op == token.LOR && bx) {
+			return bx, nil
+		}
+		y, err := eval(e.Y, env)
+		if err != nil {
+			return nil, err
+		}
+		by, ok := y.(bool)
+		if !ok {
+			return nil, fmt.Errorf("operator %s requires bool, but got %T", e.Op, y)
+		}
+		return by, nil
+	}
+
+	y, err := eval(e.Y, env)
+	if err != nil {
+		return nil, err
+	}
+
+	if nx, ok := toFloat(x); ok {
+		ny, ok := toFloat(y)
+		if !ok {
+			return nil, fmt.Errorf("mismatched types %T and %T", x, y)
+		}
+		return compare(e.Op, nx < ny, nx == ny)
+	}
+	switch vx := x.(type) {
+	case string:
+		vy, ok := y.(string)
+		if !ok {
+			return nil, fmt.Errorf("mismatched types %T and %T", x, y)
+		}
+		return compare(e.Op, vx < vy, vx == vy)
+	case bool:
+		vy, ok := y.(bool)
+		if !ok {
+			return nil, fmt.Errorf("mismatched types %T and %T", x, y)
+		}
+		if e.Op != token.EQL && e.Op != token.NEQ {
+			return nil, fmt.Errorf("operator %s is not defined on bool", e.Op)
+		}
+		return compare(e.Op, false, vx == vy)
+	}
+	return nil, fmt.Errorf("operator %s is not defined on %T", e.Op, x)
+}
+
+func compare(op token.Token, less, equal bool) (bool, error) {
+	switch op {
+	case token.EQL:
+		return equal, nil
+	case token.NEQ:
+		return !equal, nil
+	case token.LSS:
+		return less, nil
+	case token.LEQ:
+		return less || equal, nil
+	case token.GTR:
+		return !less && !equal, nil
+	case token.GEQ:
+		return !less, nil
+	}
+	return false, fmt.Errorf("unsupported operator %s", op)
+}
+
+func toFloat(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case int64:
+		return float64(n), true
+	case int:
+		return float64(n), true
+	case float64:
+		return n, true
+	}
+	return 0, false
+}
+
+func call(name string, args []interface{}) (interface{}, error) {
+	switch name {
+	case "len":
+		switch v := args[0].(type) {
+		case string:
+			return int64(len(v)), nil
+		case []string:
+			return int64(len(v)), nil
+		}
+		return nil, fmt.Errorf("len requires string or list, but got %T", args[0])
+	case "lower", "upper":
+		s, ok := args[0].(string)
+		if !ok {
+			return nil, fmt.Errorf("%s requires string, but got %T", name, args[0])
+		}
+		if name == "lower" {
+			return strings.ToLower(s), nil
+		}
+		return strings.ToUpper(s), nil
+	case "contains":
+		s, ok := args[1].(string)
+		if !ok {
+			return nil, fmt.Errorf("contains requires string as the second argument, but got %T", args[1])
+		}
+		switch v := args[0].(type) {
+		case string:
+			return strings.Contains(strings.ToLower(v), strings.ToLower(s)), nil
+		case []string:
+			for _, item := range v {
+				if strings.EqualFold(item, s) {
+					return true, nil
+				}
+			}
+			return false, nil
+		}
+		return nil, fmt.Errorf("contains requires string or list, but got %T", args[0])
+	case "hasPrefix", "hasSuffix":
+		s, ok1 := args[0].(string)
+		affix, ok2 := args[1].(string)
+		if !ok1 || !ok2 {
+			return nil, fmt.Errorf("%s requires string arguments", name)
+		}
+		s, affix = strings.ToLower(s), strings.ToLower(affix)
+		if name == "hasPrefix" {
+			return strings.HasPrefix(s, affix), nil
+		}
+		return strings.HasSuffix(s, affix), nil
+	case "match":
+		pattern, ok := args[0].(string)
+		if !ok {
+			return nil, fmt.Errorf("match requires string pattern, but got %T", args[0])
+		}
+		re, err := compileRegexp(pattern)
+		if err != nil {
+			return nil, err
+		}
+		switch v := args[1].(type) {
+		case string:
+			return re.MatchString(v), nil
+		case []string:
+			for _, item := range v {
+				if re.MatchString(item) {
+					return true, nil
+				}
+			}
+			return false, nil
+		}
+		return nil, fmt.Errorf("match requires string or list, but got %T", args[1])
+	}
+	return nil, fmt.Errorf("unknown function %s", name)
+}
+
+var regexpCache sync.Map
+
+// compileRegexp caches the compiled regular expressions, the patterns of match are literals,
+// so the cache is bounded by the rules.
+func compileRegexp(pattern string) (*regexp.Regexp, error) {
+	if re, ok := regexpCache.Load(pattern); ok {
+		return re.(*regexp.Regexp), nil
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, err
+	}
+	regexpCache.Store(pattern, re)
+	return re, nil
+}
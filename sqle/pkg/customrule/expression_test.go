@@ -0,0 +1,86 @@
+package customrule
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompileExpression(t *testing.T) {
+	for _, text := range []string{
+		`sql_type == "dml"`,
+		`!match("(?i)\\bwhere\\b", sql) && sql_type == "dml"`,
+		`len(tables) > 3 || contains(tables, "t1")`,
+		`hasPrefix(lower(sql), "drop") && db_type != "MySQL"`,
+		`(len(sql) >= -1) == true`,
+	} {
+		_, err := CompileExpression(text, Variables)
+		assert.NoError(t, err, text)
+	}
+
+	for _, text := range []string{
+		``,
+		`sql_type = "dml"`,
+		`unknown == "a"`,
+		`os.Exit(1)`,
+		`exec("rm -rf /")`,
+		`len(sql, sql)`,
+		`match(sql, "a")`,
+		`match("(", sql)`,
+		`func() bool { return true }()`,
+		`tables[0] == "t1"`,
+		`len(sql) + 1 > 2`,
+		`'a' == sql`,
+		strings.Repeat("true && ", MaxExpressionLength) + "true",
+	} {
+		_, err := CompileExpression(text, Variables)
+		assert.Error(t, err, text)
+	}
+}
+
+func TestExpression_Eval(t *testing.T) {
+	env := Env{
+		VarSQL:         "DELETE FROM t1",
+		VarSQLType:     SQLTypeDML,
+		VarTables:      []string{"t1"},
+		VarFingerprint: "DELETE FROM t1",
+		VarDBType:      "PostgreSQL",
+	}
+	cases := map[string]bool{
+		`sql_type == "dml" && !match("(?i)\\bwhere\\b", sql)`: true,
+		`sql_type == "ddl" && unknownFuncIsNotEvaluated`:      false,
+		`contains(tables, "T1")`:                              true,
+		`contains(sql, "where")`:                              false,
+		`len(tables) == 1 && len(sql) > 10.5`:                 true,
+		`hasPrefix(sql, "delete") && hasSuffix(sql, "T1")`:    true,
+		`upper(db_type) == "POSTGRESQL"`:                      true,
+		`match("^t\\d$", tables)`:                             true,
+		`db_type < "Q" || false`:                              true,
+	}
+	for text, expected := range cases {
+		vars := append([]string{"unknownFuncIsNotEvaluated"}, Variables...)
+		expr, err := CompileExpression(text, vars)
+		if !assert.NoError(t, err, text) {
+			continue
+		}
+		actual, err := expr.Eval(env)
+		assert.NoError(t, err, text)
+		assert.Equal(t, expected, actual, text)
+	}
+
+	for _, text := range []string{
+		`sql`,
+		`len(sql)`,
+		`sql == 1`,
+		`!sql`,
+		`sql_type == "dml" && len(sql)`,
+	} {
+		expr, err := CompileExpression(text, Variables)
+		if !assert.NoError(t, err, text) {
+			continue
+		}
+		_, err = expr.Eval(env)
+		assert.Error(t, err, text)
+	}
+}
@@ -0,0 +1,125 @@
+package customrule
+
+import (
+	"regexp"
+	"strings"
+)
+
+// The statement types of SQL which are available as the variable sql_type of expression.
+const (
+	SQLTypeDDL   = "ddl"
+	SQLTypeDML   = "dml"
+	SQLTypeDQL   = "dql"
+	SQLTypeDCL   = "dcl"
+	SQLTypeOther = "other"
+)
+
+// The variables of expression.
+const (
+	VarSQL         = "sql"
+	VarSQLType     = "sql_type"
+	VarTables      = "tables"
+	VarFingerprint = "fingerprint"
+	VarDBType      = "db_type"
+)
+
+var Variables = []string{VarSQL, VarSQLType, VarTables, VarFingerprint, VarDBType}
+
+// SQLInfo is the information of SQL which the custom rules are evaluated on.
+type SQLInfo struct {
+	SQL         string
+	Type        string
+	Tables      []string
+	Fingerprint string
+	DBType      string
+}
+
+func (i *SQLInfo) Env() Env {
+	tables := i.Tables
+	if tables == nil {
+		tables = []string{}
+	}
+	return Env{
+		VarSQL:         i.SQL,
+		VarSQLType:     i.Type,
+		VarTables:      tables,
+		VarFingerprint: i.Fingerprint,
+		VarDBType:      i.DBType,
+	}
+}
+
+var (
+	commentRegexp    = regexp.MustCompile(`(?s)/\*.*?\*/|--[^\n]*|#[^\n]*`)
+	firstWordRegexp  = regexp.MustCompile(`^[\s(]*([A-Za-z]+)`)
+	literalRegexp    = regexp.MustCompile(`'(?:[^'\\]|\\.|'')*'|\b\d+(?:\.\d+)?\b`)
+	inListRegexp     = regexp.MustCompile(`(?i)\bIN\s*\(\s*\?(?:\s*,\s*\?)*\s*\)`)
+	whitespaceRegexp = regexp.MustCompile(`\s+`)
+	tableRegexp      = regexp.MustCompile(`(?i)\b(?:FROM|JOIN|UPDATE|INTO|TABLE(?:\s+IF\s+(?:NOT\s+)?EXISTS)?)\s+((?:[A-Za-z_][\w$]*|` +
+		"`[^`]+`" + `|"[^"]+"|\[[^\]]+\])(?:\s*\.\s*(?:[A-Za-z_][\w$]*|` + "`[^`]+`" + `|"[^"]+"|\[[^\]]+\]))*)`)
+	identifierQuotes = "`\"[]"
+)
+
+var sqlTypes = map[string]string{
+	"CREATE": SQLTypeDDL, "ALTER": SQLTypeDDL, "DROP": SQLTypeDDL, "TRUNCATE": SQLTypeDDL,
+	"RENAME": SQLTypeDDL, "COMMENT": SQLTypeDDL,
+	"INSERT": SQLTypeDML, "UPDATE": SQLTypeDML, "DELETE": SQLTypeDML, "REPLACE": SQLTypeDML,
+	"MERGE": SQLTypeDML, "UPSERT": SQLTypeDML, "LOAD": SQLTypeDML, "COPY": SQLTypeDML,
+	"SELECT": SQLTypeDQL, "WITH": SQLTypeDQL, "SHOW": SQLTypeDQL, "EXPLAIN": SQLTypeDQL,
+	"DESC": SQLTypeDQL, "DESCRIBE": SQLTypeDQL, "VALUES": SQLTypeDQL,
+	"GRANT": SQLTypeDCL, "REVOKE": SQLTypeDCL,
+}
+
+// NewSQLInfo extracts the information of SQL by lexical analysis, it does not depend on the parser of
+// database, so it works for all DB types, the result may be inaccurate for the complicated SQL.
+func NewSQLInfo(dbType, sql string) *SQLInfo {
+	stripped := strings.TrimSpace(commentRegexp.ReplaceAllString(sql, " "))
+	return &SQLInfo{
+		SQL:         sql,
+		Type:        Classify(stripped),
+		Tables:      ExtractTables(stripped),
+		Fingerprint: Fingerprint(stripped),
+		DBType:      dbType,
+	}
+}
+
+// Classify returns the statement type by the first keyword of SQL.
+func Classify(sql string) string {
+	matches := firstWordRegexp.FindStringSubmatch(sql)
+	if matches == nil {
+		return SQLTypeOther
+	}
+	if typ, ok := sqlTypes[strings.ToUpper(matches[1])]; ok {
+		return typ
+	}
+	return SQLTypeOther
+}
+
+// Fingerprint replaces the literals of SQL with "?" and collapses the whitespaces.
+func Fingerprint(sql string) string {
+	fp := literalRegexp.ReplaceAllString(sql, "?")
+	fp = inListRegexp.ReplaceAllString(fp, "IN (?)")
+	fp = whitespaceRegexp.ReplaceAllString(fp, " ")
+	return strings.TrimRight(strings.TrimSpace(fp), ";")
+}
+
+// ExtractTables returns the deduplicated tables referenced by SQL, the quotes of identifier are removed.
+func ExtractTables(sql string) []string {
+	// the literal may contain the keywords, e.g. "select 'from a'"
+	sql = literalRegexp.ReplaceAllString(sql, "?")
+
+	var tables []string
+	seen := map[string]struct{}{}
+	for _, matches := range tableRegexp.FindAllStringSubmatch(sql, -1) {
+		parts := strings.Split(matches[1], ".")
+		for i := range parts {
+			parts[i] = strings.Trim(strings.TrimSpace(parts[i]), identifierQuotes)
+		}
+		table := strings.Join(parts, ".")
+		if _, ok := seen[strings.ToLower(table)]; ok {
+			continue
+		}
+		seen[strings.ToLower(table)] = struct{}{}
+		tables = append(tables, table)
+	}
+	return tables
+}
@@ -0,0 +1,49 @@
+package customrule
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClassify(t *testing.T) {
+	cases := map[string]string{
+		"create table t1(id int)":              SQLTypeDDL,
+		"  ALTER TABLE t1 ADD c1 int":          SQLTypeDDL,
+		"insert into t1 values (1)":            SQLTypeDML,
+		"(select 1) union (select 2)":          SQLTypeDQL,
+		"with t as (select 1) select * from t": SQLTypeDQL,
+		"grant select on t1 to u1":             SQLTypeDCL,
+		"begin":                                SQLTypeOther,
+		"":                                     SQLTypeOther,
+	}
+	for sql, expected := range cases {
+		assert.Equal(t, expected, Classify(sql), sql)
+	}
+}
+
+func TestExtractTables(t *testing.T) {
+	cases := map[string][]string{
+		"select * from t1 join `db1`.`t2` on t1.id = t2.id where v = 'from t3'": {"t1", "db1.t2"},
+		`update "public"."T1" set v = 1`:                                        {"public.T1"},
+		"insert into [dbo].[t1] select * from T1":                               {"dbo.t1", "T1"},
+		"create table if not exists t1 (id int)":                                {"t1"},
+		"drop table t1":                                                         {"t1"},
+		"select 1":                                                              nil,
+	}
+	for sql, expected := range cases {
+		assert.Equal(t, expected, ExtractTables(sql), sql)
+	}
+}
+
+func TestNewSQLInfo(t *testing.T) {
+	info := NewSQLInfo("PostgreSQL", "/* hint */ SELECT *\n  FROM t1 -- comment\n WHERE id IN (1, 2, 3) AND name = 'a''b';")
+	assert.Equal(t, SQLTypeDQL, info.Type)
+	assert.Equal(t, []string{"t1"}, info.Tables)
+	assert.Equal(t, "SELECT * FROM t1 WHERE id IN (?) AND name = ?", info.Fingerprint)
+	assert.Equal(t, "PostgreSQL", info.DBType)
+
+	env := NewSQLInfo("PostgreSQL", "vacuum").Env()
+	assert.Equal(t, []string{}, env[VarTables])
+	assert.Equal(t, SQLTypeOther, env[VarSQLType])
+}
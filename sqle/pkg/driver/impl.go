@@ -277,6 +277,10 @@ func (p *DriverImpl) EstimateSQLAffectRows(ctx context.Context, sql string) (*dr
 	return &driverV2.EstimatedAffectRows{}, nil
 }
 
+func (p *DriverImpl) ListTables(ctx context.Context, conf *driverV2.ListTablesConf) (*driverV2.ListTablesResult, error) {
+	return &driverV2.ListTablesResult{}, nil
+}
+
 func (p *DriverImpl) KillProcess(ctx context.Context) (*driverV2.KillProcessInfo, error) {
 	return &driverV2.KillProcessInfo{}, nil
 }
@@ -0,0 +1,123 @@
+package oauth2
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"fmt"
+	"math/big"
+	"net/http"
+
+	"github.com/golang-jwt/jwt"
+)
+
+// jwk is a public key of JSON Web Key Set (RFC 7517), only the RSA and EC keys are supported.
+type jwk struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+func decodeBigInt(s string) (*big.Int, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+	return new(big.Int).SetBytes(b), nil
+}
+
+func (k *jwk) publicKey() (interface{}, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := decodeBigInt(k.N)
+		if err != nil {
+			return nil, fmt.Errorf("decode modulus of key %s failed: %v", k.Kid, err)
+		}
+		e, err := decodeBigInt(k.E)
+		if err != nil {
+			return nil, fmt.Errorf("decode exponent of key %s failed: %v", k.Kid, err)
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("curve %s of key %s is not supported", k.Crv, k.Kid)
+		}
+		x, err := decodeBigInt(k.X)
+		if err != nil {
+			return nil, fmt.Errorf("decode x of key %s failed: %v", k.Kid, err)
+		}
+		y, err := decodeBigInt(k.Y)
+		if err != nil {
+			return nil, fmt.Errorf("decode y of key %s failed: %v", k.Kid, err)
+		}
+		return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+	default:
+		return nil, fmt.Errorf("key type %s of key %s is not supported", k.Kty, k.Kid)
+	}
+}
+
+func (c *Config) fetchJWKS(ctx context.Context) ([]jwk, error) {
+	ctx, cancel := context.WithTimeout(ctx, requestTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.JWKSURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	set := struct {
+		Keys []jwk `json:"keys"`
+	}{}
+	if err := doJSONRequest(req, &set); err != nil {
+		return nil, fmt.Errorf("get jwks failed: %v", err)
+	}
+	return set.Keys, nil
+}
+
+// verifyIDToken verifies the signature of ID token by the key of JWKS whose id is the same as the
+// "kid" of token, the expiry is also checked.
+func (c *Config) verifyIDToken(ctx context.Context, idToken string) (Claims, error) {
+	keys, err := c.fetchJWKS(ctx)
+	if err != nil {
+		return nil, err
+	}
+	claims := jwt.MapClaims{}
+	_, err = jwt.ParseWithClaims(idToken, claims, func(t *jwt.Token) (interface{}, error) {
+		var kty string
+		switch t.Method.(type) {
+		case *jwt.SigningMethodRSA, *jwt.SigningMethodRSAPSS:
+			kty = "RSA"
+		case *jwt.SigningMethodECDSA:
+			kty = "EC"
+		default:
+			return nil, fmt.Errorf("signing method %v of id token is not supported", t.Header["alg"])
+		}
+		kid, _ := t.Header["kid"].(string)
+		for i := range keys {
+			key := keys[i]
+			if key.Kty != kty || (key.Use != "" && key.Use != "sig") || (kid != "" && key.Kid != kid) {
+				continue
+			}
+			return key.publicKey()
+		}
+		return nil, fmt.Errorf("key %s of id token is not found in jwks", kid)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("verify id token failed: %v", err)
+	}
+	return Claims(claims), nil
+}
@@ -0,0 +1,257 @@
+// Package oauth2 implements the client of OAuth2 authorization code flow with PKCE (RFC 7636),
+// the user info is read from the verified OIDC ID token and the user info endpoint.
+package oauth2
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const requestTimeout = 30 * time.Second
+
+type Config struct {
+	ClientID     string
+	ClientSecret string
+	RedirectURL  string
+	AuthURL      string
+	TokenURL     string
+	// UserInfoURL is optional if the claims are in ID token.
+	UserInfoURL string
+	// JWKSURL is the key set which verifies the signature of ID token, the ID token is ignored if
+	// it is empty.
+	JWKSURL string
+	// Issuer is the expected "iss" of ID token.
+	Issuer string
+	Scopes []string
+	// AccessTokenTag is the field of access token in the token response, default is "access_token".
+	AccessTokenTag string
+}
+
+// RandomString returns a URL safe random string which is used as state, nonce and code verifier.
+func RandomString() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// CodeChallenge returns the S256 code challenge of the code verifier.
+func CodeChallenge(verifier string) string {
+	sum := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+// AuthCodeURL returns the URL of identity provider to which the user is redirected to login.
+func (c *Config) AuthCodeURL(state, nonce, verifier string) (string, error) {
+	u, err := url.Parse(c.AuthURL)
+	if err != nil {
+		return "", fmt.Errorf("parse auth url failed: %v", err)
+	}
+	q := u.Query()
+	q.Set("response_type", "code")
+	q.Set("client_id", c.ClientID)
+	q.Set("redirect_uri", c.RedirectURL)
+	q.Set("state", state)
+	q.Set("code_challenge", CodeChallenge(verifier))
+	q.Set("code_challenge_method", "S256")
+	if len(c.Scopes) > 0 {
+		q.Set("scope", strings.Join(c.Scopes, " "))
+	}
+	if nonce != "" {
+		q.Set("nonce", nonce)
+	}
+	u.RawQuery = q.Encode()
+	return u.String(), nil
+}
+
+type Token struct {
+	AccessToken string
+	IDToken     string
+}
+
+// Exchange exchanges the authorization code for the token.
+func (c *Config) Exchange(ctx context.Context, code, verifier string) (*Token, error) {
+	form := url.Values{}
+	form.Set("grant_type", "authorization_code")
+	form.Set("code", code)
+	form.Set("redirect_uri", c.RedirectURL)
+	form.Set("client_id", c.ClientID)
+	form.Set("code_verifier", verifier)
+	if c.ClientSecret != "" {
+		form.Set("client_secret", c.ClientSecret)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, requestTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.TokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	body := map[string]interface{}{}
+	if err := doJSONRequest(req, &body); err != nil {
+		return nil, fmt.Errorf("exchange token failed: %v", err)
+	}
+
+	tag := c.AccessTokenTag
+	if tag == "" {
+		tag = "access_token"
+	}
+	token := &Token{
+		AccessToken: Claims(body).String(tag),
+		IDToken:     Claims(body).String("id_token"),
+	}
+	if token.AccessToken == "" {
+		return nil, fmt.Errorf("exchange token failed: %s is not in the response", tag)
+	}
+	return token, nil
+}
+
+// Claims returns the claims of user from ID token and user info endpoint, the claims of user info
+// endpoint take precedence. The ID token is only used when its signature is verified by JWKS, and
+// the issuer, the audience, the expiry and the nonce are checked. The user info must be of the
+// same subject as the ID token (OIDC Core 5.3.2), otherwise the user info may be substituted.
+func (c *Config) Claims(ctx context.Context, token *Token, nonce string) (Claims, error) {
+	if c.JWKSURL == "" && c.UserInfoURL == "" {
+		return nil, fmt.Errorf("neither jwks url nor user info url is configured")
+	}
+	claims := Claims{}
+	if token.IDToken != "" && c.JWKSURL != "" {
+		idClaims, err := c.verifyIDToken(ctx, token.IDToken)
+		if err != nil {
+			return nil, err
+		}
+		if err := idClaims.validate(c.Issuer, c.ClientID, nonce, time.Now()); err != nil {
+			return nil, err
+		}
+		for k, v := range idClaims {
+			claims[k] = v
+		}
+	}
+	if c.UserInfoURL != "" {
+		ctx, cancel := context.WithTimeout(ctx, requestTimeout)
+		defer cancel()
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.UserInfoURL, nil)
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Authorization", "Bearer "+token.AccessToken)
+		req.Header.Set("Accept", "application/json")
+		userInfo := map[string]interface{}{}
+		if err := doJSONRequest(req, &userInfo); err != nil {
+			return nil, fmt.Errorf("get user info failed: %v", err)
+		}
+		if sub := claims.String("sub"); sub != "" && Claims(userInfo).String("sub") != sub {
+			return nil, fmt.Errorf("sub of user info does not match the id token")
+		}
+		for k, v := range userInfo {
+			claims[k] = v
+		}
+	}
+	return claims, nil
+}
+
+func doJSONRequest(req *http.Request, v interface{}) error {
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("status: %s, body: %s", resp.Status, body)
+	}
+	return json.Unmarshal(body, v)
+}
+
+// Claims are the claims of user, the value is read by the path separated by ".", e.g.
+// "realm_access.roles".
+type Claims map[string]interface{}
+
+func (c Claims) get(path string) interface{} {
+	var v interface{} = map[string]interface{}(c)
+	for _, key := range strings.Split(path, ".") {
+		m, ok := v.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		v = m[key]
+	}
+	return v
+}
+
+func (c Claims) String(path string) string {
+	switch v := c.get(path).(type) {
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case nil:
+		return ""
+	default:
+		return fmt.Sprintf("%v", v)
+	}
+}
+
+// Strings reads the claim of array, the string is split by ",". The leading "/" of full group
+// path (e.g. "/dba" of Keycloak) is removed.
+func (c Claims) Strings(path string) []string {
+	values := []string{}
+	switch v := c.get(path).(type) {
+	case []interface{}:
+		for _, item := range v {
+			if s, ok := item.(string); ok && s != "" {
+				values = append(values, strings.TrimPrefix(s, "/"))
+			}
+		}
+	case string:
+		for _, s := range strings.Split(v, ",") {
+			if s = strings.TrimSpace(s); s != "" {
+				values = append(values, strings.TrimPrefix(s, "/"))
+			}
+		}
+	}
+	return values
+}
+
+func (c Claims) validate(issuer, clientID, nonce string, now time.Time) error {
+	if issuer == "" || c.String("iss") != issuer {
+		return fmt.Errorf("issuer of id token does not match")
+	}
+	audienceMatched := false
+	switch aud := c.get("aud").(type) {
+	case string:
+		audienceMatched = aud == clientID
+	case []interface{}:
+		for _, item := range aud {
+			if item == clientID {
+				audienceMatched = true
+			}
+		}
+	}
+	if !audienceMatched {
+		return fmt.Errorf("audience of id token does not match the client id")
+	}
+	if exp, ok := c.get("exp").(float64); !ok || now.Unix() > int64(exp) {
+		return fmt.Errorf("id token is expired")
+	}
+	if nonce != "" && c.String("nonce") != nonce {
+		return fmt.Errorf("nonce of id token does not match")
+	}
+	return nil
+}
@@ -0,0 +1,212 @@
+package oauth2
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt"
+	"github.com/stretchr/testify/assert"
+)
+
+// mockIdP is an identity provider which supports authorization code flow with PKCE.
+type mockIdP struct {
+	server        *httptest.Server
+	codeChallenge string
+	nonce         string
+	idTokenClaims map[string]interface{}
+	// signingKey signs the ID token, it is not the key in JWKS if the token is forged.
+	signingKey *rsa.PrivateKey
+	jwksKey    *rsa.PrivateKey
+}
+
+func newMockIdP(t *testing.T) *mockIdP {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+	idp := &mockIdP{signingKey: key, jwksKey: key}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		assert.NoError(t, r.ParseForm())
+		if r.Form.Get("code") != "code" || r.Form.Get("client_secret") != "secret" ||
+			CodeChallenge(r.Form.Get("code_verifier")) != idp.codeChallenge {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"error":"invalid_grant"}`))
+			return
+		}
+		claims := jwt.MapClaims{
+			"iss":   idp.server.URL,
+			"sub":   "user-1",
+			"aud":   "sqle",
+			"exp":   time.Now().Add(time.Minute).Unix(),
+			"nonce": idp.nonce,
+		}
+		for k, v := range idp.idTokenClaims {
+			claims[k] = v
+		}
+		token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+		token.Header["kid"] = "key-1"
+		idToken, err := token.SignedString(idp.signingKey)
+		assert.NoError(t, err)
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"access_token": "access-token",
+			"token_type":   "Bearer",
+			"id_token":     idToken,
+		})
+	})
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, r *http.Request) {
+		pub := idp.jwksKey.PublicKey
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"keys": []map[string]string{{
+				"kty": "RSA",
+				"kid": "key-1",
+				"use": "sig",
+				"n":   base64.RawURLEncoding.EncodeToString(pub.N.Bytes()),
+				"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes()),
+			}},
+		})
+	})
+	mux.HandleFunc("/userinfo", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer access-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		_, _ = w.Write([]byte(`{"sub":"user-1","preferred_username":"alice","email":"alice@example.com",` +
+			`"groups":["/dba","/dev"],"realm_access":{"roles":["admin"]}}`))
+	})
+	idp.server = httptest.NewServer(mux)
+	return idp
+}
+
+func (idp *mockIdP) config() *Config {
+	return &Config{
+		ClientID:     "sqle",
+		ClientSecret: "secret",
+		RedirectURL:  "http://sqle/v1/oauth2/callback",
+		AuthURL:      idp.server.URL + "/auth?kc_idp_hint=ldap",
+		TokenURL:     idp.server.URL + "/token",
+		UserInfoURL:  idp.server.URL + "/userinfo",
+		JWKSURL:      idp.server.URL + "/jwks",
+		Issuer:       idp.server.URL,
+		Scopes:       []string{"openid", "profile"},
+	}
+}
+
+func TestAuthorizationCodeFlow(t *testing.T) {
+	idp := newMockIdP(t)
+	defer idp.server.Close()
+	cfg := idp.config()
+
+	verifier, err := RandomString()
+	assert.NoError(t, err)
+	authURL, err := cfg.AuthCodeURL("state", "nonce", verifier)
+	assert.NoError(t, err)
+	u, err := url.Parse(authURL)
+	assert.NoError(t, err)
+	q := u.Query()
+	assert.Equal(t, "/auth", u.Path)
+	assert.Equal(t, "ldap", q.Get("kc_idp_hint"))
+	assert.Equal(t, "code", q.Get("response_type"))
+	assert.Equal(t, "sqle", q.Get("client_id"))
+	assert.Equal(t, "http://sqle/v1/oauth2/callback", q.Get("redirect_uri"))
+	assert.Equal(t, "openid profile", q.Get("scope"))
+	assert.Equal(t, "state", q.Get("state"))
+	assert.Equal(t, "nonce", q.Get("nonce"))
+	assert.Equal(t, "S256", q.Get("code_challenge_method"))
+	idp.codeChallenge = q.Get("code_challenge")
+	idp.nonce = q.Get("nonce")
+
+	// the code verifier does not match the code challenge
+	_, err = cfg.Exchange(context.Background(), "code", "another")
+	assert.Error(t, err)
+
+	token, err := cfg.Exchange(context.Background(), "code", verifier)
+	assert.NoError(t, err)
+	assert.Equal(t, "access-token", token.AccessToken)
+
+	claims, err := cfg.Claims(context.Background(), token, "nonce")
+	assert.NoError(t, err)
+	assert.Equal(t, "user-1", claims.String("sub"))
+	assert.Equal(t, "alice", claims.String("preferred_username"))
+	assert.Equal(t, "alice@example.com", claims.String("email"))
+	assert.Equal(t, []string{"dba", "dev"}, claims.Strings("groups"))
+	assert.Equal(t, []string{"admin"}, claims.Strings("realm_access.roles"))
+
+	_, err = cfg.Claims(context.Background(), token, "another")
+	assert.Error(t, err)
+
+	// the issuer does not match
+	cfg.Issuer = "http://another"
+	_, err = cfg.Claims(context.Background(), token, "nonce")
+	assert.Error(t, err)
+	cfg.Issuer = idp.server.URL
+
+	// the user info is not of the subject of ID token
+	idp.idTokenClaims = map[string]interface{}{"sub": "user-2"}
+	token, err = cfg.Exchange(context.Background(), "code", verifier)
+	assert.NoError(t, err)
+	_, err = cfg.Claims(context.Background(), token, "nonce")
+	assert.Error(t, err)
+	idp.idTokenClaims = nil
+
+	// the ID token is not signed by the key of JWKS
+	forgedKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+	idp.signingKey = forgedKey
+	token, err = cfg.Exchange(context.Background(), "code", verifier)
+	assert.NoError(t, err)
+	_, err = cfg.Claims(context.Background(), token, "nonce")
+	assert.Error(t, err)
+
+	// the ID token is ignored without JWKS
+	cfg.JWKSURL = ""
+	claims, err = cfg.Claims(context.Background(), token, "nonce")
+	assert.NoError(t, err)
+	assert.Equal(t, "alice", claims.String("preferred_username"))
+	cfg.UserInfoURL = ""
+	_, err = cfg.Claims(context.Background(), token, "nonce")
+	assert.Error(t, err)
+}
+
+func TestClaimsValidate(t *testing.T) {
+	now := time.Now()
+	exp := float64(now.Add(time.Minute).Unix())
+	cases := []struct {
+		claims Claims
+		valid  bool
+	}{
+		{Claims{"iss": "idp", "aud": "sqle", "exp": exp, "nonce": "n"}, true},
+		{Claims{"iss": "idp", "aud": []interface{}{"other", "sqle"}, "exp": exp, "nonce": "n"}, true},
+		{Claims{"iss": "other", "aud": "sqle", "exp": exp, "nonce": "n"}, false},
+		{Claims{"aud": "sqle", "exp": exp, "nonce": "n"}, false},
+		{Claims{"iss": "idp", "aud": "other", "exp": exp, "nonce": "n"}, false},
+		{Claims{"iss": "idp", "aud": "sqle", "exp": float64(now.Add(-time.Minute).Unix()), "nonce": "n"}, false},
+		{Claims{"iss": "idp", "aud": "sqle", "nonce": "n"}, false},
+		{Claims{"iss": "idp", "aud": "sqle", "exp": exp, "nonce": "x"}, false},
+	}
+	for i, c := range cases {
+		err := c.claims.validate("idp", "sqle", "n", now)
+		assert.Equal(t, c.valid, err == nil, "case %d", i)
+	}
+}
+
+func TestClaimsValue(t *testing.T) {
+	claims := Claims{
+		"id":     float64(1001),
+		"groups": "dba, dev,",
+		"nested": map[string]interface{}{"name": "alice"},
+	}
+	assert.Equal(t, "1001", claims.String("id"))
+	assert.Equal(t, "alice", claims.String("nested.name"))
+	assert.Equal(t, "", claims.String("nested.name.first"))
+	assert.Equal(t, "", claims.String("not_exist"))
+	assert.Equal(t, []string{"dba", "dev"}, claims.Strings("groups"))
+	assert.Equal(t, []string{}, claims.Strings("not_exist"))
+}
@@ -0,0 +1,29 @@
+package index
+
+import (
+	"fmt"
+	"strings"
+
+	driverV2 "github.com/actiontech/sqle/sqle/driver/v2"
+)
+
+// AdviceMessage returns the audit result message of the advice, the advice itself
+// is carried by the audit result as well, so the message is only for display.
+func AdviceMessage(a *driverV2.IndexAdvice) string {
+	var details []string
+	if a.CreateIndexSQL != "" {
+		details = append(details, fmt.Sprintf("参考语句：%s", a.CreateIndexSQL))
+	}
+	if a.CoveringIndexSQL != "" {
+		details = append(details, fmt.Sprintf("覆盖索引：%s", a.CoveringIndexSQL))
+	}
+	if a.EstimatedRows > 0 {
+		details = append(details, fmt.Sprintf("执行计划预估扫描行数：%d", a.EstimatedRows))
+	}
+
+	message := fmt.Sprintf("建议从表 %s 的以下列中 [%s] 选取合适的列添加索引", a.TableName, strings.Join(a.Columns, ","))
+	if len(details) > 0 {
+		message += "，" + strings.Join(details, "；")
+	}
+	return message
+}
@@ -0,0 +1,23 @@
+package index
+
+import (
+	"testing"
+
+	driverV2 "github.com/actiontech/sqle/sqle/driver/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAdviceMessage(t *testing.T) {
+	advice := &driverV2.IndexAdvice{
+		TableName:        "t1",
+		Columns:          []string{"a", "b"},
+		CreateIndexSQL:   "CREATE INDEX idx_t1_a_b ON t1 (a, b)",
+		CoveringIndexSQL: "CREATE INDEX idx_t1_a_b_c ON t1 (a, b, c)",
+		EstimatedRows:    1000,
+	}
+	assert.Equal(t, "建议从表 t1 的以下列中 [a,b] 选取合适的列添加索引，参考语句：CREATE INDEX idx_t1_a_b ON t1 (a, b)；覆盖索引：CREATE INDEX idx_t1_a_b_c ON t1 (a, b, c)；执行计划预估扫描行数：1000",
+		AdviceMessage(advice))
+
+	advice = &driverV2.IndexAdvice{TableName: "t1", Columns: []string{"a", "LEFT(`b`, 5)"}}
+	assert.Equal(t, "建议从表 t1 的以下列中 [a,LEFT(`b`, 5)] 选取合适的列添加索引", AdviceMessage(advice))
+}
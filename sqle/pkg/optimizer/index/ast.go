@@ -11,6 +11,12 @@ type SelectAST interface {
 	// it returns []string{"a", "b"}.
 	EqualPredicateColumnsInWhere() []string
 
+	// RangePredicateColumnsInWhere find the range predicate column in where clause.
+	//
+	// For example, the SQL: select * from t where a > 1 and b between 1 and 2;
+	// it returns []string{"a", "b"}.
+	RangePredicateColumnsInWhere() []string
+
 	// ColumnsInOrderBy find the columns in order by clause.
 	//
 	// For example, the SQL: select * from t order by a desc, b;
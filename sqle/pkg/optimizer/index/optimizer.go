@@ -74,7 +74,16 @@ func NewOptimizer(ctx *session.Context, opts ...optimizerOption) *Optimizer {
 
 // Optimize try him best to give three-star index advice for ast.
 func (o *Optimizer) Optimize(ast SelectAST) (columns []string, err error) {
-	columns = append(ast.EqualPredicateColumnsInWhere(), ast.ColumnsInOrderBy()...)
+	columns = ast.EqualPredicateColumnsInWhere()
+	// only the first range column narrows the index slice, the index columns
+	// after it can not be used to seek, so the other range columns are ignored.
+	for _, column := range ast.RangePredicateColumnsInWhere() {
+		if !utils.StringsContains(columns, column) {
+			columns = append(columns, column)
+			break
+		}
+	}
+	columns = append(columns, ast.ColumnsInOrderBy()...)
 
 	// todo 由于涉及的场景较复杂，暂时不检查select的字段
 	//columns = append(columns, ast.ColumnsInProjection()...)
@@ -101,6 +110,23 @@ func (o *Optimizer) Optimize(ast SelectAST) (columns []string, err error) {
 	return columns, nil
 }
 
+// CoveringColumns returns the columns of the index which deserves the third star,
+// it appends the columns in select projection to the index columns. It returns nil
+// if the projection can not be covered, e.g. select * from t.
+func (o *Optimizer) CoveringColumns(ast SelectAST, columns []string) []string {
+	projection := ast.ColumnsInProjection()
+	if len(projection) == 0 {
+		return nil
+	}
+
+	covering := utils.RemoveDuplicate(append(append([]string{}, columns...), projection...))
+	if len(covering) == len(columns) {
+		// the index columns already cover the projection.
+		return nil
+	}
+	return covering
+}
+
 func isColumnHasIndex(column string, constraints []*ast.Constraint) bool {
 	for _, constraint := range constraints {
 		for _, key := range constraint.Keys {
@@ -0,0 +1,129 @@
+// Package xlsx writes a single sheet workbook of Office Open XML (ECMA-376) with inline strings,
+// it is enough for exporting the tables which are opened by Excel and WPS.
+package xlsx
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"io"
+	"strconv"
+)
+
+// maxCellLength is the max number of UTF-16 code units in a cell of Excel, the longer text is
+// truncated, otherwise Excel reports the workbook is corrupted.
+const maxCellLength = 32767
+
+var staticParts = []struct {
+	name    string
+	content string
+}{
+	{"[Content_Types].xml", xml.Header + `<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">` +
+		`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>` +
+		`<Default Extension="xml" ContentType="application/xml"/>` +
+		`<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>` +
+		`<Override PartName="/xl/worksheets/sheet1.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>` +
+		`</Types>`},
+	{"_rels/.rels", xml.Header + `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+		`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>` +
+		`</Relationships>`},
+	{"xl/_rels/workbook.xml.rels", xml.Header + `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+		`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/>` +
+		`</Relationships>`},
+}
+
+// Writer writes the rows into the only sheet of workbook like csv.Writer, the workbook is complete
+// after Close.
+type Writer struct {
+	zw    *zip.Writer
+	sheet io.Writer
+	row   int
+}
+
+func NewWriter(w io.Writer, sheetName string) (*Writer, error) {
+	zw := zip.NewWriter(w)
+	for _, part := range staticParts {
+		f, err := zw.Create(part.name)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := io.WriteString(f, part.content); err != nil {
+			return nil, err
+		}
+	}
+
+	name := &bytes.Buffer{}
+	if err := xml.EscapeText(name, []byte(sheetName)); err != nil {
+		return nil, err
+	}
+	f, err := zw.Create("xl/workbook.xml")
+	if err != nil {
+		return nil, err
+	}
+	_, err = io.WriteString(f, xml.Header+`<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" `+
+		`xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">`+
+		`<sheets><sheet name="`+name.String()+`" sheetId="1" r:id="rId1"/></sheets></workbook>`)
+	if err != nil {
+		return nil, err
+	}
+
+	sheet, err := zw.Create("xl/worksheets/sheet1.xml")
+	if err != nil {
+		return nil, err
+	}
+	_, err = io.WriteString(sheet, xml.Header+`<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>`)
+	if err != nil {
+		return nil, err
+	}
+	return &Writer{zw: zw, sheet: sheet}, nil
+}
+
+// Write writes a row, all cells are strings.
+func (w *Writer) Write(record []string) error {
+	w.row++
+	rowNum := strconv.Itoa(w.row)
+	buf := &bytes.Buffer{}
+	buf.WriteString(`<row r="` + rowNum + `">`)
+	for i, value := range record {
+		buf.WriteString(`<c r="` + ColumnName(i) + rowNum + `" t="inlineStr"><is><t xml:space="preserve">`)
+		if err := xml.EscapeText(buf, []byte(truncate(value))); err != nil {
+			return err
+		}
+		buf.WriteString(`</t></is></c>`)
+	}
+	buf.WriteString(`</row>`)
+	_, err := w.sheet.Write(buf.Bytes())
+	return err
+}
+
+// Close completes the sheet and the workbook, it does not close the underlying writer.
+func (w *Writer) Close() error {
+	if _, err := io.WriteString(w.sheet, `</sheetData></worksheet>`); err != nil {
+		return err
+	}
+	return w.zw.Close()
+}
+
+// ColumnName returns the name of column by the index which starts from 0, e.g. "A", "Z", "AA".
+func ColumnName(index int) string {
+	name := []byte{}
+	for index++; index > 0; index = (index - 1) / 26 {
+		name = append([]byte{byte('A' + (index-1)%26)}, name...)
+	}
+	return string(name)
+}
+
+func truncate(value string) string {
+	length := 0
+	for i, r := range value {
+		length++
+		// the rune out of Basic Multilingual Plane is a surrogate pair in UTF-16
+		if r > 0xFFFF {
+			length++
+		}
+		if length > maxCellLength {
+			return value[:i]
+		}
+	}
+	return value
+}
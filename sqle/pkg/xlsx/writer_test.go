@@ -0,0 +1,70 @@
+package xlsx
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestColumnName(t *testing.T) {
+	for index, name := range map[int]string{0: "A", 25: "Z", 26: "AA", 51: "AZ", 52: "BA", 701: "ZZ", 702: "AAA"} {
+		assert.Equal(t, name, ColumnName(index), "index %d", index)
+	}
+}
+
+func TestWriter(t *testing.T) {
+	buf := &bytes.Buffer{}
+	w, err := NewWriter(buf, "工单<1>")
+	assert.NoError(t, err)
+	assert.NoError(t, w.Write([]string{"id", "sql"}))
+	assert.NoError(t, w.Write([]string{"1", "select * from t where a < 1 and b = '&'\n  -- \x00"}))
+	assert.NoError(t, w.Write([]string{"2", strings.Repeat("中", maxCellLength+1)}))
+	assert.NoError(t, w.Write([]string{"3", strings.Repeat("😀", maxCellLength/2+1)}))
+	assert.NoError(t, w.Close())
+
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	assert.NoError(t, err)
+	parts := map[string][]byte{}
+	for _, f := range zr.File {
+		r, err := f.Open()
+		assert.NoError(t, err)
+		parts[f.Name], err = ioutil.ReadAll(r)
+		assert.NoError(t, err)
+		assert.NoError(t, r.Close())
+
+		// all parts are well-formed
+		d := xml.NewDecoder(bytes.NewReader(parts[f.Name]))
+		for {
+			if _, err = d.Token(); err != nil {
+				break
+			}
+		}
+		assert.Equal(t, "EOF", err.Error(), f.Name)
+	}
+	for _, name := range []string{"[Content_Types].xml", "_rels/.rels", "xl/workbook.xml", "xl/_rels/workbook.xml.rels", "xl/worksheets/sheet1.xml"} {
+		assert.Contains(t, parts, name)
+	}
+	assert.Contains(t, string(parts["xl/workbook.xml"]), `name="工单&lt;1&gt;"`)
+
+	sheet := struct {
+		Rows []struct {
+			R     string `xml:"r,attr"`
+			Cells []struct {
+				R     string `xml:"r,attr"`
+				Value string `xml:"is>t"`
+			} `xml:"c"`
+		} `xml:"sheetData>row"`
+	}{}
+	assert.NoError(t, xml.Unmarshal(parts["xl/worksheets/sheet1.xml"], &sheet))
+	assert.Len(t, sheet.Rows, 4)
+	assert.Equal(t, "2", sheet.Rows[1].R)
+	assert.Equal(t, "B2", sheet.Rows[1].Cells[1].R)
+	assert.Equal(t, "select * from t where a < 1 and b = '&'\n  -- �", sheet.Rows[1].Cells[1].Value)
+	assert.Equal(t, maxCellLength, len([]rune(sheet.Rows[2].Cells[1].Value)))
+	assert.Equal(t, maxCellLength/2, len([]rune(sheet.Rows[3].Cells[1].Value)))
+}
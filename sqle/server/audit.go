@@ -147,6 +147,7 @@ func hookAudit(l *logrus.Entry, task *model.Task, p driver.Plugin, hook AuditHoo
 		if err != nil {
 			return err
 		}
+		executeSQL.SQLType = node.Type
 		var whitelistMatch bool
 		for _, wl := range whitelist {
 			if wl.MatchType == model.SQLWhitelistFPMatch {
@@ -342,6 +343,6 @@ func genRollbackSQL(l *logrus.Entry, task *model.Task, p driver.Plugin) ([]*mode
 func appendExecuteSqlResults(executeSQL *model.ExecuteSQL, result *driverV2.AuditResults) {
 	for i := range result.Results {
 		ar := result.Results[i]
-		executeSQL.AuditResults.Append(string(ar.Level), ar.RuleName, ar.Message)
+		executeSQL.AuditResults.AppendIndexAdvice(string(ar.Level), ar.RuleName, ar.Message, ar.IndexAdvice)
 	}
 }
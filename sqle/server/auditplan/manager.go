@@ -3,6 +3,7 @@ package auditplan
 import (
 	"context"
 	"fmt"
+	"strconv"
 	"time"
 
 	"github.com/actiontech/sqle/sqle/errors"
@@ -10,6 +11,7 @@ import (
 	"github.com/actiontech/sqle/sqle/model"
 	"github.com/actiontech/sqle/sqle/notification"
 	"github.com/actiontech/sqle/sqle/server"
+	"github.com/actiontech/sqle/sqle/server/cluster"
 	"github.com/robfig/cron/v3"
 	"github.com/sirupsen/logrus"
 )
@@ -32,12 +34,18 @@ func audit(auditPlanId uint, task Task) (*model.AuditPlanReportV2, error) {
 	}
 
 	for i, executeSQL := range taskResp.ExecuteSQLs {
-		auditPlanReport.AuditPlanReportSQLs = append(auditPlanReport.AuditPlanReportSQLs, &model.AuditPlanReportSQLV2{
+		reportSQL := &model.AuditPlanReportSQLV2{
 			SQL:          executeSQL.Content,
 			Number:       uint(i + 1),
 			AuditResults: executeSQL.AuditResults,
 			Schema:       executeSQL.Schema,
-		})
+		}
+		// the execute SQLs are generated from filtered SQLs in order
+		if i < len(auditResultResp.FilteredSqls) {
+			reportSQL.FingerprintMD5 = auditResultResp.FilteredSqls[i].GetFingerprintMD5()
+			reportSQL.Info = auditResultResp.FilteredSqls[i].Info
+		}
+		auditPlanReport.AuditPlanReportSQLs = append(auditPlanReport.AuditPlanReportSQLs, reportSQL)
 	}
 
 	s := model.GetStorage()
@@ -84,7 +92,8 @@ func GetSQLs(entry *logrus.Entry, ap *model.AuditPlan, args map[string]interface
 }
 
 func init() {
-	server.OnlyRunOnLeaderJobs = append(server.OnlyRunOnLeaderJobs, NewManager)
+	// audit plans are distributed to all nodes of cluster, see Manager.isOwner.
+	server.RunOnAllJobs = append(server.RunOnAllJobs, NewManager)
 }
 
 func NewManager(entry *logrus.Entry) server.ServerJob {
@@ -115,6 +124,10 @@ type Manager struct {
 
 	tasks map[uint] /* audit plan id*/ Task
 
+	// ring is used to distribute audit plans to the alive nodes on cluster mode,
+	// it is nil on non-cluster mode and all audit plans are run on current node.
+	ring *cluster.HashRing
+
 	lastSyncTime   *time.Time
 	isFullSyncDone bool
 
@@ -145,21 +158,42 @@ func (mgr *Manager) Start() {
 }
 
 func (mgr *Manager) sync() error {
-	// 全量同步智能扫描任务，仅需成功做一次
-	if !mgr.isFullSyncDone {
+	isNodesChanged, err := mgr.syncClusterNodes()
+	if err != nil {
+		return err
+	}
+	// 全量同步智能扫描任务，仅需成功做一次；集群节点变化时需要重新分配智能扫描任务
+	if !mgr.isFullSyncDone || isNodesChanged {
 		aps, err := mgr.persist.GetActiveAuditPlans()
 		if err != nil {
 			return err
 		}
 		mgr.isFullSyncDone = true
+		ownedIds := make(map[uint]struct{}, len(aps))
 		for _, v := range aps {
 			ap := v
+			if !mgr.isOwner(ap.ID) {
+				continue
+			}
+			ownedIds[ap.ID] = struct{}{}
+			if _, ok := mgr.tasks[ap.ID]; ok {
+				continue
+			}
 
 			err := mgr.startAuditPlan(ap)
 			if err != nil {
 				mgr.logger.WithField("name", ap.Name).Errorf("start audit task failed, error: %v", err)
 			}
 		}
+		for id := range mgr.tasks {
+			if _, ok := ownedIds[id]; ok {
+				continue
+			}
+			err := mgr.deleteAuditPlan(id)
+			if err != nil {
+				mgr.logger.WithField("id", id).Errorf("stop audit task moved to other node failed, error: %v", err)
+			}
+		}
 	}
 	// 增量同步智能扫描任务，根据数据库记录的更新时间筛选，更新后将下次筛选的时间为上一次记录的最晚的更新时间。
 	aps, err := mgr.persist.GetLatestAuditPlanRecords(*mgr.lastSyncTime)
@@ -178,6 +212,38 @@ func (mgr *Manager) sync() error {
 	return nil
 }
 
+// syncClusterNodes rebuilds the hash ring by the alive nodes, it returns true if the nodes have changed.
+func (mgr *Manager) syncClusterNodes() (bool, error) {
+	if !cluster.IsClusterMode {
+		return false, nil
+	}
+	nodes, err := mgr.persist.GetAliveClusterNodeIds(int(cluster.LeaseTimeout.Seconds()))
+	if err != nil {
+		return false, err
+	}
+	// no alive node is read, it is usually a transient failure since current node keeps
+	// alive by heartbeat, keep the last known ring so that the audit plans are not stopped.
+	if len(nodes) == 0 {
+		mgr.logger.Warnf("no alive cluster node is found, keep the last known nodes")
+		return false, nil
+	}
+	ring := cluster.NewHashRing(nodes)
+	if ring.Equal(mgr.ring) {
+		return false, nil
+	}
+	mgr.logger.Infof("cluster nodes changed to %v, rebalance audit plans", ring.Nodes())
+	mgr.ring = ring
+	return true, nil
+}
+
+// isOwner returns true if the audit plan should be run on current node.
+func (mgr *Manager) isOwner(auditPlanId uint) bool {
+	if mgr.ring == nil || len(mgr.ring.Nodes()) == 0 {
+		return true
+	}
+	return mgr.ring.Get(strconv.FormatUint(uint64(auditPlanId), 10)) == cluster.ServerId
+}
+
 func (mgr *Manager) Stop() {
 	mgr.exitCh <- struct{}{}
 	<-mgr.doneCh
@@ -198,7 +264,7 @@ func (mgr *Manager) syncTask(auditPlanId uint) error {
 	if err != nil {
 		return err
 	}
-	if !exist {
+	if !exist || !mgr.isOwner(auditPlanId) {
 		return mgr.deleteAuditPlan(auditPlanId)
 	} else {
 		return mgr.startAuditPlan(ap)
@@ -4,19 +4,243 @@
 package auditplan
 
 import (
-	e "errors"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
 
+	"github.com/actiontech/sqle/sqle/driver/mysql/util"
+	driverV2 "github.com/actiontech/sqle/sqle/driver/v2"
 	"github.com/actiontech/sqle/sqle/model"
+	"github.com/actiontech/sqle/sqle/pkg/customrule"
 )
 
+// sqlManageRecord is the SQL seen by a source, the records of the same SQL are merged into one
+// SqlManage of the ledger.
+type sqlManageRecord struct {
+	fingerprint string
+	sqlText     string
+	schema      string
+	// count is added to the occurrence count of the SQL.
+	count uint64
+	// initialCount is the occurrence count of the SQL if it is new to the ledger and count is 0.
+	initialCount uint64
+	receivedAt   time.Time
+	audited      bool
+	auditLevel   string
+	auditResults model.AuditResults
+}
+
+// syncSqlManage upserts the records into the ledger, the first appear time, the occurrence count,
+// the highest audit level and the triggered rules are accumulated.
+func syncSqlManage(projectId uint, source, instName string, auditPlanId, sqlAuditRecordId uint, records []*sqlManageRecord) error {
+	if len(records) == 0 {
+		return nil
+	}
+	s := model.GetStorage()
+
+	md5s := make([]string, 0, len(records))
+	for _, r := range records {
+		md5s = append(md5s, model.GenProjFpSourceInstSchemaMd5(projectId, r.fingerprint, source, instName, r.schema))
+	}
+	existedList, err := s.GetSqlManagesByProjFpSourceInstSchemaMd5s(md5s)
+	if err != nil {
+		return err
+	}
+	existed := make(map[string]*model.SqlManage, len(existedList))
+	for _, sm := range existedList {
+		existed[sm.ProjFpSourceInstSchemaMd5] = sm
+	}
+
+	merged := make(map[string]*model.SqlManage, len(records))
+	sqlManageList := make([]*model.SqlManage, 0, len(records))
+	for i, r := range records {
+		md5 := md5s[i]
+		sm, ok := merged[md5]
+		if !ok {
+			if sm, ok = existed[md5]; ok {
+				// only the increment of the occurrence count is saved, it is added to the stored count.
+				sm.FpCount = 0
+			} else {
+				receivedAt := r.receivedAt
+				sm = &model.SqlManage{
+					SqlFingerprint:            r.fingerprint,
+					ProjFpSourceInstSchemaMd5: md5,
+					Source:                    source,
+					FirstAppearTimestamp:      &receivedAt,
+					InstanceName:              instName,
+					SchemaName:                r.schema,
+					ProjectId:                 projectId,
+					AuditResults:              model.AuditResults{},
+				}
+				if r.count == 0 {
+					sm.FpCount = r.initialCount
+				}
+			}
+			merged[md5] = sm
+			sqlManageList = append(sqlManageList, sm)
+		}
+
+		sm.SqlText = r.sqlText
+		sm.FpCount += r.count
+		if sm.LastReceiveTimestamp == nil || r.receivedAt.After(*sm.LastReceiveTimestamp) {
+			receivedAt := r.receivedAt
+			sm.LastReceiveTimestamp = &receivedAt
+		}
+		if auditPlanId != 0 {
+			sm.AuditPlanId = auditPlanId
+		}
+		if r.audited {
+			sm.MergeAuditResults(r.auditLevel, r.auditResults)
+		}
+	}
+
+	return s.InsertOrUpdateSqlManage(sqlManageList, sqlAuditRecordId)
+}
+
+// sqlFingerprint is used for the SQL which has no fingerprint generated by the driver.
+func sqlFingerprint(dbType, sql string) string {
+	if dbType == driverV2.DriverTypeMySQL {
+		if fp, err := util.Fingerprint(sql, true); err == nil {
+			return fp
+		}
+	}
+	return customrule.Fingerprint(sql)
+}
+
+// parseSQLInfo returns the counter and the last receive time of the SQL collected by audit plan.
+func parseSQLInfo(info map[string]interface{}) (uint64, time.Time) {
+	counter, receivedAt := uint64(1), time.Now()
+	switch v := info["counter"].(type) {
+	case float64:
+		counter = uint64(v)
+	case uint64:
+		counter = v
+	case int64:
+		counter = uint64(v)
+	case int:
+		counter = uint64(v)
+	case string:
+		if n, err := strconv.ParseUint(v, 10, 64); err == nil {
+			counter = n
+		}
+	}
+	if v, ok := info["last_receive_timestamp"].(string); ok {
+		if t, err := time.Parse(time.RFC3339, v); err == nil {
+			receivedAt = t
+		}
+	}
+	return counter, receivedAt
+}
+
 func (sap *SyncFromAuditPlan) SyncSqlManager() error {
-	return e.New("sql manage is enterprise version feature")
+	task := sap.Task
+	if task == nil || sap.AuditReport == nil {
+		return nil
+	}
+	ap, exist, err := model.GetStorage().GetAuditPlanById(sap.AuditReport.AuditPlanID)
+	if err != nil {
+		return err
+	}
+	if !exist {
+		return ErrAuditPlanNotExist
+	}
+
+	records := make([]*sqlManageRecord, 0, len(sap.FilterSqls))
+	for i, sql := range sap.FilterSqls {
+		if i >= len(task.ExecuteSQLs) {
+			break
+		}
+		info := map[string]interface{}{}
+		if len(sql.Info) > 0 {
+			if err := json.Unmarshal(sql.Info, &info); err != nil {
+				return fmt.Errorf("parse info of audit plan sql failed: %v", err)
+			}
+		}
+		counter, receivedAt := parseSQLInfo(info)
+		executeSQL := task.ExecuteSQLs[i]
+		records = append(records, &sqlManageRecord{
+			fingerprint:  sql.Fingerprint,
+			sqlText:      sql.SQLContent,
+			schema:       sql.Schema,
+			initialCount: counter,
+			receivedAt:   receivedAt,
+			audited:      true,
+			auditLevel:   executeSQL.AuditLevel,
+			auditResults: executeSQL.AuditResults,
+		})
+	}
+	return syncSqlManage(ap.ProjectId, model.SQLManageSourceAuditPlan, ap.InstanceName, ap.ID, 0, records)
 }
 
 func (sa *SyncFromSqlAuditRecord) SyncSqlManager() error {
-	return e.New("sql manage is enterprise version feature")
+	task := sa.Task
+	if task == nil {
+		return nil
+	}
+	now := time.Now()
+	records := make([]*sqlManageRecord, 0, len(task.ExecuteSQLs))
+	for _, executeSQL := range task.ExecuteSQLs {
+		fp, ok := sa.SqlFpMap[executeSQL.Content]
+		if !ok {
+			fp = sqlFingerprint(task.DBType, executeSQL.Content)
+		}
+		records = append(records, &sqlManageRecord{
+			fingerprint:  fp,
+			sqlText:      executeSQL.Content,
+			schema:       task.Schema,
+			count:        1,
+			receivedAt:   now,
+			audited:      true,
+			auditLevel:   executeSQL.AuditLevel,
+			auditResults: executeSQL.AuditResults,
+		})
+	}
+	return syncSqlManage(sa.ProjectId, model.SQLManageSourceSqlAuditRecord, task.InstanceName(), 0, sa.SqlAuditRecordID, records)
+}
+
+func (sw *SyncFromWorkflow) SyncSqlManager() error {
+	s := model.GetStorage()
+	now := time.Now()
+	for _, taskId := range sw.TaskIds {
+		task, exist, err := s.GetTaskDetailById(strconv.Itoa(int(taskId)))
+		if err != nil {
+			return err
+		}
+		if !exist {
+			continue
+		}
+		records := make([]*sqlManageRecord, 0, len(task.ExecuteSQLs))
+		for _, executeSQL := range task.ExecuteSQLs {
+			records = append(records, &sqlManageRecord{
+				fingerprint:  sqlFingerprint(task.DBType, executeSQL.Content),
+				sqlText:      executeSQL.Content,
+				schema:       task.Schema,
+				count:        1,
+				receivedAt:   now,
+				audited:      true,
+				auditLevel:   executeSQL.AuditLevel,
+				auditResults: executeSQL.AuditResults,
+			})
+		}
+		if err := syncSqlManage(sw.ProjectId, model.SQLManageSourceWorkflow, task.InstanceName(), 0, 0, records); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 func SyncToSqlManage(sqls []*SQL, ap *model.AuditPlan) error {
-	return e.New("sql manage is enterprise version feature")
+	records := make([]*sqlManageRecord, 0, len(sqls))
+	for _, sql := range sqls {
+		counter, receivedAt := parseSQLInfo(sql.Info)
+		records = append(records, &sqlManageRecord{
+			fingerprint: sql.Fingerprint,
+			sqlText:     sql.SQLContent,
+			schema:      sql.Schema,
+			count:       counter,
+			receivedAt:  receivedAt,
+		})
+	}
+	return syncSqlManage(ap.ProjectId, model.SQLManageSourceAuditPlan, ap.InstanceName, ap.ID, 0, records)
 }
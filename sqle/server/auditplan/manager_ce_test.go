@@ -0,0 +1,79 @@
+//go:build !enterprise
+// +build !enterprise
+
+package auditplan
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/actiontech/sqle/sqle/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseSQLInfo(t *testing.T) {
+	counter, receivedAt := parseSQLInfo(map[string]interface{}{
+		"counter":                float64(3),
+		"last_receive_timestamp": "2023-01-01T10:00:00Z",
+	})
+	assert.Equal(t, uint64(3), counter)
+	assert.Equal(t, time.Date(2023, 1, 1, 10, 0, 0, 0, time.UTC), receivedAt.UTC())
+
+	counter, _ = parseSQLInfo(map[string]interface{}{"counter": "5"})
+	assert.Equal(t, uint64(5), counter)
+
+	counter, _ = parseSQLInfo(nil)
+	assert.Equal(t, uint64(1), counter)
+}
+
+func TestSyncSqlManage(t *testing.T) {
+	mockDB, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	model.InitMockStorage(mockDB)
+
+	firstAppear := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	receivedAt := time.Date(2023, 1, 2, 0, 0, 0, 0, time.UTC)
+	existedMd5 := model.GenProjFpSourceInstSchemaMd5(1, "SELECT * FROM t1", model.SQLManageSourceWorkflow, "inst1", "db1")
+	newMd5 := model.GenProjFpSourceInstSchemaMd5(1, "DELETE FROM t1", model.SQLManageSourceWorkflow, "inst1", "db1")
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `sql_manages` WHERE `sql_manages`.`deleted_at` IS NULL AND ((proj_fp_source_inst_schema_md5 IN (?,?,?)))")).
+		WithArgs(existedMd5, newMd5, existedMd5).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "proj_fp_source_inst_schema_md5", "sql_fingerprint", "fp_count", "audit_level", "audit_results", "first_appear_timestamp", "last_receive_timestamp", "instance_name", "schema_name", "project_id", "source"}).
+			AddRow(1, existedMd5, "SELECT * FROM t1", 3, "warn", []byte(`[{"level":"warn","message":"old message","rule_name":"r1"}]`), firstAppear, firstAppear, "inst1", "db1", 1, model.SQLManageSourceWorkflow))
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO sql_manages")+"(?s).*"+regexp.QuoteMeta("fp_count               = fp_count + VALUES(fp_count)")).
+		WithArgs(
+			"SELECT * FROM t1", existedMd5, "select * from t1", model.SQLManageSourceWorkflow, "error",
+			model.AuditResults{
+				{Level: "warn", Message: "new message", RuleName: "r1"},
+				{Level: "error", Message: "message", RuleName: "r2"},
+			}, uint64(2), &firstAppear, &receivedAt, "inst1", "db1", "", uint(0), uint(1),
+			"DELETE FROM t1", newMd5, "delete from t1", model.SQLManageSourceWorkflow, "",
+			model.AuditResults{}, uint64(1), &receivedAt, &receivedAt, "inst1", "db1", "", uint(0), uint(1),
+		).
+		WillReturnResult(sqlmock.NewResult(1, 2))
+	mock.ExpectCommit()
+
+	err = syncSqlManage(1, model.SQLManageSourceWorkflow, "inst1", 0, 0, []*sqlManageRecord{
+		{
+			fingerprint: "SELECT * FROM t1", sqlText: "SELECT * FROM t1", schema: "db1", count: 1, receivedAt: receivedAt,
+			audited: true, auditLevel: "warn",
+			auditResults: model.AuditResults{{Level: "warn", Message: "new message", RuleName: "r1"}},
+		},
+		{
+			fingerprint: "DELETE FROM t1", sqlText: "delete from t1", schema: "db1", count: 1, receivedAt: receivedAt,
+			audited: true, auditLevel: "",
+		},
+		{
+			fingerprint: "SELECT * FROM t1", sqlText: "select * from t1", schema: "db1", count: 1, receivedAt: receivedAt,
+			audited: true, auditLevel: "error",
+			auditResults: model.AuditResults{{Level: "error", Message: "message", RuleName: "r2"}},
+		},
+	})
+	assert.NoError(t, err)
+
+	mockDB.Close()
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
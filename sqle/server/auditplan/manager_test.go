@@ -9,6 +9,7 @@ import (
 	"github.com/actiontech/sqle/sqle/log"
 
 	"github.com/actiontech/sqle/sqle/model"
+	"github.com/actiontech/sqle/sqle/server/cluster"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -111,3 +112,36 @@ func TestManager(t *testing.T) {
 
 	assert.Equal(t, &nextTimeMore2, m.lastSyncTime) // next time do not change.
 }
+
+func TestManager_isOwner(t *testing.T) {
+	j := NewManager(log.NewEntry())
+	m, _ := j.(*Manager)
+
+	// non-cluster mode, all audit plans are run on current node.
+	for i := uint(1); i <= 100; i++ {
+		assert.True(t, m.isOwner(i))
+	}
+
+	originServerId := cluster.ServerId
+	defer func() { cluster.ServerId = originServerId }()
+
+	m.ring = cluster.NewHashRing([]string{"server_1", "server_2"})
+	owned := map[string]int{}
+	for _, serverId := range []string{"server_1", "server_2"} {
+		cluster.ServerId = serverId
+		for i := uint(1); i <= 100; i++ {
+			if m.isOwner(i) {
+				owned[serverId]++
+			}
+		}
+	}
+	assert.Equal(t, 100, owned["server_1"]+owned["server_2"])
+	assert.NotZero(t, owned["server_1"])
+	assert.NotZero(t, owned["server_2"])
+
+	// the ring is empty, current node runs all audit plans rather than none.
+	m.ring = cluster.NewHashRing([]string{})
+	for i := uint(1); i <= 100; i++ {
+		assert.True(t, m.isOwner(i))
+	}
+}
@@ -37,3 +37,15 @@ func NewSyncFromSqlAudit(task *model.Task, fpMap map[string]string, projectID ui
 		SqlFpMap:         fpMap,
 	}
 }
+
+type SyncFromWorkflow struct {
+	TaskIds   []uint
+	ProjectId uint
+}
+
+func NewSyncFromWorkflow(taskIds []uint, projectID uint) SqlManager {
+	return &SyncFromWorkflow{
+		TaskIds:   taskIds,
+		ProjectId: projectID,
+	}
+}
@@ -53,6 +53,16 @@ const (
 	paramKeyFirstSqlsScrappedInLastPeriodHours  = "first_sqls_scrapped_in_last_period_hours"
 )
 
+// secretParamKeys are the params which are credentials, they are never exported.
+var secretParamKeys = map[string]struct{}{
+	paramKeyAccessKeySecret: {},
+}
+
+func IsSecretParam(key string) bool {
+	_, ok := secretParamKeys[key]
+	return ok
+}
+
 var Metas = []Meta{
 	{
 		Type:         TypeDefault,
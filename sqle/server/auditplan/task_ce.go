@@ -4,11 +4,92 @@
 package auditplan
 
 import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+
 	"github.com/actiontech/sqle/sqle/model"
 
 	"github.com/sirupsen/logrus"
 )
 
+// SlowLogTask receives the slow log pushed by scannerd, the statistics of query time are accumulated.
+type SlowLogTask struct {
+	*DefaultTask
+}
+
 func NewSlowLogTask(entry *logrus.Entry, ap *model.AuditPlan) Task {
-	return NewDefaultTask(entry, ap)
+	return &SlowLogTask{&DefaultTask{newBaseTask(entry, ap)}}
+}
+
+func (at *SlowLogTask) PartialSyncSQLs(sqls []*SQL) error {
+	return at.persist.UpdateSlowLogAuditPlanSQLs(at.ap.ID, convertSQLsToModelSQLs(sqls))
+}
+
+func (at *SlowLogTask) GetSQLs(args map[string]interface{}) ([]Head, []map[string] /* head name */ string, uint64, error) {
+	auditPlanSQLs, count, err := at.persist.GetAuditPlanSQLsByReq(args)
+	if err != nil {
+		return nil, nil, count, err
+	}
+	head := []Head{
+		{
+			Name: "fingerprint",
+			Desc: "SQL指纹",
+			Type: "sql",
+		},
+		{
+			Name: "sql",
+			Desc: "最后一次匹配到该指纹的语句",
+			Type: "sql",
+		},
+		{
+			Name: "counter",
+			Desc: "匹配到该指纹的语句数量",
+		},
+		{
+			Name: "last_receive_timestamp",
+			Desc: "最后一次匹配到该指纹的时间",
+		},
+		{
+			Name: "query_time_avg",
+			Desc: "平均执行时间（秒）",
+		},
+		{
+			Name: "query_time_max",
+			Desc: "最大执行时间（秒）",
+		},
+		{
+			Name: "db_user",
+			Desc: "执行用户",
+		},
+	}
+	rows := make([]map[string]string, 0, len(auditPlanSQLs))
+	for _, sql := range auditPlanSQLs {
+		var info = struct {
+			Counter              uint64   `json:"counter"`
+			LastReceiveTimestamp string   `json:"last_receive_timestamp"`
+			QueryTimeAvg         *float64 `json:"query_time_avg"`
+			QueryTimeMax         *float64 `json:"query_time_max"`
+			DBUser               string   `json:"db_user"`
+		}{}
+		err := json.Unmarshal(sql.Info, &info)
+		if err != nil {
+			return nil, nil, 0, err
+		}
+		row := map[string]string{
+			"sql":                    sql.SQLContent,
+			"fingerprint":            sql.Fingerprint,
+			"counter":                strconv.FormatUint(info.Counter, 10),
+			"last_receive_timestamp": info.LastReceiveTimestamp,
+			"db_user":                info.DBUser,
+		}
+		if info.QueryTimeAvg != nil {
+			row["query_time_avg"] = fmt.Sprintf("%.6f", *info.QueryTimeAvg)
+		}
+		if info.QueryTimeMax != nil {
+			row["query_time_max"] = fmt.Sprintf("%.6f", *info.QueryTimeMax)
+		}
+		rows = append(rows, row)
+	}
+	return head, rows, count, nil
 }
@@ -0,0 +1,453 @@
+package server
+
+import (
+	"context"
+	"database/sql"
+	_errors "errors"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	mysqlDriver "github.com/actiontech/sqle/sqle/driver/mysql"
+	"github.com/actiontech/sqle/sqle/driver/mysql/util"
+	driverV2 "github.com/actiontech/sqle/sqle/driver/v2"
+	"github.com/actiontech/sqle/sqle/model"
+
+	"github.com/pingcap/parser/ast"
+)
+
+// executionWaitInterval is the interval of checking whether the paused task is resumed
+// or the replicas catch up.
+const executionWaitInterval = time.Second
+
+var errExecutionTerminated = _errors.New("上线被用户中止")
+
+type batchDMLConfig struct {
+	size          int64
+	sleep         time.Duration
+	maxReplicaLag int64
+	replicas      []string
+}
+
+// getBatchDMLConfig returns nil if the instance does not enable batch DML.
+func getBatchDMLConfig(inst *model.Instance) *batchDMLConfig {
+	if inst == nil || inst.DbType != driverV2.DriverTypeMySQL {
+		return nil
+	}
+	if !inst.AdditionalParams.GetParam(mysqlDriver.ParamBatchDMLEnabled).Bool() {
+		return nil
+	}
+	// the params of instance which is created before batch DML is supported are absent, use the defaults.
+	cfg := &batchDMLConfig{
+		size:          1000,
+		sleep:         100 * time.Millisecond,
+		maxReplicaLag: 10,
+	}
+	if p := inst.AdditionalParams.GetParam(mysqlDriver.ParamBatchDMLSize); p != nil && p.Int() > 0 {
+		cfg.size = int64(p.Int())
+	}
+	if p := inst.AdditionalParams.GetParam(mysqlDriver.ParamBatchDMLSleepMs); p != nil && p.Int() >= 0 {
+		cfg.sleep = time.Duration(p.Int()) * time.Millisecond
+	}
+	if p := inst.AdditionalParams.GetParam(mysqlDriver.ParamBatchDMLMaxReplicaLag); p != nil && p.Int() > 0 {
+		cfg.maxReplicaLag = int64(p.Int())
+	}
+	for _, addr := range strings.Split(inst.AdditionalParams.GetParam(mysqlDriver.ParamBatchDMLReplicas).String(), ",") {
+		if addr = strings.TrimSpace(addr); addr != "" {
+			cfg.replicas = append(cfg.replicas, addr)
+		}
+	}
+	return cfg
+}
+
+// batchDML is the single table UPDATE/DELETE which can be split into primary key range chunks.
+type batchDML struct {
+	schema string
+	table  string
+	// qualifier is the alias or name of the table which is used to reference the primary key.
+	qualifier string
+	// setColumns is the columns updated by the UPDATE statement.
+	setColumns []string
+	// stmtWithoutWhere is the statement without WHERE clause, the WHERE clause is the last
+	// clause since the statement with ORDER BY or LIMIT is not eligible.
+	stmtWithoutWhere string
+	where            string
+
+	pk string
+}
+
+// parseBatchDML returns false if the SQL is not an eligible DML, the eligible DML is the single table
+// UPDATE/DELETE with WHERE clause and without ORDER BY and LIMIT.
+func parseBatchDML(query string) (*batchDML, bool, error) {
+	node, err := util.ParseOneSql(query)
+	if err != nil {
+		return nil, false, err
+	}
+
+	b := &batchDML{}
+	var where ast.ExprNode
+	switch stmt := node.(type) {
+	case *ast.UpdateStmt:
+		if stmt.MultipleTable || stmt.Where == nil || stmt.Order != nil || stmt.Limit != nil {
+			return nil, false, nil
+		}
+		if !b.setTable(stmt.TableRefs) {
+			return nil, false, nil
+		}
+		for _, assignment := range stmt.List {
+			b.setColumns = append(b.setColumns, assignment.Column.Name.L)
+		}
+		where = stmt.Where
+		stmt.Where = nil
+		if b.stmtWithoutWhere, err = util.RestoreToSql(stmt); err != nil {
+			return nil, false, err
+		}
+	case *ast.DeleteStmt:
+		if stmt.IsMultiTable || stmt.Where == nil || stmt.Order != nil || stmt.Limit != nil {
+			return nil, false, nil
+		}
+		if !b.setTable(stmt.TableRefs) {
+			return nil, false, nil
+		}
+		where = stmt.Where
+		stmt.Where = nil
+		if b.stmtWithoutWhere, err = util.RestoreToSql(stmt); err != nil {
+			return nil, false, err
+		}
+	default:
+		return nil, false, nil
+	}
+
+	if b.where, err = util.RestoreToSql(where); err != nil {
+		return nil, false, err
+	}
+	return b, true, nil
+}
+
+func (b *batchDML) setTable(refs *ast.TableRefsClause) bool {
+	if refs == nil || refs.TableRefs == nil || refs.TableRefs.Right != nil {
+		return false
+	}
+	source, ok := refs.TableRefs.Left.(*ast.TableSource)
+	if !ok {
+		return false
+	}
+	table, ok := source.Source.(*ast.TableName)
+	if !ok {
+		return false
+	}
+	b.schema = table.Schema.O
+	b.table = table.Name.O
+	b.qualifier = fmt.Sprintf("`%s`", table.Name.O)
+	if b.schema != "" {
+		b.qualifier = fmt.Sprintf("`%s`.`%s`", b.schema, table.Name.O)
+	}
+	if source.AsName.L != "" {
+		b.qualifier = fmt.Sprintf("`%s`", source.AsName.O)
+	}
+	return true
+}
+
+func (b *batchDML) tableName() string {
+	if b.schema != "" {
+		return fmt.Sprintf("`%s`.`%s`", b.schema, b.table)
+	}
+	return fmt.Sprintf("`%s`", b.table)
+}
+
+// chunkSQL returns the SQL of chunk [lower, upper), the upper is included if it is the last chunk.
+func (b *batchDML) chunkSQL(lower, upper int64, last bool) string {
+	op := "<"
+	if last {
+		op = "<="
+	}
+	return fmt.Sprintf("%s WHERE (%s) AND %s.`%s` >= %d AND %s.`%s` %s %d",
+		b.stmtWithoutWhere, b.where, b.qualifier, b.pk, lower, b.qualifier, b.pk, op, upper)
+}
+
+// lookupPrimaryKey sets the primary key of the table, it returns false if the table does not have a
+// single column integer primary key or the primary key is updated by the statement.
+func (b *batchDML) lookupPrimaryKey(ctx context.Context, db *sql.DB, defaultSchema string) (bool, error) {
+	schema := b.schema
+	if schema == "" {
+		schema = defaultSchema
+	}
+	rows, err := db.QueryContext(ctx, "SELECT COLUMN_NAME, DATA_TYPE FROM information_schema.COLUMNS "+
+		"WHERE TABLE_SCHEMA = ? AND TABLE_NAME = ? AND COLUMN_KEY = 'PRI'", schema, b.table)
+	if err != nil {
+		return false, err
+	}
+	defer rows.Close()
+
+	var columns, types []string
+	for rows.Next() {
+		var column, typ string
+		if err := rows.Scan(&column, &typ); err != nil {
+			return false, err
+		}
+		columns = append(columns, column)
+		types = append(types, strings.ToLower(typ))
+	}
+	if err := rows.Err(); err != nil {
+		return false, err
+	}
+
+	if len(columns) != 1 {
+		return false, nil
+	}
+	switch types[0] {
+	case "tinyint", "smallint", "mediumint", "int", "bigint":
+	default:
+		return false, nil
+	}
+	for _, column := range b.setColumns {
+		if strings.EqualFold(column, columns[0]) {
+			return false, nil
+		}
+	}
+	b.pk = columns[0]
+	return true, nil
+}
+
+type batchDMLProgress struct {
+	rowAffects int64
+	chunks     int64
+	// percent is the percentage of the primary key range which has been executed.
+	percent float64
+}
+
+// runBatchDML executes the DML chunk by chunk in autocommit mode. The primary key range is fixed when
+// it starts, the rows inserted with greater primary key during the execution are not affected. The wait
+// is called before each chunk to block the execution when the task is paused or the replicas lag, and
+// the report is called after each chunk.
+func runBatchDML(ctx context.Context, db *sql.DB, b *batchDML, size int64, sleep time.Duration,
+	wait func() error, report func(batchDMLProgress) error) error {
+
+	var minPK, maxPK sql.NullInt64
+	query := fmt.Sprintf("SELECT MIN(`%s`), MAX(`%s`) FROM %s", b.pk, b.pk, b.tableName())
+	if err := db.QueryRowContext(ctx, query).Scan(&minPK, &maxPK); err != nil {
+		return err
+	}
+	if !minPK.Valid {
+		return report(batchDMLProgress{percent: 100})
+	}
+
+	progress := batchDMLProgress{}
+	lower := minPK.Int64
+	for {
+		if err := wait(); err != nil {
+			return err
+		}
+
+		var upper int64
+		query := fmt.Sprintf("SELECT `%s` FROM %s WHERE `%s` >= %d ORDER BY `%s` LIMIT 1 OFFSET %d",
+			b.pk, b.tableName(), b.pk, lower, b.pk, size)
+		err := db.QueryRowContext(ctx, query).Scan(&upper)
+		last := _errors.Is(err, sql.ErrNoRows) || (err == nil && upper > maxPK.Int64)
+		if last {
+			upper = maxPK.Int64
+		} else if err != nil {
+			return err
+		}
+
+		result, err := db.ExecContext(ctx, b.chunkSQL(lower, upper, last))
+		if err != nil {
+			return err
+		}
+		rowAffects, err := result.RowsAffected()
+		if err != nil {
+			return err
+		}
+		progress.rowAffects += rowAffects
+		progress.chunks++
+		if last {
+			progress.percent = 100
+		} else {
+			progress.percent = float64(upper-minPK.Int64) * 100 / float64(maxPK.Int64-minPK.Int64+1)
+		}
+		if err := report(progress); err != nil {
+			return err
+		}
+		if last {
+			return nil
+		}
+		lower = upper
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(sleep):
+		}
+	}
+}
+
+// replicaLag returns the Seconds_Behind_Master of the replica, it returns -1 if the replication
+// is not running, and returns 0 if the instance is not a replica.
+func replicaLag(ctx context.Context, db *sql.DB) (int64, error) {
+	rows, err := db.QueryContext(ctx, "SHOW SLAVE STATUS")
+	if err != nil {
+		return 0, err
+	}
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return 0, err
+	}
+	if !rows.Next() {
+		return 0, rows.Err()
+	}
+	values := make([]sql.NullInt64, len(columns))
+	dest := make([]interface{}, len(columns))
+	var lagIdx = -1
+	for i, column := range columns {
+		if column == "Seconds_Behind_Master" {
+			lagIdx = i
+			dest[i] = &values[i]
+		} else {
+			dest[i] = new(sql.RawBytes)
+		}
+	}
+	if lagIdx < 0 {
+		return 0, fmt.Errorf("column Seconds_Behind_Master is not found in slave status")
+	}
+	if err := rows.Scan(dest...); err != nil {
+		return 0, err
+	}
+	if !values[lagIdx].Valid {
+		return -1, nil
+	}
+	return values[lagIdx].Int64, nil
+}
+
+// newBatchDMLExecutor returns nil if the instance does not enable batch DML or the SQL is not eligible.
+func (a *action) newBatchDMLExecutor(query string) (*batchDMLExecutor, error) {
+	cfg := getBatchDMLConfig(a.task.Instance)
+	if cfg == nil {
+		return nil, nil
+	}
+	b, ok, err := parseBatchDML(query)
+	if err != nil || !ok {
+		return nil, err
+	}
+
+	db, err := newMySQLDB(a.task.Instance, a.task.Schema)
+	if err != nil {
+		return nil, err
+	}
+	ok, err = b.lookupPrimaryKey(context.TODO(), db, a.task.Schema)
+	if err != nil || !ok {
+		db.Close()
+		return nil, err
+	}
+	return &batchDMLExecutor{cfg: cfg, dml: b, db: db}, nil
+}
+
+type batchDMLExecutor struct {
+	cfg *batchDMLConfig
+	dml *batchDML
+	db  *sql.DB
+}
+
+// execBatchDML executes the DML in batches and updates the progress to storage. The termination
+// does not kill the connection of batch DML, it stops the execution before the next chunk.
+func (a *action) execBatchDML(executeSQL *model.ExecuteSQL, e *batchDMLExecutor) error {
+	st := model.GetStorage()
+	defer e.db.Close()
+
+	replicas := make(map[string]*sql.DB, len(e.cfg.replicas))
+	for _, addr := range e.cfg.replicas {
+		host, port, err := net.SplitHostPort(addr)
+		if err != nil {
+			return fmt.Errorf("invalid replica address %s: %v", addr, err)
+		}
+		inst := *a.task.Instance
+		inst.Host, inst.Port = host, port
+		db, err := newMySQLDB(&inst, "")
+		if err != nil {
+			return err
+		}
+		defer db.Close()
+		replicas[addr] = db
+	}
+
+	done := make(chan struct{})
+	a.Lock()
+	a.batchDMLDone = done
+	a.Unlock()
+	defer func() {
+		a.Lock()
+		a.batchDMLDone = nil
+		a.Unlock()
+		close(done)
+	}()
+
+	executeSQL.ExecStatus = model.SQLExecuteStatusDoing
+	executeSQL.RowAffects = 0
+	executeSQL.BatchProgress = 0
+	executeSQL.BatchChunks = 0
+	if err := st.Save(executeSQL); err != nil {
+		return err
+	}
+
+	a.entry.Infof("execute SQL %d in batches, primary key: %s, batch size: %d", executeSQL.Number, e.dml.pk, e.cfg.size)
+	ctx := context.Background()
+	execErr := runBatchDML(ctx, e.db, e.dml, e.cfg.size, e.cfg.sleep,
+		func() error {
+			return a.waitRunnable(ctx, replicas, e.cfg.maxReplicaLag)
+		},
+		func(progress batchDMLProgress) error {
+			executeSQL.RowAffects = progress.rowAffects
+			executeSQL.BatchChunks = progress.chunks
+			executeSQL.BatchProgress = progress.percent
+			return st.Save(executeSQL)
+		})
+
+	if execErr != nil {
+		executeSQL.ExecStatus = model.SQLExecuteStatusFailed
+		executeSQL.ExecResult = execErr.Error()
+		if _errors.Is(execErr, errExecutionTerminated) {
+			executeSQL.ExecStatus = model.SQLExecuteStatusTerminateSucc
+		}
+	} else {
+		executeSQL.ExecStatus = model.SQLExecuteStatusSucceeded
+		executeSQL.ExecResult = model.TaskExecResultOK
+	}
+	if err := st.Save(executeSQL); err != nil {
+		return err
+	}
+	return execErr
+}
+
+// waitRunnable blocks while the task is paused or any of the replicas lags behind the threshold,
+// it returns errExecutionTerminated if the task is terminated.
+func (a *action) waitRunnable(ctx context.Context, replicas map[string]*sql.DB, maxReplicaLag int64) error {
+	for {
+		if a.hasTermination() {
+			return errExecutionTerminated
+		}
+		runnable := !a.isPaused()
+		for addr, db := range replicas {
+			if !runnable {
+				break
+			}
+			lag, err := replicaLag(ctx, db)
+			if err != nil {
+				return fmt.Errorf("check lag of replica %s failed: %v", addr, err)
+			}
+			if lag < 0 || lag > maxReplicaLag {
+				a.entry.Infof("replica %s lags %d seconds, wait for it to catch up", addr, lag)
+				runnable = false
+			}
+		}
+		if runnable {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(executionWaitInterval):
+		}
+	}
+}
@@ -0,0 +1,190 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseBatchDML(t *testing.T) {
+	for _, query := range []string{
+		"select * from t1 where id > 1",
+		"insert into t1 values (1)",
+		"update t1 set v1 = 1",
+		"delete from t1",
+		"update t1 set v1 = 1 where v2 > 1 limit 10",
+		"delete from t1 where v2 > 1 order by id",
+		"update t1, t2 set t1.v1 = t2.v1 where t1.id = t2.id",
+		"delete t1 from t1 join t2 on t1.id = t2.id where t2.v1 = 1",
+	} {
+		_, ok, err := parseBatchDML(query)
+		assert.NoError(t, err)
+		assert.False(t, ok, query)
+	}
+
+	b, ok, err := parseBatchDML("update t1 set v1 = 'a' where created_at < '2023-01-01' or v2 = 1")
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	b.pk = "id"
+	assert.Equal(t, "UPDATE `t1` SET `v1`='a' WHERE (`created_at`<'2023-01-01' OR `v2`=1) AND `t1`.`id` >= 1 AND `t1`.`id` < 1001",
+		b.chunkSQL(1, 1001, false))
+
+	b, ok, err = parseBatchDML("delete from db1.t1 as a where a.v1 = 1")
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	b.pk = "id"
+	assert.Equal(t, "db1", b.schema)
+	assert.Equal(t, "`db1`.`t1`", b.tableName())
+	assert.Equal(t, "DELETE FROM `db1`.`t1` AS `a` WHERE (`a`.`v1`=1) AND `a`.`id` >= 1001 AND `a`.`id` <= 1500",
+		b.chunkSQL(1001, 1500, true))
+}
+
+func TestBatchDML_lookupPrimaryKey(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	query := regexp.QuoteMeta("SELECT COLUMN_NAME, DATA_TYPE FROM information_schema.COLUMNS")
+
+	b, _, err := parseBatchDML("update t1 set v1 = 1 where v2 = 1")
+	assert.NoError(t, err)
+	mock.ExpectQuery(query).WithArgs("db1", "t1").WillReturnRows(
+		sqlmock.NewRows([]string{"COLUMN_NAME", "DATA_TYPE"}).AddRow("id", "bigint"))
+	ok, err := b.lookupPrimaryKey(context.TODO(), db, "db1")
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, "id", b.pk)
+
+	// composite primary key
+	b, _, err = parseBatchDML("update t1 set v1 = 1 where v2 = 1")
+	assert.NoError(t, err)
+	mock.ExpectQuery(query).WithArgs("db1", "t1").WillReturnRows(
+		sqlmock.NewRows([]string{"COLUMN_NAME", "DATA_TYPE"}).AddRow("id", "int").AddRow("v1", "int"))
+	ok, err = b.lookupPrimaryKey(context.TODO(), db, "db1")
+	assert.NoError(t, err)
+	assert.False(t, ok)
+
+	// non-integer primary key
+	b, _, err = parseBatchDML("delete from db2.t1 where v2 = 1")
+	assert.NoError(t, err)
+	mock.ExpectQuery(query).WithArgs("db2", "t1").WillReturnRows(
+		sqlmock.NewRows([]string{"COLUMN_NAME", "DATA_TYPE"}).AddRow("id", "varchar"))
+	ok, err = b.lookupPrimaryKey(context.TODO(), db, "db1")
+	assert.NoError(t, err)
+	assert.False(t, ok)
+
+	// primary key is updated
+	b, _, err = parseBatchDML("update t1 set id = id + 1 where v2 = 1")
+	assert.NoError(t, err)
+	mock.ExpectQuery(query).WithArgs("db1", "t1").WillReturnRows(
+		sqlmock.NewRows([]string{"COLUMN_NAME", "DATA_TYPE"}).AddRow("ID", "int"))
+	ok, err = b.lookupPrimaryKey(context.TODO(), db, "db1")
+	assert.NoError(t, err)
+	assert.False(t, ok)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRunBatchDML(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	b, _, err := parseBatchDML("delete from t1 where v1 = 1")
+	assert.NoError(t, err)
+	b.pk = "id"
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT MIN(`id`), MAX(`id`) FROM `t1`")).WillReturnRows(
+		sqlmock.NewRows([]string{"MIN(`id`)", "MAX(`id`)"}).AddRow(1, 250))
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT `id` FROM `t1` WHERE `id` >= 1 ORDER BY `id` LIMIT 1 OFFSET 100")).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(101))
+	mock.ExpectExec(regexp.QuoteMeta(b.chunkSQL(1, 101, false))).WillReturnResult(sqlmock.NewResult(0, 30))
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT `id` FROM `t1` WHERE `id` >= 101 ORDER BY `id` LIMIT 1 OFFSET 100")).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(201))
+	mock.ExpectExec(regexp.QuoteMeta(b.chunkSQL(101, 201, false))).WillReturnResult(sqlmock.NewResult(0, 20))
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT `id` FROM `t1` WHERE `id` >= 201 ORDER BY `id` LIMIT 1 OFFSET 100")).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	mock.ExpectExec(regexp.QuoteMeta(b.chunkSQL(201, 250, true))).WillReturnResult(sqlmock.NewResult(0, 10))
+
+	var waits int
+	var progresses []batchDMLProgress
+	err = runBatchDML(context.TODO(), db, b, 100, 0,
+		func() error {
+			waits++
+			return nil
+		},
+		func(progress batchDMLProgress) error {
+			progresses = append(progresses, progress)
+			return nil
+		})
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+
+	assert.Equal(t, 3, waits)
+	assert.Equal(t, []batchDMLProgress{
+		{rowAffects: 30, chunks: 1, percent: 40},
+		{rowAffects: 50, chunks: 2, percent: 80},
+		{rowAffects: 60, chunks: 3, percent: 100},
+	}, progresses)
+}
+
+func TestRunBatchDML_Terminated(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	b, _, err := parseBatchDML("update t1 set v1 = 2 where v1 = 1")
+	assert.NoError(t, err)
+	b.pk = "id"
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT MIN(`id`), MAX(`id`) FROM `t1`")).WillReturnRows(
+		sqlmock.NewRows([]string{"MIN(`id`)", "MAX(`id`)"}).AddRow(1, 1000))
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT `id` FROM `t1` WHERE `id` >= 1 ORDER BY `id` LIMIT 1 OFFSET 100")).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(101))
+	mock.ExpectExec(regexp.QuoteMeta(b.chunkSQL(1, 101, false))).WillReturnResult(sqlmock.NewResult(0, 100))
+
+	var waits int
+	err = runBatchDML(context.TODO(), db, b, 100, 0,
+		func() error {
+			waits++
+			if waits > 1 {
+				return errExecutionTerminated
+			}
+			return nil
+		},
+		func(progress batchDMLProgress) error {
+			return nil
+		})
+	assert.Equal(t, errExecutionTerminated, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestReplicaLag(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	columns := []string{"Slave_IO_State", "Master_Host", "Seconds_Behind_Master", "Last_Error"}
+	for _, c := range []struct {
+		rows     *sqlmock.Rows
+		expected int64
+	}{
+		{sqlmock.NewRows(columns).AddRow("Waiting for master to send event", "10.0.0.1", 15, ""), 15},
+		{sqlmock.NewRows(columns).AddRow("", "10.0.0.1", nil, "error"), -1},
+		{sqlmock.NewRows(columns), 0},
+	} {
+		mock.ExpectQuery(regexp.QuoteMeta("SHOW SLAVE STATUS")).WillReturnRows(c.rows)
+		lag, err := replicaLag(context.TODO(), db)
+		assert.NoError(t, err)
+		assert.Equal(t, c.expected, lag)
+	}
+
+	mock.ExpectQuery(regexp.QuoteMeta("SHOW SLAVE STATUS")).WillReturnError(fmt.Errorf("access denied"))
+	_, err = replicaLag(context.TODO(), db)
+	assert.Error(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,457 @@
+package server
+
+import (
+	"context"
+	"database/sql"
+	"encoding/hex"
+	"fmt"
+	"math/rand"
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/actiontech/sqle/sqle/model"
+
+	gomysql "github.com/go-mysql-org/go-mysql/mysql"
+	"github.com/go-mysql-org/go-mysql/replication"
+	"github.com/sirupsen/logrus"
+)
+
+// binlogRollback generates the rollback SQL of DML from the binlog events which are written by the
+// execution, the rollback SQL reverts the rows which are actually changed.
+type binlogRollback struct {
+	entry *logrus.Entry
+	inst  *model.Instance
+	db    *sql.DB
+	// threadID is the connection id of the session which executes the SQLs, only the
+	// transactions written by the session are reverted.
+	threadID uint32
+	// tableMetas caches the table metas by "schema.table".
+	tableMetas map[string]*binlogTableMeta
+}
+
+type binlogPosition struct {
+	File string
+	Pos  uint32
+}
+
+// after returns true if the position p is after or equal to the position o.
+func (p binlogPosition) after(o binlogPosition) bool {
+	if p.File != o.File {
+		return p.File > o.File
+	}
+	return p.Pos >= o.Pos
+}
+
+type binlogTableMeta struct {
+	columns  []string
+	unsigned []bool
+	// primaryKeys is the indexes of primary key columns, the rows are matched by all columns if it is empty.
+	primaryKeys []int
+}
+
+type binlogRowsChange struct {
+	schema    string
+	table     string
+	eventType replication.EventType
+	// rows is the row images, the before and after images are adjacent for update event.
+	rows [][]interface{}
+}
+
+// binlogStatement is the changes made by one statement, the statement text is recorded by the rows query event.
+type binlogStatement struct {
+	query   string
+	changes []*binlogRowsChange
+}
+
+type binlogTransaction struct {
+	threadID   uint32
+	gtid       string
+	statements []*binlogStatement
+}
+
+func newBinlogRollback(entry *logrus.Entry, inst *model.Instance) (*binlogRollback, error) {
+	db, err := newMySQLDB(inst, "")
+	if err != nil {
+		return nil, err
+	}
+
+	var logBin, binlogFormat string
+	if err := db.QueryRow("SELECT @@GLOBAL.log_bin, @@GLOBAL.binlog_format").Scan(&logBin, &binlogFormat); err != nil {
+		db.Close()
+		return nil, err
+	}
+	if logBin != "1" && !strings.EqualFold(logBin, "ON") {
+		db.Close()
+		return nil, fmt.Errorf("binlog is disabled")
+	}
+	if !strings.EqualFold(binlogFormat, "ROW") {
+		db.Close()
+		return nil, fmt.Errorf("binlog format is %s, only ROW format is supported", binlogFormat)
+	}
+	return &binlogRollback{
+		entry:      entry,
+		inst:       inst,
+		db:         db,
+		tableMetas: map[string]*binlogTableMeta{},
+	}, nil
+}
+
+// binlogSessionMarkerVariable is the user variable set by the executing session, it is used to
+// find the connection id of the session since the driver does not return query results.
+const binlogSessionMarkerVariable = "sqle_binlog_rollback_marker"
+
+// sessionThreadID returns the connection id of the session whose marker variable is the given value.
+func (b *binlogRollback) sessionThreadID(marker string) (uint32, error) {
+	var threadID uint32
+	err := b.db.QueryRow(`SELECT t.PROCESSLIST_ID FROM performance_schema.user_variables_by_thread v
+JOIN performance_schema.threads t ON t.THREAD_ID = v.THREAD_ID
+WHERE v.VARIABLE_NAME = ? AND v.VARIABLE_VALUE = ?`, binlogSessionMarkerVariable, marker).Scan(&threadID)
+	if err == sql.ErrNoRows {
+		return 0, fmt.Errorf("the executing session is not found in performance_schema")
+	}
+	return threadID, err
+}
+
+func (b *binlogRollback) close() {
+	b.db.Close()
+}
+
+// position returns the current binlog position of instance.
+func (b *binlogRollback) position() (binlogPosition, error) {
+	rows, err := b.db.Query("SHOW MASTER STATUS")
+	if err != nil {
+		return binlogPosition{}, err
+	}
+	defer rows.Close()
+	columns, err := rows.Columns()
+	if err != nil {
+		return binlogPosition{}, err
+	}
+	if !rows.Next() {
+		return binlogPosition{}, fmt.Errorf("binlog status is empty, binlog may be disabled")
+	}
+	values := make([]sql.RawBytes, len(columns))
+	dest := make([]interface{}, len(columns))
+	for i := range values {
+		dest[i] = &values[i]
+	}
+	if err := rows.Scan(dest...); err != nil {
+		return binlogPosition{}, err
+	}
+	pos := binlogPosition{}
+	for i, column := range columns {
+		switch column {
+		case "File":
+			pos.File = string(values[i])
+		case "Position":
+			p, err := strconv.ParseUint(string(values[i]), 10, 32)
+			if err != nil {
+				return binlogPosition{}, err
+			}
+			pos.Pos = uint32(p)
+		}
+	}
+	return pos, rows.Err()
+}
+
+// generate returns the rollback SQL of each executed SQL, the changes are read from the binlog between
+// the start and end position. Only the transactions of the thread which executes the SQLs are used.
+func (b *binlogRollback) generate(ctx context.Context, start, end binlogPosition, executedSQLs []string) ([]string, error) {
+	if start.after(end) {
+		return make([]string, len(executedSQLs)), nil
+	}
+	txs, err := b.readTransactions(ctx, start, end)
+	if err != nil {
+		return nil, err
+	}
+	b.entry.Infof("read %d transactions from binlog %s:%d to %s:%d", len(txs), start.File, start.Pos, end.File, end.Pos)
+	return generateRollbackSQLsFromBinlog(txs, b.threadID, executedSQLs, b.getTableMeta)
+}
+
+func (b *binlogRollback) readTransactions(ctx context.Context, start, end binlogPosition) ([]*binlogTransaction, error) {
+	port, err := strconv.ParseUint(b.inst.Port, 10, 16)
+	if err != nil {
+		return nil, err
+	}
+	syncer := replication.NewBinlogSyncer(replication.BinlogSyncerConfig{
+		// the server id should be unique among the replicas of instance.
+		ServerID:         uint32(rand.Int31n(1<<30)) + 1<<30,
+		Flavor:           gomysql.MySQLFlavor,
+		Host:             b.inst.Host,
+		Port:             uint16(port),
+		User:             b.inst.User,
+		Password:         b.inst.Password,
+		UseDecimal:       true,
+		DisableRetrySync: true,
+	})
+	defer syncer.Close()
+
+	streamer, err := syncer.StartSync(gomysql.Position{Name: start.File, Pos: start.Pos})
+	if err != nil {
+		return nil, err
+	}
+
+	txs := []*binlogTransaction{}
+	var tx *binlogTransaction
+	current := binlogPosition{File: start.File}
+	for {
+		ev, err := streamer.GetEvent(ctx)
+		if err != nil {
+			return nil, err
+		}
+		switch e := ev.Event.(type) {
+		case *replication.RotateEvent:
+			current = binlogPosition{File: string(e.NextLogName), Pos: uint32(e.Position)}
+			continue
+		case *replication.GTIDEvent:
+			tx = &binlogTransaction{gtid: formatGTID(e)}
+		case *replication.QueryEvent:
+			if strings.EqualFold(string(e.Query), "BEGIN") {
+				if tx == nil {
+					tx = &binlogTransaction{}
+				}
+				tx.threadID = e.SlaveProxyID
+				txs = append(txs, tx)
+			} else {
+				tx = nil
+			}
+		case *replication.RowsQueryEvent:
+			if tx != nil {
+				tx.statements = append(tx.statements, &binlogStatement{query: string(e.Query)})
+			}
+		case *replication.RowsEvent:
+			if tx != nil {
+				if len(tx.statements) == 0 {
+					tx.statements = append(tx.statements, &binlogStatement{})
+				}
+				stmt := tx.statements[len(tx.statements)-1]
+				stmt.changes = append(stmt.changes, &binlogRowsChange{
+					schema:    string(e.Table.Schema),
+					table:     string(e.Table.Table),
+					eventType: ev.Header.EventType,
+					rows:      e.Rows,
+				})
+			}
+		case *replication.XIDEvent:
+			tx = nil
+		}
+		// the position of the fake rotate event and format description event is 0.
+		if ev.Header.LogPos > 0 {
+			current.Pos = ev.Header.LogPos
+		}
+		if current.after(end) {
+			return txs, nil
+		}
+	}
+}
+
+func (b *binlogRollback) getTableMeta(schema, table string) (*binlogTableMeta, error) {
+	key := fmt.Sprintf("%s.%s", schema, table)
+	if meta, ok := b.tableMetas[key]; ok {
+		return meta, nil
+	}
+	rows, err := b.db.Query(`SELECT COLUMN_NAME, COLUMN_TYPE, COLUMN_KEY FROM information_schema.COLUMNS
+WHERE TABLE_SCHEMA = ? AND TABLE_NAME = ? ORDER BY ORDINAL_POSITION`, schema, table)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	meta := &binlogTableMeta{}
+	for rows.Next() {
+		var name, typ, key string
+		if err := rows.Scan(&name, &typ, &key); err != nil {
+			return nil, err
+		}
+		if key == "PRI" {
+			meta.primaryKeys = append(meta.primaryKeys, len(meta.columns))
+		}
+		meta.columns = append(meta.columns, name)
+		meta.unsigned = append(meta.unsigned, strings.Contains(strings.ToLower(typ), "unsigned"))
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if len(meta.columns) == 0 {
+		return nil, fmt.Errorf("table %s not exist", key)
+	}
+	b.tableMetas[key] = meta
+	return meta, nil
+}
+
+// generateRollbackSQLsFromBinlog returns the rollback SQL of each executed SQL. Only the transactions of the
+// thread are used, the statements are matched to the executed SQLs in order by the rows query event. The
+// changes of each statement are reverted in reverse order, so the rollback SQL can be executed one by one.
+func generateRollbackSQLsFromBinlog(txs []*binlogTransaction, threadID uint32, executedSQLs []string,
+	getTableMeta func(schema, table string) (*binlogTableMeta, error)) ([]string, error) {
+	normalize := func(sql string) string {
+		return strings.TrimRight(strings.TrimSpace(sql), "; \t\n")
+	}
+
+	rollbackSQLs := make([][]string, len(executedSQLs))
+	idx := 0
+	for _, tx := range txs {
+		if tx.threadID != threadID {
+			continue
+		}
+		for _, stmt := range tx.statements {
+			matched := false
+			for i := idx; i < len(executedSQLs); i++ {
+				if normalize(executedSQLs[i]) == normalize(stmt.query) {
+					idx, matched = i, true
+					break
+				}
+			}
+			// the changes which can not be matched to an executed SQL are not reverted.
+			if !matched {
+				continue
+			}
+			for _, change := range stmt.changes {
+				meta, err := getTableMeta(change.schema, change.table)
+				if err != nil {
+					return nil, err
+				}
+				sqls, err := reverseRowsChange(change, meta)
+				if err != nil {
+					return nil, err
+				}
+				rollbackSQLs[idx] = append(sqls, rollbackSQLs[idx]...)
+			}
+		}
+	}
+
+	result := make([]string, len(executedSQLs))
+	for i, sqls := range rollbackSQLs {
+		result[i] = strings.Join(sqls, "\n")
+	}
+	return result, nil
+}
+
+// reverseRowsChange returns the SQLs which revert the rows change, the SQLs are in reverse order of rows.
+func reverseRowsChange(change *binlogRowsChange, meta *binlogTableMeta) ([]string, error) {
+	table := fmt.Sprintf("`%s`.`%s`", change.schema, change.table)
+	for _, row := range change.rows {
+		if len(row) != len(meta.columns) {
+			return nil, fmt.Errorf("the columns of table %s have been changed after execution", table)
+		}
+	}
+
+	sqls := []string{}
+	switch change.eventType {
+	case replication.WRITE_ROWS_EVENTv0, replication.WRITE_ROWS_EVENTv1, replication.WRITE_ROWS_EVENTv2:
+		for _, row := range change.rows {
+			where, limit := binlogRowCondition(row, meta)
+			sqls = append(sqls, fmt.Sprintf("DELETE FROM %s WHERE %s%s;", table, where, limit))
+		}
+	case replication.DELETE_ROWS_EVENTv0, replication.DELETE_ROWS_EVENTv1, replication.DELETE_ROWS_EVENTv2:
+		columns := make([]string, len(meta.columns))
+		for i, name := range meta.columns {
+			columns[i] = fmt.Sprintf("`%s`", name)
+		}
+		for _, row := range change.rows {
+			values := make([]string, len(row))
+			for i := range row {
+				values[i] = formatBinlogValue(row[i], meta.unsigned[i])
+			}
+			sqls = append(sqls, fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s);",
+				table, strings.Join(columns, ", "), strings.Join(values, ", ")))
+		}
+	case replication.UPDATE_ROWS_EVENTv0, replication.UPDATE_ROWS_EVENTv1, replication.UPDATE_ROWS_EVENTv2:
+		if len(change.rows)%2 != 0 {
+			return nil, fmt.Errorf("the row images of update event on table %s are not paired", table)
+		}
+		for i := 0; i < len(change.rows); i += 2 {
+			before, after := change.rows[i], change.rows[i+1]
+			set := []string{}
+			for n := range before {
+				if reflect.DeepEqual(before[n], after[n]) {
+					continue
+				}
+				set = append(set, fmt.Sprintf("`%s` = %s", meta.columns[n], formatBinlogValue(before[n], meta.unsigned[n])))
+			}
+			if len(set) == 0 {
+				continue
+			}
+			where, limit := binlogRowCondition(after, meta)
+			sqls = append(sqls, fmt.Sprintf("UPDATE %s SET %s WHERE %s%s;", table, strings.Join(set, ", "), where, limit))
+		}
+	default:
+		return nil, fmt.Errorf("unknown rows event type %v", change.eventType)
+	}
+
+	for i, j := 0, len(sqls)-1; i < j; i, j = i+1, j-1 {
+		sqls[i], sqls[j] = sqls[j], sqls[i]
+	}
+	return sqls, nil
+}
+
+// binlogRowCondition returns the where condition which matches the row by primary key, all columns are used
+// if the table has no primary key and only one row is matched.
+func binlogRowCondition(row []interface{}, meta *binlogTableMeta) (string, string) {
+	where := []string{}
+	if len(meta.primaryKeys) > 0 {
+		for _, i := range meta.primaryKeys {
+			where = append(where, fmt.Sprintf("`%s` = %s", meta.columns[i], formatBinlogValue(row[i], meta.unsigned[i])))
+		}
+		return strings.Join(where, " AND "), ""
+	}
+	for i := range row {
+		where = append(where, fmt.Sprintf("`%s` <=> %s", meta.columns[i], formatBinlogValue(row[i], meta.unsigned[i])))
+	}
+	return strings.Join(where, " AND "), " LIMIT 1"
+}
+
+var binlogValueEscaper = strings.NewReplacer(`\`, `\\`, `'`, `\'`, "\x00", `\0`, "\n", `\n`, "\r", `\r`, "\x1a", `\Z`)
+
+// formatBinlogValue formats the value of row image as SQL literal, the integer of unsigned column is decoded
+// as signed integer by binlog parser.
+func formatBinlogValue(v interface{}, unsigned bool) string {
+	switch value := v.(type) {
+	case nil:
+		return "NULL"
+	case int8:
+		if unsigned {
+			return strconv.FormatUint(uint64(uint8(value)), 10)
+		}
+		return strconv.FormatInt(int64(value), 10)
+	case int16:
+		if unsigned {
+			return strconv.FormatUint(uint64(uint16(value)), 10)
+		}
+		return strconv.FormatInt(int64(value), 10)
+	case int32:
+		if unsigned {
+			// the MEDIUMINT is decoded as int32 too, its value is never negative if it is unsigned.
+			return strconv.FormatUint(uint64(uint32(value)), 10)
+		}
+		return strconv.FormatInt(int64(value), 10)
+	case int64:
+		if unsigned {
+			return strconv.FormatUint(uint64(value), 10)
+		}
+		return strconv.FormatInt(value, 10)
+	case float32:
+		return strconv.FormatFloat(float64(value), 'g', -1, 32)
+	case float64:
+		return strconv.FormatFloat(value, 'g', -1, 64)
+	case string:
+		return fmt.Sprintf("'%s'", binlogValueEscaper.Replace(value))
+	case []byte:
+		if utf8.Valid(value) {
+			return fmt.Sprintf("'%s'", binlogValueEscaper.Replace(string(value)))
+		}
+		return "0x" + hex.EncodeToString(value)
+	case fmt.Stringer:
+		return fmt.Sprintf("'%s'", binlogValueEscaper.Replace(value.String()))
+	default:
+		return fmt.Sprintf("'%v'", value)
+	}
+}
+
+func formatGTID(e *replication.GTIDEvent) string {
+	if len(e.SID) != 16 {
+		return ""
+	}
+	return fmt.Sprintf("%x-%x-%x-%x-%x:%d", e.SID[0:4], e.SID[4:6], e.SID[6:8], e.SID[8:10], e.SID[10:16], e.GNO)
+}
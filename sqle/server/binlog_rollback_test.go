@@ -0,0 +1,97 @@
+package server
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/go-mysql-org/go-mysql/replication"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGenerateRollbackSQLsFromBinlog(t *testing.T) {
+	metas := map[string]*binlogTableMeta{
+		"db1.t1": {columns: []string{"id", "name"}, unsigned: []bool{true, false}, primaryKeys: []int{0}},
+		"db1.t2": {columns: []string{"a", "b"}, unsigned: []bool{false, false}},
+	}
+	getTableMeta := func(schema, table string) (*binlogTableMeta, error) {
+		meta, ok := metas[fmt.Sprintf("%s.%s", schema, table)]
+		if !ok {
+			return nil, fmt.Errorf("table not exist")
+		}
+		return meta, nil
+	}
+
+	txs := []*binlogTransaction{
+		// the transaction of other thread is ignored.
+		{threadID: 2, statements: []*binlogStatement{{
+			query: "delete from t1",
+			changes: []*binlogRowsChange{{schema: "db1", table: "t1", eventType: replication.DELETE_ROWS_EVENTv2,
+				rows: [][]interface{}{{int64(9), "x"}}}},
+		}}},
+		{threadID: 1, statements: []*binlogStatement{
+			{
+				query: "insert into t1 values (1, 'a'), (2, 'b')",
+				changes: []*binlogRowsChange{{schema: "db1", table: "t1", eventType: replication.WRITE_ROWS_EVENTv2,
+					rows: [][]interface{}{{int64(1), "a"}, {int64(2), "b"}}}},
+			},
+			{
+				query: "update t1 set name = 'c' where id = 1",
+				changes: []*binlogRowsChange{{schema: "db1", table: "t1", eventType: replication.UPDATE_ROWS_EVENTv2,
+					rows: [][]interface{}{{int64(1), "a"}, {int64(1), "c"}}}},
+			},
+			{
+				query: "delete from t2",
+				changes: []*binlogRowsChange{{schema: "db1", table: "t2", eventType: replication.DELETE_ROWS_EVENTv2,
+					rows: [][]interface{}{{int32(-1), []byte("it's")}, {nil, []byte{0xff, 0x00}}}}},
+			},
+		}},
+	}
+	rollbackSQLs, err := generateRollbackSQLsFromBinlog(txs, 1, []string{
+		"insert into t1 values (1, 'a'), (2, 'b');",
+		"update t1 set name = 'c' where id = 1",
+		"update t1 set name = 'd' where id = 100",
+		"delete from t2",
+	}, getTableMeta)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{
+		"DELETE FROM `db1`.`t1` WHERE `id` = 2;\nDELETE FROM `db1`.`t1` WHERE `id` = 1;",
+		"UPDATE `db1`.`t1` SET `name` = 'a' WHERE `id` = 1;",
+		"",
+		"INSERT INTO `db1`.`t2` (`a`, `b`) VALUES (NULL, 0xff00);\nINSERT INTO `db1`.`t2` (`a`, `b`) VALUES (-1, 'it\\'s');",
+	}, rollbackSQLs)
+
+	// the rows of update event are matched by all columns if the table has no primary key.
+	rollbackSQLs, err = generateRollbackSQLsFromBinlog([]*binlogTransaction{{threadID: 1, statements: []*binlogStatement{{
+		query: "update t2 set b = 'y'",
+		changes: []*binlogRowsChange{{schema: "db1", table: "t2", eventType: replication.UPDATE_ROWS_EVENTv2,
+			rows: [][]interface{}{{int32(1), "x"}, {int32(1), "y"}}}},
+	}}}}, 1, []string{"update t2 set b = 'y'"}, getTableMeta)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"UPDATE `db1`.`t2` SET `b` = 'x' WHERE `a` <=> 1 AND `b` <=> 'y' LIMIT 1;"}, rollbackSQLs)
+
+	// nothing is generated if the statement is not found in the transactions of thread.
+	rollbackSQLs, err = generateRollbackSQLsFromBinlog(txs, 1, []string{"delete from t3"}, getTableMeta)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{""}, rollbackSQLs)
+
+	// the same statement executed by other thread is not reverted.
+	rollbackSQLs, err = generateRollbackSQLsFromBinlog(txs, 3, []string{"delete from t1"}, getTableMeta)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{""}, rollbackSQLs)
+}
+
+func TestFormatBinlogValue(t *testing.T) {
+	assert.Equal(t, "18446744073709551615", formatBinlogValue(int64(-1), true))
+	assert.Equal(t, "255", formatBinlogValue(int8(-1), true))
+	assert.Equal(t, "-1", formatBinlogValue(int8(-1), false))
+	assert.Equal(t, "1.5", formatBinlogValue(float64(1.5), false))
+	assert.Equal(t, "'12.3'", formatBinlogValue(decimal.RequireFromString("12.30"), false))
+	assert.Equal(t, "'a\\nb'", formatBinlogValue("a\nb", false))
+}
+
+func TestBinlogPositionAfter(t *testing.T) {
+	assert.True(t, binlogPosition{File: "mysql-bin.000002", Pos: 4}.after(binlogPosition{File: "mysql-bin.000001", Pos: 100}))
+	assert.True(t, binlogPosition{File: "mysql-bin.000001", Pos: 100}.after(binlogPosition{File: "mysql-bin.000001", Pos: 100}))
+	assert.False(t, binlogPosition{File: "mysql-bin.000001", Pos: 99}.after(binlogPosition{File: "mysql-bin.000001", Pos: 100}))
+}
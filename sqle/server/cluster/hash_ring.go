@@ -0,0 +1,75 @@
+package cluster
+
+import (
+	"hash/crc32"
+	"sort"
+	"strconv"
+)
+
+// defaultVirtualNodeReplicas is the number of virtual nodes of each node in hash ring,
+// more virtual nodes make the keys distributed more evenly.
+const defaultVirtualNodeReplicas = 100
+
+// HashRing is a consistent hashing ring, only the keys on the removed or added node
+// will be moved to other nodes when the nodes of cluster changed.
+type HashRing struct {
+	nodes    []string
+	hashes   []uint32
+	hashNode map[uint32]string
+}
+
+func NewHashRing(nodes []string) *HashRing {
+	r := &HashRing{
+		nodes:    make([]string, 0, len(nodes)),
+		hashes:   make([]uint32, 0, len(nodes)*defaultVirtualNodeReplicas),
+		hashNode: make(map[uint32]string, len(nodes)*defaultVirtualNodeReplicas),
+	}
+	for _, node := range nodes {
+		r.nodes = append(r.nodes, node)
+		for i := 0; i < defaultVirtualNodeReplicas; i++ {
+			hash := crc32.ChecksumIEEE([]byte(node + "#" + strconv.Itoa(i)))
+			if _, ok := r.hashNode[hash]; ok {
+				continue
+			}
+			r.hashes = append(r.hashes, hash)
+			r.hashNode[hash] = node
+		}
+	}
+	sort.Strings(r.nodes)
+	sort.Slice(r.hashes, func(i, j int) bool { return r.hashes[i] < r.hashes[j] })
+	return r
+}
+
+// Get returns the node which the key belongs to, it returns empty string if the ring has no node.
+func (r *HashRing) Get(key string) string {
+	if len(r.hashes) == 0 {
+		return ""
+	}
+	hash := crc32.ChecksumIEEE([]byte(key))
+	i := sort.Search(len(r.hashes), func(i int) bool { return r.hashes[i] >= hash })
+	if i == len(r.hashes) {
+		i = 0
+	}
+	return r.hashNode[r.hashes[i]]
+}
+
+// Nodes returns the sorted nodes of the ring.
+func (r *HashRing) Nodes() []string {
+	return r.nodes
+}
+
+// Equal returns true if the two rings have the same nodes.
+func (r *HashRing) Equal(other *HashRing) bool {
+	if r == nil || other == nil {
+		return r == other
+	}
+	if len(r.nodes) != len(other.nodes) {
+		return false
+	}
+	for i := range r.nodes {
+		if r.nodes[i] != other.nodes[i] {
+			return false
+		}
+	}
+	return true
+}
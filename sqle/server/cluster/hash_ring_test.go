@@ -0,0 +1,42 @@
+package cluster
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHashRing(t *testing.T) {
+	empty := NewHashRing(nil)
+	assert.Equal(t, "", empty.Get("1"))
+
+	ring := NewHashRing([]string{"server_3", "server_1", "server_2"})
+	assert.Equal(t, []string{"server_1", "server_2", "server_3"}, ring.Nodes())
+	assert.True(t, ring.Equal(NewHashRing([]string{"server_1", "server_2", "server_3"})))
+	assert.False(t, ring.Equal(NewHashRing([]string{"server_1", "server_2"})))
+
+	counts := map[string]int{}
+	owners := map[string]string{}
+	for i := 0; i < 3000; i++ {
+		key := strconv.Itoa(i)
+		owner := ring.Get(key)
+		assert.Equal(t, owner, ring.Get(key))
+		owners[key] = owner
+		counts[owner]++
+	}
+	assert.Len(t, counts, 3)
+	for _, count := range counts {
+		assert.Greater(t, count, 500)
+	}
+
+	// only the keys on the removed node are moved.
+	shrunk := NewHashRing([]string{"server_1", "server_2"})
+	for key, owner := range owners {
+		if owner != "server_3" {
+			assert.Equal(t, owner, shrunk.Get(key))
+		} else {
+			assert.NotEqual(t, "server_3", shrunk.Get(key))
+		}
+	}
+}
@@ -0,0 +1,119 @@
+package cluster
+
+import (
+	"sync"
+	"time"
+
+	"github.com/actiontech/sqle/sqle/log"
+	"github.com/actiontech/sqle/sqle/model"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// HeartbeatInterval is the interval for node to refresh the heartbeat and renew the leader lease.
+	HeartbeatInterval = 5 * time.Second
+	// LeaseTimeout is the time after which a node without heartbeat is considered dead,
+	// the leader lease can be taken over by other nodes after it.
+	LeaseTimeout = 30 * time.Second
+)
+
+// LeaseNode elects the leader through the lease in SQLE storage,
+// all nodes of the cluster should use the same storage and have different server id.
+type LeaseNode struct {
+	serverId string
+	entry    *logrus.Entry
+
+	mutex sync.RWMutex
+	// leaseExpiredAt is the local deadline of the lease held by the node, it is zero if the node is not leader.
+	leaseExpiredAt time.Time
+
+	exitCh chan struct{}
+	doneCh chan struct{}
+}
+
+func NewLeaseNode() *LeaseNode {
+	return &LeaseNode{
+		exitCh: make(chan struct{}),
+		doneCh: make(chan struct{}),
+	}
+}
+
+func (n *LeaseNode) Join(serverId string) {
+	n.serverId = serverId
+	n.entry = log.NewEntry().WithField("type", "cluster").WithField("server_id", serverId)
+	n.entry.Infof("join cluster")
+
+	n.keepalive()
+	go func() {
+		tick := time.NewTicker(HeartbeatInterval)
+		defer tick.Stop()
+		for {
+			select {
+			case <-tick.C:
+				n.keepalive()
+			case <-n.exitCh:
+				n.doneCh <- struct{}{}
+				return
+			}
+		}
+	}()
+}
+
+func (n *LeaseNode) Leave() {
+	n.exitCh <- struct{}{}
+	<-n.doneCh
+
+	n.stepDown()
+	s := model.GetStorage()
+	if err := s.ReleaseClusterLeaderLease(n.serverId); err != nil {
+		n.entry.Errorf("release leader lease failed, error: %v", err)
+	}
+	if err := s.DeleteClusterNode(n.serverId); err != nil {
+		n.entry.Errorf("delete cluster node failed, error: %v", err)
+	}
+	n.entry.Infof("leave cluster")
+}
+
+// IsLeader returns false once the lease is expired locally, even though the storage is unreachable,
+// so that the leader jobs will not be run on two nodes at the same time.
+func (n *LeaseNode) IsLeader() bool {
+	n.mutex.RLock()
+	defer n.mutex.RUnlock()
+	return time.Now().Before(n.leaseExpiredAt)
+}
+
+func (n *LeaseNode) keepalive() {
+	// the deadline is calculated before the request, it is always earlier than the deadline in storage.
+	deadline := time.Now().Add(LeaseTimeout)
+
+	s := model.GetStorage()
+	if err := s.UpdateClusterNodeHeartbeat(n.serverId); err != nil {
+		n.entry.Errorf("update heartbeat failed, error: %v", err)
+	}
+	isLeader, err := s.TryAcquireClusterLeaderLease(n.serverId, int(LeaseTimeout.Seconds()))
+	if err != nil {
+		n.entry.Errorf("acquire leader lease failed, error: %v", err)
+		return
+	}
+	if !isLeader {
+		n.stepDown()
+		return
+	}
+
+	n.mutex.Lock()
+	if n.leaseExpiredAt.IsZero() {
+		n.entry.Infof("become leader")
+	}
+	n.leaseExpiredAt = deadline
+	n.mutex.Unlock()
+}
+
+func (n *LeaseNode) stepDown() {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+	if !n.leaseExpiredAt.IsZero() {
+		n.entry.Infof("step down from leader")
+	}
+	n.leaseExpiredAt = time.Time{}
+}
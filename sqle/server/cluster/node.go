@@ -2,7 +2,10 @@ package cluster
 
 var IsClusterMode bool = false
 
-var DefaultNode Node = &NoClusterNode{}
+// ServerId is the id of current server, it is only set on cluster mode.
+var ServerId string
+
+var DefaultNode Node = NewLeaseNode()
 
 type Node interface {
 	Join(serverId string)
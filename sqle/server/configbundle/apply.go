@@ -0,0 +1,342 @@
+package configbundle
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/actiontech/sqle/sqle/driver"
+	"github.com/actiontech/sqle/sqle/errors"
+	"github.com/actiontech/sqle/sqle/model"
+	"github.com/actiontech/sqle/sqle/server/auditplan"
+	"github.com/actiontech/sqle/sqle/utils"
+)
+
+// auditPlanTokenExpire is the same as the token expire of audit plan created by API.
+const auditPlanTokenExpire = 365 * 24 * time.Hour
+
+// Apply reconciles storage with bundle, the operator becomes the creator of the created
+// projects and audit plans. The changes are applied one by one, if one of them fails,
+// the changes before it are kept and applying the bundle again continues from it.
+func Apply(s *model.Storage, bundle *Bundle, operator *model.User) ([]*Change, error) {
+	changes, err := Plan(s, bundle)
+	if err != nil {
+		return nil, err
+	}
+	for i, change := range changes {
+		if err := applyChange(s, change, operator); err != nil {
+			return changes[:i], fmt.Errorf("%s failed: %v", change, err)
+		}
+	}
+	return changes, nil
+}
+
+func applyChange(s *model.Storage, change *Change, operator *model.User) error {
+	if change.Kind == KindProject {
+		p := change.desired.(*Project)
+		if change.Action == ActionCreate {
+			return s.CreateProject(p.Name, p.Desc, operator.ID)
+		}
+		return s.UpdateProjectInfoByID(p.Name, map[string]interface{}{"desc": p.Desc})
+	}
+
+	project, exist, err := s.GetProjectByName(change.ProjectName)
+	if err != nil {
+		return err
+	}
+	if !exist {
+		return errors.New(errors.DataNotExist, fmt.Errorf("project %s not exist", change.ProjectName))
+	}
+	if project.IsArchived() {
+		return errors.New(errors.DataInvalid, fmt.Errorf("project %s is archived", project.Name))
+	}
+
+	switch desired := change.desired.(type) {
+	case *RuleTemplate:
+		return applyRuleTemplate(s, project, desired)
+	case *Instance:
+		return applyInstance(s, project, desired)
+	case *WorkflowTemplate:
+		return applyWorkflowTemplate(s, project, desired)
+	case *Member:
+		return applyMember(s, project, desired)
+	case *AuditPlan:
+		return applyAuditPlan(s, project, desired, operator)
+	case *SqlWhitelist:
+		return applySqlWhitelist(s, project, desired)
+	default:
+		return fmt.Errorf("unknown change %s", change)
+	}
+}
+
+func applyRuleTemplate(s *model.Storage, project *model.Project, desired *RuleTemplate) error {
+	template, exist, err := s.GetRuleTemplateByProjectIdAndName(project.ID, desired.Name)
+	if err != nil {
+		return err
+	}
+	if !exist {
+		template = &model.RuleTemplate{
+			ProjectId: project.ID,
+			Name:      desired.Name,
+			DBType:    desired.DBType,
+		}
+	}
+	template.Desc = desired.Desc
+
+	ruleNames := make([]string, 0, len(desired.Rules))
+	for _, r := range desired.Rules {
+		ruleNames = append(ruleNames, r.Name)
+	}
+	rules := map[string]model.Rule{}
+	if len(ruleNames) > 0 {
+		if rules, err = s.GetAndCheckRuleExist(ruleNames, template.DBType); err != nil {
+			return err
+		}
+	}
+	if err := s.Save(template); err != nil {
+		return err
+	}
+
+	templateRules := make([]model.RuleTemplateRule, 0, len(desired.Rules))
+	for _, r := range desired.Rules {
+		ruleParams := rules[r.Name].Params
+		ps := ruleParams.Copy()
+		for k, v := range r.Params {
+			if err := ps.SetParamValue(k, v); err != nil {
+				return err
+			}
+		}
+		templateRules = append(templateRules, model.NewRuleTemplateRule(template, &model.Rule{
+			Name:   r.Name,
+			Level:  r.Level,
+			DBType: template.DBType,
+			Params: ps,
+		}))
+	}
+	if err := s.UpdateRuleTemplateRules(template, templateRules...); err != nil {
+		return err
+	}
+
+	templateCustomRules := make([]model.RuleTemplateCustomRule, 0, len(desired.CustomRules))
+	for _, r := range desired.CustomRules {
+		templateCustomRules = append(templateCustomRules, model.NewRuleTemplateCustomRule(template, &model.CustomRule{
+			RuleId: r.RuleId,
+			Level:  r.Level,
+			DBType: template.DBType,
+		}))
+	}
+	return s.UpdateRuleTemplateCustomRules(template, templateCustomRules...)
+}
+
+func applyInstance(s *model.Storage, project *model.Project, desired *Instance) error {
+	instance, exist, err := s.GetInstanceByNameAndProjectID(desired.Name, project.ID)
+	if err != nil {
+		return err
+	}
+	if !exist {
+		instance = &model.Instance{
+			Name:               desired.Name,
+			DbType:             desired.DBType,
+			WorkflowTemplateId: project.WorkflowTemplateId,
+			ProjectId:          project.ID,
+			Source:             model.InstanceSourceSQLE,
+		}
+	}
+
+	additionalParams := driver.GetPluginManager().AllAdditionalParams()[desired.DBType]
+	for k, v := range desired.AdditionalParams {
+		if err := additionalParams.SetParamValue(k, v); err != nil {
+			return err
+		}
+	}
+	instance.Host = desired.Host
+	instance.Port = desired.Port
+	instance.User = desired.User
+	instance.Desc = desired.Desc
+	instance.AdditionalParams = additionalParams
+	instance.MaintenancePeriod = convertMaintenanceTimesToPeriods(desired.MaintenanceTimes)
+	instance.SqlQueryConfig = model.SqlQueryConfig{
+		MaxPreQueryRows:                  desired.SQLQueryConfig.MaxPreQueryRows,
+		QueryTimeoutSecond:               desired.SQLQueryConfig.QueryTimeoutSecond,
+		AuditEnabled:                     desired.SQLQueryConfig.AuditEnabled,
+		AllowQueryWhenLessThanAuditLevel: desired.SQLQueryConfig.AllowQueryWhenLessThanAuditLevel,
+	}
+	if err := s.Save(instance); err != nil {
+		return err
+	}
+
+	templates := []*model.RuleTemplate{}
+	if desired.RuleTemplateName != "" {
+		template, exist, err := s.GetGlobalAndProjectRuleTemplateByNameAndProjectId(desired.RuleTemplateName, project.ID)
+		if err != nil {
+			return err
+		}
+		if !exist {
+			return errors.New(errors.DataNotExist, fmt.Errorf("rule template %s not exist", desired.RuleTemplateName))
+		}
+		templates = append(templates, template)
+	}
+	return s.UpdateInstanceRuleTemplates(instance, templates...)
+}
+
+func applyWorkflowTemplate(s *model.Storage, project *model.Project, desired *WorkflowTemplate) error {
+	template, exist, err := s.GetWorkflowTemplateById(project.WorkflowTemplateId)
+	if err != nil {
+		return err
+	}
+	if !exist {
+		return errors.New(errors.DataNotExist, fmt.Errorf("workflow template is not exist"))
+	}
+
+	userNames := []string{}
+	conditionInstanceNames := []string{}
+	for _, step := range desired.Steps {
+		userNames = append(userNames, step.Assignees...)
+		if step.Condition != nil {
+			conditionInstanceNames = append(conditionInstanceNames, step.Condition.InstanceNames...)
+		}
+	}
+	users, err := s.GetAndCheckUserExist(userNames)
+	if err != nil {
+		return err
+	}
+	userMap := map[string]*model.User{}
+	for _, user := range users {
+		userMap[user.Name] = user
+	}
+	conditionInstanceIds := map[string]uint{}
+	if len(conditionInstanceNames) > 0 {
+		instances, err := s.GetAndCheckInstanceExist(conditionInstanceNames, project.Name)
+		if err != nil {
+			return err
+		}
+		for _, instance := range instances {
+			conditionInstanceIds[instance.Name] = instance.ID
+		}
+	}
+
+	steps := make([]*model.WorkflowStepTemplate, 0, len(desired.Steps))
+	for i, step := range desired.Steps {
+		steps = append(steps, step.toModel(i+1, conditionInstanceIds, userMap))
+	}
+	if err := s.UpdateWorkflowTemplateSteps(template.ID, steps); err != nil {
+		return err
+	}
+
+	template.Desc = desired.Desc
+	template.AllowSubmitWhenLessAuditLevel = desired.AllowSubmitWhenLessAuditLevel
+	return s.Save(template)
+}
+
+func applyMember(s *model.Storage, project *model.Project, desired *Member) error {
+	bindRoles := make([]model.BindRole, 0, len(desired.Roles))
+	for _, r := range desired.Roles {
+		bindRoles = append(bindRoles, model.BindRole{
+			InstanceName: r.InstanceName,
+			RoleNames:    r.RoleNames,
+		})
+	}
+
+	isMember, isManager := false, false
+	for _, u := range project.Members {
+		if u.Name == desired.UserName {
+			isMember = true
+		}
+	}
+	for _, u := range project.Managers {
+		if u.Name == desired.UserName {
+			isManager = true
+		}
+	}
+	// the creator of project is added as manager when creating project
+	if !isMember {
+		return s.AddMember(desired.UserName, project.Name, desired.IsManager, bindRoles)
+	}
+
+	if desired.IsManager && !isManager {
+		if err := s.AddProjectManager(desired.UserName, project.Name); err != nil {
+			return err
+		}
+	}
+	if !desired.IsManager && isManager {
+		isLast, err := s.IsLastProjectManager(desired.UserName, project.Name)
+		if err != nil {
+			return err
+		}
+		if isLast {
+			return errors.New(errors.DataInvalid, fmt.Errorf("user %s is the last manager of project %s", desired.UserName, project.Name))
+		}
+		if err := s.RemoveProjectManager(desired.UserName, project.Name); err != nil {
+			return err
+		}
+	}
+	return s.UpdateUserRoles(desired.UserName, project.Name, bindRoles)
+}
+
+func applyAuditPlan(s *model.Storage, project *model.Project, desired *AuditPlan, operator *model.User) error {
+	ap, exist, err := s.GetAuditPlanFromProjectByName(project.Name, desired.Name)
+	if err != nil {
+		return err
+	}
+	if !exist {
+		// 为了控制JWT Token的长度，保证其长度不超过数据表定义的长度上限(255字符)
+		// 因此使用MD5算法将变长的 operator name 和 Name 转换为固定长度
+		j := utils.NewJWT(utils.JWTSecretKey)
+		token, err := j.CreateToken(operator.Name, time.Now().Add(auditPlanTokenExpire).Unix(),
+			utils.WithAuditPlanName(utils.Md5(desired.Name)))
+		if err != nil {
+			return errors.New(errors.DataConflict, err)
+		}
+		ap = &model.AuditPlan{
+			ProjectId:    project.ID,
+			Name:         desired.Name,
+			CreateUserID: operator.ID,
+			Token:        token,
+		}
+	}
+
+	meta, err := auditplan.GetMeta(desired.Type)
+	if err != nil {
+		return err
+	}
+	for k, v := range desired.Params {
+		if err := meta.Params.SetParamValue(k, v); err != nil {
+			return err
+		}
+	}
+	// the secret params are not exported, keep the stored values if they are absent from bundle
+	for _, p := range meta.Params {
+		if _, ok := desired.Params[p.Key]; ok || !auditplan.IsSecretParam(p.Key) {
+			continue
+		}
+		if stored := ap.Params.GetParam(p.Key); stored != nil {
+			p.Value = stored.Value
+		}
+	}
+	ap.Type = desired.Type
+	ap.CronExpression = desired.CronExpression
+	ap.DBType = desired.DBType
+	ap.InstanceName = desired.InstanceName
+	ap.InstanceDatabase = desired.InstanceDatabase
+	ap.RuleTemplateName = desired.RuleTemplateName
+	ap.Params = meta.Params
+	return s.Save(ap)
+}
+
+func applySqlWhitelist(s *model.Storage, project *model.Project, desired *SqlWhitelist) error {
+	whitelist, err := s.GetSqlWhitelistsByProjectId(project.ID)
+	if err != nil {
+		return err
+	}
+	for _, w := range whitelist {
+		if w.Value == desired.Value && w.MatchType == desired.MatchType {
+			w.Desc = desired.Desc
+			return s.Save(w)
+		}
+	}
+	return s.Save(&model.SqlWhitelist{
+		ProjectId: project.ID,
+		Value:     desired.Value,
+		Desc:      desired.Desc,
+		MatchType: desired.MatchType,
+	})
+}
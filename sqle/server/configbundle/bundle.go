@@ -0,0 +1,288 @@
+// Package configbundle implements the declarative configuration of SQLE. A bundle
+// describes projects and their members, rule templates, workflow template, instances,
+// audit plans and SQL whitelist, it can be exported from SQLE, stored in git and applied
+// to another SQLE (e.g. promoted from staging to production).
+//
+// Applying a bundle only creates and updates resources, resources absent from the
+// bundle are left untouched. Secrets (e.g. the password of instance) are never
+// exported nor applied, they should be set on SQLE after the instance is created.
+// The secret params of audit plan are not exported either, they are only applied when
+// set in the bundle, otherwise the stored values are kept.
+package configbundle
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	"github.com/actiontech/sqle/sqle/errors"
+	"github.com/actiontech/sqle/sqle/model"
+
+	yaml "gopkg.in/yaml.v2"
+)
+
+const (
+	FormatYAML = "yaml"
+	FormatJSON = "json"
+)
+
+type Bundle struct {
+	Projects []*Project `json:"projects" yaml:"projects"`
+}
+
+type Project struct {
+	Name             string            `json:"name" yaml:"name"`
+	Desc             string            `json:"desc,omitempty" yaml:"desc,omitempty"`
+	Members          []*Member         `json:"members,omitempty" yaml:"members,omitempty"`
+	RuleTemplates    []*RuleTemplate   `json:"rule_templates,omitempty" yaml:"rule_templates,omitempty"`
+	WorkflowTemplate *WorkflowTemplate `json:"workflow_template,omitempty" yaml:"workflow_template,omitempty"`
+	Instances        []*Instance       `json:"instances,omitempty" yaml:"instances,omitempty"`
+	AuditPlans       []*AuditPlan      `json:"audit_plans,omitempty" yaml:"audit_plans,omitempty"`
+	SqlWhitelist     []*SqlWhitelist   `json:"sql_whitelist,omitempty" yaml:"sql_whitelist,omitempty"`
+}
+
+type Member struct {
+	UserName  string      `json:"user_name" yaml:"user_name"`
+	IsManager bool        `json:"is_manager,omitempty" yaml:"is_manager,omitempty"`
+	Roles     []*BindRole `json:"roles,omitempty" yaml:"roles,omitempty"`
+}
+
+type BindRole struct {
+	InstanceName string   `json:"instance_name" yaml:"instance_name"`
+	RoleNames    []string `json:"role_names" yaml:"role_names"`
+}
+
+type RuleTemplate struct {
+	Name        string        `json:"name" yaml:"name"`
+	Desc        string        `json:"desc,omitempty" yaml:"desc,omitempty"`
+	DBType      string        `json:"db_type" yaml:"db_type"`
+	Rules       []*Rule       `json:"rules,omitempty" yaml:"rules,omitempty"`
+	CustomRules []*CustomRule `json:"custom_rules,omitempty" yaml:"custom_rules,omitempty"`
+}
+
+type Rule struct {
+	Name  string `json:"name" yaml:"name"`
+	Level string `json:"level,omitempty" yaml:"level,omitempty"`
+	// Params only need to contain the params whose value is different from the default value of rule.
+	Params map[string]string `json:"params,omitempty" yaml:"params,omitempty"`
+}
+
+type CustomRule struct {
+	RuleId string `json:"rule_id" yaml:"rule_id"`
+	Level  string `json:"level,omitempty" yaml:"level,omitempty"`
+}
+
+type WorkflowTemplate struct {
+	Desc                          string                  `json:"desc,omitempty" yaml:"desc,omitempty"`
+	AllowSubmitWhenLessAuditLevel string                  `json:"allow_submit_when_less_audit_level,omitempty" yaml:"allow_submit_when_less_audit_level,omitempty"`
+	Steps                         []*WorkflowStepTemplate `json:"steps" yaml:"steps"`
+}
+
+type WorkflowStepTemplate struct {
+	Type                 string   `json:"type" yaml:"type"`
+	Desc                 string   `json:"desc,omitempty" yaml:"desc,omitempty"`
+	ApprovedByAuthorized bool     `json:"approved_by_authorized,omitempty" yaml:"approved_by_authorized,omitempty"`
+	ExecuteByAuthorized  bool     `json:"execute_by_authorized,omitempty" yaml:"execute_by_authorized,omitempty"`
+	Assignees            []string `json:"assignee_user_name_list,omitempty" yaml:"assignee_user_name_list,omitempty"`
+	// Condition is nil for the step which is always required.
+	Condition *WorkflowStepCondition `json:"condition,omitempty" yaml:"condition,omitempty"`
+	// ApproveMode is any_of by default for sql_review step, ApproveQuorum is only used by quorum mode.
+	ApproveMode   string `json:"approve_mode,omitempty" yaml:"approve_mode,omitempty"`
+	ApproveQuorum uint   `json:"approve_quorum,omitempty" yaml:"approve_quorum,omitempty"`
+}
+
+// toModel converts the step, instanceIds and users are the instances in project and the
+// assignees by name.
+func (st *WorkflowStepTemplate) toModel(number int, instanceIds map[string]uint, users map[string]*model.User) *model.WorkflowStepTemplate {
+	stepUsers := make([]*model.User, 0, len(st.Assignees))
+	for _, userName := range st.Assignees {
+		stepUsers = append(stepUsers, users[userName])
+	}
+	return &model.WorkflowStepTemplate{
+		Number:               uint(number),
+		ApprovedByAuthorized: sql.NullBool{Bool: st.ApprovedByAuthorized, Valid: true},
+		ExecuteByAuthorized:  sql.NullBool{Bool: st.ExecuteByAuthorized, Valid: true},
+		Typ:                  st.Type,
+		Desc:                 st.Desc,
+		Condition:            st.Condition.toModel(instanceIds),
+		ApproveMode:          st.ApproveMode,
+		ApproveQuorum:        st.ApproveQuorum,
+		Users:                stepUsers,
+	}
+}
+
+// WorkflowStepCondition refers to the instances by name, they are stored by id in SQLE.
+type WorkflowStepCondition struct {
+	AuditLevel    string   `json:"audit_level,omitempty" yaml:"audit_level,omitempty"`
+	SQLTypes      []string `json:"sql_types,omitempty" yaml:"sql_types,omitempty"`
+	InstanceNames []string `json:"instance_names,omitempty" yaml:"instance_names,omitempty"`
+}
+
+func (c *WorkflowStepCondition) isEmpty() bool {
+	return c == nil || (c.AuditLevel == "" && len(c.SQLTypes) == 0 && len(c.InstanceNames) == 0)
+}
+
+// toModel converts the condition, instanceIds is the id of instances in project by name.
+func (c *WorkflowStepCondition) toModel(instanceIds map[string]uint) model.WorkflowStepCondition {
+	if c == nil {
+		return model.WorkflowStepCondition{}
+	}
+	condition := model.WorkflowStepCondition{
+		AuditLevel: c.AuditLevel,
+		SQLTypes:   c.SQLTypes,
+	}
+	for _, name := range c.InstanceNames {
+		condition.InstanceIds = append(condition.InstanceIds, instanceIds[name])
+	}
+	return condition
+}
+
+// Instance is the instance without password.
+type Instance struct {
+	Name             string             `json:"name" yaml:"name"`
+	DBType           string             `json:"db_type" yaml:"db_type"`
+	Host             string             `json:"host" yaml:"host"`
+	Port             string             `json:"port" yaml:"port"`
+	User             string             `json:"user" yaml:"user"`
+	Desc             string             `json:"desc,omitempty" yaml:"desc,omitempty"`
+	RuleTemplateName string             `json:"rule_template_name,omitempty" yaml:"rule_template_name,omitempty"`
+	MaintenanceTimes []*MaintenanceTime `json:"maintenance_times,omitempty" yaml:"maintenance_times,omitempty"`
+	AdditionalParams map[string]string  `json:"additional_params,omitempty" yaml:"additional_params,omitempty"`
+	SQLQueryConfig   *SQLQueryConfig    `json:"sql_query_config,omitempty" yaml:"sql_query_config,omitempty"`
+}
+
+type MaintenanceTime struct {
+	StartHour   int `json:"start_hour" yaml:"start_hour"`
+	StartMinute int `json:"start_minute" yaml:"start_minute"`
+	EndHour     int `json:"end_hour" yaml:"end_hour"`
+	EndMinute   int `json:"end_minute" yaml:"end_minute"`
+}
+
+type SQLQueryConfig struct {
+	MaxPreQueryRows                  int    `json:"max_pre_query_rows,omitempty" yaml:"max_pre_query_rows,omitempty"`
+	QueryTimeoutSecond               int    `json:"query_timeout_second,omitempty" yaml:"query_timeout_second,omitempty"`
+	AuditEnabled                     bool   `json:"audit_enabled,omitempty" yaml:"audit_enabled,omitempty"`
+	AllowQueryWhenLessThanAuditLevel string `json:"allow_query_when_less_than_audit_level,omitempty" yaml:"allow_query_when_less_than_audit_level,omitempty"`
+}
+
+type AuditPlan struct {
+	Name             string `json:"name" yaml:"name"`
+	Type             string `json:"type,omitempty" yaml:"type,omitempty"`
+	CronExpression   string `json:"cron_expression" yaml:"cron_expression"`
+	DBType           string `json:"db_type,omitempty" yaml:"db_type,omitempty"`
+	InstanceName     string `json:"instance_name,omitempty" yaml:"instance_name,omitempty"`
+	InstanceDatabase string `json:"instance_database,omitempty" yaml:"instance_database,omitempty"`
+	RuleTemplateName string `json:"rule_template_name,omitempty" yaml:"rule_template_name,omitempty"`
+	// Params only need to contain the params whose value is different from the default value of audit plan type.
+	Params map[string]string `json:"params,omitempty" yaml:"params,omitempty"`
+}
+
+type SqlWhitelist struct {
+	Value     string `json:"value" yaml:"value"`
+	MatchType string `json:"match_type,omitempty" yaml:"match_type,omitempty"`
+	Desc      string `json:"desc,omitempty" yaml:"desc,omitempty"`
+}
+
+// Parse parses the bundle from YAML or JSON content, JSON is parsed as YAML.
+func Parse(content []byte) (*Bundle, error) {
+	bundle := &Bundle{}
+	if err := yaml.UnmarshalStrict(content, bundle); err != nil {
+		return nil, errors.New(errors.DataParseFail, fmt.Errorf("parse config bundle failed: %v", err))
+	}
+	if err := bundle.validate(); err != nil {
+		return nil, errors.New(errors.DataInvalid, err)
+	}
+	return bundle, nil
+}
+
+func Marshal(bundle *Bundle, format string) ([]byte, error) {
+	switch format {
+	case FormatJSON:
+		return json.MarshalIndent(bundle, "", "  ")
+	case FormatYAML, "":
+		return yaml.Marshal(bundle)
+	default:
+		return nil, errors.New(errors.DataInvalid, fmt.Errorf("unsupported config bundle format %s", format))
+	}
+}
+
+// validate checks the required fields and the duplicated names of bundle, it doesn't
+// check the references to the resources in storage.
+func (b *Bundle) validate() error {
+	projectNames := map[string]struct{}{}
+	for _, p := range b.Projects {
+		if p.Name == "" {
+			return fmt.Errorf("project name is required")
+		}
+		if _, ok := projectNames[p.Name]; ok {
+			return fmt.Errorf("project %s is duplicated", p.Name)
+		}
+		projectNames[p.Name] = struct{}{}
+
+		names := map[string]struct{}{}
+		for _, m := range p.Members {
+			if err := checkName(names, p.Name, "member", m.UserName); err != nil {
+				return err
+			}
+			for _, r := range m.Roles {
+				if r.InstanceName == "" || len(r.RoleNames) == 0 {
+					return fmt.Errorf("project %s: instance name and role names of member %s are required", p.Name, m.UserName)
+				}
+			}
+		}
+		names = map[string]struct{}{}
+		for _, t := range p.RuleTemplates {
+			if err := checkName(names, p.Name, "rule template", t.Name); err != nil {
+				return err
+			}
+			if t.DBType == "" {
+				return fmt.Errorf("project %s: db type of rule template %s is required", p.Name, t.Name)
+			}
+		}
+		names = map[string]struct{}{}
+		for _, i := range p.Instances {
+			if err := checkName(names, p.Name, "instance", i.Name); err != nil {
+				return err
+			}
+			if i.Host == "" || i.Port == "" || i.User == "" {
+				return fmt.Errorf("project %s: host, port and user of instance %s are required", p.Name, i.Name)
+			}
+		}
+		names = map[string]struct{}{}
+		for _, ap := range p.AuditPlans {
+			if err := checkName(names, p.Name, "audit plan", ap.Name); err != nil {
+				return err
+			}
+			if ap.CronExpression == "" {
+				return fmt.Errorf("project %s: cron expression of audit plan %s is required", p.Name, ap.Name)
+			}
+			if ap.InstanceDatabase != "" && ap.InstanceName == "" {
+				return fmt.Errorf("project %s: instance name of audit plan %s is required when instance database is set", p.Name, ap.Name)
+			}
+			if ap.InstanceName == "" && ap.DBType == "" {
+				return fmt.Errorf("project %s: db type of audit plan %s is required when instance name is empty", p.Name, ap.Name)
+			}
+		}
+		names = map[string]struct{}{}
+		for _, w := range p.SqlWhitelist {
+			if w.Value == "" {
+				return fmt.Errorf("project %s: value of sql whitelist is required", p.Name)
+			}
+			if err := checkName(names, p.Name, "sql whitelist", whitelistKey(w)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func checkName(names map[string]struct{}, projectName, kind, name string) error {
+	if name == "" {
+		return fmt.Errorf("project %s: %s name is required", projectName, kind)
+	}
+	if _, ok := names[name]; ok {
+		return fmt.Errorf("project %s: %s %s is duplicated", projectName, kind, name)
+	}
+	names[name] = struct{}{}
+	return nil
+}
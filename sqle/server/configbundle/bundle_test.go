@@ -0,0 +1,238 @@
+package configbundle
+
+import (
+	"testing"
+
+	"github.com/actiontech/sqle/sqle/pkg/params"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse(t *testing.T) {
+	yamlContent := `
+projects:
+- name: project_1
+  desc: project for test
+  members:
+  - user_name: user_1
+    is_manager: true
+    roles:
+    - instance_name: mysql_1
+      role_names: [dev]
+  instances:
+  - name: mysql_1
+    db_type: MySQL
+    host: 10.10.10.10
+    port: "3306"
+    user: root
+  sql_whitelist:
+  - value: select 1
+`
+	bundle, err := Parse([]byte(yamlContent))
+	assert.NoError(t, err)
+	assert.Len(t, bundle.Projects, 1)
+	assert.Equal(t, "project_1", bundle.Projects[0].Name)
+	assert.True(t, bundle.Projects[0].Members[0].IsManager)
+	assert.Equal(t, []string{"dev"}, bundle.Projects[0].Members[0].Roles[0].RoleNames)
+	assert.Equal(t, "3306", bundle.Projects[0].Instances[0].Port)
+
+	jsonContent := `{"projects": [{"name": "project_1", "sql_whitelist": [{"value": "select 1", "match_type": "fp_match"}]}]}`
+	bundle, err = Parse([]byte(jsonContent))
+	assert.NoError(t, err)
+	assert.Equal(t, "fp_match", bundle.Projects[0].SqlWhitelist[0].MatchType)
+
+	// unknown field
+	_, err = Parse([]byte(`{"projects": [{"name": "project_1", "instance": []}]}`))
+	assert.Error(t, err)
+
+	// duplicated name
+	_, err = Parse([]byte(`
+projects:
+- name: project_1
+  instances:
+  - {name: mysql_1, host: 10.10.10.10, port: "3306", user: root}
+  - {name: mysql_1, host: 10.10.10.11, port: "3306", user: root}
+`))
+	assert.Error(t, err)
+
+	// audit plan without instance and db type
+	_, err = Parse([]byte(`
+projects:
+- name: project_1
+  audit_plans:
+  - {name: ap_1, cron_expression: "0 */1 * * *"}
+`))
+	assert.Error(t, err)
+}
+
+func TestMarshal(t *testing.T) {
+	bundle := &Bundle{Projects: []*Project{{
+		Name: "project_1",
+		SqlWhitelist: []*SqlWhitelist{
+			{Value: "select 1", MatchType: "exact_match"},
+		},
+	}}}
+	for _, format := range []string{FormatYAML, FormatJSON} {
+		content, err := Marshal(bundle, format)
+		assert.NoError(t, err)
+		parsed, err := Parse(content)
+		assert.NoError(t, err)
+		assert.Equal(t, bundle, parsed)
+	}
+
+	_, err := Marshal(bundle, "xml")
+	assert.Error(t, err)
+}
+
+func newTestProject() *Project {
+	return &Project{
+		Name: "project_1",
+		Desc: "project for test",
+		Members: []*Member{
+			{UserName: "user_1", IsManager: true, Roles: []*BindRole{{InstanceName: "mysql_1", RoleNames: []string{"dev"}}}},
+		},
+		RuleTemplates: []*RuleTemplate{
+			{
+				Name:        "template_1",
+				DBType:      "MySQL",
+				Rules:       []*Rule{{Name: "rule_1", Level: "error", Params: map[string]string{"max": "10"}}},
+				CustomRules: []*CustomRule{{RuleId: "custom_rule_1", Level: "warn"}},
+			},
+		},
+		WorkflowTemplate: defaultWorkflowTemplate("project_1"),
+		Instances: []*Instance{
+			{
+				Name:             "mysql_1",
+				DBType:           "MySQL",
+				Host:             "10.10.10.10",
+				Port:             "3306",
+				User:             "root",
+				RuleTemplateName: "template_1",
+				AdditionalParams: map[string]string{},
+				SQLQueryConfig:   &SQLQueryConfig{MaxPreQueryRows: 100, QueryTimeoutSecond: 10},
+			},
+		},
+		AuditPlans: []*AuditPlan{
+			{Name: "ap_1", Type: "default", CronExpression: "0 */1 * * *", DBType: "MySQL", InstanceName: "mysql_1", RuleTemplateName: "template_1"},
+		},
+		SqlWhitelist: []*SqlWhitelist{{Value: "select 1", MatchType: "exact_match"}},
+	}
+}
+
+func TestDiffProject_Create(t *testing.T) {
+	changes := diffProject(newTestProject(), nil)
+
+	kinds := []string{}
+	for _, c := range changes {
+		assert.Equal(t, ActionCreate, c.Action)
+		assert.Equal(t, "project_1", c.ProjectName)
+		kinds = append(kinds, c.Kind)
+	}
+	// the workflow template is created with project, so it is not changed if it is the default one.
+	assert.Equal(t, []string{
+		KindProject, KindRuleTemplate, KindInstance, KindMember, KindAuditPlan, KindSqlWhitelist,
+	}, kinds)
+	assert.Contains(t, changes[1].Details, `+ rule rule_1: level error, params {"max":"10"}`)
+	assert.Contains(t, changes[1].Details, "+ custom rule custom_rule_1: level warn")
+	assert.Contains(t, changes[2].Details, `host: "" -> "10.10.10.10"`)
+}
+
+func TestDiffProject_Unchanged(t *testing.T) {
+	current := newTestProject()
+	desired := newTestProject()
+	// nil and empty value are the same
+	current.Members[0].Roles[0].RoleNames = []string{"dev"}
+	current.Instances[0].MaintenanceTimes = []*MaintenanceTime{}
+	desired.Instances[0].AdditionalParams = nil
+	assert.Empty(t, diffProject(desired, current))
+
+	// resources absent from bundle are not changed
+	desired.Instances = nil
+	desired.WorkflowTemplate = nil
+	assert.Empty(t, diffProject(desired, current))
+}
+
+func TestDiffProject_Update(t *testing.T) {
+	current := newTestProject()
+	desired := newTestProject()
+	desired.Desc = "new desc"
+	desired.RuleTemplates[0].Rules = []*Rule{
+		{Name: "rule_1", Level: "warn", Params: map[string]string{"max": "10"}},
+		{Name: "rule_2", Level: "error"},
+	}
+	desired.RuleTemplates[0].CustomRules = nil
+	desired.Instances[0].Host = "10.10.10.11"
+	desired.WorkflowTemplate.Steps[0].Assignees = []string{"user_1"}
+	desired.Members[0].IsManager = false
+	desired.AuditPlans[0].CronExpression = "0 */2 * * *"
+	desired.SqlWhitelist = []*SqlWhitelist{
+		{Value: "select 1", MatchType: "exact_match", Desc: "new desc"},
+		{Value: "select 2", MatchType: "fp_match"},
+	}
+
+	changes := diffProject(desired, current)
+	assert.Len(t, changes, 8)
+	for i, c := range changes[:7] {
+		assert.Equal(t, ActionUpdate, c.Action, i)
+	}
+
+	assert.Equal(t, KindProject, changes[0].Kind)
+	assert.Equal(t, []string{`desc: "project for test" -> "new desc"`}, changes[0].Details)
+
+	assert.Equal(t, KindRuleTemplate, changes[1].Kind)
+	assert.Equal(t, []string{
+		`rule rule_1 level: "error" -> "warn"`,
+		"+ rule rule_2: level error, params []",
+		"- custom rule custom_rule_1",
+	}, changes[1].Details)
+
+	assert.Equal(t, KindInstance, changes[2].Kind)
+	assert.Equal(t, []string{`host: "10.10.10.10" -> "10.10.10.11"`}, changes[2].Details)
+
+	assert.Equal(t, KindWorkflowTemplate, changes[3].Kind)
+	assert.Len(t, changes[3].Details, 1)
+
+	assert.Equal(t, KindMember, changes[4].Kind)
+	assert.Equal(t, []string{"is_manager: true -> false"}, changes[4].Details)
+
+	assert.Equal(t, KindAuditPlan, changes[5].Kind)
+	assert.Equal(t, []string{`cron_expression: "0 */1 * * *" -> "0 */2 * * *"`}, changes[5].Details)
+
+	assert.Equal(t, KindSqlWhitelist, changes[6].Kind)
+	assert.Equal(t, "select 1", changes[6].Name)
+
+	assert.Equal(t, ActionCreate, changes[7].Action)
+	assert.Equal(t, KindSqlWhitelist, changes[7].Kind)
+	assert.Equal(t, "select 2", changes[7].Name)
+}
+
+func TestAuditPlanSecretParams(t *testing.T) {
+	ps := params.Params{
+		{Key: "access_key_id", Value: "id"},
+		{Key: "access_key_secret", Value: "s3cret"},
+	}
+	assert.Equal(t, map[string]string{"access_key_id": "id"}, auditPlanParamsToMap(ps))
+
+	current := newTestProject()
+	current.AuditPlans[0].Params = map[string]string{"access_key_id": "id"}
+	desired := newTestProject()
+	desired.AuditPlans[0].Params = map[string]string{"access_key_id": "id", "access_key_secret": "s3cret"}
+	changes := diffProject(desired, current)
+	assert.Len(t, changes, 1)
+	assert.Equal(t, KindAuditPlan, changes[0].Kind)
+	assert.Len(t, changes[0].Details, 1)
+	assert.Contains(t, changes[0].Details[0], "******")
+	assert.NotContains(t, changes[0].Details[0], "s3cret")
+}
+
+func TestWorkflowStepConditionToModel(t *testing.T) {
+	var empty *WorkflowStepCondition
+	assert.True(t, empty.isEmpty())
+	assert.True(t, (&WorkflowStepCondition{}).isEmpty())
+
+	c := &WorkflowStepCondition{AuditLevel: "warn", InstanceNames: []string{"mysql_1", "mysql_2"}}
+	assert.False(t, c.isEmpty())
+	condition := c.toModel(map[string]uint{"mysql_1": 1, "mysql_2": 5})
+	assert.Equal(t, "warn", condition.AuditLevel)
+	assert.Equal(t, []uint{1, 5}, condition.InstanceIds)
+}
@@ -0,0 +1,305 @@
+package configbundle
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/actiontech/sqle/sqle/errors"
+	"github.com/actiontech/sqle/sqle/model"
+	"github.com/actiontech/sqle/sqle/pkg/params"
+	"github.com/actiontech/sqle/sqle/server/auditplan"
+)
+
+// Export returns the bundle of projects in storage.
+func Export(s *model.Storage, projectNames []string) (*Bundle, error) {
+	bundle := &Bundle{Projects: []*Project{}}
+	for _, name := range projectNames {
+		project, exist, err := loadProject(s, name)
+		if err != nil {
+			return nil, err
+		}
+		if !exist {
+			return nil, errors.New(errors.DataNotExist, fmt.Errorf("project %s not exist", name))
+		}
+		bundle.Projects = append(bundle.Projects, project)
+	}
+	return bundle, nil
+}
+
+// loadProject loads the project and all its resources from storage, it is the current
+// state that the project in bundle is compared to.
+func loadProject(s *model.Storage, name string) (*Project, bool, error) {
+	p, exist, err := s.GetProjectByName(name)
+	if err != nil || !exist {
+		return nil, exist, err
+	}
+	project := &Project{
+		Name: p.Name,
+		Desc: p.Desc,
+	}
+
+	if project.Members, err = loadMembers(s, p); err != nil {
+		return nil, false, err
+	}
+	if project.RuleTemplates, err = loadRuleTemplates(s, p); err != nil {
+		return nil, false, err
+	}
+	if project.WorkflowTemplate, err = loadWorkflowTemplate(s, p); err != nil {
+		return nil, false, err
+	}
+	if project.Instances, err = loadInstances(s, p); err != nil {
+		return nil, false, err
+	}
+
+	aps, err := s.GetAuditPlansByProjectId(p.ID)
+	if err != nil {
+		return nil, false, err
+	}
+	project.AuditPlans = make([]*AuditPlan, 0, len(aps))
+	for _, ap := range aps {
+		project.AuditPlans = append(project.AuditPlans, &AuditPlan{
+			Name:             ap.Name,
+			Type:             ap.Type,
+			CronExpression:   ap.CronExpression,
+			DBType:           ap.DBType,
+			InstanceName:     ap.InstanceName,
+			InstanceDatabase: ap.InstanceDatabase,
+			RuleTemplateName: ap.RuleTemplateName,
+			Params:           auditPlanParamsToMap(ap.Params),
+		})
+	}
+
+	whitelist, err := s.GetSqlWhitelistsByProjectId(p.ID)
+	if err != nil {
+		return nil, false, err
+	}
+	project.SqlWhitelist = make([]*SqlWhitelist, 0, len(whitelist))
+	for _, w := range whitelist {
+		project.SqlWhitelist = append(project.SqlWhitelist, &SqlWhitelist{
+			Value:     w.Value,
+			MatchType: w.MatchType,
+			Desc:      w.Desc,
+		})
+	}
+	return project, true, nil
+}
+
+func loadMembers(s *model.Storage, p *model.Project) ([]*Member, error) {
+	managers := map[string]struct{}{}
+	for _, u := range p.Managers {
+		managers[u.Name] = struct{}{}
+	}
+	names := make([]string, 0, len(p.Members))
+	for _, u := range p.Members {
+		names = append(names, u.Name)
+	}
+	bindRoles, err := s.GetBindRolesByMemberNames(names, p.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	members := make([]*Member, 0, len(p.Members))
+	for _, u := range p.Members {
+		_, isManager := managers[u.Name]
+		m := &Member{
+			UserName:  u.Name,
+			IsManager: isManager,
+			Roles:     []*BindRole{},
+		}
+		for _, r := range bindRoles[u.Name] {
+			m.Roles = append(m.Roles, &BindRole{
+				InstanceName: r.InstanceName,
+				RoleNames:    r.RoleNames,
+			})
+		}
+		sortBindRoles(m.Roles)
+		members = append(members, m)
+	}
+	sort.Slice(members, func(i, j int) bool { return members[i].UserName < members[j].UserName })
+	return members, nil
+}
+
+func loadRuleTemplates(s *model.Storage, p *model.Project) ([]*RuleTemplate, error) {
+	names, err := s.GetRuleTemplateNamesByProjectName(p.Name)
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(names)
+
+	templates := make([]*RuleTemplate, 0, len(names))
+	for _, name := range names {
+		t, exist, err := s.GetRuleTemplateDetailByNameAndProjectIds([]uint{p.ID}, name)
+		if err != nil {
+			return nil, err
+		}
+		if !exist {
+			continue
+		}
+		template := &RuleTemplate{
+			Name:        t.Name,
+			Desc:        t.Desc,
+			DBType:      t.DBType,
+			Rules:       make([]*Rule, 0, len(t.RuleList)),
+			CustomRules: make([]*CustomRule, 0, len(t.CustomRuleList)),
+		}
+		for i := range t.RuleList {
+			rule := t.RuleList[i]
+			ps := rule.RuleParams
+			if len(ps) == 0 && rule.Rule != nil {
+				ps = rule.Rule.Params
+			}
+			template.Rules = append(template.Rules, &Rule{
+				Name:   rule.RuleName,
+				Level:  rule.RuleLevel,
+				Params: paramsToMap(ps),
+			})
+		}
+		for _, rule := range t.CustomRuleList {
+			template.CustomRules = append(template.CustomRules, &CustomRule{
+				RuleId: rule.RuleId,
+				Level:  rule.RuleLevel,
+			})
+		}
+		sortRules(template)
+		templates = append(templates, template)
+	}
+	return templates, nil
+}
+
+func loadWorkflowTemplate(s *model.Storage, p *model.Project) (*WorkflowTemplate, error) {
+	t, exist, err := s.GetWorkflowTemplateById(p.WorkflowTemplateId)
+	if err != nil {
+		return nil, err
+	}
+	if !exist {
+		return nil, nil
+	}
+	steps, err := s.GetWorkflowStepsDetailByTemplateId(t.ID)
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(steps, func(i, j int) bool { return steps[i].Number < steps[j].Number })
+	conditionInstanceIds := []uint{}
+	for _, step := range steps {
+		conditionInstanceIds = append(conditionInstanceIds, step.Condition.InstanceIds...)
+	}
+	conditionInstanceNames := map[uint]string{}
+	if len(conditionInstanceIds) > 0 {
+		instances, err := s.GetInstancesByIds(conditionInstanceIds)
+		if err != nil {
+			return nil, err
+		}
+		for _, instance := range instances {
+			conditionInstanceNames[instance.ID] = instance.Name
+		}
+	}
+
+	template := &WorkflowTemplate{
+		Desc:                          t.Desc,
+		AllowSubmitWhenLessAuditLevel: t.AllowSubmitWhenLessAuditLevel,
+		Steps:                         make([]*WorkflowStepTemplate, 0, len(steps)),
+	}
+	for _, step := range steps {
+		users := make([]string, 0, len(step.Users))
+		for _, u := range step.Users {
+			users = append(users, u.Name)
+		}
+		stepTemplate := &WorkflowStepTemplate{
+			Type:                 step.Typ,
+			Desc:                 step.Desc,
+			ApprovedByAuthorized: step.ApprovedByAuthorized.Bool,
+			ExecuteByAuthorized:  step.ExecuteByAuthorized.Bool,
+			Assignees:            users,
+		}
+		if step.Typ == model.WorkflowStepTypeSQLReview {
+			stepTemplate.ApproveMode = step.GetApproveMode()
+			stepTemplate.ApproveQuorum = step.ApproveQuorum
+		}
+		if !step.Condition.IsEmpty() {
+			stepTemplate.Condition = &WorkflowStepCondition{
+				AuditLevel: step.Condition.AuditLevel,
+				SQLTypes:   step.Condition.SQLTypes,
+			}
+			// the deleted instances are left out since they are never matched
+			for _, id := range step.Condition.InstanceIds {
+				if name, ok := conditionInstanceNames[id]; ok {
+					stepTemplate.Condition.InstanceNames = append(stepTemplate.Condition.InstanceNames, name)
+				}
+			}
+		}
+		template.Steps = append(template.Steps, stepTemplate)
+	}
+	return template, nil
+}
+
+func loadInstances(s *model.Storage, p *model.Project) ([]*Instance, error) {
+	instances := make([]*Instance, 0, len(p.Instances))
+	for _, inst := range p.Instances {
+		templates, err := s.GetRuleTemplatesByInstance(inst)
+		if err != nil {
+			return nil, err
+		}
+		instance := &Instance{
+			Name:             inst.Name,
+			DBType:           inst.DbType,
+			Host:             inst.Host,
+			Port:             inst.Port,
+			User:             inst.User,
+			Desc:             inst.Desc,
+			MaintenanceTimes: make([]*MaintenanceTime, 0, len(inst.MaintenancePeriod)),
+			AdditionalParams: paramsToMap(inst.AdditionalParams),
+			SQLQueryConfig: &SQLQueryConfig{
+				MaxPreQueryRows:                  inst.SqlQueryConfig.MaxPreQueryRows,
+				QueryTimeoutSecond:               inst.SqlQueryConfig.QueryTimeoutSecond,
+				AuditEnabled:                     inst.SqlQueryConfig.AuditEnabled,
+				AllowQueryWhenLessThanAuditLevel: inst.SqlQueryConfig.AllowQueryWhenLessThanAuditLevel,
+			},
+		}
+		if len(templates) > 0 {
+			instance.RuleTemplateName = templates[0].Name
+		}
+		for _, period := range inst.MaintenancePeriod {
+			instance.MaintenanceTimes = append(instance.MaintenanceTimes, &MaintenanceTime{
+				StartHour:   period.StartHour,
+				StartMinute: period.StartMinute,
+				EndHour:     period.EndHour,
+				EndMinute:   period.EndMinute,
+			})
+		}
+		instances = append(instances, instance)
+	}
+	sort.Slice(instances, func(i, j int) bool { return instances[i].Name < instances[j].Name })
+	return instances, nil
+}
+
+func paramsToMap(ps params.Params) map[string]string {
+	m := make(map[string]string, len(ps))
+	for _, p := range ps {
+		m[p.Key] = p.Value
+	}
+	return m
+}
+
+// auditPlanParamsToMap is the same as paramsToMap except that the secret params (e.g. the access
+// key secret of cloud database) are left out.
+func auditPlanParamsToMap(ps params.Params) map[string]string {
+	m := make(map[string]string, len(ps))
+	for _, p := range ps {
+		if !auditplan.IsSecretParam(p.Key) {
+			m[p.Key] = p.Value
+		}
+	}
+	return m
+}
+
+func sortBindRoles(roles []*BindRole) {
+	for _, r := range roles {
+		sort.Strings(r.RoleNames)
+	}
+	sort.Slice(roles, func(i, j int) bool { return roles[i].InstanceName < roles[j].InstanceName })
+}
+
+func sortRules(t *RuleTemplate) {
+	sort.Slice(t.Rules, func(i, j int) bool { return t.Rules[i].Name < t.Rules[j].Name })
+	sort.Slice(t.CustomRules, func(i, j int) bool { return t.CustomRules[i].RuleId < t.CustomRules[j].RuleId })
+}
@@ -0,0 +1,342 @@
+package configbundle
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+
+	driverV2 "github.com/actiontech/sqle/sqle/driver/v2"
+	"github.com/actiontech/sqle/sqle/model"
+	"github.com/actiontech/sqle/sqle/server/auditplan"
+)
+
+const (
+	ActionCreate = "create"
+	ActionUpdate = "update"
+)
+
+const (
+	KindProject          = "project"
+	KindRuleTemplate     = "rule_template"
+	KindInstance         = "instance"
+	KindWorkflowTemplate = "workflow_template"
+	KindMember           = "member"
+	KindAuditPlan        = "audit_plan"
+	KindSqlWhitelist     = "sql_whitelist"
+)
+
+// Change is a difference between the bundle and storage, applying it makes the
+// resource in storage the same as the one in bundle.
+type Change struct {
+	Action      string
+	Kind        string
+	ProjectName string
+	Name        string
+	// Details describes the changed fields, e.g. `host: "10.0.0.1" -> "10.0.0.2"`.
+	Details []string
+
+	desired interface{}
+}
+
+func (c *Change) String() string {
+	return fmt.Sprintf("%s %s %s/%s", c.Action, c.Kind, c.ProjectName, c.Name)
+}
+
+// Plan returns the changes needed to reconcile storage with bundle, in the order
+// they should be applied.
+func Plan(s *model.Storage, bundle *Bundle) ([]*Change, error) {
+	changes := []*Change{}
+	for _, p := range bundle.Projects {
+		current, exist, err := loadProject(s, p.Name)
+		if err != nil {
+			return nil, err
+		}
+		if !exist {
+			current = nil
+		}
+		desired, err := resolveProject(s, p, current)
+		if err != nil {
+			return nil, err
+		}
+		changes = append(changes, diffProject(desired, current)...)
+	}
+	return changes, nil
+}
+
+// diffProject compares the resolved project in bundle with the current project in
+// storage, current is nil if the project doesn't exist. Only the resources present in
+// desired are compared.
+func diffProject(desired, current *Project) []*Change {
+	changes := []*Change{}
+	add := func(action, kind, name string, details []string, v interface{}) {
+		changes = append(changes, &Change{
+			Action:      action,
+			Kind:        kind,
+			ProjectName: desired.Name,
+			Name:        name,
+			Details:     details,
+			desired:     v,
+		})
+	}
+
+	if current == nil {
+		add(ActionCreate, KindProject, desired.Name, diffFields(field{"desc", "", desired.Desc}), desired)
+		current = &Project{Name: desired.Name, WorkflowTemplate: defaultWorkflowTemplate(desired.Name)}
+	} else if d := diffFields(field{"desc", current.Desc, desired.Desc}); len(d) > 0 {
+		add(ActionUpdate, KindProject, desired.Name, d, desired)
+	}
+
+	currentTemplates := map[string]*RuleTemplate{}
+	for _, t := range current.RuleTemplates {
+		currentTemplates[t.Name] = t
+	}
+	for _, t := range desired.RuleTemplates {
+		if c, ok := currentTemplates[t.Name]; !ok {
+			add(ActionCreate, KindRuleTemplate, t.Name, diffRuleTemplate(&RuleTemplate{}, t), t)
+		} else if d := diffRuleTemplate(c, t); len(d) > 0 {
+			add(ActionUpdate, KindRuleTemplate, t.Name, d, t)
+		}
+	}
+
+	currentInstances := map[string]*Instance{}
+	for _, i := range current.Instances {
+		currentInstances[i.Name] = i
+	}
+	for _, i := range desired.Instances {
+		if c, ok := currentInstances[i.Name]; !ok {
+			add(ActionCreate, KindInstance, i.Name, diffInstance(&Instance{}, i), i)
+		} else if d := diffInstance(c, i); len(d) > 0 {
+			add(ActionUpdate, KindInstance, i.Name, d, i)
+		}
+	}
+
+	if desired.WorkflowTemplate != nil {
+		c := current.WorkflowTemplate
+		if c == nil {
+			c = &WorkflowTemplate{}
+		}
+		if d := diffWorkflowTemplate(c, desired.WorkflowTemplate); len(d) > 0 {
+			add(ActionUpdate, KindWorkflowTemplate, desired.Name, d, desired.WorkflowTemplate)
+		}
+	}
+
+	currentMembers := map[string]*Member{}
+	for _, m := range current.Members {
+		currentMembers[m.UserName] = m
+	}
+	for _, m := range desired.Members {
+		if c, ok := currentMembers[m.UserName]; !ok {
+			add(ActionCreate, KindMember, m.UserName, diffMember(&Member{}, m), m)
+		} else if d := diffMember(c, m); len(d) > 0 {
+			add(ActionUpdate, KindMember, m.UserName, d, m)
+		}
+	}
+
+	currentAuditPlans := map[string]*AuditPlan{}
+	for _, ap := range current.AuditPlans {
+		currentAuditPlans[ap.Name] = ap
+	}
+	for _, ap := range desired.AuditPlans {
+		if c, ok := currentAuditPlans[ap.Name]; !ok {
+			add(ActionCreate, KindAuditPlan, ap.Name, diffAuditPlan(&AuditPlan{}, ap), ap)
+		} else if d := diffAuditPlan(c, ap); len(d) > 0 {
+			add(ActionUpdate, KindAuditPlan, ap.Name, d, ap)
+		}
+	}
+
+	currentWhitelist := map[string]*SqlWhitelist{}
+	for _, w := range current.SqlWhitelist {
+		currentWhitelist[whitelistKey(w)] = w
+	}
+	for _, w := range desired.SqlWhitelist {
+		if c, ok := currentWhitelist[whitelistKey(w)]; !ok {
+			add(ActionCreate, KindSqlWhitelist, w.Value, diffFields(
+				field{"match_type", "", w.MatchType},
+				field{"desc", "", w.Desc},
+			), w)
+		} else if d := diffFields(field{"desc", c.Desc, w.Desc}); len(d) > 0 {
+			add(ActionUpdate, KindSqlWhitelist, w.Value, d, w)
+		}
+	}
+	return changes
+}
+
+func diffRuleTemplate(current, desired *RuleTemplate) []string {
+	details := diffFields(
+		field{"desc", current.Desc, desired.Desc},
+		field{"db_type", current.DBType, desired.DBType},
+	)
+
+	currentRules := map[string]*Rule{}
+	for _, r := range current.Rules {
+		currentRules[r.Name] = r
+	}
+	desiredRules := map[string]struct{}{}
+	for _, r := range desired.Rules {
+		desiredRules[r.Name] = struct{}{}
+		c, ok := currentRules[r.Name]
+		if !ok {
+			details = append(details, fmt.Sprintf("+ rule %s: level %s, params %s", r.Name, r.Level, format(r.Params)))
+			continue
+		}
+		d := diffFields(
+			field{"level", c.Level, r.Level},
+			field{"params", c.Params, r.Params},
+		)
+		for _, detail := range d {
+			details = append(details, fmt.Sprintf("rule %s %s", r.Name, detail))
+		}
+	}
+	for _, r := range current.Rules {
+		if _, ok := desiredRules[r.Name]; !ok {
+			details = append(details, fmt.Sprintf("- rule %s", r.Name))
+		}
+	}
+
+	currentCustomRules := map[string]*CustomRule{}
+	for _, r := range current.CustomRules {
+		currentCustomRules[r.RuleId] = r
+	}
+	desiredCustomRules := map[string]struct{}{}
+	for _, r := range desired.CustomRules {
+		desiredCustomRules[r.RuleId] = struct{}{}
+		c, ok := currentCustomRules[r.RuleId]
+		if !ok {
+			details = append(details, fmt.Sprintf("+ custom rule %s: level %s", r.RuleId, r.Level))
+			continue
+		}
+		if c.Level != r.Level {
+			details = append(details, fmt.Sprintf("custom rule %s level: %s -> %s", r.RuleId, c.Level, r.Level))
+		}
+	}
+	for _, r := range current.CustomRules {
+		if _, ok := desiredCustomRules[r.RuleId]; !ok {
+			details = append(details, fmt.Sprintf("- custom rule %s", r.RuleId))
+		}
+	}
+	return details
+}
+
+func diffInstance(current, desired *Instance) []string {
+	return diffFields(
+		field{"db_type", current.DBType, desired.DBType},
+		field{"host", current.Host, desired.Host},
+		field{"port", current.Port, desired.Port},
+		field{"user", current.User, desired.User},
+		field{"desc", current.Desc, desired.Desc},
+		field{"rule_template_name", current.RuleTemplateName, desired.RuleTemplateName},
+		field{"maintenance_times", current.MaintenanceTimes, desired.MaintenanceTimes},
+		field{"additional_params", current.AdditionalParams, desired.AdditionalParams},
+		field{"sql_query_config", current.SQLQueryConfig, desired.SQLQueryConfig},
+	)
+}
+
+func diffWorkflowTemplate(current, desired *WorkflowTemplate) []string {
+	fields := []field{
+		{"desc", current.Desc, desired.Desc},
+		{"allow_submit_when_less_audit_level", current.AllowSubmitWhenLessAuditLevel, desired.AllowSubmitWhenLessAuditLevel},
+	}
+	for i := 0; i < len(current.Steps) || i < len(desired.Steps); i++ {
+		var c, d *WorkflowStepTemplate
+		if i < len(current.Steps) {
+			c = current.Steps[i]
+		}
+		if i < len(desired.Steps) {
+			d = desired.Steps[i]
+		}
+		fields = append(fields, field{fmt.Sprintf("step %d", i+1), c, d})
+	}
+	return diffFields(fields...)
+}
+
+func diffMember(current, desired *Member) []string {
+	return diffFields(
+		field{"is_manager", current.IsManager, desired.IsManager},
+		field{"roles", current.Roles, desired.Roles},
+	)
+}
+
+func diffAuditPlan(current, desired *AuditPlan) []string {
+	return diffFields(
+		field{"type", current.Type, desired.Type},
+		field{"cron_expression", current.CronExpression, desired.CronExpression},
+		field{"db_type", current.DBType, desired.DBType},
+		field{"instance_name", current.InstanceName, desired.InstanceName},
+		field{"instance_database", current.InstanceDatabase, desired.InstanceDatabase},
+		field{"rule_template_name", current.RuleTemplateName, desired.RuleTemplateName},
+		field{"params", redactAuditPlanParams(current.Params), redactAuditPlanParams(desired.Params)},
+	)
+}
+
+// redactAuditPlanParams hides the value of secret params in the details of change.
+func redactAuditPlanParams(ps map[string]string) map[string]string {
+	redacted := make(map[string]string, len(ps))
+	for k, v := range ps {
+		if auditplan.IsSecretParam(k) {
+			v = "******"
+		}
+		redacted[k] = v
+	}
+	return redacted
+}
+
+func whitelistKey(w *SqlWhitelist) string {
+	matchType := w.MatchType
+	if matchType == "" {
+		matchType = model.SQLWhitelistExactMatch
+	}
+	return matchType + ":" + w.Value
+}
+
+// defaultWorkflowTemplate is the workflow template created with project, see Storage.CreateProject.
+func defaultWorkflowTemplate(projectName string) *WorkflowTemplate {
+	return &WorkflowTemplate{
+		Desc:                          fmt.Sprintf("%v 默认模板", projectName),
+		AllowSubmitWhenLessAuditLevel: string(driverV2.RuleLevelWarn),
+		Steps: []*WorkflowStepTemplate{
+			{Type: model.WorkflowStepTypeSQLReview, ApprovedByAuthorized: true, Assignees: []string{}, ApproveMode: model.WorkflowStepApproveModeAnyOf},
+			{Type: model.WorkflowStepTypeSQLExecute, ExecuteByAuthorized: true, Assignees: []string{}},
+		},
+	}
+}
+
+type field struct {
+	name    string
+	current interface{}
+	desired interface{}
+}
+
+func diffFields(fields ...field) []string {
+	details := []string{}
+	for _, f := range fields {
+		c, d := format(f.current), format(f.desired)
+		if c != d {
+			details = append(details, fmt.Sprintf("%s: %s -> %s", f.name, c, d))
+		}
+	}
+	return details
+}
+
+// format formats the field value for comparing and display, nil and empty slice (or map)
+// are formatted to the same value.
+func format(v interface{}) string {
+	switch v := v.(type) {
+	case string:
+		return strconv.Quote(v)
+	case bool, int:
+		return fmt.Sprint(v)
+	}
+	rv := reflect.ValueOf(v)
+	if !rv.IsValid() || (rv.Kind() == reflect.Ptr && rv.IsNil()) {
+		return "null"
+	}
+	if (rv.Kind() == reflect.Slice || rv.Kind() == reflect.Map) && rv.Len() == 0 {
+		return "[]"
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(b)
+}
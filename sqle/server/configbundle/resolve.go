@@ -0,0 +1,451 @@
+package configbundle
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/actiontech/sqle/sqle/driver"
+	driverV2 "github.com/actiontech/sqle/sqle/driver/v2"
+	"github.com/actiontech/sqle/sqle/errors"
+	"github.com/actiontech/sqle/sqle/model"
+	"github.com/actiontech/sqle/sqle/server/auditplan"
+
+	dry "github.com/ungerik/go-dry"
+)
+
+// resolveProject checks the references of project in bundle and fills the default values,
+// so that it can be compared with the project loaded from storage. current is nil if the
+// project doesn't exist.
+func resolveProject(s *model.Storage, p *Project, current *Project) (*Project, error) {
+	if current == nil {
+		current = &Project{Name: p.Name}
+	}
+	r := &resolver{s: s, desired: p, current: current}
+	project := &Project{
+		Name: p.Name,
+		Desc: p.Desc,
+	}
+
+	var err error
+	if project.RuleTemplates, err = r.resolveRuleTemplates(); err != nil {
+		return nil, errors.New(errors.DataInvalid, fmt.Errorf("project %s: %v", p.Name, err))
+	}
+	if project.Instances, err = r.resolveInstances(); err != nil {
+		return nil, errors.New(errors.DataInvalid, fmt.Errorf("project %s: %v", p.Name, err))
+	}
+	if project.WorkflowTemplate, err = r.resolveWorkflowTemplate(); err != nil {
+		return nil, errors.New(errors.DataInvalid, fmt.Errorf("project %s: %v", p.Name, err))
+	}
+	if project.Members, err = r.resolveMembers(); err != nil {
+		return nil, errors.New(errors.DataInvalid, fmt.Errorf("project %s: %v", p.Name, err))
+	}
+	if project.AuditPlans, err = r.resolveAuditPlans(project.Instances); err != nil {
+		return nil, errors.New(errors.DataInvalid, fmt.Errorf("project %s: %v", p.Name, err))
+	}
+	if project.SqlWhitelist, err = r.resolveSqlWhitelist(); err != nil {
+		return nil, errors.New(errors.DataInvalid, fmt.Errorf("project %s: %v", p.Name, err))
+	}
+	return project, nil
+}
+
+type resolver struct {
+	s       *model.Storage
+	desired *Project
+	current *Project
+}
+
+// ruleTemplateDBType returns the db type of the rule template which can be referenced by
+// the project, the rule template is looked up in bundle, project and global rule templates.
+func (r *resolver) ruleTemplateDBType(name string) (string, error) {
+	for _, t := range r.desired.RuleTemplates {
+		if t.Name == name {
+			return t.DBType, nil
+		}
+	}
+	for _, t := range r.current.RuleTemplates {
+		if t.Name == name {
+			return t.DBType, nil
+		}
+	}
+	t, exist, err := r.s.GetRuleTemplateByProjectIdAndName(model.ProjectIdForGlobalRuleTemplate, name)
+	if err != nil {
+		return "", err
+	}
+	if !exist {
+		return "", fmt.Errorf("rule template %s not exist", name)
+	}
+	return t.DBType, nil
+}
+
+func (r *resolver) instanceExist(name string) bool {
+	for _, i := range r.desired.Instances {
+		if i.Name == name {
+			return true
+		}
+	}
+	for _, i := range r.current.Instances {
+		if i.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *resolver) resolveRuleTemplates() ([]*RuleTemplate, error) {
+	templates := make([]*RuleTemplate, 0, len(r.desired.RuleTemplates))
+	for _, t := range r.desired.RuleTemplates {
+		isNew := true
+		for _, c := range r.current.RuleTemplates {
+			if c.Name == t.Name {
+				isNew = false
+				if c.DBType != t.DBType {
+					return nil, fmt.Errorf("db type of rule template %s can not be changed from %s to %s", t.Name, c.DBType, t.DBType)
+				}
+			}
+		}
+		if isNew {
+			_, exist, err := r.s.GetRuleTemplateByProjectIdAndName(model.ProjectIdForGlobalRuleTemplate, t.Name)
+			if err != nil {
+				return nil, err
+			}
+			if exist {
+				return nil, fmt.Errorf("rule template %s is exist in global rule templates", t.Name)
+			}
+		}
+
+		template := &RuleTemplate{
+			Name:        t.Name,
+			Desc:        t.Desc,
+			DBType:      t.DBType,
+			Rules:       make([]*Rule, 0, len(t.Rules)),
+			CustomRules: make([]*CustomRule, 0, len(t.CustomRules)),
+		}
+
+		ruleNames := make([]string, 0, len(t.Rules))
+		for _, rule := range t.Rules {
+			ruleNames = append(ruleNames, rule.Name)
+		}
+		rules := map[string]model.Rule{}
+		if len(ruleNames) > 0 {
+			var err error
+			if rules, err = r.s.GetAndCheckRuleExist(ruleNames, t.DBType); err != nil {
+				return nil, err
+			}
+		}
+		for _, rule := range t.Rules {
+			ruleParams := rules[rule.Name].Params
+			ps := ruleParams.Copy()
+			for k, v := range rule.Params {
+				if err := ps.SetParamValue(k, v); err != nil {
+					return nil, fmt.Errorf("set param of rule %s in rule template %s failed: %v", rule.Name, t.Name, err)
+				}
+			}
+			level := rule.Level
+			if level == "" {
+				level = rules[rule.Name].Level
+			}
+			template.Rules = append(template.Rules, &Rule{
+				Name:   rule.Name,
+				Level:  level,
+				Params: paramsToMap(ps),
+			})
+		}
+
+		ruleIds := make([]string, 0, len(t.CustomRules))
+		for _, rule := range t.CustomRules {
+			ruleIds = append(ruleIds, rule.RuleId)
+		}
+		customRules := map[string]model.CustomRule{}
+		if len(ruleIds) > 0 {
+			var err error
+			if customRules, err = r.s.GetAndCheckCustomRuleExist(ruleIds); err != nil {
+				return nil, err
+			}
+		}
+		for _, rule := range t.CustomRules {
+			if customRules[rule.RuleId].DBType != t.DBType {
+				return nil, fmt.Errorf("db type of custom rule %s is different from rule template %s", rule.RuleId, t.Name)
+			}
+			level := rule.Level
+			if level == "" {
+				level = customRules[rule.RuleId].Level
+			}
+			template.CustomRules = append(template.CustomRules, &CustomRule{
+				RuleId: rule.RuleId,
+				Level:  level,
+			})
+		}
+		sortRules(template)
+		templates = append(templates, template)
+	}
+	return templates, nil
+}
+
+func (r *resolver) resolveInstances() ([]*Instance, error) {
+	pm := driver.GetPluginManager()
+	instances := make([]*Instance, 0, len(r.desired.Instances))
+	for _, i := range r.desired.Instances {
+		instance := &Instance{
+			Name:             i.Name,
+			DBType:           i.DBType,
+			Host:             i.Host,
+			Port:             i.Port,
+			User:             i.User,
+			Desc:             i.Desc,
+			RuleTemplateName: i.RuleTemplateName,
+			MaintenanceTimes: i.MaintenanceTimes,
+		}
+		if instance.DBType == "" {
+			instance.DBType = driverV2.DriverTypeMySQL
+		}
+		if !dry.StringInSlice(instance.DBType, pm.AllDrivers()) {
+			return nil, &driverV2.DriverNotSupportedError{DriverTyp: instance.DBType}
+		}
+		for _, c := range r.current.Instances {
+			if c.Name == i.Name && c.DBType != instance.DBType {
+				return nil, fmt.Errorf("db type of instance %s can not be changed from %s to %s", i.Name, c.DBType, instance.DBType)
+			}
+		}
+
+		periods := convertMaintenanceTimesToPeriods(i.MaintenanceTimes)
+		if !periods.SelfCheck() {
+			return nil, fmt.Errorf("maintenance times of instance %s is invalid", i.Name)
+		}
+
+		additionalParams := pm.AllAdditionalParams()[instance.DBType]
+		for k, v := range i.AdditionalParams {
+			if err := additionalParams.SetParamValue(k, v); err != nil {
+				return nil, fmt.Errorf("set additional param of instance %s failed: %v", i.Name, err)
+			}
+		}
+		instance.AdditionalParams = paramsToMap(additionalParams)
+
+		instance.SQLQueryConfig = &SQLQueryConfig{}
+		if i.SQLQueryConfig != nil {
+			*instance.SQLQueryConfig = *i.SQLQueryConfig
+		}
+		// default value, see CreateInstance API
+		if instance.SQLQueryConfig.QueryTimeoutSecond == 0 {
+			instance.SQLQueryConfig.QueryTimeoutSecond = 10
+		}
+		if instance.SQLQueryConfig.MaxPreQueryRows == 0 {
+			instance.SQLQueryConfig.MaxPreQueryRows = 100
+		}
+		if instance.SQLQueryConfig.AuditEnabled && instance.SQLQueryConfig.AllowQueryWhenLessThanAuditLevel == "" {
+			instance.SQLQueryConfig.AllowQueryWhenLessThanAuditLevel = string(driverV2.RuleLevelError)
+		}
+
+		if i.RuleTemplateName != "" {
+			dbType, err := r.ruleTemplateDBType(i.RuleTemplateName)
+			if err != nil {
+				return nil, err
+			}
+			if dbType != instance.DBType {
+				return nil, fmt.Errorf("db type of rule template %s is different from instance %s", i.RuleTemplateName, i.Name)
+			}
+		}
+		instances = append(instances, instance)
+	}
+	sort.Slice(instances, func(i, j int) bool { return instances[i].Name < instances[j].Name })
+	return instances, nil
+}
+
+func (r *resolver) resolveWorkflowTemplate() (*WorkflowTemplate, error) {
+	t := r.desired.WorkflowTemplate
+	if t == nil {
+		return nil, nil
+	}
+	userNames := []string{}
+	for _, step := range t.Steps {
+		userNames = append(userNames, step.Assignees...)
+	}
+	userMap := map[string]*model.User{}
+	if len(userNames) > 0 {
+		users, err := r.s.GetAndCheckUserExist(userNames)
+		if err != nil {
+			return nil, err
+		}
+		for _, user := range users {
+			userMap[user.Name] = user
+		}
+	}
+	// the steps are checked as the UpdateWorkflowTemplate API.
+	steps := make([]*model.WorkflowStepTemplate, 0, len(t.Steps))
+	for i, step := range t.Steps {
+		steps = append(steps, step.toModel(i+1, nil, userMap))
+	}
+	if err := model.ValidateWorkflowStepTemplates(steps); err != nil {
+		return nil, err
+	}
+	template := &WorkflowTemplate{
+		Desc:                          t.Desc,
+		AllowSubmitWhenLessAuditLevel: t.AllowSubmitWhenLessAuditLevel,
+		Steps:                         t.Steps,
+	}
+	if template.AllowSubmitWhenLessAuditLevel == "" {
+		template.AllowSubmitWhenLessAuditLevel = string(driverV2.RuleLevelWarn)
+	}
+
+	for _, step := range t.Steps {
+		if step.Type == model.WorkflowStepTypeSQLReview && step.ApproveMode == "" {
+			step.ApproveMode = model.WorkflowStepApproveModeAnyOf
+		}
+		// the empty condition is not stored, it is the same as no condition.
+		if step.Condition.isEmpty() {
+			step.Condition = nil
+		}
+		if step.Condition == nil {
+			continue
+		}
+		for _, name := range step.Condition.InstanceNames {
+			if !r.instanceExist(name) {
+				return nil, fmt.Errorf("instance %s of workflow step condition not exist", name)
+			}
+		}
+	}
+	return template, nil
+}
+
+func (r *resolver) resolveMembers() ([]*Member, error) {
+	userNames := []string{}
+	roleNames := []string{}
+	members := make([]*Member, 0, len(r.desired.Members))
+	for _, m := range r.desired.Members {
+		userNames = append(userNames, m.UserName)
+		member := &Member{
+			UserName:  m.UserName,
+			IsManager: m.IsManager,
+			Roles:     make([]*BindRole, 0, len(m.Roles)),
+		}
+		for _, role := range m.Roles {
+			if !r.instanceExist(role.InstanceName) {
+				return nil, fmt.Errorf("instance %s of member %s not exist", role.InstanceName, m.UserName)
+			}
+			roleNames = append(roleNames, role.RoleNames...)
+			member.Roles = append(member.Roles, &BindRole{
+				InstanceName: role.InstanceName,
+				RoleNames:    append([]string{}, role.RoleNames...),
+			})
+		}
+		sortBindRoles(member.Roles)
+		members = append(members, member)
+	}
+	if len(userNames) > 0 {
+		if _, err := r.s.GetAndCheckUserExist(userNames); err != nil {
+			return nil, err
+		}
+	}
+	if len(roleNames) > 0 {
+		if _, err := r.s.GetAndCheckRoleExist(roleNames); err != nil {
+			return nil, err
+		}
+	}
+	sort.Slice(members, func(i, j int) bool { return members[i].UserName < members[j].UserName })
+	return members, nil
+}
+
+// resolveAuditPlans fills the default values of audit plan as the CreateAuditPlan API, instances
+// is the resolved instances of bundle.
+func (r *resolver) resolveAuditPlans(instances []*Instance) ([]*AuditPlan, error) {
+	auditPlans := make([]*AuditPlan, 0, len(r.desired.AuditPlans))
+	for _, ap := range r.desired.AuditPlans {
+		auditPlan := &AuditPlan{
+			Name:             ap.Name,
+			Type:             ap.Type,
+			CronExpression:   ap.CronExpression,
+			DBType:           ap.DBType,
+			InstanceName:     ap.InstanceName,
+			InstanceDatabase: ap.InstanceDatabase,
+			RuleTemplateName: ap.RuleTemplateName,
+		}
+		if auditPlan.Type == "" {
+			auditPlan.Type = auditplan.TypeDefault
+		}
+
+		var instance *Instance
+		if ap.InstanceName != "" {
+			for _, i := range append(instances, r.current.Instances...) {
+				if i.Name == ap.InstanceName {
+					instance = i
+					break
+				}
+			}
+			if instance == nil {
+				return nil, fmt.Errorf("instance %s of audit plan %s not exist", ap.InstanceName, ap.Name)
+			}
+			if ap.DBType != "" && ap.DBType != instance.DBType {
+				return nil, fmt.Errorf("db type of audit plan %s is different from instance %s", ap.Name, ap.InstanceName)
+			}
+			auditPlan.DBType = instance.DBType
+		}
+		if !dry.StringInSlice(auditPlan.DBType, driver.GetPluginManager().AllDrivers()) {
+			return nil, &driverV2.DriverNotSupportedError{DriverTyp: auditPlan.DBType}
+		}
+
+		meta, err := auditplan.GetMeta(auditPlan.Type)
+		if err != nil {
+			return nil, err
+		}
+		if meta.InstanceType != auditplan.InstanceTypeAll && meta.InstanceType != auditPlan.DBType {
+			return nil, fmt.Errorf("audit plan type %s not found", auditPlan.Type)
+		}
+		for k, v := range ap.Params {
+			if err := meta.Params.SetParamValue(k, v); err != nil {
+				return nil, fmt.Errorf("set param of audit plan %s failed: %v", ap.Name, err)
+			}
+		}
+		// the secret params are only compared and applied when they are set in bundle
+		auditPlan.Params = auditPlanParamsToMap(meta.Params)
+		for k, v := range ap.Params {
+			if auditplan.IsSecretParam(k) {
+				auditPlan.Params[k] = v
+			}
+		}
+
+		// 规则模板选择规则: 指定规则模板 -- > 数据源绑定的规则模板 -- > 数据库类型默认模板
+		switch {
+		case auditPlan.RuleTemplateName != "":
+			if _, err := r.ruleTemplateDBType(auditPlan.RuleTemplateName); err != nil {
+				return nil, err
+			}
+		case instance != nil && instance.RuleTemplateName != "":
+			auditPlan.RuleTemplateName = instance.RuleTemplateName
+		default:
+			auditPlan.RuleTemplateName = r.s.GetDefaultRuleTemplateName(auditPlan.DBType)
+		}
+		auditPlans = append(auditPlans, auditPlan)
+	}
+	return auditPlans, nil
+}
+
+func (r *resolver) resolveSqlWhitelist() ([]*SqlWhitelist, error) {
+	whitelist := make([]*SqlWhitelist, 0, len(r.desired.SqlWhitelist))
+	for _, w := range r.desired.SqlWhitelist {
+		item := &SqlWhitelist{
+			Value:     w.Value,
+			MatchType: w.MatchType,
+			Desc:      w.Desc,
+		}
+		if item.MatchType == "" {
+			item.MatchType = model.SQLWhitelistExactMatch
+		}
+		if item.MatchType != model.SQLWhitelistExactMatch && item.MatchType != model.SQLWhitelistFPMatch {
+			return nil, fmt.Errorf("match type %s of sql whitelist is invalid", item.MatchType)
+		}
+		whitelist = append(whitelist, item)
+	}
+	return whitelist, nil
+}
+
+func convertMaintenanceTimesToPeriods(times []*MaintenanceTime) model.Periods {
+	periods := make(model.Periods, 0, len(times))
+	for _, t := range times {
+		periods = append(periods, &model.Period{
+			StartHour:   t.StartHour,
+			StartMinute: t.StartMinute,
+			EndHour:     t.EndHour,
+			EndMinute:   t.EndMinute,
+		})
+	}
+	return periods
+}
@@ -4,10 +4,103 @@
 package server
 
 import (
+	"fmt"
+	"regexp"
+	"sort"
+
+	"github.com/actiontech/sqle/sqle/driver/mysql/util"
 	driverV2 "github.com/actiontech/sqle/sqle/driver/v2"
 	"github.com/actiontech/sqle/sqle/model"
+	"github.com/actiontech/sqle/sqle/pkg/customrule"
 
+	"github.com/pingcap/parser/ast"
 	"github.com/sirupsen/logrus"
 )
 
-func CustomRuleAudit(l *logrus.Entry, task *model.Task, sqls []string, results []*driverV2.AuditResults, customRules []*model.CustomRule) {}
+// CustomRuleMatcher returns true if the rule is triggered by the SQL.
+type CustomRuleMatcher func(info *customrule.SQLInfo) (bool, error)
+
+// CompileCustomRule checks the script of custom rule and returns the matcher of it.
+func CompileCustomRule(scriptType, script string) (CustomRuleMatcher, error) {
+	switch scriptType {
+	case model.CustomRuleScriptTypeRegular, "":
+		re, err := regexp.Compile(script)
+		if err != nil {
+			return nil, fmt.Errorf("invalid regular expression: %v", err)
+		}
+		return func(info *customrule.SQLInfo) (bool, error) {
+			return re.MatchString(info.SQL), nil
+		}, nil
+	case model.CustomRuleScriptTypeExpression:
+		expr, err := customrule.CompileExpression(script, customrule.Variables)
+		if err != nil {
+			return nil, err
+		}
+		return func(info *customrule.SQLInfo) (bool, error) {
+			return expr.Eval(info.Env())
+		}, nil
+	}
+	return nil, fmt.Errorf("unknown script type %s", scriptType)
+}
+
+// newCustomRuleSQLInfo prefers the MySQL parser to extract the tables and the fingerprint, and
+// falls back to the lexical analysis for the other DB types or the SQL which can not be parsed.
+func newCustomRuleSQLInfo(dbType, sql string) *customrule.SQLInfo {
+	info := customrule.NewSQLInfo(dbType, sql)
+	if dbType != driverV2.DriverTypeMySQL {
+		return info
+	}
+	node, err := util.ParseOneSql(sql)
+	if err != nil {
+		return info
+	}
+	extractor := &util.TableNameExtractor{TableNames: map[string]*ast.TableName{}}
+	node.Accept(extractor)
+	tables := make([]string, 0, len(extractor.TableNames))
+	for _, table := range extractor.TableNames {
+		if table.Schema.O != "" {
+			tables = append(tables, fmt.Sprintf("%s.%s", table.Schema.O, table.Name.O))
+		} else {
+			tables = append(tables, table.Name.O)
+		}
+	}
+	sort.Strings(tables)
+	info.Tables = tables
+	if fp, err := util.Fingerprint(sql, true); err == nil {
+		info.Fingerprint = fp
+	}
+	return info
+}
+
+func CustomRuleAudit(l *logrus.Entry, task *model.Task, sqls []string, results []*driverV2.AuditResults, customRules []*model.CustomRule) {
+	if len(customRules) == 0 {
+		return
+	}
+
+	matchers := make([]CustomRuleMatcher, len(customRules))
+	for i, rule := range customRules {
+		matcher, err := CompileCustomRule(rule.ScriptType, rule.RuleScript)
+		if err != nil {
+			l.Warnf("compile custom rule %s failed, skip it, err: %v", rule.RuleId, err)
+			continue
+		}
+		matchers[i] = matcher
+	}
+
+	for i, sql := range sqls {
+		info := newCustomRuleSQLInfo(task.DBType, sql)
+		for j, rule := range customRules {
+			if matchers[j] == nil {
+				continue
+			}
+			matched, err := matchers[j](info)
+			if err != nil {
+				l.Warnf("evaluate custom rule %s on sql %s failed, err: %v", rule.RuleId, sql, err)
+				continue
+			}
+			if matched {
+				results[i].Add(driverV2.RuleLevel(rule.Level), rule.RuleId, rule.Desc)
+			}
+		}
+	}
+}
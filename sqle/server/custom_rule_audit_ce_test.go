@@ -0,0 +1,78 @@
+//go:build !enterprise
+// +build !enterprise
+
+package server
+
+import (
+	"testing"
+
+	driverV2 "github.com/actiontech/sqle/sqle/driver/v2"
+	"github.com/actiontech/sqle/sqle/log"
+	"github.com/actiontech/sqle/sqle/model"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCustomRuleAudit(t *testing.T) {
+	rules := []*model.CustomRule{
+		{
+			RuleId:     "r1",
+			Desc:       "禁止使用 SELECT *",
+			Level:      string(driverV2.RuleLevelWarn),
+			RuleScript: `(?i)select\s+\*`,
+			ScriptType: model.CustomRuleScriptTypeRegular,
+		},
+		{
+			RuleId:     "r2",
+			Desc:       "DELETE 语句必须带 WHERE 条件",
+			Level:      string(driverV2.RuleLevelError),
+			RuleScript: `sql_type == "dml" && hasPrefix(fingerprint, "delete") && !match("(?i)\\bwhere\\b", sql)`,
+			ScriptType: model.CustomRuleScriptTypeExpression,
+		},
+		{
+			RuleId:     "r3",
+			Desc:       "禁止操作 t_audit 表",
+			Level:      string(driverV2.RuleLevelNotice),
+			RuleScript: `contains(tables, "t_audit")`,
+			ScriptType: model.CustomRuleScriptTypeExpression,
+		},
+		{
+			RuleId:     "invalid",
+			Desc:       "invalid",
+			Level:      string(driverV2.RuleLevelError),
+			RuleScript: `(`,
+		},
+	}
+	sqls := []string{
+		"select * from t1",
+		"delete from t1",
+		"delete from t_audit where id = 1",
+		"update t1 set v = 1 where id = 1",
+	}
+
+	for _, dbType := range []string{driverV2.DriverTypeMySQL, "PostgreSQL"} {
+		results := make([]*driverV2.AuditResults, len(sqls))
+		for i := range results {
+			results[i] = driverV2.NewAuditResults()
+		}
+		CustomRuleAudit(log.NewEntry(), &model.Task{DBType: dbType}, sqls, results, rules)
+
+		assert.Equal(t, driverV2.RuleLevelWarn, results[0].Level(), dbType)
+		assert.Equal(t, "[warn]禁止使用 SELECT *", results[0].Message(), dbType)
+		assert.Equal(t, driverV2.RuleLevelError, results[1].Level(), dbType)
+		assert.Equal(t, "r2", results[1].Results[0].RuleName, dbType)
+		assert.Equal(t, driverV2.RuleLevelNotice, results[2].Level(), dbType)
+		assert.False(t, results[3].HasResult(), dbType)
+	}
+}
+
+func TestCompileCustomRule(t *testing.T) {
+	_, err := CompileCustomRule(model.CustomRuleScriptTypeRegular, `^drop`)
+	assert.NoError(t, err)
+	_, err = CompileCustomRule(model.CustomRuleScriptTypeExpression, `sql_type == "ddl"`)
+	assert.NoError(t, err)
+	_, err = CompileCustomRule(model.CustomRuleScriptTypeExpression, `^drop`)
+	assert.Error(t, err)
+	_, err = CompileCustomRule("lua", `return true`)
+	assert.Error(t, err)
+}
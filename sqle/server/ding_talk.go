@@ -87,7 +87,7 @@ func (j *DingTalkJob) dingTalkRotation(entry *logrus.Entry) {
 						continue
 					}
 
-					if nextStep.Template.Typ != model.WorkflowStepTypeSQLExecute {
+					if workflow.Record.CurrentWorkflowStepId == nextStep.ID && nextStep.Template.Typ != model.WorkflowStepTypeSQLExecute {
 						imPkg.CreateApprove(strconv.Itoa(int(workflow.ID)))
 					}
 
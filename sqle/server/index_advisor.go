@@ -0,0 +1,153 @@
+package server
+
+import (
+	"sort"
+	"strings"
+
+	rulepkg "github.com/actiontech/sqle/sqle/driver/mysql/rule"
+	"github.com/actiontech/sqle/sqle/model"
+)
+
+// IndexAdvice is the index advice deduplicated across the SQLs of a task or an
+// audit plan report.
+type IndexAdvice struct {
+	TableName        string
+	Columns          []string
+	CreateIndexSQL   string
+	CoveringIndexSQL string
+	// EstimatedRows is the sum of the rows examined by the SQLs which can be
+	// optimized by the index, it is the estimated benefit of the index.
+	EstimatedRows int64
+	// SQLNumbers is the numbers of the SQLs which can be optimized by the index.
+	SQLNumbers []uint
+}
+
+type indexAdvisor struct {
+	advices map[string]*IndexAdvice
+}
+
+func newIndexAdvisor() *indexAdvisor {
+	return &indexAdvisor{advices: map[string]*IndexAdvice{}}
+}
+
+func (a *indexAdvisor) add(number uint, results model.AuditResults) {
+	for _, result := range results {
+		if result.RuleName != rulepkg.ConfigOptimizeIndexEnabled {
+			continue
+		}
+		advice := result.IndexAdvice
+		if advice == nil {
+			continue
+		}
+
+		key := strings.ToLower(advice.TableName + "." + strings.Join(advice.Columns, ","))
+		ia, ok := a.advices[key]
+		if !ok {
+			ia = &IndexAdvice{
+				TableName:        advice.TableName,
+				Columns:          advice.Columns,
+				CreateIndexSQL:   advice.CreateIndexSQL,
+				CoveringIndexSQL: advice.CoveringIndexSQL,
+			}
+			a.advices[key] = ia
+		}
+		if ia.CoveringIndexSQL == "" {
+			ia.CoveringIndexSQL = advice.CoveringIndexSQL
+		}
+		ia.merge(advice.EstimatedRows, number)
+	}
+}
+
+func (ia *IndexAdvice) merge(rows int64, numbers ...uint) {
+	ia.EstimatedRows += rows
+	for _, number := range numbers {
+		exist := false
+		for _, n := range ia.SQLNumbers {
+			if n == number {
+				exist = true
+				break
+			}
+		}
+		if !exist {
+			ia.SQLNumbers = append(ia.SQLNumbers, number)
+		}
+	}
+}
+
+// isPrefixOf returns true if the index is the leftmost prefix of the other index,
+// the query which can use the index can use the other index as well.
+func (ia *IndexAdvice) isPrefixOf(other *IndexAdvice) bool {
+	if !strings.EqualFold(ia.TableName, other.TableName) || len(ia.Columns) >= len(other.Columns) {
+		return false
+	}
+	for i, column := range ia.Columns {
+		if !strings.EqualFold(column, other.Columns[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// result returns the deduplicated advices order by the estimated benefit, the
+// advice which is the leftmost prefix of another advice is merged into it.
+func (a *indexAdvisor) result() []*IndexAdvice {
+	advices := make([]*IndexAdvice, 0, len(a.advices))
+	for _, advice := range a.advices {
+		advices = append(advices, advice)
+	}
+	// the longer index is checked first so that the shorter one can be merged into it.
+	sort.Slice(advices, func(i, j int) bool {
+		if len(advices[i].Columns) != len(advices[j].Columns) {
+			return len(advices[i].Columns) > len(advices[j].Columns)
+		}
+		return advices[i].CreateIndexSQL < advices[j].CreateIndexSQL
+	})
+
+	result := []*IndexAdvice{}
+	for _, advice := range advices {
+		merged := false
+		for _, r := range result {
+			if advice.CreateIndexSQL != "" && r.CreateIndexSQL != "" && advice.isPrefixOf(r) {
+				r.merge(advice.EstimatedRows, advice.SQLNumbers...)
+				merged = true
+				break
+			}
+		}
+		if !merged {
+			result = append(result, advice)
+		}
+	}
+
+	for _, advice := range result {
+		sort.Slice(advice.SQLNumbers, func(i, j int) bool {
+			return advice.SQLNumbers[i] < advice.SQLNumbers[j]
+		})
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		if result[i].EstimatedRows != result[j].EstimatedRows {
+			return result[i].EstimatedRows > result[j].EstimatedRows
+		}
+		return len(result[i].SQLNumbers) > len(result[j].SQLNumbers)
+	})
+	return result
+}
+
+// GetTaskIndexAdvices collects the index advices from the audit results of the
+// task SQLs, the task SQLs should be preloaded.
+func GetTaskIndexAdvices(task *model.Task) []*IndexAdvice {
+	advisor := newIndexAdvisor()
+	for _, sql := range task.ExecuteSQLs {
+		advisor.add(sql.Number, sql.AuditResults)
+	}
+	return advisor.result()
+}
+
+// GetAuditPlanReportIndexAdvices collects the index advices from the audit
+// results of the audit plan report SQLs, the report SQLs should be preloaded.
+func GetAuditPlanReportIndexAdvices(report *model.AuditPlanReportV2) []*IndexAdvice {
+	advisor := newIndexAdvisor()
+	for _, sql := range report.AuditPlanReportSQLs {
+		advisor.add(sql.Number, sql.AuditResults)
+	}
+	return advisor.result()
+}
@@ -0,0 +1,88 @@
+package server
+
+import (
+	"testing"
+
+	rulepkg "github.com/actiontech/sqle/sqle/driver/mysql/rule"
+	driverV2 "github.com/actiontech/sqle/sqle/driver/v2"
+	"github.com/actiontech/sqle/sqle/model"
+	indexoptimizer "github.com/actiontech/sqle/sqle/pkg/optimizer/index"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newIndexAdviceResult(advice *driverV2.IndexAdvice) model.AuditResult {
+	return model.AuditResult{
+		Level:       "notice",
+		Message:     indexoptimizer.AdviceMessage(advice),
+		RuleName:    rulepkg.ConfigOptimizeIndexEnabled,
+		IndexAdvice: advice,
+	}
+}
+
+func TestGetTaskIndexAdvices(t *testing.T) {
+	adviceA := &driverV2.IndexAdvice{
+		TableName:      "t1",
+		Columns:        []string{"a"},
+		CreateIndexSQL: "CREATE INDEX idx_t1_a ON t1 (a)",
+		EstimatedRows:  100,
+	}
+	adviceAB := &driverV2.IndexAdvice{
+		TableName:        "t1",
+		Columns:          []string{"a", "b"},
+		CreateIndexSQL:   "CREATE INDEX idx_t1_a_b ON t1 (a, b)",
+		CoveringIndexSQL: "CREATE INDEX idx_t1_a_b_c ON t1 (a, b, c)",
+		EstimatedRows:    10,
+	}
+	adviceC := &driverV2.IndexAdvice{
+		TableName:      "t2",
+		Columns:        []string{"c"},
+		CreateIndexSQL: "CREATE INDEX idx_t2_c ON t2 (c)",
+		EstimatedRows:  50,
+	}
+	adviceFunc := &driverV2.IndexAdvice{
+		TableName: "t2",
+		Columns:   []string{"LEFT(`d`, 5)"},
+	}
+
+	task := &model.Task{
+		ExecuteSQLs: []*model.ExecuteSQL{
+			{BaseSQL: model.BaseSQL{Number: 1}, AuditResults: model.AuditResults{newIndexAdviceResult(adviceA)}},
+			{BaseSQL: model.BaseSQL{Number: 2}, AuditResults: model.AuditResults{newIndexAdviceResult(adviceAB)}},
+			{BaseSQL: model.BaseSQL{Number: 3}, AuditResults: model.AuditResults{
+				newIndexAdviceResult(adviceC),
+				newIndexAdviceResult(adviceFunc),
+				{Level: "error", Message: "除了自增列及大字段列之外，每个列都必须添加默认值", RuleName: "ddl_check_column_without_default"},
+			}},
+			{BaseSQL: model.BaseSQL{Number: 4}, AuditResults: model.AuditResults{
+				newIndexAdviceResult(adviceC),
+				// the result without advice is ignored.
+				{Level: "notice", Message: "建议从表 t3 的以下列中 [e] 选取合适的列添加索引", RuleName: rulepkg.ConfigOptimizeIndexEnabled},
+			}},
+		},
+	}
+
+	advices := GetTaskIndexAdvices(task)
+	assert.Equal(t, []*IndexAdvice{
+		{
+			TableName:        "t1",
+			Columns:          []string{"a", "b"},
+			CreateIndexSQL:   "CREATE INDEX idx_t1_a_b ON t1 (a, b)",
+			CoveringIndexSQL: "CREATE INDEX idx_t1_a_b_c ON t1 (a, b, c)",
+			EstimatedRows:    110,
+			SQLNumbers:       []uint{1, 2},
+		},
+		{
+			TableName:      "t2",
+			Columns:        []string{"c"},
+			CreateIndexSQL: "CREATE INDEX idx_t2_c ON t2 (c)",
+			EstimatedRows:  100,
+			SQLNumbers:     []uint{3, 4},
+		},
+		{
+			TableName:  "t2",
+			Columns:    []string{"LEFT(`d`, 5)"},
+			SQLNumbers: []uint{3},
+		},
+	}, advices)
+}
@@ -17,12 +17,12 @@ var OnlyRunOnLeaderJobs = []func(entry *logrus.Entry) ServerJob{
 	NewCleanJob,
 	NewDingTalkJob,
 	NewFeishuJob,
-}
-
-var RunOnAllJobs = []func(entry *logrus.Entry) ServerJob{
+	// the scheduled and queued workflows must be executed only once in cluster.
 	NewWorkflowScheduleJob,
 }
 
+var RunOnAllJobs = []func(entry *logrus.Entry) ServerJob{}
+
 type ServerJobManager struct {
 	clusterNode         cluster.Node
 	onlyRunOnLeaderJobs []ServerJob
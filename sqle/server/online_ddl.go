@@ -0,0 +1,150 @@
+package server
+
+import (
+	"os"
+	"time"
+
+	"github.com/actiontech/sqle/sqle/driver/mysql/onlineddl"
+	"github.com/actiontech/sqle/sqle/driver/mysql/util"
+	driverV2 "github.com/actiontech/sqle/sqle/driver/v2"
+	"github.com/actiontech/sqle/sqle/model"
+	"github.com/actiontech/sqle/sqle/server/cluster"
+
+	"github.com/pingcap/parser/ast"
+)
+
+// onlineDDLProgressInterval is the interval of saving the progress of gh-ost migration and
+// applying the cut-over requested on other nodes.
+const onlineDDLProgressInterval = 2 * time.Second
+
+// onlineDDLMigrationKey returns false if the SQL can not be executed by gh-ost, the key must
+// be the same as the one generated by the MySQL driver, and the driver uses the task schema
+// as the default schema.
+func onlineDDLMigrationKey(task *model.Task, query string) (string, bool) {
+	inst := task.Instance
+	if inst == nil || inst.DbType != driverV2.DriverTypeMySQL {
+		return "", false
+	}
+	node, err := util.ParseOneSql(query)
+	if err != nil {
+		return "", false
+	}
+	stmt, ok := node.(*ast.AlterTableStmt)
+	if !ok {
+		return "", false
+	}
+	schema := stmt.Table.Schema.String()
+	if schema == "" {
+		schema = task.Schema
+	}
+	return onlineddl.MigrationKey(inst.Host, inst.Port, schema, stmt.Table.Name.String()), true
+}
+
+func convertOnlineDDLProgress(p *onlineddl.Progress) *model.OnlineDDLProgress {
+	return &model.OnlineDDLProgress{
+		RowsCopied:         p.RowsCopied,
+		RowsEstimate:       p.RowsEstimate,
+		ProgressPct:        p.ProgressPct,
+		ETASeconds:         p.ETASeconds,
+		LagMillis:          p.LagMillis,
+		Throttled:          p.Throttled,
+		ThrottleReason:     p.ThrottleReason,
+		PostponingCutOver:  p.PostponingCutOver,
+		CutOverPostponable: p.CutOverPostponable,
+	}
+}
+
+// watchOnlineDDLProgress saves the progress of gh-ost migration of the SQL periodically until
+// the returned function is called, the returned function returns the last progress, it is nil
+// if the SQL is not executed by gh-ost. The migration is registered in storage with the node
+// which runs it, the cut-over requested on other nodes is applied by the watcher.
+func (a *action) watchOnlineDDLProgress(executeSQL *model.ExecuteSQL) func() *model.OnlineDDLProgress {
+	key, ok := onlineDDLMigrationKey(a.task, executeSQL.Content)
+	if !ok {
+		return func() *model.OnlineDDLProgress { return nil }
+	}
+	st := model.GetStorage()
+	host, _ := os.Hostname()
+	err := st.SaveOnlineDDLMigration(&model.OnlineDDLMigration{
+		TaskId:       a.task.ID,
+		ExecuteSQLId: executeSQL.ID,
+		MigrationKey: key,
+		ServerId:     cluster.ServerId,
+		Host:         host,
+	})
+	if err != nil {
+		a.entry.Warnf("register gh-ost migration failed, the cut-over can not be controlled, err: %v", err)
+	}
+
+	var last *model.OnlineDDLProgress
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		ticker := time.NewTicker(onlineDDLProgressInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				request, err := st.TakeOnlineDDLCutOverRequest(a.task.ID, executeSQL.ID)
+				if err != nil {
+					a.entry.Warnf("get cut-over request of gh-ost migration failed, err: %v", err)
+				} else if request != "" {
+					if err := cutOverOnlineDDL(key, request == model.OnlineDDLCutOverRequestPostpone); err != nil {
+						a.entry.Warnf("apply cut-over request %s of gh-ost migration failed, err: %v", request, err)
+					}
+				}
+
+				p, ok := onlineddl.GetProgress(key)
+				if !ok {
+					continue
+				}
+				last = convertOnlineDDLProgress(p)
+				if err := st.UpdateExecuteSQLOnlineDDLProgress(executeSQL.ID, last); err != nil {
+					a.entry.Warnf("update progress of gh-ost migration failed, err: %v", err)
+				}
+			}
+		}
+	}()
+
+	return func() *model.OnlineDDLProgress {
+		close(done)
+		<-stopped
+		if err := st.DeleteOnlineDDLMigration(a.task.ID, executeSQL.ID); err != nil {
+			a.entry.Warnf("unregister gh-ost migration failed, err: %v", err)
+		}
+		return last
+	}
+}
+
+func cutOverOnlineDDL(key string, postpone bool) error {
+	if postpone {
+		return onlineddl.PostponeCutOver(key)
+	}
+	return onlineddl.CutOver(key)
+}
+
+// CutOverOnlineDDL triggers or postpones the cut-over of the gh-ost migration of the executing task.
+// The migration running on other node is controlled by that node, the request is applied within
+// onlineDDLProgressInterval.
+func CutOverOnlineDDL(taskID uint, postpone bool) error {
+	st := model.GetStorage()
+	m, exist, err := st.GetOnlineDDLMigration(taskID)
+	if err != nil {
+		return err
+	}
+	if !exist {
+		return onlineddl.ErrMigrationNotFound
+	}
+	if m.ServerId == cluster.ServerId {
+		return cutOverOnlineDDL(m.MigrationKey, postpone)
+	}
+
+	request := model.OnlineDDLCutOverRequestCutOver
+	if postpone {
+		request = model.OnlineDDLCutOverRequestPostpone
+	}
+	return st.RequestOnlineDDLCutOver(taskID, request)
+}
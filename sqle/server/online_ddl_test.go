@@ -0,0 +1,32 @@
+package server
+
+import (
+	"testing"
+
+	driverV2 "github.com/actiontech/sqle/sqle/driver/v2"
+	"github.com/actiontech/sqle/sqle/model"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOnlineDDLMigrationKey(t *testing.T) {
+	task := &model.Task{
+		Schema:   "db1",
+		Instance: &model.Instance{DbType: driverV2.DriverTypeMySQL, Host: "10.0.0.1", Port: "3306"},
+	}
+
+	key, ok := onlineDDLMigrationKey(task, "alter table t1 add column v4 int")
+	assert.True(t, ok)
+	assert.Equal(t, "10.0.0.1:3306/db1.t1", key)
+
+	key, ok = onlineDDLMigrationKey(task, "alter table db2.T2 add index idx_v1(v1)")
+	assert.True(t, ok)
+	assert.Equal(t, "10.0.0.1:3306/db2.T2", key)
+
+	_, ok = onlineDDLMigrationKey(task, "update t1 set v1 = 1")
+	assert.False(t, ok)
+
+	task.Instance.DbType = driverV2.DriverTypePostgreSQL
+	_, ok = onlineDDLMigrationKey(task, "alter table t1 add column v4 int")
+	assert.False(t, ok)
+}
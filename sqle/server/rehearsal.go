@@ -0,0 +1,247 @@
+package server
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/actiontech/sqle/sqle/driver/mysql/util"
+	"github.com/actiontech/sqle/sqle/model"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/pingcap/parser/ast"
+)
+
+// rehearseTimeout is the max time of rehearsal, the transaction holds the row locks
+// until it is rolled back, so it should not last too long.
+const rehearseTimeout = 10 * time.Minute
+
+var (
+	errRehearseImplicitCommit = fmt.Errorf("非 DML 语句可能隐式提交事务，不支持预演")
+	errRehearseSelectInto     = fmt.Errorf("SELECT ... INTO 语句会写入文件或变量，不支持预演")
+	errRehearsePreviousFailed = fmt.Errorf("前序语句预演失败，跳过预演")
+)
+
+// rehearseTransactionalEngines are the engines which support rollback, the changes of the table
+// with other engines, e.g. MyISAM, are kept even if the transaction is rolled back.
+var rehearseTransactionalEngines = map[string]struct{}{
+	"innodb":  {},
+	"tokudb":  {},
+	"rocksdb": {},
+}
+
+// newMySQLDB opens the connection pool of MySQL instance, it keeps only one connection so that
+// the session variables and transaction are always on the same connection.
+func newMySQLDB(inst *model.Instance, schema string) (*sql.DB, error) {
+	cfg := mysql.NewConfig()
+	cfg.User = inst.User
+	cfg.Passwd = inst.Password
+	cfg.Net = "tcp"
+	cfg.Addr = net.JoinHostPort(inst.Host, inst.Port)
+	cfg.DBName = schema
+	cfg.Timeout = 10 * time.Second
+	db, err := sql.Open("mysql", cfg.FormatDSN())
+	if err != nil {
+		return nil, err
+	}
+	db.SetMaxOpenConns(1)
+	return db, nil
+}
+
+// rehearse executes the DMLs of task in a transaction which is always rolled back, it records the
+// actual affected rows, warnings and elapsed time of each DML for approvers. The driver Tx commits
+// the transaction and does not return the warnings, so the rehearsal uses its own connection.
+func (a *action) rehearse() error {
+	st := model.GetStorage()
+	task := a.task
+
+	a.entry.Info("start rehearsal...")
+
+	for _, executeSQL := range task.ExecuteSQLs {
+		executeSQL.RehearseStatus = model.SQLRehearseStatusInitialized
+		executeSQL.RehearseResult = ""
+		executeSQL.RehearseRowAffects = 0
+		executeSQL.RehearseWarnings = ""
+		executeSQL.RehearseElapsedMs = 0
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), rehearseTimeout)
+	defer cancel()
+
+	db, err := newMySQLDB(task.Instance, task.Schema)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	rehearseErr := rehearseExecuteSQLs(ctx, db, task.ExecuteSQLs)
+	if rehearseErr != nil {
+		a.entry.Errorf("rehearsal failed, err: %v", rehearseErr)
+	}
+
+	if err := st.UpdateExecuteSQLs(task.ExecuteSQLs); err != nil {
+		return err
+	}
+	a.entry.Info("rehearsal is completed")
+	return rehearseErr
+}
+
+// rehearsalStmt is the statement which can be rehearsed in transaction.
+type rehearsalStmt struct {
+	// tables are the tables referred by the statement, the statement is not rehearsed if any of
+	// them is not transactional, since the change of it can not be rolled back.
+	tables   []*ast.TableName
+	readOnly bool
+}
+
+// parseRehearsalStmt returns error if the SQL can not be rehearsed. Only DMLs are rehearsed, the
+// other statements, e.g. DDL, LOCK TABLES and transaction control, may commit the transaction implicitly.
+func parseRehearsalStmt(query string) (*rehearsalStmt, error) {
+	node, err := util.ParseOneSql(query)
+	if err != nil {
+		return nil, err
+	}
+	stmt := &rehearsalStmt{}
+	switch n := node.(type) {
+	case *ast.InsertStmt, *ast.UpdateStmt, *ast.DeleteStmt:
+	case *ast.SelectStmt:
+		if n.SelectIntoOpt != nil {
+			return nil, errRehearseSelectInto
+		}
+		stmt.readOnly = true
+	case *ast.UnionStmt:
+		stmt.readOnly = true
+	default:
+		return nil, errRehearseImplicitCommit
+	}
+
+	extractor := &util.TableNameExtractor{TableNames: map[string]*ast.TableName{}}
+	node.Accept(extractor)
+	for _, table := range extractor.TableNames {
+		stmt.tables = append(stmt.tables, table)
+	}
+	return stmt, nil
+}
+
+// checkTransactionalTables returns error if any table of the statement is not transactional. The
+// view is rejected as well since the engines of its base tables are unknown.
+func checkTransactionalTables(ctx context.Context, conn *sql.Conn, tables []*ast.TableName) error {
+	for _, table := range tables {
+		var tableType string
+		var engine sql.NullString
+		err := conn.QueryRowContext(ctx, `SELECT TABLE_TYPE, ENGINE FROM information_schema.TABLES
+WHERE TABLE_SCHEMA = COALESCE(NULLIF(?, ''), DATABASE()) AND TABLE_NAME = ?`, table.Schema.O, table.Name.O).
+			Scan(&tableType, &engine)
+		if err == sql.ErrNoRows {
+			// the table does not exist, the statement fails on execution.
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if strings.EqualFold(tableType, "VIEW") {
+			return fmt.Errorf("语句涉及视图 %s，无法确认其基表的存储引擎，不支持预演", table.Name.O)
+		}
+		if _, ok := rehearseTransactionalEngines[strings.ToLower(engine.String)]; !ok {
+			return fmt.Errorf("语句涉及非事务引擎 %s 的表 %s，预演的修改无法回滚，不支持预演", engine.String, table.Name.O)
+		}
+	}
+	return nil
+}
+
+// rehearseExecuteSQLs executes the DMLs in a transaction and rolls back, the SQLs after the first
+// failed one are skipped because the failure may have rolled back the transaction already. The
+// transaction is read only if all the DMLs are SELECT, so that nothing can be changed at all.
+func rehearseExecuteSQLs(ctx context.Context, db *sql.DB, executeSQLs []*model.ExecuteSQL) error {
+	stmts := make([]*rehearsalStmt, len(executeSQLs))
+	readOnly := true
+	for i, executeSQL := range executeSQLs {
+		stmt, err := parseRehearsalStmt(executeSQL.Content)
+		if err != nil {
+			executeSQL.RehearseStatus = model.SQLRehearseStatusSkipped
+			executeSQL.RehearseResult = err.Error()
+			continue
+		}
+		stmts[i] = stmt
+		readOnly = readOnly && stmt.readOnly
+	}
+
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	// the statement after a deadlock or lock wait timeout may start a new transaction,
+	// disable autocommit to make sure that nothing is committed in any case.
+	if _, err := conn.ExecContext(ctx, "SET SESSION autocommit = 0"); err != nil {
+		return err
+	}
+	tx, err := conn.BeginTx(ctx, &sql.TxOptions{ReadOnly: readOnly})
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	var failed bool
+	for i, executeSQL := range executeSQLs {
+		if stmts[i] == nil {
+			continue
+		}
+		if failed {
+			executeSQL.RehearseStatus = model.SQLRehearseStatusSkipped
+			executeSQL.RehearseResult = errRehearsePreviousFailed.Error()
+			continue
+		}
+		if !stmts[i].readOnly {
+			if err := checkTransactionalTables(ctx, conn, stmts[i].tables); err != nil {
+				executeSQL.RehearseStatus = model.SQLRehearseStatusSkipped
+				executeSQL.RehearseResult = err.Error()
+				continue
+			}
+		}
+
+		start := time.Now()
+		result, execErr := tx.ExecContext(ctx, executeSQL.Content)
+		executeSQL.RehearseElapsedMs = time.Since(start).Milliseconds()
+		if execErr != nil {
+			executeSQL.RehearseStatus = model.SQLRehearseStatusFailed
+			executeSQL.RehearseResult = execErr.Error()
+			failed = true
+			continue
+		}
+		if executeSQL.RehearseRowAffects, err = result.RowsAffected(); err != nil {
+			return err
+		}
+		if executeSQL.RehearseWarnings, err = showWarnings(ctx, tx); err != nil {
+			return err
+		}
+		executeSQL.RehearseStatus = model.SQLRehearseStatusSucceeded
+		executeSQL.RehearseResult = model.TaskExecResultOK
+	}
+
+	return tx.Rollback()
+}
+
+func showWarnings(ctx context.Context, tx *sql.Tx) (string, error) {
+	rows, err := tx.QueryContext(ctx, "SHOW WARNINGS")
+	if err != nil {
+		return "", err
+	}
+	defer rows.Close()
+
+	var warnings []string
+	for rows.Next() {
+		var level, code, message string
+		if err := rows.Scan(&level, &code, &message); err != nil {
+			return "", err
+		}
+		warnings = append(warnings, fmt.Sprintf("%s %s: %s", level, code, message))
+	}
+	return strings.Join(warnings, "\n"), rows.Err()
+}
@@ -0,0 +1,87 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/actiontech/sqle/sqle/model"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRehearseExecuteSQLs(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	executeSQLs := []*model.ExecuteSQL{
+		{BaseSQL: model.BaseSQL{Number: 1, Content: "update t1 set v1 = 'a' where v2 > 1"}},
+		{BaseSQL: model.BaseSQL{Number: 2, Content: "alter table t1 add column v3 int"}},
+		{BaseSQL: model.BaseSQL{Number: 3, Content: "delete from t4 where id = 1"}},
+		{BaseSQL: model.BaseSQL{Number: 4, Content: "delete from t2 where id = 1"}},
+		{BaseSQL: model.BaseSQL{Number: 5, Content: "delete from t3 where id = 1"}},
+	}
+	tableQuery := regexp.QuoteMeta("SELECT TABLE_TYPE, ENGINE FROM information_schema.TABLES")
+
+	mock.ExpectExec(regexp.QuoteMeta("SET SESSION autocommit = 0")).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectBegin()
+	mock.ExpectQuery(tableQuery).WithArgs("", "t1").WillReturnRows(
+		sqlmock.NewRows([]string{"TABLE_TYPE", "ENGINE"}).AddRow("BASE TABLE", "InnoDB"))
+	mock.ExpectExec(regexp.QuoteMeta(executeSQLs[0].Content)).WillReturnResult(sqlmock.NewResult(0, 48213))
+	mock.ExpectQuery(regexp.QuoteMeta("SHOW WARNINGS")).WillReturnRows(
+		sqlmock.NewRows([]string{"Level", "Code", "Message"}).
+			AddRow("Warning", "1265", "Data truncated for column 'v1' at row 1"))
+	mock.ExpectQuery(tableQuery).WithArgs("", "t4").WillReturnRows(
+		sqlmock.NewRows([]string{"TABLE_TYPE", "ENGINE"}).AddRow("BASE TABLE", "MyISAM"))
+	mock.ExpectQuery(tableQuery).WithArgs("", "t2").WillReturnRows(sqlmock.NewRows([]string{"TABLE_TYPE", "ENGINE"}))
+	mock.ExpectExec(regexp.QuoteMeta(executeSQLs[3].Content)).WillReturnError(fmt.Errorf("table t2 not exist"))
+	mock.ExpectRollback()
+
+	err = rehearseExecuteSQLs(context.TODO(), db, executeSQLs)
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+
+	assert.Equal(t, model.SQLRehearseStatusSucceeded, executeSQLs[0].RehearseStatus)
+	assert.Equal(t, int64(48213), executeSQLs[0].RehearseRowAffects)
+	assert.Equal(t, "Warning 1265: Data truncated for column 'v1' at row 1", executeSQLs[0].RehearseWarnings)
+
+	assert.Equal(t, model.SQLRehearseStatusSkipped, executeSQLs[1].RehearseStatus)
+	assert.Equal(t, errRehearseImplicitCommit.Error(), executeSQLs[1].RehearseResult)
+
+	assert.Equal(t, model.SQLRehearseStatusSkipped, executeSQLs[2].RehearseStatus)
+	assert.Contains(t, executeSQLs[2].RehearseResult, "MyISAM")
+
+	assert.Equal(t, model.SQLRehearseStatusFailed, executeSQLs[3].RehearseStatus)
+	assert.Equal(t, "table t2 not exist", executeSQLs[3].RehearseResult)
+
+	assert.Equal(t, model.SQLRehearseStatusSkipped, executeSQLs[4].RehearseStatus)
+	assert.Equal(t, errRehearsePreviousFailed.Error(), executeSQLs[4].RehearseResult)
+}
+
+func TestParseRehearsalStmt(t *testing.T) {
+	stmt, err := parseRehearsalStmt("select * from t1 join db2.t2 on t1.id = t2.id")
+	assert.NoError(t, err)
+	assert.True(t, stmt.readOnly)
+	assert.Len(t, stmt.tables, 2)
+
+	stmt, err = parseRehearsalStmt("insert into t1 select * from t2")
+	assert.NoError(t, err)
+	assert.False(t, stmt.readOnly)
+	assert.Len(t, stmt.tables, 2)
+
+	for _, query := range []string{
+		"create table t1 (id int)",
+		"lock tables t1 write",
+		"commit",
+		"set autocommit = 1",
+	} {
+		_, err = parseRehearsalStmt(query)
+		assert.Equal(t, errRehearseImplicitCommit, err, query)
+	}
+
+	_, err = parseRehearsalStmt("select * from t1 into outfile '/tmp/t1'")
+	assert.Equal(t, errRehearseSelectInto, err)
+}
@@ -12,7 +12,7 @@ import (
 	"github.com/actiontech/sqle/sqle/utils"
 	"github.com/go-sql-driver/mysql"
 
-	_ "github.com/actiontech/sqle/sqle/driver/mysql"
+	mysqlDriver "github.com/actiontech/sqle/sqle/driver/mysql"
 	driverV2 "github.com/actiontech/sqle/sqle/driver/v2"
 	"github.com/actiontech/sqle/sqle/errors"
 	"github.com/actiontech/sqle/sqle/log"
@@ -165,6 +165,8 @@ func (s *Sqled) do(action *action) error {
 		err = action.execute()
 	case ActionTypeRollback:
 		err = action.rollback()
+	case ActionTypeRehearse:
+		err = action.rehearse()
 	}
 	if err != nil {
 		action.err = err
@@ -186,6 +188,7 @@ const (
 	ActionTypeAudit = iota + 1
 	ActionTypeExecute
 	ActionTypeRollback
+	ActionTypeRehearse
 )
 
 // Action is an action for the task;
@@ -207,6 +210,18 @@ type action struct {
 
 	customRules []*model.CustomRule
 	rules       []*model.Rule
+
+	// binlog is used to generate the rollback SQL of DML from binlog after it is executed,
+	// it is nil if the instance does not enable it.
+	binlog *binlogRollback
+
+	// paused is synchronized from the task status by the termination watcher, the execution
+	// is blocked before the next non-DML SQL, the next chunk of batch DML or the next group of
+	// adjacent DMLs while it is true. The DMLs of a group are executed in one transaction, so
+	// the execution can not be paused between them.
+	paused bool
+	// batchDMLDone is not nil while a DML is executed in batches, it is closed when the batch DML stops.
+	batchDMLDone chan struct{}
 }
 
 const (
@@ -240,12 +255,27 @@ func (a *action) terminatedFailed() {
 	a.Unlock()
 }
 
+func (a *action) isPaused() bool {
+	a.Lock()
+	defer a.Unlock()
+	return a.paused
+}
+
+func (a *action) setPaused(paused bool) {
+	a.Lock()
+	a.paused = paused
+	a.Unlock()
+}
+
 var (
 	ErrActionExecuteOnExecutedTask       = _errors.New("task has been executed, can not do execute on it")
 	ErrActionExecuteOnNonAuditedTask     = _errors.New("task has not been audited, can not do execute on it")
 	ErrActionRollbackOnRollbackedTask    = _errors.New("task has been rollbacked, can not do rollback on it")
 	ErrActionRollbackOnExecuteFailedTask = _errors.New("task has been executed failed, can not do rollback on it")
 	ErrActionRollbackOnNonExecutedTask   = _errors.New("task has not been executed, can not do rollback on it")
+	ErrActionRehearseOnExecutedTask      = _errors.New("task has been executed, can not do rehearse on it")
+	ErrActionRehearseOnNonAuditedTask    = _errors.New("task has not been audited, can not do rehearse on it")
+	ErrActionRehearseOnNonMySQLTask      = _errors.New("only MySQL task supports rehearse")
 )
 
 // validation validate whether task can do action type(a.typ) or not.
@@ -271,6 +301,16 @@ func (a *action) validation(task *model.Task) error {
 		if !task.HasDoingExecute() {
 			return errors.New(errors.TaskActionInvalid, ErrActionRollbackOnNonExecutedTask)
 		}
+	case ActionTypeRehearse:
+		if task.Instance == nil || task.DBType != driverV2.DriverTypeMySQL {
+			return errors.New(errors.TaskActionInvalid, ErrActionRehearseOnNonMySQLTask)
+		}
+		if task.HasDoingExecute() {
+			return errors.New(errors.TaskActionDone, ErrActionRehearseOnExecutedTask)
+		}
+		if !task.HasDoingAudit() {
+			return errors.New(errors.TaskActionInvalid, ErrActionRehearseOnNonAuditedTask)
+		}
 	}
 	return nil
 }
@@ -324,6 +364,20 @@ func (a *action) audit() (err error) {
 }
 
 func (a *action) terminateExecution(ctx context.Context) error {
+	// the batch DML checks the termination before each chunk, wait for it to stop instead of
+	// killing the connection, so that the executed chunks are recorded.
+	a.Lock()
+	batchDMLDone := a.batchDMLDone
+	a.Unlock()
+	if batchDMLDone != nil {
+		select {
+		case <-batchDMLDone:
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
 	if !driver.GetPluginManager().
 		IsOptionalModuleEnabled(a.task.DBType, driverV2.OptionalModuleKillProcess) {
 		return driver.NewErrPluginAPINotImplement(driverV2.OptionalModuleKillProcess)
@@ -345,6 +399,14 @@ func (a *action) execute() (err error) {
 		return err
 	}
 
+	if a.isRollbackByBinlog() {
+		if err := a.prepareBinlogRollback(); err != nil {
+			a.entry.Warnf("prepare binlog rollback failed, the rollback SQL generated by audit will be used, err: %v", err)
+		} else {
+			defer a.binlog.close()
+		}
+	}
+
 	exeErrChan := make(chan error)
 	terminateErrChan := make(chan error)
 
@@ -359,7 +421,9 @@ func (a *action) execute() (err error) {
 				case <-a.done:
 					return
 				default:
-					if a.GetTaskStatus(st) == model.TaskStatusTerminating {
+					status := a.GetTaskStatus(st)
+					a.setPaused(status == model.TaskStatusPaused)
+					if status == model.TaskStatusTerminating {
 						a.terminate()
 						ctx, cancel := context.WithTimeout(
 							context.Background(), time.Minute*2)
@@ -456,8 +520,24 @@ func (a *action) execTask() (err error) {
 
 		switch nodes[0].Type {
 		case driverV2.SQLTypeDML:
-			txSQLs = append(txSQLs, executeSQL)
-			if i == len(task.ExecuteSQLs)-1 {
+			batch, batchErr := a.newBatchDMLExecutor(executeSQL.Content)
+			if batchErr != nil {
+				a.entry.Warnf("SQL %d can not be executed in batches, err: %v", executeSQL.Number, batchErr)
+			}
+			if batch != nil {
+				if len(txSQLs) > 0 {
+					if err = a.execSQLs(txSQLs); err != nil {
+						return err
+					}
+					txSQLs = nil
+				}
+				if err = a.execBatchDML(executeSQL, batch); err != nil {
+					return err
+				}
+			} else {
+				txSQLs = append(txSQLs, executeSQL)
+			}
+			if i == len(task.ExecuteSQLs)-1 && len(txSQLs) > 0 {
 				if err = a.execSQLs(txSQLs); err != nil {
 					return err
 				}
@@ -470,6 +550,9 @@ func (a *action) execTask() (err error) {
 				}
 				txSQLs = nil
 			}
+			if err = a.waitRunnable(context.TODO(), nil, 0); err != nil {
+				return err
+			}
 			if err = a.execSQL(executeSQL); err != nil {
 				return err
 			}
@@ -487,7 +570,16 @@ func (a *action) execSQL(executeSQL *model.ExecuteSQL) error {
 		return err
 	}
 
+	stopWatching := a.watchOnlineDDLProgress(executeSQL)
 	_, execErr := a.plugin.Exec(context.TODO(), executeSQL.Content)
+	if progress := stopWatching(); progress != nil {
+		if execErr == nil {
+			progress.ProgressPct = 100
+			progress.ETASeconds = 0
+			progress.PostponingCutOver = false
+		}
+		executeSQL.OnlineDDLProgress = progress
+	}
 	if execErr != nil {
 		executeSQL.ExecStatus = model.SQLExecuteStatusFailed
 		executeSQL.ExecResult = execErr.Error()
@@ -511,6 +603,10 @@ func (a *action) execSQL(executeSQL *model.ExecuteSQL) error {
 func (a *action) execSQLs(executeSQLs []*model.ExecuteSQL) error {
 	st := model.GetStorage()
 
+	if err := a.waitRunnable(context.TODO(), nil, 0); err != nil {
+		return err
+	}
+
 	for _, executeSQL := range executeSQLs {
 		executeSQL.ExecStatus = model.SQLExecuteStatusDoing
 	}
@@ -523,6 +619,14 @@ func (a *action) execSQLs(executeSQLs []*model.ExecuteSQL) error {
 		qs = append(qs, executeSQL.Content)
 	}
 
+	var binlogStart binlogPosition
+	if a.binlog != nil {
+		var err error
+		if binlogStart, err = a.binlog.position(); err != nil {
+			a.entry.Warnf("get binlog position failed, err: %v", err)
+		}
+	}
+
 	results, txErr := a.plugin.Tx(context.TODO(), qs...)
 	for idx, executeSQL := range executeSQLs {
 		if txErr != nil {
@@ -552,9 +656,95 @@ func (a *action) execSQLs(executeSQLs []*model.ExecuteSQL) error {
 	if txErr != nil {
 		return txErr
 	}
+	if a.binlog != nil && binlogStart.File != "" {
+		if err := a.updateRollbackSQLsByBinlog(binlogStart, executeSQLs); err != nil {
+			a.entry.Warnf("generate rollback SQL from binlog failed, the rollback SQL generated by audit will be used, err: %v", err)
+		}
+	}
 	return nil
 }
 
+func (a *action) isRollbackByBinlog() bool {
+	inst := a.task.Instance
+	if inst == nil || inst.DbType != driverV2.DriverTypeMySQL {
+		return false
+	}
+	param := inst.AdditionalParams.GetParam(mysqlDriver.ParamRollbackByBinlog)
+	return param != nil && param.Bool()
+}
+
+func (a *action) prepareBinlogRollback() error {
+	binlog, err := newBinlogRollback(a.entry, a.task.Instance)
+	if err != nil {
+		return err
+	}
+	// the rows query event records the text of executed SQL, it is used to match the changes to the executed SQL.
+	if _, err := a.plugin.Exec(context.TODO(), "SET SESSION binlog_rows_query_log_events = ON"); err != nil {
+		binlog.close()
+		return err
+	}
+	// the binlog events are filtered by the connection id of the executing session.
+	marker := fmt.Sprintf("%d-%d", a.task.ID, time.Now().UnixNano())
+	if _, err := a.plugin.Exec(context.TODO(), fmt.Sprintf("SET @%s = '%s'", binlogSessionMarkerVariable, marker)); err != nil {
+		binlog.close()
+		return err
+	}
+	if binlog.threadID, err = binlog.sessionThreadID(marker); err != nil {
+		binlog.close()
+		return err
+	}
+	a.binlog = binlog
+	return nil
+}
+
+// updateRollbackSQLsByBinlog replaces the rollback SQLs of executed SQLs by the ones generated from binlog,
+// the rollback SQL generated by audit is kept if nothing is generated from binlog.
+func (a *action) updateRollbackSQLsByBinlog(start binlogPosition, executeSQLs []*model.ExecuteSQL) error {
+	end, err := a.binlog.position()
+	if err != nil {
+		return err
+	}
+	qs := make([]string, 0, len(executeSQLs))
+	for _, executeSQL := range executeSQLs {
+		qs = append(qs, executeSQL.Content)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
+	defer cancel()
+	contents, err := a.binlog.generate(ctx, start, end, qs)
+	if err != nil {
+		return err
+	}
+
+	rollbackSQLs := make([]*model.RollbackSQL, 0, len(executeSQLs))
+	for idx, executeSQL := range executeSQLs {
+		if contents[idx] == "" {
+			continue
+		}
+		var rollbackSQL *model.RollbackSQL
+		for _, sql := range a.task.RollbackSQLs {
+			if sql.ExecuteSQLId == executeSQL.ID {
+				rollbackSQL = sql
+				break
+			}
+		}
+		if rollbackSQL == nil {
+			rollbackSQL = &model.RollbackSQL{
+				BaseSQL: model.BaseSQL{
+					TaskId: executeSQL.TaskId,
+				},
+				ExecuteSQLId: executeSQL.ID,
+			}
+			a.task.RollbackSQLs = append(a.task.RollbackSQLs, rollbackSQL)
+		}
+		rollbackSQL.Content = contents[idx]
+		rollbackSQLs = append(rollbackSQLs, rollbackSQL)
+	}
+	if len(rollbackSQLs) == 0 {
+		return nil
+	}
+	return model.GetStorage().UpdateRollbackSQLs(rollbackSQLs)
+}
+
 func (a *action) rollback() (err error) {
 	task := a.task
 	a.entry.Info("start rollback SQL")
@@ -101,11 +101,20 @@ func (d *mockDriver) EstimateSQLAffectRows(ctx context.Context, sql string) (*dr
 	return nil, nil
 }
 
+func (d *mockDriver) ListTables(ctx context.Context, conf *driverV2.ListTablesConf) (*driverV2.ListTablesResult, error) {
+	return nil, nil
+}
+
+func (d *mockDriver) GetTableMeta(ctx context.Context, table *driverV2.Table) (*driverV2.TableMeta, error) {
+	return nil, nil
+}
+
 func TestAction_validation(t *testing.T) {
 	actions := map[int]*action{
 		ActionTypeAudit:    {typ: ActionTypeAudit},
 		ActionTypeExecute:  {typ: ActionTypeExecute},
 		ActionTypeRollback: {typ: ActionTypeRollback},
+		ActionTypeRehearse: {typ: ActionTypeRehearse},
 	}
 
 	auditingTask := &model.Task{
@@ -151,6 +160,16 @@ func TestAction_validation(t *testing.T) {
 		{BaseSQL: model.BaseSQL{ExecStatus: model.SQLExecuteStatusInitialized}, AuditStatus: model.SQLAuditStatusFinished},
 	}}
 	assert.EqualError(t, actions[ActionTypeRollback].validation(noExecutedTask), ErrActionRollbackOnNonExecutedTask.Error())
+
+	executingTask.DBType, executingTask.Instance = driverV2.DriverTypeMySQL, &model.Instance{}
+	assert.EqualError(t, actions[ActionTypeRehearse].validation(executingTask), ErrActionRehearseOnExecutedTask.Error())
+	noAuditedTask.DBType, noAuditedTask.Instance = driverV2.DriverTypeMySQL, &model.Instance{}
+	assert.EqualError(t, actions[ActionTypeRehearse].validation(noAuditedTask), ErrActionRehearseOnNonAuditedTask.Error())
+	noExecutedTask.Instance = &model.Instance{}
+	noExecutedTask.DBType = driverV2.DriverTypePostgreSQL
+	assert.EqualError(t, actions[ActionTypeRehearse].validation(noExecutedTask), ErrActionRehearseOnNonMySQLTask.Error())
+	noExecutedTask.DBType = driverV2.DriverTypeMySQL
+	assert.Nil(t, actions[ActionTypeRehearse].validation(noExecutedTask))
 }
 
 func Test_action_audit_UpdateTask(t *testing.T) {
@@ -170,7 +189,7 @@ func Test_action_audit_UpdateTask(t *testing.T) {
 
 	mock.ExpectBegin()
 	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO `execute_sql_detail`")).
-		WithArgs(model.MockTime, model.MockTime, nil, 0, 0, act.task.ExecuteSQLs[0].Content, "", "", 0, "", 0, 0, "", "", model.SQLAuditStatusFinished, `[{"level":"normal","message":"白名单","rule_name":""}]`, "2882fdbb7d5bcda7b49ea0803493467e", "normal").
+		WithArgs(model.MockTime, model.MockTime, nil, 0, 0, act.task.ExecuteSQLs[0].Content, "", "", 0, "", 0, 0, "", "", model.SQLAuditStatusFinished, `[{"level":"normal","message":"白名单","rule_name":""}]`, "2882fdbb7d5bcda7b49ea0803493467e", "normal", "", "", 0, "", 0, float64(0), 0, nil).
 		WillReturnResult(sqlmock.NewResult(1, 1))
 	mock.ExpectCommit()
 
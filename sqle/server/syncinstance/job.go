@@ -0,0 +1,115 @@
+package syncinstance
+
+import (
+	"context"
+	"time"
+
+	"github.com/actiontech/sqle/sqle/model"
+	"github.com/actiontech/sqle/sqle/server"
+
+	"github.com/robfig/cron/v3"
+	"github.com/sirupsen/logrus"
+)
+
+// reloadInterval is the interval to load the changed tasks, the tasks are changed by API on any
+// node of cluster but only scheduled on the leader.
+const reloadInterval = time.Minute
+
+func init() {
+	server.OnlyRunOnLeaderJobs = append(server.OnlyRunOnLeaderJobs, NewSyncInstanceJob)
+}
+
+type scheduledTask struct {
+	entryID  cron.EntryID
+	interval string
+}
+
+// SyncInstanceJob runs the SyncInstanceTask by its cron expression.
+type SyncInstanceJob struct {
+	cron   *cron.Cron
+	tasks  map[uint] /* task id */ scheduledTask
+	logger *logrus.Entry
+	exitCh chan struct{}
+	doneCh chan struct{}
+}
+
+func NewSyncInstanceJob(entry *logrus.Entry) server.ServerJob {
+	return &SyncInstanceJob{
+		cron:   cron.New(),
+		tasks:  map[uint]scheduledTask{},
+		logger: entry.WithField("job", "sync_instance"),
+		exitCh: make(chan struct{}),
+		doneCh: make(chan struct{}),
+	}
+}
+
+func (j *SyncInstanceJob) Start() {
+	j.cron.Start()
+	go func() {
+		j.logger.Infof("start")
+		defer j.logger.Infof("stop")
+
+		j.reload()
+		tick := time.NewTicker(reloadInterval)
+		defer tick.Stop()
+		for {
+			select {
+			case <-j.exitCh:
+				j.doneCh <- struct{}{}
+				return
+			case <-tick.C:
+				j.reload()
+			}
+		}
+	}()
+}
+
+func (j *SyncInstanceJob) Stop() {
+	j.exitCh <- struct{}{}
+	<-j.doneCh
+	<-j.cron.Stop().Done()
+}
+
+func (j *SyncInstanceJob) reload() {
+	tasks, err := model.GetStorage().GetAllSyncInstanceTasks()
+	if err != nil {
+		j.logger.Errorf("get sync instance tasks failed, error: %v", err)
+		return
+	}
+
+	current := make(map[uint]string, len(tasks))
+	for _, task := range tasks {
+		current[task.ID] = task.SyncInstanceInterval
+	}
+	for id, scheduled := range j.tasks {
+		if interval, ok := current[id]; !ok || interval != scheduled.interval {
+			j.cron.Remove(scheduled.entryID)
+			delete(j.tasks, id)
+		}
+	}
+	for id, interval := range current {
+		if _, ok := j.tasks[id]; ok {
+			continue
+		}
+		taskId := id
+		entryID, err := j.cron.AddFunc(interval, func() {
+			j.run(taskId)
+		})
+		if err != nil {
+			j.logger.Errorf("schedule sync instance task %d failed, error: %v", taskId, err)
+			continue
+		}
+		j.tasks[id] = scheduledTask{entryID: entryID, interval: interval}
+	}
+}
+
+func (j *SyncInstanceJob) run(taskId uint) {
+	entry := j.logger.WithField("task_id", taskId)
+	record, err := Run(context.Background(), taskId)
+	if err != nil {
+		entry.Errorf("sync instance failed, error: %v", err)
+		return
+	}
+	entry.Infof("sync instance succeeded, created: %d, updated: %d, deleted: %d, conflicts: %d",
+		record.CreatedNum, record.UpdatedNum, record.DeletedNum, len(record.Conflicts))
+}
@@ -0,0 +1,76 @@
+package syncinstance
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+)
+
+// Source provides the instances which are synchronized into SQLE, e.g. a CMDB.
+type Source interface {
+	// Fetch returns all instances of the source, addr is the URL of SyncInstanceTask.
+	Fetch(ctx context.Context, addr string) ([]*SourceInstance, error)
+}
+
+// SourceInstance is an instance provided by Source, it is identified by name in the project.
+type SourceInstance struct {
+	Name     string `json:"name" yaml:"name"`
+	DbType   string `json:"db_type" yaml:"db_type"`
+	Host     string `json:"host" yaml:"host"`
+	Port     string `json:"port" yaml:"port"`
+	User     string `json:"user" yaml:"user"`
+	Password string `json:"password" yaml:"password"`
+	Desc     string `json:"desc" yaml:"desc"`
+	// AdditionalParams are the additional params of driver, e.g. "service_name" of Oracle.
+	AdditionalParams map[string]string `json:"additional_params" yaml:"additional_params"`
+}
+
+// UnmarshalJSON accepts the port in number, which is common in CMDB.
+func (i *SourceInstance) UnmarshalJSON(b []byte) error {
+	type alias SourceInstance
+	v := struct {
+		*alias
+		Port interface{} `json:"port"`
+	}{alias: (*alias)(i)}
+	if err := json.Unmarshal(b, &v); err != nil {
+		return err
+	}
+	switch port := v.Port.(type) {
+	case nil:
+		i.Port = ""
+	case string:
+		i.Port = port
+	case float64:
+		i.Port = strconv.FormatFloat(port, 'f', -1, 64)
+	default:
+		return fmt.Errorf("port of instance %s is invalid", i.Name)
+	}
+	return nil
+}
+
+const (
+	SourceHTTP = "http"
+	SourceFile = "file"
+)
+
+var sources = map[string]Source{
+	SourceHTTP: &httpSource{},
+	SourceFile: &fileSource{},
+}
+
+func GetSource(name string) (Source, bool) {
+	s, ok := sources[name]
+	return s, ok
+}
+
+// SourceNames returns the names of registered sources in order.
+func SourceNames() []string {
+	names := make([]string, 0, len(sources))
+	for name := range sources {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,106 @@
+package syncinstance
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+
+	yaml "gopkg.in/yaml.v2"
+)
+
+// fileSource reads instances from a local YAML, JSON or CSV file, the format is decided by the
+// extension of file. The first line of CSV is the header, the column names are the same as the
+// JSON fields of SourceInstance and the additional params are not supported.
+type fileSource struct{}
+
+func (s *fileSource) Fetch(ctx context.Context, addr string) ([]*SourceInstance, error) {
+	path := strings.TrimPrefix(addr, "file://")
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		return parseYAMLInstances(content)
+	case ".json":
+		return parseJSONInstances(content)
+	case ".csv":
+		return parseCSVInstances(content)
+	default:
+		return nil, fmt.Errorf("file %s is not yaml, json or csv", path)
+	}
+}
+
+func parseYAMLInstances(content []byte) ([]*SourceInstance, error) {
+	var instances []*SourceInstance
+	if err := yaml.Unmarshal(content, &instances); err == nil {
+		if instances == nil {
+			return nil, fmt.Errorf("parse instances failed: the file is empty")
+		}
+		return instances, nil
+	}
+	wrapper := struct {
+		Instances *[]*SourceInstance `yaml:"instances"`
+	}{}
+	if err := yaml.Unmarshal(content, &wrapper); err != nil {
+		return nil, fmt.Errorf("parse instances failed: %v", err)
+	}
+	if wrapper.Instances == nil {
+		return nil, fmt.Errorf("parse instances failed: \"instances\" is not in the file")
+	}
+	return *wrapper.Instances, nil
+}
+
+func parseCSVInstances(content []byte) ([]*SourceInstance, error) {
+	content = bytes.TrimPrefix(content, []byte("\xEF\xBB\xBF"))
+	rows, err := csv.NewReader(bytes.NewReader(content)).ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("parse instances failed: %v", err)
+	}
+	if len(rows) == 0 {
+		return nil, fmt.Errorf("parse instances failed: the header is missing")
+	}
+
+	header := rows[0]
+	hasName := false
+	for _, column := range header {
+		if strings.TrimSpace(strings.ToLower(column)) == "name" {
+			hasName = true
+		}
+	}
+	if !hasName {
+		return nil, fmt.Errorf("parse instances failed: the column \"name\" is missing in the header")
+	}
+	instances := make([]*SourceInstance, 0, len(rows)-1)
+	for _, row := range rows[1:] {
+		inst := &SourceInstance{}
+		for i, column := range header {
+			if i >= len(row) {
+				break
+			}
+			value := strings.TrimSpace(row[i])
+			switch strings.TrimSpace(strings.ToLower(column)) {
+			case "name":
+				inst.Name = value
+			case "db_type":
+				inst.DbType = value
+			case "host":
+				inst.Host = value
+			case "port":
+				inst.Port = value
+			case "user":
+				inst.User = value
+			case "password":
+				inst.Password = value
+			case "desc":
+				inst.Desc = value
+			}
+		}
+		instances = append(instances, inst)
+	}
+	return instances, nil
+}
@@ -0,0 +1,282 @@
+package syncinstance
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/actiontech/sqle/sqle/common"
+	"github.com/actiontech/sqle/sqle/driver"
+	"github.com/actiontech/sqle/sqle/log"
+	"github.com/actiontech/sqle/sqle/model"
+	"github.com/actiontech/sqle/sqle/pkg/params"
+)
+
+// syncPlan is the difference between the source and the instances of the project.
+type syncPlan struct {
+	created   []*model.Instance
+	updated   []*model.Instance
+	deleted   []*model.Instance
+	conflicts model.SyncInstanceConflicts
+}
+
+func (p *syncPlan) addConflict(name, format string, args ...interface{}) {
+	p.conflicts = append(p.conflicts, model.SyncInstanceConflict{
+		InstanceName: name,
+		Reason:       fmt.Sprintf(format, args...),
+	})
+}
+
+// planSync compares the instances of source with the instances of project. The instances are
+// matched by name, only the instances created by the task are updated and deleted, the others
+// with the same name are reported as conflicts. The source is rejected if any instance has no
+// name, or if it provides no instance of the task while the task does not allow an empty source,
+// since the synchronized instances would be deleted by mistake in both cases.
+func planSync(task *model.SyncInstanceTask, sourceInstances []*SourceInstance, projectInstances []*model.Instance,
+	defaultParams params.Params) (*syncPlan, error) {
+	for i, si := range sourceInstances {
+		if si.Name == "" {
+			return nil, fmt.Errorf("the name of instance %d in source is empty", i+1)
+		}
+	}
+
+	plan := &syncPlan{}
+	existed := make(map[string]*model.Instance, len(projectInstances))
+	for _, inst := range projectInstances {
+		existed[inst.Name] = inst
+	}
+
+	seen := map[string]struct{}{}
+	for _, si := range sourceInstances {
+		if si.DbType == "" {
+			si.DbType = task.DbType
+		}
+		if si.DbType != task.DbType {
+			continue
+		}
+		if _, ok := seen[si.Name]; ok {
+			plan.addConflict(si.Name, "instance name is duplicated in source")
+			continue
+		}
+		seen[si.Name] = struct{}{}
+		if si.Host == "" || si.Port == "" {
+			plan.addConflict(si.Name, "host or port of instance is empty")
+			continue
+		}
+		additionalParams := defaultParams.Copy()
+		if err := setAdditionalParams(&additionalParams, si.AdditionalParams); err != nil {
+			plan.addConflict(si.Name, "%v", err)
+			continue
+		}
+
+		inst, ok := existed[si.Name]
+		if !ok {
+			plan.created = append(plan.created, &model.Instance{
+				ProjectId:        task.ProjectId,
+				Name:             si.Name,
+				DbType:           si.DbType,
+				Host:             si.Host,
+				Port:             si.Port,
+				User:             si.User,
+				Password:         si.Password,
+				Desc:             si.Desc,
+				AdditionalParams: additionalParams,
+				SqlQueryConfig: model.SqlQueryConfig{
+					MaxPreQueryRows:    100,
+					QueryTimeoutSecond: 10,
+				},
+				WorkflowTemplateId: task.Project.WorkflowTemplateId,
+				Source:             task.Source,
+				SyncInstanceTaskID: task.ID,
+			})
+			continue
+		}
+		if inst.SyncInstanceTaskID != task.ID {
+			plan.addConflict(si.Name, "instance with the same name exists and is not created by the sync task")
+			continue
+		}
+		if updateInstance(inst, si, additionalParams, task.RuleTemplateID) {
+			plan.updated = append(plan.updated, inst)
+		}
+	}
+
+	for _, inst := range projectInstances {
+		if inst.SyncInstanceTaskID != task.ID {
+			continue
+		}
+		if _, ok := seen[inst.Name]; !ok {
+			plan.deleted = append(plan.deleted, inst)
+		}
+	}
+	if len(seen) == 0 && len(plan.deleted) > 0 && !task.AllowEmptySource {
+		return nil, fmt.Errorf("source provides no %s instance, the %d synchronized instances are not deleted "+
+			"unless the task allows an empty source", task.DbType, len(plan.deleted))
+	}
+	return plan, nil
+}
+
+func setAdditionalParams(ps *params.Params, values map[string]string) error {
+	for key, value := range values {
+		if err := ps.SetParamValue(key, value); err != nil {
+			return fmt.Errorf("set additional param %s failed: %v", key, err)
+		}
+	}
+	return nil
+}
+
+func paramValuesEqual(a, b params.Params) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	values := make(map[string]string, len(a))
+	for _, p := range a {
+		values[p.Key] = p.Value
+	}
+	for _, p := range b {
+		if v, ok := values[p.Key]; !ok || v != p.Value {
+			return false
+		}
+	}
+	return true
+}
+
+// updateInstance applies the source instance to the synchronized instance, it returns false if
+// nothing is changed. The password is not changed if the source does not provide it.
+func updateInstance(inst *model.Instance, si *SourceInstance, additionalParams params.Params, ruleTemplateId uint) bool {
+	changed := false
+	for _, field := range []struct {
+		target *string
+		value  string
+	}{
+		{&inst.Host, si.Host},
+		{&inst.Port, si.Port},
+		{&inst.User, si.User},
+		{&inst.Desc, si.Desc},
+	} {
+		if *field.target != field.value {
+			*field.target = field.value
+			changed = true
+		}
+	}
+	if si.Password != "" && si.Password != inst.Password {
+		inst.Password = si.Password
+		// the password is encrypted again before save
+		inst.SecretPassword = ""
+		changed = true
+	}
+	if !paramValuesEqual(inst.AdditionalParams, additionalParams) {
+		inst.AdditionalParams = additionalParams
+		changed = true
+	}
+	if len(inst.RuleTemplates) != 1 || inst.RuleTemplates[0].ID != ruleTemplateId {
+		changed = true
+	}
+	return changed
+}
+
+// Run synchronizes the instances of task and saves the result as a record.
+func Run(ctx context.Context, taskId uint) (*model.SyncInstanceTaskRecord, error) {
+	s := model.GetStorage()
+	task, exist, err := s.GetSyncInstanceTaskById(taskId)
+	if err != nil {
+		return nil, err
+	}
+	if !exist {
+		return nil, fmt.Errorf("sync instance task %d is not exist", taskId)
+	}
+
+	// the same task is not run concurrently by the scheduler on leader and the manual trigger on
+	// any node.
+	claimed, err := s.ClaimSyncInstanceTask(task.ID, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	if !claimed {
+		return nil, fmt.Errorf("sync instance task %d is running", taskId)
+	}
+	defer func() {
+		if err := s.ReleaseSyncInstanceTask(task.ID); err != nil {
+			log.NewEntry().Errorf("release sync instance task %d error: %v", task.ID, err)
+		}
+	}()
+
+	record := &model.SyncInstanceTaskRecord{
+		TaskId:    task.ID,
+		StartTime: time.Now(),
+		Conflicts: model.SyncInstanceConflicts{},
+	}
+	syncErr := syncInstances(ctx, task, record)
+	record.EndTime = time.Now()
+	record.Status = model.SyncInstanceStatusSucceeded
+	attrs := map[string]interface{}{"last_sync_status": model.SyncInstanceStatusSucceeded}
+	if syncErr != nil {
+		record.Status = model.SyncInstanceStatusFailed
+		record.ErrorMessage = syncErr.Error()
+		attrs["last_sync_status"] = model.SyncInstanceStatusFailed
+	} else {
+		attrs["last_sync_success_time"] = record.EndTime
+	}
+
+	if err := s.Save(record); err != nil {
+		return record, err
+	}
+	if err := s.UpdateSyncInstanceTaskById(task.ID, attrs); err != nil {
+		return record, err
+	}
+	return record, syncErr
+}
+
+func syncInstances(ctx context.Context, task *model.SyncInstanceTask, record *model.SyncInstanceTaskRecord) error {
+	source, ok := GetSource(task.Source)
+	if !ok {
+		return fmt.Errorf("source %s is not supported", task.Source)
+	}
+	if task.Project == nil || task.RuleTemplate == nil {
+		return fmt.Errorf("project or rule template of sync instance task is not exist")
+	}
+	if task.Project.IsArchived() {
+		return fmt.Errorf("project %s is archived", task.Project.Name)
+	}
+
+	sourceInstances, err := source.Fetch(ctx, task.URL)
+	if err != nil {
+		return fmt.Errorf("fetch instances from %s failed: %v", task.Source, err)
+	}
+	s := model.GetStorage()
+	projectInstances, err := s.GetInstancesByProjectId(task.ProjectId)
+	if err != nil {
+		return err
+	}
+	defaultParams := driver.GetPluginManager().AllAdditionalParams()[task.DbType]
+	plan, err := planSync(task, sourceInstances, projectInstances, defaultParams)
+	if err != nil {
+		return err
+	}
+
+	deleted := make([]*model.Instance, 0, len(plan.deleted))
+	for _, inst := range plan.deleted {
+		if err := common.CheckDeleteInstance(inst.ID); err != nil {
+			plan.addConflict(inst.Name, "instance is not in source but can't be deleted: %v", err)
+			continue
+		}
+		deleted = append(deleted, inst)
+	}
+
+	instances := append(plan.created, plan.updated...)
+	err = s.BatchUpdateSyncTask(&model.SyncTaskInstance{
+		Instances:            instances,
+		RuleTemplate:         task.RuleTemplate,
+		NeedDeletedInstances: deleted,
+	})
+	if err != nil {
+		return err
+	}
+	record.CreatedNum = uint(len(plan.created))
+	record.UpdatedNum = uint(len(plan.updated))
+	record.DeletedNum = uint(len(deleted))
+	record.Conflicts = plan.conflicts
+	if record.Conflicts == nil {
+		record.Conflicts = model.SyncInstanceConflicts{}
+	}
+	return nil
+}
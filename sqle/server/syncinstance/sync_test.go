@@ -0,0 +1,122 @@
+package syncinstance
+
+import (
+	"testing"
+
+	"github.com/actiontech/sqle/sqle/model"
+	"github.com/actiontech/sqle/sqle/pkg/params"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPlanSync(t *testing.T) {
+	task := &model.SyncInstanceTask{
+		Model:          model.Model{ID: 1},
+		ProjectId:      2,
+		Source:         SourceHTTP,
+		DbType:         "MySQL",
+		RuleTemplateID: 3,
+		Project:        &model.Project{WorkflowTemplateId: 4},
+	}
+	ruleTemplates := []model.RuleTemplate{{Model: model.Model{ID: 3}}}
+	defaultParams := params.Params{{Key: "timeout", Value: "10", Type: params.ParamTypeInt}}
+	projectInstances := []*model.Instance{
+		// unchanged
+		{Name: "inst_1", Host: "10.0.0.1", Port: "3306", User: "root", Password: "pass", SyncInstanceTaskID: 1, RuleTemplates: ruleTemplates,
+			AdditionalParams: defaultParams.Copy()},
+		// host is changed
+		{Name: "inst_2", Host: "10.0.0.2", Port: "3306", User: "root", Password: "pass", SyncInstanceTaskID: 1, RuleTemplates: ruleTemplates,
+			AdditionalParams: defaultParams.Copy()},
+		// created manually
+		{Name: "inst_3", Host: "10.0.0.3", Port: "3306", Source: model.InstanceSourceSQLE},
+		// retired in source
+		{Name: "inst_4", Host: "10.0.0.4", Port: "3306", SyncInstanceTaskID: 1, RuleTemplates: ruleTemplates},
+		// created by another task
+		{Name: "inst_5", Host: "10.0.0.5", Port: "3306", SyncInstanceTaskID: 2},
+	}
+	sourceInstances := []*SourceInstance{
+		{Name: "inst_1", Host: "10.0.0.1", Port: "3306", User: "root"},
+		{Name: "inst_2", Host: "10.0.0.20", Port: "3306", User: "root"},
+		{Name: "inst_3", Host: "10.0.0.3", Port: "3306"},
+		{Name: "inst_6", Host: "10.0.0.6", Port: "3306", User: "root", Password: "pass", AdditionalParams: map[string]string{"timeout": "5"}},
+		{Name: "inst_6", Host: "10.0.0.6", Port: "3306"},
+		{Name: "inst_7", DbType: "PostgreSQL", Host: "10.0.0.7", Port: "5432"},
+		{Name: "inst_8", Host: "10.0.0.8"},
+		{Name: "inst_9", Host: "10.0.0.9", Port: "3306", AdditionalParams: map[string]string{"unknown": "1"}},
+	}
+
+	plan, err := planSync(task, sourceInstances, projectInstances, defaultParams)
+	assert.NoError(t, err)
+
+	assert.Len(t, plan.created, 1)
+	created := plan.created[0]
+	assert.Equal(t, "inst_6", created.Name)
+	assert.Equal(t, "MySQL", created.DbType)
+	assert.Equal(t, uint(2), created.ProjectId)
+	assert.Equal(t, uint(1), created.SyncInstanceTaskID)
+	assert.Equal(t, uint(4), created.WorkflowTemplateId)
+	assert.Equal(t, SourceHTTP, created.Source)
+	assert.Equal(t, "5", created.AdditionalParams.GetParam("timeout").Value)
+	assert.Equal(t, "10", defaultParams.GetParam("timeout").Value)
+
+	assert.Len(t, plan.updated, 1)
+	assert.Equal(t, "inst_2", plan.updated[0].Name)
+	assert.Equal(t, "10.0.0.20", plan.updated[0].Host)
+	// password is kept if the source does not provide it
+	assert.Equal(t, "pass", plan.updated[0].Password)
+
+	assert.Len(t, plan.deleted, 1)
+	assert.Equal(t, "inst_4", plan.deleted[0].Name)
+
+	conflictNames := []string{}
+	for _, conflict := range plan.conflicts {
+		conflictNames = append(conflictNames, conflict.InstanceName)
+	}
+	assert.Equal(t, []string{"inst_3", "inst_6", "inst_8", "inst_9"}, conflictNames)
+}
+
+func TestPlanSyncRejectSource(t *testing.T) {
+	task := &model.SyncInstanceTask{
+		Model:   model.Model{ID: 1},
+		DbType:  "MySQL",
+		Project: &model.Project{},
+	}
+	projectInstances := []*model.Instance{
+		{Name: "inst_1", Host: "10.0.0.1", Port: "3306", SyncInstanceTaskID: 1},
+	}
+
+	// the instance without name can't be matched, nothing is deleted
+	_, err := planSync(task, []*SourceInstance{{Host: "10.0.0.1", Port: "3306"}}, projectInstances, nil)
+	assert.Error(t, err)
+
+	// the source provides no instance of the task
+	_, err = planSync(task, []*SourceInstance{}, projectInstances, nil)
+	assert.Error(t, err)
+	_, err = planSync(task, []*SourceInstance{{Name: "inst_2", DbType: "PostgreSQL"}}, projectInstances, nil)
+	assert.Error(t, err)
+
+	// nothing to delete
+	plan, err := planSync(task, []*SourceInstance{}, nil, nil)
+	assert.NoError(t, err)
+	assert.Len(t, plan.deleted, 0)
+
+	task.AllowEmptySource = true
+	plan, err = planSync(task, []*SourceInstance{}, projectInstances, nil)
+	assert.NoError(t, err)
+	assert.Len(t, plan.deleted, 1)
+}
+
+func TestUpdateInstance(t *testing.T) {
+	ruleTemplates := []model.RuleTemplate{{Model: model.Model{ID: 1}}}
+	inst := &model.Instance{Host: "10.0.0.1", Port: "3306", User: "root", Password: "pass", SecretPassword: "secret", RuleTemplates: ruleTemplates}
+	si := &SourceInstance{Host: "10.0.0.1", Port: "3306", User: "root"}
+	assert.False(t, updateInstance(inst, si, nil, 1))
+
+	// rule template of task is changed
+	assert.True(t, updateInstance(inst, si, nil, 2))
+
+	si.Password = "new_pass"
+	assert.True(t, updateInstance(inst, si, nil, 1))
+	assert.Equal(t, "new_pass", inst.Password)
+	assert.Equal(t, "", inst.SecretPassword)
+}
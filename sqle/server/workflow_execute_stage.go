@@ -0,0 +1,116 @@
+package server
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/actiontech/sqle/sqle/model"
+	"github.com/actiontech/sqle/sqle/notification"
+	"github.com/sirupsen/logrus"
+)
+
+// progressWorkflowExecuteStages is called after the task of workflow finishes. The next
+// stage is launched on behalf of the user who executes the finished task if the tasks of
+// current stage are all executed successfully, and the workflow is halted if any task of
+// current stage fails.
+func progressWorkflowExecuteStages(s *model.Storage, workflowId, triggerUserId uint, l *logrus.Entry) {
+	workflow, exist, err := s.GetWorkflowDetailById(strconv.Itoa(int(workflowId)))
+	if err != nil {
+		l.Errorf("get workflow %d from storage error: %v", workflowId, err)
+		return
+	}
+	if !exist || !workflow.Record.IsStaged() {
+		return
+	}
+
+	stages := workflow.Record.GetExecuteStages()
+	for i, stage := range stages {
+		switch stage.State() {
+		case model.WorkflowExecuteStageStateSucceeded:
+			continue
+		case model.WorkflowExecuteStageStateFailed:
+			// the later stages are not executed, the executed tasks can be rolled back by
+			// the rollback SQLs generated on audit.
+			err = s.UpdateWorkflowRecordByID(workflow.Record.ID, map[string]interface{}{
+				"status": model.WorkflowStatusExecFailed,
+			})
+			if err != nil {
+				l.Errorf("halt workflow %s failed: %v", workflow.Subject, err)
+			}
+		case model.WorkflowExecuteStageStatePending:
+			// the first stage is launched by user.
+			if i == 0 {
+				return
+			}
+			if triggerUserId == 0 {
+				l.Errorf("stage %d of workflow %s is not executed, cause the user who triggers it is unknown", stage.Stage, workflow.Subject)
+				return
+			}
+			launchWorkflowExecuteStage(s, workflow, stage, triggerUserId, time.Now(), l)
+		}
+		return
+	}
+}
+
+// launchWorkflowExecuteStage executes the tasks of stage whose instance is within maintenance
+// window, and queues the others for the next maintenance window of instance.
+func launchWorkflowExecuteStage(s *model.Storage, workflow *model.Workflow, stage *model.WorkflowExecuteStage,
+	userId uint, now time.Time, l *logrus.Entry) {
+
+	execRecords, queueRecords := splitWorkflowExecuteStage(stage, now)
+	claimed, err := s.ClaimWorkflowExecuteStage(execRecords, queueRecords, userId, model.MaintenancePriorityMedium)
+	if err != nil {
+		l.Errorf("claim stage %d of workflow %s error: %v", stage.Stage, workflow.Subject, err)
+		return
+	}
+	if !claimed {
+		l.Infof("stage %d of workflow %s has been launched by others", stage.Stage, workflow.Subject)
+		return
+	}
+	if len(queueRecords) > 0 {
+		l.Infof("queue %d tasks of stage %d of workflow %s for the maintenance window", len(queueRecords), stage.Stage, workflow.Subject)
+	}
+	if len(execRecords) == 0 {
+		return
+	}
+
+	needExecTaskIds := map[uint]uint{}
+	for _, ir := range execRecords {
+		needExecTaskIds[ir.TaskId] = userId
+	}
+	l.Infof("start to execute stage %d of workflow %s", stage.Stage, workflow.Subject)
+	// reload the workflow to see the tasks claimed just now.
+	w, exist, err := s.GetWorkflowDetailById(strconv.Itoa(int(workflow.ID)))
+	if err == nil && !exist {
+		err = fmt.Errorf("workflow is not exist")
+	}
+	if err != nil {
+		if releaseErr := s.ReleaseInstanceRecordsClaim(execRecords); releaseErr != nil {
+			l.Errorf("release the claim of stage %d of workflow %s error: %v", stage.Stage, workflow.Subject, releaseErr)
+		}
+	} else {
+		err = executeClaimedWorkflow(w, needExecTaskIds, execRecords)
+	}
+	if err != nil {
+		l.Errorf("execute stage %d of workflow %s failed: %v", stage.Stage, workflow.Subject, err)
+		go notification.NotifyWorkflow(fmt.Sprintf("%v", workflow.ID), notification.WorkflowNotifyTypeExecuteFail)
+	}
+}
+
+// splitWorkflowExecuteStage splits the pending tasks of stage into the tasks to be executed
+// now and the tasks to be queued since their instance is out of maintenance window. The
+// tasks which have been queued by user are left to the queue.
+func splitWorkflowExecuteStage(stage *model.WorkflowExecuteStage, now time.Time) (execRecords, queueRecords []*model.WorkflowInstanceRecord) {
+	for _, ir := range stage.InstanceRecords {
+		if ir.IsSQLExecuted || ir.MaintenanceQueuedAt != nil {
+			continue
+		}
+		if ir.Instance != nil && len(ir.Instance.MaintenancePeriod) != 0 && !ir.Instance.MaintenancePeriod.IsWithinScope(now) {
+			queueRecords = append(queueRecords, ir)
+			continue
+		}
+		execRecords = append(execRecords, ir)
+	}
+	return execRecords, queueRecords
+}
@@ -0,0 +1,35 @@
+package server
+
+import (
+	"testing"
+	"time"
+
+	"github.com/actiontech/sqle/sqle/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSplitWorkflowExecuteStage(t *testing.T) {
+	now := time.Date(2023, 1, 1, 10, 0, 0, 0, time.Local)
+	inWindow := &model.Instance{MaintenancePeriod: model.Periods{{StartHour: 9, EndHour: 11}}}
+	outWindow := &model.Instance{MaintenancePeriod: model.Periods{{StartHour: 2, EndHour: 4}}}
+	noWindow := &model.Instance{}
+
+	stage := &model.WorkflowExecuteStage{Stage: 2, InstanceRecords: []*model.WorkflowInstanceRecord{
+		{TaskId: 1, Instance: inWindow},
+		{TaskId: 2, Instance: outWindow},
+		{TaskId: 3, Instance: noWindow},
+		// the task queued by user is left to the queue
+		{TaskId: 4, Instance: outWindow, MaintenanceQueuedAt: &now},
+		{TaskId: 5, Instance: inWindow, IsSQLExecuted: true},
+	}}
+	execRecords, queueRecords := splitWorkflowExecuteStage(stage, now)
+	taskIds := func(records []*model.WorkflowInstanceRecord) []uint {
+		ids := []uint{}
+		for _, ir := range records {
+			ids = append(ids, ir.TaskId)
+		}
+		return ids
+	}
+	assert.Equal(t, []uint{1, 3}, taskIds(execRecords))
+	assert.Equal(t, []uint{2}, taskIds(queueRecords))
+}
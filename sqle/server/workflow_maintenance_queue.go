@@ -0,0 +1,139 @@
+package server
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/actiontech/sqle/sqle/model"
+	"github.com/actiontech/sqle/sqle/notification"
+	"github.com/sirupsen/logrus"
+)
+
+// executeMaintenanceQueuedTasks launches the tasks queued for the maintenance window of
+// instance, the tasks of the same instance are executed one by one.
+func (j *WorkflowScheduleJob) executeMaintenanceQueuedTasks(entry *logrus.Entry) {
+	st := model.GetStorage()
+	records, err := st.GetMaintenanceQueuedInstanceRecords()
+	if err != nil {
+		entry.Errorf("get maintenance queued tasks from storage error: %v", err)
+		return
+	}
+
+	instanceIds := []uint{}
+	queues := map[uint][]*model.WorkflowInstanceRecord{}
+	for _, record := range records {
+		if record.Instance == nil || record.Task == nil {
+			continue
+		}
+		if _, ok := queues[record.InstanceId]; !ok {
+			instanceIds = append(instanceIds, record.InstanceId)
+		}
+		queues[record.InstanceId] = append(queues[record.InstanceId], record)
+	}
+	if len(instanceIds) == 0 {
+		return
+	}
+
+	now := time.Now()
+	for _, id := range instanceIds {
+		queue := queues[id]
+		if err := launchMaintenanceQueuedTask(entry, st, queue, now); err != nil {
+			entry.Errorf("execute maintenance queued tasks of instance %s error: %v", queue[0].Instance.Name, err)
+		}
+	}
+}
+
+// launchMaintenanceQueuedTask launches the first task of queue if the instance is within
+// maintenance window and not busy. If the estimated duration of the task exceeds the
+// remaining time of window, no more task is launched in the window and the rest of queue
+// is rolled over to the next window. The task is claimed before it is launched, so that it
+// is not launched twice.
+func launchMaintenanceQueuedTask(entry *logrus.Entry, st *model.Storage, queue []*model.WorkflowInstanceRecord,
+	now time.Time) error {
+
+	start, end, ok := queue[0].Instance.MaintenancePeriod.CurrentPeriod(now)
+	if !ok {
+		return nil
+	}
+
+	rolledOver := func(record *model.WorkflowInstanceRecord) bool {
+		return record.MaintenanceRolloverAt != nil && !record.MaintenanceRolloverAt.Before(start)
+	}
+	for i, record := range queue {
+		if rolledOver(record) {
+			continue
+		}
+		estimate, err := st.EstimateTaskExecuteDuration(record.Task)
+		if err != nil {
+			return err
+		}
+		if estimate > end.Sub(now) {
+			rollovers := []*model.WorkflowInstanceRecord{}
+			for _, r := range queue[i:] {
+				if !rolledOver(r) {
+					rollovers = append(rollovers, r)
+				}
+			}
+			entry.Infof("the estimated duration %v of task %d exceeds the remaining maintenance time, roll over %d tasks",
+				estimate, record.TaskId, len(rollovers))
+			return rolloverMaintenanceQueuedTasks(st, rollovers, now)
+		}
+		// the task waits for the executing task of instance, it is checked right before the
+		// launch since the task launched by the last schedule or the execute stages may be
+		// executing now.
+		busyInstanceIds, err := st.GetInstanceIdsWithExecutingTask([]uint{record.InstanceId})
+		if err != nil {
+			return err
+		}
+		if len(busyInstanceIds) > 0 {
+			return nil
+		}
+
+		claimedRecords := []*model.WorkflowInstanceRecord{record}
+		claimed, err := st.ClaimInstanceRecordsForExecution(claimedRecords, record.ScheduleUserId)
+		if err != nil {
+			return err
+		}
+		if !claimed {
+			entry.Infof("maintenance queued task %d has been launched by others", record.TaskId)
+			return nil
+		}
+		w, err := st.GetWorkflowDetailByTaskID(record.TaskId)
+		if err != nil {
+			if releaseErr := st.ReleaseInstanceRecordsClaim(claimedRecords); releaseErr != nil {
+				entry.Errorf("release the claim of task %d error: %v", record.TaskId, releaseErr)
+			}
+			return err
+		}
+		entry.Infof("start to execute maintenance queued task %d of workflow %s", record.TaskId, w.Subject)
+		return executeClaimedWorkflow(w, map[uint]uint{record.TaskId: record.ScheduleUserId}, claimedRecords)
+	}
+	return nil
+}
+
+func rolloverMaintenanceQueuedTasks(st *model.Storage, records []*model.WorkflowInstanceRecord, now time.Time) error {
+	ids := make([]uint, 0, len(records))
+	for _, record := range records {
+		ids = append(ids, record.ID)
+	}
+	if err := st.UpdateInstanceRecordsMaintenanceRollover(ids, now); err != nil {
+		return err
+	}
+
+	notified := map[uint]bool{}
+	for _, record := range records {
+		if notified[record.WorkflowRecordId] {
+			continue
+		}
+		notified[record.WorkflowRecordId] = true
+		w, exist, err := st.GetWorkflowByTaskId(record.TaskId)
+		if err != nil {
+			return err
+		}
+		if !exist {
+			continue
+		}
+		go notification.NotifyWorkflow(fmt.Sprintf("%v", w.ID), notification.WorkflowNotifyTypeExecuteRollover)
+	}
+	return nil
+}
@@ -29,6 +29,11 @@ func NewWorkflowScheduleJob(entry *logrus.Entry) ServerJob {
 }
 
 func (j *WorkflowScheduleJob) WorkflowSchedule(entry *logrus.Entry) {
+	j.executeScheduledWorkflows(entry)
+	j.executeMaintenanceQueuedTasks(entry)
+}
+
+func (j *WorkflowScheduleJob) executeScheduledWorkflows(entry *logrus.Entry) {
 	st := model.GetStorage()
 	workflows, err := st.GetNeedScheduledWorkflows()
 	if err != nil {
@@ -77,6 +82,22 @@ func (j *WorkflowScheduleJob) WorkflowSchedule(entry *logrus.Entry) {
 }
 
 func ExecuteWorkflow(workflow *model.Workflow, needExecTaskIdToUserId map[uint]uint) error {
+	return executeWorkflow(workflow, needExecTaskIdToUserId, false)
+}
+
+// executeClaimedWorkflow executes the tasks which have been claimed by
+// Storage.ClaimInstanceRecordsForExecution, the claim is released if the tasks are not launched.
+func executeClaimedWorkflow(workflow *model.Workflow, needExecTaskIdToUserId map[uint]uint, claimedRecords []*model.WorkflowInstanceRecord) error {
+	err := executeWorkflow(workflow, needExecTaskIdToUserId, true)
+	if err != nil {
+		if releaseErr := model.GetStorage().ReleaseInstanceRecordsClaim(claimedRecords); releaseErr != nil {
+			log.NewEntry().Errorf("release the claim of tasks of workflow %s error: %v", workflow.Subject, releaseErr)
+		}
+	}
+	return err
+}
+
+func executeWorkflow(workflow *model.Workflow, needExecTaskIdToUserId map[uint]uint, claimed bool) error {
 	s := model.GetStorage()
 
 	// get task and check connection before to execute it.
@@ -124,7 +145,9 @@ func ExecuteWorkflow(workflow *model.Workflow, needExecTaskIdToUserId map[uint]u
 		}
 	}
 	if allTaskHasExecuted {
+		now := time.Now()
 		currentStep.State = model.WorkflowStepStateApprove
+		currentStep.OperateAt = &now
 		workflow.Record.Status = model.WorkflowStatusExecuting
 		workflow.Record.CurrentWorkflowStepId = 0
 		operateStep = currentStep
@@ -132,6 +155,10 @@ func ExecuteWorkflow(workflow *model.Workflow, needExecTaskIdToUserId map[uint]u
 		operateStep = nil
 	}
 
+	// the claimed tasks have been marked as executed.
+	if claimed {
+		needExecTaskRecords = nil
+	}
 	err := s.UpdateWorkflowExecInstanceRecord(workflow, operateStep, needExecTaskRecords)
 	if err != nil {
 		return err
@@ -150,6 +177,7 @@ func ExecuteWorkflow(workflow *model.Workflow, needExecTaskIdToUserId map[uint]u
 				updateStatus(s, workflow, l)
 				lock.Unlock()
 			}
+			progressWorkflowExecuteStages(s, workflow.ID, needExecTaskIdToUserId[id], l)
 
 			if err != nil || task.Status == model.TaskStatusExecuteFailed {
 				go notification.NotifyWorkflow(fmt.Sprintf("%v", workflow.ID), notification.WorkflowNotifyTypeExecuteFail)
@@ -176,7 +204,7 @@ func updateStatus(s *model.Storage, workflow *model.Workflow, l *logrus.Entry) {
 	var hasWaitExecute bool
 
 	for _, task := range tasks {
-		if task.Status == model.TaskStatusExecuting {
+		if task.Status == model.TaskStatusExecuting || task.Status == model.TaskStatusPaused {
 			hasExecuting = true
 		}
 		if task.Status == model.TaskStatusExecuteFailed {
@@ -222,27 +250,29 @@ func ApproveWorkflowProcess(workflow *model.Workflow, user *model.User, s *model
 			fmt.Errorf("workflow has been approved, you should to execute it"))
 	}
 
-	currentStep.State = model.WorkflowStepStateApprove
 	now := time.Now()
-	currentStep.OperateAt = &now
-	currentStep.OperationUserId = user.ID
-	nextStep := workflow.NextStep()
-	workflow.Record.CurrentWorkflowStepId = nextStep.ID
-	if nextStep.Template.Typ == model.WorkflowStepTypeSQLExecute {
-		workflow.Record.Status = model.WorkflowStatusWaitForExecution
-	}
-
-	err := s.UpdateWorkflowStep(workflow, currentStep)
+	approval := &model.WorkflowStepApproval{
+		WorkflowStepId: currentStep.ID,
+		UserId:         user.ID,
+		State:          model.WorkflowStepStateApprove,
+		OperateAt:      &now,
+	}
+	// the workflow moves to the next step only if the approvals of step are enough,
+	// see model.WorkflowStepApproveModeAnyOf.
+	stepApproved, err := s.ApproveWorkflowStep(workflow, currentStep, approval)
 	if err != nil {
 		return fmt.Errorf("update workflow status failed, %v", err)
 	}
 
-	go notification.NotifyWorkflow(strconv.Itoa(int(workflow.ID)), notification.WorkflowNotifyTypeApprove)
+	if stepApproved {
+		go notification.NotifyWorkflow(strconv.Itoa(int(workflow.ID)), notification.WorkflowNotifyTypeApprove)
+	}
 
 	return nil
 }
 
 func RejectWorkflowProcess(workflow *model.Workflow, reason string, user *model.User, s *model.Storage) error {
+	// any assignee can veto the step whatever the approve mode of step is.
 	currentStep := workflow.CurrentStep()
 	currentStep.State = model.WorkflowStepStateReject
 	currentStep.Reason = reason
@@ -253,7 +283,14 @@ func RejectWorkflowProcess(workflow *model.Workflow, reason string, user *model.
 	workflow.Record.Status = model.WorkflowStatusReject
 	workflow.Record.CurrentWorkflowStepId = 0
 
-	if err := s.UpdateWorkflowStep(workflow, currentStep); err != nil {
+	approval := &model.WorkflowStepApproval{
+		WorkflowStepId: currentStep.ID,
+		UserId:         user.ID,
+		State:          model.WorkflowStepStateReject,
+		Reason:         reason,
+		OperateAt:      &now,
+	}
+	if err := s.UpdateWorkflowStep(workflow, currentStep, approval); err != nil {
 		return fmt.Errorf("update workflow status failed, %v", err)
 	}
 
@@ -329,10 +366,24 @@ func GetNeedExecTaskIds(s *model.Storage, workflow *model.Workflow, user *model.
 			fmt.Errorf("please go online during instance operation and maintenance time. these instances are not in maintenance time[%v]", strings.Join(cannotExecuteInstanceNames, ",")))
 	}
 
-	// 定时的instances和已上线的跳过
+	instRecords := workflow.Record.InstanceRecords
+	// 分阶段上线的工单只上线当前阶段的任务，之后的阶段在当前阶段上线成功后自动上线
+	if workflow.Record.IsStaged() {
+		stage := workflow.Record.CurrentExecuteStage()
+		if stage == nil {
+			return map[uint]uint{}, nil
+		}
+		if stage.State() == model.WorkflowExecuteStageStateFailed {
+			return nil, errors.New(errors.TaskActionInvalid,
+				fmt.Errorf("the execution is halted since the tasks of execute stage %d failed", stage.Stage))
+		}
+		instRecords = stage.InstanceRecords
+	}
+
+	// 定时的、排队等待运维时间的instances和已上线的跳过
 	needExecTaskIds := make(map[uint]uint)
-	for _, instRecord := range workflow.Record.InstanceRecords {
-		if instRecord.ScheduledAt != nil || instRecord.IsSQLExecuted {
+	for _, instRecord := range instRecords {
+		if instRecord.ScheduledAt != nil || instRecord.MaintenanceQueuedAt != nil || instRecord.IsSQLExecuted {
 			continue
 		}
 		needExecTaskIds[instRecord.TaskId] = user.ID
@@ -396,5 +447,9 @@ func CheckUserCanOperateStep(user *model.User, workflow *model.Workflow, stepId
 		return fmt.Errorf("you are not allow to operate the workflow")
 	}
 
+	if currentStep.HasBeenDecidedBy(user) {
+		return fmt.Errorf("you have operated the workflow step, waiting for the other assignees")
+	}
+
 	return nil
 }
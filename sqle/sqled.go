@@ -94,6 +94,7 @@ func Run(config *config.Config) error {
 	var node cluster.Node
 	if sqleCnf.EnableClusterMode {
 		cluster.IsClusterMode = true
+		cluster.ServerId = sqleCnf.ServerId
 		log.Logger().Infoln("running sqled server on cluster mode")
 		node = cluster.DefaultNode
 		node.Join(sqleCnf.ServerId)